@@ -9,6 +9,8 @@ package actions
 import (
 	"context"
 	"errors"
+	"slices"
+	"strings"
 	"time"
 
 	"code.gitea.io/gitea/models/db"
@@ -56,7 +58,8 @@ func init() {
 // ActionArtifact is a file that is stored in the artifact storage.
 type ActionArtifact struct {
 	ID                 int64 `xorm:"pk autoincr"`
-	RunID              int64 `xorm:"index unique(runid_name_path)"` // The run id of the artifact
+	RunID              int64 `xorm:"index unique(runid_attempt_name_path)"`              // The run id of the artifact
+	RunAttempt         int64 `xorm:"unique(runid_attempt_name_path) NOT NULL DEFAULT 1"` // The attempt of the run which uploaded the artifact
 	RunnerID           int64
 	RepoID             int64 `xorm:"index"`
 	OwnerID            int64
@@ -65,24 +68,30 @@ type ActionArtifact struct {
 	FileSize           int64              // The size of the artifact in bytes
 	FileCompressedSize int64              // The size of the artifact in bytes after gzip compression
 	ContentEncoding    string             // The content encoding of the artifact
-	ArtifactPath       string             `xorm:"index unique(runid_name_path)"` // The path to the artifact when runner uploads it
-	ArtifactName       string             `xorm:"index unique(runid_name_path)"` // The name of the artifact when runner uploads it
-	Status             ArtifactStatus     `xorm:"index"`                         // The status of the artifact, uploading, expired or need-delete
+	ArtifactPath       string             `xorm:"index unique(runid_attempt_name_path)"` // The path to the artifact when runner uploads it
+	ArtifactName       string             `xorm:"index unique(runid_attempt_name_path)"` // The name of the artifact when runner uploads it
+	Status             ArtifactStatus     `xorm:"index"`                                 // The status of the artifact, uploading, expired or need-delete
 	CreatedUnix        timeutil.TimeStamp `xorm:"created"`
 	UpdatedUnix        timeutil.TimeStamp `xorm:"updated index"`
 	ExpiredUnix        timeutil.TimeStamp `xorm:"index"` // The time when the artifact will be expired
 }
 
+// CreateArtifact creates an artifact uploaded by the task, or returns the one created by the same attempt of the run.
+// The artifacts uploaded by the previous attempts are kept, so each attempt has its own artifacts.
 func CreateArtifact(ctx context.Context, t *ActionTask, artifactName, artifactPath string, expiredDays int64) (*ActionArtifact, error) {
 	if err := t.LoadJob(ctx); err != nil {
 		return nil, err
 	}
-	artifact, err := getArtifactByNameAndPath(ctx, t.Job.RunID, artifactName, artifactPath)
+	if err := t.Job.LoadRun(ctx); err != nil {
+		return nil, err
+	}
+	artifact, err := getArtifactByNameAndPath(ctx, t.Job.RunID, t.Job.Run.Attempt, artifactName, artifactPath)
 	if errors.Is(err, util.ErrNotExist) {
 		artifact := &ActionArtifact{
 			ArtifactName: artifactName,
 			ArtifactPath: artifactPath,
 			RunID:        t.Job.RunID,
+			RunAttempt:   t.Job.Run.Attempt,
 			RunnerID:     t.RunnerID,
 			RepoID:       t.RepoID,
 			OwnerID:      t.OwnerID,
@@ -107,9 +116,9 @@ func CreateArtifact(ctx context.Context, t *ActionTask, artifactName, artifactPa
 	return artifact, nil
 }
 
-func getArtifactByNameAndPath(ctx context.Context, runID int64, name, fpath string) (*ActionArtifact, error) {
+func getArtifactByNameAndPath(ctx context.Context, runID, runAttempt int64, name, fpath string) (*ActionArtifact, error) {
 	var art ActionArtifact
-	has, err := db.GetEngine(ctx).Where("run_id = ? AND artifact_name = ? AND artifact_path = ?", runID, name, fpath).Get(&art)
+	has, err := db.GetEngine(ctx).Where("run_id = ? AND run_attempt = ? AND artifact_name = ? AND artifact_path = ?", runID, runAttempt, name, fpath).Get(&art)
 	if err != nil {
 		return nil, err
 	} else if !has {
@@ -129,6 +138,8 @@ type FindArtifactsOptions struct {
 	db.ListOptions
 	RepoID               int64
 	RunID                int64
+	RunAttempt           int64
+	MaxRunAttempt        int64
 	ArtifactName         string
 	Status               int
 	FinalizedArtifactsV4 bool
@@ -148,6 +159,12 @@ func (opts FindArtifactsOptions) ToConds() builder.Cond {
 	if opts.RunID > 0 {
 		cond = cond.And(builder.Eq{"run_id": opts.RunID})
 	}
+	if opts.RunAttempt > 0 {
+		cond = cond.And(builder.Eq{"run_attempt": opts.RunAttempt})
+	}
+	if opts.MaxRunAttempt > 0 {
+		cond = cond.And(builder.Lte{"run_attempt": opts.MaxRunAttempt})
+	}
 	if opts.ArtifactName != "" {
 		cond = cond.And(builder.Eq{"artifact_name": opts.ArtifactName})
 	}
@@ -162,21 +179,76 @@ func (opts FindArtifactsOptions) ToConds() builder.Cond {
 	return cond
 }
 
+// FilterLatestAttemptArtifacts returns the artifacts of the latest attempt for each artifact name.
+// An attempt of a run can still use the artifacts uploaded by the previous attempts if it hasn't uploaded them again,
+// for example, the jobs which are not rerun keep their artifacts.
+func FilterLatestAttemptArtifacts(arts []*ActionArtifact) []*ActionArtifact {
+	latest := make(map[string]int64, len(arts))
+	for _, art := range arts {
+		latest[art.ArtifactName] = max(latest[art.ArtifactName], art.RunAttempt)
+	}
+	ret := make([]*ActionArtifact, 0, len(arts))
+	for _, art := range arts {
+		if art.RunAttempt == latest[art.ArtifactName] {
+			ret = append(ret, art)
+		}
+	}
+	return ret
+}
+
+// GetRunAttemptArtifacts returns the artifacts available in an attempt of a run, filtered by the name if it's not empty.
+// The artifacts still being uploaded by a later attempt don't hide the ones of an earlier attempt,
+// but the deleted ones do, otherwise an artifact of an earlier attempt shows up again after deleting the latest one.
+// So the caller should check the status of the returned artifacts.
+func GetRunAttemptArtifacts(ctx context.Context, runID, runAttempt int64, artifactName string) ([]*ActionArtifact, error) {
+	arts, err := db.Find[ActionArtifact](ctx, FindArtifactsOptions{
+		RunID:         runID,
+		MaxRunAttempt: runAttempt,
+		ArtifactName:  artifactName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	arts = slices.DeleteFunc(arts, func(art *ActionArtifact) bool {
+		return art.Status == ArtifactStatusUploadPending || art.Status == ArtifactStatusUploadError
+	})
+	return FilterLatestAttemptArtifacts(arts), nil
+}
+
 // ActionArtifactMeta is the meta-data of an artifact
 type ActionArtifactMeta struct {
 	ArtifactName string
+	RunAttempt   int64
 	FileSize     int64
 	Status       ArtifactStatus
 }
 
-// ListUploadedArtifactsMeta returns all uploaded artifacts meta of a run
-func ListUploadedArtifactsMeta(ctx context.Context, runID int64) ([]*ActionArtifactMeta, error) {
-	arts := make([]*ActionArtifactMeta, 0, 10)
-	return arts, db.GetEngine(ctx).Table("action_artifact").
-		Where("run_id=? AND (status=? OR status=?)", runID, ArtifactStatusUploadConfirmed, ArtifactStatusExpired).
-		GroupBy("artifact_name").
-		Select("artifact_name, sum(file_size) as file_size, max(status) as status").
-		Find(&arts)
+// ListUploadedArtifactsMeta returns all uploaded artifacts meta of an attempt of a run, see GetRunAttemptArtifacts
+func ListUploadedArtifactsMeta(ctx context.Context, runID, runAttempt int64) ([]*ActionArtifactMeta, error) {
+	arts, err := GetRunAttemptArtifacts(ctx, runID, runAttempt, "")
+	if err != nil {
+		return nil, err
+	}
+
+	metas := make([]*ActionArtifactMeta, 0, len(arts))
+	nameToMeta := make(map[string]*ActionArtifactMeta, len(arts))
+	for _, art := range arts {
+		if art.Status != ArtifactStatusUploadConfirmed && art.Status != ArtifactStatusExpired {
+			continue
+		}
+		meta, ok := nameToMeta[art.ArtifactName]
+		if !ok {
+			meta = &ActionArtifactMeta{ArtifactName: art.ArtifactName, RunAttempt: art.RunAttempt}
+			nameToMeta[art.ArtifactName] = meta
+			metas = append(metas, meta)
+		}
+		meta.FileSize += art.FileSize
+		meta.Status = max(meta.Status, art.Status)
+	}
+	slices.SortFunc(metas, func(a, b *ActionArtifactMeta) int {
+		return strings.Compare(a.ArtifactName, b.ArtifactName)
+	})
+	return metas, nil
 }
 
 // ListNeedExpiredArtifacts returns all need expired artifacts but not deleted
@@ -200,9 +272,9 @@ func SetArtifactExpired(ctx context.Context, artifactID int64) error {
 	return err
 }
 
-// SetArtifactNeedDelete sets an artifact to need-delete, cron job will delete it
-func SetArtifactNeedDelete(ctx context.Context, runID int64, name string) error {
-	_, err := db.GetEngine(ctx).Where("run_id=? AND artifact_name=? AND status = ?", runID, name, ArtifactStatusUploadConfirmed).Cols("status").Update(&ActionArtifact{Status: ArtifactStatusPendingDeletion})
+// SetArtifactNeedDelete sets an artifact uploaded by an attempt of a run to need-delete, cron job will delete it
+func SetArtifactNeedDelete(ctx context.Context, runID, runAttempt int64, name string) error {
+	_, err := db.GetEngine(ctx).Where("run_id=? AND run_attempt=? AND artifact_name=? AND status = ?", runID, runAttempt, name, ArtifactStatusUploadConfirmed).Cols("status").Update(&ActionArtifact{Status: ArtifactStatusPendingDeletion})
 	return err
 }
 
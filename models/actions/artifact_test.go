@@ -0,0 +1,56 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListUploadedArtifactsMeta(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	// the fixtures of run 791 are uploaded by the first attempt, the second attempt uploads "artifact-download" again
+	require.NoError(t, db.Insert(ctx, &ActionArtifact{
+		RunID: 791, RunAttempt: 2, RepoID: 4, OwnerID: 1, FileSize: 2048,
+		ArtifactPath: "abc.txt", ArtifactName: "artifact-download", Status: ArtifactStatusUploadConfirmed,
+	}))
+	// the artifacts being uploaded by an attempt don't hide the ones of the previous attempts
+	require.NoError(t, db.Insert(ctx, &ActionArtifact{
+		RunID: 791, RunAttempt: 2, RepoID: 4, OwnerID: 1, FileSize: 4096,
+		ArtifactPath: "abc.txt", ArtifactName: "multi-file-download", Status: ArtifactStatusUploadPending,
+	}))
+
+	metas, err := ListUploadedArtifactsMeta(ctx, 791, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []*ActionArtifactMeta{
+		{ArtifactName: "artifact-download", RunAttempt: 1, FileSize: 1024, Status: ArtifactStatusUploadConfirmed},
+		{ArtifactName: "multi-file-download", RunAttempt: 1, FileSize: 2048, Status: ArtifactStatusUploadConfirmed},
+	}, metas)
+
+	metas, err = ListUploadedArtifactsMeta(ctx, 791, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []*ActionArtifactMeta{
+		{ArtifactName: "artifact-download", RunAttempt: 2, FileSize: 2048, Status: ArtifactStatusUploadConfirmed},
+		{ArtifactName: "multi-file-download", RunAttempt: 1, FileSize: 2048, Status: ArtifactStatusUploadConfirmed},
+	}, metas)
+
+	// deleting the artifact of the second attempt doesn't bring back the one of the first attempt
+	require.NoError(t, SetArtifactNeedDelete(ctx, 791, 2, "artifact-download"))
+	metas, err = ListUploadedArtifactsMeta(ctx, 791, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []*ActionArtifactMeta{
+		{ArtifactName: "multi-file-download", RunAttempt: 1, FileSize: 2048, Status: ArtifactStatusUploadConfirmed},
+	}, metas)
+	artifacts, err := GetRunAttemptArtifacts(ctx, 791, 1, "artifact-download")
+	require.NoError(t, err)
+	require.Len(t, artifacts, 1)
+	assert.Equal(t, ArtifactStatusUploadConfirmed, artifacts[0].Status)
+}
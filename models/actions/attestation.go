@@ -0,0 +1,90 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// ActionAttestation is a signed provenance statement of a software artifact built by an Actions job,
+// it can be attached to an artifact of the run or to a package version of the repository owner.
+type ActionAttestation struct {
+	ID               int64
+	RepoID           int64  `xorm:"index"`
+	OwnerID          int64  `xorm:"index"`
+	RunID            int64  `xorm:"index"`
+	TaskID           int64  `xorm:"NOT NULL DEFAULT 0"`
+	ArtifactID       int64  `xorm:"index NOT NULL DEFAULT 0"`
+	PackageVersionID int64  `xorm:"index NOT NULL DEFAULT 0"`
+	SubjectName      string `xorm:"NOT NULL DEFAULT ''"`
+	SubjectDigest    string `xorm:"VARCHAR(255) index NOT NULL"` // "sha256:<hex>"
+	PredicateType    string `xorm:"NOT NULL"`
+	KeyID            string `xorm:"NOT NULL DEFAULT ''"` // the ID of the instance key which signed the envelope
+	Envelope         string `xorm:"LONGTEXT NOT NULL"`   // the DSSE envelope in JSON
+
+	Created timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionAttestation))
+}
+
+// GetAttestationByID returns the attestation of the repository by its ID
+func GetAttestationByID(ctx context.Context, repoID, id int64) (*ActionAttestation, error) {
+	var attestation ActionAttestation
+	has, err := db.GetEngine(ctx).Where("id = ? AND repo_id = ?", id, repoID).Get(&attestation)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, util.NewNotExistErrorf("attestation with id %d does not exist", id)
+	}
+	return &attestation, nil
+}
+
+type FindAttestationsOptions struct {
+	db.ListOptions
+	RepoID           int64
+	RunID            int64
+	ArtifactID       int64
+	PackageVersionID int64
+	SubjectDigest    string
+}
+
+func (opts FindAttestationsOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.RunID > 0 {
+		cond = cond.And(builder.Eq{"run_id": opts.RunID})
+	}
+	if opts.ArtifactID > 0 {
+		cond = cond.And(builder.Eq{"artifact_id": opts.ArtifactID})
+	}
+	if opts.PackageVersionID > 0 {
+		cond = cond.And(builder.Eq{"package_version_id": opts.PackageVersionID})
+	}
+	if opts.SubjectDigest != "" {
+		cond = cond.And(builder.Eq{"subject_digest": opts.SubjectDigest})
+	}
+	return cond
+}
+
+func (opts FindAttestationsOptions) ToOrders() string {
+	return "`id` DESC"
+}
+
+// DetachAttestationsFromPackageVersion keeps the attestations of a deleted package version,
+// they can still be fetched by the digests of their subjects
+func DetachAttestationsFromPackageVersion(ctx context.Context, packageVersionID int64) error {
+	_, err := db.GetEngine(ctx).Where("package_version_id = ?", packageVersionID).
+		Cols("package_version_id").Update(&ActionAttestation{PackageVersionID: 0})
+	return err
+}
@@ -0,0 +1,221 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/optional"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// CacheKeyMaxLength is the max length of the key of a cache, it's the same as GitHub Actions
+const CacheKeyMaxLength = 512
+
+// ActionCache represents a cache entry created by `actions/cache`.
+// A cache is scoped to the repository and the ref of the run which creates it,
+// and it's immutable once the upload is completed.
+type ActionCache struct {
+	ID             int64
+	RepoID         int64              `xorm:"INDEX(repo_ref) NOT NULL"`
+	Ref            string             `xorm:"INDEX(repo_ref) NOT NULL"` // the ref of the run which creates the cache, e.g. refs/heads/main
+	CacheKey       string             `xorm:"VARCHAR(512) NOT NULL"`
+	Version        string             `xorm:"NOT NULL"` // the hash of the paths and the compression method of the cache, computed by the client
+	RunID          int64              `xorm:"NOT NULL DEFAULT 0"`
+	Size           int64              `xorm:"NOT NULL DEFAULT 0"`
+	IsComplete     bool               `xorm:"INDEX NOT NULL DEFAULT FALSE"`
+	LastAccessUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	CreatedUnix    timeutil.TimeStamp `xorm:"created INDEX"`
+	UpdatedUnix    timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionCache))
+}
+
+// StoragePath returns the path of the cache content in the storage
+func (c *ActionCache) StoragePath() string {
+	return fmt.Sprintf("%d/%d", c.RepoID, c.ID)
+}
+
+// TmpStorageDir returns the directory to keep the uploading chunks of the cache in the storage
+func (c *ActionCache) TmpStorageDir() string {
+	return fmt.Sprintf("tmp/%d", c.ID)
+}
+
+type FindCachesOptions struct {
+	db.ListOptions
+	RepoID              int64
+	Refs                []string
+	Key                 string
+	Version             string
+	IsComplete          optional.Option[bool]
+	LastAccessBefore    timeutil.TimeStamp
+	CreatedBefore       timeutil.TimeStamp
+	OrderByLastAccessed bool
+}
+
+func (opts FindCachesOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if len(opts.Refs) > 0 {
+		cond = cond.And(builder.In("ref", opts.Refs))
+	}
+	if opts.Key != "" {
+		cond = cond.And(builder.Eq{"cache_key": opts.Key})
+	}
+	if opts.Version != "" {
+		cond = cond.And(builder.Eq{"version": opts.Version})
+	}
+	if opts.IsComplete.Has() {
+		cond = cond.And(builder.Eq{"is_complete": opts.IsComplete.Value()})
+	}
+	if opts.LastAccessBefore > 0 {
+		cond = cond.And(builder.Lt{"last_access_unix": opts.LastAccessBefore})
+	}
+	if opts.CreatedBefore > 0 {
+		cond = cond.And(builder.Lt{"created_unix": opts.CreatedBefore})
+	}
+	return cond
+}
+
+func (opts FindCachesOptions) ToOrders() string {
+	if opts.OrderByLastAccessed {
+		return "last_access_unix ASC, id ASC"
+	}
+	return "created_unix DESC, id DESC"
+}
+
+// GetCacheByRepoAndID returns the cache of the repository by id
+func GetCacheByRepoAndID(ctx context.Context, repoID, id int64) (*ActionCache, error) {
+	var cache ActionCache
+	has, err := db.GetEngine(ctx).Where("id=? AND repo_id=?", id, repoID).Get(&cache)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, fmt.Errorf("cache with id %d: %w", id, util.ErrNotExist)
+	}
+	return &cache, nil
+}
+
+// InsertCache reserves a cache entry for uploading,
+// it fails if there is already a completed cache with the same key and version in the ref.
+func InsertCache(ctx context.Context, cache *ActionCache) error {
+	if cache.CacheKey == "" || len(cache.CacheKey) > CacheKeyMaxLength {
+		return util.NewInvalidArgumentErrorf("invalid cache key %q", cache.CacheKey)
+	}
+	if cache.Version == "" {
+		return util.NewInvalidArgumentErrorf("cache version is required")
+	}
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		exist, err := db.Exist[ActionCache](ctx, FindCachesOptions{
+			RepoID:     cache.RepoID,
+			Refs:       []string{cache.Ref},
+			Key:        cache.CacheKey,
+			Version:    cache.Version,
+			IsComplete: optional.Some(true),
+		}.ToConds())
+		if err != nil {
+			return err
+		} else if exist {
+			return util.NewAlreadyExistErrorf("cache with key %q already exists", cache.CacheKey)
+		}
+		cache.LastAccessUnix = timeutil.TimeStampNow()
+		return db.Insert(ctx, cache)
+	})
+}
+
+// UpdateCache updates the columns of the cache
+func UpdateCache(ctx context.Context, cache *ActionCache, cols ...string) error {
+	sess := db.GetEngine(ctx).ID(cache.ID)
+	if len(cols) > 0 {
+		sess.Cols(cols...)
+	}
+	_, err := sess.Update(cache)
+	return err
+}
+
+// DeleteCacheByID deletes the record of a cache, the caller should delete its content in the storage
+func DeleteCacheByID(ctx context.Context, id int64) error {
+	_, err := db.GetEngine(ctx).ID(id).Delete(&ActionCache{})
+	return err
+}
+
+// FindCacheByKeys finds the cache to restore for the keys, the first key is the primary key and the others are restore keys.
+// The refs are searched in order, and for each ref, every key is matched exactly then as a prefix,
+// the most recently created cache is chosen if there are multiple caches matching the same key.
+// See https://docs.github.com/en/actions/writing-workflows/choosing-what-your-workflow-does/caching-dependencies-to-speed-up-workflows#matching-a-cache-key
+func FindCacheByKeys(ctx context.Context, repoID int64, refs, keys []string, version string) (*ActionCache, error) {
+	if len(refs) == 0 || len(keys) == 0 {
+		return nil, nil
+	}
+	// Load all the completed caches with the version and match the keys here,
+	// so the matching is case-sensitive and doesn't depend on how the database handles LIKE.
+	// The version is the hash of the cached paths, so there are not too many caches to load.
+	caches, err := db.Find[ActionCache](ctx, FindCachesOptions{
+		RepoID:     repoID,
+		Refs:       refs,
+		Version:    version,
+		IsComplete: optional.Some(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return matchCacheByKeys(caches, refs, keys), nil
+}
+
+// matchCacheByKeys matches the caches which are sorted by created time in descending order
+func matchCacheByKeys(caches []*ActionCache, refs, keys []string) *ActionCache {
+	for _, ref := range refs {
+		for _, key := range keys {
+			var prefixMatched *ActionCache
+			for _, cache := range caches {
+				if cache.Ref != ref {
+					continue
+				}
+				if cache.CacheKey == key {
+					return cache
+				}
+				if prefixMatched == nil && strings.HasPrefix(cache.CacheKey, key) {
+					prefixMatched = cache
+				}
+			}
+			if prefixMatched != nil {
+				return prefixMatched
+			}
+		}
+	}
+	return nil
+}
+
+// RepoCacheSize is the total size of the completed caches of a repository
+type RepoCacheSize struct {
+	RepoID int64
+	Size   int64
+}
+
+// FindReposExceedingCacheSize returns the repositories whose completed caches exceed the max size in total
+func FindReposExceedingCacheSize(ctx context.Context, maxSize int64) ([]*RepoCacheSize, error) {
+	sizes := make([]*RepoCacheSize, 0, 10)
+	err := db.GetEngine(ctx).Table("action_cache").
+		Select("repo_id, SUM(size) AS size").
+		Where("is_complete = ?", true).
+		GroupBy("repo_id").
+		Having(fmt.Sprintf("SUM(size) > %d", maxSize)).
+		Find(&sizes)
+	return sizes, err
+}
+
+// GetRepoCacheSize returns the total size of the completed caches of a repository
+func GetRepoCacheSize(ctx context.Context, repoID int64) (int64, error) {
+	return db.GetEngine(ctx).Where("repo_id = ? AND is_complete = ?", repoID, true).SumInt(new(ActionCache), "size")
+}
@@ -0,0 +1,50 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchCacheByKeys(t *testing.T) {
+	// sorted by created time in descending order
+	caches := []*ActionCache{
+		{ID: 5, Ref: "refs/heads/feature", CacheKey: "linux-deps-new"},
+		{ID: 4, Ref: "refs/heads/main", CacheKey: "linux-deps-abc"},
+		{ID: 3, Ref: "refs/heads/main", CacheKey: "linux-deps-old"},
+		{ID: 2, Ref: "refs/heads/feature", CacheKey: "linux-deps-abc"},
+		{ID: 1, Ref: "refs/heads/main", CacheKey: "Linux-deps-xyz"},
+	}
+	featureRefs := []string{"refs/heads/feature", "refs/heads/main"}
+
+	testCases := []struct {
+		name     string
+		refs     []string
+		keys     []string
+		expected int64
+	}{
+		{"exact match in the current ref", featureRefs, []string{"linux-deps-abc"}, 2},
+		{"prefix match of the primary key", featureRefs, []string{"linux-deps"}, 5},
+		{"restore key matches after the primary key", featureRefs, []string{"windows-deps", "linux-"}, 5},
+		{"fallback to the default branch", featureRefs, []string{"linux-deps-old"}, 3},
+		{"current ref first", []string{"refs/heads/main", "refs/heads/feature"}, []string{"linux-deps-abc"}, 4},
+		{"case sensitive", featureRefs, []string{"Linux-"}, 1},
+		{"no match", featureRefs, []string{"macos-"}, 0},
+		{"other refs are invisible", []string{"refs/heads/other"}, []string{"linux-"}, 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cache := matchCacheByKeys(caches, tc.refs, tc.keys)
+			if tc.expected == 0 {
+				assert.Nil(t, cache)
+				return
+			}
+			if assert.NotNil(t, cache) {
+				assert.Equal(t, tc.expected, cache.ID)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// DeploymentReviewState represents the state of a deployment review
+type DeploymentReviewState int
+
+const (
+	DeploymentReviewStateApproved DeploymentReviewState = iota + 1
+	DeploymentReviewStateRejected
+)
+
+// String returns the state name used by the API
+func (s DeploymentReviewState) String() string {
+	switch s {
+	case DeploymentReviewStateApproved:
+		return "approved"
+	case DeploymentReviewStateRejected:
+		return "rejected"
+	}
+	return "unknown"
+}
+
+// ActionDeploymentReview represents a review of a job targeting an environment which requires reviews.
+// A review is only valid for the attempt of the job when it's submitted, so a rerun job has to be reviewed again.
+type ActionDeploymentReview struct {
+	ID            int64
+	RepoID        int64                 `xorm:"index"`
+	RunID         int64                 `xorm:"index"`
+	JobID         int64                 `xorm:"index(job_attempt)"` // the id of the ActionRunJob, not the job id in the workflow
+	JobAttempt    int64                 `xorm:"index(job_attempt)"` // the attempt of the job when it's reviewed
+	EnvironmentID int64                 `xorm:"index"`
+	ReviewerID    int64                 `xorm:"index"`
+	State         DeploymentReviewState `xorm:"NOT NULL"`
+	Comment       string                `xorm:"TEXT"`
+
+	Created timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionDeploymentReview))
+}
+
+type FindDeploymentReviewsOptions struct {
+	db.ListOptions
+	RunID         int64
+	JobID         int64
+	EnvironmentID int64
+}
+
+func (opts FindDeploymentReviewsOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RunID > 0 {
+		cond = cond.And(builder.Eq{"run_id": opts.RunID})
+	}
+	if opts.JobID > 0 {
+		cond = cond.And(builder.Eq{"job_id": opts.JobID})
+	}
+	if opts.EnvironmentID > 0 {
+		cond = cond.And(builder.Eq{"environment_id": opts.EnvironmentID})
+	}
+	return cond
+}
+
+func (opts FindDeploymentReviewsOptions) ToOrders() string {
+	return "id ASC"
+}
+
+// GetDeploymentReviewOfJob returns the review of the current attempt of the job, nil if the job hasn't been reviewed
+func GetDeploymentReviewOfJob(ctx context.Context, job *ActionRunJob) (*ActionDeploymentReview, error) {
+	var review ActionDeploymentReview
+	has, err := db.GetEngine(ctx).Where("job_id=? AND job_attempt=?", job.ID, job.Attempt).Desc("id").Get(&review)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return &review, nil
+}
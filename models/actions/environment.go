@@ -0,0 +1,193 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/gobwas/glob"
+	"xorm.io/builder"
+)
+
+const (
+	// EnvironmentNameMaxLength is the max length of the name of an environment
+	EnvironmentNameMaxLength = 255
+	// EnvironmentMaxReviewers is the max number of the required reviewers of an environment, including users and teams
+	EnvironmentMaxReviewers = 6
+	// EnvironmentMaxWaitTimer is the max minutes of the wait timer of an environment, which is 30 days
+	EnvironmentMaxWaitTimer = 43200
+)
+
+// ActionEnvironment represents a deployment environment of a repository.
+// The jobs targeting an environment by `jobs.<job_id>.environment` have to pass its protection rules before running,
+// and they can access the secrets and variables of the environment.
+type ActionEnvironment struct {
+	ID                int64
+	RepoID            int64    `xorm:"UNIQUE(repo_name) NOT NULL"`
+	Name              string   `xorm:"NOT NULL"`
+	LowerName         string   `xorm:"UNIQUE(repo_name) NOT NULL"`
+	ReviewerUserIDs   []int64  `xorm:"JSON TEXT"`              // the users who can approve the jobs targeting the environment
+	ReviewerTeamIDs   []int64  `xorm:"JSON TEXT"`              // the teams whose members can approve the jobs targeting the environment
+	PreventSelfReview bool     `xorm:"NOT NULL DEFAULT FALSE"` // whether the user who triggered the run can't approve its jobs
+	WaitTimer         int64    `xorm:"NOT NULL DEFAULT 0"`     // the minutes to wait before the jobs targeting the environment can run
+	BranchPatterns    []string `xorm:"JSON TEXT"`              // the glob patterns of the branches which can deploy to the environment, empty means all
+
+	Created timeutil.TimeStamp `xorm:"created"`
+	Updated timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionEnvironment))
+}
+
+// HasRequiredReviewers returns whether the jobs targeting the environment require reviews
+func (env *ActionEnvironment) HasRequiredReviewers() bool {
+	return len(env.ReviewerUserIDs) > 0 || len(env.ReviewerTeamIDs) > 0
+}
+
+// IsBranchAllowed returns whether the branch can deploy to the environment
+func (env *ActionEnvironment) IsBranchAllowed(branch string) bool {
+	if len(env.BranchPatterns) == 0 {
+		return true
+	}
+	for _, pattern := range env.BranchPatterns {
+		g, err := glob.Compile(pattern, '/')
+		if err != nil {
+			log.Warn("Invalid branch pattern %q of environment %d: %v", pattern, env.ID, err)
+			continue
+		}
+		if g.Match(branch) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the name and the protection rules of the environment
+func (env *ActionEnvironment) Validate() error {
+	env.Name = strings.TrimSpace(env.Name)
+	if env.Name == "" || len(env.Name) > EnvironmentNameMaxLength {
+		return util.NewInvalidArgumentErrorf("invalid environment name %q", env.Name)
+	}
+	env.LowerName = strings.ToLower(env.Name)
+	if len(env.ReviewerUserIDs)+len(env.ReviewerTeamIDs) > EnvironmentMaxReviewers {
+		return util.NewInvalidArgumentErrorf("an environment can have at most %d reviewers", EnvironmentMaxReviewers)
+	}
+	if env.WaitTimer < 0 || env.WaitTimer > EnvironmentMaxWaitTimer {
+		return util.NewInvalidArgumentErrorf("wait timer must be between 0 and %d minutes", EnvironmentMaxWaitTimer)
+	}
+	for _, pattern := range env.BranchPatterns {
+		if _, err := glob.Compile(pattern, '/'); err != nil {
+			return util.NewInvalidArgumentErrorf("invalid branch pattern %q: %v", pattern, err)
+		}
+	}
+	slices.Sort(env.ReviewerUserIDs)
+	env.ReviewerUserIDs = slices.Compact(env.ReviewerUserIDs)
+	slices.Sort(env.ReviewerTeamIDs)
+	env.ReviewerTeamIDs = slices.Compact(env.ReviewerTeamIDs)
+	return nil
+}
+
+type FindEnvironmentsOptions struct {
+	db.ListOptions
+	IDs    []int64
+	RepoID int64
+	Name   string
+}
+
+func (opts FindEnvironmentsOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if len(opts.IDs) > 0 {
+		cond = cond.And(builder.In("id", opts.IDs))
+	}
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.Name != "" {
+		cond = cond.And(builder.Eq{"lower_name": strings.ToLower(opts.Name)})
+	}
+	return cond
+}
+
+func (opts FindEnvironmentsOptions) ToOrders() string {
+	return "lower_name ASC"
+}
+
+// GetEnvironmentByRepoAndID returns the environment of the repository by id
+func GetEnvironmentByRepoAndID(ctx context.Context, repoID, id int64) (*ActionEnvironment, error) {
+	var env ActionEnvironment
+	has, err := db.GetEngine(ctx).Where("id=? AND repo_id=?", id, repoID).Get(&env)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, fmt.Errorf("environment with id %d: %w", id, util.ErrNotExist)
+	}
+	return &env, nil
+}
+
+// GetEnvironmentByRepoAndName returns the environment of the repository by name, the name is case-insensitive
+func GetEnvironmentByRepoAndName(ctx context.Context, repoID int64, name string) (*ActionEnvironment, error) {
+	var env ActionEnvironment
+	has, err := db.GetEngine(ctx).Where("repo_id=? AND lower_name=?", repoID, strings.ToLower(name)).Get(&env)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, fmt.Errorf("environment %q: %w", name, util.ErrNotExist)
+	}
+	return &env, nil
+}
+
+// InsertEnvironment inserts a new environment after validating it
+func InsertEnvironment(ctx context.Context, env *ActionEnvironment) error {
+	if err := env.Validate(); err != nil {
+		return err
+	}
+	if _, err := GetEnvironmentByRepoAndName(ctx, env.RepoID, env.Name); err == nil {
+		return util.NewAlreadyExistErrorf("environment %q already exists", env.Name)
+	} else if !errors.Is(err, util.ErrNotExist) {
+		return err
+	}
+	return db.Insert(ctx, env)
+}
+
+// GetOrInsertEnvironment returns the environment of the repository by name,
+// an environment without protection rules is created if it doesn't exist, like GitHub does for the jobs targeting a new environment.
+func GetOrInsertEnvironment(ctx context.Context, repoID int64, name string) (*ActionEnvironment, error) {
+	env, err := GetEnvironmentByRepoAndName(ctx, repoID, name)
+	if err == nil || !errors.Is(err, util.ErrNotExist) {
+		return env, err
+	}
+	env = &ActionEnvironment{RepoID: repoID, Name: name}
+	return env, InsertEnvironment(ctx, env)
+}
+
+// UpdateEnvironment updates the environment after validating it
+func UpdateEnvironment(ctx context.Context, env *ActionEnvironment, cols ...string) error {
+	if err := env.Validate(); err != nil {
+		return err
+	}
+	_, err := db.GetEngine(ctx).ID(env.ID).Cols(cols...).Update(env)
+	return err
+}
+
+// DeleteEnvironment deletes the environment and its deployment reviews,
+// the secrets and the variables of the environment should be deleted by the caller.
+func DeleteEnvironment(ctx context.Context, env *ActionEnvironment) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.DeleteByID[ActionEnvironment](ctx, env.ID); err != nil {
+			return err
+		}
+		_, err := db.DeleteByBean(ctx, &ActionDeploymentReview{EnvironmentID: env.ID})
+		return err
+	})
+}
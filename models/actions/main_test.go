@@ -12,6 +12,7 @@ import (
 func TestMain(m *testing.M) {
 	unittest.MainTest(m, &unittest.TestOptions{
 		FixtureFiles: []string{
+			"action_artifact.yml",
 			"action_runner_token.yml",
 		},
 	})
@@ -0,0 +1,145 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// MetricsBuckets are the upper bounds in seconds of the buckets of the job wait time and the job duration histograms
+var MetricsBuckets = []float64{5, 15, 30, 60, 120, 300, 600, 1800, 3600, 7200, 21600}
+
+// HistogramSnapshot is a point-in-time copy of a histogram, the buckets map the upper bounds to the cumulative counts
+type HistogramSnapshot struct {
+	Count   uint64
+	Sum     float64
+	Buckets map[float64]uint64
+}
+
+// durationHistogram is a cumulative histogram kept in memory,
+// the observations are recorded when the tasks change their states so that scraping doesn't need to scan the tasks
+type durationHistogram struct {
+	mu     sync.Mutex
+	counts []uint64 // non-cumulative counts of MetricsBuckets
+	count  uint64
+	sum    float64
+}
+
+func (h *durationHistogram) observe(d time.Duration) {
+	seconds := max(d.Seconds(), 0)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.counts == nil {
+		h.counts = make([]uint64, len(MetricsBuckets))
+	}
+	if i, _ := slices.BinarySearch(MetricsBuckets, seconds); i < len(MetricsBuckets) {
+		h.counts[i]++
+	}
+	h.count++
+	h.sum += seconds
+}
+
+func (h *durationHistogram) snapshot() HistogramSnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s := HistogramSnapshot{
+		Count:   h.count,
+		Sum:     h.sum,
+		Buckets: make(map[float64]uint64, len(MetricsBuckets)),
+	}
+	var cumulative uint64
+	for i, bound := range MetricsBuckets {
+		if h.counts != nil {
+			cumulative += h.counts[i]
+		}
+		s.Buckets[bound] = cumulative
+	}
+	return s
+}
+
+var (
+	jobWaitHistogram     durationHistogram
+	jobDurationHistogram durationHistogram
+	taskFailuresCounter  atomic.Uint64
+)
+
+// observeTaskStarted records how long the job has been waiting for a runner,
+// the job is updated when it becomes waiting, so its updated time is when it was queued
+func observeTaskStarted(queued, started timeutil.TimeStamp) {
+	if queued > 0 && started >= queued {
+		jobWaitHistogram.observe(started.AsTime().Sub(queued.AsTime()))
+	}
+}
+
+// observeTaskStopped records the duration and the result of a finished task
+func observeTaskStopped(task *ActionTask) {
+	if task.Started > 0 && task.Stopped >= task.Started {
+		jobDurationHistogram.observe(task.Stopped.AsTime().Sub(task.Started.AsTime()))
+	}
+	if task.Status == StatusFailure {
+		taskFailuresCounter.Add(1)
+	}
+}
+
+// Metrics contains the statistics of Actions exposed to the metrics collector
+type Metrics struct {
+	QueuedJobs     map[string]int64 // the waiting jobs by the comma separated sorted "runs-on" labels
+	RunnersOnline  int64
+	RunnersBusy    int64
+	RunnersIdle    int64
+	TaskFailures   uint64
+	JobWaitTime    HistogramSnapshot
+	JobDuration    HistogramSnapshot
+	OldestQueuedAt timeutil.TimeStamp // when the earliest waiting job was queued, 0 if no job is waiting
+}
+
+// GetMetrics returns the statistics of Actions, only the indexed columns of the waiting jobs,
+// the online runners and the running tasks are queried so that it's cheap enough for every scrape
+func GetMetrics(ctx context.Context) (*Metrics, error) {
+	m := &Metrics{
+		QueuedJobs:   map[string]int64{},
+		TaskFailures: taskFailuresCounter.Load(),
+		JobWaitTime:  jobWaitHistogram.snapshot(),
+		JobDuration:  jobDurationHistogram.snapshot(),
+	}
+
+	var jobs []*ActionRunJob
+	if err := db.GetEngine(ctx).Cols("runs_on", "updated").
+		Where(builder.Eq{"status": StatusWaiting, "is_reusable_workflow": false}).
+		Find(&jobs); err != nil {
+		return nil, err
+	}
+	for _, job := range jobs {
+		labels := slices.Clone(job.RunsOn)
+		slices.Sort(labels)
+		m.QueuedJobs[strings.Join(slices.Compact(labels), ",")]++
+		if m.OldestQueuedAt == 0 || job.Updated < m.OldestQueuedAt {
+			m.OldestQueuedAt = job.Updated
+		}
+	}
+
+	onlineCond := builder.Gt{"last_online": time.Now().Add(-RunnerOfflineTime).Unix()}
+	var err error
+	if m.RunnersOnline, err = db.GetEngine(ctx).Where(onlineCond).Count(new(ActionRunner)); err != nil {
+		return nil, err
+	}
+	if m.RunnersBusy, err = db.GetEngine(ctx).Where(onlineCond).
+		And(builder.In("id", builder.Select("runner_id").From("action_task").Where(builder.Eq{"status": StatusRunning}))).
+		Count(new(ActionRunner)); err != nil {
+		return nil, err
+	}
+	m.RunnersIdle = m.RunnersOnline - m.RunnersBusy
+
+	return m, nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDurationHistogram(t *testing.T) {
+	var h durationHistogram
+	h.observe(5 * time.Second)
+	h.observe(40 * time.Second)
+	h.observe(24 * time.Hour)
+
+	s := h.snapshot()
+	assert.EqualValues(t, 3, s.Count)
+	assert.InDelta(t, 86445, s.Sum, 0.001)
+	assert.EqualValues(t, 1, s.Buckets[5])
+	assert.EqualValues(t, 1, s.Buckets[30])
+	assert.EqualValues(t, 2, s.Buckets[60])
+	assert.EqualValues(t, 2, s.Buckets[21600])
+}
+
+func TestGetMetrics(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	before, err := GetMetrics(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, db.Insert(ctx, []*ActionRunJob{
+		{RunID: 1000, Name: "a", Status: StatusWaiting, RunsOn: []string{"ubuntu-latest", "docker"}},
+		{RunID: 1000, Name: "b", Status: StatusWaiting, RunsOn: []string{"docker", "ubuntu-latest"}},
+		{RunID: 1000, Name: "c", Status: StatusRunning, RunsOn: []string{"docker", "ubuntu-latest"}},
+		{RunID: 1000, Name: "d", Status: StatusWaiting, RunsOn: []string{"docker", "ubuntu-latest"}, IsReusableWorkflow: true},
+	}))
+	now := timeutil.TimeStampNow()
+	busy := &ActionRunner{UUID: "metrics-busy", Name: "busy", TokenHash: "metrics-busy", LastOnline: now}
+	idle := &ActionRunner{UUID: "metrics-idle", Name: "idle", TokenHash: "metrics-idle", LastOnline: now}
+	offline := &ActionRunner{UUID: "metrics-offline", Name: "offline", TokenHash: "metrics-offline"}
+	for _, runner := range []*ActionRunner{busy, idle, offline} {
+		require.NoError(t, db.Insert(ctx, runner))
+	}
+	require.NoError(t, db.Insert(ctx, &ActionTask{RunnerID: busy.ID, Status: StatusRunning, TokenHash: "metrics-task"}))
+
+	observeTaskStopped(&ActionTask{Status: StatusFailure, Started: now - 90, Stopped: now})
+
+	after, err := GetMetrics(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, before.QueuedJobs["docker,ubuntu-latest"]+2, after.QueuedJobs["docker,ubuntu-latest"])
+	assert.NotZero(t, after.OldestQueuedAt)
+	assert.Equal(t, before.RunnersOnline+2, after.RunnersOnline)
+	assert.Equal(t, before.RunnersBusy+1, after.RunnersBusy)
+	assert.Equal(t, before.RunnersIdle+1, after.RunnersIdle)
+	assert.Equal(t, before.TaskFailures+1, after.TaskFailures)
+	assert.Equal(t, before.JobDuration.Count+1, after.JobDuration.Count)
+	assert.Equal(t, before.JobDuration.Buckets[60], after.JobDuration.Buckets[60])
+	assert.Equal(t, before.JobDuration.Buckets[120]+1, after.JobDuration.Buckets[120])
+}
+
+func TestObserveTaskStoppedAfterCommit(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	task := &ActionTask{Status: StatusRunning, TokenHash: "metrics-commit"}
+	require.NoError(t, db.Insert(ctx, task))
+	failures := taskFailuresCounter.Load()
+
+	// the rollback of the caller's transaction doesn't count
+	assert.Error(t, db.WithTx(ctx, func(ctx context.Context) error {
+		task.Status = StatusFailure
+		require.NoError(t, UpdateTask(ctx, task, "status"))
+		return errors.New("rollback")
+	}))
+	assert.Equal(t, failures, taskFailuresCounter.Load())
+
+	require.NoError(t, db.WithTx(ctx, func(ctx context.Context) error {
+		require.NoError(t, UpdateTask(ctx, task, "status"))
+		assert.Equal(t, failures, taskFailuresCounter.Load(), "the task is observed before the commit")
+		return nil
+	}))
+	assert.Equal(t, failures+1, taskFailuresCounter.Load())
+}
@@ -22,7 +22,6 @@ import (
 	"code.gitea.io/gitea/modules/util"
 	webhook_module "code.gitea.io/gitea/modules/webhook"
 
-	"github.com/nektos/act/pkg/jobparser"
 	"xorm.io/builder"
 )
 
@@ -30,7 +29,7 @@ import (
 type ActionRun struct {
 	ID                int64
 	Title             string
-	RepoID            int64                  `xorm:"index unique(repo_index)"`
+	RepoID            int64                  `xorm:"index unique(repo_index) index(repo_concurrency)"`
 	Repo              *repo_model.Repository `xorm:"-"`
 	OwnerID           int64                  `xorm:"index"`
 	WorkflowID        string                 `xorm:"index"`                    // the name of workflow file
@@ -53,9 +52,14 @@ type ActionRun struct {
 	Started timeutil.TimeStamp
 	Stopped timeutil.TimeStamp
 	// PreviousDuration is used for recording previous duration
-	PreviousDuration time.Duration
-	Created          timeutil.TimeStamp `xorm:"created"`
-	Updated          timeutil.TimeStamp `xorm:"updated"`
+	PreviousDuration  time.Duration
+	Attempt           int64              `xorm:"NOT NULL DEFAULT 1"`                          // the attempt number of the run, increased when the run is rerun
+	IsDebugEnabled    bool               `xorm:"NOT NULL DEFAULT FALSE"`                      // whether the debug logging is enabled for the current attempt
+	ConcurrencyGroup  string             `xorm:"index(repo_concurrency) NOT NULL DEFAULT ''"` // evaluated workflow-level concurrency group
+	ConcurrencyCancel bool               `xorm:"NOT NULL DEFAULT FALSE"`                      // whether to cancel in-progress runs of the same concurrency group
+	QuotaExceeded     bool               `xorm:"NOT NULL DEFAULT FALSE"`                      // whether the owner had exceeded the usage quota when the run was created
+	Created           timeutil.TimeStamp `xorm:"created"`
+	Updated           timeutil.TimeStamp `xorm:"updated"`
 }
 
 func init() {
@@ -181,7 +185,8 @@ func (run *ActionRun) IsSchedule() bool {
 	return run.ScheduleID > 0
 }
 
-func updateRepoRunsNumbers(ctx context.Context, repo *repo_model.Repository) error {
+// UpdateRepoRunsNumbers updates the number of runs and closed runs of the repository
+func UpdateRepoRunsNumbers(ctx context.Context, repo *repo_model.Repository) error {
 	_, err := db.GetEngine(ctx).ID(repo.ID).
 		NoAutoTime().
 		SetExpr("num_action_runs",
@@ -238,121 +243,154 @@ func CancelPreviousJobs(ctx context.Context, repoID int64, ref, workflowID strin
 			return cancelledJobs, err
 		}
 
-		// Iterate over each job and attempt to cancel it.
-		for _, job := range jobs {
-			// Skip jobs that are already in a terminal state (completed, cancelled, etc.).
-			status := job.Status
-			if status.IsDone() {
-				continue
-			}
+		cjs, err := CancelJobs(ctx, jobs)
+		cancelledJobs = append(cancelledJobs, cjs...)
+		if err != nil {
+			return cancelledJobs, err
+		}
+	}
 
-			// If the job has no associated task (probably an error), set its status to 'Cancelled' and stop it.
-			if job.TaskID == 0 {
-				job.Status = StatusCancelled
-				job.Stopped = timeutil.TimeStampNow()
-
-				// Update the job's status and stopped time in the database.
-				n, err := UpdateRunJob(ctx, job, builder.Eq{"task_id": 0}, "status", "stopped")
-				if err != nil {
-					return cancelledJobs, err
-				}
-
-				// If the update affected 0 rows, it means the job has changed in the meantime, so we need to try again.
-				if n == 0 {
-					return cancelledJobs, errors.New("job has changed, try again")
-				}
-
-				cancelledJobs = append(cancelledJobs, job)
-				// Continue with the next job.
-				continue
-			}
+	// Return nil to indicate successful cancellation of all running and waiting jobs.
+	return cancelledJobs, nil
+}
 
-			// If the job has an associated task, try to stop the task, effectively cancelling the job.
-			if err := StopTask(ctx, job.TaskID, StatusCancelled); err != nil {
+// CancelJobs cancels the given jobs which are not done yet, and returns the cancelled jobs.
+func CancelJobs(ctx context.Context, jobs []*ActionRunJob) ([]*ActionRunJob, error) {
+	cancelledJobs := make([]*ActionRunJob, 0, len(jobs))
+
+	// Iterate over each job and attempt to cancel it.
+	for _, job := range jobs {
+		// Skip jobs that are already in a terminal state (completed, cancelled, etc.).
+		status := job.Status
+		if status.IsDone() {
+			continue
+		}
+
+		// If the job has no associated task (probably an error), set its status to 'Cancelled' and stop it.
+		if job.TaskID == 0 {
+			job.Status = StatusCancelled
+			job.Stopped = timeutil.TimeStampNow()
+
+			// Update the job's status and stopped time in the database.
+			n, err := UpdateRunJob(ctx, job, builder.Eq{"task_id": 0}, "status", "stopped")
+			if err != nil {
 				return cancelledJobs, err
 			}
+
+			// If the update affected 0 rows, it means the job has changed in the meantime, so we need to try again.
+			if n == 0 {
+				return cancelledJobs, errors.New("job has changed, try again")
+			}
+
 			cancelledJobs = append(cancelledJobs, job)
+			// Continue with the next job.
+			continue
 		}
+
+		// If the job has an associated task, try to stop the task, effectively cancelling the job.
+		if err := StopTask(ctx, job.TaskID, StatusCancelled); err != nil {
+			return cancelledJobs, err
+		}
+		job.Status = StatusCancelled
+		cancelledJobs = append(cancelledJobs, job)
 	}
 
-	// Return nil to indicate successful cancellation of all running and waiting jobs.
 	return cancelledJobs, nil
 }
 
-// InsertRun inserts a run
-// The title will be cut off at 255 characters if it's longer than 255 characters.
-func InsertRun(ctx context.Context, run *ActionRun, jobs []*jobparser.SingleWorkflow) error {
-	ctx, committer, err := db.TxContext(ctx)
+// GetConcurrentRunsAndJobs returns the runs and the jobs of the repository which belong to the concurrency group and have one of the given statuses.
+// Workflow-level and job-level concurrency groups share the same namespace, like GitHub does.
+func GetConcurrentRunsAndJobs(ctx context.Context, repoID int64, concurrencyGroup string, statuses []Status) ([]*ActionRun, []*ActionRunJob, error) {
+	runs, err := db.Find[ActionRun](ctx, FindRunOptions{
+		RepoID:           repoID,
+		ConcurrencyGroup: concurrencyGroup,
+		Status:           statuses,
+	})
 	if err != nil {
-		return err
+		return nil, nil, fmt.Errorf("find runs: %w", err)
 	}
-	defer committer.Close()
 
-	index, err := db.GetNextResourceIndex(ctx, "action_run_index", run.RepoID)
+	jobs, err := db.Find[ActionRunJob](ctx, FindRunJobOptions{
+		RepoID:           repoID,
+		ConcurrencyGroup: concurrencyGroup,
+		Statuses:         statuses,
+	})
 	if err != nil {
-		return err
+		return nil, nil, fmt.Errorf("find jobs: %w", err)
 	}
-	run.Index = index
-	run.Title = util.EllipsisDisplayString(run.Title, 255)
 
-	if err := db.Insert(ctx, run); err != nil {
-		return err
+	return runs, jobs, nil
+}
+
+// findConcurrentJobs returns the jobs which belong to the concurrency group with one of the given statuses,
+// and the unfinished jobs of the runs which belong to the concurrency group with one of the given statuses.
+func findConcurrentJobs(ctx context.Context, repoID int64, concurrencyGroup string, statuses []Status) ([]*ActionRunJob, error) {
+	runs, jobs, err := GetConcurrentRunsAndJobs(ctx, repoID, concurrencyGroup, statuses)
+	if err != nil {
+		return nil, err
 	}
 
-	if run.Repo == nil {
-		repo, err := repo_model.GetRepositoryByID(ctx, run.RepoID)
+	for _, run := range runs {
+		runJobs, err := db.Find[ActionRunJob](ctx, FindRunJobOptions{RunID: run.ID})
 		if err != nil {
-			return err
+			return nil, fmt.Errorf("find jobs of run %d: %w", run.ID, err)
+		}
+		for _, job := range runJobs {
+			if !job.Status.IsDone() {
+				jobs = append(jobs, job)
+			}
 		}
-		run.Repo = repo
 	}
+	return jobs, nil
+}
 
-	if err := updateRepoRunsNumbers(ctx, run.Repo); err != nil {
-		return err
+// concurrencyStatusesToCancel returns the statuses of the runs and jobs that should be cancelled by a new one in the same concurrency group.
+// A concurrency group can have at most one pending run or job, so the pending (blocked) ones are always cancelled,
+// and the in-progress ones are cancelled only if cancel-in-progress is true.
+func concurrencyStatusesToCancel(cancelInProgress bool) []Status {
+	if cancelInProgress {
+		return []Status{StatusRunning, StatusWaiting, StatusBlocked}
 	}
+	return []Status{StatusBlocked}
+}
 
-	runJobs := make([]*ActionRunJob, 0, len(jobs))
-	var hasWaiting bool
-	for _, v := range jobs {
-		id, job := v.Job()
-		needs := job.Needs()
-		if err := v.SetJob(id, job.EraseNeeds()); err != nil {
-			return err
-		}
-		payload, _ := v.Marshal()
-		status := StatusWaiting
-		if len(needs) > 0 || run.NeedApproval {
-			status = StatusBlocked
-		} else {
-			hasWaiting = true
-		}
-		job.Name = util.EllipsisDisplayString(job.Name, 255)
-		runJobs = append(runJobs, &ActionRunJob{
-			RunID:             run.ID,
-			RepoID:            run.RepoID,
-			OwnerID:           run.OwnerID,
-			CommitSHA:         run.CommitSHA,
-			IsForkPullRequest: run.IsForkPullRequest,
-			Name:              job.Name,
-			WorkflowPayload:   payload,
-			JobID:             id,
-			Needs:             needs,
-			RunsOn:            job.RunsOn(),
-			Status:            status,
-		})
+// CancelPreviousJobsByRunConcurrency cancels the jobs of other runs which belong to the workflow-level concurrency group of the run.
+func CancelPreviousJobsByRunConcurrency(ctx context.Context, actionRun *ActionRun) ([]*ActionRunJob, error) {
+	if actionRun.ConcurrencyGroup == "" {
+		return nil, nil
 	}
-	if err := db.Insert(ctx, runJobs); err != nil {
-		return err
+
+	jobs, err := findConcurrentJobs(ctx, actionRun.RepoID, actionRun.ConcurrencyGroup, concurrencyStatusesToCancel(actionRun.ConcurrencyCancel))
+	if err != nil {
+		return nil, err
 	}
 
-	// if there is a job in the waiting status, increase tasks version.
-	if hasWaiting {
-		if err := IncreaseTaskVersion(ctx, run.OwnerID, run.RepoID); err != nil {
-			return err
+	jobsToCancel := make([]*ActionRunJob, 0, len(jobs))
+	for _, job := range jobs {
+		if job.RunID != actionRun.ID {
+			jobsToCancel = append(jobsToCancel, job)
 		}
 	}
+	return CancelJobs(ctx, jobsToCancel)
+}
 
-	return committer.Commit()
+// ShouldBlockRunByConcurrency returns whether the jobs of the run should be blocked
+// because another run or job of the same workflow-level concurrency group is in progress.
+func ShouldBlockRunByConcurrency(ctx context.Context, actionRun *ActionRun) (bool, error) {
+	if actionRun.ConcurrencyGroup == "" {
+		return false, nil
+	}
+
+	jobs, err := findConcurrentJobs(ctx, actionRun.RepoID, actionRun.ConcurrencyGroup, []Status{StatusRunning, StatusWaiting})
+	if err != nil {
+		return false, err
+	}
+	for _, job := range jobs {
+		if job.RunID != actionRun.ID {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 func GetRunByRepoAndID(ctx context.Context, repoID, runID int64) (*ActionRun, error) {
@@ -437,7 +475,7 @@ func UpdateRun(ctx context.Context, run *ActionRun, cols ...string) error {
 		if err = run.LoadRepo(ctx); err != nil {
 			return err
 		}
-		if err := updateRepoRunsNumbers(ctx, run.Repo); err != nil {
+		if err := UpdateRepoRunsNumbers(ctx, run.Repo); err != nil {
 			return err
 		}
 	}
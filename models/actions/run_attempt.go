@@ -0,0 +1,134 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ActionRunAttempt records a previous attempt of a run.
+// The latest attempt is always the run itself, so the record is only created when the run is rerun,
+// it keeps the results of the jobs in the attempt, and the logs can still be found by the ids of the tasks.
+type ActionRunAttempt struct {
+	ID               int64
+	RepoID           int64 `xorm:"index"`
+	RunID            int64 `xorm:"unique(run_attempt)"`
+	Attempt          int64 `xorm:"unique(run_attempt)"`
+	Status           Status
+	IsDebugEnabled   bool `xorm:"NOT NULL DEFAULT FALSE"`
+	Started          timeutil.TimeStamp
+	Stopped          timeutil.TimeStamp
+	PreviousDuration time.Duration
+	Jobs             []*ActionRunAttemptJob `xorm:"JSON LONGTEXT"`
+	Created          timeutil.TimeStamp     `xorm:"created"`
+}
+
+// ActionRunAttemptJob is the result of a job in a previous attempt of a run
+type ActionRunAttemptJob struct {
+	ID      int64              `json:"id"` // the id of the ActionRunJob
+	Status  Status             `json:"status"`
+	Attempt int64              `json:"attempt"`
+	TaskID  int64              `json:"task_id"`
+	Started timeutil.TimeStamp `json:"started"`
+	Stopped timeutil.TimeStamp `json:"stopped"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionRunAttempt))
+}
+
+// NewRunAttempt creates the record of the current attempt of the run before it's rerun
+func NewRunAttempt(run *ActionRun, jobs []*ActionRunJob) *ActionRunAttempt {
+	attempt := &ActionRunAttempt{
+		RepoID:           run.RepoID,
+		RunID:            run.ID,
+		Attempt:          run.Attempt,
+		Status:           run.Status,
+		IsDebugEnabled:   run.IsDebugEnabled,
+		Started:          run.Started,
+		Stopped:          run.Stopped,
+		PreviousDuration: run.PreviousDuration,
+		Jobs:             make([]*ActionRunAttemptJob, 0, len(jobs)),
+	}
+	for _, job := range jobs {
+		attempt.Jobs = append(attempt.Jobs, &ActionRunAttemptJob{
+			ID:      job.ID,
+			Status:  job.Status,
+			Attempt: job.Attempt,
+			TaskID:  job.TaskID,
+			Started: job.Started,
+			Stopped: job.Stopped,
+		})
+	}
+	return attempt
+}
+
+// ApplyToRun returns a copy of the run with the results of the attempt
+func (attempt *ActionRunAttempt) ApplyToRun(run *ActionRun) *ActionRun {
+	r := *run
+	r.Attempt = attempt.Attempt
+	r.Status = attempt.Status
+	r.IsDebugEnabled = attempt.IsDebugEnabled
+	r.Started = attempt.Started
+	r.Stopped = attempt.Stopped
+	r.PreviousDuration = attempt.PreviousDuration
+	return &r
+}
+
+// ApplyToJobs returns the copies of the jobs with the results of the attempt,
+// the jobs which didn't exist in the attempt are skipped.
+func (attempt *ActionRunAttempt) ApplyToJobs(jobs []*ActionRunJob) ActionJobList {
+	results := make(map[int64]*ActionRunAttemptJob, len(attempt.Jobs))
+	for _, j := range attempt.Jobs {
+		results[j.ID] = j
+	}
+	ret := make(ActionJobList, 0, len(attempt.Jobs))
+	for _, job := range jobs {
+		result, ok := results[job.ID]
+		if !ok {
+			continue
+		}
+		j := *job
+		j.Status = result.Status
+		j.Attempt = result.Attempt
+		j.TaskID = result.TaskID
+		j.Started = result.Started
+		j.Stopped = result.Stopped
+		ret = append(ret, &j)
+	}
+	return ret
+}
+
+// Duration returns the duration of the attempt
+func (attempt *ActionRunAttempt) Duration() time.Duration {
+	return calculateDuration(attempt.Started, attempt.Stopped, attempt.Status) + attempt.PreviousDuration
+}
+
+// InsertRunAttempt inserts the record of a previous attempt of a run
+func InsertRunAttempt(ctx context.Context, attempt *ActionRunAttempt) error {
+	return db.Insert(ctx, attempt)
+}
+
+// GetRunAttempt returns the record of a previous attempt of the run
+func GetRunAttempt(ctx context.Context, runID, attempt int64) (*ActionRunAttempt, error) {
+	var runAttempt ActionRunAttempt
+	has, err := db.GetEngine(ctx).Where("run_id=? AND attempt=?", runID, attempt).Get(&runAttempt)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, util.NewNotExistErrorf("attempt %d of run %d", attempt, runID)
+	}
+	return &runAttempt, nil
+}
+
+// GetRunAttempts returns the records of the previous attempts of the run, ordered by the attempt number
+func GetRunAttempts(ctx context.Context, runID int64) ([]*ActionRunAttempt, error) {
+	var attempts []*ActionRunAttempt
+	return attempts, db.GetEngine(ctx).Where("run_id=?", runID).OrderBy("attempt ASC").Find(&attempts)
+}
@@ -0,0 +1,55 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunAttempt(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	run := &ActionRun{ID: 1000, RepoID: 100, Attempt: 1, Status: StatusFailure, Started: 100, Stopped: 160}
+	jobs := []*ActionRunJob{
+		{ID: 1, RunID: run.ID, JobID: "build", Status: StatusSuccess, Attempt: 1, TaskID: 10},
+		{ID: 2, RunID: run.ID, JobID: "test", Status: StatusFailure, Attempt: 1, TaskID: 11},
+	}
+	require.NoError(t, InsertRunAttempt(ctx, NewRunAttempt(run, jobs)))
+
+	// the jobs are rerun in the new attempt
+	run.Attempt = 2
+	run.Status = StatusRunning
+	jobs[1].Status = StatusRunning
+	jobs[1].Attempt = 2
+	jobs[1].TaskID = 12
+
+	attempts, err := GetRunAttempts(ctx, run.ID)
+	require.NoError(t, err)
+	require.Len(t, attempts, 1)
+	_, err = GetRunAttempt(ctx, run.ID, 2)
+	assert.ErrorIs(t, err, util.ErrNotExist)
+
+	attempt, err := GetRunAttempt(ctx, run.ID, 1)
+	require.NoError(t, err)
+	attemptRun := attempt.ApplyToRun(run)
+	assert.EqualValues(t, 1, attemptRun.Attempt)
+	assert.Equal(t, StatusFailure, attemptRun.Status)
+	assert.EqualValues(t, 60, attemptRun.Duration().Seconds())
+	assert.EqualValues(t, 2, run.Attempt, "the run should not be changed")
+
+	attemptJobs := attempt.ApplyToJobs(jobs)
+	require.Len(t, attemptJobs, 2)
+	assert.Equal(t, StatusFailure, attemptJobs[1].Status)
+	assert.EqualValues(t, 1, attemptJobs[1].Attempt)
+	assert.EqualValues(t, 11, attemptJobs[1].TaskID)
+	assert.EqualValues(t, 12, jobs[1].TaskID, "the jobs should not be changed")
+}
@@ -22,7 +22,7 @@ type ActionRunJob struct {
 	ID                int64
 	RunID             int64                  `xorm:"index"`
 	Run               *ActionRun             `xorm:"-"`
-	RepoID            int64                  `xorm:"index"`
+	RepoID            int64                  `xorm:"index index(repo_concurrency)"`
 	Repo              *repo_model.Repository `xorm:"-"`
 	OwnerID           int64                  `xorm:"index"`
 	CommitSHA         string                 `xorm:"index"`
@@ -35,10 +35,28 @@ type ActionRunJob struct {
 	RunsOn            []string `xorm:"JSON TEXT"`
 	TaskID            int64    // the latest task of the job
 	Status            Status   `xorm:"index"`
-	Started           timeutil.TimeStamp
-	Stopped           timeutil.TimeStamp
-	Created           timeutil.TimeStamp `xorm:"created"`
-	Updated           timeutil.TimeStamp `xorm:"updated index"`
+
+	RawConcurrency         string // raw concurrency defined in the job, evaluated after the job is ready to run
+	IsConcurrencyEvaluated bool   // whether RawConcurrency has been evaluated into ConcurrencyGroup and ConcurrencyCancel
+	ConcurrencyGroup       string `xorm:"index(repo_concurrency) NOT NULL DEFAULT ''"` // evaluated job-level concurrency group
+	ConcurrencyCancel      bool   `xorm:"NOT NULL DEFAULT FALSE"`                      // whether to cancel in-progress jobs of the same concurrency group
+
+	IsReusableWorkflow bool              `xorm:"NOT NULL DEFAULT FALSE"`   // whether the job calls a reusable workflow, such a job is never picked by runners
+	ParentJobID        int64             `xorm:"index NOT NULL DEFAULT 0"` // the id of the job which calls the reusable workflow this job belongs to
+	Outputs            map[string]string `xorm:"JSON TEXT"`                // the outputs of a job calling a reusable workflow
+
+	RawEnvironment         string             // raw environment defined in the job, evaluated after the job is ready to run
+	IsEnvironmentEvaluated bool               `xorm:"NOT NULL DEFAULT FALSE"`   // whether RawEnvironment has been evaluated into EnvironmentID
+	EnvironmentID          int64              `xorm:"index NOT NULL DEFAULT 0"` // the environment targeted by the job
+	EnvironmentWaitUntil   timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`       // when the wait timer of the environment expires
+
+	RawMatrix   string `xorm:"TEXT"`               // raw matrix computed by expressions, expanded by the job emitter after the needs are done
+	MaxParallel int    `xorm:"NOT NULL DEFAULT 0"` // the maximum number of the running jobs of the same matrix, 0 means unlimited
+
+	Started timeutil.TimeStamp
+	Stopped timeutil.TimeStamp
+	Created timeutil.TimeStamp `xorm:"created"`
+	Updated timeutil.TimeStamp `xorm:"updated index"`
 }
 
 func init() {
@@ -49,6 +67,13 @@ func (job *ActionRunJob) Duration() time.Duration {
 	return calculateDuration(job.Started, job.Stopped, job.Status)
 }
 
+// IsStartedByJobEmitter returns whether the job has to be started by the job emitter even if it doesn't need other jobs,
+// since something has to be checked before it runs: the reusable workflow it calls, the environment it targets,
+// its dynamic matrix or the max-parallel limit of its matrix.
+func (job *ActionRunJob) IsStartedByJobEmitter() bool {
+	return job.IsReusableWorkflow || job.RawEnvironment != "" || job.RawMatrix != "" || job.MaxParallel > 0
+}
+
 func (job *ActionRunJob) LoadRun(ctx context.Context) error {
 	if job.Run == nil {
 		run, err := GetRunByRepoAndID(ctx, job.RepoID, job.RunID)
@@ -165,6 +190,47 @@ func UpdateRunJob(ctx context.Context, job *ActionRunJob, cond builder.Cond, col
 	return affected, nil
 }
 
+// CancelPreviousJobsByJobConcurrency cancels other jobs and the jobs of other runs which belong to the job-level concurrency group of the job.
+// The concurrency of the job must have been evaluated.
+func CancelPreviousJobsByJobConcurrency(ctx context.Context, job *ActionRunJob) ([]*ActionRunJob, error) {
+	if job.ConcurrencyGroup == "" {
+		return nil, nil
+	}
+
+	jobs, err := findConcurrentJobs(ctx, job.RepoID, job.ConcurrencyGroup, concurrencyStatusesToCancel(job.ConcurrencyCancel))
+	if err != nil {
+		return nil, err
+	}
+
+	jobsToCancel := make([]*ActionRunJob, 0, len(jobs))
+	for _, j := range jobs {
+		if j.ID != job.ID && j.RunID != job.RunID {
+			jobsToCancel = append(jobsToCancel, j)
+		}
+	}
+	return CancelJobs(ctx, jobsToCancel)
+}
+
+// ShouldBlockJobByConcurrency returns whether the job should be blocked
+// because another job or run of the same job-level concurrency group is in progress.
+// The concurrency of the job must have been evaluated.
+func ShouldBlockJobByConcurrency(ctx context.Context, job *ActionRunJob) (bool, error) {
+	if job.ConcurrencyGroup == "" {
+		return false, nil
+	}
+
+	jobs, err := findConcurrentJobs(ctx, job.RepoID, job.ConcurrencyGroup, []Status{StatusRunning, StatusWaiting})
+	if err != nil {
+		return false, err
+	}
+	for _, j := range jobs {
+		if j.ID != job.ID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func AggregateJobStatus(jobs []*ActionRunJob) Status {
 	allSuccessOrSkipped := len(jobs) != 0
 	allSkipped := len(jobs) != 0
@@ -75,6 +75,10 @@ type FindRunJobOptions struct {
 	CommitSHA     string
 	Statuses      []Status
 	UpdatedBefore timeutil.TimeStamp
+
+	ConcurrencyGroup string
+
+	EnvironmentWaitUntilBefore timeutil.TimeStamp // the jobs whose wait timers of environments have expired
 }
 
 func (opts FindRunJobOptions) ToConds() builder.Cond {
@@ -94,6 +98,13 @@ func (opts FindRunJobOptions) ToConds() builder.Cond {
 	if opts.UpdatedBefore > 0 {
 		cond = cond.And(builder.Lt{"`action_run_job`.updated": opts.UpdatedBefore})
 	}
+	if opts.ConcurrencyGroup != "" {
+		cond = cond.And(builder.Eq{"`action_run_job`.concurrency_group": opts.ConcurrencyGroup})
+	}
+	if opts.EnvironmentWaitUntilBefore > 0 {
+		cond = cond.And(builder.Gt{"`action_run_job`.environment_wait_until": 0}).
+			And(builder.Lte{"`action_run_job`.environment_wait_until": opts.EnvironmentWaitUntilBefore})
+	}
 	return cond
 }
 
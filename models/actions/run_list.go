@@ -71,8 +71,11 @@ type FindRunOptions struct {
 	TriggerUserID int64
 	TriggerEvent  webhook_module.HookEventType
 	Approved      bool // not util.OptionalBool, it works only when it's true
+	NeedApproval  bool // not util.OptionalBool, it works only when it's true
 	Status        []Status
 	CommitSHA     string
+
+	ConcurrencyGroup string
 }
 
 func (opts FindRunOptions) ToConds() builder.Cond {
@@ -89,6 +92,9 @@ func (opts FindRunOptions) ToConds() builder.Cond {
 	if opts.Approved {
 		cond = cond.And(builder.Gt{"`action_run`.approved_by": 0})
 	}
+	if opts.NeedApproval {
+		cond = cond.And(builder.Eq{"`action_run`.need_approval": true})
+	}
 	if len(opts.Status) > 0 {
 		cond = cond.And(builder.In("`action_run`.status", opts.Status))
 	}
@@ -101,6 +107,9 @@ func (opts FindRunOptions) ToConds() builder.Cond {
 	if opts.CommitSHA != "" {
 		cond = cond.And(builder.Eq{"`action_run`.commit_sha": opts.CommitSHA})
 	}
+	if opts.ConcurrencyGroup != "" {
+		cond = cond.And(builder.Eq{"`action_run`.concurrency_group": opts.ConcurrencyGroup})
+	}
 	return cond
 }
 
@@ -0,0 +1,73 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShouldBlockByConcurrency(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	runningRun := &ActionRun{RepoID: 100, Index: 1, ConcurrencyGroup: "group-a", Status: StatusRunning}
+	require.NoError(t, db.Insert(ctx, runningRun))
+	runningJob := &ActionRunJob{RunID: runningRun.ID, RepoID: 100, JobID: "build", Status: StatusRunning}
+	require.NoError(t, db.Insert(ctx, runningJob))
+
+	pendingRun := &ActionRun{RepoID: 100, Index: 2, ConcurrencyGroup: "group-a", Status: StatusBlocked}
+	require.NoError(t, db.Insert(ctx, pendingRun))
+	pendingJob := &ActionRunJob{RunID: pendingRun.ID, RepoID: 100, JobID: "build", Status: StatusBlocked}
+	require.NoError(t, db.Insert(ctx, pendingJob))
+
+	runs, jobs, err := GetConcurrentRunsAndJobs(ctx, 100, "group-a", []Status{StatusRunning, StatusWaiting})
+	require.NoError(t, err)
+	if assert.Len(t, runs, 1) {
+		assert.Equal(t, runningRun.ID, runs[0].ID)
+	}
+	assert.Empty(t, jobs)
+
+	t.Run("run-level", func(t *testing.T) {
+		blocked, err := ShouldBlockRunByConcurrency(ctx, pendingRun)
+		require.NoError(t, err)
+		assert.True(t, blocked)
+
+		blocked, err = ShouldBlockRunByConcurrency(ctx, runningRun)
+		require.NoError(t, err)
+		assert.False(t, blocked)
+
+		blocked, err = ShouldBlockRunByConcurrency(ctx, &ActionRun{RepoID: 100, ConcurrencyGroup: "group-b"})
+		require.NoError(t, err)
+		assert.False(t, blocked)
+
+		blocked, err = ShouldBlockRunByConcurrency(ctx, &ActionRun{RepoID: 101, ConcurrencyGroup: "group-a"})
+		require.NoError(t, err)
+		assert.False(t, blocked)
+	})
+
+	t.Run("job-level", func(t *testing.T) {
+		job := &ActionRunJob{RunID: pendingRun.ID + 1, RepoID: 100, JobID: "deploy", ConcurrencyGroup: "group-a", IsConcurrencyEvaluated: true}
+		blocked, err := ShouldBlockJobByConcurrency(ctx, job)
+		require.NoError(t, err)
+		assert.True(t, blocked)
+
+		job.ConcurrencyGroup = "group-b"
+		blocked, err = ShouldBlockJobByConcurrency(ctx, job)
+		require.NoError(t, err)
+		assert.False(t, blocked)
+
+		runningJob.ConcurrencyGroup = "group-b"
+		_, err = db.GetEngine(ctx).ID(runningJob.ID).Cols("concurrency_group").Update(runningJob)
+		require.NoError(t, err)
+		blocked, err = ShouldBlockJobByConcurrency(ctx, job)
+		require.NoError(t, err)
+		assert.True(t, blocked)
+	})
+}
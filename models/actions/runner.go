@@ -61,6 +61,8 @@ type ActionRunner struct {
 	AgentLabels []string `xorm:"TEXT"`
 	// Store if this is a runner that only ever get one single job assigned
 	Ephemeral bool `xorm:"ephemeral NOT NULL DEFAULT false"`
+	// The runner group of the instance-level or org-level runner, 0 means it doesn't belong to any group
+	GroupID int64 `xorm:"index NOT NULL DEFAULT 0"`
 
 	Created timeutil.TimeStamp `xorm:"created"`
 	Updated timeutil.TimeStamp `xorm:"updated"`
@@ -191,6 +193,7 @@ type FindRunnerOptions struct {
 	Sort          string
 	Filter        string
 	IsOnline      optional.Option[bool]
+	GroupID       int64
 	WithAvailable bool // not only runners belong to, but also runners can be used
 }
 
@@ -220,6 +223,10 @@ func (opts FindRunnerOptions) ToConds() builder.Cond {
 		cond = cond.And(c)
 	}
 
+	if opts.GroupID > 0 {
+		cond = cond.And(builder.Eq{"group_id": opts.GroupID})
+	}
+
 	if opts.Filter != "" {
 		cond = cond.And(builder.Like{"name", opts.Filter})
 	}
@@ -0,0 +1,190 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/gobwas/glob"
+	"xorm.io/builder"
+)
+
+// RunnerGroupNameMaxLength is the max length of the name of a runner group
+const RunnerGroupNameMaxLength = 255
+
+// ActionRunnerGroup represents a named group of the instance-level or org-level runners.
+// The runners of a group only pick the jobs of the runs which are allowed by the access policies of the group,
+// the runners which don't belong to any group can be used by all repositories of their owners like before.
+type ActionRunnerGroup struct {
+	ID               int64
+	OwnerID          int64    `xorm:"UNIQUE(owner_name) NOT NULL"` // 0 means an instance-level group
+	Name             string   `xorm:"NOT NULL"`
+	LowerName        string   `xorm:"UNIQUE(owner_name) NOT NULL"`
+	RestrictRepos    bool     `xorm:"NOT NULL DEFAULT FALSE"` // whether only the selected repositories can use the runners of the group
+	RepoIDs          []int64  `xorm:"JSON TEXT"`              // the selected repositories
+	WorkflowPatterns []string `xorm:"JSON TEXT"`              // the glob patterns of the workflow files which can use the runners, empty means all
+	RefPatterns      []string `xorm:"JSON TEXT"`              // the glob patterns of the refs which can use the runners, empty means all
+
+	Created timeutil.TimeStamp `xorm:"created"`
+	Updated timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionRunnerGroup))
+}
+
+// IsRepoAllowed returns whether the runners of the group can run the jobs of the repository
+func (g *ActionRunnerGroup) IsRepoAllowed(repoID int64) bool {
+	return !g.RestrictRepos || slices.Contains(g.RepoIDs, repoID)
+}
+
+// IsRunAllowed returns whether the runners of the group can run the jobs of the run
+func (g *ActionRunnerGroup) IsRunAllowed(run *ActionRun) bool {
+	return g.IsRepoAllowed(run.RepoID) &&
+		matchRunnerGroupPatterns(g.ID, g.WorkflowPatterns, run.WorkflowID, nil) &&
+		matchRunnerGroupPatterns(g.ID, g.RefPatterns, run.Ref, []rune{'/'})
+}
+
+// HasRunPatterns returns whether the group restricts the workflows or the refs of the runs
+func (g *ActionRunnerGroup) HasRunPatterns() bool {
+	return len(g.WorkflowPatterns) > 0 || len(g.RefPatterns) > 0
+}
+
+func matchRunnerGroupPatterns(groupID int64, patterns []string, s string, separators []rune) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		g, err := glob.Compile(pattern, separators...)
+		if err != nil {
+			log.Warn("Invalid pattern %q of runner group %d: %v", pattern, groupID, err)
+			continue
+		}
+		if g.Match(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the name and the access policies of the group
+func (g *ActionRunnerGroup) Validate() error {
+	g.Name = strings.TrimSpace(g.Name)
+	if g.Name == "" || len(g.Name) > RunnerGroupNameMaxLength {
+		return util.NewInvalidArgumentErrorf("invalid runner group name %q", g.Name)
+	}
+	g.LowerName = strings.ToLower(g.Name)
+	for _, pattern := range g.WorkflowPatterns {
+		if _, err := glob.Compile(pattern); err != nil {
+			return util.NewInvalidArgumentErrorf("invalid workflow pattern %q: %v", pattern, err)
+		}
+	}
+	for _, pattern := range g.RefPatterns {
+		if _, err := glob.Compile(pattern, '/'); err != nil {
+			return util.NewInvalidArgumentErrorf("invalid ref pattern %q: %v", pattern, err)
+		}
+	}
+	slices.Sort(g.RepoIDs)
+	g.RepoIDs = slices.Compact(g.RepoIDs)
+	return nil
+}
+
+type FindRunnerGroupsOptions struct {
+	db.ListOptions
+	OwnerID int64
+}
+
+func (opts FindRunnerGroupsOptions) ToConds() builder.Cond {
+	return builder.Eq{"owner_id": opts.OwnerID}
+}
+
+func (opts FindRunnerGroupsOptions) ToOrders() string {
+	return "lower_name ASC"
+}
+
+// GetRunnerGroupByID returns the runner group by id
+func GetRunnerGroupByID(ctx context.Context, id int64) (*ActionRunnerGroup, error) {
+	var group ActionRunnerGroup
+	has, err := db.GetEngine(ctx).ID(id).Get(&group)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, fmt.Errorf("runner group with id %d: %w", id, util.ErrNotExist)
+	}
+	return &group, nil
+}
+
+// GetRunnerGroupByOwnerAndID returns the runner group of the owner by id, ownerID 0 means the instance-level groups
+func GetRunnerGroupByOwnerAndID(ctx context.Context, ownerID, id int64) (*ActionRunnerGroup, error) {
+	group, err := GetRunnerGroupByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if group.OwnerID != ownerID {
+		return nil, fmt.Errorf("runner group with id %d: %w", id, util.ErrNotExist)
+	}
+	return group, nil
+}
+
+// GetRunnerGroupByOwnerAndName returns the runner group of the owner by name, the name is case-insensitive
+func GetRunnerGroupByOwnerAndName(ctx context.Context, ownerID int64, name string) (*ActionRunnerGroup, error) {
+	var group ActionRunnerGroup
+	has, err := db.GetEngine(ctx).Where("owner_id=? AND lower_name=?", ownerID, strings.ToLower(name)).Get(&group)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, fmt.Errorf("runner group %q: %w", name, util.ErrNotExist)
+	}
+	return &group, nil
+}
+
+// InsertRunnerGroup inserts a new runner group after validating it
+func InsertRunnerGroup(ctx context.Context, group *ActionRunnerGroup) error {
+	if err := group.Validate(); err != nil {
+		return err
+	}
+	if _, err := GetRunnerGroupByOwnerAndName(ctx, group.OwnerID, group.Name); err == nil {
+		return util.NewAlreadyExistErrorf("runner group %q already exists", group.Name)
+	} else if !errors.Is(err, util.ErrNotExist) {
+		return err
+	}
+	return db.Insert(ctx, group)
+}
+
+// UpdateRunnerGroup updates the runner group after validating it
+func UpdateRunnerGroup(ctx context.Context, group *ActionRunnerGroup, cols ...string) error {
+	if err := group.Validate(); err != nil {
+		return err
+	}
+	if existing, err := GetRunnerGroupByOwnerAndName(ctx, group.OwnerID, group.Name); err == nil && existing.ID != group.ID {
+		return util.NewAlreadyExistErrorf("runner group %q already exists", group.Name)
+	} else if err != nil && !errors.Is(err, util.ErrNotExist) {
+		return err
+	}
+	if slices.Contains(cols, "name") {
+		cols = append(cols, "lower_name")
+	}
+	_, err := db.GetEngine(ctx).ID(group.ID).Cols(cols...).Update(group)
+	return err
+}
+
+// DeleteRunnerGroup deletes the runner group, its runners don't belong to any group after that
+func DeleteRunnerGroup(ctx context.Context, group *ActionRunnerGroup) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).Where("group_id=?", group.ID).Cols("group_id").Update(&ActionRunner{GroupID: 0}); err != nil {
+			return err
+		}
+		_, err := db.DeleteByID[ActionRunnerGroup](ctx, group.ID)
+		return err
+	})
+}
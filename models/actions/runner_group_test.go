@@ -0,0 +1,49 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionRunnerGroup_IsRunAllowed(t *testing.T) {
+	group := &ActionRunnerGroup{
+		RestrictRepos:    true,
+		RepoIDs:          []int64{1, 2},
+		WorkflowPatterns: []string{"deploy*.yml"},
+		RefPatterns:      []string{"refs/heads/main", "refs/tags/v*"},
+	}
+	cases := []struct {
+		run     *ActionRun
+		allowed bool
+	}{
+		{&ActionRun{RepoID: 1, WorkflowID: "deploy.yml", Ref: "refs/heads/main"}, true},
+		{&ActionRun{RepoID: 2, WorkflowID: "deploy-prod.yml", Ref: "refs/tags/v1.0.0"}, true},
+		{&ActionRun{RepoID: 3, WorkflowID: "deploy.yml", Ref: "refs/heads/main"}, false},
+		{&ActionRun{RepoID: 1, WorkflowID: "build.yml", Ref: "refs/heads/main"}, false},
+		{&ActionRun{RepoID: 1, WorkflowID: "deploy.yml", Ref: "refs/heads/feature"}, false},
+		{&ActionRun{RepoID: 1, WorkflowID: "deploy.yml", Ref: "refs/tags/v1/extra"}, false},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.allowed, group.IsRunAllowed(c.run), "%+v", c.run)
+	}
+
+	// the group without policies allows all runs
+	assert.True(t, (&ActionRunnerGroup{}).IsRunAllowed(&ActionRun{RepoID: 3, WorkflowID: "build.yml", Ref: "refs/heads/feature"}))
+	// no repositories can use the runners if no repositories are selected
+	assert.False(t, (&ActionRunnerGroup{RestrictRepos: true}).IsRunAllowed(&ActionRun{RepoID: 1}))
+}
+
+func TestActionRunnerGroup_Validate(t *testing.T) {
+	group := &ActionRunnerGroup{Name: " Production ", RepoIDs: []int64{2, 1, 2}}
+	assert.NoError(t, group.Validate())
+	assert.Equal(t, "Production", group.Name)
+	assert.Equal(t, "production", group.LowerName)
+	assert.Equal(t, []int64{1, 2}, group.RepoIDs)
+
+	assert.Error(t, (&ActionRunnerGroup{Name: " "}).Validate())
+	assert.Error(t, (&ActionRunnerGroup{Name: "group", RefPatterns: []string{"refs/heads/[main"}}).Validate())
+}
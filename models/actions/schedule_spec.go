@@ -30,6 +30,10 @@ type ActionScheduleSpec struct {
 	Prev timeutil.TimeStamp
 	Spec string
 
+	// LastSkipped is the last time this job was skipped, and LastSkipReason tells why
+	LastSkipped    timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	LastSkipReason ScheduleSkipReason `xorm:"VARCHAR(64) NOT NULL DEFAULT ''"`
+
 	Created timeutil.TimeStamp `xorm:"created"`
 	Updated timeutil.TimeStamp `xorm:"updated"`
 }
@@ -59,6 +63,63 @@ func (s *ActionScheduleSpec) Parse() (cron.Schedule, error) {
 	return specSchedule, nil
 }
 
+// ScheduleSkipReason tells why a scheduled job didn't run
+type ScheduleSkipReason string
+
+const (
+	// ScheduleSkipReasonMissed means the time was missed, e.g. the server was down, and the missed runs are not caught up
+	ScheduleSkipReasonMissed ScheduleSkipReason = "missed"
+	// ScheduleSkipReasonWorkflowDisabled means the workflow was disabled at the time
+	ScheduleSkipReasonWorkflowDisabled ScheduleSkipReason = "workflow_disabled"
+)
+
+// splitTimezone splits the spec into the timezone prefix and the cron expression
+func (s *ActionScheduleSpec) splitTimezone() (timezone, cronSpec string) {
+	cronSpec = strings.TrimSpace(s.Spec)
+	if strings.HasPrefix(cronSpec, "TZ=") || strings.HasPrefix(cronSpec, "CRON_TZ=") {
+		tz, rest, _ := strings.Cut(cronSpec, " ")
+		_, timezone, _ = strings.Cut(tz, "=")
+		cronSpec = strings.TrimSpace(rest)
+	}
+	return timezone, cronSpec
+}
+
+// Cron returns the cron expression of the spec without the timezone
+func (s *ActionScheduleSpec) Cron() string {
+	_, cronSpec := s.splitTimezone()
+	return cronSpec
+}
+
+// Timezone returns the timezone the spec is evaluated in, it's empty for the intervals like "@every 5m"
+func (s *ActionScheduleSpec) Timezone() string {
+	timezone, cronSpec := s.splitTimezone()
+	if strings.HasPrefix(cronSpec, "@every ") {
+		return ""
+	}
+	if timezone == "" {
+		return "UTC"
+	}
+	return timezone
+}
+
+// UpcomingTimes returns the next times the job will run after the given time
+func (s *ActionScheduleSpec) UpcomingTimes(after time.Time, count int) ([]time.Time, error) {
+	schedule, err := s.Parse()
+	if err != nil {
+		return nil, err
+	}
+	times := make([]time.Time, 0, count)
+	for range count {
+		next := schedule.Next(after)
+		if next.IsZero() {
+			break
+		}
+		times = append(times, next)
+		after = next
+	}
+	return times, nil
+}
+
 func init() {
 	db.RegisterModel(new(ActionScheduleSpec))
 }
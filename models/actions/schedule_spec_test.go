@@ -67,3 +67,35 @@ func TestActionScheduleSpec_Parse(t *testing.T) {
 		})
 	}
 }
+
+func TestActionScheduleSpec_CronAndTimezone(t *testing.T) {
+	for _, tt := range []struct {
+		spec, cron, timezone string
+	}{
+		{spec: "0 10 * * *", cron: "0 10 * * *", timezone: "UTC"},
+		{spec: "CRON_TZ=Europe/Berlin 30 2 * * 1-5", cron: "30 2 * * 1-5", timezone: "Europe/Berlin"},
+		{spec: "TZ=America/New_York @daily", cron: "@daily", timezone: "America/New_York"},
+		{spec: "@every 5m", cron: "@every 5m", timezone: ""},
+	} {
+		s := &ActionScheduleSpec{Spec: tt.spec}
+		assert.Equal(t, tt.cron, s.Cron(), tt.spec)
+		assert.Equal(t, tt.timezone, s.Timezone(), tt.spec)
+	}
+}
+
+func TestActionScheduleSpec_UpcomingTimes(t *testing.T) {
+	now, err := time.Parse(time.RFC3339, "2024-03-30T00:00:00Z")
+	require.NoError(t, err)
+
+	// the times follow the wall clock of the timezone across the daylight saving time change
+	s := &ActionScheduleSpec{Spec: "CRON_TZ=Europe/Berlin 30 10 * * *"}
+	times, err := s.UpcomingTimes(now, 3)
+	require.NoError(t, err)
+	require.Len(t, times, 3)
+	assert.Equal(t, "2024-03-30T09:30:00Z", times[0].UTC().Format(time.RFC3339))
+	assert.Equal(t, "2024-03-31T08:30:00Z", times[1].UTC().Format(time.RFC3339))
+	assert.Equal(t, "2024-04-01T08:30:00Z", times[2].UTC().Format(time.RFC3339))
+
+	_, err = (&ActionScheduleSpec{Spec: "0 10 * *"}).UpcomingTimes(now, 3)
+	assert.Error(t, err)
+}
@@ -43,6 +43,9 @@ type ActionTask struct {
 	CommitSHA         string `xorm:"index"`
 	IsForkPullRequest bool
 
+	// TokenPermissions is the access of the token to the repository, it's nil for the tasks created before the permissions are supported
+	TokenPermissions TokenPermissions `xorm:"JSON TEXT"`
+
 	Token          string `xorm:"-"`
 	TokenHash      string `xorm:"UNIQUE"` // sha256 of token
 	TokenSalt      string
@@ -54,6 +57,9 @@ type ActionTask struct {
 	LogSize      int64      // blob size
 	LogIndexes   LogIndexes `xorm:"LONGBLOB"`                   // line number to offset
 	LogExpired   bool       `xorm:"index(stopped_log_expired)"` // files that are too old will be deleted
+	// StaleLogFilename is the uncompressed log file replaced by the compressed one,
+	// it's kept for a while since the log may still be read by the requests started before the compression
+	StaleLogFilename string `xorm:"index"`
 
 	Created timeutil.TimeStamp `xorm:"created"`
 	Updated timeutil.TimeStamp `xorm:"updated index"`
@@ -233,12 +239,27 @@ func CreateTaskForRunner(ctx context.Context, runner *ActionRunner) (*ActionTask
 			Join("INNER", "repo_unit", "`repository`.id = `repo_unit`.repo_id").
 			Where(builder.Eq{"`repository`.owner_id": runner.OwnerID, "`repo_unit`.type": unit.TypeActions}))
 	}
+	// the runners of a group can only run the jobs allowed by the access policies of the group
+	var group *ActionRunnerGroup
+	if runner.GroupID > 0 && runner.RepoID == 0 {
+		group, err = GetRunnerGroupByID(ctx, runner.GroupID)
+		if err != nil {
+			return nil, false, fmt.Errorf("GetRunnerGroupByID: %w", err)
+		}
+		if group.RestrictRepos {
+			if len(group.RepoIDs) == 0 {
+				return nil, false, nil
+			}
+			jobCond = jobCond.And(builder.In("repo_id", group.RepoIDs))
+		}
+	}
 	if jobCond.IsValid() {
 		jobCond = builder.In("run_id", builder.Select("id").From("action_run").Where(jobCond))
 	}
 
 	var jobs []*ActionRunJob
-	if err := e.Where("task_id=? AND status=?", 0, StatusWaiting).And(jobCond).Asc("updated", "id").Find(&jobs); err != nil {
+	// the jobs calling reusable workflows are handled by the server
+	if err := e.Where("task_id=? AND status=? AND is_reusable_workflow=?", 0, StatusWaiting, false).And(jobCond).Asc("updated", "id").Find(&jobs); err != nil {
 		return nil, false, err
 	}
 
@@ -246,10 +267,20 @@ func CreateTaskForRunner(ctx context.Context, runner *ActionRunner) (*ActionTask
 	var job *ActionRunJob
 	log.Trace("runner labels: %v", runner.AgentLabels)
 	for _, v := range jobs {
-		if isSubset(runner.AgentLabels, v.RunsOn) {
-			job = v
-			break
+		if !isSubset(runner.AgentLabels, v.RunsOn) {
+			continue
+		}
+		if group != nil && group.HasRunPatterns() {
+			// the workflows and the refs are glob patterns, so they are checked after the jobs are loaded
+			if err := v.LoadRun(ctx); err != nil {
+				return nil, false, err
+			}
+			if !group.IsRunAllowed(v.Run) {
+				continue
+			}
 		}
+		job = v
+		break
 	}
 	if job == nil {
 		return nil, false, nil
@@ -258,6 +289,7 @@ func CreateTaskForRunner(ctx context.Context, runner *ActionRunner) (*ActionTask
 		return nil, false, err
 	}
 
+	queued := job.Updated // the updated time is overwritten when the job is updated
 	now := timeutil.TimeStampNow()
 	job.Attempt++
 	job.Started = now
@@ -325,6 +357,7 @@ func CreateTaskForRunner(ctx context.Context, runner *ActionRunner) (*ActionTask
 	if err := committer.Commit(); err != nil {
 		return nil, false, err
 	}
+	observeTaskStarted(queued, task.Started)
 
 	return task, true, nil
 }
@@ -334,6 +367,11 @@ func UpdateTask(ctx context.Context, task *ActionTask, cols ...string) error {
 	if len(cols) > 0 {
 		sess.Cols(cols...)
 	}
+	if task.Status.IsDone() && util.SliceContainsString(cols, "status") {
+		// the task may be stopped in a transaction of the caller, like cancelling a run,
+		// the closure is called after the transaction has been committed, so a rollback doesn't count
+		sess.After(func(any) { observeTaskStopped(task) })
+	}
 	_, err := sess.Update(task)
 
 	// Automatically delete the ephemeral runner if the task is done
@@ -477,12 +515,66 @@ func StopTask(ctx context.Context, taskID int64, status Status) error {
 	return nil
 }
 
-func FindOldTasksToExpire(ctx context.Context, olderThan timeutil.TimeStamp, limit int) ([]*ActionTask, error) {
+// ErrTaskLogExpired is returned when reading the log of a task which has been removed by the log retention
+var ErrTaskLogExpired = util.NewNotExistErrorf("the log has expired and has been removed")
+
+// FindOldTasksToExpire returns the stopped tasks whose logs haven't expired and are older than olderThan,
+// the tasks belong to the repository if repoID isn't 0.
+func FindOldTasksToExpire(ctx context.Context, olderThan timeutil.TimeStamp, repoID int64, limit int) ([]*ActionTask, error) {
 	e := db.GetEngine(ctx)
 
+	cond := builder.Expr("stopped > 0 AND stopped < ? AND log_expired = ?", olderThan, false)
+	if repoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": repoID})
+	}
+
 	tasks := make([]*ActionTask, 0, limit)
 	// Check "stopped > 0" to avoid deleting tasks that are still running
-	return tasks, e.Where("stopped > 0 AND stopped < ? AND log_expired = ?", olderThan, false).
+	return tasks, e.Where(cond).
+		Limit(limit).
+		Find(&tasks)
+}
+
+// FindRepoIDsWithOldTasksToExpire returns the ids of the repositories which have stopped tasks
+// whose logs haven't expired and are older than olderThan
+func FindRepoIDsWithOldTasksToExpire(ctx context.Context, olderThan timeutil.TimeStamp) ([]int64, error) {
+	repoIDs := make([]int64, 0, 10)
+	return repoIDs, db.GetEngine(ctx).Table("action_task").
+		Where("stopped > 0 AND stopped < ? AND log_expired = ?", olderThan, false).
+		Distinct("repo_id").
+		Find(&repoIDs)
+}
+
+// FindTasksToArchiveLogs returns the tasks stopped before stoppedBefore whose logs are still in DBFS,
+// or are uncompressed in object storage if compressed is true. The tasks are ordered by id and have ids greater than afterID.
+func FindTasksToArchiveLogs(ctx context.Context, stoppedBefore timeutil.TimeStamp, compressed bool, afterID int64, limit int) ([]*ActionTask, error) {
+	cond := builder.NewCond().
+		And(builder.Gt{"id": afterID}).
+		And(builder.Gt{"stopped": 0}).
+		And(builder.Lt{"stopped": stoppedBefore}).
+		And(builder.Eq{"log_expired": false})
+	inStorageCond := builder.Eq{"log_in_storage": false}
+	if compressed {
+		cond = cond.And(builder.Or(inStorageCond, builder.Not{builder.Like{"log_filename", "%.zst"}}))
+	} else {
+		cond = cond.And(inStorageCond)
+	}
+
+	tasks := make([]*ActionTask, 0, limit)
+	return tasks, db.GetEngine(ctx).Where(cond).
+		OrderBy("id").
+		Limit(limit).
+		Find(&tasks)
+}
+
+// FindTasksWithStaleLogs returns the tasks which have stale log files and haven't been updated since updatedBefore
+func FindTasksWithStaleLogs(ctx context.Context, updatedBefore timeutil.TimeStamp, afterID int64, limit int) ([]*ActionTask, error) {
+	tasks := make([]*ActionTask, 0, limit)
+	return tasks, db.GetEngine(ctx).
+		Where(builder.Gt{"id": afterID}).
+		And(builder.Neq{"stale_log_filename": ""}).
+		And(builder.Lt{"updated": updatedBefore}).
+		OrderBy("id").
 		Limit(limit).
 		Find(&tasks)
 }
@@ -0,0 +1,55 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// ActionTaskSummary represents the Markdown summary written by a step of ActionTask to GITHUB_STEP_SUMMARY.
+// Like the outputs, the summaries are bound to a task, so the summaries of the previous attempts are kept when a job is rerun.
+type ActionTaskSummary struct {
+	ID        int64
+	RepoID    int64              `xorm:"index"`
+	TaskID    int64              `xorm:"INDEX UNIQUE(task_id_step_index)"`
+	StepIndex int64              `xorm:"UNIQUE(task_id_step_index)"`
+	Content   string             `xorm:"MEDIUMTEXT"`
+	Created   timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionTaskSummary))
+}
+
+// FindTaskSummariesByTaskIDs returns the summaries of the tasks, ordered by the tasks and the steps
+func FindTaskSummariesByTaskIDs(ctx context.Context, taskIDs []int64) ([]*ActionTaskSummary, error) {
+	if len(taskIDs) == 0 {
+		return nil, nil
+	}
+	var summaries []*ActionTaskSummary
+	return summaries, db.GetEngine(ctx).In("task_id", taskIDs).OrderBy("task_id ASC, step_index ASC").Find(&summaries)
+}
+
+// InsertTaskSummaryIfNotExist inserts the summary of a step of the task if it does not exist.
+func InsertTaskSummaryIfNotExist(ctx context.Context, task *ActionTask, stepIndex int64, content string) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		sess := db.GetEngine(ctx)
+		// the conditions are explicit because the index of the first step is zero
+		if exist, err := sess.Where("task_id=? AND step_index=?", task.ID, stepIndex).Exist(&ActionTaskSummary{}); err != nil {
+			return err
+		} else if exist {
+			return nil
+		}
+		_, err := sess.Insert(&ActionTaskSummary{
+			RepoID:    task.RepoID,
+			TaskID:    task.ID,
+			StepIndex: stepIndex,
+			Content:   content,
+		})
+		return err
+	})
+}
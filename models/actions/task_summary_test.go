@@ -0,0 +1,32 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertTaskSummaryIfNotExist(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	task := &ActionTask{ID: 1000, RepoID: 100}
+	// the summaries can be sent in any order
+	require.NoError(t, InsertTaskSummaryIfNotExist(ctx, task, 1, "step 1"))
+	require.NoError(t, InsertTaskSummaryIfNotExist(ctx, task, 0, "step 0"))
+	// the resent summaries are ignored
+	require.NoError(t, InsertTaskSummaryIfNotExist(ctx, task, 0, "step 0 resent"))
+
+	summaries, err := FindTaskSummariesByTaskIDs(ctx, []int64{task.ID})
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+	assert.Equal(t, "step 0", summaries[0].Content)
+	assert.Equal(t, "step 1", summaries[1].Content)
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"slices"
+
+	"code.gitea.io/gitea/models/perm"
+	"code.gitea.io/gitea/models/unit"
+)
+
+// TokenScope is a scope of the `permissions` in the workflows,
+// see https://docs.github.com/en/actions/writing-workflows/workflow-syntax-for-github-actions#permissions
+type TokenScope string
+
+const (
+	TokenScopeActions      TokenScope = "actions"
+	TokenScopeAttestations TokenScope = "attestations"
+	TokenScopeContents     TokenScope = "contents"
+	TokenScopeIDToken      TokenScope = "id-token"
+	TokenScopeIssues       TokenScope = "issues"
+	TokenScopePackages     TokenScope = "packages"
+	TokenScopePullRequests TokenScope = "pull-requests"
+	TokenScopeProjects     TokenScope = "repository-projects"
+)
+
+// TokenRepoScopes are the scopes granting the access to the repository,
+// the "id-token" and "attestations" scopes are not among them since they only allow to request ID tokens and attestations signed by the instance.
+var TokenRepoScopes = []TokenScope{
+	TokenScopeActions,
+	TokenScopeContents,
+	TokenScopeIssues,
+	TokenScopePackages,
+	TokenScopePullRequests,
+	TokenScopeProjects,
+}
+
+// IsValid returns whether the scope is supported
+func (s TokenScope) IsValid() bool {
+	return s == TokenScopeIDToken || s == TokenScopeAttestations || slices.Contains(TokenRepoScopes, s)
+}
+
+// unitTokenScopes maps the units of the repository to the scopes controlling the access to them
+var unitTokenScopes = map[unit.Type]TokenScope{
+	unit.TypeCode:            TokenScopeContents,
+	unit.TypeReleases:        TokenScopeContents,
+	unit.TypeWiki:            TokenScopeContents,
+	unit.TypeExternalWiki:    TokenScopeContents,
+	unit.TypeIssues:          TokenScopeIssues,
+	unit.TypeExternalTracker: TokenScopeIssues,
+	unit.TypePullRequests:    TokenScopePullRequests,
+	unit.TypePackages:        TokenScopePackages,
+	unit.TypeActions:         TokenScopeActions,
+	unit.TypeProjects:        TokenScopeProjects,
+}
+
+// TokenPermissions maps the scopes to the access modes granted to the token of an Actions task,
+// the scopes not in the map are not accessible.
+type TokenPermissions map[TokenScope]perm.AccessMode
+
+// NewRepoTokenPermissions returns the permissions granting the mode to all repository scopes
+func NewRepoTokenPermissions(mode perm.AccessMode) TokenPermissions {
+	p := make(TokenPermissions, len(TokenRepoScopes))
+	for _, scope := range TokenRepoScopes {
+		p[scope] = mode
+	}
+	return p
+}
+
+// UnitAccessMode returns the access mode of the token to the unit of the repository
+func (p TokenPermissions) UnitAccessMode(unitType unit.Type) perm.AccessMode {
+	scope, ok := unitTokenScopes[unitType]
+	if !ok {
+		return perm.AccessModeNone
+	}
+	return p[scope]
+}
+
+// Intersect returns the permissions granted by both p and other,
+// it's used to limit the permissions of the jobs in reusable workflows by the callers.
+func (p TokenPermissions) Intersect(other TokenPermissions) TokenPermissions {
+	ret := make(TokenPermissions, len(p))
+	for scope, mode := range p {
+		if m := min(mode, other[scope]); m > perm.AccessModeNone {
+			ret[scope] = m
+		}
+	}
+	return ret
+}
+
+// LimitTo returns the permissions whose access modes are not higher than the mode
+func (p TokenPermissions) LimitTo(mode perm.AccessMode) TokenPermissions {
+	ret := make(TokenPermissions, len(p))
+	for scope, m := range p {
+		if m = min(m, mode); m > perm.AccessModeNone {
+			ret[scope] = m
+		}
+	}
+	return ret
+}
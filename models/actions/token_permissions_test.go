@@ -0,0 +1,36 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/perm"
+	"code.gitea.io/gitea/models/unit"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenPermissions(t *testing.T) {
+	perms := TokenPermissions{
+		TokenScopeContents: perm.AccessModeWrite,
+		TokenScopeIssues:   perm.AccessModeRead,
+		TokenScopeIDToken:  perm.AccessModeWrite,
+	}
+	assert.Equal(t, perm.AccessModeWrite, perms.UnitAccessMode(unit.TypeCode))
+	assert.Equal(t, perm.AccessModeWrite, perms.UnitAccessMode(unit.TypeReleases))
+	assert.Equal(t, perm.AccessModeRead, perms.UnitAccessMode(unit.TypeIssues))
+	assert.Equal(t, perm.AccessModeNone, perms.UnitAccessMode(unit.TypePullRequests))
+
+	assert.Equal(t, TokenPermissions{
+		TokenScopeContents: perm.AccessModeRead,
+		TokenScopeIssues:   perm.AccessModeRead,
+		TokenScopeIDToken:  perm.AccessModeRead,
+	}, perms.LimitTo(perm.AccessModeRead))
+
+	assert.Equal(t, TokenPermissions{
+		TokenScopeContents: perm.AccessModeRead,
+		TokenScopeIssues:   perm.AccessModeRead,
+	}, perms.Intersect(NewRepoTokenPermissions(perm.AccessModeRead)))
+}
@@ -0,0 +1,95 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// UsageQuotaPolicy is what happens to the new runs when the usage quota of the owner is exceeded
+type UsageQuotaPolicy string
+
+const (
+	// UsageQuotaPolicySoft allows the new runs, they are only marked as exceeding the quota
+	UsageQuotaPolicySoft UsageQuotaPolicy = "soft"
+	// UsageQuotaPolicyHard blocks the new runs, their jobs are cancelled without running
+	UsageQuotaPolicyHard UsageQuotaPolicy = "hard"
+)
+
+// IsValid returns whether the policy is a known one
+func (p UsageQuotaPolicy) IsValid() bool {
+	return p == UsageQuotaPolicySoft || p == UsageQuotaPolicyHard
+}
+
+// TaskUsage is the total running time of the tasks of a repository which run on the same labels
+type TaskUsage struct {
+	RepoID  int64
+	RunsOn  []string `xorm:"-"`
+	Labels  string   `xorm:"runs_on"`
+	Tasks   int64
+	Seconds int64
+}
+
+// FindTaskUsagesOptions are the options to sum up the running time of the tasks
+type FindTaskUsagesOptions struct {
+	OwnerID       int64
+	RepoID        int64
+	StoppedAfter  timeutil.TimeStamp // inclusive
+	StoppedBefore timeutil.TimeStamp // exclusive
+}
+
+func (opts FindTaskUsagesOptions) ToConds() builder.Cond {
+	cond := builder.NewCond().
+		And(builder.Gt{"`action_task`.started": 0}).
+		And(builder.Gt{"`action_task`.stopped": 0})
+	if opts.OwnerID > 0 {
+		cond = cond.And(builder.Eq{"`action_task`.owner_id": opts.OwnerID})
+	}
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"`action_task`.repo_id": opts.RepoID})
+	}
+	if opts.StoppedAfter > 0 {
+		cond = cond.And(builder.Gte{"`action_task`.stopped": opts.StoppedAfter})
+	}
+	if opts.StoppedBefore > 0 {
+		cond = cond.And(builder.Lt{"`action_task`.stopped": opts.StoppedBefore})
+	}
+	return cond
+}
+
+// FindTaskUsages sums up the running time of the finished tasks by repository and runner labels of their jobs
+func FindTaskUsages(ctx context.Context, opts FindTaskUsagesOptions) ([]*TaskUsage, error) {
+	usages := make([]*TaskUsage, 0, 10)
+	err := db.GetEngine(ctx).Table("action_task").
+		Select("`action_task`.repo_id, `action_run_job`.runs_on, COUNT(*) AS tasks, SUM(`action_task`.stopped - `action_task`.started) AS seconds").
+		Join("INNER", "action_run_job", "`action_run_job`.id = `action_task`.job_id").
+		Where(opts.ToConds()).
+		GroupBy("`action_task`.repo_id, `action_run_job`.runs_on").
+		OrderBy("`action_task`.repo_id").
+		Find(&usages)
+	if err != nil {
+		return nil, err
+	}
+	for _, usage := range usages {
+		if usage.Labels != "" {
+			if err := json.Unmarshal([]byte(usage.Labels), &usage.RunsOn); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return usages, nil
+}
+
+// SumTaskSeconds returns the total running time in seconds of the finished tasks
+func SumTaskSeconds(ctx context.Context, opts FindTaskUsagesOptions) (int64, error) {
+	return db.GetEngine(ctx).Table("action_task").
+		Where(opts.ToConds()).
+		SumInt(new(ActionTask), "`action_task`.stopped - `action_task`.started")
+}
@@ -0,0 +1,58 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindTaskUsages(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := t.Context()
+
+	linux := &ActionRunJob{RunID: 2000, RepoID: 2001, OwnerID: 2000, Name: "linux", RunsOn: []string{"ubuntu-latest"}}
+	windows := &ActionRunJob{RunID: 2000, RepoID: 2001, OwnerID: 2000, Name: "windows", RunsOn: []string{"windows-latest"}}
+	require.NoError(t, db.Insert(ctx, linux))
+	require.NoError(t, db.Insert(ctx, windows))
+	require.NoError(t, db.Insert(ctx, []*ActionTask{
+		{JobID: linux.ID, RepoID: 2001, OwnerID: 2000, Started: 1000, Stopped: 1090, TokenHash: "usage-1"},
+		{JobID: linux.ID, RepoID: 2001, OwnerID: 2000, Started: 2000, Stopped: 2030, TokenHash: "usage-2"},
+		{JobID: windows.ID, RepoID: 2001, OwnerID: 2000, Started: 3000, Stopped: 3300, TokenHash: "usage-3"},
+		// the running task is not counted
+		{JobID: windows.ID, RepoID: 2001, OwnerID: 2000, Started: 4000, TokenHash: "usage-4"},
+	}))
+
+	usages, err := FindTaskUsages(ctx, FindTaskUsagesOptions{OwnerID: 2000})
+	require.NoError(t, err)
+	if assert.Len(t, usages, 2) {
+		byLabels := map[string]*TaskUsage{}
+		for _, usage := range usages {
+			assert.EqualValues(t, 2001, usage.RepoID)
+			require.Len(t, usage.RunsOn, 1)
+			byLabels[usage.RunsOn[0]] = usage
+		}
+		assert.EqualValues(t, 2, byLabels["ubuntu-latest"].Tasks)
+		assert.EqualValues(t, 120, byLabels["ubuntu-latest"].Seconds)
+		assert.EqualValues(t, 1, byLabels["windows-latest"].Tasks)
+		assert.EqualValues(t, 300, byLabels["windows-latest"].Seconds)
+	}
+
+	seconds, err := SumTaskSeconds(ctx, FindTaskUsagesOptions{RepoID: 2001})
+	require.NoError(t, err)
+	assert.EqualValues(t, 420, seconds)
+
+	// only the tasks stopped in the period are counted
+	seconds, err = SumTaskSeconds(ctx, FindTaskUsagesOptions{OwnerID: 2000, StoppedAfter: 2030, StoppedBefore: 3300})
+	require.NoError(t, err)
+	assert.EqualValues(t, 30, seconds)
+	usages, err = FindTaskUsages(ctx, FindTaskUsagesOptions{OwnerID: 2000, StoppedAfter: 3301})
+	require.NoError(t, err)
+	assert.Empty(t, usages)
+}
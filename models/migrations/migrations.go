@@ -24,6 +24,7 @@ import (
 	"code.gitea.io/gitea/models/migrations/v1_22"
 	"code.gitea.io/gitea/models/migrations/v1_23"
 	"code.gitea.io/gitea/models/migrations/v1_24"
+	"code.gitea.io/gitea/models/migrations/v1_25"
 	"code.gitea.io/gitea/models/migrations/v1_6"
 	"code.gitea.io/gitea/models/migrations/v1_7"
 	"code.gitea.io/gitea/models/migrations/v1_8"
@@ -382,6 +383,24 @@ func prepareMigrationTasks() []*migration {
 		newMigration(318, "Add anonymous_access_mode for repo_unit", v1_24.AddRepoUnitAnonymousAccessMode),
 		newMigration(319, "Add ExclusiveOrder to Label table", v1_24.AddExclusiveOrderColumnToLabelTable),
 		newMigration(320, "Migrate two_factor_policy to login_source table", v1_24.MigrateSkipTwoFactor),
+
+		// Gitea 1.24.0 ends at migration ID number 320 (database version 321)
+		newMigration(321, "Add concurrency columns to action_run and action_run_job", v1_25.AddActionsConcurrency),
+		newMigration(322, "Add reusable workflow columns to action_run_job", v1_25.AddActionsReusableWorkflowColumns),
+		newMigration(323, "Add environments for actions", v1_25.AddActionsEnvironments),
+		newMigration(324, "Add action_cache table", v1_25.AddActionCacheTable),
+		newMigration(325, "Add token_permissions column to action_task table", v1_25.AddTokenPermissionsToActionTask),
+		newMigration(326, "Add raw_matrix and max_parallel columns to action_run_job table", v1_25.AddMatrixColumnsToActionRunJob),
+		newMigration(327, "Add attempt columns to action_run table and action_run_attempt table", v1_25.AddActionRunAttempts),
+		newMigration(328, "Add action_task_summary table", v1_25.AddActionTaskSummaryTable),
+		newMigration(329, "Add action_runner_group table and group_id to action_runner", v1_25.AddActionRunnerGroups),
+		newMigration(330, "Add last_skipped and last_skip_reason to action_schedule_spec", v1_25.AddLastSkippedToActionScheduleSpec),
+		newMigration(331, "Add action_attestation table", v1_25.AddActionAttestationTable),
+		newMigration(332, "Add quota_exceeded to action_run", v1_25.AddQuotaExceededToActionRun),
+		newMigration(333, "Add package_remote table", v1_25.AddPackageRemoteTable),
+		newMigration(334, "Add semver keep options to package cleanup rules and package cleanup run tables", v1_25.AddPackageCleanupRuleSemverAndRunTables),
+		newMigration(335, "Add run_attempt to action_artifact", v1_25.AddRunAttemptToActionArtifact),
+		newMigration(336, "Add stale_log_filename to action_task", v1_25.AddStaleLogFilenameToActionTask),
 	}
 	return preparedMigrations
 }
@@ -0,0 +1,17 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_25
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddActionsReusableWorkflowColumns(x *xorm.Engine) error {
+	type ActionRunJob struct {
+		IsReusableWorkflow bool              `xorm:"NOT NULL DEFAULT FALSE"`
+		ParentJobID        int64             `xorm:"index NOT NULL DEFAULT 0"`
+		Outputs            map[string]string `xorm:"JSON TEXT"`
+	}
+	return x.Sync(new(ActionRunJob))
+}
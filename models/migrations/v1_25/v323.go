@@ -0,0 +1,67 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_25
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionsEnvironments(x *xorm.Engine) error {
+	type ActionEnvironment struct {
+		ID                int64
+		RepoID            int64    `xorm:"UNIQUE(repo_name) NOT NULL"`
+		Name              string   `xorm:"NOT NULL"`
+		LowerName         string   `xorm:"UNIQUE(repo_name) NOT NULL"`
+		ReviewerUserIDs   []int64  `xorm:"JSON TEXT"`
+		ReviewerTeamIDs   []int64  `xorm:"JSON TEXT"`
+		PreventSelfReview bool     `xorm:"NOT NULL DEFAULT FALSE"`
+		WaitTimer         int64    `xorm:"NOT NULL DEFAULT 0"`
+		BranchPatterns    []string `xorm:"JSON TEXT"`
+
+		Created timeutil.TimeStamp `xorm:"created"`
+		Updated timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type ActionDeploymentReview struct {
+		ID            int64
+		RepoID        int64  `xorm:"index"`
+		RunID         int64  `xorm:"index"`
+		JobID         int64  `xorm:"index(job_attempt)"`
+		JobAttempt    int64  `xorm:"index(job_attempt)"`
+		EnvironmentID int64  `xorm:"index"`
+		ReviewerID    int64  `xorm:"index"`
+		State         int    `xorm:"NOT NULL"`
+		Comment       string `xorm:"TEXT"`
+
+		Created timeutil.TimeStamp `xorm:"created"`
+	}
+
+	type ActionRunJob struct {
+		RawEnvironment         string
+		IsEnvironmentEvaluated bool               `xorm:"NOT NULL DEFAULT FALSE"`
+		EnvironmentID          int64              `xorm:"index NOT NULL DEFAULT 0"`
+		EnvironmentWaitUntil   timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	// the environment id is a part of the unique index of the names, the old index is dropped and recreated by Sync
+	type Secret struct {
+		ID            int64
+		OwnerID       int64  `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL"`
+		RepoID        int64  `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL DEFAULT 0"`
+		EnvironmentID int64  `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL DEFAULT 0"`
+		Name          string `xorm:"UNIQUE(owner_repo_name) NOT NULL"`
+	}
+
+	type ActionVariable struct {
+		ID            int64  `xorm:"pk autoincr"`
+		OwnerID       int64  `xorm:"UNIQUE(owner_repo_name)"`
+		RepoID        int64  `xorm:"INDEX UNIQUE(owner_repo_name)"`
+		EnvironmentID int64  `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL DEFAULT 0"`
+		Name          string `xorm:"UNIQUE(owner_repo_name) NOT NULL"`
+	}
+
+	return x.Sync(new(ActionEnvironment), new(ActionDeploymentReview), new(ActionRunJob), new(Secret), new(ActionVariable))
+}
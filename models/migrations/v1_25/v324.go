@@ -0,0 +1,28 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_25
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionCacheTable(x *xorm.Engine) error {
+	type ActionCache struct {
+		ID             int64
+		RepoID         int64              `xorm:"INDEX(repo_ref) NOT NULL"`
+		Ref            string             `xorm:"INDEX(repo_ref) NOT NULL"`
+		CacheKey       string             `xorm:"VARCHAR(512) NOT NULL"`
+		Version        string             `xorm:"NOT NULL"`
+		RunID          int64              `xorm:"NOT NULL DEFAULT 0"`
+		Size           int64              `xorm:"NOT NULL DEFAULT 0"`
+		IsComplete     bool               `xorm:"INDEX NOT NULL DEFAULT FALSE"`
+		LastAccessUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+		CreatedUnix    timeutil.TimeStamp `xorm:"created INDEX"`
+		UpdatedUnix    timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync(new(ActionCache))
+}
@@ -0,0 +1,16 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_25
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddTokenPermissionsToActionTask(x *xorm.Engine) error {
+	type ActionTask struct {
+		TokenPermissions map[string]int `xorm:"JSON TEXT"`
+	}
+
+	return x.Sync(new(ActionTask))
+}
@@ -0,0 +1,17 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_25
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddMatrixColumnsToActionRunJob(x *xorm.Engine) error {
+	type ActionRunJob struct {
+		RawMatrix   string `xorm:"TEXT"`
+		MaxParallel int    `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync(new(ActionRunJob))
+}
@@ -0,0 +1,44 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_25
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionRunAttempts(x *xorm.Engine) error {
+	type ActionRun struct {
+		Attempt        int64 `xorm:"NOT NULL DEFAULT 1"`
+		IsDebugEnabled bool  `xorm:"NOT NULL DEFAULT FALSE"`
+	}
+
+	type ActionRunAttemptJob struct {
+		ID      int64              `json:"id"`
+		Status  int                `json:"status"`
+		Attempt int64              `json:"attempt"`
+		TaskID  int64              `json:"task_id"`
+		Started timeutil.TimeStamp `json:"started"`
+		Stopped timeutil.TimeStamp `json:"stopped"`
+	}
+
+	type ActionRunAttempt struct {
+		ID               int64
+		RepoID           int64 `xorm:"index"`
+		RunID            int64 `xorm:"unique(run_attempt)"`
+		Attempt          int64 `xorm:"unique(run_attempt)"`
+		Status           int
+		IsDebugEnabled   bool `xorm:"NOT NULL DEFAULT FALSE"`
+		Started          timeutil.TimeStamp
+		Stopped          timeutil.TimeStamp
+		PreviousDuration time.Duration
+		Jobs             []*ActionRunAttemptJob `xorm:"JSON LONGTEXT"`
+		Created          timeutil.TimeStamp     `xorm:"created"`
+	}
+
+	return x.Sync(new(ActionRun), new(ActionRunAttempt))
+}
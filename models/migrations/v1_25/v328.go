@@ -0,0 +1,23 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_25
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionTaskSummaryTable(x *xorm.Engine) error {
+	type ActionTaskSummary struct {
+		ID        int64
+		RepoID    int64              `xorm:"index"`
+		TaskID    int64              `xorm:"INDEX UNIQUE(task_id_step_index)"`
+		StepIndex int64              `xorm:"UNIQUE(task_id_step_index)"`
+		Content   string             `xorm:"MEDIUMTEXT"`
+		Created   timeutil.TimeStamp `xorm:"created"`
+	}
+
+	return x.Sync(new(ActionTaskSummary))
+}
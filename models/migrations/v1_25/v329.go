@@ -0,0 +1,32 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_25
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionRunnerGroups(x *xorm.Engine) error {
+	type ActionRunnerGroup struct {
+		ID               int64
+		OwnerID          int64    `xorm:"UNIQUE(owner_name) NOT NULL"`
+		Name             string   `xorm:"NOT NULL"`
+		LowerName        string   `xorm:"UNIQUE(owner_name) NOT NULL"`
+		RestrictRepos    bool     `xorm:"NOT NULL DEFAULT FALSE"`
+		RepoIDs          []int64  `xorm:"JSON TEXT"`
+		WorkflowPatterns []string `xorm:"JSON TEXT"`
+		RefPatterns      []string `xorm:"JSON TEXT"`
+
+		Created timeutil.TimeStamp `xorm:"created"`
+		Updated timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type ActionRunner struct {
+		GroupID int64 `xorm:"index NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync(new(ActionRunnerGroup), new(ActionRunner))
+}
@@ -0,0 +1,18 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_25
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddLastSkippedToActionScheduleSpec(x *xorm.Engine) error {
+	type ActionScheduleSpec struct {
+		LastSkipped    timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+		LastSkipReason string             `xorm:"VARCHAR(64) NOT NULL DEFAULT ''"`
+	}
+	return x.Sync(new(ActionScheduleSpec))
+}
@@ -0,0 +1,30 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_25
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionAttestationTable(x *xorm.Engine) error {
+	type ActionAttestation struct {
+		ID               int64
+		RepoID           int64  `xorm:"index"`
+		OwnerID          int64  `xorm:"index"`
+		RunID            int64  `xorm:"index"`
+		TaskID           int64  `xorm:"NOT NULL DEFAULT 0"`
+		ArtifactID       int64  `xorm:"index NOT NULL DEFAULT 0"`
+		PackageVersionID int64  `xorm:"index NOT NULL DEFAULT 0"`
+		SubjectName      string `xorm:"NOT NULL DEFAULT ''"`
+		SubjectDigest    string `xorm:"VARCHAR(255) index NOT NULL"`
+		PredicateType    string `xorm:"NOT NULL"`
+		KeyID            string `xorm:"NOT NULL DEFAULT ''"`
+		Envelope         string `xorm:"LONGTEXT NOT NULL"`
+
+		Created timeutil.TimeStamp `xorm:"created"`
+	}
+	return x.Sync(new(ActionAttestation))
+}
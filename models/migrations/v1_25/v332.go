@@ -0,0 +1,15 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_25
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddQuotaExceededToActionRun(x *xorm.Engine) error {
+	type ActionRun struct {
+		QuotaExceeded bool `xorm:"NOT NULL DEFAULT FALSE"`
+	}
+	return x.Sync(new(ActionRun))
+}
@@ -0,0 +1,41 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_25
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddPackageCleanupRuleSemverAndRunTables(x *xorm.Engine) error {
+	type PackageCleanupRule struct {
+		KeepSemverRange    string `xorm:"NOT NULL DEFAULT ''"`
+		KeepLatestPerMajor bool   `xorm:"NOT NULL DEFAULT false"`
+	}
+	if _, err := x.SyncWithOptions(xorm.SyncOptions{
+		IgnoreConstrains: true,
+		IgnoreIndices:    true,
+	}, new(PackageCleanupRule)); err != nil {
+		return err
+	}
+
+	type PackageCleanupRun struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RuleID      int64              `xorm:"INDEX NOT NULL"`
+		OwnerID     int64              `xorm:"INDEX NOT NULL"`
+		Type        string             `xorm:"NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created INDEX NOT NULL"`
+	}
+
+	type PackageCleanupRunVersion struct {
+		ID          int64  `xorm:"pk autoincr"`
+		RunID       int64  `xorm:"INDEX NOT NULL"`
+		PackageName string `xorm:"NOT NULL"`
+		Version     string `xorm:"NOT NULL"`
+		Size        int64  `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync(new(PackageCleanupRun), new(PackageCleanupRunVersion))
+}
@@ -0,0 +1,35 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_25
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddRunAttemptToActionArtifact(x *xorm.Engine) error {
+	// all the columns and indexes are declared, so the sync only replaces the unique index of the artifacts,
+	// which includes the attempt now, and each attempt of a run keeps its own artifacts
+	type ActionArtifact struct {
+		ID                 int64 `xorm:"pk autoincr"`
+		RunID              int64 `xorm:"index unique(runid_attempt_name_path)"`
+		RunAttempt         int64 `xorm:"unique(runid_attempt_name_path) NOT NULL DEFAULT 1"`
+		RunnerID           int64
+		RepoID             int64 `xorm:"index"`
+		OwnerID            int64
+		CommitSHA          string
+		StoragePath        string
+		FileSize           int64
+		FileCompressedSize int64
+		ContentEncoding    string
+		ArtifactPath       string             `xorm:"index unique(runid_attempt_name_path)"`
+		ArtifactName       string             `xorm:"index unique(runid_attempt_name_path)"`
+		Status             int64              `xorm:"index"`
+		CreatedUnix        timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix        timeutil.TimeStamp `xorm:"updated index"`
+		ExpiredUnix        timeutil.TimeStamp `xorm:"index"`
+	}
+	return x.Sync(new(ActionArtifact))
+}
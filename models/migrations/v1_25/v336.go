@@ -0,0 +1,15 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_25
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddStaleLogFilenameToActionTask(x *xorm.Engine) error {
+	type ActionTask struct {
+		StaleLogFilename string `xorm:"index"`
+	}
+	return x.Sync(new(ActionTask))
+}
@@ -12,10 +12,11 @@ import (
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 
+	"github.com/hashicorp/go-version"
 	"xorm.io/builder"
 )
 
-var ErrPackageCleanupRuleNotExist = util.NewNotExistErrorf("package blob does not exist")
+var ErrPackageCleanupRuleNotExist = util.NewNotExistErrorf("package cleanup rule does not exist")
 
 func init() {
 	db.RegisterModel(new(PackageCleanupRule))
@@ -23,23 +24,26 @@ func init() {
 
 // PackageCleanupRule represents a rule which describes when to clean up package versions
 type PackageCleanupRule struct {
-	ID                   int64              `xorm:"pk autoincr"`
-	Enabled              bool               `xorm:"INDEX NOT NULL DEFAULT false"`
-	OwnerID              int64              `xorm:"UNIQUE(s) INDEX NOT NULL DEFAULT 0"`
-	Type                 Type               `xorm:"UNIQUE(s) INDEX NOT NULL"`
-	KeepCount            int                `xorm:"NOT NULL DEFAULT 0"`
-	KeepPattern          string             `xorm:"NOT NULL DEFAULT ''"`
-	KeepPatternMatcher   *regexp.Regexp     `xorm:"-"`
-	RemoveDays           int                `xorm:"NOT NULL DEFAULT 0"`
-	RemovePattern        string             `xorm:"NOT NULL DEFAULT ''"`
-	RemovePatternMatcher *regexp.Regexp     `xorm:"-"`
-	MatchFullName        bool               `xorm:"NOT NULL DEFAULT false"`
-	CreatedUnix          timeutil.TimeStamp `xorm:"created NOT NULL DEFAULT 0"`
-	UpdatedUnix          timeutil.TimeStamp `xorm:"updated NOT NULL DEFAULT 0"`
+	ID                   int64               `xorm:"pk autoincr"`
+	Enabled              bool                `xorm:"INDEX NOT NULL DEFAULT false"`
+	OwnerID              int64               `xorm:"UNIQUE(s) INDEX NOT NULL DEFAULT 0"`
+	Type                 Type                `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	KeepCount            int                 `xorm:"NOT NULL DEFAULT 0"`
+	KeepPattern          string              `xorm:"NOT NULL DEFAULT ''"`
+	KeepPatternMatcher   *regexp.Regexp      `xorm:"-"`
+	KeepSemverRange      string              `xorm:"NOT NULL DEFAULT ''"`
+	KeepSemverMatcher    version.Constraints `xorm:"-"`
+	KeepLatestPerMajor   bool                `xorm:"NOT NULL DEFAULT false"`
+	RemoveDays           int                 `xorm:"NOT NULL DEFAULT 0"`
+	RemovePattern        string              `xorm:"NOT NULL DEFAULT ''"`
+	RemovePatternMatcher *regexp.Regexp      `xorm:"-"`
+	MatchFullName        bool                `xorm:"NOT NULL DEFAULT false"`
+	CreatedUnix          timeutil.TimeStamp  `xorm:"created NOT NULL DEFAULT 0"`
+	UpdatedUnix          timeutil.TimeStamp  `xorm:"updated NOT NULL DEFAULT 0"`
 }
 
 func (pcr *PackageCleanupRule) CompiledPattern() error {
-	if pcr.KeepPatternMatcher != nil || pcr.RemovePatternMatcher != nil {
+	if pcr.KeepPatternMatcher != nil || pcr.KeepSemverMatcher != nil || pcr.RemovePatternMatcher != nil {
 		return nil
 	}
 
@@ -51,6 +55,14 @@ func (pcr *PackageCleanupRule) CompiledPattern() error {
 		}
 	}
 
+	if pcr.KeepSemverRange != "" {
+		var err error
+		pcr.KeepSemverMatcher, err = version.NewConstraint(pcr.KeepSemverRange)
+		if err != nil {
+			return err
+		}
+	}
+
 	if pcr.RemovePattern != "" {
 		var err error
 		pcr.RemovePatternMatcher, err = regexp.Compile(fmt.Sprintf(`(?i)\A%s\z`, pcr.RemovePattern))
@@ -89,9 +101,16 @@ func GetCleanupRulesByOwner(ctx context.Context, ownerID int64) ([]*PackageClean
 	return pcrs, db.GetEngine(ctx).Where("owner_id = ?", ownerID).Find(&pcrs)
 }
 
+// DeleteCleanupRuleByID deletes a cleanup rule and its run history
 func DeleteCleanupRuleByID(ctx context.Context, ruleID int64) error {
-	_, err := db.GetEngine(ctx).ID(ruleID).Delete(&PackageCleanupRule{})
-	return err
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if err := DeleteCleanupRunsByRuleID(ctx, ruleID); err != nil {
+			return err
+		}
+
+		_, err := db.GetEngine(ctx).ID(ruleID).Delete(&PackageCleanupRule{})
+		return err
+	})
 }
 
 func HasOwnerCleanupRuleForPackageType(ctx context.Context, ownerID int64, packageType Type) (bool, error) {
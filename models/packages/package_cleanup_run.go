@@ -0,0 +1,98 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+func init() {
+	db.RegisterModel(new(PackageCleanupRun))
+	db.RegisterModel(new(PackageCleanupRunVersion))
+}
+
+// PackageCleanupRun represents an execution of a cleanup rule which removed package versions
+type PackageCleanupRun struct {
+	ID          int64              `xorm:"pk autoincr"`
+	RuleID      int64              `xorm:"INDEX NOT NULL"`
+	OwnerID     int64              `xorm:"INDEX NOT NULL"`
+	Type        Type               `xorm:"NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created INDEX NOT NULL"`
+}
+
+// PackageCleanupRunVersion represents a package version removed by a cleanup run
+type PackageCleanupRunVersion struct {
+	ID          int64  `xorm:"pk autoincr"`
+	RunID       int64  `xorm:"INDEX NOT NULL"`
+	PackageName string `xorm:"NOT NULL"`
+	Version     string `xorm:"NOT NULL"`
+	Size        int64  `xorm:"NOT NULL DEFAULT 0"`
+}
+
+// InsertCleanupRun inserts a cleanup run together with the versions it removed
+func InsertCleanupRun(ctx context.Context, pcr *PackageCleanupRun, versions []*PackageCleanupRunVersion) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if err := db.Insert(ctx, pcr); err != nil {
+			return err
+		}
+
+		for _, v := range versions {
+			v.RunID = pcr.ID
+		}
+		if len(versions) == 0 {
+			return nil
+		}
+		return db.Insert(ctx, versions)
+	})
+}
+
+// GetCleanupRunsByRuleID gets the runs of a cleanup rule, the most recent run first
+func GetCleanupRunsByRuleID(ctx context.Context, ruleID int64, paginator db.Paginator) ([]*PackageCleanupRun, int64, error) {
+	sess := db.GetEngine(ctx).
+		Where("rule_id = ?", ruleID).
+		Desc("created_unix").
+		Desc("id")
+
+	if paginator != nil {
+		sess = db.SetSessionPagination(sess, paginator)
+	}
+
+	runs := make([]*PackageCleanupRun, 0, 10)
+	count, err := sess.FindAndCount(&runs)
+	return runs, count, err
+}
+
+// GetCleanupRunVersionsByRunIDs gets the removed versions of the runs grouped by run id
+func GetCleanupRunVersionsByRunIDs(ctx context.Context, runIDs []int64) (map[int64][]*PackageCleanupRunVersion, error) {
+	versions := make([]*PackageCleanupRunVersion, 0, len(runIDs))
+	if err := db.GetEngine(ctx).
+		Where(builder.In("run_id", runIDs)).
+		Asc("id").
+		Find(&versions); err != nil {
+		return nil, err
+	}
+
+	m := make(map[int64][]*PackageCleanupRunVersion, len(runIDs))
+	for _, v := range versions {
+		m[v.RunID] = append(m[v.RunID], v)
+	}
+	return m, nil
+}
+
+// DeleteCleanupRunsByRuleID deletes all runs of a cleanup rule
+func DeleteCleanupRunsByRuleID(ctx context.Context, ruleID int64) error {
+	if _, err := db.GetEngine(ctx).
+		Where(builder.In("run_id", builder.Select("id").From("package_cleanup_run").Where(builder.Eq{"rule_id": ruleID}))).
+		Delete(&PackageCleanupRunVersion{}); err != nil {
+		return err
+	}
+
+	_, err := db.GetEngine(ctx).Where("rule_id = ?", ruleID).Delete(&PackageCleanupRun{})
+	return err
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"context"
+	"slices"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/secret"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+var ErrPackageRemoteNotExist = util.NewNotExistErrorf("package remote does not exist")
+
+// RemoteTypes are the package types which can be fetched from a remote
+var RemoteTypes = []Type{
+	TypeContainer,
+	TypeGo,
+	TypeMaven,
+	TypeNpm,
+	TypePyPI,
+}
+
+// IsRemoteSupported returns if packages of the type can be fetched from a remote
+func (pt Type) IsRemoteSupported() bool {
+	return slices.Contains(RemoteTypes, pt)
+}
+
+func init() {
+	db.RegisterModel(new(PackageRemote))
+}
+
+// PackageRemote represents an upstream registry from which the missing packages of an owner are fetched
+type PackageRemote struct {
+	ID                int64              `xorm:"pk autoincr"`
+	Enabled           bool               `xorm:"INDEX NOT NULL DEFAULT false"`
+	OwnerID           int64              `xorm:"UNIQUE(s) INDEX NOT NULL DEFAULT 0"`
+	Type              Type               `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	URL               string             `xorm:"TEXT NOT NULL"`
+	Username          string             `xorm:"NOT NULL DEFAULT ''"`
+	PasswordEncrypted string             `xorm:"TEXT"`
+	MetadataTTL       int64              `xorm:"NOT NULL DEFAULT 0"` // in seconds, 0 uses the default of the instance
+	CreatedUnix       timeutil.TimeStamp `xorm:"created NOT NULL DEFAULT 0"`
+	UpdatedUnix       timeutil.TimeStamp `xorm:"updated NOT NULL DEFAULT 0"`
+}
+
+// Password returns the decrypted password used to authenticate at the remote
+func (pr *PackageRemote) Password() (string, error) {
+	if pr.PasswordEncrypted == "" {
+		return "", nil
+	}
+	return secret.DecryptSecret(setting.SecretKey, pr.PasswordEncrypted)
+}
+
+// SetPassword encrypts and sets the password used to authenticate at the remote
+func (pr *PackageRemote) SetPassword(password string) error {
+	if password == "" {
+		pr.PasswordEncrypted = ""
+		return nil
+	}
+	encrypted, err := secret.EncryptSecret(setting.SecretKey, password)
+	if err != nil {
+		return err
+	}
+	pr.PasswordEncrypted = encrypted
+	return nil
+}
+
+// GetMetadataTTL returns how long the mutable metadata fetched from the remote is cached in seconds
+func (pr *PackageRemote) GetMetadataTTL() int64 {
+	if pr.MetadataTTL > 0 {
+		return pr.MetadataTTL
+	}
+	return int64(setting.Packages.RemoteMetadataTTL.Seconds())
+}
+
+func InsertRemote(ctx context.Context, pr *PackageRemote) error {
+	return db.Insert(ctx, pr)
+}
+
+func UpdateRemote(ctx context.Context, pr *PackageRemote) error {
+	_, err := db.GetEngine(ctx).ID(pr.ID).AllCols().Update(pr)
+	return err
+}
+
+func GetRemoteByOwnerAndType(ctx context.Context, ownerID int64, packageType Type) (*PackageRemote, error) {
+	pr := &PackageRemote{}
+
+	has, err := db.GetEngine(ctx).Where("owner_id = ? AND type = ?", ownerID, packageType).Get(pr)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrPackageRemoteNotExist
+	}
+	return pr, nil
+}
+
+func GetRemotesByOwner(ctx context.Context, ownerID int64) ([]*PackageRemote, error) {
+	prs := make([]*PackageRemote, 0, len(RemoteTypes))
+	return prs, db.GetEngine(ctx).Where("owner_id = ?", ownerID).OrderBy("type").Find(&prs)
+}
+
+func DeleteRemoteByID(ctx context.Context, remoteID int64) error {
+	_, err := db.GetEngine(ctx).ID(remoteID).Delete(&PackageRemote{})
+	return err
+}
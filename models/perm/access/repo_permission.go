@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"slices"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/models/organization"
 	perm_model "code.gitea.io/gitea/models/perm"
@@ -359,6 +360,32 @@ func GetUserRepoPermission(ctx context.Context, repo *repo_model.Repository, use
 	return perm, err
 }
 
+// GetActionsUserRepoPermission returns the permissions of the token of an Actions task to the repository.
+// The token can only access the repository of the task, and the access is limited by the token permissions of the task.
+func GetActionsUserRepoPermission(ctx context.Context, repo *repo_model.Repository, task *actions_model.ActionTask) (perm Permission, err error) {
+	if task.RepoID != repo.ID {
+		return perm, nil
+	}
+	if err := repo.LoadUnits(ctx); err != nil {
+		return perm, err
+	}
+
+	if task.TokenPermissions == nil {
+		// the tasks created before the token permissions are supported have the read-write access, or the read access for the pull requests from forks
+		perm.AccessMode = util.Iif(task.IsForkPullRequest, perm_model.AccessModeRead, perm_model.AccessModeWrite)
+		perm.SetUnitsWithDefaultAccessMode(repo.Units, perm.AccessMode)
+		return perm, nil
+	}
+
+	perm.AccessMode = task.TokenPermissions[actions_model.TokenScopeContents]
+	perm.units = repo.Units
+	perm.unitsMode = make(map[unit.Type]perm_model.AccessMode, len(repo.Units))
+	for _, u := range repo.Units {
+		perm.unitsMode[u.Type] = task.TokenPermissions.UnitAccessMode(u.Type)
+	}
+	return perm, nil
+}
+
 // IsUserRealRepoAdmin check if this user is real repo admin
 func IsUserRealRepoAdmin(ctx context.Context, repo *repo_model.Repository, user *user_model.User) (bool, error) {
 	if repo.OwnerID == user.ID {
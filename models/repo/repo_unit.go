@@ -168,8 +168,46 @@ func (cfg *PullRequestsConfig) GetDefaultMergeStyle() MergeStyle {
 	return MergeStyleMerge
 }
 
+// ActionsTokenPermissionMode is the default access of the tokens of the Actions jobs which don't declare the `permissions`
+type ActionsTokenPermissionMode string
+
+const (
+	// ActionsTokenPermissionModePermissive grants the read-write access to the repository
+	ActionsTokenPermissionModePermissive ActionsTokenPermissionMode = "permissive"
+	// ActionsTokenPermissionModeRestricted grants the read-only access to the repository
+	ActionsTokenPermissionModeRestricted ActionsTokenPermissionMode = "restricted"
+)
+
+// IsValid returns whether the mode is valid, the empty mode means following the owner of the repository
+func (m ActionsTokenPermissionMode) IsValid() bool {
+	return m == "" || m == ActionsTokenPermissionModePermissive || m == ActionsTokenPermissionModeRestricted
+}
+
+// ActionsApprovalPolicy decides which runs triggered by the pull requests from forks need to be approved before running
+type ActionsApprovalPolicy string
+
+const (
+	// ActionsApprovalPolicyFirstTimeContributors requires approval for the users who have never been approved in the repository
+	ActionsApprovalPolicyFirstTimeContributors ActionsApprovalPolicy = "first_time_contributors"
+	// ActionsApprovalPolicyAllExternalContributors requires approval for all users who can't write to the repository
+	ActionsApprovalPolicyAllExternalContributors ActionsApprovalPolicy = "all_external_contributors"
+	// ActionsApprovalPolicyNone doesn't require approval, except for the restricted users
+	ActionsApprovalPolicyNone ActionsApprovalPolicy = "none"
+)
+
+// IsValid returns whether the policy is valid, the empty policy means following the owner of the repository
+func (p ActionsApprovalPolicy) IsValid() bool {
+	return p == "" || p == ActionsApprovalPolicyFirstTimeContributors || p == ActionsApprovalPolicyAllExternalContributors || p == ActionsApprovalPolicyNone
+}
+
 type ActionsConfig struct {
 	DisabledWorkflows []string
+	// TokenPermissionMode is the default permission mode of the tokens, empty means following the owner of the repository
+	TokenPermissionMode ActionsTokenPermissionMode
+	// ApprovalPolicy is the approval policy of the runs from forks, empty means following the owner of the repository
+	ApprovalPolicy ActionsApprovalPolicy
+	// LogRetentionDays is the retention period of the job logs in days, 0 means following the owner of the repository
+	LogRetentionDays int64
 }
 
 func (cfg *ActionsConfig) EnableWorkflow(file string) {
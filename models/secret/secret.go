@@ -25,6 +25,7 @@ import (
 // It can be:
 //  1. org/user level secret, OwnerID is org/user ID and RepoID is 0
 //  2. repo level secret, OwnerID is 0 and RepoID is repo ID
+//  3. environment level secret, OwnerID is 0, RepoID is repo ID and EnvironmentID is the environment ID
 //
 // Please note that it's not acceptable to have both OwnerID and RepoID to be non-zero,
 // or it will be complicated to find secrets belonging to a specific owner.
@@ -35,13 +36,14 @@ import (
 // Please note that it's not acceptable to have both OwnerID and RepoID to zero, global secrets are not supported.
 // It's for security reasons, admin may be not aware of that the secrets could be stolen by any user when setting them as global.
 type Secret struct {
-	ID          int64
-	OwnerID     int64              `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL"`
-	RepoID      int64              `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL DEFAULT 0"`
-	Name        string             `xorm:"UNIQUE(owner_repo_name) NOT NULL"`
-	Data        string             `xorm:"LONGTEXT"` // encrypted data
-	Description string             `xorm:"TEXT"`
-	CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL"`
+	ID            int64
+	OwnerID       int64              `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL"`
+	RepoID        int64              `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL DEFAULT 0"`
+	EnvironmentID int64              `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL DEFAULT 0"`
+	Name          string             `xorm:"UNIQUE(owner_repo_name) NOT NULL"`
+	Data          string             `xorm:"LONGTEXT"` // encrypted data
+	Description   string             `xorm:"TEXT"`
+	CreatedUnix   timeutil.TimeStamp `xorm:"created NOT NULL"`
 }
 
 const (
@@ -72,7 +74,18 @@ func InsertEncryptedSecret(ctx context.Context, ownerID, repoID int64, name, dat
 	if ownerID == 0 && repoID == 0 {
 		return nil, fmt.Errorf("%w: ownerID and repoID cannot be both zero, global secrets are not supported", util.ErrInvalidArgument)
 	}
+	return insertEncryptedSecret(ctx, &Secret{OwnerID: ownerID, RepoID: repoID}, name, data, description)
+}
+
+// InsertEncryptedEnvironmentSecret creates, encrypts, and validates a new secret of an environment of the repository
+func InsertEncryptedEnvironmentSecret(ctx context.Context, repoID, environmentID int64, name, data, description string) (*Secret, error) {
+	if repoID == 0 || environmentID == 0 {
+		return nil, fmt.Errorf("%w: repoID and environmentID are required for environment secrets", util.ErrInvalidArgument)
+	}
+	return insertEncryptedSecret(ctx, &Secret{RepoID: repoID, EnvironmentID: environmentID}, name, data, description)
+}
 
+func insertEncryptedSecret(ctx context.Context, secret *Secret, name, data, description string) (*Secret, error) {
 	if len(data) > SecretDataMaxLength {
 		return nil, util.NewInvalidArgumentErrorf("data too long")
 	}
@@ -84,13 +97,9 @@ func InsertEncryptedSecret(ctx context.Context, ownerID, repoID int64, name, dat
 		return nil, err
 	}
 
-	secret := &Secret{
-		OwnerID:     ownerID,
-		RepoID:      repoID,
-		Name:        strings.ToUpper(name),
-		Data:        encrypted,
-		Description: description,
-	}
+	secret.Name = strings.ToUpper(name)
+	secret.Data = encrypted
+	secret.Description = description
 	return secret, db.Insert(ctx, secret)
 }
 
@@ -100,10 +109,11 @@ func init() {
 
 type FindSecretsOptions struct {
 	db.ListOptions
-	RepoID   int64
-	OwnerID  int64 // it will be ignored if RepoID is set
-	SecretID int64
-	Name     string
+	RepoID        int64
+	OwnerID       int64 // it will be ignored if RepoID is set
+	EnvironmentID int64 // the secrets of the repo itself are found if it's 0
+	SecretID      int64
+	Name          string
 }
 
 func (opts FindSecretsOptions) ToConds() builder.Cond {
@@ -116,6 +126,7 @@ func (opts FindSecretsOptions) ToConds() builder.Cond {
 	} else {
 		cond = cond.And(builder.Eq{"owner_id": opts.OwnerID})
 	}
+	cond = cond.And(builder.Eq{"environment_id": opts.EnvironmentID})
 
 	if opts.SecretID != 0 {
 		cond = cond.And(builder.Eq{"id": opts.SecretID})
@@ -175,16 +186,43 @@ func GetSecretsOfTask(ctx context.Context, task *actions_model.ActionTask) (map[
 		return nil, err
 	}
 
-	for _, secret := range append(ownerSecrets, repoSecrets...) {
+	if err := decryptSecrets(secrets, append(ownerSecrets, repoSecrets...)); err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
+
+// GetSecretsOfEnvironment returns the decrypted secrets of the environment targeted by the task's job,
+// they override the secrets of the repository and its owner.
+// Like GetSecretsOfTask, the secrets are not available for the runs triggered by pull requests from forks.
+func GetSecretsOfEnvironment(ctx context.Context, task *actions_model.ActionTask) (map[string]string, error) {
+	secrets := map[string]string{}
+	if task.Job.EnvironmentID == 0 ||
+		task.Job.Run.IsForkPullRequest && task.Job.Run.TriggerEvent != actions_module.GithubEventPullRequestTarget {
+		return secrets, nil
+	}
+
+	envSecrets, err := db.Find[Secret](ctx, FindSecretsOptions{RepoID: task.Job.RepoID, EnvironmentID: task.Job.EnvironmentID})
+	if err != nil {
+		log.Error("find secrets of environment %v: %v", task.Job.EnvironmentID, err)
+		return nil, err
+	}
+	if err := decryptSecrets(secrets, envSecrets); err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
+
+func decryptSecrets(secrets map[string]string, encryptedSecrets []*Secret) error {
+	for _, secret := range encryptedSecrets {
 		v, err := secret_module.DecryptSecret(setting.SecretKey, secret.Data)
 		if err != nil {
 			log.Error("decrypt secret %v %q: %v", secret.ID, secret.Name, err)
-			return nil, err
+			return err
 		}
 		secrets[secret.Name] = v
 	}
-
-	return secrets, nil
+	return nil
 }
 
 func CountWrongRepoLevelSecrets(ctx context.Context) (int64, error) {
@@ -26,4 +26,15 @@ const (
 	SettingEmailNotificationGiteaActionsAll         = "all"
 	SettingEmailNotificationGiteaActionsFailureOnly = "failure-only" // Default for actions email preference
 	SettingEmailNotificationGiteaActionsDisabled    = "disabled"
+
+	// SettingsKeyActionsTokenPermissionMode is the setting key for the default permission mode of the Actions tokens of the repositories owned by the user or the organization
+	SettingsKeyActionsTokenPermissionMode = "actions.token_permission_mode"
+	// SettingsKeyActionsApprovalPolicy is the setting key for the approval policy of the runs from forks in the repositories owned by the user or the organization
+	SettingsKeyActionsApprovalPolicy = "actions.approval_policy"
+	// SettingsKeyActionsLogRetentionDays is the setting key for the retention period of the job logs in the repositories owned by the user or the organization
+	SettingsKeyActionsLogRetentionDays = "actions.log_retention_days"
+	// SettingsKeyActionsUsageQuotaMinutes is the setting key for the monthly quota in minutes of the jobs in the repositories owned by the user or the organization
+	SettingsKeyActionsUsageQuotaMinutes = "actions.usage_quota_minutes"
+	// SettingsKeyActionsUsageQuotaPolicy is the setting key for what happens when the usage quota of the user or the organization is exceeded
+	SettingsKeyActionsUsageQuotaPolicy = "actions.usage_quota_policy"
 )
@@ -23,6 +23,9 @@ const (
 	GithubEventPullRequestComment       = "pull_request_comment"
 	GithubEventGollum                   = "gollum"
 	GithubEventSchedule                 = "schedule"
+	GithubEventLabel                    = "label"
+	GithubEventMilestone                = "milestone"
+	GithubEventRepositoryDispatch       = "repository_dispatch"
 )
 
 // IsDefaultBranchWorkflow returns true if the event only triggers workflows on the default branch
@@ -59,6 +62,18 @@ func IsDefaultBranchWorkflow(triggedEvent webhook_module.HookEventType) bool {
 		// Github "issues" event
 		// https://docs.github.com/en/actions/using-workflows/events-that-trigger-workflows#issues
 		return true
+	case webhook_module.HookEventLabel:
+		// GitHub "label" event
+		// https://docs.github.com/en/actions/using-workflows/events-that-trigger-workflows#label
+		return true
+	case webhook_module.HookEventMilestone:
+		// GitHub "milestone" event
+		// https://docs.github.com/en/actions/using-workflows/events-that-trigger-workflows#milestone
+		return true
+	case webhook_module.HookEventRepositoryDispatch:
+		// GitHub "repository_dispatch" event
+		// https://docs.github.com/en/actions/using-workflows/events-that-trigger-workflows#repository_dispatch
+		return true
 	}
 
 	return false
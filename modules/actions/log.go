@@ -134,19 +134,47 @@ func TransferLogs(ctx context.Context, filename string) (func(), error) {
 	}
 	defer f.Close()
 
-	var reader io.Reader = f
+	if err := saveLogs(filename, f); err != nil {
+		return nil, err
+	}
+	return remove, nil
+}
+
+// CompressLogs compresses the uncompressed log in object storage with zstd and returns the filename of the compressed log.
+// The offsets of the lines don't change since the compressed log is seekable by the uncompressed offsets.
+// The uncompressed log is kept, it should be removed after nothing refers to it.
+func CompressLogs(filename string) (string, error) {
+	if strings.HasSuffix(filename, ".zst") {
+		return "", fmt.Errorf("log %q has been compressed", filename)
+	}
+	f, err := storage.Actions.Open(filename)
+	if err != nil {
+		return "", fmt.Errorf("storage open %q: %w", filename, err)
+	}
+	defer f.Close()
+
+	compressed := filename + ".zst"
+	if err := saveLogs(compressed, f); err != nil {
+		return "", err
+	}
+	return compressed, nil
+}
+
+// saveLogs saves the content to object storage, it compresses the content if the filename has the suffix ".zst"
+func saveLogs(filename string, content io.Reader) error {
+	reader := content
 	if strings.HasSuffix(filename, ".zst") {
 		r, w := io.Pipe()
 		reader = r
 		zstdWriter, err := zstd.NewSeekableWriter(w, logZstdBlockSize)
 		if err != nil {
-			return nil, fmt.Errorf("zstd NewSeekableWriter: %w", err)
+			return fmt.Errorf("zstd NewSeekableWriter: %w", err)
 		}
 		go func() {
 			defer func() {
 				_ = w.CloseWithError(zstdWriter.Close())
 			}()
-			if _, err := io.Copy(zstdWriter, f); err != nil {
+			if _, err := io.Copy(zstdWriter, content); err != nil {
 				_ = w.CloseWithError(err)
 				return
 			}
@@ -154,9 +182,9 @@ func TransferLogs(ctx context.Context, filename string) (func(), error) {
 	}
 
 	if _, err := storage.Actions.Save(filename, reader, -1); err != nil {
-		return nil, fmt.Errorf("storage save %q: %w", filename, err)
+		return fmt.Errorf("storage save %q: %w", filename, err)
 	}
-	return remove, nil
+	return nil
 }
 
 func RemoveLogs(ctx context.Context, inStorage bool, filename string) error {
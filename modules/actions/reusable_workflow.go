@@ -0,0 +1,68 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"path"
+	"strings"
+
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ReusableWorkflowMaxDepth is the max nesting depth of reusable workflows, the top level caller workflow is not counted.
+// See https://docs.github.com/en/actions/sharing-automations/reusing-workflows#nesting-reusable-workflows
+const ReusableWorkflowMaxDepth = 4
+
+// ReusableWorkflowRef represents a reusable workflow referenced by `jobs.<job_id>.uses`
+type ReusableWorkflowRef struct {
+	OwnerName string // empty for a workflow in the same repository
+	RepoName  string // empty for a workflow in the same repository
+	Path      string // the path of the workflow file in the repository, like ".gitea/workflows/build.yml"
+	Ref       string // the branch, tag or commit SHA, empty for a workflow in the same repository
+}
+
+// IsLocal returns whether the reusable workflow is in the same repository and commit as the caller workflow
+func (r *ReusableWorkflowRef) IsLocal() bool {
+	return r.OwnerName == ""
+}
+
+// IsReusableWorkflowUses returns whether the `uses` of a job refers to a reusable workflow
+func IsReusableWorkflowUses(uses string) bool {
+	_, err := ParseReusableWorkflowUses(uses)
+	return err == nil
+}
+
+// ParseReusableWorkflowUses parses the `uses` of a job, it supports two formats:
+//   - "./.gitea/workflows/build.yml" for a workflow in the same repository
+//   - "owner/repo/.gitea/workflows/build.yml@ref" for a workflow in another repository
+//
+// See https://docs.github.com/en/actions/writing-workflows/workflow-syntax-for-github-actions#jobsjob_iduses
+func ParseReusableWorkflowUses(uses string) (*ReusableWorkflowRef, error) {
+	if strings.HasPrefix(uses, "./") {
+		p := path.Clean(strings.TrimPrefix(uses, "./"))
+		if !IsWorkflow(p) || strings.Contains(p, "@") {
+			return nil, util.NewInvalidArgumentErrorf("invalid local reusable workflow %q", uses)
+		}
+		return &ReusableWorkflowRef{Path: p}, nil
+	}
+
+	fullPath, ref, ok := strings.Cut(uses, "@")
+	if !ok || ref == "" {
+		return nil, util.NewInvalidArgumentErrorf("reusable workflow %q requires a ref", uses)
+	}
+	parts := strings.SplitN(fullPath, "/", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+		return nil, util.NewInvalidArgumentErrorf("invalid reusable workflow %q", uses)
+	}
+	p := path.Clean(parts[2])
+	if !IsWorkflow(p) {
+		return nil, util.NewInvalidArgumentErrorf("invalid reusable workflow path %q", uses)
+	}
+	return &ReusableWorkflowRef{
+		OwnerName: parts[0],
+		RepoName:  parts[1],
+		Path:      p,
+		Ref:       ref,
+	}, nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReusableWorkflowUses(t *testing.T) {
+	testCases := []struct {
+		uses     string
+		expected *ReusableWorkflowRef
+	}{
+		{
+			uses:     "./.gitea/workflows/build.yml",
+			expected: &ReusableWorkflowRef{Path: ".gitea/workflows/build.yml"},
+		},
+		{
+			uses:     "./.github/workflows/build.yaml",
+			expected: &ReusableWorkflowRef{Path: ".github/workflows/build.yaml"},
+		},
+		{
+			uses:     "owner/repo/.gitea/workflows/build.yml@main",
+			expected: &ReusableWorkflowRef{OwnerName: "owner", RepoName: "repo", Path: ".gitea/workflows/build.yml", Ref: "main"},
+		},
+		{
+			uses:     "owner/repo/.github/workflows/build.yml@v1.0.0",
+			expected: &ReusableWorkflowRef{OwnerName: "owner", RepoName: "repo", Path: ".github/workflows/build.yml", Ref: "v1.0.0"},
+		},
+		{uses: "./.gitea/workflows/build.yml@main"},
+		{uses: "./build.yml"},
+		{uses: "owner/repo/.gitea/workflows/build.yml"},
+		{uses: "owner/repo/build.yml@main"},
+		{uses: "owner/.gitea/workflows/build.yml@main"},
+		{uses: "actions/checkout@v4"},
+		{uses: "docker://alpine:3.8"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.uses, func(t *testing.T) {
+			ref, err := ParseReusableWorkflowUses(tc.uses)
+			if tc.expected == nil {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, ref)
+			assert.Equal(t, tc.expected.OwnerName == "", ref.IsLocal())
+		})
+	}
+}
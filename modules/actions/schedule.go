@@ -0,0 +1,61 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+// GetScheduleSpecs returns the cron specs of the "schedule" event of the workflow.
+// A schedule can have a "timezone" next to the "cron", the timezone is added to the spec as the "CRON_TZ=" prefix,
+// the specs without a timezone are evaluated in UTC.
+//
+//	on:
+//	  schedule:
+//	    - cron: "30 2 * * *"
+//	      timezone: "Europe/Berlin"
+func GetScheduleSpecs(workflow *model.Workflow) ([]string, error) {
+	schedules, ok := workflow.OnEvent("schedule").([]any)
+	if !ok {
+		return nil, nil
+	}
+
+	specs := make([]string, 0, len(schedules))
+	for _, v := range schedules {
+		schedule, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		cronSpec, ok := schedule["cron"].(string)
+		if !ok {
+			continue
+		}
+		cronSpec = strings.TrimSpace(cronSpec)
+		if schedule["timezone"] == nil {
+			specs = append(specs, cronSpec)
+			continue
+		}
+
+		timezone, ok := schedule["timezone"].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid timezone of schedule %q", cronSpec)
+		}
+		if timezone = strings.TrimSpace(timezone); timezone == "" {
+			specs = append(specs, cronSpec)
+			continue
+		}
+		if strings.HasPrefix(cronSpec, "TZ=") || strings.HasPrefix(cronSpec, "CRON_TZ=") {
+			return nil, fmt.Errorf("schedule %q has both a timezone prefix and the timezone %q", cronSpec, timezone)
+		}
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return nil, fmt.Errorf("invalid timezone %q of schedule %q: %w", timezone, cronSpec, err)
+		}
+		specs = append(specs, fmt.Sprintf("CRON_TZ=%s %s", timezone, cronSpec))
+	}
+	return specs, nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nektos/act/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetScheduleSpecs(t *testing.T) {
+	readWorkflow := func(t *testing.T, content string) *model.Workflow {
+		workflow, err := model.ReadWorkflow(strings.NewReader(content))
+		require.NoError(t, err)
+		return workflow
+	}
+
+	specs, err := GetScheduleSpecs(readWorkflow(t, `
+on:
+  schedule:
+    - cron: "0 10 * * *"
+    - cron: "30 2 * * 1-5"
+      timezone: Europe/Berlin
+    - cron: "CRON_TZ=Asia/Tokyo 0 0 * * *"
+    - cron: "@daily"
+      timezone: ""
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo test
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"0 10 * * *",
+		"CRON_TZ=Europe/Berlin 30 2 * * 1-5",
+		"CRON_TZ=Asia/Tokyo 0 0 * * *",
+		"@daily",
+	}, specs)
+
+	specs, err = GetScheduleSpecs(readWorkflow(t, "on: push\njobs: {}\n"))
+	require.NoError(t, err)
+	assert.Empty(t, specs)
+
+	_, err = GetScheduleSpecs(readWorkflow(t, `
+on:
+  schedule:
+    - cron: "0 10 * * *"
+      timezone: Mars/Olympus_Mons
+jobs: {}
+`))
+	assert.ErrorContains(t, err, `invalid timezone "Mars/Olympus_Mons"`)
+
+	_, err = GetScheduleSpecs(readWorkflow(t, `
+on:
+  schedule:
+    - cron: "TZ=UTC 0 10 * * *"
+      timezone: Europe/Berlin
+jobs: {}
+`))
+	assert.ErrorContains(t, err, "both a timezone prefix and the timezone")
+}
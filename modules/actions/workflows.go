@@ -191,8 +191,6 @@ func detectMatched(gitRepo *git.Repository, commit *git.Commit, triggedEvent web
 
 	switch triggedEvent {
 	case // events with no activity types
-		webhook_module.HookEventCreate,
-		webhook_module.HookEventDelete,
 		webhook_module.HookEventFork,
 		webhook_module.HookEventWiki,
 		webhook_module.HookEventSchedule:
@@ -206,6 +204,20 @@ func detectMatched(gitRepo *git.Repository, commit *git.Commit, triggedEvent web
 		webhook_module.HookEventPush:
 		return matchPushEvent(commit, payload.(*api.PushPayload), evt)
 
+	case // create
+		webhook_module.HookEventCreate:
+		if createPayload, ok := payload.(*api.CreatePayload); ok {
+			return matchCreateDeleteEvent(git.RefName(createPayload.Ref), evt)
+		}
+		return len(evt.Acts()) == 0
+
+	case // delete
+		webhook_module.HookEventDelete:
+		if deletePayload, ok := payload.(*api.DeletePayload); ok {
+			return matchCreateDeleteEvent(git.RefName(deletePayload.Ref), evt)
+		}
+		return len(evt.Acts()) == 0
+
 	case // issues
 		webhook_module.HookEventIssues,
 		webhook_module.HookEventIssueAssign,
@@ -250,6 +262,18 @@ func detectMatched(gitRepo *git.Repository, commit *git.Commit, triggedEvent web
 		webhook_module.HookEventWorkflowRun:
 		return matchWorkflowRunEvent(payload.(*api.WorkflowRunPayload), evt)
 
+	case // label
+		webhook_module.HookEventLabel:
+		return matchLabelEvent(payload.(*api.LabelPayload), evt)
+
+	case // milestone
+		webhook_module.HookEventMilestone:
+		return matchMilestoneEvent(payload.(*api.MilestonePayload), evt)
+
+	case // repository_dispatch
+		webhook_module.HookEventRepositoryDispatch:
+		return matchRepositoryDispatchEvent(payload.(*api.RepositoryDispatchPayload), evt)
+
 	default:
 		log.Warn("unsupported event %q", triggedEvent)
 		return false
@@ -745,3 +769,131 @@ func matchWorkflowRunEvent(payload *api.WorkflowRunPayload, evt *jobparser.Event
 	}
 	return matchTimes == len(evt.Acts())
 }
+
+// matchCreateDeleteEvent matches the created or deleted ref with the filters of the workflow.
+// GitHub doesn't support filters for these events, they are supported like the ones of the push event.
+func matchCreateDeleteEvent(refName git.RefName, evt *jobparser.Event) bool {
+	// with no special filter parameters
+	if len(evt.Acts()) == 0 {
+		return true
+	}
+
+	matchTimes := 0
+	hasBranchFilter := false
+	hasTagFilter := false
+	// all acts conditions should be satisfied
+	for cond, vals := range evt.Acts() {
+		switch cond {
+		case "branches", "branches-ignore":
+			hasBranchFilter = true
+			if !refName.IsBranch() {
+				break
+			}
+			patterns, err := workflowpattern.CompilePatterns(vals...)
+			if err != nil {
+				break
+			}
+			if cond == "branches" && !workflowpattern.Skip(patterns, []string{refName.BranchName()}, &workflowpattern.EmptyTraceWriter{}) ||
+				cond == "branches-ignore" && !workflowpattern.Filter(patterns, []string{refName.BranchName()}, &workflowpattern.EmptyTraceWriter{}) {
+				matchTimes++
+			}
+		case "tags", "tags-ignore":
+			hasTagFilter = true
+			if !refName.IsTag() {
+				break
+			}
+			patterns, err := workflowpattern.CompilePatterns(vals...)
+			if err != nil {
+				break
+			}
+			if cond == "tags" && !workflowpattern.Skip(patterns, []string{refName.TagName()}, &workflowpattern.EmptyTraceWriter{}) ||
+				cond == "tags-ignore" && !workflowpattern.Filter(patterns, []string{refName.TagName()}, &workflowpattern.EmptyTraceWriter{}) {
+				matchTimes++
+			}
+		default:
+			log.Warn("create or delete event unsupported condition %q", cond)
+		}
+	}
+	// if both branch and tag filter are defined in the workflow only one needs to match
+	if hasBranchFilter && hasTagFilter {
+		matchTimes++
+	}
+	return matchTimes == len(evt.Acts())
+}
+
+func matchLabelEvent(payload *api.LabelPayload, evt *jobparser.Event) bool {
+	// with no special filter parameters
+	if len(evt.Acts()) == 0 {
+		return true
+	}
+
+	matchTimes := 0
+	// all acts conditions should be satisfied
+	for cond, vals := range evt.Acts() {
+		switch cond {
+		case "types":
+			// See https://docs.github.com/en/actions/using-workflows/events-that-trigger-workflows#label
+			// Activity types with the same name:
+			// created, edited, deleted
+			for _, val := range vals {
+				if glob.MustCompile(val, '/').Match(string(payload.Action)) {
+					matchTimes++
+					break
+				}
+			}
+		default:
+			log.Warn("label event unsupported condition %q", cond)
+		}
+	}
+	return matchTimes == len(evt.Acts())
+}
+
+func matchMilestoneEvent(payload *api.MilestonePayload, evt *jobparser.Event) bool {
+	// with no special filter parameters
+	if len(evt.Acts()) == 0 {
+		return true
+	}
+
+	matchTimes := 0
+	// all acts conditions should be satisfied
+	for cond, vals := range evt.Acts() {
+		switch cond {
+		case "types":
+			// See https://docs.github.com/en/actions/using-workflows/events-that-trigger-workflows#milestone
+			// Activity types with the same name:
+			// created, closed, opened, edited, deleted
+			for _, val := range vals {
+				if glob.MustCompile(val, '/').Match(string(payload.Action)) {
+					matchTimes++
+					break
+				}
+			}
+		default:
+			log.Warn("milestone event unsupported condition %q", cond)
+		}
+	}
+	return matchTimes == len(evt.Acts())
+}
+
+func matchRepositoryDispatchEvent(payload *api.RepositoryDispatchPayload, evt *jobparser.Event) bool {
+	// with no special filter parameters
+	if len(evt.Acts()) == 0 {
+		return true
+	}
+
+	matchTimes := 0
+	// all acts conditions should be satisfied
+	for cond, vals := range evt.Acts() {
+		switch cond {
+		case "types":
+			// See https://docs.github.com/en/actions/using-workflows/events-that-trigger-workflows#repository_dispatch
+			// the types are the custom event types sent by the API
+			if slices.Contains(vals, payload.Action) {
+				matchTimes++
+			}
+		default:
+			log.Warn("repository dispatch event unsupported condition %q", cond)
+		}
+	}
+	return matchTimes == len(evt.Acts())
+}
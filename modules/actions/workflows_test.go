@@ -143,6 +143,55 @@ func TestDetectMatched(t *testing.T) {
 			yamlOn:   "on:\n  push:\n    paths:\n      - src/**",
 			expected: true,
 		},
+		{
+			desc:         "HookEventCreate(create) branch matches GithubEventCreate(create) with branches filter",
+			triggedEvent: webhook_module.HookEventCreate,
+			payload:      &api.CreatePayload{Ref: "refs/heads/release/v1", RefType: "branch"},
+			yamlOn:       "on:\n  create:\n    branches: [release/*]",
+			expected:     true,
+		},
+		{
+			desc:         "HookEventCreate(create) tag doesn't match GithubEventCreate(create) with branches filter",
+			triggedEvent: webhook_module.HookEventCreate,
+			payload:      &api.CreatePayload{Ref: "refs/tags/v1.0.0", RefType: "tag"},
+			yamlOn:       "on:\n  create:\n    branches: [release/*]",
+			expected:     false,
+		},
+		{
+			desc:         "HookEventDelete(delete) tag matches GithubEventDelete(delete) with tags-ignore filter",
+			triggedEvent: webhook_module.HookEventDelete,
+			payload:      &api.DeletePayload{Ref: "refs/tags/v1.0.0", RefType: "tag"},
+			yamlOn:       "on:\n  delete:\n    tags-ignore: [v2.*]",
+			expected:     true,
+		},
+		{
+			desc:         "HookEventLabel(label) `created` action matches GithubEventLabel(label) with `created` activity type",
+			triggedEvent: webhook_module.HookEventLabel,
+			payload:      &api.LabelPayload{Action: api.HookLabelCreated},
+			yamlOn:       "on:\n  label:\n    types: [created]",
+			expected:     true,
+		},
+		{
+			desc:         "HookEventMilestone(milestone) `closed` action doesn't match GithubEventMilestone(milestone) with `opened` activity type",
+			triggedEvent: webhook_module.HookEventMilestone,
+			payload:      &api.MilestonePayload{Action: api.HookMilestoneClosed},
+			yamlOn:       "on:\n  milestone:\n    types: [opened]",
+			expected:     false,
+		},
+		{
+			desc:         "HookEventRepositoryDispatch(repository_dispatch) matches GithubEventRepositoryDispatch(repository_dispatch) with the same type",
+			triggedEvent: webhook_module.HookEventRepositoryDispatch,
+			payload:      &api.RepositoryDispatchPayload{Action: "deploy"},
+			yamlOn:       "on:\n  repository_dispatch:\n    types: [deploy, rollback]",
+			expected:     true,
+		},
+		{
+			desc:         "HookEventRepositoryDispatch(repository_dispatch) doesn't match GithubEventRepositoryDispatch(repository_dispatch) with other types",
+			triggedEvent: webhook_module.HookEventRepositoryDispatch,
+			payload:      &api.RepositoryDispatchPayload{Action: "deploy"},
+			yamlOn:       "on:\n  repository_dispatch:\n    types: [rollback]",
+			expected:     false,
+		},
 	}
 
 	for _, tc := range testCases {
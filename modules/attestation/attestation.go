@@ -0,0 +1,149 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+// Package attestation implements the in-toto statements and their DSSE envelopes,
+// see https://github.com/in-toto/attestation/blob/main/spec/v1/README.md and https://github.com/secure-systems-lab/dsse/blob/master/envelope.md
+package attestation
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/modules/json"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// StatementType is the type of the in-toto statements
+	StatementType = "https://in-toto.io/Statement/v1"
+	// PayloadType is the type of the payloads of the envelopes containing in-toto statements
+	PayloadType = "application/vnd.in-toto+json"
+)
+
+// Statement is an in-toto statement, which binds the predicate to the subjects
+type Statement struct {
+	Type          string     `json:"_type"`
+	Subject       []*Subject `json:"subject"`
+	PredicateType string     `json:"predicateType"`
+	Predicate     any        `json:"predicate"`
+}
+
+// Subject is a software artifact identified by its digests
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Envelope is a DSSE envelope which contains a signed payload
+type Envelope struct {
+	PayloadType string       `json:"payloadType"`
+	Payload     string       `json:"payload"` // base64 encoded
+	Signatures  []*Signature `json:"signatures"`
+}
+
+// Signature is a signature of an envelope
+type Signature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"` // base64 encoded
+}
+
+// Key is the key to sign and verify the envelopes, the signatures are computed by the JWS algorithm of the key
+type Key interface {
+	SigningMethod() jwt.SigningMethod
+	SignKey() any
+	VerifyKey() any
+}
+
+// ParseDigest parses a digest in the form "sha256:<hex>" and returns the algorithm and the lowercase hex value
+func ParseDigest(digest string) (algorithm, value string, err error) {
+	algorithm, value, ok := strings.Cut(digest, ":")
+	if !ok || algorithm != "sha256" {
+		return "", "", fmt.Errorf("unsupported digest %q, it must be sha256:<hex>", digest)
+	}
+	value = strings.ToLower(value)
+	if b, err := hex.DecodeString(value); err != nil || len(b) != 32 {
+		return "", "", fmt.Errorf("invalid sha256 digest %q", digest)
+	}
+	return algorithm, value, nil
+}
+
+// PAE returns the pre-authentication encoding of the payload, which is what is signed
+func PAE(payloadType string, payload []byte) []byte {
+	return fmt.Appendf(nil, "DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload)
+}
+
+// Sign marshals the statement and signs it by the key
+func Sign(statement *Statement, keyID string, key Key) (*Envelope, error) {
+	payload, err := json.Marshal(statement)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := key.SigningMethod().Sign(string(PAE(PayloadType, payload)), key.SignKey())
+	if err != nil {
+		return nil, fmt.Errorf("sign: %w", err)
+	}
+	return &Envelope{
+		PayloadType: PayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []*Signature{{KeyID: keyID, Sig: base64.StdEncoding.EncodeToString(sig)}},
+	}, nil
+}
+
+// ErrInvalidSignature is returned when an envelope isn't signed by the key
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// Verify verifies the envelope is signed by the key and returns the statement in it
+func Verify(envelope *Envelope, keyID string, key Key) (*Statement, error) {
+	if envelope.PayloadType != PayloadType {
+		return nil, fmt.Errorf("unsupported payload type %q", envelope.PayloadType)
+	}
+	payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+
+	verified := false
+	for _, signature := range envelope.Signatures {
+		if signature.KeyID != keyID {
+			continue
+		}
+		sig, err := base64.StdEncoding.DecodeString(signature.Sig)
+		if err != nil {
+			continue
+		}
+		if key.SigningMethod().Verify(string(PAE(envelope.PayloadType, payload)), sig, key.VerifyKey()) == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, ErrInvalidSignature
+	}
+
+	var statement Statement
+	if err := json.Unmarshal(payload, &statement); err != nil {
+		return nil, fmt.Errorf("invalid statement: %w", err)
+	}
+	if statement.Type != StatementType {
+		return nil, fmt.Errorf("unsupported statement type %q", statement.Type)
+	}
+	return &statement, nil
+}
+
+// HasSubject returns whether the statement has a subject with the digest in the form "sha256:<hex>"
+func (s *Statement) HasSubject(digest string) bool {
+	algorithm, value, err := ParseDigest(digest)
+	if err != nil {
+		return false
+	}
+	for _, subject := range s.Subject {
+		if strings.EqualFold(subject.Digest[algorithm], value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package attestation
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testKey struct {
+	key *ecdsa.PrivateKey
+}
+
+func (k testKey) SigningMethod() jwt.SigningMethod { return jwt.SigningMethodES256 }
+func (k testKey) SignKey() any                     { return k.key }
+func (k testKey) VerifyKey() any                   { return k.key.Public() }
+
+func newTestKey(t *testing.T) testKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return testKey{key: key}
+}
+
+func TestParseDigest(t *testing.T) {
+	algorithm, value, err := ParseDigest("sha256:E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855")
+	require.NoError(t, err)
+	assert.Equal(t, "sha256", algorithm)
+	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", value)
+
+	for _, digest := range []string{"", "e3b0c442", "sha1:da39a3ee5e6b4b0d3255bfef95601890afd80709", "sha256:e3b0c442", "sha256:zz"} {
+		_, _, err := ParseDigest(digest)
+		assert.Error(t, err, digest)
+	}
+}
+
+func TestPAE(t *testing.T) {
+	// the example of https://github.com/secure-systems-lab/dsse/blob/master/protocol.md
+	assert.Equal(t, "DSSEv1 29 http://example.com/HelloWorld 11 hello world", string(PAE("http://example.com/HelloWorld", []byte("hello world"))))
+}
+
+func TestSignAndVerify(t *testing.T) {
+	key := newTestKey(t)
+	statement := &Statement{
+		Type:          StatementType,
+		Subject:       []*Subject{{Name: "app", Digest: map[string]string{"sha256": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"}}},
+		PredicateType: "https://slsa.dev/provenance/v1",
+		Predicate:     map[string]any{"buildDefinition": map[string]any{"buildType": "test"}},
+	}
+
+	envelope, err := Sign(statement, "key-1", key)
+	require.NoError(t, err)
+	assert.Equal(t, PayloadType, envelope.PayloadType)
+	require.Len(t, envelope.Signatures, 1)
+	assert.Equal(t, "key-1", envelope.Signatures[0].KeyID)
+
+	verified, err := Verify(envelope, "key-1", key)
+	require.NoError(t, err)
+	assert.Equal(t, statement.PredicateType, verified.PredicateType)
+	assert.True(t, verified.HasSubject("sha256:E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855"))
+	assert.False(t, verified.HasSubject("sha256:0000000000000000000000000000000000000000000000000000000000000000"))
+
+	t.Run("OtherKey", func(t *testing.T) {
+		_, err := Verify(envelope, "key-1", newTestKey(t))
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+		_, err = Verify(envelope, "key-2", key)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("Tampered", func(t *testing.T) {
+		tampered := *envelope
+		tampered.Payload = base64.StdEncoding.EncodeToString([]byte(`{"_type":"https://in-toto.io/Statement/v1","subject":[]}`))
+		_, err := Verify(&tampered, "key-1", key)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+
+		tampered = *envelope
+		tampered.PayloadType = "application/json"
+		_, err = Verify(&tampered, "key-1", key)
+		assert.Error(t, err)
+	})
+}
@@ -131,6 +131,15 @@ func FormatError(err error, locale translation.Locale) (string, error) {
 	return "", err
 }
 
+// EscapeFormula prefixes the cell with a single quote if it starts with a character which makes the spreadsheet applications
+// evaluate it as a formula, to prevent CSV injection in the exported files.
+func EscapeFormula(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
 // Looks for possible delimiters right before or after (with spaces after the former) double quotes with closing quotes
 var beforeAfterQuotes = regexp.MustCompile(`([,@\t;|]{0,1}) *(?:"[^"]*")+([,@\t;|]{0,1})`)
 
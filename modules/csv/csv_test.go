@@ -583,3 +583,21 @@ func TestFormatError(t *testing.T) {
 		}
 	}
 }
+
+func TestEscapeFormula(t *testing.T) {
+	cases := map[string]string{
+		"":                  "",
+		"user2/repo1":       "user2/repo1",
+		"ubuntu-latest":     "ubuntu-latest",
+		"=HYPERLINK(\"x\")": "'=HYPERLINK(\"x\")",
+		"+1":                "'+1",
+		"-1+2":              "'-1+2",
+		"@SUM(A1:A2)":       "'@SUM(A1:A2)",
+		"\t=1":              "'\t=1",
+		"\r=1":              "'\r=1",
+		"a=1":               "a=1",
+	}
+	for cell, expected := range cases {
+		assert.Equal(t, expected, EscapeFormula(cell), "cell %q", cell)
+	}
+}
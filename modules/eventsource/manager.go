@@ -12,6 +12,7 @@ type Manager struct {
 	mutex sync.Mutex
 
 	messengers map[int64]*Messenger
+	topics     map[string]*Messenger // the messengers of the topics which are not bound to a user, e.g. the logs of an Actions task
 	connection chan struct{}
 }
 
@@ -20,6 +21,7 @@ var manager *Manager
 func init() {
 	manager = &Manager{
 		messengers: make(map[int64]*Messenger),
+		topics:     make(map[string]*Messenger),
 		connection: make(chan struct{}, 1),
 	}
 }
@@ -66,6 +68,10 @@ func (m *Manager) UnregisterAll() {
 		messenger.UnregisterAll()
 	}
 	m.messengers = map[int64]*Messenger{}
+	for _, messenger := range m.topics {
+		messenger.UnregisterAll()
+	}
+	m.topics = map[string]*Messenger{}
 }
 
 // SendMessage sends a message to a particular user
@@ -87,3 +93,38 @@ func (m *Manager) SendMessageBlocking(uid int64, message *Event) {
 		messenger.SendMessageBlocking(message)
 	}
 }
+
+// RegisterTopic registers a message channel of the topic
+func (m *Manager) RegisterTopic(topic string) <-chan *Event {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	messenger, ok := m.topics[topic]
+	if !ok {
+		messenger = NewMessenger(0)
+		m.topics[topic] = messenger
+	}
+	return messenger.Register()
+}
+
+// UnregisterTopic unregisters a message channel of the topic
+func (m *Manager) UnregisterTopic(topic string, channel <-chan *Event) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	messenger, ok := m.topics[topic]
+	if !ok {
+		return
+	}
+	if messenger.Unregister(channel) {
+		delete(m.topics, topic)
+	}
+}
+
+// SendTopicMessage sends a message to the channels of the topic, the message is dropped for the channels which are full
+func (m *Manager) SendTopicMessage(topic string, message *Event) {
+	m.mutex.Lock()
+	messenger, ok := m.topics[topic]
+	m.mutex.Unlock()
+	if ok {
+		messenger.SendMessage(message)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package eventsource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagerTopics(t *testing.T) {
+	m := &Manager{
+		messengers: map[int64]*Messenger{},
+		topics:     map[string]*Messenger{},
+		connection: make(chan struct{}, 1),
+	}
+
+	ch1 := m.RegisterTopic("topic-1")
+	ch2 := m.RegisterTopic("topic-2")
+	m.SendTopicMessage("topic-1", &Event{Name: "message"})
+	// the messages of the topics without channels are dropped
+	m.SendTopicMessage("topic-3", &Event{Name: "message"})
+
+	assert.Equal(t, "message", (<-ch1).Name)
+	assert.Empty(t, ch2)
+
+	m.UnregisterTopic("topic-1", ch1)
+	_, ok := <-ch1
+	assert.False(t, ok)
+	assert.NotContains(t, m.topics, "topic-1")
+	assert.Contains(t, m.topics, "topic-2")
+	// the user channels are not affected by the topics
+	assert.Empty(t, m.messengers)
+}
@@ -5,9 +5,12 @@ package metrics
 
 import (
 	"runtime"
+	"time"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	activities_model "code.gitea.io/gitea/models/activities"
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -18,34 +21,42 @@ const namespace = "gitea_"
 // Collector implements the prometheus.Collector interface and
 // exposes gitea metrics for prometheus
 type Collector struct {
-	Accesses           *prometheus.Desc
-	Attachments        *prometheus.Desc
-	BuildInfo          *prometheus.Desc
-	Comments           *prometheus.Desc
-	Follows            *prometheus.Desc
-	HookTasks          *prometheus.Desc
-	Issues             *prometheus.Desc
-	IssuesOpen         *prometheus.Desc
-	IssuesClosed       *prometheus.Desc
-	IssuesByLabel      *prometheus.Desc
-	IssuesByRepository *prometheus.Desc
-	Labels             *prometheus.Desc
-	LoginSources       *prometheus.Desc
-	Milestones         *prometheus.Desc
-	Mirrors            *prometheus.Desc
-	Oauths             *prometheus.Desc
-	Organizations      *prometheus.Desc
-	Projects           *prometheus.Desc
-	ProjectColumns     *prometheus.Desc
-	PublicKeys         *prometheus.Desc
-	Releases           *prometheus.Desc
-	Repositories       *prometheus.Desc
-	Stars              *prometheus.Desc
-	Teams              *prometheus.Desc
-	UpdateTasks        *prometheus.Desc
-	Users              *prometheus.Desc
-	Watches            *prometheus.Desc
-	Webhooks           *prometheus.Desc
+	Accesses             *prometheus.Desc
+	ActionsJobDuration   *prometheus.Desc
+	ActionsJobWaitTime   *prometheus.Desc
+	ActionsJobsQueued    *prometheus.Desc
+	ActionsQueueAge      *prometheus.Desc
+	ActionsRunnersBusy   *prometheus.Desc
+	ActionsRunnersIdle   *prometheus.Desc
+	ActionsRunnersOnline *prometheus.Desc
+	ActionsTaskFailures  *prometheus.Desc
+	Attachments          *prometheus.Desc
+	BuildInfo            *prometheus.Desc
+	Comments             *prometheus.Desc
+	Follows              *prometheus.Desc
+	HookTasks            *prometheus.Desc
+	Issues               *prometheus.Desc
+	IssuesOpen           *prometheus.Desc
+	IssuesClosed         *prometheus.Desc
+	IssuesByLabel        *prometheus.Desc
+	IssuesByRepository   *prometheus.Desc
+	Labels               *prometheus.Desc
+	LoginSources         *prometheus.Desc
+	Milestones           *prometheus.Desc
+	Mirrors              *prometheus.Desc
+	Oauths               *prometheus.Desc
+	Organizations        *prometheus.Desc
+	Projects             *prometheus.Desc
+	ProjectColumns       *prometheus.Desc
+	PublicKeys           *prometheus.Desc
+	Releases             *prometheus.Desc
+	Repositories         *prometheus.Desc
+	Stars                *prometheus.Desc
+	Teams                *prometheus.Desc
+	UpdateTasks          *prometheus.Desc
+	Users                *prometheus.Desc
+	Watches              *prometheus.Desc
+	Webhooks             *prometheus.Desc
 }
 
 // NewCollector returns a new Collector with all prometheus.Desc initialized
@@ -56,6 +67,46 @@ func NewCollector() Collector {
 			"Number of Accesses",
 			nil, nil,
 		),
+		ActionsJobDuration: prometheus.NewDesc(
+			namespace+"actions_job_duration_seconds",
+			"Duration of the Actions jobs run by runners",
+			nil, nil,
+		),
+		ActionsJobWaitTime: prometheus.NewDesc(
+			namespace+"actions_job_wait_seconds",
+			"Time the Actions jobs waited for a runner",
+			nil, nil,
+		),
+		ActionsJobsQueued: prometheus.NewDesc(
+			namespace+"actions_jobs_queued",
+			"Number of Actions jobs waiting for a runner",
+			[]string{"labels"}, nil,
+		),
+		ActionsQueueAge: prometheus.NewDesc(
+			namespace+"actions_jobs_queued_oldest_seconds",
+			"Time the oldest queued Actions job has been waiting",
+			nil, nil,
+		),
+		ActionsRunnersOnline: prometheus.NewDesc(
+			namespace+"actions_runners_online",
+			"Number of online Actions runners",
+			nil, nil,
+		),
+		ActionsRunnersBusy: prometheus.NewDesc(
+			namespace+"actions_runners_busy",
+			"Number of online Actions runners running a task",
+			nil, nil,
+		),
+		ActionsRunnersIdle: prometheus.NewDesc(
+			namespace+"actions_runners_idle",
+			"Number of online Actions runners without a running task",
+			nil, nil,
+		),
+		ActionsTaskFailures: prometheus.NewDesc(
+			namespace+"actions_task_failures_total",
+			"Number of failed Actions tasks",
+			nil, nil,
+		),
 		Attachments: prometheus.NewDesc(
 			namespace+"attachments",
 			"Number of Attachments",
@@ -202,6 +253,14 @@ func NewCollector() Collector {
 // Describe returns all possible prometheus.Desc
 func (c Collector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.Accesses
+	ch <- c.ActionsJobDuration
+	ch <- c.ActionsJobWaitTime
+	ch <- c.ActionsJobsQueued
+	ch <- c.ActionsQueueAge
+	ch <- c.ActionsRunnersOnline
+	ch <- c.ActionsRunnersBusy
+	ch <- c.ActionsRunnersIdle
+	ch <- c.ActionsTaskFailures
 	ch <- c.Attachments
 	ch <- c.BuildInfo
 	ch <- c.Comments
@@ -245,6 +304,9 @@ func (c Collector) Collect(ch chan<- prometheus.Metric) {
 		prometheus.GaugeValue,
 		float64(stats.Counter.Attachment),
 	)
+	if setting.Actions.Enabled {
+		c.collectActions(ch)
+	}
 	ch <- prometheus.MustNewConstMetric(
 		c.BuildInfo,
 		prometheus.GaugeValue,
@@ -393,3 +455,60 @@ func (c Collector) Collect(ch chan<- prometheus.Metric) {
 		float64(stats.Counter.Webhook),
 	)
 }
+
+func (c Collector) collectActions(ch chan<- prometheus.Metric) {
+	stats, err := actions_model.GetMetrics(db.DefaultContext)
+	if err != nil {
+		log.Error("Unable to get Actions metrics: %v", err)
+		return
+	}
+	ch <- prometheus.MustNewConstHistogram(
+		c.ActionsJobDuration,
+		stats.JobDuration.Count,
+		stats.JobDuration.Sum,
+		stats.JobDuration.Buckets,
+	)
+	ch <- prometheus.MustNewConstHistogram(
+		c.ActionsJobWaitTime,
+		stats.JobWaitTime.Count,
+		stats.JobWaitTime.Sum,
+		stats.JobWaitTime.Buckets,
+	)
+	for labels, count := range stats.QueuedJobs {
+		ch <- prometheus.MustNewConstMetric(
+			c.ActionsJobsQueued,
+			prometheus.GaugeValue,
+			float64(count),
+			labels,
+		)
+	}
+	var queueAge float64
+	if stats.OldestQueuedAt > 0 {
+		queueAge = max(time.Since(stats.OldestQueuedAt.AsTime()).Seconds(), 0)
+	}
+	ch <- prometheus.MustNewConstMetric(
+		c.ActionsQueueAge,
+		prometheus.GaugeValue,
+		queueAge,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.ActionsRunnersOnline,
+		prometheus.GaugeValue,
+		float64(stats.RunnersOnline),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.ActionsRunnersBusy,
+		prometheus.GaugeValue,
+		float64(stats.RunnersBusy),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.ActionsRunnersIdle,
+		prometheus.GaugeValue,
+		float64(stats.RunnersIdle),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.ActionsTaskFailures,
+		prometheus.CounterValue,
+		float64(stats.TaskFailures),
+	)
+}
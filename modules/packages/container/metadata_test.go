@@ -64,3 +64,13 @@ func TestParseImageConfig(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, &Metadata{Platform: "unknown/unknown"}, metadata)
 }
+
+func TestManifestArtifactType(t *testing.T) {
+	manifest := &oci.Manifest{
+		Config: oci.Descriptor{MediaType: oci.MediaTypeEmptyJSON},
+	}
+	assert.Equal(t, oci.MediaTypeEmptyJSON, ManifestArtifactType(manifest))
+
+	manifest.ArtifactType = "application/spdx+json"
+	assert.Equal(t, "application/spdx+json", ManifestArtifactType(manifest))
+}
@@ -0,0 +1,228 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package hex
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"sort"
+)
+
+// The Hex API uses the Erlang external term format as its default body encoding.
+// Only maps, binaries, booleans, integers and lists are supported.
+// https://www.erlang.org/doc/apps/erts/erl_ext_dist.html
+
+// ContentTypeErlang is the content type of bodies in the external term format
+const ContentTypeErlang = "application/vnd.hex+erlang"
+
+const (
+	etfVersion        = 131
+	etfSmallInteger   = 97
+	etfInteger        = 98
+	etfAtom           = 100
+	etfNil            = 106
+	etfList           = 108
+	etfBinary         = 109
+	etfMap            = 116
+	etfAtomUTF8       = 118
+	etfSmallAtomUTF8  = 119
+	maxTermCollection = 1 << 16
+)
+
+var errInvalidExternalTerm = errors.New("invalid external term")
+
+// EncodeExternalTerm encodes a value in the external term format.
+// Strings are encoded as binaries and nil as the atom nil.
+func EncodeExternalTerm(v any) ([]byte, error) {
+	return appendExternalTerm([]byte{etfVersion}, v)
+}
+
+func appendExternalTerm(b []byte, v any) ([]byte, error) {
+	switch t := v.(type) {
+	case nil:
+		return appendAtom(b, "nil"), nil
+	case bool:
+		if t {
+			return appendAtom(b, "true"), nil
+		}
+		return appendAtom(b, "false"), nil
+	case string:
+		b = append(b, etfBinary)
+		b = binary.BigEndian.AppendUint32(b, uint32(len(t)))
+		return append(b, t...), nil
+	case int:
+		if t >= 0 && t <= math.MaxUint8 {
+			return append(b, etfSmallInteger, byte(t)), nil
+		}
+		if t < math.MinInt32 || t > math.MaxInt32 {
+			return nil, errInvalidExternalTerm
+		}
+		b = append(b, etfInteger)
+		return binary.BigEndian.AppendUint32(b, uint32(int32(t))), nil
+	case []any:
+		if len(t) == 0 {
+			return append(b, etfNil), nil
+		}
+		b = append(b, etfList)
+		b = binary.BigEndian.AppendUint32(b, uint32(len(t)))
+		for _, e := range t {
+			var err error
+			if b, err = appendExternalTerm(b, e); err != nil {
+				return nil, err
+			}
+		}
+		return append(b, etfNil), nil
+	case map[string]any:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		b = append(b, etfMap)
+		b = binary.BigEndian.AppendUint32(b, uint32(len(t)))
+		for _, k := range keys {
+			var err error
+			if b, err = appendExternalTerm(b, k); err != nil {
+				return nil, err
+			}
+			if b, err = appendExternalTerm(b, t[k]); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	}
+	return nil, errInvalidExternalTerm
+}
+
+func appendAtom(b []byte, atom string) []byte {
+	b = append(b, etfSmallAtomUTF8, byte(len(atom)))
+	return append(b, atom...)
+}
+
+// DecodeExternalTerm decodes a value in the external term format.
+// Binaries are decoded as strings, map keys are converted to strings.
+func DecodeExternalTerm(b []byte) (any, error) {
+	if len(b) == 0 || b[0] != etfVersion {
+		return nil, errInvalidExternalTerm
+	}
+	v, rest, err := decodeExternalTerm(b[1:])
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, errInvalidExternalTerm
+	}
+	return v, nil
+}
+
+func decodeExternalTerm(b []byte) (any, []byte, error) {
+	if len(b) == 0 {
+		return nil, nil, errInvalidExternalTerm
+	}
+
+	tag, b := b[0], b[1:]
+	switch tag {
+	case etfSmallInteger:
+		if len(b) < 1 {
+			return nil, nil, errInvalidExternalTerm
+		}
+		return int(b[0]), b[1:], nil
+	case etfInteger:
+		if len(b) < 4 {
+			return nil, nil, errInvalidExternalTerm
+		}
+		return int(int32(binary.BigEndian.Uint32(b))), b[4:], nil
+	case etfAtom, etfAtomUTF8:
+		if len(b) < 2 {
+			return nil, nil, errInvalidExternalTerm
+		}
+		n := int(binary.BigEndian.Uint16(b))
+		return decodeAtom(b[2:], n)
+	case etfSmallAtomUTF8:
+		if len(b) < 1 {
+			return nil, nil, errInvalidExternalTerm
+		}
+		return decodeAtom(b[1:], int(b[0]))
+	case etfBinary:
+		if len(b) < 4 {
+			return nil, nil, errInvalidExternalTerm
+		}
+		n := binary.BigEndian.Uint32(b)
+		b = b[4:]
+		if uint64(len(b)) < uint64(n) {
+			return nil, nil, errInvalidExternalTerm
+		}
+		return string(b[:n]), b[n:], nil
+	case etfNil:
+		return []any{}, b, nil
+	case etfList:
+		if len(b) < 4 {
+			return nil, nil, errInvalidExternalTerm
+		}
+		n := binary.BigEndian.Uint32(b)
+		if n > maxTermCollection {
+			return nil, nil, errInvalidExternalTerm
+		}
+		b = b[4:]
+		l := make([]any, 0, n)
+		for range n {
+			var e any
+			var err error
+			if e, b, err = decodeExternalTerm(b); err != nil {
+				return nil, nil, err
+			}
+			l = append(l, e)
+		}
+		// proper lists end with the empty list
+		if len(b) == 0 || b[0] != etfNil {
+			return nil, nil, errInvalidExternalTerm
+		}
+		return l, b[1:], nil
+	case etfMap:
+		if len(b) < 4 {
+			return nil, nil, errInvalidExternalTerm
+		}
+		n := binary.BigEndian.Uint32(b)
+		if n > maxTermCollection {
+			return nil, nil, errInvalidExternalTerm
+		}
+		b = b[4:]
+		m := make(map[string]any, n)
+		for range n {
+			var k, v any
+			var err error
+			if k, b, err = decodeExternalTerm(b); err != nil {
+				return nil, nil, err
+			}
+			if v, b, err = decodeExternalTerm(b); err != nil {
+				return nil, nil, err
+			}
+			key, ok := k.(string)
+			if !ok {
+				return nil, nil, errInvalidExternalTerm
+			}
+			m[key] = v
+		}
+		return m, b, nil
+	}
+	return nil, nil, errInvalidExternalTerm
+}
+
+func decodeAtom(b []byte, n int) (any, []byte, error) {
+	if len(b) < n {
+		return nil, nil, errInvalidExternalTerm
+	}
+	switch atom := string(b[:n]); atom {
+	case "true":
+		return true, b[n:], nil
+	case "false":
+		return false, b[n:], nil
+	case "nil":
+		return nil, b[n:], nil
+	default:
+		return atom, b[n:], nil
+	}
+}
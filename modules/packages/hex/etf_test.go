@@ -0,0 +1,64 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package hex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExternalTerm(t *testing.T) {
+	t.Run("Encode", func(t *testing.T) {
+		b, err := EncodeExternalTerm(map[string]any{"status": 404, "message": "ok"})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{
+			131, 116, 0, 0, 0, 2,
+			109, 0, 0, 0, 7, 'm', 'e', 's', 's', 'a', 'g', 'e',
+			109, 0, 0, 0, 2, 'o', 'k',
+			109, 0, 0, 0, 6, 's', 't', 'a', 't', 'u', 's',
+			98, 0, 0, 1, 148,
+		}, b)
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		v := map[string]any{
+			"reason":   "security",
+			"message":  "",
+			"flag":     true,
+			"nothing":  nil,
+			"list":     []any{"a", 1, -5},
+			"empty":    []any{},
+			"nested":   map[string]any{"key": false},
+			"negative": -70000,
+		}
+		b, err := EncodeExternalTerm(v)
+		assert.NoError(t, err)
+
+		decoded, err := DecodeExternalTerm(b)
+		assert.NoError(t, err)
+		assert.Equal(t, v, decoded)
+	})
+
+	t.Run("Atoms", func(t *testing.T) {
+		// #{reason => security} with atoms encoded as ATOM_EXT
+		v, err := DecodeExternalTerm([]byte{131, 116, 0, 0, 0, 1, 100, 0, 6, 'r', 'e', 'a', 's', 'o', 'n', 100, 0, 8, 's', 'e', 'c', 'u', 'r', 'i', 't', 'y'})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"reason": "security"}, v)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, b := range [][]byte{
+			nil,
+			{130, 106},
+			{131, 109, 0, 0, 0, 5, 'a'},
+			{131, 108, 0, 0, 0, 1, 97, 1},
+			{131, 116, 0, 0, 0, 1, 97, 1, 97, 1},
+			{131, 106, 106},
+		} {
+			_, err := DecodeExternalTerm(b)
+			assert.Error(t, err, b)
+		}
+	})
+}
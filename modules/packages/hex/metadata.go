@@ -0,0 +1,288 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package hex
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/validation"
+)
+
+var (
+	// ErrMissingFile indicates a missing file in the package tarball
+	ErrMissingFile = util.NewInvalidArgumentErrorf("package tarball is missing a file")
+	// ErrInvalidTarballVersion indicates an unsupported tarball format
+	ErrInvalidTarballVersion = util.NewInvalidArgumentErrorf("package tarball version is not supported")
+	// ErrInvalidChecksum indicates a checksum which doesn't match the content of the tarball
+	ErrInvalidChecksum = util.NewInvalidArgumentErrorf("package tarball checksum is invalid")
+	// ErrInvalidMetadata indicates an unparsable metadata.config file
+	ErrInvalidMetadata = util.NewInvalidArgumentErrorf("package metadata is invalid")
+	// ErrInvalidName indicates an invalid package name
+	ErrInvalidName = util.NewInvalidArgumentErrorf("package name is invalid")
+	// ErrInvalidVersion indicates an invalid package version
+	ErrInvalidVersion = util.NewInvalidArgumentErrorf("package version is invalid")
+	// ErrInvalidRetirementReason indicates an unknown reason to retire a release
+	ErrInvalidRetirementReason = util.NewInvalidArgumentErrorf("retirement reason is invalid")
+)
+
+const (
+	PropertyInnerChecksum   = "hex.checksum.inner"
+	PropertyRetiredReason   = "hex.retired.reason"
+	PropertyRetiredMessage  = "hex.retired.message"
+	SettingKeyPrivate       = "hex.key.private"
+	SettingKeyPublic        = "hex.key.public"
+	RepositoryPackage       = "_hex"
+	RepositoryVersion       = "_repository"
+	supportedTarballVersion = "3"
+
+	maxMetadataSize = 1 * 1024 * 1024
+	maxReadmeSize   = 1 * 1024 * 1024
+)
+
+var (
+	namePattern = regexp.MustCompile(`\A[a-z][a-z0-9_]{0,127}\z`)
+	// Hex requires strict semantic versions with major, minor and patch numbers
+	versionPattern = regexp.MustCompile(`\A(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?\z`)
+)
+
+// Package represents a Hex package
+type Package struct {
+	Name          string
+	Version       string
+	InnerChecksum string
+	Metadata      *Metadata
+}
+
+// Metadata represents the metadata of a Hex package
+type Metadata struct {
+	App          string            `json:"app,omitempty"`
+	Description  string            `json:"description,omitempty"`
+	Licenses     []string          `json:"licenses,omitempty"`
+	Links        map[string]string `json:"links,omitempty"`
+	BuildTools   []string          `json:"build_tools,omitempty"`
+	Elixir       string            `json:"elixir,omitempty"`
+	Requirements []*Requirement    `json:"requirements,omitempty"`
+	Readme       string            `json:"readme,omitempty"`
+}
+
+// Requirement represents a dependency of a Hex package
+type Requirement struct {
+	Name        string `json:"name"`
+	App         string `json:"app,omitempty"`
+	Requirement string `json:"requirement"`
+	Optional    bool   `json:"optional,omitempty"`
+	Repository  string `json:"repository,omitempty"`
+}
+
+// IsValidName checks if the name is a valid Hex package name
+func IsValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// IsValidVersion checks if the version is a valid Hex version
+func IsValidVersion(v string) bool {
+	return versionPattern.MatchString(v)
+}
+
+// TarballFilename returns the name of the tarball of a package version
+func TarballFilename(name, version string) string {
+	return name + "-" + version + ".tar"
+}
+
+// ParsePackage parses the outer tarball of a Hex package
+// https://github.com/hexpm/specifications/blob/main/package_tarball.md
+func ParsePackage(r io.Reader) (*Package, error) {
+	var tarballVersion, checksum, metadata, contents []byte
+
+	tr := tar.NewReader(r)
+	for {
+		hd, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if hd.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		var target *[]byte
+		switch hd.Name {
+		case "VERSION":
+			target = &tarballVersion
+		case "CHECKSUM":
+			target = &checksum
+		case "metadata.config":
+			target = &metadata
+		case "contents.tar.gz":
+			target = &contents
+		default:
+			continue
+		}
+
+		if hd.Name != "contents.tar.gz" && hd.Size > maxMetadataSize {
+			return nil, ErrInvalidMetadata
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		*target = data
+	}
+
+	if tarballVersion == nil || metadata == nil || contents == nil {
+		return nil, ErrMissingFile
+	}
+	if strings.TrimSpace(string(tarballVersion)) != supportedTarballVersion {
+		return nil, ErrInvalidTarballVersion
+	}
+
+	h := sha256.New()
+	h.Write(tarballVersion)
+	h.Write(metadata)
+	h.Write(contents)
+	innerChecksum := hex.EncodeToString(h.Sum(nil))
+
+	if checksum != nil && !strings.EqualFold(strings.TrimSpace(string(checksum)), innerChecksum) {
+		return nil, ErrInvalidChecksum
+	}
+
+	p, err := parseMetadata(metadata)
+	if err != nil {
+		return nil, err
+	}
+	p.InnerChecksum = innerChecksum
+
+	p.Metadata.Readme, err = extractReadme(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func parseMetadata(data []byte) (*Package, error) {
+	terms, err := parseTerms(string(data))
+	if err != nil {
+		return nil, ErrInvalidMetadata
+	}
+
+	values := termToMap(terms)
+
+	p := &Package{
+		Name:    termToString(values["name"]),
+		Version: termToString(values["version"]),
+		Metadata: &Metadata{
+			App:          termToString(values["app"]),
+			Description:  termToString(values["description"]),
+			Licenses:     termToStrings(values["licenses"]),
+			BuildTools:   termToStrings(values["build_tools"]),
+			Elixir:       termToString(values["elixir"]),
+			Requirements: parseRequirements(values["requirements"]),
+		},
+	}
+
+	if !IsValidName(p.Name) {
+		return nil, ErrInvalidName
+	}
+	if !IsValidVersion(p.Version) {
+		return nil, ErrInvalidVersion
+	}
+
+	if links := termToMap(values["links"]); len(links) > 0 {
+		p.Metadata.Links = make(map[string]string, len(links))
+		for name, link := range links {
+			if url := termToString(link); validation.IsValidURL(url) {
+				p.Metadata.Links[name] = url
+			}
+		}
+	}
+
+	return p, nil
+}
+
+// parseRequirements supports the property list format ({Name, Properties}) and the legacy list of maps with a name key
+func parseRequirements(t any) []*Requirement {
+	var requirements []*Requirement
+
+	add := func(name string, props map[string]any) {
+		if name == "" || props == nil {
+			return
+		}
+		optional, _ := props["optional"].(bool)
+		requirements = append(requirements, &Requirement{
+			Name:        name,
+			App:         termToString(props["app"]),
+			Requirement: termToString(props["requirement"]),
+			Optional:    optional,
+			Repository:  termToString(props["repository"]),
+		})
+	}
+
+	switch v := t.(type) {
+	case map[string]any:
+		for name, props := range v {
+			add(name, termToMap(props))
+		}
+	case []any:
+		for _, e := range v {
+			if tuple, ok := e.(Tuple); ok && len(tuple) == 2 {
+				add(termToString(tuple[0]), termToMap(tuple[1]))
+			} else if props := termToMap(e); props != nil {
+				add(termToString(props["name"]), props)
+			}
+		}
+	}
+
+	sort.Slice(requirements, func(i, j int) bool {
+		return requirements[i].Name < requirements[j].Name
+	})
+
+	return requirements
+}
+
+// extractReadme returns the content of the readme in the root of the gzipped contents
+func extractReadme(contents []byte) (string, error) {
+	gzr, err := gzip.NewReader(bytes.NewReader(contents))
+	if err != nil {
+		return "", err
+	}
+	defer gzr.Close()
+
+	tr := tar.NewReader(gzr)
+	for {
+		hd, err := tr.Next()
+		if err == io.EOF {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if hd.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		if name := path.Clean(strings.TrimPrefix(hd.Name, "./")); strings.EqualFold(name, "README.md") {
+			data, err := io.ReadAll(io.LimitReader(tr, maxReadmeSize))
+			if err != nil {
+				return "", err
+			}
+			return string(data), nil
+		}
+	}
+}
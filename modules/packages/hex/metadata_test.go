@@ -0,0 +1,187 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package hex
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	packageName        = "gitea"
+	packageVersion     = "1.0.1"
+	packageDescription = "Package Description"
+	packageReadme      = "# Gitea"
+)
+
+var packageMetadata = `{<<"app">>,<<"gitea">>}.
+{<<"build_tools">>,[<<"mix">>]}.
+{<<"description">>,<<"Package Description">>}.
+{<<"elixir">>,<<"~> 1.15">>}.
+{<<"files">>,[<<"lib">>,<<"lib/gitea.ex">>,<<"mix.exs">>,<<"README.md">>]}.
+{<<"licenses">>,[<<"MIT">>]}.
+{<<"links">>,[{<<"GitHub">>,<<"https://github.com/go-gitea/gitea">>}]}.
+{<<"name">>,<<"gitea">>}.
+{<<"requirements">>,
+ [{<<"jason">>,
+   [{<<"app">>,<<"jason">>},
+    {<<"optional">>,true},
+    {<<"repository">>,<<"hexpm">>},
+    {<<"requirement">>,<<"~> 1.4">>}]},
+  {<<"decimal">>,
+   [{<<"app">>,<<"decimal">>},
+    {<<"optional">>,false},
+    {<<"repository">>,<<"hexpm">>},
+    {<<"requirement">>,<<"~> 2.0">>}]}]}.
+{<<"version">>,<<"1.0.1">>}.
+`
+
+func createContents(files map[string]string) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	for name, content := range files {
+		tw.WriteHeader(&tar.Header{
+			Name: name,
+			Mode: 0o600,
+			Size: int64(len(content)),
+		})
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	zw.Close()
+	return buf.Bytes()
+}
+
+func createPackage(version, metadata string, contents []byte, checksum string) []byte {
+	if checksum == "" {
+		h := sha256.New()
+		h.Write([]byte(version))
+		h.Write([]byte(metadata))
+		h.Write(contents)
+		checksum = strings.ToUpper(hex.EncodeToString(h.Sum(nil)))
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, f := range []struct {
+		Name    string
+		Content []byte
+	}{
+		{"VERSION", []byte(version)},
+		{"CHECKSUM", []byte(checksum)},
+		{"metadata.config", []byte(metadata)},
+		{"contents.tar.gz", contents},
+	} {
+		tw.WriteHeader(&tar.Header{
+			Name: f.Name,
+			Mode: 0o600,
+			Size: int64(len(f.Content)),
+		})
+		tw.Write(f.Content)
+	}
+	tw.Close()
+	return buf.Bytes()
+}
+
+func TestParsePackage(t *testing.T) {
+	contents := createContents(map[string]string{
+		"lib/gitea.ex": "defmodule Gitea do\nend",
+		"README.md":    packageReadme,
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		p, err := ParsePackage(bytes.NewReader(createPackage("3", packageMetadata, contents, "")))
+		assert.NoError(t, err)
+		assert.NotNil(t, p)
+
+		assert.Equal(t, packageName, p.Name)
+		assert.Equal(t, packageVersion, p.Version)
+		assert.Len(t, p.InnerChecksum, 64)
+		assert.Equal(t, packageName, p.Metadata.App)
+		assert.Equal(t, packageDescription, p.Metadata.Description)
+		assert.Equal(t, packageReadme, p.Metadata.Readme)
+		assert.Equal(t, []string{"MIT"}, p.Metadata.Licenses)
+		assert.Equal(t, []string{"mix"}, p.Metadata.BuildTools)
+		assert.Equal(t, "~> 1.15", p.Metadata.Elixir)
+		assert.Equal(t, map[string]string{"GitHub": "https://github.com/go-gitea/gitea"}, p.Metadata.Links)
+		assert.Equal(t, []*Requirement{
+			{Name: "decimal", App: "decimal", Requirement: "~> 2.0", Repository: "hexpm"},
+			{Name: "jason", App: "jason", Requirement: "~> 1.4", Optional: true, Repository: "hexpm"},
+		}, p.Metadata.Requirements)
+	})
+
+	t.Run("MissingFile", func(t *testing.T) {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		tw.WriteHeader(&tar.Header{Name: "VERSION", Mode: 0o600, Size: 1})
+		tw.Write([]byte("3"))
+		tw.Close()
+
+		p, err := ParsePackage(&buf)
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrMissingFile)
+	})
+
+	t.Run("InvalidTarballVersion", func(t *testing.T) {
+		p, err := ParsePackage(bytes.NewReader(createPackage("2", packageMetadata, contents, "")))
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrInvalidTarballVersion)
+	})
+
+	t.Run("InvalidChecksum", func(t *testing.T) {
+		p, err := ParsePackage(bytes.NewReader(createPackage("3", packageMetadata, contents, strings.Repeat("A", 64))))
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrInvalidChecksum)
+	})
+
+	t.Run("InvalidMetadata", func(t *testing.T) {
+		p, err := ParsePackage(bytes.NewReader(createPackage("3", `{<<"name">>,<<"gitea">>`, contents, "")))
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrInvalidMetadata)
+	})
+
+	t.Run("InvalidName", func(t *testing.T) {
+		p, err := ParsePackage(bytes.NewReader(createPackage("3", strings.Replace(packageMetadata, `{<<"name">>,<<"gitea">>}`, `{<<"name">>,<<"Gitea">>}`, 1), contents, "")))
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrInvalidName)
+	})
+
+	t.Run("InvalidVersion", func(t *testing.T) {
+		p, err := ParsePackage(bytes.NewReader(createPackage("3", strings.Replace(packageMetadata, `<<"1.0.1">>`, `<<"1.0">>`, 1), contents, "")))
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrInvalidVersion)
+	})
+}
+
+func TestParseTerms(t *testing.T) {
+	terms, err := parseTerms(`% comment
+{<<"name">>, <<"caf", "é"/utf8>>}.
+{atom, ['quoted atom', "string", -12, #{<<"key">> => true}]}.
+`)
+	assert.NoError(t, err)
+	assert.Equal(t, []any{
+		Tuple{"name", "café"},
+		Tuple{Atom("atom"), []any{Atom("quoted atom"), "string", int64(-12), map[string]any{"key": true}}},
+	}, terms)
+
+	for _, s := range []string{`{<<"name">>}`, `{<<"name">>,}.`, `<<"name>>.`, `#{a}.`} {
+		_, err := parseTerms(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestParseRequirements(t *testing.T) {
+	// legacy format with a list of maps
+	terms, err := parseTerms(`[#{<<"name">> => <<"plug">>, <<"app">> => <<"plug">>, <<"requirement">> => <<"~> 1.0">>, <<"optional">> => false}].`)
+	assert.NoError(t, err)
+	assert.Equal(t, []*Requirement{{Name: "plug", App: "plug", Requirement: "~> 1.0"}}, parseRequirements(terms[0]))
+}
@@ -0,0 +1,207 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package hex
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The repository resources are protobuf messages wrapped in a signed envelope and gzipped.
+// https://github.com/hexpm/specifications/blob/main/registry-v2.md
+
+// RetirementReason is the reason why a release was retired
+type RetirementReason int
+
+const (
+	RetiredOther RetirementReason = iota
+	RetiredInvalid
+	RetiredSecurity
+	RetiredDeprecated
+	RetiredRenamed
+)
+
+var retirementReasons = []string{"other", "invalid", "security", "deprecated", "renamed"}
+
+// ParseRetirementReason parses the name of a retirement reason
+func ParseRetirementReason(s string) (RetirementReason, error) {
+	for i, reason := range retirementReasons {
+		if reason == s {
+			return RetirementReason(i), nil
+		}
+	}
+	return RetiredOther, ErrInvalidRetirementReason
+}
+
+func (r RetirementReason) String() string {
+	return retirementReasons[r]
+}
+
+// NamesEntry is a package in the /names resource
+type NamesEntry struct {
+	Name      string
+	UpdatedAt time.Time
+}
+
+// VersionsEntry is a package in the /versions resource
+type VersionsEntry struct {
+	Name     string
+	Versions []string
+	Retired  []int
+}
+
+// Release is a version in the /packages/<name> resource
+type Release struct {
+	Version       string
+	InnerChecksum []byte
+	OuterChecksum []byte
+	Requirements  []*Requirement
+	Retired       *Retirement
+}
+
+// Retirement describes why a release was retired
+type Retirement struct {
+	Reason  RetirementReason
+	Message string
+}
+
+// EncodeNames encodes the /names resource
+func EncodeNames(repository string, entries []*NamesEntry) []byte {
+	var b []byte
+	for _, e := range entries {
+		var p []byte
+		p = protowire.AppendTag(p, 1, protowire.BytesType)
+		p = protowire.AppendString(p, e.Name)
+
+		var ts []byte
+		ts = protowire.AppendTag(ts, 1, protowire.VarintType)
+		ts = protowire.AppendVarint(ts, uint64(e.UpdatedAt.Unix()))
+		if nanos := e.UpdatedAt.Nanosecond(); nanos != 0 {
+			ts = protowire.AppendTag(ts, 2, protowire.VarintType)
+			ts = protowire.AppendVarint(ts, uint64(nanos))
+		}
+		p = protowire.AppendTag(p, 2, protowire.BytesType)
+		p = protowire.AppendBytes(p, ts)
+
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendBytes(b, p)
+	}
+	b = protowire.AppendTag(b, 2, protowire.BytesType)
+	return protowire.AppendString(b, repository)
+}
+
+// EncodeVersions encodes the /versions resource
+func EncodeVersions(repository string, entries []*VersionsEntry) []byte {
+	var b []byte
+	for _, e := range entries {
+		var p []byte
+		p = protowire.AppendTag(p, 1, protowire.BytesType)
+		p = protowire.AppendString(p, e.Name)
+		for _, v := range e.Versions {
+			p = protowire.AppendTag(p, 2, protowire.BytesType)
+			p = protowire.AppendString(p, v)
+		}
+		if len(e.Retired) > 0 {
+			var packed []byte
+			for _, i := range e.Retired {
+				packed = protowire.AppendVarint(packed, uint64(i))
+			}
+			p = protowire.AppendTag(p, 3, protowire.BytesType)
+			p = protowire.AppendBytes(p, packed)
+		}
+
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendBytes(b, p)
+	}
+	b = protowire.AppendTag(b, 2, protowire.BytesType)
+	return protowire.AppendString(b, repository)
+}
+
+// EncodePackage encodes the /packages/<name> resource
+func EncodePackage(repository, name string, releases []*Release) []byte {
+	var b []byte
+	for _, r := range releases {
+		var p []byte
+		p = protowire.AppendTag(p, 1, protowire.BytesType)
+		p = protowire.AppendString(p, r.Version)
+		p = protowire.AppendTag(p, 2, protowire.BytesType)
+		p = protowire.AppendBytes(p, r.InnerChecksum)
+		for _, req := range r.Requirements {
+			var d []byte
+			d = protowire.AppendTag(d, 1, protowire.BytesType)
+			d = protowire.AppendString(d, req.Name)
+			d = protowire.AppendTag(d, 2, protowire.BytesType)
+			d = protowire.AppendString(d, req.Requirement)
+			if req.Optional {
+				d = protowire.AppendTag(d, 3, protowire.VarintType)
+				d = protowire.AppendVarint(d, 1)
+			}
+			if req.App != "" && req.App != req.Name {
+				d = protowire.AppendTag(d, 4, protowire.BytesType)
+				d = protowire.AppendString(d, req.App)
+			}
+			if req.Repository != "" {
+				d = protowire.AppendTag(d, 5, protowire.BytesType)
+				d = protowire.AppendString(d, req.Repository)
+			}
+			p = protowire.AppendTag(p, 3, protowire.BytesType)
+			p = protowire.AppendBytes(p, d)
+		}
+		if r.Retired != nil {
+			var rs []byte
+			rs = protowire.AppendTag(rs, 1, protowire.VarintType)
+			rs = protowire.AppendVarint(rs, uint64(r.Retired.Reason))
+			if r.Retired.Message != "" {
+				rs = protowire.AppendTag(rs, 2, protowire.BytesType)
+				rs = protowire.AppendString(rs, r.Retired.Message)
+			}
+			p = protowire.AppendTag(p, 4, protowire.BytesType)
+			p = protowire.AppendBytes(p, rs)
+		}
+		if len(r.OuterChecksum) > 0 {
+			p = protowire.AppendTag(p, 5, protowire.BytesType)
+			p = protowire.AppendBytes(p, r.OuterChecksum)
+		}
+
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendBytes(b, p)
+	}
+	b = protowire.AppendTag(b, 2, protowire.BytesType)
+	b = protowire.AppendString(b, name)
+	b = protowire.AppendTag(b, 3, protowire.BytesType)
+	return protowire.AppendString(b, repository)
+}
+
+// SignAndCompress wraps the payload in a signed envelope and gzips the result.
+// The signature is a RSA PKCS #1 v1.5 signature of the SHA-512 hash of the payload.
+func SignAndCompress(key *rsa.PrivateKey, payload []byte) ([]byte, error) {
+	hash := sha512.Sum512(payload)
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA512, hash[:])
+	if err != nil {
+		return nil, err
+	}
+
+	var signed []byte
+	signed = protowire.AppendTag(signed, 1, protowire.BytesType)
+	signed = protowire.AppendBytes(signed, payload)
+	signed = protowire.AppendTag(signed, 2, protowire.BytesType)
+	signed = protowire.AppendBytes(signed, signature)
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(signed); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package hex
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// decodeFields returns the values of all fields of a message grouped by field number
+func decodeFields(t *testing.T, b []byte) map[protowire.Number][]any {
+	fields := make(map[protowire.Number][]any)
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		require.GreaterOrEqual(t, n, 0)
+		b = b[n:]
+
+		switch typ {
+		case protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			require.GreaterOrEqual(t, n, 0)
+			fields[num] = append(fields[num], v)
+			b = b[n:]
+		case protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
+			require.GreaterOrEqual(t, n, 0)
+			fields[num] = append(fields[num], v)
+			b = b[n:]
+		default:
+			t.Fatalf("unexpected wire type %d", typ)
+		}
+	}
+	return fields
+}
+
+func TestParseRetirementReason(t *testing.T) {
+	reason, err := ParseRetirementReason("security")
+	assert.NoError(t, err)
+	assert.Equal(t, RetiredSecurity, reason)
+	assert.Equal(t, "security", reason.String())
+
+	_, err = ParseRetirementReason("unknown")
+	assert.ErrorIs(t, err, ErrInvalidRetirementReason)
+}
+
+func TestEncodeNames(t *testing.T) {
+	fields := decodeFields(t, EncodeNames("gitea", []*NamesEntry{{Name: "pkg", UpdatedAt: time.Unix(1700000000, 0)}}))
+
+	assert.Equal(t, []any{[]byte("gitea")}, fields[2])
+	require.Len(t, fields[1], 1)
+
+	pkg := decodeFields(t, fields[1][0].([]byte))
+	assert.Equal(t, []any{[]byte("pkg")}, pkg[1])
+	ts := decodeFields(t, pkg[2][0].([]byte))
+	assert.Equal(t, []any{uint64(1700000000)}, ts[1])
+}
+
+func TestEncodeVersions(t *testing.T) {
+	fields := decodeFields(t, EncodeVersions("gitea", []*VersionsEntry{{Name: "pkg", Versions: []string{"1.0.0", "1.1.0"}, Retired: []int{1}}}))
+
+	pkg := decodeFields(t, fields[1][0].([]byte))
+	assert.Equal(t, []any{[]byte("pkg")}, pkg[1])
+	assert.Equal(t, []any{[]byte("1.0.0"), []byte("1.1.0")}, pkg[2])
+	assert.Equal(t, []any{[]byte{1}}, pkg[3])
+}
+
+func TestEncodePackage(t *testing.T) {
+	fields := decodeFields(t, EncodePackage("gitea", "pkg", []*Release{
+		{
+			Version:       "1.0.0",
+			InnerChecksum: []byte{1, 2},
+			OuterChecksum: []byte{3, 4},
+			Requirements:  []*Requirement{{Name: "dep", App: "dep_app", Requirement: "~> 1.0", Optional: true}},
+			Retired:       &Retirement{Reason: RetiredDeprecated, Message: "use other"},
+		},
+	}))
+
+	assert.Equal(t, []any{[]byte("pkg")}, fields[2])
+	assert.Equal(t, []any{[]byte("gitea")}, fields[3])
+
+	release := decodeFields(t, fields[1][0].([]byte))
+	assert.Equal(t, []any{[]byte("1.0.0")}, release[1])
+	assert.Equal(t, []any{[]byte{1, 2}}, release[2])
+	assert.Equal(t, []any{[]byte{3, 4}}, release[5])
+
+	dep := decodeFields(t, release[3][0].([]byte))
+	assert.Equal(t, []any{[]byte("dep")}, dep[1])
+	assert.Equal(t, []any{[]byte("~> 1.0")}, dep[2])
+	assert.Equal(t, []any{uint64(1)}, dep[3])
+	assert.Equal(t, []any{[]byte("dep_app")}, dep[4])
+
+	retired := decodeFields(t, release[4][0].([]byte))
+	assert.Equal(t, []any{uint64(RetiredDeprecated)}, retired[1])
+	assert.Equal(t, []any{[]byte("use other")}, retired[2])
+}
+
+func TestSignAndCompress(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	payload := EncodeNames("gitea", nil)
+
+	data, err := SignAndCompress(key, payload)
+	require.NoError(t, err)
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	signed, err := io.ReadAll(zr)
+	require.NoError(t, err)
+
+	fields := decodeFields(t, signed)
+	assert.Equal(t, []any{payload}, fields[1])
+
+	hash := sha512.Sum512(payload)
+	assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA512, hash[:], fields[2][0].([]byte)))
+}
@@ -0,0 +1,294 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package hex
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// The metadata.config file of a Hex package contains Erlang terms as written by file:consult/1.
+// Only the subset of the term syntax used by the Hex tooling is supported.
+
+// Atom represents an Erlang atom
+type Atom string
+
+// Tuple represents an Erlang tuple
+type Tuple []any
+
+var errInvalidTerm = errors.New("invalid Erlang term")
+
+type termParser struct {
+	s   string
+	pos int
+}
+
+// parseTerms parses a sequence of dot terminated terms
+func parseTerms(s string) ([]any, error) {
+	p := &termParser{s: s}
+
+	var terms []any
+	for {
+		p.skipWhitespace()
+		if p.pos >= len(p.s) {
+			return terms, nil
+		}
+
+		term, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		p.skipWhitespace()
+		if !p.consume(".") {
+			return nil, errInvalidTerm
+		}
+		terms = append(terms, term)
+	}
+}
+
+func (p *termParser) skipWhitespace() {
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		if c == '%' {
+			for p.pos < len(p.s) && p.s[p.pos] != '\n' {
+				p.pos++
+			}
+			continue
+		}
+		if c != ' ' && c != '\t' && c != '\r' && c != '\n' {
+			return
+		}
+		p.pos++
+	}
+}
+
+func (p *termParser) consume(token string) bool {
+	p.skipWhitespace()
+	if strings.HasPrefix(p.s[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *termParser) parseTerm() (any, error) {
+	p.skipWhitespace()
+	if p.pos >= len(p.s) {
+		return nil, errInvalidTerm
+	}
+
+	switch c := p.s[p.pos]; {
+	case strings.HasPrefix(p.s[p.pos:], "<<"):
+		return p.parseBinary()
+	case c == '"':
+		return p.parseQuoted('"')
+	case c == '\'':
+		s, err := p.parseQuoted('\'')
+		return Atom(s), err
+	case c == '[':
+		p.pos++
+		return p.parseSequence("]")
+	case c == '{':
+		p.pos++
+		elems, err := p.parseSequence("}")
+		return Tuple(elems), err
+	case c == '#':
+		return p.parseMap()
+	case c == '-' || (c >= '0' && c <= '9'):
+		return p.parseInteger()
+	case c >= 'a' && c <= 'z':
+		return p.parseAtom()
+	}
+	return nil, errInvalidTerm
+}
+
+func (p *termParser) parseSequence(end string) ([]any, error) {
+	elems := make([]any, 0)
+	if p.consume(end) {
+		return elems, nil
+	}
+	for {
+		elem, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, elem)
+
+		if p.consume(end) {
+			return elems, nil
+		}
+		if !p.consume(",") {
+			return nil, errInvalidTerm
+		}
+	}
+}
+
+func (p *termParser) parseMap() (map[string]any, error) {
+	if !p.consume("#{") {
+		return nil, errInvalidTerm
+	}
+
+	m := make(map[string]any)
+	if p.consume("}") {
+		return m, nil
+	}
+	for {
+		key, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume("=>") {
+			return nil, errInvalidTerm
+		}
+		value, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		m[termToString(key)] = value
+
+		if p.consume("}") {
+			return m, nil
+		}
+		if !p.consume(",") {
+			return nil, errInvalidTerm
+		}
+	}
+}
+
+// parseBinary parses <<"text">> and <<"text"/utf8>>
+func (p *termParser) parseBinary() (string, error) {
+	p.pos += 2
+
+	var sb strings.Builder
+	for {
+		p.skipWhitespace()
+		if p.pos >= len(p.s) || p.s[p.pos] != '"' {
+			break
+		}
+		s, err := p.parseQuoted('"')
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(s)
+		p.consume("/utf8")
+		if !p.consume(",") {
+			break
+		}
+	}
+	if !p.consume(">>") {
+		return "", errInvalidTerm
+	}
+	return sb.String(), nil
+}
+
+func (p *termParser) parseQuoted(quote byte) (string, error) {
+	p.pos++
+
+	var sb strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+		switch c {
+		case quote:
+			return sb.String(), nil
+		case '\\':
+			if p.pos >= len(p.s) {
+				return "", errInvalidTerm
+			}
+			e := p.s[p.pos]
+			p.pos++
+			switch e {
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			default:
+				sb.WriteByte(e)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", errInvalidTerm
+}
+
+func (p *termParser) parseInteger() (int64, error) {
+	start := p.pos
+	if p.s[p.pos] == '-' {
+		p.pos++
+	}
+	for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+		p.pos++
+	}
+	i, err := strconv.ParseInt(p.s[start:p.pos], 10, 64)
+	if err != nil {
+		return 0, errInvalidTerm
+	}
+	return i, nil
+}
+
+func (p *termParser) parseAtom() (any, error) {
+	start := p.pos
+	for p.pos < len(p.s) {
+		c := rune(p.s[p.pos])
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != '_' && c != '@' {
+			break
+		}
+		p.pos++
+	}
+
+	switch atom := p.s[start:p.pos]; atom {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	default:
+		return Atom(atom), nil
+	}
+}
+
+func termToString(t any) string {
+	switch v := t.(type) {
+	case string:
+		return v
+	case Atom:
+		return string(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	}
+	return ""
+}
+
+func termToStrings(t any) []string {
+	l, ok := t.([]any)
+	if !ok {
+		return nil
+	}
+	s := make([]string, 0, len(l))
+	for _, e := range l {
+		s = append(s, termToString(e))
+	}
+	return s
+}
+
+// termToMap converts a property list or a map into a map
+func termToMap(t any) map[string]any {
+	switch v := t.(type) {
+	case map[string]any:
+		return v
+	case []any:
+		m := make(map[string]any, len(v))
+		for _, e := range v {
+			if tuple, ok := e.(Tuple); ok && len(tuple) == 2 {
+				m[termToString(tuple[0])] = tuple[1]
+			}
+		}
+		return m
+	}
+	return nil
+}
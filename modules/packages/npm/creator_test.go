@@ -5,7 +5,9 @@ package npm
 
 import (
 	"bytes"
+	"crypto/sha1"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"strings"
 	"testing"
@@ -299,3 +301,45 @@ func TestParsePackage(t *testing.T) {
 		assert.Equal(t, repository.URL, p.Metadata.Repository.URL)
 	})
 }
+
+func TestParseRemotePackage(t *testing.T) {
+	data := []byte("tarball")
+	sum := sha1.Sum(data)
+
+	meta := func() *PackageMetadataVersion {
+		return &PackageMetadataVersion{
+			Name:    "@scope/test-package",
+			Version: "1.0.0",
+			Dist: PackageDistribution{
+				Shasum: hex.EncodeToString(sum[:]),
+			},
+		}
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		p, err := ParseRemotePackage(meta(), data)
+		assert.NoError(t, err)
+		assert.Equal(t, "@scope/test-package", p.Name)
+		assert.Equal(t, "1.0.0", p.Version)
+		assert.Equal(t, "test-package-1.0.0.tgz", p.Filename)
+		assert.Equal(t, "@scope", p.Metadata.Scope)
+		assert.Equal(t, data, p.Data)
+	})
+
+	t.Run("Integrity", func(t *testing.T) {
+		m := meta()
+		m.Dist.Integrity = "sha1-" + base64.StdEncoding.EncodeToString(sum[:])
+		_, err := ParseRemotePackage(m, data)
+		assert.NoError(t, err)
+
+		_, err = ParseRemotePackage(m, []byte("modified"))
+		assert.ErrorIs(t, err, ErrInvalidIntegrity)
+	})
+
+	t.Run("MissingChecksum", func(t *testing.T) {
+		m := meta()
+		m.Dist.Shasum = ""
+		_, err := ParseRemotePackage(m, data)
+		assert.ErrorIs(t, err, ErrInvalidIntegrity)
+	})
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package pypi
+
+import (
+	"io"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// SimpleLink is a file link of a project page of the simple repository api
+// https://peps.python.org/pep-0503/
+type SimpleLink struct {
+	URL            string
+	Filename       string
+	HashSHA256     string
+	RequiresPython string
+}
+
+// ParseSimpleIndex parses the file links of a project page. Relative urls are resolved against the base url.
+func ParseSimpleIndex(r io.Reader, baseURL *url.URL) ([]*SimpleLink, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var links []*SimpleLink
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "a" {
+			if link := parseSimpleLink(n, baseURL); link != nil {
+				links = append(links, link)
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	return links, nil
+}
+
+func parseSimpleLink(n *html.Node, baseURL *url.URL) *SimpleLink {
+	link := &SimpleLink{}
+	for _, attr := range n.Attr {
+		switch attr.Key {
+		case "href":
+			u, err := baseURL.Parse(attr.Val)
+			if err != nil {
+				return nil
+			}
+			for param := range strings.SplitSeq(u.Fragment, "&") {
+				if hash, ok := strings.CutPrefix(param, "sha256="); ok {
+					link.HashSHA256 = strings.ToLower(hash)
+				}
+			}
+			u.Fragment = ""
+			link.URL = u.String()
+			link.Filename, _ = url.PathUnescape(u.Path[strings.LastIndex(u.Path, "/")+1:])
+		case "data-requires-python":
+			link.RequiresPython = attr.Val
+		}
+	}
+	if link.URL == "" || link.Filename == "" {
+		return nil
+	}
+	return link
+}
+
+// VersionFromFilename extracts the version from the name of a source distribution or wheel of the package
+// https://packaging.python.org/en/latest/specifications/binary-distribution-format/#file-name-convention
+func VersionFromFilename(packageName, filename string) string {
+	packageName = normalizer.Replace(strings.ToLower(packageName))
+	name := strings.ToLower(filename)
+	for _, ext := range []string{".tar.gz", ".tar.bz2", ".zip", ".whl", ".egg"} {
+		if trimmed, ok := strings.CutSuffix(name, ext); ok {
+			name = trimmed
+			break
+		}
+	}
+
+	// the distribution name may use other separators than the normalized package name
+	if len(name) <= len(packageName)+1 || normalizer.Replace(name[:len(packageName)]) != packageName || name[len(packageName)] != '-' {
+		return ""
+	}
+	version := filename[len(packageName)+1 : len(name)]
+	if strings.HasSuffix(filename, ".whl") || strings.HasSuffix(filename, ".egg") {
+		version, _, _ = strings.Cut(version, "-")
+	}
+	return version
+}
+
+var normalizer = strings.NewReplacer(".", "-", "_", "-")
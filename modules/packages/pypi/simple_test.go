@@ -0,0 +1,60 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package pypi
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSimpleIndex(t *testing.T) {
+	content := `<!DOCTYPE html>
+<html>
+	<body>
+		<h1>Links for test-package</h1>
+		<a href="https://files.example.com/packages/test_package-1.0.1-py3-none-any.whl#sha256=ABC123" data-requires-python="&gt;=3.8">test_package-1.0.1-py3-none-any.whl</a><br>
+		<a href="../../files/test-package/1.0.1/test-package-1.0.1.tar.gz#sha256=def456">test-package-1.0.1.tar.gz</a><br>
+		<a>no link</a>
+	</body>
+</html>`
+
+	baseURL, _ := url.Parse("https://example.com/pypi/simple/test-package/")
+	links, err := ParseSimpleIndex(strings.NewReader(content), baseURL)
+	require.NoError(t, err)
+	require.Len(t, links, 2)
+
+	assert.Equal(t, "https://files.example.com/packages/test_package-1.0.1-py3-none-any.whl", links[0].URL)
+	assert.Equal(t, "test_package-1.0.1-py3-none-any.whl", links[0].Filename)
+	assert.Equal(t, "abc123", links[0].HashSHA256)
+	assert.Equal(t, ">=3.8", links[0].RequiresPython)
+
+	assert.Equal(t, "https://example.com/pypi/files/test-package/1.0.1/test-package-1.0.1.tar.gz", links[1].URL)
+	assert.Equal(t, "test-package-1.0.1.tar.gz", links[1].Filename)
+	assert.Equal(t, "def456", links[1].HashSHA256)
+	assert.Empty(t, links[1].RequiresPython)
+}
+
+func TestVersionFromFilename(t *testing.T) {
+	cases := []struct {
+		Filename string
+		Version  string
+	}{
+		{"test-package-1.0.1.tar.gz", "1.0.1"},
+		{"test_package-1.0.1.tar.gz", "1.0.1"},
+		{"Test.Package-2.0rc1.zip", "2.0rc1"},
+		{"test_package-1.0.1-py3-none-any.whl", "1.0.1"},
+		{"test_package-1.0.1-1-cp312-cp312-manylinux_2_17_x86_64.whl", "1.0.1"},
+		{"test-package2-1.0.1.tar.gz", ""},
+		{"other-1.0.1.tar.gz", ""},
+		{"test-package.tar.gz", ""},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.Version, VersionFromFilename("Test_Package", c.Filename), c.Filename)
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package terraform
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/hashicorp/go-version"
+)
+
+var (
+	// ErrInvalidName indicates an invalid module or provider name
+	ErrInvalidName = util.NewInvalidArgumentErrorf("package name is invalid")
+	// ErrInvalidVersion indicates an invalid version
+	ErrInvalidVersion = util.NewInvalidArgumentErrorf("package version is invalid")
+	// ErrInvalidFilename indicates a filename which does not belong to a provider release
+	ErrInvalidFilename = util.NewInvalidArgumentErrorf("filename is invalid")
+	// ErrMissingConfiguration indicates a module archive without configuration files
+	ErrMissingConfiguration = util.NewInvalidArgumentErrorf("module archive contains no configuration files")
+	// ErrInvalidManifest indicates an invalid provider manifest
+	ErrInvalidManifest = util.NewInvalidArgumentErrorf("provider manifest is invalid")
+	// ErrInvalidSigningKey indicates an invalid public key
+	ErrInvalidSigningKey = util.NewInvalidArgumentErrorf("signing key is invalid")
+	// ErrInvalidSignature indicates a signature which can't be verified with the signing key
+	ErrInvalidSignature = util.NewInvalidArgumentErrorf("signature of the checksums is invalid")
+)
+
+const (
+	PropertyOS    = "terraform.os"
+	PropertyArch  = "terraform.arch"
+	PropertyKeyID = "terraform.key_id"
+
+	KindModule   = "module"
+	KindProvider = "provider"
+
+	// DefaultProtocol is the plugin protocol of providers released without a manifest
+	DefaultProtocol = "5.0"
+
+	maxReadmeSize     = 1 * 1024 * 1024
+	maxManifestSize   = 64 * 1024
+	maxSigningKeySize = 64 * 1024
+)
+
+var (
+	moduleNamePattern   = regexp.MustCompile(`\A[0-9A-Za-z](?:[0-9A-Za-z_-]{0,62}[0-9A-Za-z])?\z`)
+	systemPattern       = regexp.MustCompile(`\A[0-9a-z]{1,64}\z`)
+	providerTypePattern = regexp.MustCompile(`\A[0-9a-z](?:[0-9a-z-]{0,62}[0-9a-z])?\z`)
+	platformPattern     = regexp.MustCompile(`\A([0-9a-z]+)_([0-9a-z]+)\.zip\z`)
+)
+
+// Metadata represents the metadata of a Terraform module or provider version
+type Metadata struct {
+	Kind      string   `json:"kind"`
+	Readme    string   `json:"readme,omitempty"`
+	Protocols []string `json:"protocols,omitempty"`
+}
+
+// ProviderFileType is the type of a file of a provider release
+type ProviderFileType int
+
+const (
+	ProviderFileArchive ProviderFileType = iota
+	ProviderFileChecksums
+	ProviderFileSignature
+	ProviderFileSigningKey
+	ProviderFileManifest
+)
+
+// ProviderFile describes a file of a provider release
+type ProviderFile struct {
+	Type ProviderFileType
+	OS   string
+	Arch string
+}
+
+// IsValidModuleName checks if the name and target system of a module are valid
+func IsValidModuleName(name, system string) bool {
+	return moduleNamePattern.MatchString(name) && systemPattern.MatchString(system)
+}
+
+// IsValidProviderType checks if the type of a provider is valid
+func IsValidProviderType(providerType string) bool {
+	return providerTypePattern.MatchString(providerType)
+}
+
+// IsValidVersion checks if the version is a valid semantic version
+func IsValidVersion(v string) bool {
+	_, err := version.NewSemver(v)
+	return err == nil && !strings.HasPrefix(v, "v")
+}
+
+// ModulePackageName returns the package name of a module.
+// The names of providers never contain a slash.
+func ModulePackageName(name, system string) string {
+	return name + "/" + system
+}
+
+// ModuleFilename returns the name of the archive of a module version
+func ModuleFilename(name, system, version string) string {
+	return "terraform-" + system + "-" + name + "-" + version + ".tar.gz"
+}
+
+// ProviderFilename returns the name of a file of a provider release
+func ProviderFilename(providerType, version string, f *ProviderFile) string {
+	prefix := "terraform-provider-" + providerType + "_" + version + "_"
+	switch f.Type {
+	case ProviderFileChecksums:
+		return prefix + "SHA256SUMS"
+	case ProviderFileSignature:
+		return prefix + "SHA256SUMS.sig"
+	case ProviderFileSigningKey:
+		return prefix + "signing_key.asc"
+	case ProviderFileManifest:
+		return prefix + "manifest.json"
+	default:
+		return prefix + f.OS + "_" + f.Arch + ".zip"
+	}
+}
+
+// ParseProviderFilename parses the name of a file of a provider release.
+// The names follow the conventions of the provider release tooling.
+func ParseProviderFilename(providerType, version, filename string) (*ProviderFile, error) {
+	rest, ok := strings.CutPrefix(filename, "terraform-provider-"+providerType+"_"+version+"_")
+	if !ok {
+		return nil, ErrInvalidFilename
+	}
+
+	switch rest {
+	case "SHA256SUMS":
+		return &ProviderFile{Type: ProviderFileChecksums}, nil
+	case "SHA256SUMS.sig":
+		return &ProviderFile{Type: ProviderFileSignature}, nil
+	case "signing_key.asc":
+		return &ProviderFile{Type: ProviderFileSigningKey}, nil
+	case "manifest.json":
+		return &ProviderFile{Type: ProviderFileManifest}, nil
+	}
+
+	m := platformPattern.FindStringSubmatch(rest)
+	if m == nil {
+		return nil, ErrInvalidFilename
+	}
+	return &ProviderFile{Type: ProviderFileArchive, OS: m[1], Arch: m[2]}, nil
+}
+
+// ParseModuleArchive parses a gzipped module archive.
+// The archive must contain configuration files, the readme in the root directory is extracted.
+func ParseModuleArchive(r io.Reader) (*Metadata, error) {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gzr.Close()
+
+	m := &Metadata{Kind: KindModule}
+	hasConfiguration := false
+
+	tr := tar.NewReader(gzr)
+	for {
+		hd, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if hd.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean(strings.TrimPrefix(hd.Name, "./"))
+		lowerName := strings.ToLower(name)
+
+		if strings.HasSuffix(lowerName, ".tf") || strings.HasSuffix(lowerName, ".tf.json") || strings.HasSuffix(lowerName, ".tofu") {
+			hasConfiguration = true
+		} else if lowerName == "readme.md" {
+			data, err := io.ReadAll(io.LimitReader(tr, maxReadmeSize))
+			if err != nil {
+				return nil, err
+			}
+			m.Readme = string(data)
+		}
+	}
+
+	if !hasConfiguration {
+		return nil, ErrMissingConfiguration
+	}
+	return m, nil
+}
+
+// ParseProviderManifest parses the manifest of a provider release and returns the supported plugin protocols
+func ParseProviderManifest(r io.Reader) ([]string, error) {
+	var manifest struct {
+		Version  int `json:"version"`
+		Metadata struct {
+			ProtocolVersions []string `json:"protocol_versions"`
+		} `json:"metadata"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r, maxManifestSize)).Decode(&manifest); err != nil {
+		return nil, ErrInvalidManifest
+	}
+	if manifest.Version != 1 || len(manifest.Metadata.ProtocolVersions) == 0 {
+		return nil, ErrInvalidManifest
+	}
+	return manifest.Metadata.ProtocolVersions, nil
+}
+
+// ParseSigningKey parses an ascii armored public key and returns its id
+func ParseSigningKey(r io.Reader) (string, error) {
+	keyring, err := openpgp.ReadArmoredKeyRing(io.LimitReader(r, maxSigningKeySize))
+	if err != nil || len(keyring) == 0 || keyring[0].PrimaryKey == nil || keyring[0].PrivateKey != nil {
+		return "", ErrInvalidSigningKey
+	}
+
+	return strings.ToUpper(keyring[0].PrimaryKey.KeyIdString()), nil
+}
+
+// VerifySignature verifies the detached signature of the checksums file with the armored public key
+func VerifySignature(armoredKey string, checksums, signature io.Reader) error {
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armoredKey))
+	if err != nil {
+		return ErrInvalidSigningKey
+	}
+
+	if _, err := openpgp.CheckDetachedSignature(keyring, checksums, signature, nil); err != nil {
+		return ErrInvalidSignature
+	}
+	return nil
+}
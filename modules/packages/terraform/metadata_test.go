@@ -0,0 +1,153 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package terraform
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createArchive(files map[string]string) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	for name, content := range files {
+		hdr := &tar.Header{
+			Name: name,
+			Mode: 0o600,
+			Size: int64(len(content)),
+		}
+		tw.WriteHeader(hdr)
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	zw.Close()
+	return buf.Bytes()
+}
+
+func TestValidation(t *testing.T) {
+	assert.True(t, IsValidModuleName("consul", "aws"))
+	assert.True(t, IsValidModuleName("vpc_network-2", "google"))
+	assert.False(t, IsValidModuleName("-consul", "aws"))
+	assert.False(t, IsValidModuleName("consul", "AWS"))
+	assert.False(t, IsValidModuleName("con/sul", "aws"))
+
+	assert.True(t, IsValidProviderType("random"))
+	assert.True(t, IsValidProviderType("my-provider"))
+	assert.False(t, IsValidProviderType("Random"))
+	assert.False(t, IsValidProviderType("my_provider"))
+
+	assert.True(t, IsValidVersion("1.0.0"))
+	assert.True(t, IsValidVersion("1.0.0-beta.1"))
+	assert.False(t, IsValidVersion("v1.0.0"))
+	assert.False(t, IsValidVersion("latest"))
+}
+
+func TestParseProviderFilename(t *testing.T) {
+	cases := []struct {
+		Filename string
+		Expected *ProviderFile
+	}{
+		{"terraform-provider-random_1.0.0_linux_amd64.zip", &ProviderFile{Type: ProviderFileArchive, OS: "linux", Arch: "amd64"}},
+		{"terraform-provider-random_1.0.0_SHA256SUMS", &ProviderFile{Type: ProviderFileChecksums}},
+		{"terraform-provider-random_1.0.0_SHA256SUMS.sig", &ProviderFile{Type: ProviderFileSignature}},
+		{"terraform-provider-random_1.0.0_signing_key.asc", &ProviderFile{Type: ProviderFileSigningKey}},
+		{"terraform-provider-random_1.0.0_manifest.json", &ProviderFile{Type: ProviderFileManifest}},
+	}
+	for _, c := range cases {
+		f, err := ParseProviderFilename("random", "1.0.0", c.Filename)
+		assert.NoError(t, err)
+		assert.Equal(t, c.Expected, f)
+		assert.Equal(t, c.Filename, ProviderFilename("random", "1.0.0", f))
+	}
+
+	for _, filename := range []string{
+		"terraform-provider-random_1.0.1_linux_amd64.zip",
+		"terraform-provider-other_1.0.0_linux_amd64.zip",
+		"terraform-provider-random_1.0.0_linux_amd64.tar.gz",
+		"terraform-provider-random_1.0.0_linux.zip",
+		"random.zip",
+	} {
+		_, err := ParseProviderFilename("random", "1.0.0", filename)
+		assert.ErrorIs(t, err, ErrInvalidFilename, filename)
+	}
+}
+
+func TestParseModuleArchive(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		m, err := ParseModuleArchive(bytes.NewReader(createArchive(map[string]string{
+			"./main.tf":             `resource "null_resource" "test" {}`,
+			"README.md":             "# Test",
+			"modules/sub/README.md": "# Sub",
+		})))
+		assert.NoError(t, err)
+		assert.Equal(t, KindModule, m.Kind)
+		assert.Equal(t, "# Test", m.Readme)
+	})
+
+	t.Run("OpenTofu", func(t *testing.T) {
+		_, err := ParseModuleArchive(bytes.NewReader(createArchive(map[string]string{
+			"modules/sub/main.tofu": `resource "null_resource" "test" {}`,
+		})))
+		assert.NoError(t, err)
+	})
+
+	t.Run("MissingConfiguration", func(t *testing.T) {
+		_, err := ParseModuleArchive(bytes.NewReader(createArchive(map[string]string{
+			"README.md": "# Test",
+		})))
+		assert.ErrorIs(t, err, ErrMissingConfiguration)
+	})
+
+	t.Run("InvalidArchive", func(t *testing.T) {
+		_, err := ParseModuleArchive(strings.NewReader("not an archive"))
+		assert.Error(t, err)
+	})
+}
+
+func TestParseProviderManifest(t *testing.T) {
+	protocols, err := ParseProviderManifest(strings.NewReader(`{"version":1,"metadata":{"protocol_versions":["6.0"]}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"6.0"}, protocols)
+
+	_, err = ParseProviderManifest(strings.NewReader(`{"version":2,"metadata":{"protocol_versions":["6.0"]}}`))
+	assert.ErrorIs(t, err, ErrInvalidManifest)
+
+	_, err = ParseProviderManifest(strings.NewReader(`{"version":1}`))
+	assert.ErrorIs(t, err, ErrInvalidManifest)
+}
+
+func TestSignature(t *testing.T) {
+	e, err := openpgp.NewEntity("Gitea", "", "gitea@example.com", nil)
+	require.NoError(t, err)
+
+	var key bytes.Buffer
+	w, err := armor.Encode(&key, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, e.Serialize(w))
+	require.NoError(t, w.Close())
+
+	checksums := "abc  terraform-provider-random_1.0.0_linux_amd64.zip\n"
+
+	var signature bytes.Buffer
+	require.NoError(t, openpgp.DetachSign(&signature, e, strings.NewReader(checksums), nil))
+
+	keyID, err := ParseSigningKey(bytes.NewReader(key.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, strings.ToUpper(e.PrimaryKey.KeyIdString()), keyID)
+
+	_, err = ParseSigningKey(strings.NewReader("invalid"))
+	assert.ErrorIs(t, err, ErrInvalidSigningKey)
+
+	assert.NoError(t, VerifySignature(key.String(), strings.NewReader(checksums), bytes.NewReader(signature.Bytes())))
+	assert.ErrorIs(t, VerifySignature(key.String(), strings.NewReader(checksums+"modified"), bytes.NewReader(signature.Bytes())), ErrInvalidSignature)
+}
@@ -5,10 +5,13 @@ package setting
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
 	"code.gitea.io/gitea/modules/log"
+
+	"github.com/dustin/go-humanize"
 )
 
 // Actions settings
@@ -20,11 +23,16 @@ var (
 		LogCompression        logCompression    `ini:"LOG_COMPRESSION"`
 		ArtifactStorage       *Storage          // how the created artifacts should be stored
 		ArtifactRetentionDays int64             `ini:"ARTIFACT_RETENTION_DAYS"`
+		CacheStorage          *Storage          // how the caches of actions/cache should be stored
+		CacheRetentionDays    int64             `ini:"CACHE_RETENTION_DAYS"`
+		CacheMaxSize          int64             `ini:"-"` // the max total size of the caches of a repository
 		DefaultActionsURL     defaultActionsURL `ini:"DEFAULT_ACTIONS_URL"`
 		ZombieTaskTimeout     time.Duration     `ini:"ZOMBIE_TASK_TIMEOUT"`
 		EndlessTaskTimeout    time.Duration     `ini:"ENDLESS_TASK_TIMEOUT"`
 		AbandonedJobTimeout   time.Duration     `ini:"ABANDONED_JOB_TIMEOUT"`
 		SkipWorkflowStrings   []string          `ìni:"SKIP_WORKFLOW_STRINGS"`
+		ScheduleCatchUp       scheduleCatchUp   `ini:"SCHEDULE_CATCH_UP"`
+		ScheduleMissTolerance time.Duration     `ini:"SCHEDULE_MISS_TOLERANCE"`
 	}{
 		Enabled:             true,
 		DefaultActionsURL:   defaultActionsURLGitHub,
@@ -69,6 +77,22 @@ func (c logCompression) IsZstd() bool {
 	return c == "" || string(c) == "zstd"
 }
 
+type scheduleCatchUp string
+
+func (c scheduleCatchUp) IsValid() bool {
+	return c.IsNone() || c.IsOnce()
+}
+
+// IsNone means the scheduled runs missed during a downtime are skipped
+func (c scheduleCatchUp) IsNone() bool {
+	return string(c) == "none"
+}
+
+// IsOnce means one run is started for the scheduled runs missed during a downtime, it's the default like the cron scheduler always did
+func (c scheduleCatchUp) IsOnce() bool {
+	return c == "" || string(c) == "once"
+}
+
 func loadActionsFrom(rootCfg ConfigProvider) error {
 	sec := rootCfg.Section("actions")
 	err := sec.MapTo(&Actions)
@@ -111,13 +135,36 @@ func loadActionsFrom(rootCfg ConfigProvider) error {
 		Actions.ArtifactRetentionDays = 90
 	}
 
+	cacheSec, _ := rootCfg.GetSection("actions.caches")
+
+	Actions.CacheStorage, err = getStorage(rootCfg, "actions_caches", "", cacheSec)
+	if err != nil {
+		return err
+	}
+
+	// default to 7 days in Github Actions, caches not accessed in this period will be removed
+	if Actions.CacheRetentionDays <= 0 {
+		Actions.CacheRetentionDays = 7
+	}
+
+	// default to 10 GiB in Github Actions, the least recently used caches will be evicted when exceeding it
+	cacheMaxSize, err := humanize.ParseBytes(sec.Key("CACHE_MAX_SIZE").MustString("10 GiB"))
+	if err != nil || cacheMaxSize > math.MaxInt64 {
+		return fmt.Errorf("invalid [actions] CACHE_MAX_SIZE: %q", sec.Key("CACHE_MAX_SIZE").String())
+	}
+	Actions.CacheMaxSize = int64(cacheMaxSize)
+
 	Actions.ZombieTaskTimeout = sec.Key("ZOMBIE_TASK_TIMEOUT").MustDuration(10 * time.Minute)
 	Actions.EndlessTaskTimeout = sec.Key("ENDLESS_TASK_TIMEOUT").MustDuration(3 * time.Hour)
 	Actions.AbandonedJobTimeout = sec.Key("ABANDONED_JOB_TIMEOUT").MustDuration(24 * time.Hour)
+	Actions.ScheduleMissTolerance = sec.Key("SCHEDULE_MISS_TOLERANCE").MustDuration(10 * time.Minute)
 
 	if !Actions.LogCompression.IsValid() {
 		return fmt.Errorf("invalid [actions] LOG_COMPRESSION: %q", Actions.LogCompression)
 	}
+	if !Actions.ScheduleCatchUp.IsValid() {
+		return fmt.Errorf("invalid [actions] SCHEDULE_CATCH_UP: %q", Actions.ScheduleCatchUp)
+	}
 
 	return nil
 }
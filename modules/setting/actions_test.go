@@ -24,6 +24,8 @@ func Test_getStorageInheritNameSectionTypeForActions(t *testing.T) {
 	assert.Equal(t, "actions_log/", Actions.LogStorage.MinioConfig.BasePath)
 	assert.EqualValues(t, "minio", Actions.ArtifactStorage.Type)
 	assert.Equal(t, "actions_artifacts/", Actions.ArtifactStorage.MinioConfig.BasePath)
+	assert.EqualValues(t, "minio", Actions.CacheStorage.Type)
+	assert.Equal(t, "actions_caches/", Actions.CacheStorage.MinioConfig.BasePath)
 
 	iniStr = `
 [storage.actions_log]
@@ -179,3 +181,66 @@ DEFAULT_ACTIONS_URL = gitea
 		})
 	}
 }
+
+func Test_getCacheSettingsForActions(t *testing.T) {
+	oldActions := Actions
+	defer func() {
+		Actions = oldActions
+	}()
+
+	cfg, err := NewConfigProviderFromData(`
+[actions]
+`)
+	require.NoError(t, err)
+	require.NoError(t, loadActionsFrom(cfg))
+	assert.EqualValues(t, 7, Actions.CacheRetentionDays)
+	assert.EqualValues(t, 10*1024*1024*1024, Actions.CacheMaxSize)
+
+	cfg, err = NewConfigProviderFromData(`
+[actions]
+CACHE_RETENTION_DAYS = 30
+CACHE_MAX_SIZE = 1 GiB
+`)
+	require.NoError(t, err)
+	require.NoError(t, loadActionsFrom(cfg))
+	assert.EqualValues(t, 30, Actions.CacheRetentionDays)
+	assert.EqualValues(t, 1024*1024*1024, Actions.CacheMaxSize)
+
+	cfg, err = NewConfigProviderFromData(`
+[actions]
+CACHE_MAX_SIZE = invalid
+`)
+	require.NoError(t, err)
+	assert.Error(t, loadActionsFrom(cfg))
+}
+
+func Test_getScheduleCatchUpForActions(t *testing.T) {
+	oldActions := Actions
+	defer func() {
+		Actions = oldActions
+	}()
+
+	cfg, err := NewConfigProviderFromData(`
+[actions]
+`)
+	require.NoError(t, err)
+	require.NoError(t, loadActionsFrom(cfg))
+	assert.True(t, Actions.ScheduleCatchUp.IsOnce())
+	assert.False(t, Actions.ScheduleCatchUp.IsNone())
+
+	cfg, err = NewConfigProviderFromData(`
+[actions]
+SCHEDULE_CATCH_UP = none
+`)
+	require.NoError(t, err)
+	require.NoError(t, loadActionsFrom(cfg))
+	assert.True(t, Actions.ScheduleCatchUp.IsNone())
+	assert.False(t, Actions.ScheduleCatchUp.IsOnce())
+
+	cfg, err = NewConfigProviderFromData(`
+[actions]
+SCHEDULE_CATCH_UP = all
+`)
+	require.NoError(t, err)
+	assert.Error(t, loadActionsFrom(cfg))
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// ActionEnvironment represents a deployment environment of a repository
+// swagger:model
+type ActionEnvironment struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// the minutes to wait before the jobs targeting the environment can run
+	WaitTimer int64 `json:"wait_timer"`
+	// whether the user who triggered a run can't approve its jobs
+	PreventSelfReview bool `json:"prevent_self_review"`
+	// the users or teams who can approve the jobs targeting the environment
+	Reviewers []*ActionEnvironmentReviewer `json:"reviewers"`
+	// the glob patterns of the branches which can deploy to the environment, empty means all branches
+	BranchPatterns []string `json:"branch_patterns"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// ActionEnvironmentReviewer represents a required reviewer of an environment, which is either a user or a team
+type ActionEnvironmentReviewer struct {
+	// enum: User,Team
+	Type string `json:"type"`
+	User *User  `json:"user,omitempty"`
+	Team *Team  `json:"team,omitempty"`
+}
+
+// ActionEnvironmentReviewerOption represents a required reviewer when creating or updating an environment
+type ActionEnvironmentReviewerOption struct {
+	// enum: User,Team
+	// required: true
+	Type string `json:"type" binding:"Required;In(User,Team)"`
+	// the id of the user or the team
+	// required: true
+	ID int64 `json:"id" binding:"Required"`
+}
+
+// CreateOrUpdateEnvironmentOption options when creating or updating an environment
+// swagger:model
+type CreateOrUpdateEnvironmentOption struct {
+	// the minutes to wait before the jobs targeting the environment can run, at most 43200 (30 days)
+	WaitTimer int64 `json:"wait_timer"`
+	// whether the user who triggered a run can't approve its jobs
+	PreventSelfReview bool `json:"prevent_self_review"`
+	// the users or teams who can approve the jobs targeting the environment, at most 6
+	Reviewers []*ActionEnvironmentReviewerOption `json:"reviewers"`
+	// the glob patterns of the branches which can deploy to the environment, empty means all branches
+	BranchPatterns []string `json:"branch_patterns"`
+}
+
+// ActionPendingDeployment represents an environment waiting for reviews to deploy the jobs of a run
+// swagger:model
+type ActionPendingDeployment struct {
+	Environment *ActionEnvironment `json:"environment"`
+	// the ids of the jobs waiting for reviews
+	JobIDs []int64 `json:"job_ids"`
+	// whether the current user can approve or reject the jobs
+	CurrentUserCanApprove bool `json:"current_user_can_approve"`
+}
+
+// ReviewPendingDeploymentsOption options when approving or rejecting the pending deployments of a run
+// swagger:model
+type ReviewPendingDeploymentsOption struct {
+	// the ids of the environments to review
+	// required: true
+	EnvironmentIDs []int64 `json:"environment_ids" binding:"Required"`
+	// enum: approved,rejected
+	// required: true
+	State string `json:"state" binding:"Required;In(approved,rejected)"`
+	// the comment of the review
+	Comment string `json:"comment"`
+}
@@ -115,6 +115,9 @@ var (
 	_ Payloader = &RepositoryPayload{}
 	_ Payloader = &ReleasePayload{}
 	_ Payloader = &PackagePayload{}
+	_ Payloader = &LabelPayload{}
+	_ Payloader = &MilestonePayload{}
+	_ Payloader = &RepositoryDispatchPayload{}
 )
 
 // CreatePayload represents a payload information of create event.
@@ -436,6 +439,75 @@ func (p *PackagePayload) JSONPayload() ([]byte, error) {
 	return json.MarshalIndent(p, "", "  ")
 }
 
+// HookLabelAction an action that happens to a label of a repository
+type HookLabelAction string
+
+const (
+	// HookLabelCreated created
+	HookLabelCreated HookLabelAction = "created"
+	// HookLabelEdited edited
+	HookLabelEdited HookLabelAction = "edited"
+	// HookLabelDeleted deleted
+	HookLabelDeleted HookLabelAction = "deleted"
+)
+
+// LabelPayload represents a payload information of label event.
+type LabelPayload struct {
+	Action     HookLabelAction `json:"action"`
+	Label      *Label          `json:"label"`
+	Repository *Repository     `json:"repository"`
+	Sender     *User           `json:"sender"`
+}
+
+// JSONPayload implements Payload
+func (p *LabelPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// HookMilestoneAction an action that happens to a milestone of a repository
+type HookMilestoneAction string
+
+const (
+	// HookMilestoneCreated created
+	HookMilestoneCreated HookMilestoneAction = "created"
+	// HookMilestoneClosed closed
+	HookMilestoneClosed HookMilestoneAction = "closed"
+	// HookMilestoneOpened reopened
+	HookMilestoneOpened HookMilestoneAction = "opened"
+	// HookMilestoneEdited edited
+	HookMilestoneEdited HookMilestoneAction = "edited"
+	// HookMilestoneDeleted deleted
+	HookMilestoneDeleted HookMilestoneAction = "deleted"
+)
+
+// MilestonePayload represents a payload information of milestone event.
+type MilestonePayload struct {
+	Action     HookMilestoneAction `json:"action"`
+	Milestone  *Milestone          `json:"milestone"`
+	Repository *Repository         `json:"repository"`
+	Sender     *User               `json:"sender"`
+}
+
+// JSONPayload implements Payload
+func (p *MilestonePayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// RepositoryDispatchPayload represents a payload information of repository_dispatch event.
+type RepositoryDispatchPayload struct {
+	// Action is the event type of the dispatch
+	Action        string         `json:"action"`
+	Branch        string         `json:"branch"`
+	ClientPayload map[string]any `json:"client_payload"`
+	Repository    *Repository    `json:"repository"`
+	Sender        *User          `json:"sender"`
+}
+
+// JSONPayload implements Payload
+func (p *RepositoryDispatchPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
 // WorkflowDispatchPayload represents a workflow dispatch payload
 type WorkflowDispatchPayload struct {
 	Workflow   string         `json:"workflow"`
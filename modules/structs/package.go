@@ -31,3 +31,100 @@ type PackageFile struct {
 	HashSHA256 string `json:"sha256"`
 	HashSHA512 string `json:"sha512"`
 }
+
+// PackageRemote represents an upstream registry from which the missing packages of an owner are fetched
+type PackageRemote struct {
+	Type     string `json:"type"`
+	URL      string `json:"url"`
+	Username string `json:"username"`
+	// whether a password is stored, the password itself is never returned
+	HasPassword bool `json:"has_password"`
+	// seconds the mutable metadata like tags or indexes of the remote is cached, 0 uses the default of the instance
+	MetadataTTL int64 `json:"metadata_ttl"`
+	Enabled     bool  `json:"enabled"`
+	// swagger:strfmt date-time
+	CreatedAt time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SetPackageRemoteOption options to configure the remote of a package type
+type SetPackageRemoteOption struct {
+	// required: true
+	URL      string `json:"url" binding:"Required"`
+	Username string `json:"username"`
+	// the stored password is kept if omitted, an empty string removes it
+	Password *string `json:"password"`
+	// seconds the mutable metadata of the remote is cached, 0 uses the default of the instance
+	MetadataTTL int64 `json:"metadata_ttl"`
+	Enabled     bool  `json:"enabled"`
+}
+
+// PackageCleanupRule represents a rule which removes the package versions of an owner
+type PackageCleanupRule struct {
+	ID      int64  `json:"id"`
+	Type    string `json:"type"`
+	Enabled bool   `json:"enabled"`
+	// number of the most recent versions of every package which are kept
+	KeepCount int `json:"keep_count"`
+	// versions matching this regular expression are kept
+	KeepPattern string `json:"keep_pattern"`
+	// versions matching this semantic version range (for example ">= 1.0.0, < 2.0.0") are kept
+	KeepSemverRange string `json:"keep_semver_range"`
+	// whether the highest stable version of every major version is kept
+	KeepLatestPerMajor bool `json:"keep_latest_per_major"`
+	// only versions older than this number of days are removed
+	RemoveDays int `json:"remove_days"`
+	// only versions matching this regular expression are removed
+	RemovePattern string `json:"remove_pattern"`
+	// whether the patterns match against "package/version" instead of the version only
+	MatchFullName bool `json:"match_full_name"`
+	// swagger:strfmt date-time
+	CreatedAt time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CreatePackageCleanupRuleOption options to create a cleanup rule
+type CreatePackageCleanupRuleOption struct {
+	// required: true
+	Type               string `json:"type" binding:"Required"`
+	Enabled            bool   `json:"enabled"`
+	KeepCount          int    `json:"keep_count"`
+	KeepPattern        string `json:"keep_pattern"`
+	KeepSemverRange    string `json:"keep_semver_range"`
+	KeepLatestPerMajor bool   `json:"keep_latest_per_major"`
+	RemoveDays         int    `json:"remove_days"`
+	RemovePattern      string `json:"remove_pattern"`
+	MatchFullName      bool   `json:"match_full_name"`
+}
+
+// EditPackageCleanupRuleOption options to edit a cleanup rule, the package type can't be changed
+type EditPackageCleanupRuleOption struct {
+	Enabled            *bool   `json:"enabled"`
+	KeepCount          *int    `json:"keep_count"`
+	KeepPattern        *string `json:"keep_pattern"`
+	KeepSemverRange    *string `json:"keep_semver_range"`
+	KeepLatestPerMajor *bool   `json:"keep_latest_per_major"`
+	RemoveDays         *int    `json:"remove_days"`
+	RemovePattern      *string `json:"remove_pattern"`
+	MatchFullName      *bool   `json:"match_full_name"`
+}
+
+// PackageCleanupRun represents an execution of a cleanup rule which removed package versions
+type PackageCleanupRun struct {
+	ID       int64                       `json:"id"`
+	RuleID   int64                       `json:"rule_id"`
+	Type     string                      `json:"type"`
+	Versions []*PackageCleanupRunVersion `json:"versions"`
+	// swagger:strfmt date-time
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PackageCleanupRunVersion represents a package version removed by a cleanup run
+type PackageCleanupRunVersion struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// total size of the removed files in bytes
+	Size int64 `json:"size"`
+}
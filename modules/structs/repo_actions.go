@@ -43,6 +43,17 @@ type CreateActionWorkflowDispatch struct {
 	Inputs map[string]string `json:"inputs,omitempty"`
 }
 
+// CreateRepositoryDispatchOption represents the payload for triggering a repository_dispatch event
+// swagger:model
+type CreateRepositoryDispatchOption struct {
+	// the custom event type, it's matched with the `types` of the repository_dispatch event of the workflows
+	// required: true
+	// example: deploy
+	EventType string `json:"event_type" binding:"Required;MaxSize(100)"`
+	// the extra information which is available as `github.event.client_payload` in the workflows
+	ClientPayload map[string]any `json:"client_payload,omitempty"`
+}
+
 // ActionWorkflow represents a ActionWorkflow
 type ActionWorkflow struct {
 	ID    string `json:"id"`
@@ -58,6 +69,27 @@ type ActionWorkflow struct {
 	BadgeURL  string    `json:"badge_url"`
 	// swagger:strfmt date-time
 	DeletedAt time.Time `json:"deleted_at"`
+	// the inputs of the workflow_dispatch event declared by the workflow
+	Inputs []*ActionWorkflowInput `json:"inputs,omitempty"`
+}
+
+// ActionWorkflowInput represents an input of the workflow_dispatch event declared by a workflow
+type ActionWorkflowInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// enum: string,boolean,number,choice,environment
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+	Default  string `json:"default"`
+	// the allowed values of a choice input
+	Options []string `json:"options,omitempty"`
+}
+
+// ActionWorkflowInputsError is the error returned for invalid workflow_dispatch inputs
+type ActionWorkflowInputsError struct {
+	Message string `json:"message"`
+	// the reasons by the names of the invalid inputs
+	InvalidInputs map[string]string `json:"invalidInputs"`
 }
 
 // ActionWorkflowResponse returns a ActionWorkflow
@@ -66,6 +98,98 @@ type ActionWorkflowResponse struct {
 	TotalCount int64             `json:"total_count"`
 }
 
+// ActionWorkflowSchedule represents a cron schedule of a workflow on the default branch
+type ActionWorkflowSchedule struct {
+	WorkflowID string `json:"workflow_id"`
+	Cron       string `json:"cron"`
+	// the timezone the cron is evaluated in, empty for the intervals like "@every 1h"
+	Timezone string `json:"timezone"`
+	// swagger:strfmt date-time
+	NextRunAt time.Time `json:"next_run_at"`
+	// the next scheduled times, starting with next_run_at
+	UpcomingRunsAt []time.Time `json:"upcoming_runs_at"`
+	// the scheduled time of the last run started by the schedule
+	// swagger:strfmt date-time
+	LastScheduledAt *time.Time `json:"last_scheduled_at"`
+	// the scheduled time of the last run which was skipped
+	// swagger:strfmt date-time
+	LastSkippedAt *time.Time `json:"last_skipped_at"`
+	// why the last skipped run was skipped
+	// enum: missed,workflow_disabled
+	LastSkipReason string `json:"last_skip_reason,omitempty"`
+	// the latest run of the workflow triggered by a schedule
+	LastRun *ActionWorkflowRun `json:"last_run,omitempty"`
+}
+
+// ActionAttestation represents a signed build provenance attestation of a software artifact built by an Actions job
+type ActionAttestation struct {
+	ID            int64  `json:"id"`
+	SubjectName   string `json:"subject_name"`
+	SubjectDigest string `json:"subject_digest"`
+	PredicateType string `json:"predicate_type"`
+	RunID         int64  `json:"run_id"`
+	// the ID of the artifact the attestation is attached to, 0 if none
+	ArtifactID int64 `json:"artifact_id"`
+	// the ID of the package version the attestation is attached to, 0 if none
+	PackageVersionID int64 `json:"package_version_id"`
+	// the ID of the instance key which signed the attestation, it's published in /api/actions/.well-known/jwks
+	KeyID    string                     `json:"key_id"`
+	Envelope *ActionAttestationEnvelope `json:"envelope"`
+	// swagger:strfmt date-time
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ActionAttestationEnvelope is a DSSE envelope containing an in-toto statement
+type ActionAttestationEnvelope struct {
+	PayloadType string `json:"payloadType"`
+	// the in-toto statement encoded in base64
+	Payload    string                        `json:"payload"`
+	Signatures []*ActionAttestationSignature `json:"signatures"`
+}
+
+// ActionAttestationSignature is a signature of an attestation envelope
+type ActionAttestationSignature struct {
+	KeyID string `json:"keyid"`
+	// the signature of the pre-authentication encoding of the payload encoded in base64
+	Sig string `json:"sig"`
+}
+
+// CreateActionAttestationOption options to request an attestation of a software artifact built by the running Actions job
+type CreateActionAttestationOption struct {
+	// the name of the artifact, e.g. the file name of the binary
+	// required: true
+	SubjectName string `json:"subject_name" binding:"Required"`
+	// the digest of the artifact in the form "sha256:<hex>"
+	// required: true
+	SubjectDigest string `json:"subject_digest" binding:"Required"`
+	// the name of an artifact uploaded by the run to attach the attestation to, the subject digest must match one of the files of the artifact
+	ArtifactName string `json:"artifact_name"`
+	// the type of a package of the repository owner to attach the attestation to, the subject digest must match one of the files of the package version
+	PackageType    string `json:"package_type"`
+	PackageName    string `json:"package_name"`
+	PackageVersion string `json:"package_version"`
+}
+
+// VerifyActionAttestationOption options to verify an attestation
+type VerifyActionAttestationOption struct {
+	// required: true
+	Envelope *ActionAttestationEnvelope `json:"envelope" binding:"Required"`
+	// the digest of the artifact in the form "sha256:<hex>", the attestation must have it as a subject if it's given
+	SubjectDigest string `json:"subject_digest"`
+}
+
+// ActionAttestationVerification is the result of verifying an attestation
+type ActionAttestationVerification struct {
+	Verified bool `json:"verified"`
+	// why the attestation couldn't be verified
+	Message string `json:"message,omitempty"`
+	KeyID   string `json:"key_id,omitempty"`
+	// the in-toto statement of the verified attestation
+	Statement map[string]any `json:"statement,omitempty"`
+	// the stored attestation with the same envelope
+	Attestation *ActionAttestation `json:"attestation,omitempty"`
+}
+
 // ActionArtifact represents a ActionArtifact
 type ActionArtifact struct {
 	ID                 int64              `json:"id"`
@@ -107,6 +231,8 @@ type ActionWorkflowRun struct {
 	StartedAt time.Time `json:"started_at"`
 	// swagger:strfmt date-time
 	CompletedAt time.Time `json:"completed_at"`
+	// whether the owner had exceeded the usage quota when the run was created
+	QuotaExceeded bool `json:"quota_exceeded"`
 }
 
 // ActionWorkflowRunsResponse returns ActionWorkflowRuns
@@ -139,6 +265,33 @@ type ActionWorkflowStep struct {
 	CompletedAt time.Time `json:"completed_at"`
 }
 
+// ActionWorkflowStepSummary represents the Markdown summary written by a step of a WorkflowJob
+type ActionWorkflowStepSummary struct {
+	JobID      int64  `json:"job_id"`
+	JobName    string `json:"job_name"`
+	StepNumber int64  `json:"step_number"`
+	StepName   string `json:"step_name"`
+	Content    string `json:"content"`
+	// swagger:strfmt date-time
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ActionWorkflowJobLogLine represents a line of the log of a WorkflowJob
+type ActionWorkflowJobLogLine struct {
+	// the index of the line in the log of the job, starting from 0
+	Index int64 `json:"index"`
+	// swagger:strfmt date-time
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
+
+// ActionWorkflowJobLogChunk represents the consecutive lines of the log of a WorkflowJob sent by a log stream
+type ActionWorkflowJobLogChunk struct {
+	// the index of the first line, the streams can be resumed from the index of the next line
+	Offset int64                       `json:"offset"`
+	Lines  []*ActionWorkflowJobLogLine `json:"lines"`
+}
+
 // ActionWorkflowJob represents a WorkflowJob
 type ActionWorkflowJob struct {
 	ID         int64                 `json:"id"`
@@ -156,6 +309,8 @@ type ActionWorkflowJob struct {
 	RunnerID   int64                 `json:"runner_id,omitempty"`
 	RunnerName string                `json:"runner_name,omitempty"`
 	Steps      []*ActionWorkflowStep `json:"steps"`
+	// whether the logs of the job have been removed by the log retention
+	LogsExpired bool `json:"logs_expired"`
 	// swagger:strfmt date-time
 	CreatedAt time.Time `json:"created_at"`
 	// swagger:strfmt date-time
@@ -179,6 +334,8 @@ type ActionRunner struct {
 	Busy      bool                 `json:"busy"`
 	Ephemeral bool                 `json:"ephemeral"`
 	Labels    []*ActionRunnerLabel `json:"labels"`
+	// the id of the runner group, 0 means the runner doesn't belong to any group
+	RunnerGroupID int64 `json:"runner_group_id"`
 }
 
 // ActionRunnersResponse returns Runners
@@ -186,3 +343,118 @@ type ActionRunnersResponse struct {
 	Entries    []*ActionRunner `json:"runners"`
 	TotalCount int64           `json:"total_count"`
 }
+
+// ActionWorkflowPermissions represents the default permissions of the tokens of the workflow jobs which don't declare `permissions`
+type ActionWorkflowPermissions struct {
+	// read-write or read-only access to the repository
+	// enum: read,write
+	DefaultWorkflowPermissions string `json:"default_workflow_permissions"`
+	// whether the repository follows the default permissions of its owner, it's always false for users and organizations
+	Inherited bool `json:"inherited"`
+}
+
+// EditActionWorkflowPermissionsOption options for editing the default permissions of the tokens of the workflow jobs
+// swagger:model
+type EditActionWorkflowPermissionsOption struct {
+	// read-write or read-only access to the repository, empty means following the owner for repositories
+	// enum: read,write
+	DefaultWorkflowPermissions string `json:"default_workflow_permissions"`
+}
+
+// ActionForkPRContributorApproval represents the policy deciding which runs triggered by the pull requests from forks need approval
+type ActionForkPRContributorApproval struct {
+	// the contributors whose runs need approval, the runs of the restricted users always need approval
+	// enum: first_time_contributors,all_external_contributors,none
+	ApprovalPolicy string `json:"approval_policy"`
+	// whether the repository follows the policy of its owner, it's always false for users and organizations
+	Inherited bool `json:"inherited"`
+}
+
+// EditActionForkPRContributorApprovalOption options for editing the approval policy of the runs triggered by the pull requests from forks
+// swagger:model
+type EditActionForkPRContributorApprovalOption struct {
+	// the contributors whose runs need approval, empty means following the owner for repositories
+	// enum: first_time_contributors,all_external_contributors,none
+	ApprovalPolicy string `json:"approval_policy"`
+}
+
+// RerunActionRunOption options when rerunning the jobs of a workflow run
+// swagger:model
+type RerunActionRunOption struct {
+	// whether to enable the debug logging of the runners for the new attempt
+	EnableDebugLogging bool `json:"enable_debug_logging"`
+}
+
+// ActionLogRetention represents the retention period of the job logs
+type ActionLogRetention struct {
+	// the number of days to keep the job logs
+	Days int64 `json:"days"`
+	// the maximum number of days allowed by the instance
+	MaximumAllowedDays int64 `json:"maximum_allowed_days"`
+	// whether the repository follows the retention period of its owner, or the owner follows the instance
+	Inherited bool `json:"inherited"`
+}
+
+// EditActionLogRetentionOption options for editing the retention period of the job logs
+// swagger:model
+type EditActionLogRetentionOption struct {
+	// the number of days to keep the job logs, 0 means following the owner for repositories, or the instance for users and organizations
+	Days int64 `json:"days"`
+}
+
+// ActionUsageReport represents the running time of the jobs in a month
+type ActionUsageReport struct {
+	// the month of the report in UTC, like 2006-01
+	Month string `json:"month"`
+	// the total running time of the jobs in seconds
+	TotalDurationSeconds int64 `json:"total_duration_seconds"`
+	// the total running time of the jobs in minutes, rounded up
+	TotalMinutes int64 `json:"total_minutes"`
+	// the usage quota of the owner, it's empty for the reports of repositories
+	Quota  *ActionUsageQuota `json:"quota,omitempty"`
+	Usages []*ActionUsage    `json:"usages"`
+}
+
+// ActionUsage represents the running time of the jobs of a repository which run on the same runner labels
+type ActionUsage struct {
+	RepositoryID int64 `json:"repository_id"`
+	// the full name of the repository
+	Repository string   `json:"repository"`
+	Labels     []string `json:"labels"`
+	// the number of the jobs, including their attempts
+	Jobs            int64 `json:"jobs"`
+	DurationSeconds int64 `json:"duration_seconds"`
+	Minutes         int64 `json:"minutes"`
+}
+
+// ActionUsageQuota represents the monthly usage quota of a user or an organization
+type ActionUsageQuota struct {
+	// the monthly quota in minutes, 0 means unlimited
+	Minutes int64 `json:"minutes"`
+	// soft only marks the new runs after the quota is exceeded, hard cancels their jobs without running
+	// enum: soft,hard
+	Policy string `json:"policy"`
+	// the minutes used in the current month
+	UsedMinutes int64 `json:"used_minutes"`
+	Exceeded    bool  `json:"exceeded"`
+}
+
+// EditActionUsageQuotaOption options for editing the monthly usage quota of a user or an organization
+// swagger:model
+type EditActionUsageQuotaOption struct {
+	// the monthly quota in minutes, 0 removes the quota
+	Minutes int64 `json:"minutes"`
+	// the default policy is soft
+	// enum: soft,hard
+	Policy string `json:"policy"`
+}
+
+// ReviewActionRunsOption options when approving or rejecting the workflow runs waiting for approval
+// swagger:model
+type ReviewActionRunsOption struct {
+	// the ids of the runs to review, empty means all runs waiting for approval
+	RunIDs []int64 `json:"run_ids"`
+	// enum: approved,rejected
+	// required: true
+	State string `json:"state" binding:"Required;In(approved,rejected)"`
+}
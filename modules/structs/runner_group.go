@@ -0,0 +1,57 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// ActionRunnerGroup represents a group of the instance-level or org-level runners with its access policies
+// swagger:model
+type ActionRunnerGroup struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// all: all repositories can use the runners of the group, selected: only the selected repositories can use them
+	// enum: all,selected
+	Visibility string `json:"visibility"`
+	// the ids of the repositories which can use the runners of the group when the visibility is selected
+	SelectedRepositoryIDs []int64 `json:"selected_repository_ids"`
+	// the glob patterns of the workflow file names which can use the runners of the group, empty means all workflows
+	WorkflowPatterns []string `json:"workflow_patterns"`
+	// the glob patterns of the full refs which can use the runners of the group, like `refs/heads/main` or `refs/tags/v*`, empty means all refs
+	RefPatterns []string `json:"ref_patterns"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateActionRunnerGroupOption options when creating a runner group
+// swagger:model
+type CreateActionRunnerGroupOption struct {
+	// required: true
+	Name string `json:"name" binding:"Required;MaxSize(255)"`
+	// all: all repositories can use the runners of the group, selected: only the selected repositories can use them
+	// enum: all,selected
+	Visibility string `json:"visibility" binding:"OmitEmpty;In(all,selected)"`
+	// the ids of the repositories which can use the runners of the group when the visibility is selected
+	SelectedRepositoryIDs []int64 `json:"selected_repository_ids"`
+	// the glob patterns of the workflow file names which can use the runners of the group, empty means all workflows
+	WorkflowPatterns []string `json:"workflow_patterns"`
+	// the glob patterns of the full refs which can use the runners of the group, empty means all refs
+	RefPatterns []string `json:"ref_patterns"`
+}
+
+// EditActionRunnerGroupOption options when editing a runner group, the fields which are not set are not changed
+// swagger:model
+type EditActionRunnerGroupOption struct {
+	Name *string `json:"name" binding:"OmitEmpty;MaxSize(255)"`
+	// all: all repositories can use the runners of the group, selected: only the selected repositories can use them
+	// enum: all,selected
+	Visibility *string `json:"visibility" binding:"OmitEmpty;In(all,selected)"`
+	// the ids of the repositories which can use the runners of the group when the visibility is selected
+	SelectedRepositoryIDs []int64 `json:"selected_repository_ids"`
+	// the glob patterns of the workflow file names which can use the runners of the group, empty means all workflows
+	WorkflowPatterns []string `json:"workflow_patterns"`
+	// the glob patterns of the full refs which can use the runners of the group, empty means all refs
+	RefPatterns []string `json:"ref_patterns"`
+}
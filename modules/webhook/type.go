@@ -40,6 +40,11 @@ const (
 	HookEventSchedule    HookEventType = "schedule"
 	HookEventWorkflowRun HookEventType = "workflow_run"
 	HookEventWorkflowJob HookEventType = "workflow_job"
+	// HookEventLabel and HookEventMilestone are the changes of the labels and the milestones of a repository
+	HookEventLabel     HookEventType = "label"
+	HookEventMilestone HookEventType = "milestone"
+	// HookEventRepositoryDispatch is the custom event created by the API
+	HookEventRepositoryDispatch HookEventType = "repository_dispatch"
 )
 
 func AllEvents() []HookEventType {
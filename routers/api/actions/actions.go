@@ -20,5 +20,9 @@ func Routes(prefix string) *web.Router {
 	path, handler = runner.NewRunnerServiceHandler()
 	m.Post(path+"*", http.StripPrefix(prefix, handler).ServeHTTP)
 
+	m.Get("/idtoken", idToken)
+	m.Get("/.well-known/openid-configuration", idTokenDiscovery)
+	m.Get("/.well-known/jwks", idTokenKeys)
+
 	return m
 }
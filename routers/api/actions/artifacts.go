@@ -119,6 +119,7 @@ func ArtifactsRoutes(prefix string) *web.Router {
 		m.Get("/{artifact_hash}/download_url", r.getDownloadArtifactURL)
 		m.Get("/{artifact_id}/download", r.downloadArtifact)
 	})
+	m.Put(stepSummaryRoute, uploadStepSummary)
 
 	return m
 }
@@ -346,6 +347,7 @@ func (ar artifactRoutes) listArtifacts(ctx *ArtifactContext) {
 		ctx.HTTPError(http.StatusInternalServerError, err.Error())
 		return
 	}
+	artifacts = actions.FilterLatestAttemptArtifacts(artifacts)
 	if len(artifacts) == 0 {
 		log.Debug("[artifact] handleListArtifacts, no artifacts")
 		ctx.HTTPError(http.StatusNotFound)
@@ -412,6 +414,7 @@ func (ar artifactRoutes) getDownloadArtifactURL(ctx *ArtifactContext) {
 		ctx.HTTPError(http.StatusInternalServerError, err.Error())
 		return
 	}
+	artifacts = actions.FilterLatestAttemptArtifacts(artifacts)
 	if len(artifacts) == 0 {
 		log.Debug("[artifact] getDownloadArtifactURL, no artifacts")
 		ctx.HTTPError(http.StatusNotFound)
@@ -217,7 +217,9 @@ func (r artifactV4Routes) verifySignature(ctx *ArtifactContext, endp string) (*a
 
 func (r *artifactV4Routes) getArtifactByName(ctx *ArtifactContext, runID int64, name string) (*actions.ActionArtifact, error) {
 	var art actions.ActionArtifact
-	has, err := db.GetEngine(ctx).Where("run_id = ? AND artifact_name = ? AND artifact_path = ? AND content_encoding = ?", runID, name, name+".zip", ArtifactV4ContentEncoding).Get(&art)
+	// the artifact uploaded by the latest attempt, the previous attempts may have uploaded the artifact with the same name
+	has, err := db.GetEngine(ctx).Where("run_id = ? AND artifact_name = ? AND artifact_path = ? AND content_encoding = ?", runID, name, name+".zip", ArtifactV4ContentEncoding).
+		Desc("run_attempt").Get(&art)
 	if err != nil {
 		return nil, err
 	} else if !has {
@@ -458,6 +460,8 @@ func (r *artifactV4Routes) listArtifacts(ctx *ArtifactContext) {
 		return
 	}
 
+	artifacts = actions.FilterLatestAttemptArtifacts(artifacts)
+
 	list := []*ListArtifactsResponse_MonolithArtifact{}
 
 	table := map[string]*ListArtifactsResponse_MonolithArtifact{}
@@ -571,7 +575,7 @@ func (r *artifactV4Routes) deleteArtifact(ctx *ArtifactContext) {
 		return
 	}
 
-	err = actions.SetArtifactNeedDelete(ctx, runID, req.Name)
+	err = actions.SetArtifactNeedDelete(ctx, runID, artifact.RunAttempt, req.Name)
 	if err != nil {
 		log.Error("Error deleting artifacts: %v", err)
 		ctx.HTTPError(http.StatusInternalServerError, err.Error())
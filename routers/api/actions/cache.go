@@ -0,0 +1,290 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+// GitHub Actions Cache API Simple Description
+// It's used by `actions/cache` with the legacy cache service, the base url is ACTIONS_CACHE_URL.
+//
+// 1. Restore cache
+// 1.1. Find the cache matching the keys, the first key is the primary key and the others are restore keys
+// GET: /api/actions_cache/_apis/artifactcache/cache?keys=key1,key2&version=hash
+// Response (204 if not found):
+// {
+//     "result": "hit",
+//     "archiveLocation": "/api/actions_cache/_apis/artifactcache/artifacts?sig=...&expires=...&repoID=1&cacheID=2",
+//     "cacheKey": "key1",
+//     "scope": "refs/heads/main",
+//     "creationTime": "2025-01-01T00:00:00Z"
+// }
+// 1.2. Download the cache from the archive location (unauthenticated request)
+//
+// 2. Save cache
+// 2.1. Reserve a cache, it fails with 409 if the cache with the key and the version already exists
+// POST: /api/actions_cache/_apis/artifactcache/caches
+// Request:
+// {
+//     "key": "key1",
+//     "version": "hash",
+//     "cacheSize": 1024
+// }
+// Response:
+// {
+//     "cacheId": 2
+// }
+// 2.2. Upload the chunks of the cache, which could be uploaded in parallel
+// PATCH: /api/actions_cache/_apis/artifactcache/caches/{cache_id}
+// Content-Range: bytes 0-1023/*
+// 2.3. Commit the cache after all chunks are uploaded
+// POST: /api/actions_cache/_apis/artifactcache/caches/{cache_id}
+// Request:
+// {
+//     "size": 1024
+// }
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+)
+
+const (
+	cacheRouteBase = "/_apis/artifactcache"
+	// cacheDownloadEndpoint is the endpoint relative to the prefix to download a cache with a signed url
+	cacheDownloadEndpoint = "_apis/artifactcache/artifacts"
+)
+
+type cacheRoutes struct {
+	prefix string
+	fs     storage.ObjectStorage
+}
+
+func CacheRoutes(prefix string) *web.Router {
+	m := web.NewRouter()
+
+	r := cacheRoutes{
+		prefix: prefix,
+		fs:     storage.ActionsCaches,
+	}
+
+	m.Group(cacheRouteBase, func() {
+		m.Get("/cache", r.findCache)
+		m.Post("/caches", r.reserveCache)
+		m.Patch("/caches/{cache_id}", r.uploadCache)
+		m.Post("/caches/{cache_id}", r.commitCache)
+	}, ArtifactContexter())
+	m.Group(cacheRouteBase, func() {
+		m.Get("/artifacts", r.downloadCache)
+	}, ArtifactV4Contexter())
+
+	return m
+}
+
+type findCacheResponse struct {
+	Result          string    `json:"result"`
+	ArchiveLocation string    `json:"archiveLocation"`
+	CacheKey        string    `json:"cacheKey"`
+	Scope           string    `json:"scope"`
+	CreationTime    time.Time `json:"creationTime"`
+}
+
+func (r cacheRoutes) findCache(ctx *ArtifactContext) {
+	keys := util.SliceRemoveAll(strings.Split(ctx.Req.URL.Query().Get("keys"), ","), "")
+	version := ctx.Req.URL.Query().Get("version")
+	if len(keys) == 0 || version == "" {
+		ctx.HTTPError(http.StatusBadRequest, "Error keys and version are required")
+		return
+	}
+
+	_, readRefs, ok := getCacheRefsOfTask(ctx)
+	if !ok {
+		return
+	}
+
+	cache, err := actions.FindCacheByKeys(ctx, ctx.ActionTask.RepoID, readRefs, keys, version)
+	if err != nil {
+		log.Error("Error finding cache: %v", err)
+		ctx.HTTPError(http.StatusInternalServerError, "Error finding cache")
+		return
+	}
+	if cache == nil {
+		ctx.Status(http.StatusNoContent)
+		return
+	}
+	touchCache(ctx, cache)
+
+	ctx.JSON(http.StatusOK, findCacheResponse{
+		Result:          "hit",
+		ArchiveLocation: buildCacheDownloadURL(ctx, r.prefix, cacheDownloadEndpoint, cache),
+		CacheKey:        cache.CacheKey,
+		Scope:           cache.Ref,
+		CreationTime:    cache.CreatedUnix.AsTime(),
+	})
+}
+
+type reserveCacheRequest struct {
+	Key       string `json:"key"`
+	Version   string `json:"version"`
+	CacheSize int64  `json:"cacheSize"`
+}
+
+type reserveCacheResponse struct {
+	CacheID int64 `json:"cacheId"`
+}
+
+func (r cacheRoutes) reserveCache(ctx *ArtifactContext) {
+	var req reserveCacheRequest
+	if err := json.NewDecoder(ctx.Req.Body).Decode(&req); err != nil {
+		log.Error("Error decode request body: %v", err)
+		ctx.HTTPError(http.StatusBadRequest, "Error decode request body")
+		return
+	}
+	if req.CacheSize > setting.Actions.CacheMaxSize {
+		ctx.HTTPError(http.StatusBadRequest, fmt.Sprintf("Error cache size %d exceeds the limit %d", req.CacheSize, setting.Actions.CacheMaxSize))
+		return
+	}
+
+	writeRef, _, ok := getCacheRefsOfTask(ctx)
+	if !ok {
+		return
+	}
+
+	cache := &actions.ActionCache{
+		RepoID:   ctx.ActionTask.RepoID,
+		Ref:      writeRef,
+		CacheKey: req.Key,
+		Version:  req.Version,
+		RunID:    ctx.ActionTask.Job.RunID,
+	}
+	if err := actions.InsertCache(ctx, cache); err != nil {
+		if errors.Is(err, util.ErrAlreadyExist) {
+			ctx.HTTPError(http.StatusConflict, err.Error())
+		} else if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.HTTPError(http.StatusBadRequest, err.Error())
+		} else {
+			log.Error("Error reserving cache: %v", err)
+			ctx.HTTPError(http.StatusInternalServerError, "Error reserving cache")
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, reserveCacheResponse{CacheID: cache.ID})
+}
+
+func (r cacheRoutes) uploadCache(ctx *ArtifactContext) {
+	cache, ok := getUploadingCacheOfTask(ctx, ctx.PathParamInt64("cache_id"))
+	if !ok {
+		return
+	}
+
+	// parse content-range header, format: bytes 0-1023/*
+	var start, end int64
+	if _, err := fmt.Sscanf(ctx.Req.Header.Get("Content-Range"), "bytes %d-%d/", &start, &end); err != nil || start < 0 || end < start {
+		ctx.HTTPError(http.StatusBadRequest, "Error invalid content range")
+		return
+	}
+	if end+1 > setting.Actions.CacheMaxSize {
+		ctx.HTTPError(http.StatusBadRequest, fmt.Sprintf("Error cache size exceeds the limit %d", setting.Actions.CacheMaxSize))
+		return
+	}
+
+	if ctx.Req.ContentLength >= 0 && ctx.Req.ContentLength != end-start+1 {
+		ctx.HTTPError(http.StatusBadRequest, "Error content length doesn't match the content range")
+		return
+	}
+
+	chunkPath := fmt.Sprintf("%s/%d-%d.chunk", cache.TmpStorageDir(), start, end)
+	if !saveCacheUpload(ctx, r.fs, chunkPath) {
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+type commitCacheRequest struct {
+	Size int64 `json:"size"`
+}
+
+type cacheChunk struct {
+	Path       string
+	Start, End int64
+}
+
+func (r cacheRoutes) commitCache(ctx *ArtifactContext) {
+	cache, ok := getUploadingCacheOfTask(ctx, ctx.PathParamInt64("cache_id"))
+	if !ok {
+		return
+	}
+
+	var req commitCacheRequest
+	if err := json.NewDecoder(ctx.Req.Body).Decode(&req); err != nil {
+		log.Error("Error decode request body: %v", err)
+		ctx.HTTPError(http.StatusBadRequest, "Error decode request body")
+		return
+	}
+
+	var chunks []*cacheChunk
+	if err := r.fs.IterateObjects(cache.TmpStorageDir(), func(fpath string, _ storage.Object) error {
+		// when read chunks from storage, it only contains storage dir and basename,
+		// no matter the subdirectory setting in storage config
+		chunk := &cacheChunk{Path: cache.TmpStorageDir() + "/" + path.Base(fpath)}
+		if _, err := fmt.Sscanf(path.Base(fpath), "%d-%d.chunk", &chunk.Start, &chunk.End); err != nil {
+			return fmt.Errorf("parse chunk name %q: %w", fpath, err)
+		}
+		chunks = append(chunks, chunk)
+		return nil
+	}); err != nil {
+		log.Error("Error listing chunks of cache %d: %v", cache.ID, err)
+		ctx.HTTPError(http.StatusInternalServerError, "Error listing chunks")
+		return
+	}
+
+	// check the chunks are continuous, the repeated chunks of retries are skipped
+	sort.Slice(chunks, func(i, j int) bool {
+		return chunks[i].Start < chunks[j].Start
+	})
+	chunkPaths := make([]string, 0, len(chunks))
+	next := int64(0)
+	for _, chunk := range chunks {
+		if chunk.Start == next {
+			chunkPaths = append(chunkPaths, chunk.Path)
+			next = chunk.End + 1
+		}
+	}
+	if next != req.Size {
+		ctx.HTTPError(http.StatusBadRequest, fmt.Sprintf("Error uploaded size %d doesn't match %d", next, req.Size))
+		return
+	}
+
+	size, err := saveCacheContent(r.fs, cache, chunkPaths, req.Size)
+	if err != nil {
+		log.Error("Error saving cache %d: %v", cache.ID, err)
+		ctx.HTTPError(http.StatusInternalServerError, "Error saving cache")
+		return
+	}
+	if !completeCache(ctx, cache, size) {
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (r cacheRoutes) downloadCache(ctx *ArtifactContext) {
+	cache, ok := verifyCacheSignature(ctx, cacheDownloadEndpoint)
+	if !ok {
+		return
+	}
+	serveCacheContent(ctx, r.fs, cache)
+}
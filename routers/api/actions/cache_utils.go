@@ -0,0 +1,214 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/httplib"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+	actions_service "code.gitea.io/gitea/services/actions"
+)
+
+const cacheURLExpiresFormat = "2006-01-02 15:04:05.999999999 -0700 MST"
+
+func buildCacheSignature(endp, expires string, repoID, cacheID int64) []byte {
+	mac := hmac.New(sha256.New, setting.GetGeneralTokenSigningSecret())
+	mac.Write([]byte("cache"))
+	mac.Write([]byte(endp))
+	mac.Write([]byte(expires))
+	fmt.Fprint(mac, repoID)
+	fmt.Fprint(mac, cacheID)
+	return mac.Sum(nil)
+}
+
+// buildSignedCacheURL builds the url to upload or download a cache without the task token,
+// since the clients use the signed urls as the urls of the blob storage.
+func buildSignedCacheURL(ctx *ArtifactContext, prefix, endp string, cache *actions.ActionCache) string {
+	expires := time.Now().Add(60 * time.Minute).Format(cacheURLExpiresFormat)
+	return strings.TrimSuffix(httplib.GuessCurrentAppURL(ctx), "/") + strings.TrimSuffix(prefix, "/") +
+		"/" + endp + "?sig=" + base64.URLEncoding.EncodeToString(buildCacheSignature(endp, expires, cache.RepoID, cache.ID)) +
+		"&expires=" + url.QueryEscape(expires) + "&repoID=" + strconv.FormatInt(cache.RepoID, 10) + "&cacheID=" + strconv.FormatInt(cache.ID, 10)
+}
+
+// buildCacheDownloadURL returns the url of the storage if it serves directly, otherwise a signed url of the endpoint
+func buildCacheDownloadURL(ctx *ArtifactContext, prefix, endp string, cache *actions.ActionCache) string {
+	if setting.Actions.CacheStorage.ServeDirect() {
+		u, err := storage.ActionsCaches.URL(cache.StoragePath(), cache.CacheKey, http.MethodGet, nil)
+		if u != nil && err == nil {
+			return u.String()
+		}
+	}
+	return buildSignedCacheURL(ctx, prefix, endp, cache)
+}
+
+func verifyCacheSignature(ctx *ArtifactContext, endp string) (*actions.ActionCache, bool) {
+	query := ctx.Req.URL.Query()
+	sig, _ := base64.URLEncoding.DecodeString(query.Get("sig"))
+	expires := query.Get("expires")
+	repoID, _ := strconv.ParseInt(query.Get("repoID"), 10, 64)
+	cacheID, _ := strconv.ParseInt(query.Get("cacheID"), 10, 64)
+
+	if !hmac.Equal(sig, buildCacheSignature(endp, expires, repoID, cacheID)) {
+		ctx.HTTPError(http.StatusUnauthorized, "Error unauthorized")
+		return nil, false
+	}
+	t, err := time.Parse(cacheURLExpiresFormat, expires)
+	if err != nil || t.Before(time.Now()) {
+		ctx.HTTPError(http.StatusUnauthorized, "Error link expired")
+		return nil, false
+	}
+	cache, err := actions.GetCacheByRepoAndID(ctx, repoID, cacheID)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.HTTPError(http.StatusNotFound, "Error cache not found")
+		} else {
+			log.Error("Error getting cache: %v", err)
+			ctx.HTTPError(http.StatusInternalServerError, "Error getting cache")
+		}
+		return nil, false
+	}
+	return cache, true
+}
+
+// getCacheRefsOfTask returns the ref to create caches and the refs to restore caches for the task of the context
+func getCacheRefsOfTask(ctx *ArtifactContext) (string, []string, bool) {
+	writeRef, readRefs, err := actions_service.GetCacheRefsOfTask(ctx, ctx.ActionTask)
+	if err != nil {
+		log.Error("Error getting cache refs of task %d: %v", ctx.ActionTask.ID, err)
+		ctx.HTTPError(http.StatusInternalServerError, "Error getting cache refs")
+		return "", nil, false
+	}
+	return writeRef, readRefs, true
+}
+
+// getUploadingCacheOfTask returns the uploading cache created by the run of the task
+func getUploadingCacheOfTask(ctx *ArtifactContext, cacheID int64) (*actions.ActionCache, bool) {
+	cache, err := actions.GetCacheByRepoAndID(ctx, ctx.ActionTask.RepoID, cacheID)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.HTTPError(http.StatusNotFound, "Error cache not found")
+		} else {
+			log.Error("Error getting cache: %v", err)
+			ctx.HTTPError(http.StatusInternalServerError, "Error getting cache")
+		}
+		return nil, false
+	}
+	if cache.IsComplete || cache.RunID != ctx.ActionTask.Job.RunID {
+		ctx.HTTPError(http.StatusBadRequest, "Error cache is not uploading by the run")
+		return nil, false
+	}
+	return cache, true
+}
+
+// touchCache updates the last access time of a cache, so it won't be evicted soon
+func touchCache(ctx *ArtifactContext, cache *actions.ActionCache) {
+	cache.LastAccessUnix = timeutil.TimeStampNow()
+	if err := actions.UpdateCache(ctx, cache, "last_access_unix"); err != nil {
+		log.Warn("Error updating last access time of cache %d: %v", cache.ID, err)
+	}
+}
+
+// completeCache marks the cache is uploaded, and evicts the least recently used caches of the repository if it's over the size limit
+func completeCache(ctx *ArtifactContext, cache *actions.ActionCache, size int64) bool {
+	cache.Size = size
+	cache.IsComplete = true
+	cache.LastAccessUnix = timeutil.TimeStampNow()
+	if err := actions.UpdateCache(ctx, cache, "size", "is_complete", "last_access_unix"); err != nil {
+		log.Error("Error updating cache %d: %v", cache.ID, err)
+		ctx.HTTPError(http.StatusInternalServerError, "Error updating cache")
+		return false
+	}
+	actions_service.DeleteCacheTmpChunks(cache)
+	if err := actions_service.EvictRepoCaches(ctx, cache.RepoID); err != nil {
+		log.Error("Error evicting caches of repo %d: %v", cache.RepoID, err)
+	}
+	return true
+}
+
+// saveCacheContent saves the merged chunks as the content of the cache, and returns the size of the content
+func saveCacheContent(fs storage.ObjectStorage, cache *actions.ActionCache, chunkPaths []string, size int64) (int64, error) {
+	readers := make([]io.Reader, 0, len(chunkPaths))
+	defer func() {
+		for _, r := range readers {
+			_ = r.(io.Closer).Close()
+		}
+	}()
+	for _, p := range chunkPaths {
+		obj, err := fs.Open(p)
+		if err != nil {
+			return 0, fmt.Errorf("open chunk %s: %w", p, err)
+		}
+		readers = append(readers, obj)
+	}
+	written, err := fs.Save(cache.StoragePath(), io.MultiReader(readers...), size)
+	if err != nil {
+		return 0, fmt.Errorf("save cache content: %w", err)
+	}
+	if size >= 0 && written != size {
+		return 0, fmt.Errorf("cache content size %d doesn't match %d", written, size)
+	}
+	return written, nil
+}
+
+// saveCacheUpload saves the body of an upload request to the path of the storage.
+// The size of the body must be known in advance and must not exceed the max cache size,
+// the body is limited anyway so a request can never store more than the max cache size.
+func saveCacheUpload(ctx *ArtifactContext, fs storage.ObjectStorage, p string) bool {
+	if ctx.Req.ContentLength < 0 {
+		ctx.HTTPError(http.StatusLengthRequired, "Error content length is required")
+		return false
+	}
+	if ctx.Req.ContentLength > setting.Actions.CacheMaxSize {
+		ctx.HTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("Error cache size exceeds the limit %d", setting.Actions.CacheMaxSize))
+		return false
+	}
+
+	written, err := fs.Save(p, io.LimitReader(ctx.Req.Body, setting.Actions.CacheMaxSize+1), ctx.Req.ContentLength)
+	if err != nil {
+		log.Error("Error saving cache content %s: %v", p, err)
+		ctx.HTTPError(http.StatusInternalServerError, "Error saving cache content")
+		return false
+	}
+	if written > setting.Actions.CacheMaxSize {
+		if err := fs.Delete(p); err != nil {
+			log.Error("Error deleting cache content %s: %v", p, err)
+		}
+		ctx.HTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("Error cache size exceeds the limit %d", setting.Actions.CacheMaxSize))
+		return false
+	}
+	return true
+}
+
+// serveCacheContent serves the content of the cache with range requests supported
+func serveCacheContent(ctx *ArtifactContext, fs storage.ObjectStorage, cache *actions.ActionCache) {
+	if !cache.IsComplete {
+		ctx.HTTPError(http.StatusNotFound, "Error cache not found")
+		return
+	}
+	obj, err := fs.Open(cache.StoragePath())
+	if err != nil {
+		log.Error("Error opening cache %d: %v", cache.ID, err)
+		ctx.HTTPError(http.StatusInternalServerError, "Error opening cache")
+		return
+	}
+	defer obj.Close()
+	ctx.Resp.Header().Set("Content-Type", "application/octet-stream")
+	http.ServeContent(ctx.Resp, ctx.Req, "", cache.CreatedUnix.AsTime(), obj)
+}
@@ -0,0 +1,324 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+// GitHub Actions Cache V2 API Simple Description
+// It's used by `actions/cache` with the v2 cache service (ACTIONS_CACHE_SERVICE_V2), the base url is ACTIONS_RESULTS_URL.
+// The signed urls are used as the urls of the Azure blob storage by the client.
+//
+// 1. Save cache
+// 1.1. CreateCacheEntry
+// Post: /twirp/github.actions.results.api.v1.CacheService/CreateCacheEntry
+// Request:
+// {
+//     "key": "key1",
+//     "version": "hash"
+// }
+// Response:
+// {
+//     "ok": true,
+//     "signedUploadUrl": "http://localhost:3000/twirp/github.actions.results.api.v1.CacheService/UploadCache?sig=...&expires=...&repoID=1&cacheID=2"
+// }
+// 1.2. Upload the cache to the signed url (unauthenticated request) as a blob, or as blocks followed by a block list
+// PUT: http://localhost:3000/twirp/github.actions.results.api.v1.CacheService/UploadCache?sig=...&expires=...&repoID=1&cacheID=2
+// PUT: http://localhost:3000/twirp/github.actions.results.api.v1.CacheService/UploadCache?sig=...&expires=...&repoID=1&cacheID=2&comp=block&blockid=blockId1
+// PUT: http://localhost:3000/twirp/github.actions.results.api.v1.CacheService/UploadCache?sig=...&expires=...&repoID=1&cacheID=2&comp=blocklist
+// 1.3. FinalizeCacheEntryUpload
+// Post: /twirp/github.actions.results.api.v1.CacheService/FinalizeCacheEntryUpload
+// Request:
+// {
+//     "key": "key1",
+//     "version": "hash",
+//     "sizeBytes": "1024"
+// }
+// Response:
+// {
+//     "ok": true,
+//     "entryId": "2"
+// }
+//
+// 2. Restore cache
+// 2.1. GetCacheEntryDownloadURL
+// Post: /twirp/github.actions.results.api.v1.CacheService/GetCacheEntryDownloadURL
+// Request:
+// {
+//     "key": "key1",
+//     "restoreKeys": ["key"],
+//     "version": "hash"
+// }
+// Response:
+// {
+//     "ok": true,
+//     "signedDownloadUrl": "http://localhost:3000/twirp/github.actions.results.api.v1.CacheService/DownloadCache?sig=...&expires=...&repoID=1&cacheID=2",
+//     "matchedKey": "key1"
+// }
+// 2.2. Download the cache from the signed url (unauthenticated request)
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/optional"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const CacheV4RouteBase = "/twirp/github.actions.results.api.v1.CacheService"
+
+type cacheV4Routes struct {
+	prefix string
+	fs     storage.ObjectStorage
+}
+
+func CacheV4Routes(prefix string) *web.Router {
+	m := web.NewRouter()
+
+	r := cacheV4Routes{
+		prefix: prefix,
+		fs:     storage.ActionsCaches,
+	}
+
+	m.Group("", func() {
+		m.Post("CreateCacheEntry", r.createCacheEntry)
+		m.Post("FinalizeCacheEntryUpload", r.finalizeCacheEntryUpload)
+		m.Post("GetCacheEntryDownloadURL", r.getCacheEntryDownloadURL)
+	}, ArtifactContexter())
+	m.Group("", func() {
+		m.Put("UploadCache", r.uploadCache)
+		m.Get("DownloadCache", r.downloadCache)
+	}, ArtifactV4Contexter())
+
+	return m
+}
+
+func (r *cacheV4Routes) parseProtbufBody(ctx *ArtifactContext, req protoreflect.ProtoMessage) bool {
+	body, err := io.ReadAll(ctx.Req.Body)
+	if err != nil {
+		log.Error("Error decode request body: %v", err)
+		ctx.HTTPError(http.StatusInternalServerError, "Error decode request body")
+		return false
+	}
+	// the metadata and the new fields of the clients are not used
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(body, req); err != nil {
+		log.Error("Error decode request body: %v", err)
+		ctx.HTTPError(http.StatusBadRequest, "Error decode request body")
+		return false
+	}
+	return true
+}
+
+func (r *cacheV4Routes) sendProtbufBody(ctx *ArtifactContext, req protoreflect.ProtoMessage) {
+	resp, err := protojson.Marshal(req)
+	if err != nil {
+		log.Error("Error encode response body: %v", err)
+		ctx.HTTPError(http.StatusInternalServerError, "Error encode response body")
+		return
+	}
+	ctx.Resp.Header().Set("Content-Type", "application/json;charset=utf-8")
+	ctx.Resp.WriteHeader(http.StatusOK)
+	_, _ = ctx.Resp.Write(resp)
+}
+
+func (r *cacheV4Routes) createCacheEntry(ctx *ArtifactContext) {
+	var req CreateCacheEntryRequest
+	if ok := r.parseProtbufBody(ctx, &req); !ok {
+		return
+	}
+
+	writeRef, _, ok := getCacheRefsOfTask(ctx)
+	if !ok {
+		return
+	}
+
+	cache := &actions.ActionCache{
+		RepoID:   ctx.ActionTask.RepoID,
+		Ref:      writeRef,
+		CacheKey: req.Key,
+		Version:  req.Version,
+		RunID:    ctx.ActionTask.Job.RunID,
+	}
+	if err := actions.InsertCache(ctx, cache); err != nil {
+		if errors.Is(err, util.ErrAlreadyExist) || errors.Is(err, util.ErrInvalidArgument) {
+			r.sendProtbufBody(ctx, &CreateCacheEntryResponse{Ok: false, Message: err.Error()})
+		} else {
+			log.Error("Error creating cache: %v", err)
+			ctx.HTTPError(http.StatusInternalServerError, "Error creating cache")
+		}
+		return
+	}
+
+	r.sendProtbufBody(ctx, &CreateCacheEntryResponse{
+		Ok:              true,
+		SignedUploadUrl: buildSignedCacheURL(ctx, r.prefix, "UploadCache", cache),
+	})
+}
+
+func (r *cacheV4Routes) uploadCache(ctx *ArtifactContext) {
+	cache, ok := verifyCacheSignature(ctx, "UploadCache")
+	if !ok {
+		return
+	}
+	if cache.IsComplete {
+		ctx.HTTPError(http.StatusConflict, "Error cache is already uploaded")
+		return
+	}
+
+	switch comp := ctx.Req.URL.Query().Get("comp"); comp {
+	case "":
+		// the whole cache is uploaded as a blob
+		if !saveCacheUpload(ctx, r.fs, cache.StoragePath()) {
+			return
+		}
+	case "block":
+		blockID := ctx.Req.URL.Query().Get("blockid")
+		if blockID == "" {
+			ctx.HTTPError(http.StatusBadRequest, "Error block id is required")
+			return
+		}
+		if !saveCacheUpload(ctx, r.fs, cacheBlockPath(cache, blockID)) {
+			return
+		}
+	case "blocklist":
+		blockList := &BlockList{}
+		if err := xml.NewDecoder(ctx.Req.Body).Decode(blockList); err != nil {
+			ctx.HTTPError(http.StatusBadRequest, "Error decode block list")
+			return
+		}
+		blockPaths := make([]string, 0, len(blockList.Latest))
+		size := int64(0)
+		for _, blockID := range blockList.Latest {
+			blockPath := cacheBlockPath(cache, blockID)
+			fi, err := r.fs.Stat(blockPath)
+			if err != nil {
+				ctx.HTTPError(http.StatusBadRequest, fmt.Sprintf("Error block %q not found", blockID))
+				return
+			}
+			size += fi.Size()
+			blockPaths = append(blockPaths, blockPath)
+		}
+		if size > setting.Actions.CacheMaxSize {
+			ctx.HTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("Error cache size exceeds the limit %d", setting.Actions.CacheMaxSize))
+			return
+		}
+		if _, err := saveCacheContent(r.fs, cache, blockPaths, size); err != nil {
+			log.Error("Error saving cache %d: %v", cache.ID, err)
+			ctx.HTTPError(http.StatusInternalServerError, "Error saving cache")
+			return
+		}
+	default:
+		ctx.HTTPError(http.StatusBadRequest, fmt.Sprintf("Error unsupported comp %q", comp))
+		return
+	}
+
+	ctx.Status(http.StatusCreated)
+}
+
+func cacheBlockPath(cache *actions.ActionCache, blockID string) string {
+	return fmt.Sprintf("%s/block-%s", cache.TmpStorageDir(), base64.URLEncoding.EncodeToString([]byte(blockID)))
+}
+
+func (r *cacheV4Routes) finalizeCacheEntryUpload(ctx *ArtifactContext) {
+	var req FinalizeCacheEntryUploadRequest
+	if ok := r.parseProtbufBody(ctx, &req); !ok {
+		return
+	}
+
+	writeRef, _, ok := getCacheRefsOfTask(ctx)
+	if !ok {
+		return
+	}
+
+	caches, err := db.Find[actions.ActionCache](ctx, actions.FindCachesOptions{
+		RepoID:     ctx.ActionTask.RepoID,
+		Refs:       []string{writeRef},
+		Key:        req.Key,
+		Version:    req.Version,
+		IsComplete: optional.Some(false),
+	})
+	if err != nil {
+		log.Error("Error finding cache: %v", err)
+		ctx.HTTPError(http.StatusInternalServerError, "Error finding cache")
+		return
+	}
+	var cache *actions.ActionCache
+	for _, c := range caches {
+		if c.RunID == ctx.ActionTask.Job.RunID {
+			cache = c
+			break
+		}
+	}
+	if cache == nil {
+		r.sendProtbufBody(ctx, &FinalizeCacheEntryUploadResponse{Ok: false, Message: "cache entry not found"})
+		return
+	}
+
+	fi, err := r.fs.Stat(cache.StoragePath())
+	if err != nil {
+		r.sendProtbufBody(ctx, &FinalizeCacheEntryUploadResponse{Ok: false, Message: "cache content is not uploaded"})
+		return
+	}
+	if req.SizeBytes > 0 && fi.Size() != req.SizeBytes {
+		r.sendProtbufBody(ctx, &FinalizeCacheEntryUploadResponse{Ok: false, Message: fmt.Sprintf("uploaded size %d doesn't match %d", fi.Size(), req.SizeBytes)})
+		return
+	}
+	if !completeCache(ctx, cache, fi.Size()) {
+		return
+	}
+
+	r.sendProtbufBody(ctx, &FinalizeCacheEntryUploadResponse{
+		Ok:      true,
+		EntryId: cache.ID,
+	})
+}
+
+func (r *cacheV4Routes) getCacheEntryDownloadURL(ctx *ArtifactContext) {
+	var req GetCacheEntryDownloadURLRequest
+	if ok := r.parseProtbufBody(ctx, &req); !ok {
+		return
+	}
+
+	_, readRefs, ok := getCacheRefsOfTask(ctx)
+	if !ok {
+		return
+	}
+
+	keys := util.SliceRemoveAll(append([]string{req.Key}, req.RestoreKeys...), "")
+	cache, err := actions.FindCacheByKeys(ctx, ctx.ActionTask.RepoID, readRefs, keys, req.Version)
+	if err != nil {
+		log.Error("Error finding cache: %v", err)
+		ctx.HTTPError(http.StatusInternalServerError, "Error finding cache")
+		return
+	}
+	if cache == nil {
+		r.sendProtbufBody(ctx, &GetCacheEntryDownloadURLResponse{Ok: false})
+		return
+	}
+	touchCache(ctx, cache)
+
+	r.sendProtbufBody(ctx, &GetCacheEntryDownloadURLResponse{
+		Ok:                true,
+		SignedDownloadUrl: buildCacheDownloadURL(ctx, r.prefix, "DownloadCache", cache),
+		MatchedKey:        cache.CacheKey,
+	})
+}
+
+func (r *cacheV4Routes) downloadCache(ctx *ArtifactContext) {
+	cache, ok := verifyCacheSignature(ctx, "DownloadCache")
+	if !ok {
+		return
+	}
+	serveCacheContent(ctx, r.fs, cache)
+}
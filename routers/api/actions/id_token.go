@@ -0,0 +1,121 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+// OIDC ID Tokens for Actions jobs
+//
+// A job with `permissions: id-token: write` receives the environment variables
+// ACTIONS_ID_TOKEN_REQUEST_URL and ACTIONS_ID_TOKEN_REQUEST_TOKEN, it requests an ID token by:
+// GET: /api/actions/idtoken?api-version=2.0&audience=<audience>
+// Authorization: Bearer <ACTIONS_ID_TOKEN_REQUEST_TOKEN>
+// Response:
+// {
+//     "value": "<signed JWT>"
+// }
+//
+// The relying parties verify the tokens by the discovery document and the JWKS:
+// GET: /api/actions/.well-known/openid-configuration
+// GET: /api/actions/.well-known/jwks
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+)
+
+func idToken(resp http.ResponseWriter, req *http.Request) {
+	ctx := context.NewBaseContext(resp, req)
+
+	authHeader := req.Header.Get("Authorization")
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		ctx.HTTPError(http.StatusUnauthorized, "Bad authorization header")
+		return
+	}
+	taskID, err := actions_service.ParseAuthorizationToken(req)
+	if err != nil || taskID == 0 {
+		ctx.HTTPError(http.StatusUnauthorized, "Invalid authorization token")
+		return
+	}
+	task, err := actions_model.GetTaskByID(req.Context(), taskID)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.HTTPError(http.StatusUnauthorized, "Invalid authorization token")
+			return
+		}
+		log.Error("GetTaskByID: %v", err)
+		ctx.HTTPError(http.StatusInternalServerError, "Error getting task")
+		return
+	}
+
+	token, err := actions_service.CreateIDToken(req.Context(), task, req.URL.Query().Get("audience"))
+	if err != nil {
+		switch {
+		case errors.Is(err, util.ErrPermissionDenied):
+			ctx.HTTPError(http.StatusForbidden, err.Error())
+		case errors.Is(err, util.ErrNotExist), errors.Is(err, util.ErrInvalidArgument):
+			log.Error("CreateIDToken: %v", err)
+			ctx.HTTPError(http.StatusNotImplemented, "ID tokens are not available")
+		default:
+			log.Error("CreateIDToken: %v", err)
+			ctx.HTTPError(http.StatusInternalServerError, "Error creating ID token")
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]string{"value": token})
+}
+
+// idTokenDiscovery serves the OpenID Connect discovery document of the ID tokens
+// See https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderMetadata
+func idTokenDiscovery(resp http.ResponseWriter, req *http.Request) {
+	ctx := context.NewBaseContext(resp, req)
+
+	signingKey, err := actions_service.GetIDTokenSigningKey()
+	if err != nil {
+		ctx.HTTPError(http.StatusNotFound)
+		return
+	}
+
+	issuer := actions_service.IDTokenIssuer()
+	ctx.JSON(http.StatusOK, map[string]any{
+		"issuer":                                issuer,
+		"jwks_uri":                              issuer + "/.well-known/jwks",
+		"response_types_supported":              []string{"id_token"},
+		"subject_types_supported":               []string{"public", "pairwise"},
+		"id_token_signing_alg_values_supported": []string{signingKey.SigningMethod().Alg()},
+		"scopes_supported":                      []string{"openid"},
+		"claims_supported": []string{
+			"sub", "aud", "exp", "iat", "iss", "jti", "nbf",
+			"ref", "ref_type", "sha", "repository", "repository_id", "repository_owner", "repository_owner_id",
+			"workflow", "workflow_ref", "job_workflow_ref", "event_name", "run_id", "run_number", "run_attempt",
+			"job", "actor", "actor_id", "environment", "head_ref", "base_ref",
+		},
+	})
+}
+
+// idTokenKeys serves the JSON Web Key Set to verify the ID tokens
+func idTokenKeys(resp http.ResponseWriter, req *http.Request) {
+	ctx := context.NewBaseContext(resp, req)
+
+	signingKey, err := actions_service.GetIDTokenSigningKey()
+	if err != nil {
+		ctx.HTTPError(http.StatusNotFound)
+		return
+	}
+	jwk, err := signingKey.ToJWK()
+	if err != nil {
+		log.Error("Error converting signing key to JWK: %v", err)
+		ctx.HTTPError(http.StatusInternalServerError)
+		return
+	}
+	jwk["use"] = "sig"
+
+	ctx.JSON(http.StatusOK, map[string][]map[string]string{"keys": {jwk}})
+}
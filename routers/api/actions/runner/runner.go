@@ -230,6 +230,7 @@ func (s *Service) UpdateTask(
 		if err := actions_service.EmitJobsIfReady(task.Job.RunID); err != nil {
 			log.Error("Emit ready jobs of run %d: %v", task.Job.RunID, err)
 		}
+		actions_service.EmitJobsOfConcurrencyGroups(ctx, task.Job)
 	}
 
 	return connect.NewResponse(&runnerv1.UpdateTaskResponse{
@@ -295,6 +296,10 @@ func (s *Service) UpdateLog(
 	if remove != nil {
 		remove()
 	}
+	actions_service.PublishTaskLogs(task.ID, ack, rows)
+	if req.Msg.NoMore {
+		actions_service.PublishTaskLogsCompleted(task.ID)
+	}
 
 	return res, nil
 }
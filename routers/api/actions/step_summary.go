@@ -0,0 +1,64 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+// Step summaries of Actions jobs
+//
+// The runner uploads the Markdown written by a step to GITHUB_STEP_SUMMARY once the step has finished,
+// with the same ACTIONS_RUNTIME_URL and ACTIONS_RUNTIME_TOKEN as the artifacts:
+// PUT: /api/actions_pipeline/_apis/pipelines/workflows/{run_id}/steps/{step_index}/summary
+// Authorization: Bearer <ACTIONS_RUNTIME_TOKEN>
+// Content-Type: text/markdown
+// Body: the content of the summary, a maximum of 1 MiB like GitHub does
+//
+// The step index is the same as the one of the step states reported by UpdateTask,
+// and the summary of a step can be uploaded only once, the resent ones are ignored.
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/log"
+)
+
+const (
+	stepSummaryRoute   = "/_apis/pipelines/workflows/{run_id}/steps/{step_index}/summary"
+	maxStepSummarySize = 1024 * 1024
+)
+
+func uploadStepSummary(ctx *ArtifactContext) {
+	task, _, ok := validateRunID(ctx)
+	if !ok {
+		return
+	}
+	stepIndex, err := strconv.ParseInt(ctx.PathParam("step_index"), 10, 64)
+	if err != nil || stepIndex < 0 {
+		ctx.HTTPError(http.StatusBadRequest, "Invalid step index")
+		return
+	}
+	if ctx.Req.ContentLength > maxStepSummarySize {
+		ctx.HTTPError(http.StatusRequestEntityTooLarge, "Step summary is too large")
+		return
+	}
+
+	content, err := io.ReadAll(io.LimitReader(ctx.Req.Body, maxStepSummarySize+1))
+	if err != nil {
+		log.Error("Error reading the summary of step %d of task %d: %v", stepIndex, task.ID, err)
+		ctx.HTTPError(http.StatusInternalServerError, "Error reading step summary")
+		return
+	}
+	if len(content) > maxStepSummarySize {
+		ctx.HTTPError(http.StatusRequestEntityTooLarge, "Step summary is too large")
+		return
+	}
+
+	if err := actions_model.InsertTaskSummaryIfNotExist(ctx, task, stepIndex, string(content)); err != nil {
+		log.Error("Error inserting the summary of step %d of task %d: %v", stepIndex, task.ID, err)
+		ctx.HTTPError(http.StatusInternalServerError, "Error inserting step summary")
+		return
+	}
+	ctx.Status(http.StatusCreated)
+}
@@ -34,6 +34,8 @@ import (
 	container_service "code.gitea.io/gitea/services/packages/container"
 
 	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	oci "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 // maximum size of a container manifest
@@ -57,6 +59,7 @@ type containerHeaders struct {
 	Location      string
 	ContentType   string
 	ContentLength optional.Option[int64]
+	Subject       string
 }
 
 // https://github.com/opencontainers/distribution-spec/blob/main/spec.md#legacy-docker-support-http-headers
@@ -80,6 +83,9 @@ func setResponseHeaders(resp http.ResponseWriter, h *containerHeaders) {
 		resp.Header().Set("Docker-Content-Digest", h.ContentDigest)
 		resp.Header().Set("ETag", fmt.Sprintf(`"%s"`, h.ContentDigest))
 	}
+	if h.Subject != "" {
+		resp.Header().Set("OCI-Subject", h.Subject)
+	}
 	resp.Header().Set("Docker-Distribution-Api-Version", "registry/2.0")
 	resp.WriteHeader(h.Status)
 }
@@ -496,11 +502,20 @@ func getBlobFromContext(ctx *context.Context) (*packages_model.PackageFileDescri
 		return nil, container_model.ErrContainerBlobNotExist
 	}
 
-	return workaroundGetContainerBlob(ctx, &container_model.BlobSearchOptions{
+	opts := &container_model.BlobSearchOptions{
 		OwnerID: ctx.Package.Owner.ID,
 		Image:   ctx.PathParam("image"),
 		Digest:  string(d),
-	})
+	}
+
+	blob, err := workaroundGetContainerBlob(ctx, opts)
+	if errors.Is(err, container_model.ErrContainerBlobNotExist) {
+		if err := fetchRemoteBlob(ctx, opts.Image, d); err != nil {
+			return nil, err
+		}
+		blob, err = workaroundGetContainerBlob(ctx, opts)
+	}
+	return blob, err
 }
 
 // https://github.com/opencontainers/distribution-spec/blob/main/spec.md#checking-if-content-exists-in-the-registry
@@ -510,7 +525,7 @@ func HeadBlob(ctx *context.Context) {
 		if errors.Is(err, container_model.ErrContainerBlobNotExist) {
 			apiErrorDefined(ctx, errBlobUnknown)
 		} else {
-			apiError(ctx, http.StatusInternalServerError, err)
+			apiError(ctx, helper.RemoteErrorStatus(err), err)
 		}
 		return
 	}
@@ -529,7 +544,7 @@ func GetBlob(ctx *context.Context) {
 		if errors.Is(err, container_model.ErrContainerBlobNotExist) {
 			apiErrorDefined(ctx, errBlobUnknown)
 		} else {
-			apiError(ctx, http.StatusInternalServerError, err)
+			apiError(ctx, helper.RemoteErrorStatus(err), err)
 		}
 		return
 	}
@@ -607,6 +622,7 @@ func PutManifest(ctx *context.Context) {
 	setResponseHeaders(ctx.Resp, &containerHeaders{
 		Location:      fmt.Sprintf("/v2/%s/%s/manifests/%s", ctx.Package.Owner.LowerName, mci.Image, reference),
 		ContentDigest: digest,
+		Subject:       mci.Subject,
 		Status:        http.StatusCreated,
 	})
 }
@@ -637,6 +653,10 @@ func getManifestFromContext(ctx *context.Context) (*packages_model.PackageFileDe
 		return nil, err
 	}
 
+	if err := syncRemoteManifest(ctx, opts); err != nil {
+		return nil, err
+	}
+
 	return workaroundGetContainerBlob(ctx, opts)
 }
 
@@ -647,7 +667,7 @@ func HeadManifest(ctx *context.Context) {
 		if errors.Is(err, container_model.ErrContainerBlobNotExist) {
 			apiErrorDefined(ctx, errManifestUnknown)
 		} else {
-			apiError(ctx, http.StatusInternalServerError, err)
+			apiError(ctx, helper.RemoteErrorStatus(err), err)
 		}
 		return
 	}
@@ -667,7 +687,7 @@ func GetManifest(ctx *context.Context) {
 		if errors.Is(err, container_model.ErrContainerBlobNotExist) {
 			apiErrorDefined(ctx, errManifestUnknown)
 		} else {
-			apiError(ctx, http.StatusInternalServerError, err)
+			apiError(ctx, helper.RemoteErrorStatus(err), err)
 		}
 		return
 	}
@@ -743,6 +763,10 @@ func serveBlob(ctx *context.Context, pfd *packages_model.PackageFileDescriptor)
 func GetTagsList(ctx *context.Context) {
 	image := ctx.PathParam("image")
 
+	if serveRemoteTagsList(ctx, image) {
+		return
+	}
+
 	if _, err := packages_model.GetPackageByName(ctx, ctx.Package.Owner.ID, packages_model.TypeContainer, image); err != nil {
 		if errors.Is(err, packages_model.ErrPackageNotExist) {
 			apiErrorDefined(ctx, errNameUnknown)
@@ -785,6 +809,68 @@ func GetTagsList(ctx *context.Context) {
 	})
 }
 
+// https://github.com/opencontainers/distribution-spec/blob/main/spec.md#listing-referrers
+func GetReferrers(ctx *context.Context) {
+	subject := digest.Digest(ctx.PathParam("digest"))
+	if subject.Validate() != nil {
+		apiErrorDefined(ctx, errDigestInvalid)
+		return
+	}
+
+	pfds, err := container_model.GetContainerBlobs(ctx, &container_model.BlobSearchOptions{
+		OwnerID:    ctx.Package.Owner.ID,
+		Image:      ctx.PathParam("image"),
+		IsManifest: true,
+		OnlyLead:   true,
+		Subject:    string(subject),
+	})
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	artifactType := ctx.FormTrim("artifactType")
+
+	descriptors := make([]oci.Descriptor, 0, len(pfds))
+	for _, pfd := range pfds {
+		pv, err := packages_model.GetVersionByID(ctx, pfd.File.VersionID)
+		if err != nil {
+			apiError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		var metadata container_module.Metadata
+		if err := json.Unmarshal([]byte(pv.MetadataJSON), &metadata); err != nil {
+			apiError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		if artifactType != "" && metadata.ArtifactType != artifactType {
+			continue
+		}
+
+		descriptors = append(descriptors, oci.Descriptor{
+			MediaType:    pfd.Properties.GetByName(container_module.PropertyMediaType),
+			Digest:       digest.Digest(pfd.Properties.GetByName(container_module.PropertyDigest)),
+			Size:         pfd.Blob.Size,
+			ArtifactType: metadata.ArtifactType,
+			Annotations:  metadata.Annotations,
+		})
+	}
+
+	if artifactType != "" {
+		ctx.Resp.Header().Set("OCI-Filters-Applied", "artifactType")
+	}
+
+	setResponseHeaders(ctx.Resp, &containerHeaders{
+		Status:      http.StatusOK,
+		ContentType: oci.MediaTypeImageIndex,
+	})
+	_ = json.NewEncoder(ctx.Resp).Encode(oci.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: oci.MediaTypeImageIndex,
+		Manifests: descriptors,
+	}) // ignore network errors
+}
+
 // FIXME: Workaround to be removed in v1.20.
 // Update maybe we should never really remote it, as long as there is legacy data?
 // https://github.com/go-gitea/gitea/issues/19586
@@ -38,6 +38,7 @@ type manifestCreationInfo struct {
 	Image      string
 	Reference  string
 	IsTagged   bool
+	Subject    string
 	Properties map[string]string
 }
 
@@ -49,6 +50,12 @@ func processManifest(ctx context.Context, mci *manifestCreationInfo, buf *packag
 	if index.SchemaVersion != 2 {
 		return "", errUnsupported.WithMessage("Schema version is not supported")
 	}
+	if index.Subject != nil {
+		if index.Subject.Digest.Validate() != nil {
+			return "", errManifestInvalid.WithMessage("Subject digest is invalid")
+		}
+		mci.Subject = string(index.Subject.Digest)
+	}
 	if _, err := buf.Seek(0, io.SeekStart); err != nil {
 		return "", err
 	}
@@ -103,6 +110,11 @@ func processOciImageManifest(ctx context.Context, mci *manifestCreationInfo, buf
 	if err != nil {
 		return "", err
 	}
+	if mci.Subject != "" {
+		metadata.Subject = mci.Subject
+		metadata.ArtifactType = container_module.ManifestArtifactType(manifest)
+		metadata.Annotations = manifest.Annotations
+	}
 	if _, err = buf.Seek(0, io.SeekStart); err != nil {
 		return "", err
 	}
@@ -175,6 +187,11 @@ func processOciImageIndex(ctx context.Context, mci *manifestCreationInfo, buf *p
 			Type:      container_module.TypeOCI,
 			Manifests: make([]*container_module.Manifest, 0, len(index.Manifests)),
 		}
+		if mci.Subject != "" {
+			metadata.Subject = mci.Subject
+			metadata.ArtifactType = index.ArtifactType
+			metadata.Annotations = index.Annotations
+		}
 
 		for _, manifest := range index.Manifests {
 			if !container_module.IsMediaTypeImageManifest(manifest.MediaType) {
@@ -319,6 +336,16 @@ func createPackageAndVersion(ctx context.Context, mci *manifestCreationInfo, met
 		}
 	}
 
+	if metadata.Subject != "" {
+		if err = packages_model.InsertOrUpdateProperty(ctx, packages_model.PropertyTypeVersion, pv.ID, container_module.PropertyManifestSubject, metadata.Subject); err != nil {
+			return nil, err
+		}
+	} else {
+		if err = packages_model.DeletePropertiesByName(ctx, packages_model.PropertyTypeVersion, pv.ID, container_module.PropertyManifestSubject); err != nil {
+			return nil, err
+		}
+	}
+
 	return pv, nil
 }
 
@@ -0,0 +1,335 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package container
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	container_model "code.gitea.io/gitea/models/packages/container"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	container_module "code.gitea.io/gitea/modules/packages/container"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/context"
+	packages_service "code.gitea.io/gitea/services/packages"
+	remote_service "code.gitea.io/gitea/services/packages/remote"
+
+	"github.com/opencontainers/go-digest"
+	oci "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+var (
+	errRemoteDigestMismatch = util.NewInvalidArgumentErrorf("digest of the remote content mismatches")
+	errRemoteManifestSize   = util.NewInvalidArgumentErrorf("manifest of the remote exceeds maximum size")
+
+	remoteManifestHeader = http.Header{"Accept": []string{strings.Join([]string{
+		oci.MediaTypeImageManifest,
+		oci.MediaTypeImageIndex,
+		"application/vnd.docker.distribution.manifest.v2+json",
+		"application/vnd.docker.distribution.manifest.list.v2+json",
+	}, ", ")}}
+)
+
+// remoteRegistry fetches the content of an image from the remote.
+// The path of the remote url is used as namespace of the images, for example https://registry-1.docker.io/library
+type remoteRegistry struct {
+	client     *remote_service.Client
+	repository string
+	owner      *user_model.User
+	creator    *user_model.User
+	image      string
+}
+
+// getRemoteRegistry returns the remote of the image or nil if the owner has none
+func getRemoteRegistry(ctx *context.Context, image string) (*remoteRegistry, error) {
+	pr, err := remote_service.GetRemote(ctx, ctx.Package.Owner, packages_model.TypeContainer, image)
+	if err != nil || pr == nil {
+		return nil, err
+	}
+
+	c, err := remote_service.NewClient(pr)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(pr.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = "/v2/" + path.Join(strings.Trim(u.Path, "/"), image)
+	u.RawPath = ""
+	u.RawQuery = ""
+
+	return &remoteRegistry{
+		client:     c,
+		repository: u.String(),
+		owner:      ctx.Package.Owner,
+		creator:    remote_service.Creator(ctx.Doer),
+		image:      image,
+	}, nil
+}
+
+func (r *remoteRegistry) markProxied(ctx *context.Context) error {
+	p, err := packages_model.GetPackageByName(ctx, r.owner.ID, packages_model.TypeContainer, r.image)
+	if err != nil {
+		return err
+	}
+	return remote_service.MarkProxied(ctx, p.ID)
+}
+
+// fetchBlob stores the blob of the remote in the upload version of the image
+func (r *remoteRegistry) fetchBlob(ctx *context.Context, d digest.Digest) error {
+	buf, err := r.client.Download(ctx, r.creator, r.repository+"/blobs/"+string(d), nil)
+	if err != nil {
+		return err
+	}
+	defer buf.Close()
+
+	if digestFromHashSummer(buf) != string(d) {
+		return errRemoteDigestMismatch
+	}
+
+	if _, err := saveAsPackageBlob(ctx,
+		buf,
+		&packages_service.PackageCreationInfo{
+			PackageInfo: packages_service.PackageInfo{
+				Owner: r.owner,
+				Name:  r.image,
+			},
+			Creator: r.creator,
+		},
+	); err != nil {
+		return err
+	}
+
+	return r.markProxied(ctx)
+}
+
+// resolveTag returns the digest of the manifest the tag points to at the remote.
+// The result is cached for the metadata ttl of the remote.
+func (r *remoteRegistry) resolveTag(ctx *context.Context, tag string) (digest.Digest, error) {
+	target := r.repository + "/manifests/" + tag
+
+	d, err := r.client.Cached("tag:"+target, func() (string, error) {
+		resp, err := r.client.Open(ctx, http.MethodHead, target, remoteManifestHeader)
+		if err != nil {
+			return "", err
+		}
+		resp.Body.Close()
+
+		if d := resp.Header.Get("Docker-Content-Digest"); d != "" {
+			return d, nil
+		}
+
+		// the header is optional, the digest is calculated from the manifest instead
+		resp, err = r.client.Open(ctx, http.MethodGet, target, remoteManifestHeader)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+
+		d, err := digest.FromReader(io.LimitReader(resp.Body, maxManifestSize))
+		return string(d), err
+	})
+	if err != nil {
+		return "", err
+	}
+	return digest.Digest(d), digest.Digest(d).Validate()
+}
+
+// fetchManifest stores the manifest of the remote with all the blobs and manifests it references
+func (r *remoteRegistry) fetchManifest(ctx *context.Context, d digest.Digest, tag string) error {
+	resp, err := r.client.Open(ctx, http.MethodGet, r.repository+"/manifests/"+string(d), remoteManifestHeader)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	maxSize := maxManifestSize + 1
+	buf, err := packages_module.CreateHashedBufferFromReaderWithSize(&io.LimitedReader{R: resp.Body, N: int64(maxSize)}, maxSize)
+	if err != nil {
+		return err
+	}
+	defer buf.Close()
+
+	if buf.Size() > maxManifestSize {
+		return errRemoteManifestSize
+	}
+	if digestFromHashSummer(buf) != string(d) {
+		return errRemoteDigestMismatch
+	}
+
+	var manifest struct {
+		MediaType string           `json:"mediaType"`
+		Config    oci.Descriptor   `json:"config"`
+		Layers    []oci.Descriptor `json:"layers"`
+		Manifests []oci.Descriptor `json:"manifests"`
+	}
+	if err := json.NewDecoder(buf).Decode(&manifest); err != nil {
+		return err
+	}
+	if _, err := buf.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	mediaType := resp.Header.Get("Content-Type")
+	if !container_module.IsMediaTypeValid(mediaType) {
+		mediaType = manifest.MediaType
+	}
+
+	// the referenced content has to exist before the manifest can be stored,
+	// an index references the manifests of all its platforms
+	if container_module.IsMediaTypeImageIndex(mediaType) {
+		for _, m := range manifest.Manifests {
+			if err := r.ensureExists(ctx, m.Digest, true); err != nil {
+				return err
+			}
+		}
+	} else {
+		for _, blob := range append([]oci.Descriptor{manifest.Config}, manifest.Layers...) {
+			if err := r.ensureExists(ctx, blob.Digest, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	mci := &manifestCreationInfo{
+		MediaType: mediaType,
+		Owner:     r.owner,
+		Creator:   r.creator,
+		Image:     r.image,
+		Reference: string(d),
+	}
+	if tag != "" {
+		mci.Reference = tag
+		mci.IsTagged = true
+	}
+
+	if _, err := processManifest(ctx, mci, buf); err != nil {
+		return err
+	}
+
+	return r.markProxied(ctx)
+}
+
+func (r *remoteRegistry) ensureExists(ctx *context.Context, d digest.Digest, isManifest bool) error {
+	if d.Validate() != nil {
+		return errRemoteDigestMismatch
+	}
+
+	_, err := workaroundGetContainerBlob(ctx, &container_model.BlobSearchOptions{
+		OwnerID:    r.owner.ID,
+		Image:      r.image,
+		Digest:     string(d),
+		IsManifest: isManifest,
+	})
+	if err == nil || !errors.Is(err, container_model.ErrContainerBlobNotExist) {
+		return err
+	}
+
+	if isManifest {
+		return r.fetchManifest(ctx, d, "")
+	}
+	return r.fetchBlob(ctx, d)
+}
+
+// syncRemoteManifest fetches the requested manifest from the remote if it is missing.
+// A tag is updated if it points to another manifest at the remote after the metadata ttl expired.
+func syncRemoteManifest(ctx *context.Context, opts *container_model.BlobSearchOptions) error {
+	r, err := getRemoteRegistry(ctx, opts.Image)
+	if err != nil || r == nil {
+		return err
+	}
+
+	if opts.Digest != "" {
+		err = r.ensureExists(ctx, digest.Digest(opts.Digest), true)
+	} else {
+		var d digest.Digest
+		if d, err = r.resolveTag(ctx, opts.Tag); err == nil {
+			var pfd *packages_model.PackageFileDescriptor
+			pfd, err = workaroundGetContainerBlob(ctx, opts)
+			if err == nil && pfd.Properties.GetByName(container_module.PropertyDigest) == string(d) {
+				return nil
+			}
+			err = r.fetchManifest(ctx, d, opts.Tag)
+		}
+		if err != nil && !errors.Is(err, util.ErrNotExist) {
+			// the stored tag is served if the remote is unavailable
+			log.Warn("Failed to fetch the tag %s of container image %s from the remote: %v", opts.Tag, opts.Image, err)
+			return nil
+		}
+	}
+	if errors.Is(err, util.ErrNotExist) {
+		return container_model.ErrContainerBlobNotExist
+	}
+	return err
+}
+
+// fetchRemoteBlob fetches the requested blob from the remote if the owner has one
+func fetchRemoteBlob(ctx *context.Context, image string, d digest.Digest) error {
+	r, err := getRemoteRegistry(ctx, image)
+	if err != nil {
+		return err
+	}
+	if r == nil {
+		return container_model.ErrContainerBlobNotExist
+	}
+
+	if err := r.fetchBlob(ctx, d); err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			return container_model.ErrContainerBlobNotExist
+		}
+		return err
+	}
+	return nil
+}
+
+// serveRemoteTagsList serves the tags of the image at the remote.
+// It returns false if the tags have to be served from the stored versions.
+func serveRemoteTagsList(ctx *context.Context, image string) bool {
+	r, err := getRemoteRegistry(ctx, image)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return true
+	}
+	if r == nil {
+		return false
+	}
+
+	target := r.repository + "/tags/list"
+	if ctx.Req.URL.RawQuery != "" {
+		target += "?" + ctx.Req.URL.RawQuery
+	}
+
+	data, err := r.client.GetMetadata(ctx, target, nil)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			apiErrorDefined(ctx, errNameUnknown)
+			return true
+		}
+		log.Warn("Failed to fetch the tags of container image %s from the remote: %v", image, err)
+		return false
+	}
+
+	var tagList struct {
+		Tags []string `json:"tags"`
+	}
+	if err := json.Unmarshal(data, &tagList); err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return true
+	}
+
+	jsonResponse(ctx, http.StatusOK, map[string]any{
+		"name": strings.ToLower(ctx.Package.Owner.LowerName + "/" + image),
+		"tags": tagList.Tags,
+	})
+	return true
+}
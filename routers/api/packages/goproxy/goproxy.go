@@ -28,6 +28,10 @@ func apiError(ctx *context.Context, status int, obj any) {
 }
 
 func EnumeratePackageVersions(ctx *context.Context) {
+	if serveRemoteMetadata(ctx, ctx.PathParam("name"), "/@v/list", "text/plain;charset=utf-8") {
+		return
+	}
+
 	pvs, err := packages_model.GetVersionsByPackageName(ctx, ctx.Package.Owner.ID, packages_model.TypeGo, ctx.PathParam("name"))
 	if err != nil {
 		apiError(ctx, http.StatusInternalServerError, err)
@@ -50,13 +54,13 @@ func EnumeratePackageVersions(ctx *context.Context) {
 }
 
 func PackageVersionMetadata(ctx *context.Context) {
-	pv, err := resolvePackage(ctx, ctx.Package.Owner.ID, ctx.PathParam("name"), ctx.PathParam("version"))
+	if ctx.PathParam("version") == "latest" && serveRemoteMetadata(ctx, ctx.PathParam("name"), "/@latest", "application/json;charset=utf-8") {
+		return
+	}
+
+	pv, err := resolveOrFetchPackage(ctx, ctx.PathParam("name"), ctx.PathParam("version"))
 	if err != nil {
-		if errors.Is(err, util.ErrNotExist) {
-			apiError(ctx, http.StatusNotFound, err)
-		} else {
-			apiError(ctx, http.StatusInternalServerError, err)
-		}
+		apiError(ctx, helper.RemoteErrorStatus(err), err)
 		return
 	}
 
@@ -70,13 +74,9 @@ func PackageVersionMetadata(ctx *context.Context) {
 }
 
 func PackageVersionGoModContent(ctx *context.Context) {
-	pv, err := resolvePackage(ctx, ctx.Package.Owner.ID, ctx.PathParam("name"), ctx.PathParam("version"))
+	pv, err := resolveOrFetchPackage(ctx, ctx.PathParam("name"), ctx.PathParam("version"))
 	if err != nil {
-		if errors.Is(err, util.ErrNotExist) {
-			apiError(ctx, http.StatusNotFound, err)
-		} else {
-			apiError(ctx, http.StatusInternalServerError, err)
-		}
+		apiError(ctx, helper.RemoteErrorStatus(err), err)
 		return
 	}
 
@@ -90,13 +90,9 @@ func PackageVersionGoModContent(ctx *context.Context) {
 }
 
 func DownloadPackageFile(ctx *context.Context) {
-	pv, err := resolvePackage(ctx, ctx.Package.Owner.ID, ctx.PathParam("name"), ctx.PathParam("version"))
+	pv, err := resolveOrFetchPackage(ctx, ctx.PathParam("name"), ctx.PathParam("version"))
 	if err != nil {
-		if errors.Is(err, util.ErrNotExist) {
-			apiError(ctx, http.StatusNotFound, err)
-		} else {
-			apiError(ctx, http.StatusInternalServerError, err)
-		}
+		apiError(ctx, helper.RemoteErrorStatus(err), err)
 		return
 	}
 
@@ -0,0 +1,122 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package goproxy
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/log"
+	goproxy_module "code.gitea.io/gitea/modules/packages/goproxy"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/context"
+	packages_service "code.gitea.io/gitea/services/packages"
+	remote_service "code.gitea.io/gitea/services/packages/remote"
+)
+
+var errRemotePackageMismatch = util.NewInvalidArgumentErrorf("package of the remote mismatches the requested version")
+
+// serveRemoteMetadata serves the mutable metadata like the version list from the remote.
+// It returns false if the metadata has to be served from the stored versions.
+func serveRemoteMetadata(ctx *context.Context, name, target, contentType string) bool {
+	pr, err := remote_service.GetRemote(ctx, ctx.Package.Owner, packages_model.TypeGo, name)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return true
+	}
+	if pr == nil {
+		return false
+	}
+
+	c, err := remote_service.NewClient(pr)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return true
+	}
+	data, err := c.GetMetadata(ctx, "/"+name+target, nil)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			apiError(ctx, http.StatusNotFound, err)
+			return true
+		}
+		log.Warn("Failed to fetch the metadata of go package %s from the remote: %v", name, err)
+		return false
+	}
+
+	ctx.Resp.Header().Set("Content-Type", contentType)
+	ctx.Resp.WriteHeader(http.StatusOK)
+	_, _ = ctx.Resp.Write(data)
+	return true
+}
+
+// resolveOrFetchPackage resolves the package version and fetches it from the remote of the owner if it is missing
+func resolveOrFetchPackage(ctx *context.Context, name, version string) (*packages_model.PackageVersion, error) {
+	pv, err := resolvePackage(ctx, ctx.Package.Owner.ID, name, version)
+	if err == nil || !errors.Is(err, util.ErrNotExist) || version == "latest" {
+		return pv, err
+	}
+
+	pr, remoteErr := remote_service.GetRemote(ctx, ctx.Package.Owner, packages_model.TypeGo, name)
+	if remoteErr != nil {
+		return nil, remoteErr
+	}
+	if pr == nil {
+		return nil, err
+	}
+	return fetchRemotePackage(ctx, pr, name, version)
+}
+
+// fetchRemotePackage stores the module zip of the remote
+func fetchRemotePackage(ctx *context.Context, pr *packages_model.PackageRemote, name, version string) (*packages_model.PackageVersion, error) {
+	c, err := remote_service.NewClient(pr)
+	if err != nil {
+		return nil, err
+	}
+
+	creator := remote_service.Creator(ctx.Doer)
+	buf, err := c.Download(ctx, creator, fmt.Sprintf("/%s/@v/%s.zip", name, version), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer buf.Close()
+
+	pck, err := goproxy_module.ParsePackage(buf, buf.Size())
+	if err != nil {
+		return nil, err
+	}
+	if pck.Name != name || pck.Version != version {
+		return nil, errRemotePackageMismatch
+	}
+
+	if _, err := buf.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	return remote_service.CreatePackageOrAddFile(
+		ctx,
+		&packages_service.PackageCreationInfo{
+			PackageInfo: packages_service.PackageInfo{
+				Owner:       ctx.Package.Owner,
+				PackageType: packages_model.TypeGo,
+				Name:        pck.Name,
+				Version:     pck.Version,
+			},
+			Creator: creator,
+			VersionProperties: map[string]string{
+				goproxy_module.PropertyGoMod: pck.GoMod,
+			},
+		},
+		&packages_service.PackageFileCreationInfo{
+			PackageFileInfo: packages_service.PackageFileInfo{
+				Filename: fmt.Sprintf("%v.zip", pck.Version),
+			},
+			Creator: creator,
+			Data:    buf,
+			IsLead:  true,
+		},
+	)
+}
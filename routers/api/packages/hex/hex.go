@@ -0,0 +1,373 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package hex
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/json"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	hex_module "code.gitea.io/gitea/modules/packages/hex"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/routers/api/packages/helper"
+	"code.gitea.io/gitea/services/context"
+	packages_service "code.gitea.io/gitea/services/packages"
+	hex_service "code.gitea.io/gitea/services/packages/hex"
+)
+
+// https://github.com/hexpm/specifications/blob/main/registry-v2.md
+// https://github.com/hexpm/specifications/blob/main/apiary.apib
+
+const (
+	maxRequestBodySize   = 64 * 1024
+	maxRetirementMessage = 140
+)
+
+func apiError(ctx *context.Context, status int, obj any) {
+	helper.LogAndProcessError(ctx, status, obj, func(message string) {
+		respond(ctx, status, map[string]any{
+			"status":  status,
+			"message": message,
+		})
+	})
+}
+
+// respond writes the response in the external term format if the client accepts it or as JSON otherwise
+func respond(ctx *context.Context, status int, obj map[string]any) {
+	if !strings.Contains(ctx.Req.Header.Get("Accept"), hex_module.ContentTypeErlang) {
+		ctx.JSON(status, obj)
+		return
+	}
+
+	b, err := hex_module.EncodeExternalTerm(obj)
+	if err != nil {
+		ctx.HTTPError(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.Resp.Header().Set("Content-Type", hex_module.ContentTypeErlang)
+	ctx.Resp.WriteHeader(status)
+	_, _ = ctx.Resp.Write(b)
+}
+
+// parseRequestBody decodes a body in the external term format or as JSON
+func parseRequestBody(ctx *context.Context) (map[string]any, error) {
+	data, err := io.ReadAll(io.LimitReader(ctx.Req.Body, maxRequestBodySize))
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(ctx.Req.Header.Get("Content-Type"), hex_module.ContentTypeErlang) {
+		v, err := hex_module.DecodeExternalTerm(data)
+		if err != nil {
+			return nil, util.NewInvalidArgumentErrorf("invalid request body")
+		}
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, util.NewInvalidArgumentErrorf("invalid request body")
+		}
+		return m, nil
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, util.NewInvalidArgumentErrorf("invalid request body")
+	}
+	return m, nil
+}
+
+// serveRepositoryFile serves a signed resource of the registry.
+// The resources are built if the registry has none yet, for example if the owner has no packages.
+func serveRepositoryFile(ctx *context.Context, pfi *packages_service.PackageFileInfo) {
+	pv, err := hex_service.GetOrCreateRepositoryVersion(ctx, ctx.Package.Owner.ID)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	s, u, pf, err := packages_service.OpenFileForDownloadByPackageVersion(ctx, pv, pfi, ctx.Req.Method)
+	if errors.Is(err, util.ErrNotExist) && pfi.CompositeKey == "" {
+		if err = hex_service.BuildAllRepositoryFiles(ctx, ctx.Package.Owner.ID); err == nil {
+			s, u, pf, err = packages_service.OpenFileForDownloadByPackageVersion(ctx, pv, pfi, ctx.Req.Method)
+		}
+	}
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			apiError(ctx, http.StatusNotFound, err)
+		} else {
+			apiError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	helper.ServePackageFile(ctx, s, u, pf)
+}
+
+// GetPublicKey serves the public key used to verify the signed registry resources
+func GetPublicKey(ctx *context.Context) {
+	_, pub, err := hex_service.GetOrCreateKeyPair(ctx, ctx.Package.Owner.ID)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.ServeContent(strings.NewReader(pub), &context.ServeHeaderOptions{
+		ContentType: "application/x-pem-file",
+		Filename:    "public_key",
+	})
+}
+
+// GetNames serves the names of all packages
+func GetNames(ctx *context.Context) {
+	serveRepositoryFile(ctx, &packages_service.PackageFileInfo{Filename: hex_service.NamesFilename})
+}
+
+// GetVersions serves the versions of all packages
+func GetVersions(ctx *context.Context) {
+	serveRepositoryFile(ctx, &packages_service.PackageFileInfo{Filename: hex_service.VersionsFilename})
+}
+
+// GetPackage serves the releases of a package
+func GetPackage(ctx *context.Context) {
+	serveRepositoryFile(ctx, hex_service.PackageFileInfo(ctx.PathParam("name")))
+}
+
+// DownloadPackageFile serves the tarball of a package version
+func DownloadPackageFile(ctx *context.Context) {
+	filename := ctx.PathParam("filename")
+
+	// package names can't contain a dash so the first one separates the name and the version
+	name, version, ok := strings.Cut(strings.TrimSuffix(filename, ".tar"), "-")
+	if !ok || !strings.HasSuffix(filename, ".tar") {
+		apiError(ctx, http.StatusNotFound, nil)
+		return
+	}
+
+	s, u, pf, err := packages_service.OpenFileForDownloadByPackageNameAndVersion(
+		ctx,
+		&packages_service.PackageInfo{
+			Owner:       ctx.Package.Owner,
+			PackageType: packages_model.TypeHex,
+			Name:        name,
+			Version:     version,
+		},
+		&packages_service.PackageFileInfo{
+			Filename: filename,
+		},
+		ctx.Req.Method,
+	)
+	if err != nil {
+		if errors.Is(err, packages_model.ErrPackageNotExist) || errors.Is(err, packages_model.ErrPackageFileNotExist) {
+			apiError(ctx, http.StatusNotFound, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	helper.ServePackageFile(ctx, s, u, pf)
+}
+
+// UploadPackage publishes a package tarball.
+// An existing version is only overwritten if the replace parameter is set.
+func UploadPackage(ctx *context.Context) {
+	defer ctx.Req.Body.Close()
+
+	buf, err := packages_module.CreateHashedBufferFromReader(ctx.Req.Body)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	defer buf.Close()
+
+	hp, err := hex_module.ParsePackage(buf)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			apiError(ctx, http.StatusUnprocessableEntity, err)
+		} else {
+			apiError(ctx, http.StatusBadRequest, err)
+		}
+		return
+	}
+
+	if _, err := buf.Seek(0, io.SeekStart); err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	if ctx.FormBool("replace") {
+		pv, err := packages_model.GetVersionByNameAndVersion(ctx, ctx.Package.Owner.ID, packages_model.TypeHex, hp.Name, hp.Version)
+		if err == nil {
+			if err := packages_service.RemovePackageVersion(ctx, ctx.Doer, pv); err != nil {
+				apiError(ctx, http.StatusInternalServerError, err)
+				return
+			}
+		} else if !errors.Is(err, packages_model.ErrPackageNotExist) {
+			apiError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	pv, _, err := packages_service.CreatePackageAndAddFile(
+		ctx,
+		&packages_service.PackageCreationInfo{
+			PackageInfo: packages_service.PackageInfo{
+				Owner:       ctx.Package.Owner,
+				PackageType: packages_model.TypeHex,
+				Name:        hp.Name,
+				Version:     hp.Version,
+			},
+			SemverCompatible: true,
+			Creator:          ctx.Doer,
+			Metadata:         hp.Metadata,
+			VersionProperties: map[string]string{
+				hex_module.PropertyInnerChecksum: hp.InnerChecksum,
+			},
+		},
+		&packages_service.PackageFileCreationInfo{
+			PackageFileInfo: packages_service.PackageFileInfo{
+				Filename: hex_module.TarballFilename(hp.Name, hp.Version),
+			},
+			Creator: ctx.Doer,
+			Data:    buf,
+			IsLead:  true,
+		},
+	)
+	if err != nil {
+		switch err {
+		case packages_model.ErrDuplicatePackageVersion:
+			apiError(ctx, http.StatusConflict, err)
+		case packages_service.ErrQuotaTotalCount, packages_service.ErrQuotaTypeSize, packages_service.ErrQuotaTotalSize:
+			apiError(ctx, http.StatusForbidden, err)
+		default:
+			apiError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	if err := hex_service.BuildSpecificRepositoryFiles(ctx, ctx.Package.Owner.ID, hp.Name); err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	pd, err := packages_model.GetPackageDescriptor(ctx, pv)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	respond(ctx, http.StatusCreated, map[string]any{
+		"version":        pd.Version.Version,
+		"url":            pd.VersionHTMLURL(),
+		"html_url":       pd.VersionHTMLURL(),
+		"inner_checksum": hp.InnerChecksum,
+		"checksum":       pd.Files[0].Blob.HashSHA256,
+	})
+}
+
+// DeletePackage deletes a package version
+func DeletePackage(ctx *context.Context) {
+	err := packages_service.RemovePackageVersionByNameAndVersion(
+		ctx,
+		ctx.Doer,
+		&packages_service.PackageInfo{
+			Owner:       ctx.Package.Owner,
+			PackageType: packages_model.TypeHex,
+			Name:        ctx.PathParam("name"),
+			Version:     ctx.PathParam("version"),
+		},
+	)
+	if err != nil {
+		if errors.Is(err, packages_model.ErrPackageNotExist) {
+			apiError(ctx, http.StatusNotFound, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := hex_service.BuildSpecificRepositoryFiles(ctx, ctx.Package.Owner.ID, ctx.PathParam("name")); err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// RetireRelease marks a package version as retired
+func RetireRelease(ctx *context.Context) {
+	pv := getPackageVersion(ctx)
+	if pv == nil {
+		return
+	}
+
+	body, err := parseRequestBody(ctx)
+	if err != nil {
+		apiError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	reasonName, _ := body["reason"].(string)
+	reason, err := hex_module.ParseRetirementReason(reasonName)
+	if err != nil {
+		apiError(ctx, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	message, _ := body["message"].(string)
+	if utf8.RuneCountInString(message) > maxRetirementMessage {
+		apiError(ctx, http.StatusUnprocessableEntity, "retirement message is too long")
+		return
+	}
+
+	if err := hex_service.RetireRelease(ctx, pv, &hex_module.Retirement{Reason: reason, Message: message}); err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := hex_service.BuildSpecificRepositoryFiles(ctx, ctx.Package.Owner.ID, ctx.PathParam("name")); err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// UnretireRelease removes the retirement of a package version
+func UnretireRelease(ctx *context.Context) {
+	pv := getPackageVersion(ctx)
+	if pv == nil {
+		return
+	}
+
+	if err := hex_service.UnretireRelease(ctx, pv); err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := hex_service.BuildSpecificRepositoryFiles(ctx, ctx.Package.Owner.ID, ctx.PathParam("name")); err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func getPackageVersion(ctx *context.Context) *packages_model.PackageVersion {
+	pv, err := packages_model.GetVersionByNameAndVersion(ctx, ctx.Package.Owner.ID, packages_model.TypeHex, ctx.PathParam("name"), ctx.PathParam("version"))
+	if err != nil {
+		if errors.Is(err, packages_model.ErrPackageNotExist) {
+			apiError(ctx, http.StatusNotFound, err)
+		} else {
+			apiError(ctx, http.StatusInternalServerError, err)
+		}
+		return nil
+	}
+	return pv
+}
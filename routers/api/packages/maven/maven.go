@@ -76,6 +76,10 @@ func handlePackageFile(ctx *context.Context, serveContent bool) {
 		return
 	}
 
+	if params.IsMeta && serveRemoteMavenMetadata(ctx, params) {
+		return
+	}
+
 	if params.IsMeta && params.Version == "" {
 		serveMavenMetadata(ctx, params)
 	} else {
@@ -126,6 +130,11 @@ func serveMavenMetadata(ctx *context.Context, params parameters) {
 	lastModified := latest.Version.CreatedUnix.AsTime().UTC().Format(http.TimeFormat)
 	ctx.Resp.Header().Set("Last-Modified", lastModified)
 
+	serveMavenMetadataContent(ctx, params, xmlMetadataWithHeader)
+}
+
+// serveMavenMetadataContent serves the metadata file or its checksum
+func serveMavenMetadataContent(ctx *context.Context, params parameters, xmlMetadataWithHeader []byte) {
 	ext := strings.ToLower(path.Ext(params.Filename))
 	if isChecksumExtension(ext) {
 		var hash []byte
@@ -154,35 +163,20 @@ func serveMavenMetadata(ctx *context.Context, params parameters) {
 }
 
 func servePackageFile(ctx *context.Context, params parameters, serveContent bool) {
-	pv, err := packages_model.GetVersionByNameAndVersion(ctx, ctx.Package.Owner.ID, packages_model.TypeMaven, params.toInternalPackageName(), params.Version)
+	pf, err := getPackageFile(ctx, params)
 	if errors.Is(err, util.ErrNotExist) {
-		pv, err = packages_model.GetVersionByNameAndVersion(ctx, ctx.Package.Owner.ID, packages_model.TypeMaven, params.toInternalPackageNameLegacy(), params.Version)
+		pf, err = fetchRemotePackageFile(ctx, params, err)
 	}
 	if err != nil {
-		if errors.Is(err, packages_model.ErrPackageNotExist) {
+		if errors.Is(err, packages_model.ErrPackageNotExist) || errors.Is(err, packages_model.ErrPackageFileNotExist) {
 			apiError(ctx, http.StatusNotFound, err)
 		} else {
-			apiError(ctx, http.StatusInternalServerError, err)
+			apiError(ctx, helper.RemoteErrorStatus(err), err)
 		}
 		return
 	}
 
-	filename := params.Filename
-
-	ext := strings.ToLower(path.Ext(filename))
-	if isChecksumExtension(ext) {
-		filename = filename[:len(filename)-len(ext)]
-	}
-
-	pf, err := packages_model.GetFileForVersionByName(ctx, pv.ID, filename, packages_model.EmptyFileKey)
-	if err != nil {
-		if errors.Is(err, packages_model.ErrPackageFileNotExist) {
-			apiError(ctx, http.StatusNotFound, err)
-		} else {
-			apiError(ctx, http.StatusInternalServerError, err)
-		}
-		return
-	}
+	ext := strings.ToLower(path.Ext(params.Filename))
 
 	pb, err := packages_model.GetBlobByID(ctx, pf.BlobID)
 	if err != nil {
@@ -234,6 +228,26 @@ func servePackageFile(ctx *context.Context, params parameters, serveContent bool
 	helper.ServePackageFile(ctx, s, u, pf, opts)
 }
 
+// getPackageFile returns the requested file, the checksum files are mapped to the file they belong to
+func getPackageFile(ctx *context.Context, params parameters) (*packages_model.PackageFile, error) {
+	pv, err := packages_model.GetVersionByNameAndVersion(ctx, ctx.Package.Owner.ID, packages_model.TypeMaven, params.toInternalPackageName(), params.Version)
+	if errors.Is(err, util.ErrNotExist) {
+		pv, err = packages_model.GetVersionByNameAndVersion(ctx, ctx.Package.Owner.ID, packages_model.TypeMaven, params.toInternalPackageNameLegacy(), params.Version)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	filename := params.Filename
+
+	ext := strings.ToLower(path.Ext(filename))
+	if isChecksumExtension(ext) {
+		filename = filename[:len(filename)-len(ext)]
+	}
+
+	return packages_model.GetFileForVersionByName(ctx, pv.ID, filename, packages_model.EmptyFileKey)
+}
+
 func mavenPkgNameKey(packageName string) string {
 	return "pkg_maven_" + packageName
 }
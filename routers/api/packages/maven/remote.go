@@ -0,0 +1,182 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package maven
+
+import (
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/globallock"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	maven_module "code.gitea.io/gitea/modules/packages/maven"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/context"
+	packages_service "code.gitea.io/gitea/services/packages"
+	remote_service "code.gitea.io/gitea/services/packages/remote"
+)
+
+var errRemoteHashMismatch = util.NewInvalidArgumentErrorf("hash of the remote file mismatches")
+
+// getRemote returns the remote of the package. Packages with a legacy name are always local.
+func getRemote(ctx *context.Context, params parameters) (*packages_model.PackageRemote, error) {
+	_, err := packages_model.GetPackageByName(ctx, ctx.Package.Owner.ID, packages_model.TypeMaven, params.toInternalPackageNameLegacy())
+	if err == nil {
+		return nil, nil
+	} else if !errors.Is(err, packages_model.ErrPackageNotExist) {
+		return nil, err
+	}
+	return remote_service.GetRemote(ctx, ctx.Package.Owner, packages_model.TypeMaven, params.toInternalPackageName())
+}
+
+// remotePath returns the path of the file at the remote
+func remotePath(params parameters, filename string) string {
+	p := "/" + strings.ReplaceAll(params.GroupID, ".", "/") + "/" + params.ArtifactID
+	if params.Version != "" {
+		p += "/" + params.Version
+	}
+	return p + "/" + filename
+}
+
+// serveRemoteMavenMetadata serves the metadata file of the remote because it changes with every new version.
+// It returns false if the metadata has to be served from the stored versions.
+func serveRemoteMavenMetadata(ctx *context.Context, params parameters) bool {
+	pr, err := getRemote(ctx, params)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return true
+	}
+	if pr == nil {
+		return false
+	}
+
+	c, err := remote_service.NewClient(pr)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return true
+	}
+	data, err := c.GetMetadata(ctx, remotePath(params, mavenMetadataFile), nil)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			apiError(ctx, http.StatusNotFound, err)
+			return true
+		}
+		log.Warn("Failed to fetch the metadata of maven package %s from the remote: %v", params.toInternalPackageName(), err)
+		return false
+	}
+
+	serveMavenMetadataContent(ctx, params, data)
+	return true
+}
+
+// fetchRemotePackageFile stores the file of the remote. The checksum files are mapped to the file they belong to.
+// The passed error is returned if the owner has no remote for the package.
+func fetchRemotePackageFile(ctx *context.Context, params parameters, notExistErr error) (*packages_model.PackageFile, error) {
+	pr, err := getRemote(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		return nil, notExistErr
+	}
+
+	filename := params.Filename
+	ext := strings.ToLower(path.Ext(filename))
+	if isChecksumExtension(ext) {
+		filename = filename[:len(filename)-len(ext)]
+		ext = strings.ToLower(path.Ext(filename))
+	}
+
+	c, err := remote_service.NewClient(pr)
+	if err != nil {
+		return nil, err
+	}
+
+	creator := remote_service.Creator(ctx.Doer)
+	buf, err := c.Download(ctx, creator, remotePath(params, filename), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer buf.Close()
+
+	// the checksum is optional but must match if the remote provides it
+	if resp, err := c.Open(ctx, http.MethodGet, remotePath(params, filename+extensionSHA1), nil); err == nil {
+		checksum, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		_, hashSHA1, _, _ := buf.Sums()
+		if fields := strings.Fields(string(checksum)); len(fields) > 0 && !strings.EqualFold(fields[0], hex.EncodeToString(hashSHA1)) {
+			return nil, errRemoteHashMismatch
+		}
+	} else if !errors.Is(err, util.ErrNotExist) {
+		return nil, err
+	}
+
+	packageName := params.toInternalPackageName()
+
+	// for the same package, only one upload at a time
+	releaser, err := globallock.Lock(ctx, mavenPkgNameKey(packageName))
+	if err != nil {
+		return nil, err
+	}
+	defer releaser()
+
+	pvci := &packages_service.PackageCreationInfo{
+		PackageInfo: packages_service.PackageInfo{
+			Owner:       ctx.Package.Owner,
+			PackageType: packages_model.TypeMaven,
+			Name:        packageName,
+			Version:     params.Version,
+		},
+		SemverCompatible: false,
+		Creator:          creator,
+	}
+	pfci := &packages_service.PackageFileCreationInfo{
+		PackageFileInfo: packages_service.PackageFileInfo{
+			Filename: filename,
+		},
+		Creator: creator,
+		Data:    buf,
+	}
+
+	if ext == extensionPom {
+		pfci.IsLead = true
+
+		pvci.Metadata, err = maven_module.ParsePackageMetaData(buf)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := buf.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+
+	pv, err := remote_service.CreatePackageOrAddFile(ctx, pvci, pfci)
+	if err != nil {
+		return nil, err
+	}
+
+	// the version may have been created by another file before the pom was fetched
+	if pvci.Metadata != nil {
+		raw, err := json.Marshal(pvci.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		if pv.MetadataJSON != string(raw) {
+			pv.MetadataJSON = string(raw)
+			if err := packages_model.UpdateVersion(ctx, pv); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return packages_model.GetFileForVersionByName(ctx, pv.ID, filename, packages_model.EmptyFileKey)
+}
@@ -17,6 +17,7 @@ import (
 	access_model "code.gitea.io/gitea/models/perm/access"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/optional"
 	packages_module "code.gitea.io/gitea/modules/packages"
 	npm_module "code.gitea.io/gitea/modules/packages/npm"
@@ -25,6 +26,7 @@ import (
 	"code.gitea.io/gitea/routers/api/packages/helper"
 	"code.gitea.io/gitea/services/context"
 	packages_service "code.gitea.io/gitea/services/packages"
+	remote_service "code.gitea.io/gitea/services/packages/remote"
 
 	"github.com/hashicorp/go-version"
 )
@@ -55,6 +57,25 @@ func packageNameFromParams(ctx *context.Context) string {
 func PackageMetadata(ctx *context.Context) {
 	packageName := packageNameFromParams(ctx)
 
+	pr, err := remote_service.GetRemote(ctx, ctx.Package.Owner, packages_model.TypeNpm, packageName)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if pr != nil {
+		metadata, err := remotePackageMetadata(ctx, pr, packageName)
+		if err == nil {
+			ctx.JSON(http.StatusOK, metadata)
+			return
+		}
+		// the stored versions are served if the remote is unavailable
+		if errors.Is(err, util.ErrNotExist) {
+			apiError(ctx, http.StatusNotFound, err)
+			return
+		}
+		log.Warn("Failed to fetch the metadata of npm package %s from the remote: %v", packageName, err)
+	}
+
 	pvs, err := packages_model.GetVersionsByPackageName(ctx, ctx.Package.Owner.ID, packages_model.TypeNpm, packageName)
 	if err != nil {
 		apiError(ctx, http.StatusInternalServerError, err)
@@ -98,12 +119,15 @@ func DownloadPackageFile(ctx *context.Context) {
 		},
 		ctx.Req.Method,
 	)
+	if errors.Is(err, packages_model.ErrPackageNotExist) || errors.Is(err, packages_model.ErrPackageFileNotExist) {
+		s, u, pf, err = downloadRemotePackageFile(ctx, packageName, packageVersion, filename, err)
+	}
 	if err != nil {
 		if errors.Is(err, packages_model.ErrPackageNotExist) || errors.Is(err, packages_model.ErrPackageFileNotExist) {
 			apiError(ctx, http.StatusNotFound, err)
 			return
 		}
-		apiError(ctx, http.StatusInternalServerError, err)
+		apiError(ctx, helper.RemoteErrorStatus(err), err)
 		return
 	}
 
@@ -0,0 +1,162 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package npm
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/json"
+	npm_module "code.gitea.io/gitea/modules/packages/npm"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/context"
+	packages_service "code.gitea.io/gitea/services/packages"
+	remote_service "code.gitea.io/gitea/services/packages/remote"
+)
+
+var remoteMetadataHeader = http.Header{"Accept": []string{"application/json"}}
+
+// remotePackagePath returns the path of the package metadata at the remote
+func remotePackagePath(packageName string) string {
+	return "/" + url.PathEscape(packageName)
+}
+
+// remoteFilename returns the name under which the tarball of the remote is stored
+func remoteFilename(tarball string) string {
+	u, err := url.Parse(tarball)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(path.Base(u.Path))
+}
+
+// remotePackageMetadata fetches the metadata of the package from the remote.
+// The tarball urls are rewritten to this registry which stores the tarballs on the first download.
+func remotePackageMetadata(ctx *context.Context, pr *packages_model.PackageRemote, packageName string) (map[string]any, error) {
+	c, err := remote_service.NewClient(pr)
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.GetMetadata(ctx, remotePackagePath(packageName), remoteMetadataHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata map[string]any
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, err
+	}
+
+	registryURL := setting.AppURL + "api/packages/" + ctx.Package.Owner.Name + "/npm"
+
+	versions, _ := metadata["versions"].(map[string]any)
+	for packageVersion, v := range versions {
+		dist, ok := v.(map[string]any)["dist"].(map[string]any)
+		if !ok {
+			continue
+		}
+		tarball, _ := dist["tarball"].(string)
+		dist["tarball"] = fmt.Sprintf("%s/%s/-/%s/%s", registryURL, url.QueryEscape(packageName), url.PathEscape(packageVersion), url.PathEscape(remoteFilename(tarball)))
+	}
+
+	return metadata, nil
+}
+
+// fetchRemotePackage stores the package version of the remote
+func fetchRemotePackage(ctx *context.Context, pr *packages_model.PackageRemote, packageName, packageVersion, filename string) (*packages_model.PackageVersion, error) {
+	c, err := remote_service.NewClient(pr)
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.GetMetadata(ctx, remotePackagePath(packageName), remoteMetadataHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata npm_module.PackageMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, err
+	}
+	meta, ok := metadata.Versions[packageVersion]
+	if !ok || remoteFilename(meta.Dist.Tarball) != filename {
+		return nil, remote_service.ErrRemoteNotExist
+	}
+
+	creator := remote_service.Creator(ctx.Doer)
+	buf, err := c.Download(ctx, creator, meta.Dist.Tarball, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer buf.Close()
+
+	tarball, err := io.ReadAll(buf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := buf.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	npmPackage, err := npm_module.ParseRemotePackage(meta, tarball)
+	if err != nil {
+		return nil, err
+	}
+	if npmPackage.Name != packageName {
+		return nil, npm_module.ErrInvalidPackageName
+	}
+
+	return remote_service.CreatePackageOrAddFile(
+		ctx,
+		&packages_service.PackageCreationInfo{
+			PackageInfo: packages_service.PackageInfo{
+				Owner:       ctx.Package.Owner,
+				PackageType: packages_model.TypeNpm,
+				Name:        npmPackage.Name,
+				Version:     npmPackage.Version,
+			},
+			SemverCompatible: true,
+			Creator:          creator,
+			Metadata:         npmPackage.Metadata,
+		},
+		&packages_service.PackageFileCreationInfo{
+			PackageFileInfo: packages_service.PackageFileInfo{
+				Filename: filename,
+			},
+			Creator: creator,
+			Data:    buf,
+			IsLead:  true,
+		},
+	)
+}
+
+// downloadRemotePackageFile fetches the missing package file from the remote of the owner.
+// The passed error is returned if the owner has no remote for the package.
+func downloadRemotePackageFile(ctx *context.Context, packageName, packageVersion, filename string, notExistErr error) (io.ReadSeekCloser, *url.URL, *packages_model.PackageFile, error) {
+	pr, err := remote_service.GetRemote(ctx, ctx.Package.Owner, packages_model.TypeNpm, packageName)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if pr == nil {
+		return nil, nil, nil, notExistErr
+	}
+
+	pv, err := fetchRemotePackage(ctx, pr, packageName, packageVersion, filename)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return packages_service.OpenFileForDownloadByPackageVersion(
+		ctx,
+		pv,
+		&packages_service.PackageFileInfo{
+			Filename: filename,
+		},
+		ctx.Req.Method,
+	)
+}
@@ -6,6 +6,7 @@ package pypi
 import (
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"regexp"
@@ -14,13 +15,16 @@ import (
 	"unicode"
 
 	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/log"
 	packages_module "code.gitea.io/gitea/modules/packages"
 	pypi_module "code.gitea.io/gitea/modules/packages/pypi"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/validation"
 	"code.gitea.io/gitea/routers/api/packages/helper"
 	"code.gitea.io/gitea/services/context"
 	packages_service "code.gitea.io/gitea/services/packages"
+	remote_service "code.gitea.io/gitea/services/packages/remote"
 )
 
 // https://peps.python.org/pep-0426/#name
@@ -48,6 +52,28 @@ func apiError(ctx *context.Context, status int, obj any) {
 // PackageMetadata returns the metadata for a single package
 func PackageMetadata(ctx *context.Context) {
 	packageName := normalizer.Replace(ctx.PathParam("id"))
+	registryURL := setting.AppURL + "api/packages/" + ctx.Package.Owner.Name + "/pypi"
+
+	pr, err := remote_service.GetRemote(ctx, ctx.Package.Owner, packages_model.TypePyPI, packageName)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if pr != nil {
+		links, err := remotePackageLinks(ctx, pr, registryURL, packageName)
+		if err == nil {
+			ctx.Data["PackageName"] = packageName
+			ctx.Data["Links"] = links
+			ctx.HTML(http.StatusOK, "api/packages/pypi/simple")
+			return
+		}
+		// the stored files are served if the remote is unavailable
+		if errors.Is(err, util.ErrNotExist) {
+			apiError(ctx, http.StatusNotFound, err)
+			return
+		}
+		log.Warn("Failed to fetch the index of PyPI package %s from the remote: %v", packageName, err)
+	}
 
 	pvs, err := packages_model.GetVersionsByPackageName(ctx, ctx.Package.Owner.ID, packages_model.TypePyPI, packageName)
 	if err != nil {
@@ -70,9 +96,21 @@ func PackageMetadata(ctx *context.Context) {
 		return strings.Compare(pds[i].Version.Version, pds[j].Version.Version) < 0
 	})
 
-	ctx.Data["RegistryURL"] = setting.AppURL + "api/packages/" + ctx.Package.Owner.Name + "/pypi"
-	ctx.Data["PackageDescriptor"] = pds[0]
-	ctx.Data["PackageDescriptors"] = pds
+	links := make([]*pypi_module.SimpleLink, 0, len(pds))
+	for _, pd := range pds {
+		metadata := pd.Metadata.(*pypi_module.Metadata)
+		for _, pf := range pd.Files {
+			links = append(links, &pypi_module.SimpleLink{
+				URL:            fmt.Sprintf("%s/files/%s/%s/%s", registryURL, pd.Package.LowerName, pd.Version.Version, pf.File.Name),
+				Filename:       pf.File.Name,
+				HashSHA256:     pf.Blob.HashSHA256,
+				RequiresPython: metadata.RequiresPython,
+			})
+		}
+	}
+
+	ctx.Data["PackageName"] = pds[0].Package.Name
+	ctx.Data["Links"] = links
 	ctx.HTML(http.StatusOK, "api/packages/pypi/simple")
 }
 
@@ -95,12 +133,15 @@ func DownloadPackageFile(ctx *context.Context) {
 		},
 		ctx.Req.Method,
 	)
+	if errors.Is(err, packages_model.ErrPackageNotExist) || errors.Is(err, packages_model.ErrPackageFileNotExist) {
+		s, u, pf, err = downloadRemotePackageFile(ctx, packageName, packageVersion, filename, err)
+	}
 	if err != nil {
 		if errors.Is(err, packages_model.ErrPackageNotExist) || errors.Is(err, packages_model.ErrPackageFileNotExist) {
 			apiError(ctx, http.StatusNotFound, err)
 			return
 		}
-		apiError(ctx, http.StatusInternalServerError, err)
+		apiError(ctx, helper.RemoteErrorStatus(err), err)
 		return
 	}
 
@@ -0,0 +1,164 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package pypi
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	pypi_module "code.gitea.io/gitea/modules/packages/pypi"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/context"
+	packages_service "code.gitea.io/gitea/services/packages"
+	remote_service "code.gitea.io/gitea/services/packages/remote"
+)
+
+var (
+	errRemoteHashMismatch = util.NewInvalidArgumentErrorf("hash of the remote file mismatches")
+	remoteIndexHeader     = http.Header{"Accept": []string{"text/html"}}
+)
+
+// fetchRemoteLinks fetches the file links of the package from the simple index of the remote
+func fetchRemoteLinks(ctx *context.Context, c *remote_service.Client, packageName string) ([]*pypi_module.SimpleLink, error) {
+	target := "/simple/" + url.PathEscape(packageName) + "/"
+
+	data, err := c.GetMetadata(ctx, target, remoteIndexHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL, err := url.Parse(c.URL(target))
+	if err != nil {
+		return nil, err
+	}
+	return pypi_module.ParseSimpleIndex(bytes.NewReader(data), baseURL)
+}
+
+// remotePackageLinks returns the file links of the package at the remote pointing to this registry
+// which stores the files on the first download
+func remotePackageLinks(ctx *context.Context, pr *packages_model.PackageRemote, registryURL, packageName string) ([]*pypi_module.SimpleLink, error) {
+	c, err := remote_service.NewClient(pr)
+	if err != nil {
+		return nil, err
+	}
+	remoteLinks, err := fetchRemoteLinks(ctx, c, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	links := make([]*pypi_module.SimpleLink, 0, len(remoteLinks))
+	for _, link := range remoteLinks {
+		packageVersion := pypi_module.VersionFromFilename(packageName, link.Filename)
+		if packageVersion == "" {
+			continue
+		}
+		links = append(links, &pypi_module.SimpleLink{
+			URL:            fmt.Sprintf("%s/files/%s/%s/%s", registryURL, url.PathEscape(packageName), url.PathEscape(packageVersion), url.PathEscape(link.Filename)),
+			Filename:       link.Filename,
+			HashSHA256:     link.HashSHA256,
+			RequiresPython: link.RequiresPython,
+		})
+	}
+	return links, nil
+}
+
+// fetchRemotePackageFile stores the package file of the remote
+func fetchRemotePackageFile(ctx *context.Context, pr *packages_model.PackageRemote, packageName, packageVersion, filename string) (*packages_model.PackageVersion, error) {
+	if !isValidNameAndVersion(packageName, packageVersion) || pypi_module.VersionFromFilename(packageName, filename) != packageVersion {
+		return nil, remote_service.ErrRemoteNotExist
+	}
+
+	c, err := remote_service.NewClient(pr)
+	if err != nil {
+		return nil, err
+	}
+	links, err := fetchRemoteLinks(ctx, c, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	var link *pypi_module.SimpleLink
+	for _, l := range links {
+		if l.Filename == filename {
+			link = l
+			break
+		}
+	}
+	if link == nil {
+		return nil, remote_service.ErrRemoteNotExist
+	}
+
+	creator := remote_service.Creator(ctx.Doer)
+	buf, err := c.Download(ctx, creator, link.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer buf.Close()
+
+	if link.HashSHA256 != "" {
+		_, _, hashSHA256, _ := buf.Sums()
+		if hex.EncodeToString(hashSHA256) != link.HashSHA256 {
+			return nil, errRemoteHashMismatch
+		}
+	}
+	if _, err := buf.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	return remote_service.CreatePackageOrAddFile(
+		ctx,
+		&packages_service.PackageCreationInfo{
+			PackageInfo: packages_service.PackageInfo{
+				Owner:       ctx.Package.Owner,
+				PackageType: packages_model.TypePyPI,
+				Name:        packageName,
+				Version:     packageVersion,
+			},
+			SemverCompatible: false,
+			Creator:          creator,
+			Metadata: &pypi_module.Metadata{
+				RequiresPython: link.RequiresPython,
+			},
+		},
+		&packages_service.PackageFileCreationInfo{
+			PackageFileInfo: packages_service.PackageFileInfo{
+				Filename: filename,
+			},
+			Creator: creator,
+			Data:    buf,
+			IsLead:  true,
+		},
+	)
+}
+
+// downloadRemotePackageFile fetches the missing package file from the remote of the owner.
+// The passed error is returned if the owner has no remote for the package.
+func downloadRemotePackageFile(ctx *context.Context, packageName, packageVersion, filename string, notExistErr error) (io.ReadSeekCloser, *url.URL, *packages_model.PackageFile, error) {
+	pr, err := remote_service.GetRemote(ctx, ctx.Package.Owner, packages_model.TypePyPI, packageName)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if pr == nil {
+		return nil, nil, nil, notExistErr
+	}
+
+	pv, err := fetchRemotePackageFile(ctx, pr, packageName, packageVersion, filename)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return packages_service.OpenFileForDownloadByPackageVersion(
+		ctx,
+		pv,
+		&packages_service.PackageFileInfo{
+			Filename: filename,
+		},
+		ctx.Req.Method,
+	)
+}
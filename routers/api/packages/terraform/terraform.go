@@ -0,0 +1,633 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package terraform
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/json"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	terraform_module "code.gitea.io/gitea/modules/packages/terraform"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/routers/api/packages/helper"
+	"code.gitea.io/gitea/services/context"
+	packages_service "code.gitea.io/gitea/services/packages"
+)
+
+// https://developer.hashicorp.com/terraform/internals/module-registry-protocol
+// https://developer.hashicorp.com/terraform/internals/provider-registry-protocol
+
+func apiError(ctx *context.Context, status int, obj any) {
+	helper.LogAndProcessError(ctx, status, obj, func(message string) {
+		ctx.JSON(status, struct {
+			Errors []string `json:"errors"`
+		}{
+			Errors: []string{
+				message,
+			},
+		})
+	})
+}
+
+func registryURL() string {
+	return setting.AppURL + "api/packages/-/terraform"
+}
+
+func packageURL(ctx *context.Context) string {
+	return fmt.Sprintf("%sapi/packages/%s/terraform", setting.AppURL, url.PathEscape(ctx.Package.Owner.Name))
+}
+
+// ServiceDiscovery serves the endpoints of the registry.
+// Terraform requests it from the root of the host, the owner is used as the namespace of the modules and providers.
+func ServiceDiscovery(ctx *context.Context) {
+	ctx.JSON(http.StatusOK, map[string]string{
+		"modules.v1":   registryURL() + "/modules/v1/",
+		"providers.v1": registryURL() + "/providers/v1/",
+	})
+}
+
+func getPackageDescriptors(ctx *context.Context, packageName string) ([]*packages_model.PackageDescriptor, error) {
+	pvs, err := packages_model.GetVersionsByPackageName(ctx, ctx.Package.Owner.ID, packages_model.TypeTerraform, packageName)
+	if err != nil {
+		return nil, err
+	}
+	if len(pvs) == 0 {
+		return nil, packages_model.ErrPackageNotExist
+	}
+
+	pds, err := packages_model.GetPackageDescriptors(ctx, pvs)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(pds, func(i, j int) bool {
+		return pds[i].SemVer.LessThan(pds[j].SemVer)
+	})
+
+	return pds, nil
+}
+
+type moduleVersion struct {
+	Version string `json:"version"`
+}
+
+type moduleVersions struct {
+	Versions []*moduleVersion `json:"versions"`
+}
+
+// EnumerateModuleVersions lists the available versions of a module
+func EnumerateModuleVersions(ctx *context.Context) {
+	pds, err := getPackageDescriptors(ctx, terraform_module.ModulePackageName(ctx.PathParam("name"), ctx.PathParam("system")))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			apiError(ctx, http.StatusNotFound, err)
+		} else {
+			apiError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	versions := make([]*moduleVersion, 0, len(pds))
+	for _, pd := range pds {
+		versions = append(versions, &moduleVersion{Version: pd.Version.Version})
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"modules": []*moduleVersions{{Versions: versions}},
+	})
+}
+
+// DownloadModule returns the location of the module archive in the X-Terraform-Get header
+func DownloadModule(ctx *context.Context) {
+	name := ctx.PathParam("name")
+	system := ctx.PathParam("system")
+	packageVersion := ctx.PathParam("version")
+
+	_, err := packages_model.GetVersionByNameAndVersion(ctx, ctx.Package.Owner.ID, packages_model.TypeTerraform, terraform_module.ModulePackageName(name, system), packageVersion)
+	if err != nil {
+		if errors.Is(err, packages_model.ErrPackageNotExist) {
+			apiError(ctx, http.StatusNotFound, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Resp.Header().Set("X-Terraform-Get", fmt.Sprintf("%s/modules/%s/%s/%s/%s", packageURL(ctx), url.PathEscape(name), url.PathEscape(system), url.PathEscape(packageVersion), url.PathEscape(terraform_module.ModuleFilename(name, system, packageVersion))))
+	ctx.Status(http.StatusNoContent)
+}
+
+// DownloadModuleFile serves the archive of a module version
+func DownloadModuleFile(ctx *context.Context) {
+	s, u, pf, err := packages_service.OpenFileForDownloadByPackageNameAndVersion(
+		ctx,
+		&packages_service.PackageInfo{
+			Owner:       ctx.Package.Owner,
+			PackageType: packages_model.TypeTerraform,
+			Name:        terraform_module.ModulePackageName(ctx.PathParam("name"), ctx.PathParam("system")),
+			Version:     ctx.PathParam("version"),
+		},
+		&packages_service.PackageFileInfo{
+			Filename: ctx.PathParam("filename"),
+		},
+		ctx.Req.Method,
+	)
+	if err != nil {
+		if errors.Is(err, packages_model.ErrPackageNotExist) || errors.Is(err, packages_model.ErrPackageFileNotExist) {
+			apiError(ctx, http.StatusNotFound, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	helper.ServePackageFile(ctx, s, u, pf)
+}
+
+// UploadModule creates a module version from a gzipped archive
+func UploadModule(ctx *context.Context) {
+	name := ctx.PathParam("name")
+	system := ctx.PathParam("system")
+	packageVersion := ctx.PathParam("version")
+
+	if !terraform_module.IsValidModuleName(name, system) {
+		apiError(ctx, http.StatusBadRequest, terraform_module.ErrInvalidName)
+		return
+	}
+	if !terraform_module.IsValidVersion(packageVersion) {
+		apiError(ctx, http.StatusBadRequest, terraform_module.ErrInvalidVersion)
+		return
+	}
+
+	upload, needToClose, err := ctx.UploadStream()
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if needToClose {
+		defer upload.Close()
+	}
+
+	buf, err := packages_module.CreateHashedBufferFromReader(upload)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	defer buf.Close()
+
+	metadata, err := terraform_module.ParseModuleArchive(buf)
+	if err != nil {
+		apiError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if _, err := buf.Seek(0, io.SeekStart); err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	_, _, err = packages_service.CreatePackageAndAddFile(
+		ctx,
+		&packages_service.PackageCreationInfo{
+			PackageInfo: packages_service.PackageInfo{
+				Owner:       ctx.Package.Owner,
+				PackageType: packages_model.TypeTerraform,
+				Name:        terraform_module.ModulePackageName(name, system),
+				Version:     packageVersion,
+			},
+			SemverCompatible: true,
+			Creator:          ctx.Doer,
+			Metadata:         metadata,
+		},
+		&packages_service.PackageFileCreationInfo{
+			PackageFileInfo: packages_service.PackageFileInfo{
+				Filename: terraform_module.ModuleFilename(name, system, packageVersion),
+			},
+			Creator: ctx.Doer,
+			Data:    buf,
+			IsLead:  true,
+		},
+	)
+	if err != nil {
+		switch err {
+		case packages_model.ErrDuplicatePackageVersion:
+			apiError(ctx, http.StatusConflict, err)
+		case packages_service.ErrQuotaTotalCount, packages_service.ErrQuotaTypeSize, packages_service.ErrQuotaTotalSize:
+			apiError(ctx, http.StatusForbidden, err)
+		default:
+			apiError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusCreated)
+}
+
+// DeleteModule deletes a module version
+func DeleteModule(ctx *context.Context) {
+	deletePackageVersion(ctx, terraform_module.ModulePackageName(ctx.PathParam("name"), ctx.PathParam("system")))
+}
+
+type providerPlatform struct {
+	OS   string `json:"os"`
+	Arch string `json:"arch"`
+}
+
+type providerVersion struct {
+	Version   string              `json:"version"`
+	Protocols []string            `json:"protocols"`
+	Platforms []*providerPlatform `json:"platforms"`
+}
+
+func protocols(pd *packages_model.PackageDescriptor) []string {
+	if metadata, ok := pd.Metadata.(*terraform_module.Metadata); ok && len(metadata.Protocols) > 0 {
+		return metadata.Protocols
+	}
+	return []string{terraform_module.DefaultProtocol}
+}
+
+// EnumerateProviderVersions lists the available versions of a provider with their platforms
+func EnumerateProviderVersions(ctx *context.Context) {
+	pds, err := getPackageDescriptors(ctx, ctx.PathParam("provider"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			apiError(ctx, http.StatusNotFound, err)
+		} else {
+			apiError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	versions := make([]*providerVersion, 0, len(pds))
+	for _, pd := range pds {
+		platforms := make([]*providerPlatform, 0, len(pd.Files))
+		for _, pfd := range pd.Files {
+			if os := pfd.Properties.GetByName(terraform_module.PropertyOS); os != "" {
+				platforms = append(platforms, &providerPlatform{
+					OS:   os,
+					Arch: pfd.Properties.GetByName(terraform_module.PropertyArch),
+				})
+			}
+		}
+
+		versions = append(versions, &providerVersion{
+			Version:   pd.Version.Version,
+			Protocols: protocols(pd),
+			Platforms: platforms,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"versions": versions,
+	})
+}
+
+type gpgPublicKey struct {
+	KeyID      string `json:"key_id"`
+	ASCIIArmor string `json:"ascii_armor"`
+}
+
+type providerPackage struct {
+	Protocols           []string `json:"protocols"`
+	OS                  string   `json:"os"`
+	Arch                string   `json:"arch"`
+	Filename            string   `json:"filename"`
+	DownloadURL         string   `json:"download_url"`
+	ShasumsURL          string   `json:"shasums_url"`
+	ShasumsSignatureURL string   `json:"shasums_signature_url"`
+	Shasum              string   `json:"shasum"`
+	SigningKeys         struct {
+		GPGPublicKeys []*gpgPublicKey `json:"gpg_public_keys"`
+	} `json:"signing_keys"`
+}
+
+// providerFiles maps the files of a provider version to their type
+func providerFiles(pd *packages_model.PackageDescriptor) map[terraform_module.ProviderFileType][]*packages_model.PackageFileDescriptor {
+	files := make(map[terraform_module.ProviderFileType][]*packages_model.PackageFileDescriptor)
+	for _, pfd := range pd.Files {
+		f, err := terraform_module.ParseProviderFilename(pd.Package.Name, pd.Version.Version, pfd.File.Name)
+		if err != nil {
+			continue
+		}
+		files[f.Type] = append(files[f.Type], pfd)
+	}
+	return files
+}
+
+func readBlob(pb *packages_model.PackageBlob) (string, error) {
+	s, err := packages_service.OpenBlobStream(pb)
+	if err != nil {
+		return "", err
+	}
+	defer s.Close()
+
+	data, err := io.ReadAll(s)
+	return string(data), err
+}
+
+// DownloadProvider returns the download information of the provider package for a platform.
+// Terraform requires the checksums, the signature and the signing key to install it.
+func DownloadProvider(ctx *context.Context) {
+	pv, err := packages_model.GetVersionByNameAndVersion(ctx, ctx.Package.Owner.ID, packages_model.TypeTerraform, ctx.PathParam("provider"), ctx.PathParam("version"))
+	if err != nil {
+		if errors.Is(err, packages_model.ErrPackageNotExist) {
+			apiError(ctx, http.StatusNotFound, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	pd, err := packages_model.GetPackageDescriptor(ctx, pv)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	files := providerFiles(pd)
+
+	var archive *packages_model.PackageFileDescriptor
+	for _, pfd := range files[terraform_module.ProviderFileArchive] {
+		if pfd.Properties.GetByName(terraform_module.PropertyOS) == ctx.PathParam("os") && pfd.Properties.GetByName(terraform_module.PropertyArch) == ctx.PathParam("arch") {
+			archive = pfd
+			break
+		}
+	}
+	if archive == nil {
+		apiError(ctx, http.StatusNotFound, packages_model.ErrPackageFileNotExist)
+		return
+	}
+	if len(files[terraform_module.ProviderFileChecksums]) == 0 || len(files[terraform_module.ProviderFileSignature]) == 0 || len(files[terraform_module.ProviderFileSigningKey]) == 0 {
+		apiError(ctx, http.StatusNotFound, errors.New("provider version is not signed"))
+		return
+	}
+
+	key := files[terraform_module.ProviderFileSigningKey][0]
+	armored, err := readBlob(key.Blob)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	versionURL := fmt.Sprintf("%s/providers/%s/%s", packageURL(ctx), url.PathEscape(pd.Package.Name), url.PathEscape(pd.Version.Version))
+
+	p := &providerPackage{
+		Protocols:           protocols(pd),
+		OS:                  archive.Properties.GetByName(terraform_module.PropertyOS),
+		Arch:                archive.Properties.GetByName(terraform_module.PropertyArch),
+		Filename:            archive.File.Name,
+		DownloadURL:         versionURL + "/" + url.PathEscape(archive.File.Name),
+		ShasumsURL:          versionURL + "/" + url.PathEscape(files[terraform_module.ProviderFileChecksums][0].File.Name),
+		ShasumsSignatureURL: versionURL + "/" + url.PathEscape(files[terraform_module.ProviderFileSignature][0].File.Name),
+		Shasum:              archive.Blob.HashSHA256,
+	}
+	p.SigningKeys.GPGPublicKeys = []*gpgPublicKey{
+		{
+			KeyID:      key.Properties.GetByName(terraform_module.PropertyKeyID),
+			ASCIIArmor: armored,
+		},
+	}
+
+	ctx.JSON(http.StatusOK, p)
+}
+
+// DownloadProviderFile serves a file of a provider version
+func DownloadProviderFile(ctx *context.Context) {
+	s, u, pf, err := packages_service.OpenFileForDownloadByPackageNameAndVersion(
+		ctx,
+		&packages_service.PackageInfo{
+			Owner:       ctx.Package.Owner,
+			PackageType: packages_model.TypeTerraform,
+			Name:        ctx.PathParam("provider"),
+			Version:     ctx.PathParam("version"),
+		},
+		&packages_service.PackageFileInfo{
+			Filename: ctx.PathParam("filename"),
+		},
+		ctx.Req.Method,
+	)
+	if err != nil {
+		if errors.Is(err, packages_model.ErrPackageNotExist) || errors.Is(err, packages_model.ErrPackageFileNotExist) {
+			apiError(ctx, http.StatusNotFound, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	helper.ServePackageFile(ctx, s, u, pf)
+}
+
+// verifyProviderSignature verifies the signature of the checksums once the checksums, the signature and the signing key are uploaded.
+// The uploaded file is passed as it is not stored yet.
+func verifyProviderSignature(ctx *context.Context, providerType, packageVersion string, uploaded *terraform_module.ProviderFile, buf *packages_module.HashedBuffer) error {
+	contents := make(map[terraform_module.ProviderFileType]string, 3)
+
+	data, err := io.ReadAll(buf)
+	if err != nil {
+		return err
+	}
+	if _, err := buf.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	contents[uploaded.Type] = string(data)
+
+	pv, err := packages_model.GetVersionByNameAndVersion(ctx, ctx.Package.Owner.ID, packages_model.TypeTerraform, providerType, packageVersion)
+	if err != nil {
+		if errors.Is(err, packages_model.ErrPackageNotExist) {
+			return nil
+		}
+		return err
+	}
+	pd, err := packages_model.GetPackageDescriptor(ctx, pv)
+	if err != nil {
+		return err
+	}
+
+	for t, pfds := range providerFiles(pd) {
+		if t != uploaded.Type && (t == terraform_module.ProviderFileChecksums || t == terraform_module.ProviderFileSignature || t == terraform_module.ProviderFileSigningKey) {
+			if contents[t], err = readBlob(pfds[0].Blob); err != nil {
+				return err
+			}
+		}
+	}
+
+	checksums, hasChecksums := contents[terraform_module.ProviderFileChecksums]
+	signature, hasSignature := contents[terraform_module.ProviderFileSignature]
+	key, hasKey := contents[terraform_module.ProviderFileSigningKey]
+	if !hasChecksums || !hasSignature || !hasKey {
+		return nil
+	}
+
+	return terraform_module.VerifySignature(key, strings.NewReader(checksums), strings.NewReader(signature))
+}
+
+// UploadProviderFile adds a file to a provider version.
+// The filename determines if it is the package of a platform, the checksums, the signature, the signing key or the manifest.
+func UploadProviderFile(ctx *context.Context) {
+	providerType := ctx.PathParam("provider")
+	packageVersion := ctx.PathParam("version")
+
+	if !terraform_module.IsValidProviderType(providerType) {
+		apiError(ctx, http.StatusBadRequest, terraform_module.ErrInvalidName)
+		return
+	}
+	if !terraform_module.IsValidVersion(packageVersion) {
+		apiError(ctx, http.StatusBadRequest, terraform_module.ErrInvalidVersion)
+		return
+	}
+
+	file, err := terraform_module.ParseProviderFilename(providerType, packageVersion, ctx.PathParam("filename"))
+	if err != nil {
+		apiError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	upload, needToClose, err := ctx.UploadStream()
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if needToClose {
+		defer upload.Close()
+	}
+
+	buf, err := packages_module.CreateHashedBufferFromReader(upload)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	defer buf.Close()
+
+	properties := map[string]string{}
+	var protocols []string
+
+	switch file.Type {
+	case terraform_module.ProviderFileArchive:
+		properties[terraform_module.PropertyOS] = file.OS
+		properties[terraform_module.PropertyArch] = file.Arch
+	case terraform_module.ProviderFileManifest:
+		protocols, err = terraform_module.ParseProviderManifest(buf)
+	case terraform_module.ProviderFileSigningKey:
+		properties[terraform_module.PropertyKeyID], err = terraform_module.ParseSigningKey(buf)
+	}
+	if err != nil {
+		apiError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if _, err := buf.Seek(0, io.SeekStart); err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	switch file.Type {
+	case terraform_module.ProviderFileChecksums, terraform_module.ProviderFileSignature, terraform_module.ProviderFileSigningKey:
+		if err := verifyProviderSignature(ctx, providerType, packageVersion, file, buf); err != nil {
+			if errors.Is(err, util.ErrInvalidArgument) {
+				apiError(ctx, http.StatusBadRequest, err)
+			} else {
+				apiError(ctx, http.StatusInternalServerError, err)
+			}
+			return
+		}
+	}
+
+	pv, _, err := packages_service.CreatePackageOrAddFileToExisting(
+		ctx,
+		&packages_service.PackageCreationInfo{
+			PackageInfo: packages_service.PackageInfo{
+				Owner:       ctx.Package.Owner,
+				PackageType: packages_model.TypeTerraform,
+				Name:        providerType,
+				Version:     packageVersion,
+			},
+			SemverCompatible: true,
+			Creator:          ctx.Doer,
+			Metadata: &terraform_module.Metadata{
+				Kind:      terraform_module.KindProvider,
+				Protocols: protocols,
+			},
+		},
+		&packages_service.PackageFileCreationInfo{
+			PackageFileInfo: packages_service.PackageFileInfo{
+				Filename: ctx.PathParam("filename"),
+			},
+			Creator:    ctx.Doer,
+			Data:       buf,
+			IsLead:     file.Type == terraform_module.ProviderFileArchive,
+			Properties: properties,
+		},
+	)
+	if err != nil {
+		switch err {
+		case packages_model.ErrDuplicatePackageFile:
+			apiError(ctx, http.StatusConflict, err)
+		case packages_service.ErrQuotaTotalCount, packages_service.ErrQuotaTypeSize, packages_service.ErrQuotaTotalSize:
+			apiError(ctx, http.StatusForbidden, err)
+		default:
+			apiError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	// the manifest may be uploaded after the version was created by another file
+	if protocols != nil {
+		metadata := &terraform_module.Metadata{}
+		if err := json.Unmarshal([]byte(pv.MetadataJSON), metadata); err != nil {
+			apiError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		metadata.Protocols = protocols
+
+		raw, err := json.Marshal(metadata)
+		if err != nil {
+			apiError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		pv.MetadataJSON = string(raw)
+		if err := packages_model.UpdateVersion(ctx, pv); err != nil {
+			apiError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	ctx.Status(http.StatusCreated)
+}
+
+// DeleteProvider deletes a provider version
+func DeleteProvider(ctx *context.Context) {
+	deletePackageVersion(ctx, ctx.PathParam("provider"))
+}
+
+func deletePackageVersion(ctx *context.Context, packageName string) {
+	err := packages_service.RemovePackageVersionByNameAndVersion(
+		ctx,
+		ctx.Doer,
+		&packages_service.PackageInfo{
+			Owner:       ctx.Package.Owner,
+			PackageType: packages_model.TypeTerraform,
+			Name:        packageName,
+			Version:     ctx.PathParam("version"),
+		},
+	)
+	if err != nil {
+		if errors.Is(err, packages_model.ErrPackageNotExist) {
+			apiError(ctx, http.StatusNotFound, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -91,3 +91,59 @@ func ListWorkflowRuns(ctx *context.APIContext) {
 
 	shared.ListRuns(ctx, 0, 0)
 }
+
+// GetActionsUsageQuota gets the monthly usage quota of the jobs of a user or an organization
+func GetActionsUsageQuota(ctx *context.APIContext) {
+	// swagger:operation GET /admin/users/{username}/actions/usage-quota admin adminGetActionUsageQuota
+	// ---
+	// summary: Get the monthly usage quota of the jobs in the repositories of a user or an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of the user or the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionUsageQuota"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.GetUsageQuota(ctx, ctx.ContextUser.ID)
+}
+
+// UpdateActionsUsageQuota updates the monthly usage quota of the jobs of a user or an organization
+func UpdateActionsUsageQuota(ctx *context.APIContext) {
+	// swagger:operation PUT /admin/users/{username}/actions/usage-quota admin adminUpdateActionUsageQuota
+	// ---
+	// summary: Update the monthly usage quota of the jobs in the repositories of a user or an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of the user or the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditActionUsageQuotaOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionUsageQuota"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.UpdateUsageQuota(ctx, ctx.ContextUser.ID)
+}
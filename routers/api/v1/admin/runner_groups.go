@@ -0,0 +1,219 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"code.gitea.io/gitea/routers/api/v1/shared"
+	"code.gitea.io/gitea/services/context"
+)
+
+// ListRunnerGroups list the instance-level runner groups
+func ListRunnerGroups(ctx *context.APIContext) {
+	// swagger:operation GET /admin/actions/runner-groups admin adminListRunnerGroups
+	// ---
+	// summary: List the instance-level runner groups
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunnerGroupList"
+
+	shared.ListRunnerGroups(ctx, 0)
+}
+
+// CreateRunnerGroup create an instance-level runner group
+func CreateRunnerGroup(ctx *context.APIContext) {
+	// swagger:operation POST /admin/actions/runner-groups admin adminCreateRunnerGroup
+	// ---
+	// summary: Create an instance-level runner group
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateActionRunnerGroupOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ActionRunnerGroup"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+
+	shared.CreateRunnerGroup(ctx, 0)
+}
+
+// GetRunnerGroup get an instance-level runner group
+func GetRunnerGroup(ctx *context.APIContext) {
+	// swagger:operation GET /admin/actions/runner-groups/{group_id} admin adminGetRunnerGroup
+	// ---
+	// summary: Get an instance-level runner group
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunnerGroup"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.GetRunnerGroup(ctx, 0, ctx.PathParamInt64("group_id"))
+}
+
+// EditRunnerGroup edit an instance-level runner group
+func EditRunnerGroup(ctx *context.APIContext) {
+	// swagger:operation PATCH /admin/actions/runner-groups/{group_id} admin adminEditRunnerGroup
+	// ---
+	// summary: Edit an instance-level runner group
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditActionRunnerGroupOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunnerGroup"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+
+	shared.EditRunnerGroup(ctx, 0, ctx.PathParamInt64("group_id"))
+}
+
+// DeleteRunnerGroup delete an instance-level runner group, its runners don't belong to any group after that
+func DeleteRunnerGroup(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/actions/runner-groups/{group_id} admin adminDeleteRunnerGroup
+	// ---
+	// summary: Delete an instance-level runner group, its runners don't belong to any group after that
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "204":
+	//     description: "No Content"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.DeleteRunnerGroup(ctx, 0, ctx.PathParamInt64("group_id"))
+}
+
+// ListRunnerGroupRunners list the runners of an instance-level runner group
+func ListRunnerGroupRunners(ctx *context.APIContext) {
+	// swagger:operation GET /admin/actions/runner-groups/{group_id}/runners admin adminListRunnerGroupRunners
+	// ---
+	// summary: List the runners of an instance-level runner group
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/definitions/ActionRunnersResponse"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.ListRunnerGroupRunners(ctx, 0, ctx.PathParamInt64("group_id"))
+}
+
+// AddRunnerGroupRunner add an instance-level runner to a runner group, the runner is removed from its previous group
+func AddRunnerGroupRunner(ctx *context.APIContext) {
+	// swagger:operation PUT /admin/actions/runner-groups/{group_id}/runners/{runner_id} admin adminAddRunnerGroupRunner
+	// ---
+	// summary: Add an instance-level runner to a runner group, the runner is removed from its previous group
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   required: true
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "204":
+	//     description: "No Content"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.AddRunnerGroupRunner(ctx, 0, ctx.PathParamInt64("group_id"), ctx.PathParamInt64("runner_id"))
+}
+
+// RemoveRunnerGroupRunner remove a runner from an instance-level runner group
+func RemoveRunnerGroupRunner(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/actions/runner-groups/{group_id}/runners/{runner_id} admin adminRemoveRunnerGroupRunner
+	// ---
+	// summary: Remove a runner from an instance-level runner group
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   required: true
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "204":
+	//     description: "No Content"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.RemoveRunnerGroupRunner(ctx, 0, ctx.PathParamInt64("group_id"), ctx.PathParamInt64("runner_id"))
+}
@@ -200,17 +200,11 @@ func repoAssignment() func(ctx *context.APIContext) {
 				return
 			}
 
-			if task.IsForkPullRequest {
-				ctx.Repo.Permission.AccessMode = perm.AccessModeRead
-			} else {
-				ctx.Repo.Permission.AccessMode = perm.AccessModeWrite
-			}
-
-			if err := ctx.Repo.Repository.LoadUnits(ctx); err != nil {
+			ctx.Repo.Permission, err = access_model.GetActionsUserRepoPermission(ctx, repo, task)
+			if err != nil {
 				ctx.APIErrorInternal(err)
 				return
 			}
-			ctx.Repo.Permission.SetUnitsWithDefaultAccessMode(ctx.Repo.Repository.Units, ctx.Repo.Permission.AccessMode)
 		} else {
 			needTwoFactor, err := doerNeedTwoFactorAuth(ctx, ctx.Doer)
 			if err != nil {
@@ -943,6 +937,16 @@ func Routes() *web.Router {
 			})
 			m.Get("/runs", reqToken(), reqChecker, act.ListWorkflowRuns)
 			m.Get("/jobs", reqToken(), reqChecker, act.ListWorkflowJobs)
+			m.Combo("/permissions/workflow").
+				Get(reqToken(), reqChecker, act.GetWorkflowPermissions).
+				Put(reqToken(), reqChecker, bind(api.EditActionWorkflowPermissionsOption{}), act.UpdateWorkflowPermissions)
+			m.Combo("/permissions/fork-pr-contributor-approval").
+				Get(reqToken(), reqChecker, act.GetForkPRContributorApproval).
+				Put(reqToken(), reqChecker, bind(api.EditActionForkPRContributorApprovalOption{}), act.UpdateForkPRContributorApproval)
+			m.Combo("/permissions/log-retention").
+				Get(reqToken(), reqChecker, act.GetLogRetention).
+				Put(reqToken(), reqChecker, bind(api.EditActionLogRetentionOption{}), act.UpdateLogRetention)
+			m.Get("/usage", reqToken(), reqChecker, act.GetUsage)
 		})
 	}
 
@@ -1082,6 +1086,7 @@ func Routes() *web.Router {
 
 				m.Get("/runs", reqToken(), user.ListWorkflowRuns)
 				m.Get("/jobs", reqToken(), user.ListWorkflowJobs)
+				m.Get("/usage", reqToken(), user.GetActionsUsage)
 			})
 
 			m.Get("/followers", user.ListMyFollowers)
@@ -1205,11 +1210,40 @@ func Routes() *web.Router {
 					m.Post("/{workflow_id}/dispatches", reqRepoWriter(unit.TypeActions), bind(api.CreateActionWorkflowDispatch{}), repo.ActionsDispatchWorkflow)
 				}, context.ReferencesGitRepo(), reqToken(), reqRepoReader(unit.TypeActions))
 
+				m.Post("/dispatches", reqToken(), reqRepoWriter(unit.TypeActions), bind(api.CreateRepositoryDispatchOption{}), repo.CreateRepositoryDispatch)
+
+				// the permission is checked by the token of the job, it doesn't need to read the actions of the repository
+				m.Post("/actions/attestations", reqToken(), bind(api.CreateActionAttestationOption{}), repo.CreateActionAttestation)
+
 				m.Group("/actions/jobs", func() {
 					m.Get("/{job_id}", repo.GetWorkflowJob)
 					m.Get("/{job_id}/logs", repo.DownloadActionsRunJobLogs)
+					m.Get("/{job_id}/logs/stream", repo.StreamActionsRunJobLogs)
 				}, reqToken(), reqRepoReader(unit.TypeActions))
 
+				m.Group("/environments", func() {
+					m.Get("", repo.ListEnvironments)
+					m.Group("/{environment_name}", func() {
+						m.Combo("").Get(repo.GetEnvironment).
+							Put(bind(api.CreateOrUpdateEnvironmentOption{}), repo.CreateOrUpdateEnvironment).
+							Delete(repo.DeleteEnvironment)
+						m.Group("/secrets", func() {
+							m.Get("", repo.ListEnvironmentSecrets)
+							m.Combo("/{secretname}").
+								Put(bind(api.CreateOrUpdateSecretOption{}), repo.CreateOrUpdateEnvironmentSecret).
+								Delete(repo.DeleteEnvironmentSecret)
+						})
+						m.Group("/variables", func() {
+							m.Get("", repo.ListEnvironmentVariables)
+							m.Combo("/{variablename}").
+								Get(repo.GetEnvironmentVariable).
+								Delete(repo.DeleteEnvironmentVariable).
+								Post(bind(api.CreateVariableOption{}), repo.CreateEnvironmentVariable).
+								Put(bind(api.UpdateVariableOption{}), repo.UpdateEnvironmentVariable)
+						})
+					})
+				}, reqToken(), reqAdmin())
+
 				m.Group("/hooks/git", func() {
 					m.Combo("").Get(repo.ListGitHooks)
 					m.Group("/{id}", func() {
@@ -1285,12 +1319,34 @@ func Routes() *web.Router {
 				}, reqToken(), reqAdmin())
 				m.Group("/actions", func() {
 					m.Get("/tasks", repo.ListActionTasks)
+					m.Get("/schedules", repo.ListActionSchedules)
+					m.Group("/attestations", func() {
+						m.Get("", repo.ListActionAttestations)
+						m.Post("/verify", bind(api.VerifyActionAttestationOption{}), repo.VerifyActionAttestation)
+						m.Get("/{attestation_id}", repo.GetActionAttestation)
+					})
 					m.Group("/runs", func() {
+						m.Post("/approvals", reqToken(), reqRepoWriter(unit.TypeActions), bind(api.ReviewActionRunsOption{}), repo.ReviewActionRuns)
 						m.Group("/{run}", func() {
 							m.Get("", repo.GetWorkflowRun)
 							m.Delete("", reqToken(), reqRepoWriter(unit.TypeActions), repo.DeleteActionRun)
+							m.Group("", func() {
+								m.Post("/cancel", repo.CancelWorkflowRun)
+								m.Post("/rerun", bind(api.RerunActionRunOption{}), repo.RerunWorkflowRun)
+								m.Post("/rerun-failed-jobs", bind(api.RerunActionRunOption{}), repo.RerunFailedWorkflowRunJobs)
+								m.Post("/jobs/{job_id}/rerun", bind(api.RerunActionRunOption{}), repo.RerunWorkflowRunJob)
+								m.Post("/approve", repo.ApproveWorkflowRun)
+							}, reqToken(), reqRepoWriter(unit.TypeActions))
 							m.Get("/jobs", repo.ListWorkflowRunJobs)
+							m.Get("/summaries", repo.ListWorkflowRunStepSummaries)
+							m.Group("/attempts/{attempt}", func() {
+								m.Get("", repo.GetWorkflowRunAttempt)
+								m.Get("/jobs", repo.ListWorkflowRunAttemptJobs)
+								m.Get("/artifacts", repo.ListWorkflowRunAttemptArtifacts)
+							})
 							m.Get("/artifacts", repo.GetArtifactsOfRun)
+							m.Combo("/pending_deployments").Get(repo.ListPendingDeployments).
+								Post(reqToken(), bind(api.ReviewPendingDeploymentsOption{}), repo.ReviewPendingDeployments)
 						})
 					})
 					m.Get("/artifacts", repo.GetArtifacts)
@@ -1604,6 +1660,28 @@ func Routes() *web.Router {
 
 		// NOTE: these are Gitea package management API - see packages.CommonRoutes and packages.DockerContainerRoutes for endpoints that implement package manager APIs
 		m.Group("/packages/{username}", func() {
+			m.Group("/-/remotes", func() {
+				m.Get("", packages.ListPackageRemotes)
+				m.Combo("/{type}").
+					Get(packages.GetPackageRemote).
+					Put(bind(api.SetPackageRemoteOption{}), packages.SetPackageRemote).
+					Delete(packages.DeletePackageRemote)
+			}, reqPackageAccess(perm.AccessModeAdmin))
+
+			m.Group("/-/cleanup-rules", func() {
+				m.Combo("").
+					Get(packages.ListPackageCleanupRules).
+					Post(bind(api.CreatePackageCleanupRuleOption{}), packages.CreatePackageCleanupRule)
+				m.Group("/{id}", func() {
+					m.Combo("").
+						Get(packages.GetPackageCleanupRule).
+						Patch(bind(api.EditPackageCleanupRuleOption{}), packages.EditPackageCleanupRule).
+						Delete(packages.DeletePackageCleanupRule)
+					m.Get("/preview", packages.PreviewPackageCleanupRule)
+					m.Get("/history", packages.ListPackageCleanupRuleHistory)
+				})
+			}, reqPackageAccess(perm.AccessModeAdmin))
+
 			m.Group("/{type}/{name}", func() {
 				m.Get("/", packages.ListPackageVersions)
 
@@ -1611,6 +1689,7 @@ func Routes() *web.Router {
 					m.Get("", packages.GetPackage)
 					m.Delete("", reqPackageAccess(perm.AccessModeWrite), packages.DeletePackage)
 					m.Get("/files", packages.ListPackageFiles)
+					m.Get("/attestations", packages.ListPackageAttestations)
 				})
 
 				m.Group("/-", func() {
@@ -1648,6 +1727,18 @@ func Routes() *web.Router {
 				reqOrgOwnership(),
 				org.NewAction(),
 			)
+			m.Group("/actions/runner-groups", func() {
+				m.Combo("").Get(org.ListRunnerGroups).
+					Post(bind(api.CreateActionRunnerGroupOption{}), org.CreateRunnerGroup)
+				m.Group("/{group_id}", func() {
+					m.Combo("").Get(org.GetRunnerGroup).
+						Patch(bind(api.EditActionRunnerGroupOption{}), org.EditRunnerGroup).
+						Delete(org.DeleteRunnerGroup)
+					m.Get("/runners", org.ListRunnerGroupRunners)
+					m.Combo("/runners/{runner_id}").Put(org.AddRunnerGroupRunner).
+						Delete(org.RemoveRunnerGroupRunner)
+				})
+			}, reqToken(), reqOrgOwnership())
 			m.Group("/public_members", func() {
 				m.Get("", org.ListPublicMembers)
 				m.Combo("/{username}").Get(org.IsPublicMember).
@@ -1732,6 +1823,8 @@ func Routes() *web.Router {
 					m.Get("/badges", admin.ListUserBadges)
 					m.Post("/badges", bind(api.UserBadgeOption{}), admin.AddUserBadges)
 					m.Delete("/badges", bind(api.UserBadgeOption{}), admin.DeleteUserBadges)
+					m.Combo("/actions/usage-quota").Get(admin.GetActionsUsageQuota).
+						Put(bind(api.EditActionUsageQuotaOption{}), admin.UpdateActionsUsageQuota)
 				}, context.UserAssignmentAPI())
 			})
 			m.Group("/emails", func() {
@@ -1757,6 +1850,18 @@ func Routes() *web.Router {
 					m.Get("/{runner_id}", admin.GetRunner)
 					m.Delete("/{runner_id}", admin.DeleteRunner)
 				})
+				m.Group("/runner-groups", func() {
+					m.Combo("").Get(admin.ListRunnerGroups).
+						Post(bind(api.CreateActionRunnerGroupOption{}), admin.CreateRunnerGroup)
+					m.Group("/{group_id}", func() {
+						m.Combo("").Get(admin.GetRunnerGroup).
+							Patch(bind(api.EditActionRunnerGroupOption{}), admin.EditRunnerGroup).
+							Delete(admin.DeleteRunnerGroup)
+						m.Get("/runners", admin.ListRunnerGroupRunners)
+						m.Combo("/runners/{runner_id}").Put(admin.AddRunnerGroupRunner).
+							Delete(admin.RemoveRunnerGroupRunner)
+					})
+				})
 				m.Get("/runs", admin.ListWorkflowRuns)
 				m.Get("/jobs", admin.ListWorkflowJobs)
 			})
@@ -660,6 +660,196 @@ func (Action) ListWorkflowRuns(ctx *context.APIContext) {
 	shared.ListRuns(ctx, ctx.Org.Organization.ID, 0)
 }
 
+// GetWorkflowPermissions gets the default permissions of the tokens of the workflow jobs of an organization
+func (Action) GetWorkflowPermissions(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/actions/permissions/workflow organization getOrgWorkflowPermissions
+	// ---
+	// summary: Get the default permissions of the tokens of the workflow jobs of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionWorkflowPermissions"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.GetWorkflowPermissions(ctx, ctx.Org.Organization.ID, nil)
+}
+
+// UpdateWorkflowPermissions updates the default permissions of the tokens of the workflow jobs of an organization
+func (Action) UpdateWorkflowPermissions(ctx *context.APIContext) {
+	// swagger:operation PUT /orgs/{org}/actions/permissions/workflow organization updateOrgWorkflowPermissions
+	// ---
+	// summary: Update the default permissions of the tokens of the workflow jobs of an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditActionWorkflowPermissionsOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionWorkflowPermissions"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.UpdateWorkflowPermissions(ctx, ctx.Org.Organization.ID, nil)
+}
+
+// GetForkPRContributorApproval gets the approval policy of the runs from forks in the repositories of an organization
+func (Action) GetForkPRContributorApproval(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/actions/permissions/fork-pr-contributor-approval organization getOrgForkPRContributorApproval
+	// ---
+	// summary: Get the approval policy of the workflow runs triggered by the pull requests from forks in the repositories of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionForkPRContributorApproval"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.GetForkPRContributorApproval(ctx, ctx.Org.Organization.ID, nil)
+}
+
+// UpdateForkPRContributorApproval updates the approval policy of the runs from forks in the repositories of an organization
+func (Action) UpdateForkPRContributorApproval(ctx *context.APIContext) {
+	// swagger:operation PUT /orgs/{org}/actions/permissions/fork-pr-contributor-approval organization updateOrgForkPRContributorApproval
+	// ---
+	// summary: Update the approval policy of the workflow runs triggered by the pull requests from forks in the repositories of an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditActionForkPRContributorApprovalOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionForkPRContributorApproval"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.UpdateForkPRContributorApproval(ctx, ctx.Org.Organization.ID, nil)
+}
+
+// GetLogRetention gets the retention period of the job logs in the repositories of an organization
+func (Action) GetLogRetention(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/actions/permissions/log-retention organization getOrgActionLogRetention
+	// ---
+	// summary: Get the retention period of the job logs in the repositories of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionLogRetention"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.GetLogRetention(ctx, ctx.Org.Organization.ID, nil)
+}
+
+// UpdateLogRetention updates the retention period of the job logs in the repositories of an organization
+func (Action) UpdateLogRetention(ctx *context.APIContext) {
+	// swagger:operation PUT /orgs/{org}/actions/permissions/log-retention organization updateOrgActionLogRetention
+	// ---
+	// summary: Update the retention period of the job logs in the repositories of an organization, it can't be longer than the retention period of the instance
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditActionLogRetentionOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionLogRetention"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.UpdateLogRetention(ctx, ctx.Org.Organization.ID, nil)
+}
+
+// GetUsage gets the monthly usage report of the jobs in the repositories of an organization
+func (Action) GetUsage(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/actions/usage organization getOrgActionUsage
+	// ---
+	// summary: Get the monthly usage report of the jobs in the repositories of an organization
+	// produces:
+	// - application/json
+	// - text/csv
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: month
+	//   in: query
+	//   description: the month of the report in UTC, like 2006-01, the default is the current month
+	//   type: string
+	// - name: format
+	//   in: query
+	//   description: the format of the report
+	//   type: string
+	//   enum: [json, csv]
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionUsageReport"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.GetUsage(ctx, ctx.Org.Organization.ID, nil)
+}
+
 var _ actions_service.API = new(Action)
 
 // Action implements actions_service.API
@@ -0,0 +1,259 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"code.gitea.io/gitea/routers/api/v1/shared"
+	"code.gitea.io/gitea/services/context"
+)
+
+// ListRunnerGroups list the org-level runner groups
+func ListRunnerGroups(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/actions/runner-groups organization orgListRunnerGroups
+	// ---
+	// summary: List the org-level runner groups
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunnerGroupList"
+
+	shared.ListRunnerGroups(ctx, ctx.Org.Organization.ID)
+}
+
+// CreateRunnerGroup create an org-level runner group
+func CreateRunnerGroup(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/actions/runner-groups organization orgCreateRunnerGroup
+	// ---
+	// summary: Create an org-level runner group
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateActionRunnerGroupOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ActionRunnerGroup"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+
+	shared.CreateRunnerGroup(ctx, ctx.Org.Organization.ID)
+}
+
+// GetRunnerGroup get an org-level runner group
+func GetRunnerGroup(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/actions/runner-groups/{group_id} organization orgGetRunnerGroup
+	// ---
+	// summary: Get an org-level runner group
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunnerGroup"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.GetRunnerGroup(ctx, ctx.Org.Organization.ID, ctx.PathParamInt64("group_id"))
+}
+
+// EditRunnerGroup edit an org-level runner group
+func EditRunnerGroup(ctx *context.APIContext) {
+	// swagger:operation PATCH /orgs/{org}/actions/runner-groups/{group_id} organization orgEditRunnerGroup
+	// ---
+	// summary: Edit an org-level runner group
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditActionRunnerGroupOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunnerGroup"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+
+	shared.EditRunnerGroup(ctx, ctx.Org.Organization.ID, ctx.PathParamInt64("group_id"))
+}
+
+// DeleteRunnerGroup delete an org-level runner group, its runners don't belong to any group after that
+func DeleteRunnerGroup(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/actions/runner-groups/{group_id} organization orgDeleteRunnerGroup
+	// ---
+	// summary: Delete an org-level runner group, its runners don't belong to any group after that
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "204":
+	//     description: "No Content"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.DeleteRunnerGroup(ctx, ctx.Org.Organization.ID, ctx.PathParamInt64("group_id"))
+}
+
+// ListRunnerGroupRunners list the runners of an org-level runner group
+func ListRunnerGroupRunners(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/actions/runner-groups/{group_id}/runners organization orgListRunnerGroupRunners
+	// ---
+	// summary: List the runners of an org-level runner group
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/definitions/ActionRunnersResponse"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.ListRunnerGroupRunners(ctx, ctx.Org.Organization.ID, ctx.PathParamInt64("group_id"))
+}
+
+// AddRunnerGroupRunner add an org-level runner to a runner group, the runner is removed from its previous group
+func AddRunnerGroupRunner(ctx *context.APIContext) {
+	// swagger:operation PUT /orgs/{org}/actions/runner-groups/{group_id}/runners/{runner_id} organization orgAddRunnerGroupRunner
+	// ---
+	// summary: Add an org-level runner to a runner group, the runner is removed from its previous group
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   required: true
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "204":
+	//     description: "No Content"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.AddRunnerGroupRunner(ctx, ctx.Org.Organization.ID, ctx.PathParamInt64("group_id"), ctx.PathParamInt64("runner_id"))
+}
+
+// RemoveRunnerGroupRunner remove a runner from an org-level runner group
+func RemoveRunnerGroupRunner(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/actions/runner-groups/{group_id}/runners/{runner_id} organization orgRemoveRunnerGroupRunner
+	// ---
+	// summary: Remove a runner from an org-level runner group
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   required: true
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "204":
+	//     description: "No Content"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.RemoveRunnerGroupRunner(ctx, ctx.Org.Organization.ID, ctx.PathParamInt64("group_id"), ctx.PathParamInt64("runner_id"))
+}
@@ -0,0 +1,403 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"errors"
+	"net/http"
+
+	"code.gitea.io/gitea/models/packages"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+	cleanup_service "code.gitea.io/gitea/services/packages/cleanup"
+)
+
+// ListPackageCleanupRules gets the cleanup rules of an owner
+func ListPackageCleanupRules(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/-/cleanup-rules package listPackageCleanupRules
+	// ---
+	// summary: Gets the cleanup rules of an owner
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageCleanupRuleList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pcrs, err := packages.GetCleanupRulesByOwner(ctx, ctx.ContextUser.ID)
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+
+	apiRules := make([]*api.PackageCleanupRule, 0, len(pcrs))
+	for _, pcr := range pcrs {
+		apiRules = append(apiRules, convert.ToPackageCleanupRule(pcr))
+	}
+
+	ctx.JSON(http.StatusOK, apiRules)
+}
+
+// CreatePackageCleanupRule creates a cleanup rule
+func CreatePackageCleanupRule(ctx *context.APIContext) {
+	// swagger:operation POST /packages/{owner}/-/cleanup-rules package createPackageCleanupRule
+	// ---
+	// summary: Creates a cleanup rule, an owner can have one rule per package type
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreatePackageCleanupRuleOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/PackageCleanupRule"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreatePackageCleanupRuleOption)
+
+	pcr, err := cleanup_service.CreateRule(ctx, &packages.PackageCleanupRule{
+		OwnerID:            ctx.ContextUser.ID,
+		Type:               packages.Type(form.Type),
+		Enabled:            form.Enabled,
+		KeepCount:          form.KeepCount,
+		KeepPattern:        form.KeepPattern,
+		KeepSemverRange:    form.KeepSemverRange,
+		KeepLatestPerMajor: form.KeepLatestPerMajor,
+		RemoveDays:         form.RemoveDays,
+		RemovePattern:      form.RemovePattern,
+		MatchFullName:      form.MatchFullName,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, util.ErrInvalidArgument):
+			ctx.APIError(http.StatusUnprocessableEntity, err)
+		case errors.Is(err, util.ErrAlreadyExist):
+			ctx.APIError(http.StatusConflict, err)
+		default:
+			ctx.APIErrorInternal(err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToPackageCleanupRule(pcr))
+}
+
+// GetPackageCleanupRule gets a cleanup rule
+func GetPackageCleanupRule(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/-/cleanup-rules/{id} package getPackageCleanupRule
+	// ---
+	// summary: Gets a cleanup rule
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the cleanup rule
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageCleanupRule"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pcr := getCleanupRuleFromContext(ctx)
+	if pcr == nil {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToPackageCleanupRule(pcr))
+}
+
+// EditPackageCleanupRule edits a cleanup rule
+func EditPackageCleanupRule(ctx *context.APIContext) {
+	// swagger:operation PATCH /packages/{owner}/-/cleanup-rules/{id} package editPackageCleanupRule
+	// ---
+	// summary: Edits a cleanup rule
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the cleanup rule
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditPackageCleanupRuleOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageCleanupRule"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	pcr := getCleanupRuleFromContext(ctx)
+	if pcr == nil {
+		return
+	}
+
+	form := web.GetForm(ctx).(*api.EditPackageCleanupRuleOption)
+
+	if form.Enabled != nil {
+		pcr.Enabled = *form.Enabled
+	}
+	if form.KeepCount != nil {
+		pcr.KeepCount = *form.KeepCount
+	}
+	if form.KeepPattern != nil {
+		pcr.KeepPattern = *form.KeepPattern
+	}
+	if form.KeepSemverRange != nil {
+		pcr.KeepSemverRange = *form.KeepSemverRange
+	}
+	if form.KeepLatestPerMajor != nil {
+		pcr.KeepLatestPerMajor = *form.KeepLatestPerMajor
+	}
+	if form.RemoveDays != nil {
+		pcr.RemoveDays = *form.RemoveDays
+	}
+	if form.RemovePattern != nil {
+		pcr.RemovePattern = *form.RemovePattern
+	}
+	if form.MatchFullName != nil {
+		pcr.MatchFullName = *form.MatchFullName
+	}
+
+	if err := cleanup_service.UpdateRule(ctx, pcr); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.APIError(http.StatusUnprocessableEntity, err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToPackageCleanupRule(pcr))
+}
+
+// DeletePackageCleanupRule deletes a cleanup rule
+func DeletePackageCleanupRule(ctx *context.APIContext) {
+	// swagger:operation DELETE /packages/{owner}/-/cleanup-rules/{id} package deletePackageCleanupRule
+	// ---
+	// summary: Deletes a cleanup rule and its history
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the cleanup rule
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pcr := getCleanupRuleFromContext(ctx)
+	if pcr == nil {
+		return
+	}
+
+	if err := packages.DeleteCleanupRuleByID(ctx, pcr.ID); err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// PreviewPackageCleanupRule gets the package versions a cleanup rule would remove
+func PreviewPackageCleanupRule(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/-/cleanup-rules/{id}/preview package previewPackageCleanupRule
+	// ---
+	// summary: Gets the package versions which would be removed if the cleanup rule is executed now
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the cleanup rule
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pcr := getCleanupRuleFromContext(ctx)
+	if pcr == nil {
+		return
+	}
+
+	pds, err := cleanup_service.PreviewCleanupRule(ctx, pcr)
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+
+	apiPackages := make([]*api.Package, 0, len(pds))
+	for _, pd := range pds {
+		apiPackage, err := convert.ToPackage(ctx, pd, ctx.Doer)
+		if err != nil {
+			ctx.APIErrorInternal(err)
+			return
+		}
+		apiPackages = append(apiPackages, apiPackage)
+	}
+
+	ctx.JSON(http.StatusOK, apiPackages)
+}
+
+// ListPackageCleanupRuleHistory gets the runs of a cleanup rule
+func ListPackageCleanupRuleHistory(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/-/cleanup-rules/{id}/history package listPackageCleanupRuleHistory
+	// ---
+	// summary: Gets the runs of a cleanup rule which removed package versions, the most recent run first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the cleanup rule
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageCleanupRunList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pcr := getCleanupRuleFromContext(ctx)
+	if pcr == nil {
+		return
+	}
+
+	listOptions := utils.GetListOptions(ctx)
+
+	runs, count, err := packages.GetCleanupRunsByRuleID(ctx, pcr.ID, &listOptions)
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+
+	runIDs := make([]int64, 0, len(runs))
+	for _, run := range runs {
+		runIDs = append(runIDs, run.ID)
+	}
+	versions, err := packages.GetCleanupRunVersionsByRunIDs(ctx, runIDs)
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+
+	apiRuns := make([]*api.PackageCleanupRun, 0, len(runs))
+	for _, run := range runs {
+		apiRuns = append(apiRuns, convert.ToPackageCleanupRun(run, versions[run.ID]))
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiRuns)
+}
+
+func getCleanupRuleFromContext(ctx *context.APIContext) *packages.PackageCleanupRule {
+	pcr, err := packages.GetCleanupRuleByID(ctx, ctx.PathParamInt64("id"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.APIErrorNotFound(err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+		return nil
+	}
+
+	if pcr.OwnerID != ctx.ContextUser.ID {
+		ctx.APIErrorNotFound(packages.ErrPackageCleanupRuleNotExist)
+		return nil
+	}
+
+	return pcr
+}
@@ -7,6 +7,8 @@ import (
 	"errors"
 	"net/http"
 
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/models/packages"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/modules/optional"
@@ -16,6 +18,7 @@ import (
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/convert"
 	packages_service "code.gitea.io/gitea/services/packages"
+	hex_service "code.gitea.io/gitea/services/packages/hex"
 )
 
 // ListPackages gets all packages of an owner
@@ -43,7 +46,7 @@ func ListPackages(ctx *context.APIContext) {
 	//   in: query
 	//   description: package type filter
 	//   type: string
-	//   enum: [alpine, cargo, chef, composer, conan, conda, container, cran, debian, generic, go, helm, maven, npm, nuget, pub, pypi, rpm, rubygems, swift, vagrant]
+	//   enum: [alpine, cargo, chef, composer, conan, conda, container, cran, debian, generic, go, helm, hex, maven, npm, nuget, pub, pypi, rpm, rubygems, swift, terraform, vagrant]
 	// - name: q
 	//   in: query
 	//   description: name filter
@@ -149,6 +152,10 @@ func DeletePackage(ctx *context.APIContext) {
 	//     "$ref": "#/responses/notFound"
 
 	err := packages_service.RemovePackageVersion(ctx, ctx.Doer, ctx.Package.Descriptor.Version)
+	if err == nil && ctx.Package.Descriptor.Package.Type == packages.TypeHex {
+		// the signed resources of the Hex registry must not list the deleted version
+		err = hex_service.BuildSpecificRepositoryFiles(ctx, ctx.Package.Owner.ID, ctx.Package.Descriptor.Package.Name)
+	}
 	if err != nil {
 		ctx.APIErrorInternal(err)
 		return
@@ -198,6 +205,61 @@ func ListPackageFiles(ctx *context.APIContext) {
 	ctx.JSON(http.StatusOK, apiPackageFiles)
 }
 
+// ListPackageAttestations gets the attestations attached to a package version
+func ListPackageAttestations(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/{type}/{name}/{version}/attestations package listPackageAttestations
+	// ---
+	// summary: Gets the build provenance attestations attached to a package version
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// - name: version
+	//   in: path
+	//   description: version of the package
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionAttestationList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	attestations, err := db.Find[actions_model.ActionAttestation](ctx, actions_model.FindAttestationsOptions{
+		PackageVersionID: ctx.Package.Descriptor.Version.ID,
+	})
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+
+	apiAttestations := make([]*api.ActionAttestation, 0, len(attestations))
+	for _, attest := range attestations {
+		apiAttestation, err := convert.ToActionAttestation(attest)
+		if err != nil {
+			ctx.APIErrorInternal(err)
+			return
+		}
+		apiAttestations = append(apiAttestations, apiAttestation)
+	}
+
+	ctx.JSON(http.StatusOK, apiAttestations)
+}
+
 // ListPackageVersions gets all versions of a package
 func ListPackageVersions(ctx *context.APIContext) {
 	// swagger:operation GET /packages/{owner}/{type}/{name} package listPackageVersions
@@ -0,0 +1,184 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"errors"
+	"net/http"
+
+	"code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/optional"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+	remote_service "code.gitea.io/gitea/services/packages/remote"
+)
+
+// ListPackageRemotes gets the remotes of an owner
+func ListPackageRemotes(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/-/remotes package listPackageRemotes
+	// ---
+	// summary: Gets the remote registries of an owner
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageRemoteList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	prs, err := packages.GetRemotesByOwner(ctx, ctx.ContextUser.ID)
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+
+	apiRemotes := make([]*api.PackageRemote, 0, len(prs))
+	for _, pr := range prs {
+		apiRemotes = append(apiRemotes, convert.ToPackageRemote(pr))
+	}
+
+	ctx.JSON(http.StatusOK, apiRemotes)
+}
+
+// GetPackageRemote gets the remote of a package type
+func GetPackageRemote(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/-/remotes/{type} package getPackageRemote
+	// ---
+	// summary: Gets the remote registry of a package type
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the packages
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageRemote"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr, err := packages.GetRemoteByOwnerAndType(ctx, ctx.ContextUser.ID, packages.Type(ctx.PathParam("type")))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.APIErrorNotFound(err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToPackageRemote(pr))
+}
+
+// SetPackageRemote creates or updates the remote of a package type
+func SetPackageRemote(ctx *context.APIContext) {
+	// swagger:operation PUT /packages/{owner}/-/remotes/{type} package setPackageRemote
+	// ---
+	// summary: Creates or updates the remote registry of a package type
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the packages
+	//   type: string
+	//   enum: [container, go, maven, npm, pypi]
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/SetPackageRemoteOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageRemote"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.SetPackageRemoteOption)
+
+	pr, err := remote_service.SetRemote(ctx, ctx.ContextUser, packages.Type(ctx.PathParam("type")), &remote_service.RemoteOptions{
+		URL:         form.URL,
+		Username:    form.Username,
+		Password:    optional.FromPtr(form.Password),
+		MetadataTTL: form.MetadataTTL,
+		Enabled:     form.Enabled,
+	})
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.APIError(http.StatusUnprocessableEntity, err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToPackageRemote(pr))
+}
+
+// DeletePackageRemote deletes the remote of a package type
+func DeletePackageRemote(ctx *context.APIContext) {
+	// swagger:operation DELETE /packages/{owner}/-/remotes/{type} package deletePackageRemote
+	// ---
+	// summary: Deletes the remote registry of a package type, the packages fetched from it are kept
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the packages
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := remote_service.DeleteRemote(ctx, ctx.ContextUser, packages.Type(ctx.PathParam("type"))); err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.APIErrorNotFound(err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -10,8 +10,10 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -26,6 +28,7 @@ import (
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
 	"code.gitea.io/gitea/routers/api/v1/shared"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	actions_service "code.gitea.io/gitea/services/actions"
@@ -758,6 +761,231 @@ func (Action) ListWorkflowRuns(ctx *context.APIContext) {
 	shared.ListRuns(ctx, 0, repoID)
 }
 
+// GetWorkflowPermissions gets the default permissions of the tokens of the workflow jobs of a repository
+func (Action) GetWorkflowPermissions(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/permissions/workflow repository getRepoWorkflowPermissions
+	// ---
+	// summary: Get the default permissions of the tokens of the workflow jobs of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionWorkflowPermissions"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.GetWorkflowPermissions(ctx, ctx.Repo.Repository.OwnerID, ctx.Repo.Repository)
+}
+
+// UpdateWorkflowPermissions updates the default permissions of the tokens of the workflow jobs of a repository
+func (Action) UpdateWorkflowPermissions(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/actions/permissions/workflow repository updateRepoWorkflowPermissions
+	// ---
+	// summary: Update the default permissions of the tokens of the workflow jobs of a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditActionWorkflowPermissionsOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionWorkflowPermissions"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.UpdateWorkflowPermissions(ctx, ctx.Repo.Repository.OwnerID, ctx.Repo.Repository)
+}
+
+// GetForkPRContributorApproval gets the approval policy of the runs from forks in a repository
+func (Action) GetForkPRContributorApproval(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/permissions/fork-pr-contributor-approval repository getRepoForkPRContributorApproval
+	// ---
+	// summary: Get the approval policy of the workflow runs triggered by the pull requests from forks in a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionForkPRContributorApproval"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.GetForkPRContributorApproval(ctx, ctx.Repo.Repository.OwnerID, ctx.Repo.Repository)
+}
+
+// UpdateForkPRContributorApproval updates the approval policy of the runs from forks in a repository
+func (Action) UpdateForkPRContributorApproval(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/actions/permissions/fork-pr-contributor-approval repository updateRepoForkPRContributorApproval
+	// ---
+	// summary: Update the approval policy of the workflow runs triggered by the pull requests from forks in a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditActionForkPRContributorApprovalOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionForkPRContributorApproval"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.UpdateForkPRContributorApproval(ctx, ctx.Repo.Repository.OwnerID, ctx.Repo.Repository)
+}
+
+// GetLogRetention gets the retention period of the job logs in a repository
+func (Action) GetLogRetention(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/permissions/log-retention repository getRepoActionLogRetention
+	// ---
+	// summary: Get the retention period of the job logs in a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionLogRetention"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.GetLogRetention(ctx, ctx.Repo.Repository.OwnerID, ctx.Repo.Repository)
+}
+
+// GetUsage gets the monthly usage report of the jobs in a repository
+func (Action) GetUsage(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/usage repository getRepoActionUsage
+	// ---
+	// summary: Get the monthly usage report of the jobs in a repository
+	// produces:
+	// - application/json
+	// - text/csv
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: month
+	//   in: query
+	//   description: the month of the report in UTC, like 2006-01, the default is the current month
+	//   type: string
+	// - name: format
+	//   in: query
+	//   description: the format of the report
+	//   type: string
+	//   enum: [json, csv]
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionUsageReport"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.GetUsage(ctx, ctx.Repo.Repository.OwnerID, ctx.Repo.Repository)
+}
+
+// UpdateLogRetention updates the retention period of the job logs in a repository
+func (Action) UpdateLogRetention(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/actions/permissions/log-retention repository updateRepoActionLogRetention
+	// ---
+	// summary: Update the retention period of the job logs in a repository, it can't be longer than the retention period of the instance
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditActionLogRetentionOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionLogRetention"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.UpdateLogRetention(ctx, ctx.Repo.Repository.OwnerID, ctx.Repo.Repository)
+}
+
 var _ actions_service.API = new(Action)
 
 // Action implements actions_service.API
@@ -833,6 +1061,86 @@ func ListActionTasks(ctx *context.APIContext) {
 	ctx.JSON(http.StatusOK, &res)
 }
 
+// ListActionSchedules lists the cron schedules of the workflows of a repository
+func ListActionSchedules(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/schedules repository ListActionSchedules
+	// ---
+	// summary: List the schedules of a repository's workflows with their upcoming and last executions
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionWorkflowScheduleList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	specs, total, err := actions_model.FindSpecs(ctx, actions_model.FindSpecOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		RepoID:      ctx.Repo.Repository.ID,
+	})
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+
+	lastRuns := make(map[string]*actions_model.ActionRun)
+	schedules := make([]*api.ActionWorkflowSchedule, 0, len(specs))
+	for _, spec := range specs {
+		if spec.Schedule == nil {
+			continue
+		}
+		workflowID := spec.Schedule.WorkflowID
+		lastRun, ok := lastRuns[workflowID]
+		if !ok {
+			runs, err := db.Find[actions_model.ActionRun](ctx, actions_model.FindRunOptions{
+				ListOptions:  db.ListOptions{PageSize: 1},
+				RepoID:       ctx.Repo.Repository.ID,
+				WorkflowID:   workflowID,
+				TriggerEvent: webhook_module.HookEventSchedule,
+			})
+			if err != nil {
+				ctx.APIErrorInternal(err)
+				return
+			}
+			if len(runs) > 0 {
+				lastRun = runs[0]
+			}
+			lastRuns[workflowID] = lastRun
+		}
+
+		schedule, err := convert.ToActionWorkflowSchedule(ctx, ctx.Repo.Repository, spec, lastRun)
+		if err != nil {
+			ctx.APIErrorInternal(err)
+			return
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	ctx.SetTotalCountHeader(total)
+	ctx.JSON(http.StatusOK, schedules)
+}
+
 func ActionsListRepositoryWorkflows(ctx *context.APIContext) {
 	// swagger:operation GET /repos/{owner}/{repo}/actions/workflows repository ActionsListRepositoryWorkflows
 	// ---
@@ -1007,7 +1315,7 @@ func ActionsDispatchWorkflow(ctx *context.APIContext) {
 	//   "404":
 	//     "$ref": "#/responses/notFound"
 	//   "422":
-	//     "$ref": "#/responses/validationError"
+	//     "$ref": "#/responses/invalidWorkflowInputsError"
 
 	workflowID := ctx.PathParam("workflow_id")
 	opt := web.GetForm(ctx).(*api.CreateActionWorkflowDispatch)
@@ -1016,28 +1324,28 @@ func ActionsDispatchWorkflow(ctx *context.APIContext) {
 		return
 	}
 
-	err := actions_service.DispatchActionWorkflow(ctx, ctx.Doer, ctx.Repo.Repository, ctx.Repo.GitRepo, workflowID, opt.Ref, func(workflowDispatch *model.WorkflowDispatch, inputs map[string]any) error {
+	err := actions_service.DispatchActionWorkflow(ctx, ctx.Doer, ctx.Repo.Repository, ctx.Repo.GitRepo, workflowID, opt.Ref, func(workflowDispatch *model.WorkflowDispatch, inputs map[string]string) error {
 		if strings.Contains(ctx.Req.Header.Get("Content-Type"), "form-urlencoded") {
 			// The chi framework's "Binding" doesn't support to bind the form map values into a map[string]string
 			// So we have to manually read the `inputs[key]` from the form
-			for name, config := range workflowDispatch.Inputs {
-				value := ctx.FormString("inputs["+name+"]", config.Default)
-				inputs[name] = value
-			}
-		} else {
-			for name, config := range workflowDispatch.Inputs {
-				value, ok := opt.Inputs[name]
-				if ok {
-					inputs[name] = value
-				} else {
-					inputs[name] = config.Default
+			for key := range ctx.Req.PostForm {
+				if name, ok := strings.CutPrefix(key, "inputs["); ok && strings.HasSuffix(name, "]") {
+					inputs[strings.TrimSuffix(name, "]")] = ctx.Req.PostForm.Get(key)
 				}
 			}
+		} else {
+			maps.Copy(inputs, opt.Inputs)
 		}
 		return nil
 	})
 	if err != nil {
-		if errors.Is(err, util.ErrNotExist) {
+		var inputsErr *actions_service.WorkflowDispatchInputsError
+		if errors.As(err, &inputsErr) {
+			ctx.JSON(http.StatusUnprocessableEntity, api.ActionWorkflowInputsError{
+				Message:       inputsErr.Error(),
+				InvalidInputs: inputsErr.InvalidInputs,
+			})
+		} else if errors.Is(err, util.ErrNotExist) {
 			ctx.APIError(http.StatusNotFound, err)
 		} else if errors.Is(err, util.ErrPermissionDenied) {
 			ctx.APIError(http.StatusForbidden, err)
@@ -1050,10 +1358,13 @@ func ActionsDispatchWorkflow(ctx *context.APIContext) {
 	ctx.Status(http.StatusNoContent)
 }
 
-func ActionsEnableWorkflow(ctx *context.APIContext) {
-	// swagger:operation PUT /repos/{owner}/{repo}/actions/workflows/{workflow_id}/enable repository ActionsEnableWorkflow
+// CreateRepositoryDispatch triggers the workflows listening to the repository_dispatch event
+func CreateRepositoryDispatch(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/dispatches repository repoCreateDispatch
 	// ---
-	// summary: Enable a workflow
+	// summary: Create a repository dispatch event to trigger the workflows of the default branch
+	// consumes:
+	// - application/json
 	// produces:
 	// - application/json
 	// parameters:
@@ -1067,30 +1378,24 @@ func ActionsEnableWorkflow(ctx *context.APIContext) {
 	//   description: name of the repo
 	//   type: string
 	//   required: true
-	// - name: workflow_id
-	//   in: path
-	//   description: id of the workflow
-	//   type: string
-	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateRepositoryDispatchOption"
 	// responses:
 	//   "204":
 	//     description: No Content
-	//   "400":
-	//     "$ref": "#/responses/error"
 	//   "403":
 	//     "$ref": "#/responses/forbidden"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
-	//   "409":
-	//     "$ref": "#/responses/conflict"
 	//   "422":
 	//     "$ref": "#/responses/validationError"
 
-	workflowID := ctx.PathParam("workflow_id")
-	err := actions_service.EnableOrDisableWorkflow(ctx, workflowID, true)
-	if err != nil {
-		if errors.Is(err, util.ErrNotExist) {
-			ctx.APIError(http.StatusNotFound, err)
+	opt := web.GetForm(ctx).(*api.CreateRepositoryDispatchOption)
+	if err := actions_service.DispatchRepositoryEvent(ctx, ctx.Doer, ctx.Repo.Repository, opt.EventType, opt.ClientPayload); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.APIError(http.StatusUnprocessableEntity, err)
 		} else {
 			ctx.APIErrorInternal(err)
 		}
@@ -1100,9 +1405,59 @@ func ActionsEnableWorkflow(ctx *context.APIContext) {
 	ctx.Status(http.StatusNoContent)
 }
 
-// GetWorkflowRun Gets a specific workflow run.
-func GetWorkflowRun(ctx *context.APIContext) {
-	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run} repository GetWorkflowRun
+func ActionsEnableWorkflow(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/actions/workflows/{workflow_id}/enable repository ActionsEnableWorkflow
+	// ---
+	// summary: Enable a workflow
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: workflow_id
+	//   in: path
+	//   description: id of the workflow
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     description: No Content
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	workflowID := ctx.PathParam("workflow_id")
+	err := actions_service.EnableOrDisableWorkflow(ctx, workflowID, true)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.APIError(http.StatusNotFound, err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetWorkflowRun Gets a specific workflow run.
+func GetWorkflowRun(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run} repository GetWorkflowRun
 	// ---
 	// summary: Gets a specific workflow run
 	// produces:
@@ -1136,6 +1491,7 @@ func GetWorkflowRun(ctx *context.APIContext) {
 
 	if err != nil || job.RepoID != ctx.Repo.Repository.ID {
 		ctx.APIError(http.StatusNotFound, util.ErrNotExist)
+		return
 	}
 
 	convertedArtifact, err := convert.ToActionWorkflowRun(ctx, ctx.Repo.Repository, job)
@@ -1146,11 +1502,49 @@ func GetWorkflowRun(ctx *context.APIContext) {
 	ctx.JSON(http.StatusOK, convertedArtifact)
 }
 
-// ListWorkflowRunJobs Lists all jobs for a workflow run.
-func ListWorkflowRunJobs(ctx *context.APIContext) {
-	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run}/jobs repository listWorkflowRunJobs
+// getRunAttempt returns the run with the results of the attempt in the path, and the jobs of the attempt
+func getRunAttempt(ctx *context.APIContext) (*actions_model.ActionRun, actions_model.ActionJobList) {
+	run, err := actions_model.GetRunByRepoAndID(ctx, ctx.Repo.Repository.ID, ctx.PathParamInt64("run"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.APIErrorNotFound(err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+		return nil, nil
+	}
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return nil, nil
+	}
+
+	attempt := ctx.PathParamInt64("attempt")
+	if attempt == run.Attempt {
+		return run, jobs
+	}
+	runAttempt, err := actions_model.GetRunAttempt(ctx, run.ID, attempt)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.APIErrorNotFound(err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+		return nil, nil
+	}
+	run = runAttempt.ApplyToRun(run)
+	jobs = runAttempt.ApplyToJobs(jobs)
+	for _, job := range jobs {
+		job.Run = run
+	}
+	return run, jobs
+}
+
+// GetWorkflowRunAttempt Gets a specific attempt of a workflow run.
+func GetWorkflowRunAttempt(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run}/attempts/{attempt} repository getWorkflowRunAttempt
 	// ---
-	// summary: Lists all jobs for a workflow run
+	// summary: Gets a specific attempt of a workflow run
 	// produces:
 	// - application/json
 	// parameters:
@@ -1166,50 +1560,38 @@ func ListWorkflowRunJobs(ctx *context.APIContext) {
 	//   required: true
 	// - name: run
 	//   in: path
-	//   description: runid of the workflow run
+	//   description: id of the run
 	//   type: integer
 	//   required: true
-	// - name: status
-	//   in: query
-	//   description: workflow status (pending, queued, in_progress, failure, success, skipped)
-	//   type: string
-	//   required: false
-	// - name: page
-	//   in: query
-	//   description: page number of results to return (1-based)
-	//   type: integer
-	// - name: limit
-	//   in: query
-	//   description: page size of results
+	// - name: attempt
+	//   in: path
+	//   description: the attempt number of the run
 	//   type: integer
+	//   required: true
 	// responses:
 	//   "200":
-	//     "$ref": "#/responses/WorkflowJobsList"
-	//   "400":
-	//     "$ref": "#/responses/error"
+	//     "$ref": "#/responses/WorkflowRun"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
 
-	repoID := ctx.Repo.Repository.ID
-
-	runID := ctx.PathParamInt64("run")
-
-	// Avoid the list all jobs functionality for this api route to be used with a runID == 0.
-	if runID <= 0 {
-		ctx.APIError(http.StatusBadRequest, util.NewInvalidArgumentErrorf("runID must be a positive integer"))
+	run, _ := getRunAttempt(ctx)
+	if ctx.Written() {
 		return
 	}
 
-	// runID is used as an additional filter next to repoID to ensure that we only list jobs for the specified repoID and runID.
-	// no additional checks for runID are needed here
-	shared.ListJobs(ctx, 0, repoID, runID)
+	convertedRun, err := convert.ToActionWorkflowRun(ctx, ctx.Repo.Repository, run)
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convertedRun)
 }
 
-// GetWorkflowJob Gets a specific workflow job for a workflow run.
-func GetWorkflowJob(ctx *context.APIContext) {
-	// swagger:operation GET /repos/{owner}/{repo}/actions/jobs/{job_id} repository getWorkflowJob
+// ListWorkflowRunAttemptJobs Lists the jobs of a specific attempt of a workflow run.
+func ListWorkflowRunAttemptJobs(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run}/attempts/{attempt}/jobs repository listWorkflowRunAttemptJobs
 	// ---
-	// summary: Gets a specific workflow job for a workflow run
+	// summary: Lists the jobs of a specific attempt of a workflow run
 	// produces:
 	// - application/json
 	// parameters:
@@ -1223,39 +1605,57 @@ func GetWorkflowJob(ctx *context.APIContext) {
 	//   description: name of the repository
 	//   type: string
 	//   required: true
-	// - name: job_id
+	// - name: run
 	//   in: path
-	//   description: id of the job
-	//   type: string
+	//   description: id of the run
+	//   type: integer
+	//   required: true
+	// - name: attempt
+	//   in: path
+	//   description: the attempt number of the run
+	//   type: integer
 	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
 	// responses:
 	//   "200":
-	//     "$ref": "#/responses/WorkflowJob"
-	//   "400":
-	//     "$ref": "#/responses/error"
+	//     "$ref": "#/responses/WorkflowJobsList"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
 
-	jobID := ctx.PathParamInt64("job_id")
-	job, _, err := db.GetByID[actions_model.ActionRunJob](ctx, jobID)
-
-	if err != nil || job.RepoID != ctx.Repo.Repository.ID {
-		ctx.APIError(http.StatusNotFound, util.ErrNotExist)
+	_, jobs := getRunAttempt(ctx)
+	if ctx.Written() {
+		return
 	}
 
-	convertedWorkflowJob, err := convert.ToActionWorkflowJob(ctx, ctx.Repo.Repository, nil, job)
-	if err != nil {
-		ctx.APIErrorInternal(err)
-		return
+	listOptions := utils.GetListOptions(ctx)
+	res := &api.ActionWorkflowJobsResponse{
+		TotalCount: int64(len(jobs)),
+		Entries:    make([]*api.ActionWorkflowJob, 0, listOptions.PageSize),
 	}
-	ctx.JSON(http.StatusOK, convertedWorkflowJob)
+	for _, job := range util.PaginateSlice(jobs, listOptions.Page, listOptions.PageSize).(actions_model.ActionJobList) {
+		convertedJob, err := convert.ToActionWorkflowJob(ctx, ctx.Repo.Repository, nil, job)
+		if err != nil {
+			ctx.APIErrorInternal(err)
+			return
+		}
+		res.Entries = append(res.Entries, convertedJob)
+	}
+	ctx.JSON(http.StatusOK, res)
 }
 
-// GetArtifacts Lists all artifacts for a repository.
-func GetArtifactsOfRun(ctx *context.APIContext) {
-	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run}/artifacts repository getArtifactsOfRun
+// ListWorkflowRunAttemptArtifacts Lists the artifacts available in a specific attempt of a workflow run.
+func ListWorkflowRunAttemptArtifacts(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run}/attempts/{attempt}/artifacts repository listWorkflowRunAttemptArtifacts
 	// ---
-	// summary: Lists all artifacts for a repository run
+	// summary: Lists the artifacts available in a specific attempt of a workflow run
+	// description: An artifact which hasn't been uploaded again by the attempt is the one uploaded by the latest previous attempt.
 	// produces:
 	// - application/json
 	// parameters:
@@ -1271,7 +1671,12 @@ func GetArtifactsOfRun(ctx *context.APIContext) {
 	//   required: true
 	// - name: run
 	//   in: path
-	//   description: runid of the workflow run
+	//   description: id of the run
+	//   type: integer
+	//   required: true
+	// - name: attempt
+	//   in: path
+	//   description: the attempt number of the run
 	//   type: integer
 	//   required: true
 	// - name: name
@@ -1279,52 +1684,56 @@ func GetArtifactsOfRun(ctx *context.APIContext) {
 	//   description: name of the artifact
 	//   type: string
 	//   required: false
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/ArtifactsList"
-	//   "400":
-	//     "$ref": "#/responses/error"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
 
-	repoID := ctx.Repo.Repository.ID
-	artifactName := ctx.Req.URL.Query().Get("name")
-
-	runID := ctx.PathParamInt64("run")
+	run, _ := getRunAttempt(ctx)
+	if ctx.Written() {
+		return
+	}
 
-	artifacts, total, err := db.FindAndCount[actions_model.ActionArtifact](ctx, actions_model.FindArtifactsOptions{
-		RepoID:               repoID,
-		RunID:                runID,
-		ArtifactName:         artifactName,
-		FinalizedArtifactsV4: true,
-		ListOptions:          utils.GetListOptions(ctx),
-	})
+	artifacts, err := actions_model.GetRunAttemptArtifacts(ctx, run.ID, run.Attempt, ctx.FormString("name"))
 	if err != nil {
 		ctx.APIErrorInternal(err)
 		return
 	}
+	artifacts = slices.DeleteFunc(artifacts, func(art *actions_model.ActionArtifact) bool {
+		finalized := art.Status == actions_model.ArtifactStatusUploadConfirmed || art.Status == actions_model.ArtifactStatusExpired
+		return !finalized || art.ContentEncoding != "application/zip"
+	})
 
-	res := new(api.ActionArtifactsResponse)
-	res.TotalCount = total
-
-	res.Entries = make([]*api.ActionArtifact, len(artifacts))
-	for i := range artifacts {
-		convertedArtifact, err := convert.ToActionArtifact(ctx.Repo.Repository, artifacts[i])
+	listOptions := utils.GetListOptions(ctx)
+	res := &api.ActionArtifactsResponse{
+		TotalCount: int64(len(artifacts)),
+		Entries:    make([]*api.ActionArtifact, 0, listOptions.PageSize),
+	}
+	for _, art := range util.PaginateSlice(artifacts, listOptions.Page, listOptions.PageSize).([]*actions_model.ActionArtifact) {
+		convertedArtifact, err := convert.ToActionArtifact(ctx.Repo.Repository, art)
 		if err != nil {
 			ctx.APIErrorInternal(err)
 			return
 		}
-		res.Entries[i] = convertedArtifact
+		res.Entries = append(res.Entries, convertedArtifact)
 	}
-
-	ctx.JSON(http.StatusOK, &res)
+	ctx.JSON(http.StatusOK, res)
 }
 
-// DeleteActionRun Delete a workflow run
-func DeleteActionRun(ctx *context.APIContext) {
-	// swagger:operation DELETE /repos/{owner}/{repo}/actions/runs/{run} repository deleteActionRun
+// ListWorkflowRunStepSummaries Lists the summaries written by the steps of the jobs in a workflow run.
+func ListWorkflowRunStepSummaries(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run}/summaries repository listWorkflowRunStepSummaries
 	// ---
-	// summary: Delete a workflow run
+	// summary: Lists the summaries written by the steps of the jobs in a workflow run
 	// produces:
 	// - application/json
 	// parameters:
@@ -1340,36 +1749,632 @@ func DeleteActionRun(ctx *context.APIContext) {
 	//   required: true
 	// - name: run
 	//   in: path
-	//   description: runid of the workflow run
+	//   description: id of the run
 	//   type: integer
 	//   required: true
+	// - name: attempt
+	//   in: query
+	//   description: the attempt number of the run, the latest attempt is used if it's not set
+	//   type: integer
 	// responses:
-	//   "204":
-	//     description: "No Content"
-	//   "400":
-	//     "$ref": "#/responses/error"
+	//   "200":
+	//     "$ref": "#/responses/WorkflowStepSummaryList"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
 
-	runID := ctx.PathParamInt64("run")
-	run, err := actions_model.GetRunByRepoAndID(ctx, ctx.Repo.Repository.ID, runID)
-	if errors.Is(err, util.ErrNotExist) {
-		ctx.APIError(http.StatusNotFound, err)
+	run, err := actions_model.GetRunByRepoAndID(ctx, ctx.Repo.Repository.ID, ctx.PathParamInt64("run"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.APIErrorNotFound(err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
 		return
-	} else if err != nil {
+	}
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+	if err != nil {
 		ctx.APIErrorInternal(err)
 		return
 	}
-	if !run.Status.IsDone() {
-		ctx.APIError(http.StatusBadRequest, "this workflow run is not done")
-		return
+	if attempt := ctx.FormInt64("attempt"); attempt > 0 && attempt != run.Attempt {
+		runAttempt, err := actions_model.GetRunAttempt(ctx, run.ID, attempt)
+		if err != nil {
+			if errors.Is(err, util.ErrNotExist) {
+				ctx.APIErrorNotFound(err)
+			} else {
+				ctx.APIErrorInternal(err)
+			}
+			return
+		}
+		jobs = runAttempt.ApplyToJobs(jobs)
 	}
 
-	if err := actions_service.DeleteRun(ctx, run); err != nil {
+	summaries, err := actions_service.GetStepSummaries(ctx, jobs)
+	if err != nil {
 		ctx.APIErrorInternal(err)
 		return
 	}
-	ctx.Status(http.StatusNoContent)
+	res := make([]*api.ActionWorkflowStepSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		res = append(res, convert.ToActionWorkflowStepSummary(summary.Job, summary.StepName, summary.ActionTaskSummary))
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// ListWorkflowRunJobs Lists all jobs for a workflow run.
+func ListWorkflowRunJobs(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run}/jobs repository listWorkflowRunJobs
+	// ---
+	// summary: Lists all jobs for a workflow run
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: name of the owner
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: runid of the workflow run
+	//   type: integer
+	//   required: true
+	// - name: status
+	//   in: query
+	//   description: workflow status (pending, queued, in_progress, failure, success, skipped)
+	//   type: string
+	//   required: false
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/WorkflowJobsList"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	repoID := ctx.Repo.Repository.ID
+
+	runID := ctx.PathParamInt64("run")
+
+	// Avoid the list all jobs functionality for this api route to be used with a runID == 0.
+	if runID <= 0 {
+		ctx.APIError(http.StatusBadRequest, util.NewInvalidArgumentErrorf("runID must be a positive integer"))
+		return
+	}
+
+	// runID is used as an additional filter next to repoID to ensure that we only list jobs for the specified repoID and runID.
+	// no additional checks for runID are needed here
+	shared.ListJobs(ctx, 0, repoID, runID)
+}
+
+// GetWorkflowJob Gets a specific workflow job for a workflow run.
+func GetWorkflowJob(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/jobs/{job_id} repository getWorkflowJob
+	// ---
+	// summary: Gets a specific workflow job for a workflow run
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: name of the owner
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: job_id
+	//   in: path
+	//   description: id of the job
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/WorkflowJob"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	jobID := ctx.PathParamInt64("job_id")
+	job, _, err := db.GetByID[actions_model.ActionRunJob](ctx, jobID)
+
+	if err != nil || job.RepoID != ctx.Repo.Repository.ID {
+		ctx.APIError(http.StatusNotFound, util.ErrNotExist)
+	}
+
+	convertedWorkflowJob, err := convert.ToActionWorkflowJob(ctx, ctx.Repo.Repository, nil, job)
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convertedWorkflowJob)
+}
+
+// GetArtifacts Lists all artifacts for a repository.
+func GetArtifactsOfRun(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run}/artifacts repository getArtifactsOfRun
+	// ---
+	// summary: Lists all artifacts for a repository run
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: name of the owner
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: runid of the workflow run
+	//   type: integer
+	//   required: true
+	// - name: name
+	//   in: query
+	//   description: name of the artifact
+	//   type: string
+	//   required: false
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ArtifactsList"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	repoID := ctx.Repo.Repository.ID
+	artifactName := ctx.Req.URL.Query().Get("name")
+
+	runID := ctx.PathParamInt64("run")
+
+	artifacts, total, err := db.FindAndCount[actions_model.ActionArtifact](ctx, actions_model.FindArtifactsOptions{
+		RepoID:               repoID,
+		RunID:                runID,
+		ArtifactName:         artifactName,
+		FinalizedArtifactsV4: true,
+		ListOptions:          utils.GetListOptions(ctx),
+	})
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+
+	res := new(api.ActionArtifactsResponse)
+	res.TotalCount = total
+
+	res.Entries = make([]*api.ActionArtifact, len(artifacts))
+	for i := range artifacts {
+		convertedArtifact, err := convert.ToActionArtifact(ctx.Repo.Repository, artifacts[i])
+		if err != nil {
+			ctx.APIErrorInternal(err)
+			return
+		}
+		res.Entries[i] = convertedArtifact
+	}
+
+	ctx.JSON(http.StatusOK, &res)
+}
+
+// ReviewActionRuns approve or reject the workflow runs waiting for approval
+func ReviewActionRuns(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runs/approvals repository reviewActionRuns
+	// ---
+	// summary: Approve or reject the workflow runs triggered by the pull requests from forks which are waiting for approval
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repository
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/ReviewActionRunsOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/WorkflowRunsList"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	opt := web.GetForm(ctx).(*api.ReviewActionRunsOption)
+
+	runs, err := db.Find[actions_model.ActionRun](ctx, actions_model.FindRunOptions{
+		RepoID:       ctx.Repo.Repository.ID,
+		NeedApproval: true,
+	})
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+	if len(opt.RunIDs) > 0 {
+		runsByID := make(map[int64]*actions_model.ActionRun, len(runs))
+		for _, run := range runs {
+			runsByID[run.ID] = run
+		}
+		runs = make([]*actions_model.ActionRun, 0, len(opt.RunIDs))
+		for _, id := range opt.RunIDs {
+			run, ok := runsByID[id]
+			if !ok {
+				ctx.APIError(http.StatusBadRequest, fmt.Errorf("run %d doesn't exist or doesn't need approval", id))
+				return
+			}
+			runs = append(runs, run)
+		}
+	}
+
+	res := &api.ActionWorkflowRunsResponse{
+		Entries:    make([]*api.ActionWorkflowRun, 0, len(runs)),
+		TotalCount: int64(len(runs)),
+	}
+	for _, run := range runs {
+		run.Repo = ctx.Repo.Repository
+		if opt.State == "approved" {
+			err = actions_service.ApproveRun(ctx, run, ctx.Doer)
+		} else {
+			err = actions_service.RejectRun(ctx, run, ctx.Doer)
+		}
+		if err != nil {
+			ctx.APIErrorInternal(err)
+			return
+		}
+		// reload the run to get the status updated by the jobs
+		run, err = actions_model.GetRunByRepoAndID(ctx, ctx.Repo.Repository.ID, run.ID)
+		if err != nil {
+			ctx.APIErrorInternal(err)
+			return
+		}
+		convertedRun, err := convert.ToActionWorkflowRun(ctx, ctx.Repo.Repository, run)
+		if err != nil {
+			ctx.APIErrorInternal(err)
+			return
+		}
+		res.Entries = append(res.Entries, convertedRun)
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// DeleteActionRun Delete a workflow run
+func DeleteActionRun(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/actions/runs/{run} repository deleteActionRun
+	// ---
+	// summary: Delete a workflow run
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: name of the owner
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: runid of the workflow run
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "204":
+	//     description: "No Content"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	runID := ctx.PathParamInt64("run")
+	run, err := actions_model.GetRunByRepoAndID(ctx, ctx.Repo.Repository.ID, runID)
+	if errors.Is(err, util.ErrNotExist) {
+		ctx.APIError(http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+	if !run.Status.IsDone() {
+		ctx.APIError(http.StatusBadRequest, "this workflow run is not done")
+		return
+	}
+
+	if err := actions_service.DeleteRun(ctx, run); err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// respondRunOperation responds the run reloaded after the operation, or the error of the operation
+func respondRunOperation(ctx *context.APIContext, run *actions_model.ActionRun, err error) {
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.APIError(http.StatusBadRequest, err)
+		} else if errors.Is(err, util.ErrNotExist) {
+			ctx.APIErrorNotFound(err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+		return
+	}
+
+	// reload the run to get the status updated by the jobs
+	run, err = actions_model.GetRunByRepoAndID(ctx, ctx.Repo.Repository.ID, run.ID)
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+	convertedRun, err := convert.ToActionWorkflowRun(ctx, ctx.Repo.Repository, run)
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convertedRun)
+}
+
+// CancelWorkflowRun Cancels a workflow run.
+func CancelWorkflowRun(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runs/{run}/cancel repository cancelWorkflowRun
+	// ---
+	// summary: Cancels a workflow run
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: name of the owner
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/WorkflowRun"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getRunByPathParam(ctx)
+	if ctx.Written() {
+		return
+	}
+	respondRunOperation(ctx, run, actions_service.CancelRun(ctx, run))
+}
+
+// RerunWorkflowRun Reruns all jobs of a workflow run.
+func RerunWorkflowRun(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runs/{run}/rerun repository rerunWorkflowRun
+	// ---
+	// summary: Reruns all jobs of a workflow run as a new attempt
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: name of the owner
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/RerunActionRunOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/WorkflowRun"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	opt := web.GetForm(ctx).(*api.RerunActionRunOption)
+	run := getRunByPathParam(ctx)
+	if ctx.Written() {
+		return
+	}
+	respondRunOperation(ctx, run, actions_service.RerunRun(ctx, run, opt.EnableDebugLogging))
+}
+
+// RerunFailedWorkflowRunJobs Reruns the failed jobs of a workflow run.
+func RerunFailedWorkflowRunJobs(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runs/{run}/rerun-failed-jobs repository rerunFailedWorkflowRunJobs
+	// ---
+	// summary: Reruns the failed and cancelled jobs of a workflow run and the jobs depending on them as a new attempt
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: name of the owner
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/RerunActionRunOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/WorkflowRun"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	opt := web.GetForm(ctx).(*api.RerunActionRunOption)
+	run := getRunByPathParam(ctx)
+	if ctx.Written() {
+		return
+	}
+	respondRunOperation(ctx, run, actions_service.RerunFailedJobs(ctx, run, opt.EnableDebugLogging))
+}
+
+// RerunWorkflowRunJob Reruns a job of a workflow run.
+func RerunWorkflowRunJob(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runs/{run}/jobs/{job_id}/rerun repository rerunWorkflowRunJob
+	// ---
+	// summary: Reruns a job of a workflow run and the jobs depending on it as a new attempt
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: name of the owner
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   required: true
+	// - name: job_id
+	//   in: path
+	//   description: id of the job
+	//   type: integer
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/RerunActionRunOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/WorkflowRun"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	opt := web.GetForm(ctx).(*api.RerunActionRunOption)
+	run := getRunByPathParam(ctx)
+	if ctx.Written() {
+		return
+	}
+	job, err := actions_model.GetRunJobByID(ctx, ctx.PathParamInt64("job_id"))
+	if err != nil || job.RunID != run.ID {
+		ctx.APIErrorNotFound(util.NewNotExistErrorf("job %d of run %d", ctx.PathParamInt64("job_id"), run.ID))
+		return
+	}
+	respondRunOperation(ctx, run, actions_service.RerunJob(ctx, run, job, opt.EnableDebugLogging))
+}
+
+// ApproveWorkflowRun Approves a workflow run waiting for approval.
+func ApproveWorkflowRun(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runs/{run}/approve repository approveWorkflowRun
+	// ---
+	// summary: Approves a workflow run triggered by a pull request from a fork which is waiting for approval
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: name of the owner
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/WorkflowRun"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getRunByPathParam(ctx)
+	if ctx.Written() {
+		return
+	}
+	respondRunOperation(ctx, run, actions_service.ApproveRun(ctx, run, ctx.Doer))
 }
 
 // GetArtifacts Lists all artifacts for a repository.
@@ -1519,7 +2524,7 @@ func DeleteArtifact(ctx *context.APIContext) {
 	}
 
 	if actions.IsArtifactV4(art) {
-		if err := actions_model.SetArtifactNeedDelete(ctx, art.RunID, art.ArtifactName); err != nil {
+		if err := actions_model.SetArtifactNeedDelete(ctx, art.RunID, art.RunAttempt, art.ArtifactName); err != nil {
 			ctx.APIErrorInternal(err)
 			return
 		}
@@ -0,0 +1,313 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/attestation"
+	"code.gitea.io/gitea/modules/json"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// CreateActionAttestation creates a signed build provenance attestation for the running Actions job
+func CreateActionAttestation(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/attestations repository createActionAttestation
+	// ---
+	// summary: Create a build provenance attestation of a software artifact built by the running Actions job
+	// description: Only the tokens of the Actions jobs with the `attestations` write permission can create attestations,
+	//   the attestations are signed by the instance key published in /api/actions/.well-known/jwks.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateActionAttestationOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ActionAttestation"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "501":
+	//     "$ref": "#/responses/error"
+
+	if ctx.Data["IsActionsToken"] != true {
+		ctx.APIError(http.StatusForbidden, "only Actions jobs can create attestations")
+		return
+	}
+	if _, _, err := actions_service.GetAttestationSigningKey(); err != nil {
+		ctx.APIError(http.StatusNotImplemented, "attestations are not available: "+err.Error())
+		return
+	}
+
+	task, err := actions_model.GetTaskByID(ctx, ctx.Data["ActionsTaskID"].(int64))
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+
+	form := web.GetForm(ctx).(*api.CreateActionAttestationOption)
+	attest, err := actions_service.CreateAttestation(ctx, task, actions_service.CreateAttestationOptions{
+		SubjectName:    form.SubjectName,
+		SubjectDigest:  form.SubjectDigest,
+		ArtifactName:   form.ArtifactName,
+		PackageType:    form.PackageType,
+		PackageName:    form.PackageName,
+		PackageVersion: form.PackageVersion,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, util.ErrPermissionDenied):
+			ctx.APIError(http.StatusForbidden, err)
+		case errors.Is(err, util.ErrNotExist):
+			ctx.APIError(http.StatusNotFound, err)
+		case errors.Is(err, util.ErrInvalidArgument):
+			ctx.APIError(http.StatusBadRequest, err)
+		default:
+			ctx.APIErrorInternal(err)
+		}
+		return
+	}
+
+	apiAttestation, err := convert.ToActionAttestation(attest)
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, apiAttestation)
+}
+
+// ListActionAttestations lists the attestations of a repository
+func ListActionAttestations(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/attestations repository listActionAttestations
+	// ---
+	// summary: List the build provenance attestations of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: subject_digest
+	//   in: query
+	//   description: the digest of the artifact in the form "sha256:<hex>"
+	//   type: string
+	// - name: run_id
+	//   in: query
+	//   description: the id of the workflow run which created the attestations
+	//   type: integer
+	// - name: artifact_id
+	//   in: query
+	//   description: the id of the artifact the attestations are attached to
+	//   type: integer
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionAttestationList"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	opts := actions_model.FindAttestationsOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		RepoID:      ctx.Repo.Repository.ID,
+		RunID:       ctx.FormInt64("run_id"),
+		ArtifactID:  ctx.FormInt64("artifact_id"),
+	}
+	if digest := ctx.FormString("subject_digest"); digest != "" {
+		algorithm, value, err := attestation.ParseDigest(digest)
+		if err != nil {
+			ctx.APIError(http.StatusBadRequest, err)
+			return
+		}
+		opts.SubjectDigest = algorithm + ":" + value
+	}
+
+	attestations, total, err := db.FindAndCount[actions_model.ActionAttestation](ctx, opts)
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+
+	apiAttestations := make([]*api.ActionAttestation, 0, len(attestations))
+	for _, attest := range attestations {
+		apiAttestation, err := convert.ToActionAttestation(attest)
+		if err != nil {
+			ctx.APIErrorInternal(err)
+			return
+		}
+		apiAttestations = append(apiAttestations, apiAttestation)
+	}
+
+	ctx.SetTotalCountHeader(total)
+	ctx.JSON(http.StatusOK, apiAttestations)
+}
+
+// GetActionAttestation gets an attestation of a repository
+func GetActionAttestation(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/attestations/{attestation_id} repository getActionAttestation
+	// ---
+	// summary: Get a build provenance attestation of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: attestation_id
+	//   in: path
+	//   description: id of the attestation
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionAttestation"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	attest, err := actions_model.GetAttestationByID(ctx, ctx.Repo.Repository.ID, ctx.PathParamInt64("attestation_id"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.APIErrorNotFound(err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+		return
+	}
+
+	apiAttestation, err := convert.ToActionAttestation(attest)
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiAttestation)
+}
+
+// VerifyActionAttestation verifies an attestation was signed by the instance for an artifact built by the repository
+func VerifyActionAttestation(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/attestations/verify repository verifyActionAttestation
+	// ---
+	// summary: Verify a build provenance attestation of an artifact built by the repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/VerifyActionAttestationOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionAttestationVerification"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	//   "501":
+	//     "$ref": "#/responses/error"
+
+	if _, _, err := actions_service.GetAttestationSigningKey(); err != nil {
+		ctx.APIError(http.StatusNotImplemented, "attestations are not available: "+err.Error())
+		return
+	}
+
+	form := web.GetForm(ctx).(*api.VerifyActionAttestationOption)
+	envelope := &attestation.Envelope{
+		PayloadType: form.Envelope.PayloadType,
+		Payload:     form.Envelope.Payload,
+		Signatures:  make([]*attestation.Signature, 0, len(form.Envelope.Signatures)),
+	}
+	for _, signature := range form.Envelope.Signatures {
+		if signature != nil {
+			envelope.Signatures = append(envelope.Signatures, &attestation.Signature{KeyID: signature.KeyID, Sig: signature.Sig})
+		}
+	}
+
+	verification, err := actions_service.VerifyAttestation(ctx, ctx.Repo.Repository, envelope, form.SubjectDigest)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.JSON(http.StatusOK, &api.ActionAttestationVerification{Message: err.Error()})
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+		return
+	}
+
+	result := &api.ActionAttestationVerification{Verified: true, KeyID: verification.KeyID}
+	statementJSON, err := json.Marshal(verification.Statement)
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+	if err := json.Unmarshal(statementJSON, &result.Statement); err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+	if verification.Attestation != nil {
+		if result.Attestation, err = convert.ToActionAttestation(verification.Attestation); err != nil {
+			ctx.APIErrorInternal(err)
+			return
+		}
+	}
+	ctx.JSON(http.StatusOK, result)
+}
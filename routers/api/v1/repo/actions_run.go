@@ -5,6 +5,7 @@ package repo
 
 import (
 	"errors"
+	"net/http"
 
 	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/modules/util"
@@ -41,11 +42,17 @@ func DownloadActionsRunJobLogs(ctx *context.APIContext) {
 	//     "$ref": "#/responses/error"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
+	//   "410":
+	//     "$ref": "#/responses/error"
 
 	jobID := ctx.PathParamInt64("job_id")
 	curJob, err := actions_model.GetRunJobByID(ctx, jobID)
 	if err != nil {
-		ctx.APIErrorInternal(err)
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.APIErrorNotFound(err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
 		return
 	}
 	if err = curJob.LoadRepo(ctx); err != nil {
@@ -54,11 +61,81 @@ func DownloadActionsRunJobLogs(ctx *context.APIContext) {
 	}
 
 	err = common.DownloadActionsRunJobLogs(ctx.Base, ctx.Repo.Repository, curJob)
+	if err != nil {
+		if errors.Is(err, actions_model.ErrTaskLogExpired) {
+			ctx.APIError(http.StatusGone, err)
+		} else if errors.Is(err, util.ErrNotExist) {
+			ctx.APIErrorNotFound(err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+	}
+}
+
+func StreamActionsRunJobLogs(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/jobs/{job_id}/logs/stream repository streamActionsRunJobLogs
+	// ---
+	// summary: Streams the job logs for a workflow run until the job is done
+	// description: The log lines are sent as server-sent events of ActionWorkflowJobLogChunk if the request accepts
+	//   "text/event-stream", otherwise they are sent as plain text. The id of an event is the offset to resume the stream from.
+	// produces:
+	// - text/event-stream
+	// - text/plain
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: name of the owner
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: job_id
+	//   in: path
+	//   description: id of the job
+	//   type: integer
+	//   required: true
+	// - name: offset
+	//   in: query
+	//   description: index of the first line to send, the "Last-Event-ID" header takes precedence over it
+	//   type: integer
+	// responses:
+	//   "200":
+	//     description: log stream
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "410":
+	//     "$ref": "#/responses/error"
+
+	jobID := ctx.PathParamInt64("job_id")
+	curJob, err := actions_model.GetRunJobByID(ctx, jobID)
 	if err != nil {
 		if errors.Is(err, util.ErrNotExist) {
 			ctx.APIErrorNotFound(err)
 		} else {
 			ctx.APIErrorInternal(err)
 		}
+		return
+	}
+	if err = curJob.LoadRepo(ctx); err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+
+	err = common.StreamActionsRunJobLogs(ctx.Base, ctx.Repo.Repository, curJob)
+	if err != nil {
+		if errors.Is(err, actions_model.ErrTaskLogExpired) {
+			ctx.APIError(http.StatusGone, err)
+		} else if errors.Is(err, util.ErrNotExist) {
+			ctx.APIErrorNotFound(err)
+		} else if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.APIError(http.StatusBadRequest, err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
 	}
 }
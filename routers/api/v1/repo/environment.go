@@ -0,0 +1,906 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	secret_model "code.gitea.io/gitea/models/secret"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+	secret_service "code.gitea.io/gitea/services/secrets"
+)
+
+// ListEnvironments list the deployment environments of a repository
+func ListEnvironments(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/environments repository repoListEnvironments
+	// ---
+	// summary: List the deployment environments of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repository
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionEnvironmentList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	envs, count, err := db.FindAndCount[actions_model.ActionEnvironment](ctx, actions_model.FindEnvironmentsOptions{
+		RepoID:      ctx.Repo.Repository.ID,
+		ListOptions: utils.GetListOptions(ctx),
+	})
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+
+	apiEnvs := make([]*api.ActionEnvironment, 0, len(envs))
+	for _, env := range envs {
+		apiEnv, err := convert.ToActionEnvironment(ctx, env, ctx.Doer)
+		if err != nil {
+			ctx.APIErrorInternal(err)
+			return
+		}
+		apiEnvs = append(apiEnvs, apiEnv)
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiEnvs)
+}
+
+// GetEnvironment get a deployment environment of a repository
+func GetEnvironment(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/environments/{environment_name} repository repoGetEnvironment
+	// ---
+	// summary: Get a deployment environment of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repository
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: environment_name
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionEnvironment"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	env := getEnvironmentByPathParam(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	apiEnv, err := convert.ToActionEnvironment(ctx, env, ctx.Doer)
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiEnv)
+}
+
+// CreateOrUpdateEnvironment create or update a deployment environment of a repository
+func CreateOrUpdateEnvironment(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/environments/{environment_name} repository repoCreateOrUpdateEnvironment
+	// ---
+	// summary: Create or update a deployment environment of a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repository
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: environment_name
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateOrUpdateEnvironmentOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionEnvironment"
+	//   "201":
+	//     "$ref": "#/responses/ActionEnvironment"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	opt := web.GetForm(ctx).(*api.CreateOrUpdateEnvironmentOption)
+
+	opts := &actions_model.ActionEnvironment{
+		Name:              ctx.PathParam("environment_name"),
+		PreventSelfReview: opt.PreventSelfReview,
+		WaitTimer:         opt.WaitTimer,
+		BranchPatterns:    opt.BranchPatterns,
+	}
+	for _, reviewer := range opt.Reviewers {
+		if reviewer.Type == "Team" {
+			opts.ReviewerTeamIDs = append(opts.ReviewerTeamIDs, reviewer.ID)
+		} else {
+			opts.ReviewerUserIDs = append(opts.ReviewerUserIDs, reviewer.ID)
+		}
+	}
+
+	env, created, err := actions_service.CreateOrUpdateEnvironment(ctx, ctx.Repo.Repository, opts)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.APIError(http.StatusBadRequest, err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+		return
+	}
+
+	apiEnv, err := convert.ToActionEnvironment(ctx, env, ctx.Doer)
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+	if created {
+		ctx.JSON(http.StatusCreated, apiEnv)
+	} else {
+		ctx.JSON(http.StatusOK, apiEnv)
+	}
+}
+
+// DeleteEnvironment delete a deployment environment of a repository
+func DeleteEnvironment(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/environments/{environment_name} repository repoDeleteEnvironment
+	// ---
+	// summary: Delete a deployment environment of a repository with its secrets and variables
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repository
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: environment_name
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     description: environment deleted
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	env := getEnvironmentByPathParam(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := actions_service.DeleteEnvironment(ctx, env); err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListEnvironmentSecrets list the secrets of a deployment environment
+func ListEnvironmentSecrets(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/environments/{environment_name}/secrets repository repoListEnvironmentSecrets
+	// ---
+	// summary: List the secrets of a deployment environment
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repository
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: environment_name
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SecretList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	env := getEnvironmentByPathParam(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	secrets, count, err := db.FindAndCount[secret_model.Secret](ctx, secret_model.FindSecretsOptions{
+		RepoID:        env.RepoID,
+		EnvironmentID: env.ID,
+		ListOptions:   utils.GetListOptions(ctx),
+	})
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+
+	apiSecrets := make([]*api.Secret, len(secrets))
+	for k, v := range secrets {
+		apiSecrets[k] = &api.Secret{
+			Name:        v.Name,
+			Description: v.Description,
+			Created:     v.CreatedUnix.AsTime(),
+		}
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiSecrets)
+}
+
+// CreateOrUpdateEnvironmentSecret create or update a secret of a deployment environment
+func CreateOrUpdateEnvironmentSecret(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/environments/{environment_name}/secrets/{secretname} repository repoUpdateEnvironmentSecret
+	// ---
+	// summary: Create or update a secret of a deployment environment
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repository
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: environment_name
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// - name: secretname
+	//   in: path
+	//   description: name of the secret
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateOrUpdateSecretOption"
+	// responses:
+	//   "201":
+	//     description: response when creating a secret
+	//   "204":
+	//     description: response when updating a secret
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	env := getEnvironmentByPathParam(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	opt := web.GetForm(ctx).(*api.CreateOrUpdateSecretOption)
+
+	_, created, err := secret_service.CreateOrUpdateEnvironmentSecret(ctx, env.RepoID, env.ID, ctx.PathParam("secretname"), opt.Data, opt.Description)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.APIError(http.StatusBadRequest, err)
+		} else if errors.Is(err, util.ErrNotExist) {
+			ctx.APIError(http.StatusNotFound, err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+		return
+	}
+
+	if created {
+		ctx.Status(http.StatusCreated)
+	} else {
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+// DeleteEnvironmentSecret delete a secret of a deployment environment
+func DeleteEnvironmentSecret(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/environments/{environment_name}/secrets/{secretname} repository repoDeleteEnvironmentSecret
+	// ---
+	// summary: Delete a secret of a deployment environment
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repository
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: environment_name
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// - name: secretname
+	//   in: path
+	//   description: name of the secret
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     description: secret deleted
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	env := getEnvironmentByPathParam(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := secret_service.DeleteEnvironmentSecretByName(ctx, env.RepoID, env.ID, ctx.PathParam("secretname")); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.APIError(http.StatusBadRequest, err)
+		} else if errors.Is(err, util.ErrNotExist) {
+			ctx.APIError(http.StatusNotFound, err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListEnvironmentVariables list the variables of a deployment environment
+func ListEnvironmentVariables(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/environments/{environment_name}/variables repository repoListEnvironmentVariables
+	// ---
+	// summary: List the variables of a deployment environment
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repository
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: environment_name
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/VariableList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	env := getEnvironmentByPathParam(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	vars, count, err := db.FindAndCount[actions_model.ActionVariable](ctx, &actions_model.FindVariablesOpts{
+		RepoID:        env.RepoID,
+		EnvironmentID: env.ID,
+		ListOptions:   utils.GetListOptions(ctx),
+	})
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+
+	variables := make([]*api.ActionVariable, len(vars))
+	for i, v := range vars {
+		variables[i] = &api.ActionVariable{
+			OwnerID:     v.OwnerID,
+			RepoID:      v.RepoID,
+			Name:        v.Name,
+			Data:        v.Data,
+			Description: v.Description,
+		}
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, variables)
+}
+
+// GetEnvironmentVariable get a variable of a deployment environment
+func GetEnvironmentVariable(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/environments/{environment_name}/variables/{variablename} repository repoGetEnvironmentVariable
+	// ---
+	// summary: Get a variable of a deployment environment
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repository
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: environment_name
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// - name: variablename
+	//   in: path
+	//   description: name of the variable
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionVariable"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	v := getEnvironmentVariableByPathParam(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &api.ActionVariable{
+		OwnerID:     v.OwnerID,
+		RepoID:      v.RepoID,
+		Name:        v.Name,
+		Data:        v.Data,
+		Description: v.Description,
+	})
+}
+
+// CreateEnvironmentVariable create a variable of a deployment environment
+func CreateEnvironmentVariable(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/environments/{environment_name}/variables/{variablename} repository repoCreateEnvironmentVariable
+	// ---
+	// summary: Create a variable of a deployment environment
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repository
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: environment_name
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// - name: variablename
+	//   in: path
+	//   description: name of the variable
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateVariableOption"
+	// responses:
+	//   "201":
+	//     description: response when creating a variable
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     description: variable name already exists.
+
+	env := getEnvironmentByPathParam(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	opt := web.GetForm(ctx).(*api.CreateVariableOption)
+	variableName := ctx.PathParam("variablename")
+
+	v, err := actions_service.GetVariable(ctx, actions_model.FindVariablesOpts{
+		RepoID:        env.RepoID,
+		EnvironmentID: env.ID,
+		Name:          variableName,
+	})
+	if err != nil && !errors.Is(err, util.ErrNotExist) {
+		ctx.APIErrorInternal(err)
+		return
+	}
+	if v != nil && v.ID > 0 {
+		ctx.APIError(http.StatusConflict, util.NewAlreadyExistErrorf("variable name %s already exists", variableName))
+		return
+	}
+
+	if _, err := actions_service.CreateEnvironmentVariable(ctx, env.RepoID, env.ID, variableName, opt.Value, opt.Description); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.APIError(http.StatusBadRequest, err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusCreated)
+}
+
+// UpdateEnvironmentVariable update a variable of a deployment environment
+func UpdateEnvironmentVariable(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/environments/{environment_name}/variables/{variablename} repository repoUpdateEnvironmentVariable
+	// ---
+	// summary: Update a variable of a deployment environment
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repository
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: environment_name
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// - name: variablename
+	//   in: path
+	//   description: name of the variable
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/UpdateVariableOption"
+	// responses:
+	//   "204":
+	//     description: response when updating a variable
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	v := getEnvironmentVariableByPathParam(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	opt := web.GetForm(ctx).(*api.UpdateVariableOption)
+	if opt.Name == "" {
+		opt.Name = ctx.PathParam("variablename")
+	}
+
+	v.Name = opt.Name
+	v.Data = opt.Value
+	v.Description = opt.Description
+
+	if _, err := actions_service.UpdateVariableNameData(ctx, v); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.APIError(http.StatusBadRequest, err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// DeleteEnvironmentVariable delete a variable of a deployment environment
+func DeleteEnvironmentVariable(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/environments/{environment_name}/variables/{variablename} repository repoDeleteEnvironmentVariable
+	// ---
+	// summary: Delete a variable of a deployment environment
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repository
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: environment_name
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// - name: variablename
+	//   in: path
+	//   description: name of the variable
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     description: variable deleted
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	v := getEnvironmentVariableByPathParam(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := actions_service.DeleteVariableByID(ctx, v.ID); err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListPendingDeployments list the environments waiting for reviews to deploy the jobs of a workflow run
+func ListPendingDeployments(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run}/pending_deployments repository listPendingDeployments
+	// ---
+	// summary: List the environments waiting for reviews to deploy the jobs of a workflow run
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repository
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionPendingDeploymentList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getRunByPathParam(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	pendingDeployments, err := actions_service.GetPendingDeployments(ctx, run)
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+
+	ret := make([]*api.ActionPendingDeployment, 0, len(pendingDeployments))
+	for _, pending := range pendingDeployments {
+		apiEnv, err := convert.ToActionEnvironment(ctx, pending.Environment, ctx.Doer)
+		if err != nil {
+			ctx.APIErrorInternal(err)
+			return
+		}
+		canApprove, err := actions_service.CanUserReviewEnvironment(ctx, pending.Environment, run, ctx.Doer)
+		if err != nil {
+			ctx.APIErrorInternal(err)
+			return
+		}
+		jobIDs := make([]int64, 0, len(pending.Jobs))
+		for _, job := range pending.Jobs {
+			jobIDs = append(jobIDs, job.ID)
+		}
+		ret = append(ret, &api.ActionPendingDeployment{
+			Environment:           apiEnv,
+			JobIDs:                jobIDs,
+			CurrentUserCanApprove: canApprove,
+		})
+	}
+	ctx.JSON(http.StatusOK, ret)
+}
+
+// ReviewPendingDeployments approve or reject the pending deployments of a workflow run
+func ReviewPendingDeployments(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runs/{run}/pending_deployments repository reviewPendingDeployments
+	// ---
+	// summary: Approve or reject the jobs of a workflow run waiting for reviews to deploy to the environments
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repository
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/ReviewPendingDeploymentsOption"
+	// responses:
+	//   "204":
+	//     description: the pending deployments have been reviewed
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getRunByPathParam(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	opt := web.GetForm(ctx).(*api.ReviewPendingDeploymentsOption)
+	state := actions_model.DeploymentReviewStateApproved
+	if opt.State == actions_model.DeploymentReviewStateRejected.String() {
+		state = actions_model.DeploymentReviewStateRejected
+	}
+
+	if err := actions_service.ReviewPendingDeployments(ctx, run, ctx.Doer, opt.EnvironmentIDs, state, opt.Comment); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.APIError(http.StatusBadRequest, err)
+		} else if errors.Is(err, util.ErrPermissionDenied) {
+			ctx.APIError(http.StatusForbidden, err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func getEnvironmentByPathParam(ctx *context.APIContext) *actions_model.ActionEnvironment {
+	env, err := actions_model.GetEnvironmentByRepoAndName(ctx, ctx.Repo.Repository.ID, ctx.PathParam("environment_name"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.APIError(http.StatusNotFound, err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+		return nil
+	}
+	return env
+}
+
+func getEnvironmentVariableByPathParam(ctx *context.APIContext) *actions_model.ActionVariable {
+	env := getEnvironmentByPathParam(ctx)
+	if ctx.Written() {
+		return nil
+	}
+	v, err := actions_service.GetVariable(ctx, actions_model.FindVariablesOpts{
+		RepoID:        env.RepoID,
+		EnvironmentID: env.ID,
+		Name:          ctx.PathParam("variablename"),
+	})
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.APIError(http.StatusNotFound, err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+		return nil
+	}
+	return v
+}
+
+func getRunByPathParam(ctx *context.APIContext) *actions_model.ActionRun {
+	run, err := actions_model.GetRunByRepoAndID(ctx, ctx.Repo.Repository.ID, ctx.PathParamInt64("run"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.APIError(http.StatusNotFound, err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+		return nil
+	}
+	return run
+}
@@ -15,6 +15,7 @@ import (
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/convert"
+	issue_service "code.gitea.io/gitea/services/issue"
 )
 
 // ListLabels list all the labels of a repository
@@ -165,7 +166,7 @@ func CreateLabel(ctx *context.APIContext) {
 		Description: form.Description,
 	}
 	l.SetArchived(form.IsArchived)
-	if err := issues_model.NewLabel(ctx, l); err != nil {
+	if err := issue_service.NewRepoLabel(ctx, ctx.Doer, ctx.Repo.Repository, l); err != nil {
 		ctx.APIErrorInternal(err)
 		return
 	}
@@ -240,7 +241,7 @@ func EditLabel(ctx *context.APIContext) {
 		l.Description = *form.Description
 	}
 	l.SetArchived(form.IsArchived != nil && *form.IsArchived)
-	if err := issues_model.UpdateLabel(ctx, l); err != nil {
+	if err := issue_service.UpdateRepoLabel(ctx, ctx.Doer, ctx.Repo.Repository, l); err != nil {
 		ctx.APIErrorInternal(err)
 		return
 	}
@@ -276,7 +277,7 @@ func DeleteLabel(ctx *context.APIContext) {
 	//   "404":
 	//     "$ref": "#/responses/notFound"
 
-	if err := issues_model.DeleteLabel(ctx, ctx.Repo.Repository.ID, ctx.PathParamInt64("id")); err != nil {
+	if err := issue_service.DeleteRepoLabel(ctx, ctx.Doer, ctx.Repo.Repository, ctx.PathParamInt64("id")); err != nil {
 		ctx.APIErrorInternal(err)
 		return
 	}
@@ -18,6 +18,7 @@ import (
 	"code.gitea.io/gitea/routers/common"
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/convert"
+	issue_service "code.gitea.io/gitea/services/issue"
 )
 
 // ListMilestones list milestones for a repository
@@ -172,7 +173,7 @@ func CreateMilestone(ctx *context.APIContext) {
 		milestone.ClosedDateUnix = timeutil.TimeStampNow()
 	}
 
-	if err := issues_model.NewMilestone(ctx, milestone); err != nil {
+	if err := issue_service.NewMilestone(ctx, ctx.Doer, ctx.Repo.Repository, milestone); err != nil {
 		ctx.APIErrorInternal(err)
 		return
 	}
@@ -232,7 +233,7 @@ func EditMilestone(ctx *context.APIContext) {
 		milestone.IsClosed = *form.State == string(api.StateClosed)
 	}
 
-	if err := issues_model.UpdateMilestone(ctx, milestone, oldIsClosed); err != nil {
+	if err := issue_service.UpdateMilestone(ctx, ctx.Doer, ctx.Repo.Repository, milestone, oldIsClosed); err != nil {
 		ctx.APIErrorInternal(err)
 		return
 	}
@@ -271,7 +272,7 @@ func DeleteMilestone(ctx *context.APIContext) {
 		return
 	}
 
-	if err := issues_model.DeleteMilestoneByRepoID(ctx, ctx.Repo.Repository.ID, m.ID); err != nil {
+	if err := issue_service.DeleteMilestone(ctx, ctx.Doer, ctx.Repo.Repository, m.ID); err != nil {
 		ctx.APIErrorInternal(err)
 		return
 	}
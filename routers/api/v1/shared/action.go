@@ -4,18 +4,28 @@
 package shared
 
 import (
+	"encoding/csv"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
 	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
+	csv_module "code.gitea.io/gitea/modules/csv"
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/modules/webhook"
 	"code.gitea.io/gitea/routers/api/v1/utils"
+	actions_service "code.gitea.io/gitea/services/actions"
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/convert"
 )
@@ -133,6 +143,11 @@ func ListRuns(ctx *context.APIContext, ownerID, repoID int64) {
 		opts.Ref = string(git.RefNameFromBranch(branch))
 	}
 	for _, status := range ctx.FormStrings("status") {
+		if status == "action_required" {
+			// the runs waiting for approval
+			opts.NeedApproval = true
+			continue
+		}
 		values, err := convertToInternal(status)
 		if err != nil {
 			ctx.APIError(http.StatusBadRequest, fmt.Errorf("Invalid status %s", status))
@@ -185,3 +200,318 @@ func ListRuns(ctx *context.APIContext, ownerID, repoID int64) {
 
 	ctx.JSON(http.StatusOK, &res)
 }
+
+// workflowPermissionsModes maps the default workflow permissions in the API to the token permission modes
+var workflowPermissionsModes = map[string]repo_model.ActionsTokenPermissionMode{
+	"":      "",
+	"read":  repo_model.ActionsTokenPermissionModeRestricted,
+	"write": repo_model.ActionsTokenPermissionModePermissive,
+}
+
+func toWorkflowPermissions(mode repo_model.ActionsTokenPermissionMode, inherited bool) *api.ActionWorkflowPermissions {
+	return &api.ActionWorkflowPermissions{
+		DefaultWorkflowPermissions: util.Iif(mode == repo_model.ActionsTokenPermissionModeRestricted, "read", "write"),
+		Inherited:                  inherited,
+	}
+}
+
+// GetWorkflowPermissions responds the default permissions of the tokens of the workflow jobs,
+// they belong to the repository if it isn't nil, otherwise they belong to the owner.
+func GetWorkflowPermissions(ctx *context.APIContext, ownerID int64, repo *repo_model.Repository) {
+	if repo == nil {
+		mode, err := actions_service.GetOwnerTokenPermissionMode(ctx, ownerID)
+		if err != nil {
+			ctx.APIErrorInternal(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, toWorkflowPermissions(mode, false))
+		return
+	}
+
+	cfgUnit, err := repo.GetUnit(ctx, unit.TypeActions)
+	if err != nil {
+		if repo_model.IsErrUnitTypeNotExist(err) {
+			ctx.APIErrorNotFound(err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+		return
+	}
+	mode, err := actions_service.GetRepoTokenPermissionMode(ctx, repo)
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, toWorkflowPermissions(mode, cfgUnit.ActionsConfig().TokenPermissionMode == ""))
+}
+
+// UpdateWorkflowPermissions updates the default permissions of the tokens of the workflow jobs,
+// they belong to the repository if it isn't nil, otherwise they belong to the owner.
+func UpdateWorkflowPermissions(ctx *context.APIContext, ownerID int64, repo *repo_model.Repository) {
+	opt := web.GetForm(ctx).(*api.EditActionWorkflowPermissionsOption)
+	mode, ok := workflowPermissionsModes[opt.DefaultWorkflowPermissions]
+	if !ok || (repo == nil && mode == "") {
+		ctx.APIError(http.StatusUnprocessableEntity, fmt.Sprintf("invalid default_workflow_permissions %q", opt.DefaultWorkflowPermissions))
+		return
+	}
+
+	var err error
+	if repo == nil {
+		err = actions_service.SetOwnerTokenPermissionMode(ctx, ownerID, mode)
+	} else {
+		err = actions_service.SetRepoTokenPermissionMode(ctx, repo, mode)
+	}
+	if err != nil {
+		if repo_model.IsErrUnitTypeNotExist(err) {
+			ctx.APIErrorNotFound(err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+		return
+	}
+	GetWorkflowPermissions(ctx, ownerID, repo)
+}
+
+// GetForkPRContributorApproval responds the approval policy of the runs triggered by the pull requests from forks,
+// it belongs to the repository if it isn't nil, otherwise it belongs to the owner.
+func GetForkPRContributorApproval(ctx *context.APIContext, ownerID int64, repo *repo_model.Repository) {
+	if repo == nil {
+		policy, err := actions_service.GetOwnerApprovalPolicy(ctx, ownerID)
+		if err != nil {
+			ctx.APIErrorInternal(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, &api.ActionForkPRContributorApproval{ApprovalPolicy: string(policy)})
+		return
+	}
+
+	cfgUnit, err := repo.GetUnit(ctx, unit.TypeActions)
+	if err != nil {
+		if repo_model.IsErrUnitTypeNotExist(err) {
+			ctx.APIErrorNotFound(err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+		return
+	}
+	policy, err := actions_service.GetRepoApprovalPolicy(ctx, repo)
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, &api.ActionForkPRContributorApproval{
+		ApprovalPolicy: string(policy),
+		Inherited:      cfgUnit.ActionsConfig().ApprovalPolicy == "",
+	})
+}
+
+// UpdateForkPRContributorApproval updates the approval policy of the runs triggered by the pull requests from forks,
+// it belongs to the repository if it isn't nil, otherwise it belongs to the owner.
+func UpdateForkPRContributorApproval(ctx *context.APIContext, ownerID int64, repo *repo_model.Repository) {
+	opt := web.GetForm(ctx).(*api.EditActionForkPRContributorApprovalOption)
+	policy := repo_model.ActionsApprovalPolicy(opt.ApprovalPolicy)
+	if !policy.IsValid() || (repo == nil && policy == "") {
+		ctx.APIError(http.StatusUnprocessableEntity, fmt.Sprintf("invalid approval_policy %q", opt.ApprovalPolicy))
+		return
+	}
+
+	var err error
+	if repo == nil {
+		err = actions_service.SetOwnerApprovalPolicy(ctx, ownerID, policy)
+	} else {
+		err = actions_service.SetRepoApprovalPolicy(ctx, repo, policy)
+	}
+	if err != nil {
+		if repo_model.IsErrUnitTypeNotExist(err) {
+			ctx.APIErrorNotFound(err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+		return
+	}
+	GetForkPRContributorApproval(ctx, ownerID, repo)
+}
+
+// GetLogRetention responds the retention period of the job logs,
+// it belongs to the repository if it isn't nil, otherwise it belongs to the owner.
+func GetLogRetention(ctx *context.APIContext, ownerID int64, repo *repo_model.Repository) {
+	if repo == nil {
+		days, err := actions_service.GetOwnerLogRetentionDays(ctx, ownerID)
+		if err != nil {
+			ctx.APIErrorInternal(err)
+			return
+		}
+		value, err := user_model.GetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsLogRetentionDays)
+		if err != nil {
+			ctx.APIErrorInternal(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, &api.ActionLogRetention{
+			Days:               days,
+			MaximumAllowedDays: setting.Actions.LogRetentionDays,
+			Inherited:          value == "",
+		})
+		return
+	}
+
+	cfgUnit, err := repo.GetUnit(ctx, unit.TypeActions)
+	if err != nil {
+		if repo_model.IsErrUnitTypeNotExist(err) {
+			ctx.APIErrorNotFound(err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+		return
+	}
+	days, err := actions_service.GetRepoLogRetentionDays(ctx, repo)
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, &api.ActionLogRetention{
+		Days:               days,
+		MaximumAllowedDays: setting.Actions.LogRetentionDays,
+		Inherited:          cfgUnit.ActionsConfig().LogRetentionDays == 0,
+	})
+}
+
+// UpdateLogRetention updates the retention period of the job logs,
+// it belongs to the repository if it isn't nil, otherwise it belongs to the owner.
+func UpdateLogRetention(ctx *context.APIContext, ownerID int64, repo *repo_model.Repository) {
+	opt := web.GetForm(ctx).(*api.EditActionLogRetentionOption)
+
+	var err error
+	if repo == nil {
+		err = actions_service.SetOwnerLogRetentionDays(ctx, ownerID, opt.Days)
+	} else {
+		err = actions_service.SetRepoLogRetentionDays(ctx, repo, opt.Days)
+	}
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.APIError(http.StatusUnprocessableEntity, err)
+		} else if repo_model.IsErrUnitTypeNotExist(err) {
+			ctx.APIErrorNotFound(err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+		return
+	}
+	GetLogRetention(ctx, ownerID, repo)
+}
+
+// GetUsage responds the running time of the jobs in the month of the `month` query parameter,
+// it belongs to the repository if it isn't nil, otherwise it belongs to the owner.
+// The report is in CSV if the `format` query parameter is "csv".
+func GetUsage(ctx *context.APIContext, ownerID int64, repo *repo_model.Repository) {
+	month, err := actions_service.ParseUsageMonth(ctx.FormString("month"))
+	if err != nil {
+		ctx.APIError(http.StatusUnprocessableEntity, err)
+		return
+	}
+	format := ctx.FormString("format")
+	if format != "" && format != "json" && format != "csv" {
+		ctx.APIError(http.StatusUnprocessableEntity, util.NewInvalidArgumentErrorf("invalid format %q", format))
+		return
+	}
+
+	var repoID int64
+	if repo != nil {
+		ownerID, repoID = 0, repo.ID
+	}
+	report, err := actions_service.GetUsageReport(ctx, ownerID, repoID, month)
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+
+	if format == "csv" {
+		ctx.Resp.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		ctx.Resp.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="actions-usage-%s.csv"`, month.Format(actions_service.UsageMonthFormat)))
+		ctx.Resp.WriteHeader(http.StatusOK)
+		w := csv.NewWriter(ctx.Resp)
+		_ = w.Write([]string{"repository", "labels", "jobs", "duration_seconds", "minutes"})
+		for _, usage := range report.Usages {
+			_ = w.Write([]string{
+				csv_module.EscapeFormula(usage.Repo.FullName()),
+				csv_module.EscapeFormula(strings.Join(usage.Labels, ",")),
+				strconv.FormatInt(usage.Jobs, 10),
+				strconv.FormatInt(usage.Seconds, 10),
+				strconv.FormatInt(usage.Minutes, 10),
+			})
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			log.Error("write the usage report: %v", err)
+		}
+		return
+	}
+
+	res := &api.ActionUsageReport{
+		Month:                month.Format(actions_service.UsageMonthFormat),
+		TotalDurationSeconds: report.TotalSeconds,
+		TotalMinutes:         report.TotalMinutes,
+		Usages:               make([]*api.ActionUsage, 0, len(report.Usages)),
+	}
+	for _, usage := range report.Usages {
+		res.Usages = append(res.Usages, &api.ActionUsage{
+			RepositoryID:    usage.Repo.ID,
+			Repository:      usage.Repo.FullName(),
+			Labels:          usage.Labels,
+			Jobs:            usage.Jobs,
+			DurationSeconds: usage.Seconds,
+			Minutes:         usage.Minutes,
+		})
+	}
+	if repo == nil {
+		if res.Quota, err = toActionUsageQuota(ctx, ownerID); err != nil {
+			ctx.APIErrorInternal(err)
+			return
+		}
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+func toActionUsageQuota(ctx *context.APIContext, ownerID int64) (*api.ActionUsageQuota, error) {
+	quota, err := actions_service.GetOwnerUsageQuota(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	month, _ := actions_service.ParseUsageMonth("")
+	used, err := actions_service.GetOwnerUsedMinutes(ctx, ownerID, month)
+	if err != nil {
+		return nil, err
+	}
+	return &api.ActionUsageQuota{
+		Minutes:     quota.Minutes,
+		Policy:      string(quota.Policy),
+		UsedMinutes: used,
+		Exceeded:    quota.Minutes > 0 && used >= quota.Minutes,
+	}, nil
+}
+
+// GetUsageQuota responds the monthly usage quota of the user or the organization
+func GetUsageQuota(ctx *context.APIContext, ownerID int64) {
+	quota, err := toActionUsageQuota(ctx, ownerID)
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, quota)
+}
+
+// UpdateUsageQuota updates the monthly usage quota of the user or the organization
+func UpdateUsageQuota(ctx *context.APIContext, ownerID int64) {
+	opt := web.GetForm(ctx).(*api.EditActionUsageQuotaOption)
+	if err := actions_service.SetOwnerUsageQuota(ctx, ownerID, &actions_service.UsageQuota{
+		Minutes: opt.Minutes,
+		Policy:  actions_model.UsageQuotaPolicy(opt.Policy),
+	}); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.APIError(http.StatusUnprocessableEntity, err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+		return
+	}
+	GetUsageQuota(ctx, ownerID)
+}
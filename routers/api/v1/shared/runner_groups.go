@@ -0,0 +1,207 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package shared
+
+import (
+	"errors"
+	"net/http"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// The runner groups of an owner are managed by the following functions,
+// ownerID == 0 means the instance-level groups, and ownerID != 0 means the groups of the given org.
+// Access rights are checked at the API route level.
+
+// ListRunnerGroups lists the runner groups of the owner
+func ListRunnerGroups(ctx *context.APIContext, ownerID int64) {
+	groups, count, err := db.FindAndCount[actions_model.ActionRunnerGroup](ctx, actions_model.FindRunnerGroupsOptions{
+		OwnerID:     ownerID,
+		ListOptions: utils.GetListOptions(ctx),
+	})
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+
+	apiGroups := make([]*api.ActionRunnerGroup, 0, len(groups))
+	for _, group := range groups {
+		apiGroups = append(apiGroups, convert.ToActionRunnerGroup(group))
+	}
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiGroups)
+}
+
+// CreateRunnerGroup creates a runner group of the owner
+func CreateRunnerGroup(ctx *context.APIContext, ownerID int64) {
+	opt := web.GetForm(ctx).(*api.CreateActionRunnerGroupOption)
+	group := &actions_model.ActionRunnerGroup{
+		OwnerID:          ownerID,
+		Name:             opt.Name,
+		RestrictRepos:    opt.Visibility == "selected",
+		RepoIDs:          opt.SelectedRepositoryIDs,
+		WorkflowPatterns: opt.WorkflowPatterns,
+		RefPatterns:      opt.RefPatterns,
+	}
+	if err := actions_service.CreateRunnerGroup(ctx, group); err != nil {
+		respondRunnerGroupError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToActionRunnerGroup(group))
+}
+
+// GetRunnerGroup gets a runner group of the owner
+func GetRunnerGroup(ctx *context.APIContext, ownerID, groupID int64) {
+	group := getRunnerGroupByID(ctx, ownerID, groupID)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToActionRunnerGroup(group))
+}
+
+// EditRunnerGroup edits a runner group of the owner
+func EditRunnerGroup(ctx *context.APIContext, ownerID, groupID int64) {
+	opt := web.GetForm(ctx).(*api.EditActionRunnerGroupOption)
+	group := getRunnerGroupByID(ctx, ownerID, groupID)
+	if ctx.Written() {
+		return
+	}
+
+	var cols []string
+	if opt.Name != nil {
+		group.Name = *opt.Name
+		cols = append(cols, "name")
+	}
+	if opt.Visibility != nil {
+		group.RestrictRepos = *opt.Visibility == "selected"
+		cols = append(cols, "restrict_repos")
+	}
+	if opt.SelectedRepositoryIDs != nil {
+		group.RepoIDs = opt.SelectedRepositoryIDs
+		cols = append(cols, "repo_ids")
+	}
+	if opt.WorkflowPatterns != nil {
+		group.WorkflowPatterns = opt.WorkflowPatterns
+		cols = append(cols, "workflow_patterns")
+	}
+	if opt.RefPatterns != nil {
+		group.RefPatterns = opt.RefPatterns
+		cols = append(cols, "ref_patterns")
+	}
+	if len(cols) > 0 {
+		if err := actions_service.UpdateRunnerGroup(ctx, group, cols...); err != nil {
+			respondRunnerGroupError(ctx, err)
+			return
+		}
+	}
+	ctx.JSON(http.StatusOK, convert.ToActionRunnerGroup(group))
+}
+
+// DeleteRunnerGroup deletes a runner group of the owner, its runners don't belong to any group after that
+func DeleteRunnerGroup(ctx *context.APIContext, ownerID, groupID int64) {
+	group := getRunnerGroupByID(ctx, ownerID, groupID)
+	if ctx.Written() {
+		return
+	}
+	if err := actions_model.DeleteRunnerGroup(ctx, group); err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListRunnerGroupRunners lists the runners of a runner group of the owner
+func ListRunnerGroupRunners(ctx *context.APIContext, ownerID, groupID int64) {
+	group := getRunnerGroupByID(ctx, ownerID, groupID)
+	if ctx.Written() {
+		return
+	}
+	runners, total, err := db.FindAndCount[actions_model.ActionRunner](ctx, &actions_model.FindRunnerOptions{
+		OwnerID:     ownerID,
+		GroupID:     group.ID,
+		ListOptions: utils.GetListOptions(ctx),
+	})
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+
+	res := new(api.ActionRunnersResponse)
+	res.TotalCount = total
+	res.Entries = make([]*api.ActionRunner, len(runners))
+	for i, runner := range runners {
+		res.Entries[i] = convert.ToActionRunner(ctx, runner)
+	}
+	ctx.JSON(http.StatusOK, &res)
+}
+
+// AddRunnerGroupRunner adds a runner of the owner to a runner group, the runner is removed from its previous group
+func AddRunnerGroupRunner(ctx *context.APIContext, ownerID, groupID, runnerID int64) {
+	group := getRunnerGroupByID(ctx, ownerID, groupID)
+	if ctx.Written() {
+		return
+	}
+	runner, ok := getRunnerByID(ctx, ownerID, 0, runnerID)
+	if !ok {
+		return
+	}
+	if err := actions_service.SetRunnerGroup(ctx, runner, group); err != nil {
+		respondRunnerGroupError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// RemoveRunnerGroupRunner removes a runner from a runner group of the owner
+func RemoveRunnerGroupRunner(ctx *context.APIContext, ownerID, groupID, runnerID int64) {
+	group := getRunnerGroupByID(ctx, ownerID, groupID)
+	if ctx.Written() {
+		return
+	}
+	runner, ok := getRunnerByID(ctx, ownerID, 0, runnerID)
+	if !ok {
+		return
+	}
+	if runner.GroupID != group.ID {
+		ctx.APIErrorNotFound("The runner doesn't belong to the runner group")
+		return
+	}
+	if err := actions_service.SetRunnerGroup(ctx, runner, nil); err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func getRunnerGroupByID(ctx *context.APIContext, ownerID, groupID int64) *actions_model.ActionRunnerGroup {
+	group, err := actions_model.GetRunnerGroupByOwnerAndID(ctx, ownerID, groupID)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.APIErrorNotFound(err)
+		} else {
+			ctx.APIErrorInternal(err)
+		}
+		return nil
+	}
+	return group
+}
+
+func respondRunnerGroupError(ctx *context.APIContext, err error) {
+	switch {
+	case errors.Is(err, util.ErrInvalidArgument):
+		ctx.APIError(http.StatusBadRequest, err)
+	case errors.Is(err, util.ErrAlreadyExist):
+		ctx.APIError(http.StatusConflict, err)
+	default:
+		ctx.APIErrorInternal(err)
+	}
+}
@@ -46,3 +46,108 @@ type swaggerResponseActionWorkflowList struct {
 	// in:body
 	Body api.ActionWorkflowResponse `json:"body"`
 }
+
+// InvalidWorkflowInputsError
+// swagger:response invalidWorkflowInputsError
+type swaggerResponseInvalidWorkflowInputsError struct {
+	// in:body
+	Body api.ActionWorkflowInputsError `json:"body"`
+}
+
+// ActionWorkflowScheduleList
+// swagger:response ActionWorkflowScheduleList
+type swaggerResponseActionWorkflowScheduleList struct {
+	// in:body
+	Body []api.ActionWorkflowSchedule `json:"body"`
+}
+
+// ActionAttestation
+// swagger:response ActionAttestation
+type swaggerResponseActionAttestation struct {
+	// in:body
+	Body api.ActionAttestation `json:"body"`
+}
+
+// ActionAttestationList
+// swagger:response ActionAttestationList
+type swaggerResponseActionAttestationList struct {
+	// in:body
+	Body []api.ActionAttestation `json:"body"`
+}
+
+// ActionAttestationVerification
+// swagger:response ActionAttestationVerification
+type swaggerResponseActionAttestationVerification struct {
+	// in:body
+	Body api.ActionAttestationVerification `json:"body"`
+}
+
+// ActionEnvironment
+// swagger:response ActionEnvironment
+type swaggerResponseActionEnvironment struct {
+	// in:body
+	Body api.ActionEnvironment `json:"body"`
+}
+
+// ActionEnvironmentList
+// swagger:response ActionEnvironmentList
+type swaggerResponseActionEnvironmentList struct {
+	// in:body
+	Body []api.ActionEnvironment `json:"body"`
+}
+
+// ActionRunnerGroup
+// swagger:response ActionRunnerGroup
+type swaggerResponseActionRunnerGroup struct {
+	// in:body
+	Body api.ActionRunnerGroup `json:"body"`
+}
+
+// ActionRunnerGroupList
+// swagger:response ActionRunnerGroupList
+type swaggerResponseActionRunnerGroupList struct {
+	// in:body
+	Body []api.ActionRunnerGroup `json:"body"`
+}
+
+// ActionPendingDeploymentList
+// swagger:response ActionPendingDeploymentList
+type swaggerResponseActionPendingDeploymentList struct {
+	// in:body
+	Body []api.ActionPendingDeployment `json:"body"`
+}
+
+// ActionWorkflowPermissions
+// swagger:response ActionWorkflowPermissions
+type swaggerResponseActionWorkflowPermissions struct {
+	// in:body
+	Body api.ActionWorkflowPermissions `json:"body"`
+}
+
+// ActionForkPRContributorApproval
+// swagger:response ActionForkPRContributorApproval
+type swaggerResponseActionForkPRContributorApproval struct {
+	// in:body
+	Body api.ActionForkPRContributorApproval `json:"body"`
+}
+
+// ActionLogRetention
+// swagger:response ActionLogRetention
+type swaggerResponseActionLogRetention struct {
+	// in:body
+	Body api.ActionLogRetention `json:"body"`
+}
+
+// ActionUsageReport
+// swagger:response ActionUsageReport
+type swaggerResponseActionUsageReport struct {
+	// in:body
+	Body api.ActionUsageReport `json:"body"`
+}
+
+// ActionUsageQuota
+// swagger:response ActionUsageQuota
+type swaggerResponseActionUsageQuota struct {
+	// in:body
+	Body api.ActionUsageQuota `json:"body"`
+}
@@ -217,9 +217,57 @@ type swaggerParameterBodies struct {
 	// in:body
 	CreateActionWorkflowDispatch api.CreateActionWorkflowDispatch
 
+	// in:body
+	CreateRepositoryDispatchOption api.CreateRepositoryDispatchOption
+
 	// in:body
 	UpdateVariableOption api.UpdateVariableOption
 
 	// in:body
 	LockIssueOption api.LockIssueOption
+
+	// in:body
+	CreateOrUpdateEnvironmentOption api.CreateOrUpdateEnvironmentOption
+
+	// in:body
+	ReviewPendingDeploymentsOption api.ReviewPendingDeploymentsOption
+
+	// in:body
+	EditActionWorkflowPermissionsOption api.EditActionWorkflowPermissionsOption
+
+	// in:body
+	EditActionForkPRContributorApprovalOption api.EditActionForkPRContributorApprovalOption
+
+	// in:body
+	ReviewActionRunsOption api.ReviewActionRunsOption
+
+	// in:body
+	RerunActionRunOption api.RerunActionRunOption
+
+	// in:body
+	CreateActionRunnerGroupOption api.CreateActionRunnerGroupOption
+
+	// in:body
+	EditActionRunnerGroupOption api.EditActionRunnerGroupOption
+
+	// in:body
+	CreateActionAttestationOption api.CreateActionAttestationOption
+
+	// in:body
+	VerifyActionAttestationOption api.VerifyActionAttestationOption
+
+	// in:body
+	EditActionLogRetentionOption api.EditActionLogRetentionOption
+
+	// in:body
+	EditActionUsageQuotaOption api.EditActionUsageQuotaOption
+
+	// in:body
+	SetPackageRemoteOption api.SetPackageRemoteOption
+
+	// in:body
+	CreatePackageCleanupRuleOption api.CreatePackageCleanupRuleOption
+
+	// in:body
+	EditPackageCleanupRuleOption api.EditPackageCleanupRuleOption
 }
@@ -27,3 +27,38 @@ type swaggerResponsePackageFileList struct {
 	// in:body
 	Body []api.PackageFile `json:"body"`
 }
+
+// PackageRemote
+// swagger:response PackageRemote
+type swaggerResponsePackageRemote struct {
+	// in:body
+	Body api.PackageRemote `json:"body"`
+}
+
+// PackageRemoteList
+// swagger:response PackageRemoteList
+type swaggerResponsePackageRemoteList struct {
+	// in:body
+	Body []api.PackageRemote `json:"body"`
+}
+
+// PackageCleanupRule
+// swagger:response PackageCleanupRule
+type swaggerResponsePackageCleanupRule struct {
+	// in:body
+	Body api.PackageCleanupRule `json:"body"`
+}
+
+// PackageCleanupRuleList
+// swagger:response PackageCleanupRuleList
+type swaggerResponsePackageCleanupRuleList struct {
+	// in:body
+	Body []api.PackageCleanupRule `json:"body"`
+}
+
+// PackageCleanupRunList
+// swagger:response PackageCleanupRunList
+type swaggerResponsePackageCleanupRunList struct {
+	// in:body
+	Body []api.PackageCleanupRun `json:"body"`
+}
@@ -463,6 +463,13 @@ type swaggerWorkflowRun struct {
 	Body api.ActionWorkflowRun `json:"body"`
 }
 
+// WorkflowStepSummaryList
+// swagger:response WorkflowStepSummaryList
+type swaggerActionWorkflowStepSummaryList struct {
+	// in:body
+	Body []api.ActionWorkflowStepSummary `json:"body"`
+}
+
 // WorkflowJobsList
 // swagger:response WorkflowJobsList
 type swaggerActionWorkflowJobsResponse struct {
@@ -440,3 +440,32 @@ func ListWorkflowJobs(ctx *context.APIContext) {
 
 	shared.ListJobs(ctx, ctx.Doer.ID, 0, 0)
 }
+
+// GetActionsUsage gets the monthly usage report of the jobs in the repositories of the authenticated user
+func GetActionsUsage(ctx *context.APIContext) {
+	// swagger:operation GET /user/actions/usage user getUserActionUsage
+	// ---
+	// summary: Get the monthly usage report of the jobs in the repositories of the authenticated user
+	// produces:
+	// - application/json
+	// - text/csv
+	// parameters:
+	// - name: month
+	//   in: query
+	//   description: the month of the report in UTC, like 2006-01, the default is the current month
+	//   type: string
+	// - name: format
+	//   in: query
+	//   description: the format of the report
+	//   type: string
+	//   enum: [json, csv]
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionUsageReport"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.GetUsage(ctx, ctx.Doer.ID, nil)
+}
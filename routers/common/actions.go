@@ -5,12 +5,18 @@ package common
 
 import (
 	"fmt"
+	"net/http"
+	"strconv"
 	"strings"
 
 	actions_model "code.gitea.io/gitea/models/actions"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/eventsource"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
+	actions_service "code.gitea.io/gitea/services/actions"
 	"code.gitea.io/gitea/services/context"
 )
 
@@ -47,7 +53,7 @@ func DownloadActionsRunJobLogs(ctx *context.Base, ctxRepo *repo_model.Repository
 	}
 
 	if task.LogExpired {
-		return util.NewNotExistErrorf("logs have been cleaned up")
+		return actions_model.ErrTaskLogExpired
 	}
 
 	reader, err := actions.OpenLogs(ctx, task.LogInStorage, task.LogFilename)
@@ -69,3 +75,82 @@ func DownloadActionsRunJobLogs(ctx *context.Base, ctxRepo *repo_model.Repository
 	})
 	return nil
 }
+
+// StreamActionsRunJobLogs streams the log of the job until all lines are sent after the job is done.
+// The lines are sent as server-sent events if the client accepts them, otherwise as plain text.
+// The stream starts from the line index in the "Last-Event-ID" header of a reconnecting event source or the "offset" parameter.
+func StreamActionsRunJobLogs(ctx *context.Base, ctxRepo *repo_model.Repository, curJob *actions_model.ActionRunJob) error {
+	if curJob.Repo.ID != ctxRepo.ID {
+		return util.NewNotExistErrorf("job not found")
+	}
+
+	if curJob.TaskID == 0 {
+		return util.NewNotExistErrorf("job not started")
+	}
+
+	task, err := actions_model.GetTaskByID(ctx, curJob.TaskID)
+	if err != nil {
+		return fmt.Errorf("GetTaskByID: %w", err)
+	}
+
+	if task.LogExpired {
+		return actions_model.ErrTaskLogExpired
+	}
+
+	offset := ctx.FormInt64("offset")
+	if lastEventID := ctx.Req.Header.Get("Last-Event-ID"); lastEventID != "" {
+		if offset, err = strconv.ParseInt(lastEventID, 10, 64); err != nil {
+			return util.NewInvalidArgumentErrorf("invalid Last-Event-ID %q", lastEventID)
+		}
+	}
+	if offset < 0 {
+		return util.NewInvalidArgumentErrorf("invalid offset %d", offset)
+	}
+
+	isEventStream := strings.Contains(ctx.Req.Header.Get("Accept"), "text/event-stream")
+	if isEventStream {
+		ctx.Resp.Header().Set("Content-Type", "text/event-stream")
+		ctx.Resp.Header().Set("Connection", "keep-alive")
+	} else {
+		ctx.Resp.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	ctx.Resp.Header().Set("Cache-Control", "no-cache")
+	ctx.Resp.Header().Set("X-Accel-Buffering", "no")
+	ctx.Resp.WriteHeader(http.StatusOK)
+	ctx.Resp.Flush()
+
+	send := func(chunk *api.ActionWorkflowJobLogChunk) error {
+		if isEventStream {
+			event := &eventsource.Event{Name: "ping"}
+			if len(chunk.Lines) > 0 {
+				event = &eventsource.Event{
+					Name: "logs",
+					Data: chunk,
+					ID:   strconv.FormatInt(chunk.Offset+int64(len(chunk.Lines)), 10),
+				}
+			}
+			if _, err := event.WriteTo(ctx.Resp); err != nil {
+				return err
+			}
+		} else {
+			for _, line := range chunk.Lines {
+				if _, err := fmt.Fprintln(ctx.Resp, line.Message); err != nil {
+					return err
+				}
+			}
+		}
+		ctx.Resp.Flush()
+		return nil
+	}
+	if err := actions_service.StreamTaskLogs(ctx, task.ID, offset, send); err != nil {
+		// the response has been started, so the error can only be logged
+		log.Debug("StreamTaskLogs for task %d: %v", task.ID, err)
+		return nil
+	}
+	if isEventStream {
+		// tell the event source not to reconnect
+		_, _ = (&eventsource.Event{Name: "end"}).WriteTo(ctx.Resp)
+		ctx.Resp.Flush()
+	}
+	return nil
+}
@@ -203,13 +203,18 @@ func NormalRoutes() *web.Router {
 		prefix := "/api/actions"
 		r.Mount(prefix, actions_router.Routes(prefix))
 
-		// TODO: Pipeline api used for runner internal communication with gitea server. but only artifact is used for now.
+		// TODO: Pipeline api used for runner internal communication with gitea server. but only artifact and step summary are used for now.
 		// In Github, it uses ACTIONS_RUNTIME_URL=https://pipelines.actions.githubusercontent.com/fLgcSHkPGySXeIFrg8W8OBSfeg3b5Fls1A1CwX566g8PayEGlg/
 		// TODO: this prefix should be generated with a token string with runner ?
 		prefix = "/api/actions_pipeline"
 		r.Mount(prefix, actions_router.ArtifactsRoutes(prefix))
 		prefix = actions_router.ArtifactV4RouteBase
 		r.Mount(prefix, actions_router.ArtifactsV4Routes(prefix))
+
+		prefix = "/api/actions_cache"
+		r.Mount(prefix, actions_router.CacheRoutes(prefix))
+		prefix = actions_router.CacheV4RouteBase
+		r.Mount(prefix, actions_router.CacheV4Routes(prefix))
 	}
 
 	r.NotFound(func(w http.ResponseWriter, req *http.Request) {
@@ -68,9 +68,16 @@ func MockActionsRunsJobs(ctx *context.Context) {
 	resp.State.Run.CanCancel = runID == 10
 	resp.State.Run.CanApprove = runID == 20
 	resp.State.Run.CanRerun = runID == 30
+	resp.State.Run.CanRerunFailed = runID == 30
 	resp.State.Run.CanDeleteArtifact = true
 	resp.State.Run.WorkflowID = "workflow-id"
 	resp.State.Run.WorkflowLink = "./workflow-link"
+	resp.State.Run.Attempt = 2
+	resp.State.Run.IsLatestAttempt = true
+	resp.State.Run.Attempts = []*actions.ViewRunAttempt{
+		{Attempt: 1, Status: actions_model.StatusFailure.String(), Duration: "1m2s"},
+		{Attempt: 2, Status: actions_model.StatusRunning.String(), Duration: "3s"},
+	}
 	resp.State.Run.Commit = actions.ViewCommit{
 		ShortSha: "ccccdddd",
 		Link:     "./commit-link",
@@ -87,9 +87,34 @@ func List(ctx *context.Context) {
 		return
 	}
 
+	prepareWorkflowSchedules(ctx)
+	if ctx.Written() {
+		return
+	}
+
 	ctx.HTML(http.StatusOK, tplListActions)
 }
 
+// prepareWorkflowSchedules shows the schedules of the current workflow with the next and the last scheduled times
+func prepareWorkflowSchedules(ctx *context.Context) {
+	workflowID := ctx.FormString("workflow")
+	if workflowID == "" {
+		return
+	}
+	specs, err := db.Find[actions_model.ActionScheduleSpec](ctx, actions_model.FindSpecOptions{RepoID: ctx.Repo.Repository.ID})
+	if err != nil {
+		ctx.ServerError("FindSpecs", err)
+		return
+	}
+	if err := actions_model.SpecList(specs).LoadSchedules(ctx); err != nil {
+		ctx.ServerError("LoadSchedules", err)
+		return
+	}
+	ctx.Data["CurWorkflowSchedules"] = slices.DeleteFunc(specs, func(spec *actions_model.ActionScheduleSpec) bool {
+		return spec.Schedule == nil || spec.Schedule.WorkflowID != workflowID
+	})
+}
+
 func WorkflowDispatchInputs(ctx *context.Context) {
 	ref := ctx.FormString("ref")
 	if ref == "" {
@@ -319,6 +344,19 @@ func prepareWorkflowList(ctx *context.Context, workflows []Workflow) {
 	ctx.Data["HasWorkflowsOrRuns"] = len(workflows) > 0 || len(runs) > 0
 
 	ctx.Data["CanWriteRepoUnitActions"] = ctx.Repo.CanWrite(unit.TypeActions)
+
+	if ctx.Repo.CanWrite(unit.TypeActions) {
+		numRunsNeedApproval, err := db.Count[actions_model.ActionRun](ctx, actions_model.FindRunOptions{
+			RepoID:       ctx.Repo.Repository.ID,
+			WorkflowID:   workflowID,
+			NeedApproval: true,
+		})
+		if err != nil {
+			ctx.ServerError("CountRunsNeedApproval", err)
+			return
+		}
+		ctx.Data["NumRunsNeedApproval"] = numRunsNeedApproval
+	}
 }
 
 // loadIsRefDeleted loads the IsRefDeleted field for each run in the list.
@@ -19,24 +19,23 @@ import (
 	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
 	git_model "code.gitea.io/gitea/models/git"
+	"code.gitea.io/gitea/models/renderhelper"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unit"
 	"code.gitea.io/gitea/modules/actions"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/markup/markdown"
 	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/templates"
-	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/common"
 	actions_service "code.gitea.io/gitea/services/actions"
 	context_module "code.gitea.io/gitea/services/context"
-	notify_service "code.gitea.io/gitea/services/notify"
 
 	"github.com/nektos/act/pkg/model"
-	"xorm.io/builder"
 )
 
 func getRunIndex(ctx *context_module.Context) int64 {
@@ -55,6 +54,7 @@ func View(ctx *context_module.Context) {
 	jobIndex := ctx.PathParamInt64("job")
 	ctx.Data["RunIndex"] = runIndex
 	ctx.Data["JobIndex"] = jobIndex
+	ctx.Data["Attempt"] = ctx.FormInt64("attempt")
 	ctx.Data["ActionsURL"] = ctx.Repo.RepoLink + "/actions"
 
 	if getRunJobs(ctx, runIndex, jobIndex); ctx.Written() {
@@ -64,6 +64,12 @@ func View(ctx *context_module.Context) {
 	ctx.HTML(http.StatusOK, tplViewActions)
 }
 
+// ViewSummary shows the summaries written by the steps of the jobs in the run
+func ViewSummary(ctx *context_module.Context) {
+	ctx.Data["IsViewSummary"] = true
+	View(ctx)
+}
+
 func ViewWorkflowFile(ctx *context_module.Context) {
 	runIndex := getRunIndex(ctx)
 	run, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, runIndex)
@@ -115,25 +121,30 @@ type ViewResponse struct {
 
 	State struct {
 		Run struct {
-			Link              string        `json:"link"`
-			Title             string        `json:"title"`
-			TitleHTML         template.HTML `json:"titleHTML"`
-			Status            string        `json:"status"`
-			CanCancel         bool          `json:"canCancel"`
-			CanApprove        bool          `json:"canApprove"` // the run needs an approval and the doer has permission to approve
-			CanRerun          bool          `json:"canRerun"`
-			CanDeleteArtifact bool          `json:"canDeleteArtifact"`
-			Done              bool          `json:"done"`
-			WorkflowID        string        `json:"workflowID"`
-			WorkflowLink      string        `json:"workflowLink"`
-			IsSchedule        bool          `json:"isSchedule"`
-			Jobs              []*ViewJob    `json:"jobs"`
-			Commit            ViewCommit    `json:"commit"`
+			Link              string            `json:"link"`
+			Title             string            `json:"title"`
+			TitleHTML         template.HTML     `json:"titleHTML"`
+			Status            string            `json:"status"`
+			CanCancel         bool              `json:"canCancel"`
+			CanApprove        bool              `json:"canApprove"` // the run needs an approval and the doer has permission to approve
+			CanRerun          bool              `json:"canRerun"`
+			CanRerunFailed    bool              `json:"canRerunFailed"`
+			CanDeleteArtifact bool              `json:"canDeleteArtifact"`
+			Done              bool              `json:"done"`
+			WorkflowID        string            `json:"workflowID"`
+			WorkflowLink      string            `json:"workflowLink"`
+			IsSchedule        bool              `json:"isSchedule"`
+			Attempt           int64             `json:"attempt"`
+			IsLatestAttempt   bool              `json:"isLatestAttempt"`
+			Attempts          []*ViewRunAttempt `json:"attempts"`
+			Jobs              []*ViewJob        `json:"jobs"`
+			Commit            ViewCommit        `json:"commit"`
 		} `json:"run"`
 		CurrentJob struct {
-			Title  string         `json:"title"`
-			Detail string         `json:"detail"`
-			Steps  []*ViewJobStep `json:"steps"`
+			Title       string         `json:"title"`
+			Detail      string         `json:"detail"`
+			LogsExpired bool           `json:"logsExpired"`
+			Steps       []*ViewJobStep `json:"steps"`
 		} `json:"currentJob"`
 	} `json:"state"`
 	Logs struct {
@@ -149,6 +160,12 @@ type ViewJob struct {
 	Duration string `json:"duration"`
 }
 
+type ViewRunAttempt struct {
+	Attempt  int64  `json:"attempt"`
+	Status   string `json:"status"`
+	Duration string `json:"duration"`
+}
+
 type ViewCommit struct {
 	ShortSha string     `json:"shortSHA"`
 	Link     string     `json:"link"`
@@ -186,12 +203,9 @@ type ViewStepLogLine struct {
 	Timestamp float64 `json:"timestamp"`
 }
 
-func getActionsViewArtifacts(ctx context.Context, repoID, runIndex int64) (artifactsViewItems []*ArtifactsViewItem, err error) {
-	run, err := actions_model.GetRunByIndex(ctx, repoID, runIndex)
-	if err != nil {
-		return nil, err
-	}
-	artifacts, err := actions_model.ListUploadedArtifactsMeta(ctx, run.ID)
+// getActionsViewArtifacts returns the artifacts of the attempt of the run, see ListUploadedArtifactsMeta
+func getActionsViewArtifacts(ctx context.Context, run *actions_model.ActionRun) (artifactsViewItems []*ArtifactsViewItem, err error) {
+	artifacts, err := actions_model.ListUploadedArtifactsMeta(ctx, run.ID, run.Attempt)
 	if err != nil {
 		return nil, err
 	}
@@ -214,34 +228,57 @@ func ViewPost(ctx *context_module.Context) {
 	if ctx.Written() {
 		return
 	}
+	latestRun := current.Run
+	runAttempts, err := actions_model.GetRunAttempts(ctx, latestRun.ID)
+	if err != nil {
+		ctx.ServerError("GetRunAttempts", err)
+		return
+	}
+	if current, jobs = getRunAttemptJobs(ctx, runAttempts, jobs, jobIndex); ctx.Written() {
+		return
+	}
 	run := current.Run
+	isLatestAttempt := run.Attempt == latestRun.Attempt
 	if err := run.LoadAttributes(ctx); err != nil {
 		ctx.ServerError("run.LoadAttributes", err)
 		return
 	}
 
-	var err error
 	resp := &ViewResponse{}
-	resp.Artifacts, err = getActionsViewArtifacts(ctx, ctx.Repo.Repository.ID, runIndex)
+	resp.Artifacts, err = getActionsViewArtifacts(ctx, run)
 	if err != nil {
-		if !errors.Is(err, util.ErrNotExist) {
-			ctx.ServerError("getActionsViewArtifacts", err)
-			return
-		}
+		ctx.ServerError("getActionsViewArtifacts", err)
+		return
 	}
 
 	// the title for the "run" is from the commit message
 	resp.State.Run.Title = run.Title
 	resp.State.Run.TitleHTML = templates.NewRenderUtils(ctx).RenderCommitMessage(run.Title, ctx.Repo.Repository)
 	resp.State.Run.Link = run.Link()
-	resp.State.Run.CanCancel = !run.Status.IsDone() && ctx.Repo.CanWrite(unit.TypeActions)
-	resp.State.Run.CanApprove = run.NeedApproval && ctx.Repo.CanWrite(unit.TypeActions)
-	resp.State.Run.CanRerun = run.Status.IsDone() && ctx.Repo.CanWrite(unit.TypeActions)
-	resp.State.Run.CanDeleteArtifact = run.Status.IsDone() && ctx.Repo.CanWrite(unit.TypeActions)
+	resp.State.Run.CanCancel = isLatestAttempt && !run.Status.IsDone() && ctx.Repo.CanWrite(unit.TypeActions)
+	resp.State.Run.CanApprove = isLatestAttempt && run.NeedApproval && ctx.Repo.CanWrite(unit.TypeActions)
+	resp.State.Run.CanRerun = isLatestAttempt && run.Status.IsDone() && ctx.Repo.CanWrite(unit.TypeActions)
+	resp.State.Run.CanRerunFailed = resp.State.Run.CanRerun && len(actions_service.GetFailedRerunJobs(jobs)) > 0
+	resp.State.Run.CanDeleteArtifact = isLatestAttempt && run.Status.IsDone() && ctx.Repo.CanWrite(unit.TypeActions)
 	resp.State.Run.Done = run.Status.IsDone()
 	resp.State.Run.WorkflowID = run.WorkflowID
 	resp.State.Run.WorkflowLink = run.WorkflowLink()
 	resp.State.Run.IsSchedule = run.IsSchedule()
+	resp.State.Run.Attempt = run.Attempt
+	resp.State.Run.IsLatestAttempt = isLatestAttempt
+	resp.State.Run.Attempts = make([]*ViewRunAttempt, 0, len(runAttempts)+1)
+	for _, v := range runAttempts {
+		resp.State.Run.Attempts = append(resp.State.Run.Attempts, &ViewRunAttempt{
+			Attempt:  v.Attempt,
+			Status:   v.Status.String(),
+			Duration: v.Duration().String(),
+		})
+	}
+	resp.State.Run.Attempts = append(resp.State.Run.Attempts, &ViewRunAttempt{
+		Attempt:  latestRun.Attempt,
+		Status:   latestRun.Status.String(),
+		Duration: latestRun.Duration().String(),
+	})
 	resp.State.Run.Jobs = make([]*ViewJob, 0, len(jobs)) // marshal to '[]' instead fo 'null' in json
 	resp.State.Run.Status = run.Status.String()
 	for _, v := range jobs {
@@ -299,6 +336,14 @@ func ViewPost(ctx *context_module.Context) {
 	if run.NeedApproval {
 		resp.State.CurrentJob.Detail = ctx.Locale.TrString("actions.need_approval_desc")
 	}
+	if run.QuotaExceeded {
+		if task == nil && current.Status.IsCancelled() {
+			// the job was cancelled without running since the owner had exceeded the hard quota
+			resp.State.CurrentJob.Detail = ctx.Locale.TrString("actions.usage_quota_blocked_desc")
+		} else {
+			resp.State.CurrentJob.Detail += " · " + ctx.Locale.TrString("actions.usage_quota_exceeded_desc")
+		}
+	}
 	resp.State.CurrentJob.Steps = make([]*ViewJobStep, 0) // marshal to '[]' instead fo 'null' in json
 	resp.Logs.StepsLog = make([]*ViewStepLog, 0)          // marshal to '[]' instead fo 'null' in json
 	if task != nil {
@@ -308,12 +353,66 @@ func ViewPost(ctx *context_module.Context) {
 			return
 		}
 		resp.State.CurrentJob.Steps = append(resp.State.CurrentJob.Steps, steps...)
+		resp.State.CurrentJob.LogsExpired = task.LogExpired
 		resp.Logs.StepsLog = append(resp.Logs.StepsLog, logs...)
 	}
 
 	ctx.JSON(http.StatusOK, resp)
 }
 
+type ViewSummaryResponse struct {
+	Summaries []*ViewStepSummary `json:"summaries"`
+}
+
+type ViewStepSummary struct {
+	JobID    int64         `json:"jobID"`
+	JobName  string        `json:"jobName"`
+	StepName string        `json:"stepName"`
+	HTML     template.HTML `json:"html"`
+}
+
+// SummaryPost returns the rendered summaries written by the steps of the jobs in the run
+func SummaryPost(ctx *context_module.Context) {
+	current, jobs := getRunJobs(ctx, getRunIndex(ctx), -1)
+	if ctx.Written() {
+		return
+	}
+	runAttempts, err := actions_model.GetRunAttempts(ctx, current.RunID)
+	if err != nil {
+		ctx.ServerError("GetRunAttempts", err)
+		return
+	}
+	if _, jobs = getRunAttemptJobs(ctx, runAttempts, jobs, -1); ctx.Written() {
+		return
+	}
+
+	summaries, err := actions_service.GetStepSummaries(ctx, jobs)
+	if err != nil {
+		ctx.ServerError("GetStepSummaries", err)
+		return
+	}
+	resp := &ViewSummaryResponse{
+		Summaries: make([]*ViewStepSummary, 0, len(summaries)), // marshal to '[]' instead fo 'null' in json
+	}
+	for _, summary := range summaries {
+		rctx := renderhelper.NewRenderContextRepoComment(ctx, ctx.Repo.Repository, renderhelper.RepoCommentOptions{
+			FootnoteContextID: strconv.FormatInt(summary.ID, 10),
+		})
+		html, err := markdown.RenderString(rctx, summary.Content)
+		if err != nil {
+			ctx.ServerError("RenderString", err)
+			return
+		}
+		resp.Summaries = append(resp.Summaries, &ViewStepSummary{
+			JobID:    summary.Job.ID,
+			JobName:  summary.Job.Name,
+			StepName: summary.StepName,
+			HTML:     html,
+		})
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
 func convertToViewModel(ctx *context_module.Context, cursors []LogCursor, task *actions_model.ActionTask) ([]*ViewJobStep, []*ViewStepLog, error) {
 	var viewJobs []*ViewJobStep
 	var logs []*ViewStepLog
@@ -396,7 +495,7 @@ func convertToViewModel(ctx *context_module.Context, cursors []LogCursor, task *
 	return viewJobs, logs, nil
 }
 
-// Rerun will rerun jobs in the given run
+// Rerun will rerun jobs in the given run as a new attempt
 // If jobIndexStr is a blank string, it means rerun all jobs
 func Rerun(ctx *context_module.Context) {
 	runIndex := getRunIndex(ctx)
@@ -406,89 +505,50 @@ func Rerun(ctx *context_module.Context) {
 		jobIndex, _ = strconv.ParseInt(jobIndexStr, 10, 64)
 	}
 
-	run, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, runIndex)
-	if err != nil {
-		ctx.ServerError("GetRunByIndex", err)
+	job, _ := getRunJobs(ctx, runIndex, jobIndex)
+	if ctx.Written() {
 		return
 	}
+	run := job.Run
 
-	// can not rerun job when workflow is disabled
-	cfgUnit := ctx.Repo.Repository.MustGetUnit(ctx, unit.TypeActions)
-	cfg := cfgUnit.ActionsConfig()
-	if cfg.IsWorkflowDisabled(run.WorkflowID) {
-		ctx.JSONError(ctx.Locale.Tr("actions.workflow.disabled"))
-		return
+	var err error
+	if jobIndexStr == "" { // rerun all jobs
+		err = actions_service.RerunRun(ctx, run, ctx.FormBool("debug"))
+	} else {
+		err = actions_service.RerunJob(ctx, run, job, ctx.FormBool("debug"))
 	}
-
-	// reset run's start and stop time when it is done
-	if run.Status.IsDone() {
-		run.PreviousDuration = run.Duration()
-		run.Started = 0
-		run.Stopped = 0
-		if err := actions_model.UpdateRun(ctx, run, "started", "stopped", "previous_duration"); err != nil {
-			ctx.ServerError("UpdateRun", err)
-			return
-		}
+	if err != nil {
+		respondRunOperationError(ctx, "Rerun", err)
+		return
 	}
+	ctx.JSONOK()
+}
 
-	job, jobs := getRunJobs(ctx, runIndex, jobIndex)
+// RerunFailed reruns the failed jobs and the jobs depending on them in the given run as a new attempt
+func RerunFailed(ctx *context_module.Context) {
+	job, _ := getRunJobs(ctx, getRunIndex(ctx), -1)
 	if ctx.Written() {
 		return
 	}
 
-	if jobIndexStr == "" { // rerun all jobs
-		for _, j := range jobs {
-			// if the job has needs, it should be set to "blocked" status to wait for other jobs
-			shouldBlock := len(j.Needs) > 0
-			if err := rerunJob(ctx, j, shouldBlock); err != nil {
-				ctx.ServerError("RerunJob", err)
-				return
-			}
-		}
-		ctx.JSONOK()
+	if err := actions_service.RerunFailedJobs(ctx, job.Run, ctx.FormBool("debug")); err != nil {
+		respondRunOperationError(ctx, "RerunFailedJobs", err)
 		return
 	}
-
-	rerunJobs := actions_service.GetAllRerunJobs(job, jobs)
-
-	for _, j := range rerunJobs {
-		// jobs other than the specified one should be set to "blocked" status
-		shouldBlock := j.JobID != job.JobID
-		if err := rerunJob(ctx, j, shouldBlock); err != nil {
-			ctx.ServerError("RerunJob", err)
-			return
-		}
-	}
-
 	ctx.JSONOK()
 }
 
-func rerunJob(ctx *context_module.Context, job *actions_model.ActionRunJob, shouldBlock bool) error {
-	status := job.Status
-	if !status.IsDone() || !job.Run.Status.IsDone() {
-		return nil
-	}
-
-	job.TaskID = 0
-	job.Status = actions_model.StatusWaiting
-	if shouldBlock {
-		job.Status = actions_model.StatusBlocked
+// respondRunOperationError responds the translated message if the operation on the run is not allowed
+func respondRunOperationError(ctx *context_module.Context, name string, err error) {
+	if errLocale := util.ErrorAsLocale(err); errLocale != nil {
+		ctx.JSONError(ctx.Tr(errLocale.TrKey, errLocale.TrArgs...))
+		return
 	}
-	job.Started = 0
-	job.Stopped = 0
-
-	if err := db.WithTx(ctx, func(ctx context.Context) error {
-		_, err := actions_model.UpdateRunJob(ctx, job, builder.Eq{"status": status}, "task_id", "status", "started", "stopped")
-		return err
-	}); err != nil {
-		return err
+	if errors.Is(err, util.ErrInvalidArgument) {
+		ctx.JSONError(err.Error())
+		return
 	}
-
-	actions_service.CreateCommitStatus(ctx, job)
-	actions_service.NotifyWorkflowRunStatusUpdateWithReload(ctx, job)
-	notify_service.WorkflowJobStatusUpdate(ctx, job.Run.Repo, job.Run.TriggerUser, job, nil)
-
-	return nil
+	ctx.ServerError(name, err)
 }
 
 func Logs(ctx *context_module.Context) {
@@ -503,115 +563,111 @@ func Logs(ctx *context_module.Context) {
 		return
 	}
 
+	if attempt := ctx.FormInt64("attempt"); attempt > 0 && attempt != run.Attempt {
+		// the logs of a previous attempt are found by the task recorded in the attempt
+		runAttempt, err := actions_model.GetRunAttempt(ctx, run.ID, attempt)
+		if err != nil {
+			ctx.NotFoundOrServerError("GetRunAttempt", func(err error) bool {
+				return errors.Is(err, util.ErrNotExist)
+			}, err)
+			return
+		}
+		jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+		if err != nil {
+			ctx.ServerError("GetRunJobsByRunID", err)
+			return
+		}
+		jobs = runAttempt.ApplyToJobs(jobs)
+		if jobIndex < 0 || jobIndex >= int64(len(jobs)) {
+			ctx.NotFound(nil)
+			return
+		}
+		job := jobs[jobIndex]
+		job.Repo = ctx.Repo.Repository
+		job.Run = runAttempt.ApplyToRun(run)
+		if err = common.DownloadActionsRunJobLogs(ctx.Base, ctx.Repo.Repository, job); err != nil {
+			handleLogsError(ctx, "DownloadActionsRunJobLogs", err)
+		}
+		return
+	}
+
 	if err = common.DownloadActionsRunJobLogsWithIndex(ctx.Base, ctx.Repo.Repository, run.ID, jobIndex); err != nil {
-		ctx.NotFoundOrServerError("DownloadActionsRunJobLogsWithIndex", func(err error) bool {
+		handleLogsError(ctx, "DownloadActionsRunJobLogsWithIndex", err)
+	}
+}
+
+// handleLogsError responds the error of reading the logs of a job, the expired logs are gone rather than not found
+func handleLogsError(ctx *context_module.Context, name string, err error) {
+	switch {
+	case errors.Is(err, actions_model.ErrTaskLogExpired):
+		ctx.HTTPError(http.StatusGone, ctx.Locale.TrString("actions.runs.expire_log_message"))
+	case errors.Is(err, util.ErrInvalidArgument):
+		ctx.HTTPError(http.StatusBadRequest, err.Error())
+	default:
+		ctx.NotFoundOrServerError(name, func(err error) bool {
 			return errors.Is(err, util.ErrNotExist)
 		}, err)
 	}
 }
 
-func Cancel(ctx *context_module.Context) {
-	runIndex := getRunIndex(ctx)
-
-	_, jobs := getRunJobs(ctx, runIndex, -1)
+// LogsStream streams the log of the running job of the latest attempt
+func LogsStream(ctx *context_module.Context) {
+	jobIndex := ctx.PathParamInt64("job")
+	job, jobs := getRunJobs(ctx, getRunIndex(ctx), jobIndex)
 	if ctx.Written() {
 		return
 	}
-
-	var updatedjobs []*actions_model.ActionRunJob
-
-	if err := db.WithTx(ctx, func(ctx context.Context) error {
-		for _, job := range jobs {
-			status := job.Status
-			if status.IsDone() {
-				continue
-			}
-			if job.TaskID == 0 {
-				job.Status = actions_model.StatusCancelled
-				job.Stopped = timeutil.TimeStampNow()
-				n, err := actions_model.UpdateRunJob(ctx, job, builder.Eq{"task_id": 0}, "status", "stopped")
-				if err != nil {
-					return err
-				}
-				if n == 0 {
-					return errors.New("job has changed, try again")
-				}
-				if n > 0 {
-					updatedjobs = append(updatedjobs, job)
-				}
-				continue
-			}
-			if err := actions_model.StopTask(ctx, job.TaskID, actions_model.StatusCancelled); err != nil {
-				return err
-			}
-		}
-		return nil
-	}); err != nil {
-		ctx.ServerError("StopTask", err)
+	if jobIndex < 0 || jobIndex >= int64(len(jobs)) {
+		ctx.NotFound(nil)
 		return
 	}
+	job.Repo = ctx.Repo.Repository
 
-	actions_service.CreateCommitStatus(ctx, jobs...)
-
-	for _, job := range updatedjobs {
-		_ = job.LoadAttributes(ctx)
-		notify_service.WorkflowJobStatusUpdate(ctx, job.Run.Repo, job.Run.TriggerUser, job, nil)
-	}
-	if len(updatedjobs) > 0 {
-		job := updatedjobs[0]
-		actions_service.NotifyWorkflowRunStatusUpdateWithReload(ctx, job)
-		notify_service.WorkflowRunStatusUpdate(ctx, job.Run.Repo, job.Run.TriggerUser, job.Run)
+	if err := common.StreamActionsRunJobLogs(ctx.Base, ctx.Repo.Repository, job); err != nil {
+		handleLogsError(ctx, "StreamActionsRunJobLogs", err)
 	}
-	ctx.JSON(http.StatusOK, struct{}{})
 }
 
-func Approve(ctx *context_module.Context) {
+func Cancel(ctx *context_module.Context) {
 	runIndex := getRunIndex(ctx)
 
-	current, jobs := getRunJobs(ctx, runIndex, -1)
+	job, _ := getRunJobs(ctx, runIndex, -1)
 	if ctx.Written() {
 		return
 	}
-	run := current.Run
-	doer := ctx.Doer
-
-	var updatedjobs []*actions_model.ActionRunJob
-
-	if err := db.WithTx(ctx, func(ctx context.Context) error {
-		run.NeedApproval = false
-		run.ApprovedBy = doer.ID
-		if err := actions_model.UpdateRun(ctx, run, "need_approval", "approved_by"); err != nil {
-			return err
-		}
-		for _, job := range jobs {
-			if len(job.Needs) == 0 && job.Status.IsBlocked() {
-				job.Status = actions_model.StatusWaiting
-				n, err := actions_model.UpdateRunJob(ctx, job, nil, "status")
-				if err != nil {
-					return err
-				}
-				if n > 0 {
-					updatedjobs = append(updatedjobs, job)
-				}
-			}
-		}
-		return nil
-	}); err != nil {
-		ctx.ServerError("UpdateRunJob", err)
+
+	if err := actions_service.CancelRun(ctx, job.Run); err != nil {
+		respondRunOperationError(ctx, "CancelRun", err)
 		return
 	}
+	ctx.JSON(http.StatusOK, struct{}{})
+}
 
-	actions_service.CreateCommitStatus(ctx, jobs...)
+func Approve(ctx *context_module.Context) {
+	approveOrRejectRun(ctx, true)
+}
 
-	if len(updatedjobs) > 0 {
-		job := updatedjobs[0]
-		actions_service.NotifyWorkflowRunStatusUpdateWithReload(ctx, job)
-		notify_service.WorkflowRunStatusUpdate(ctx, job.Run.Repo, job.Run.TriggerUser, job.Run)
+func Reject(ctx *context_module.Context) {
+	approveOrRejectRun(ctx, false)
+}
+
+func approveOrRejectRun(ctx *context_module.Context, isApprove bool) {
+	runIndex := getRunIndex(ctx)
+
+	current, _ := getRunJobs(ctx, runIndex, -1)
+	if ctx.Written() {
+		return
 	}
 
-	for _, job := range updatedjobs {
-		_ = job.LoadAttributes(ctx)
-		notify_service.WorkflowJobStatusUpdate(ctx, job.Run.Repo, job.Run.TriggerUser, job, nil)
+	var err error
+	if isApprove {
+		err = actions_service.ApproveRun(ctx, current.Run, ctx.Doer)
+	} else {
+		err = actions_service.RejectRun(ctx, current.Run, ctx.Doer)
+	}
+	if err != nil {
+		respondRunOperationError(ctx, "ApproveOrRejectRun", err)
+		return
 	}
 
 	ctx.JSON(http.StatusOK, struct{}{})
@@ -678,6 +734,39 @@ func getRunJobs(ctx *context_module.Context, runIndex, jobIndex int64) (*actions
 	return jobs[0], jobs
 }
 
+// getRunAttemptJobs returns the jobs of the attempt requested by the "attempt" parameter, the jobs of the latest attempt are returned if it's not set.
+// The jobs of a previous attempt are copies of the latest jobs with the results recorded in the attempt.
+func getRunAttemptJobs(ctx *context_module.Context, runAttempts []*actions_model.ActionRunAttempt, jobs []*actions_model.ActionRunJob, jobIndex int64) (*actions_model.ActionRunJob, []*actions_model.ActionRunJob) {
+	run := jobs[0].Run
+	attempt := ctx.FormInt64("attempt")
+	if attempt <= 0 || attempt == run.Attempt {
+		if jobIndex >= 0 && jobIndex < int64(len(jobs)) {
+			return jobs[jobIndex], jobs
+		}
+		return jobs[0], jobs
+	}
+
+	for _, runAttempt := range runAttempts {
+		if runAttempt.Attempt != attempt {
+			continue
+		}
+		attemptRun := runAttempt.ApplyToRun(run)
+		attemptJobs := runAttempt.ApplyToJobs(jobs)
+		if len(attemptJobs) == 0 {
+			break
+		}
+		for _, v := range attemptJobs {
+			v.Run = attemptRun
+		}
+		if jobIndex >= 0 && jobIndex < int64(len(attemptJobs)) {
+			return attemptJobs[jobIndex], attemptJobs
+		}
+		return attemptJobs[0], attemptJobs
+	}
+	ctx.NotFound(nil)
+	return nil, nil
+}
+
 func ArtifactsDeleteView(ctx *context_module.Context) {
 	runIndex := getRunIndex(ctx)
 	artifactName := ctx.PathParam("artifact_name")
@@ -689,7 +778,17 @@ func ArtifactsDeleteView(ctx *context_module.Context) {
 		}, err)
 		return
 	}
-	if err = actions_model.SetArtifactNeedDelete(ctx, run.ID, artifactName); err != nil {
+	// the artifact may be uploaded by a previous attempt if the latest attempt hasn't uploaded it again
+	artifacts, err := actions_model.GetRunAttemptArtifacts(ctx, run.ID, run.Attempt, artifactName)
+	if err != nil {
+		ctx.ServerError("GetRunAttemptArtifacts", err)
+		return
+	}
+	if len(artifacts) == 0 {
+		ctx.NotFound(nil)
+		return
+	}
+	if err = actions_model.SetArtifactNeedDelete(ctx, run.ID, artifacts[0].RunAttempt, artifactName); err != nil {
 		ctx.ServerError("SetArtifactNeedDelete", err)
 		return
 	}
@@ -710,12 +809,13 @@ func ArtifactsDownloadView(ctx *context_module.Context) {
 		return
 	}
 
-	artifacts, err := db.Find[actions_model.ActionArtifact](ctx, actions_model.FindArtifactsOptions{
-		RunID:        run.ID,
-		ArtifactName: artifactName,
-	})
+	attempt := ctx.FormInt64("attempt")
+	if attempt <= 0 {
+		attempt = run.Attempt
+	}
+	artifacts, err := actions_model.GetRunAttemptArtifacts(ctx, run.ID, attempt, artifactName)
 	if err != nil {
-		ctx.ServerError("FindArtifacts", err)
+		ctx.ServerError("GetRunAttemptArtifacts", err)
 		return
 	}
 	if len(artifacts) == 0 {
@@ -817,6 +917,51 @@ func disableOrEnableWorkflowFile(ctx *context_module.Context, isEnable bool) {
 	ctx.JSONRedirect(redirectURL)
 }
 
+func ApproveAllRuns(ctx *context_module.Context) {
+	approveOrRejectAllRuns(ctx, true)
+}
+
+func RejectAllRuns(ctx *context_module.Context) {
+	approveOrRejectAllRuns(ctx, false)
+}
+
+// approveOrRejectAllRuns approves or rejects all runs of the workflow waiting for approval, or of all workflows if no workflow is given
+func approveOrRejectAllRuns(ctx *context_module.Context, isApprove bool) {
+	workflow := ctx.FormString("workflow")
+	runs, err := db.Find[actions_model.ActionRun](ctx, actions_model.FindRunOptions{
+		RepoID:       ctx.Repo.Repository.ID,
+		WorkflowID:   workflow,
+		NeedApproval: true,
+	})
+	if err != nil {
+		ctx.ServerError("FindRuns", err)
+		return
+	}
+
+	for _, run := range runs {
+		run.Repo = ctx.Repo.Repository
+		if isApprove {
+			err = actions_service.ApproveRun(ctx, run, ctx.Doer)
+		} else {
+			err = actions_service.RejectRun(ctx, run, ctx.Doer)
+		}
+		if err != nil {
+			ctx.ServerError("ApproveOrRejectRun", err)
+			return
+		}
+	}
+
+	if isApprove {
+		ctx.Flash.Success(ctx.Tr("actions.runs.approve_all_success", len(runs)))
+	} else {
+		ctx.Flash.Success(ctx.Tr("actions.runs.reject_all_success", len(runs)))
+	}
+
+	redirectURL := fmt.Sprintf("%s/actions?workflow=%s&actor=%s&status=%s", ctx.Repo.RepoLink, url.QueryEscape(workflow),
+		url.QueryEscape(ctx.FormString("actor")), url.QueryEscape(ctx.FormString("status")))
+	ctx.JSONRedirect(redirectURL)
+}
+
 func Run(ctx *context_module.Context) {
 	redirectURL := fmt.Sprintf("%s/actions?workflow=%s&actor=%s&status=%s", ctx.Repo.RepoLink, url.QueryEscape(ctx.FormString("workflow")),
 		url.QueryEscape(ctx.FormString("actor")), url.QueryEscape(ctx.FormString("status")))
@@ -832,15 +977,12 @@ func Run(ctx *context_module.Context) {
 		ctx.ServerError("ref", nil)
 		return
 	}
-	err := actions_service.DispatchActionWorkflow(ctx, ctx.Doer, ctx.Repo.Repository, ctx.Repo.GitRepo, workflowID, ref, func(workflowDispatch *model.WorkflowDispatch, inputs map[string]any) error {
+	err := actions_service.DispatchActionWorkflow(ctx, ctx.Doer, ctx.Repo.Repository, ctx.Repo.GitRepo, workflowID, ref, func(workflowDispatch *model.WorkflowDispatch, inputs map[string]string) error {
 		for name, config := range workflowDispatch.Inputs {
-			value := ctx.Req.PostFormValue(name)
 			if config.Type == "boolean" {
 				inputs[name] = strconv.FormatBool(ctx.FormBool(name))
-			} else if value != "" {
-				inputs[name] = value
 			} else {
-				inputs[name] = config.Default
+				inputs[name] = ctx.Req.PostFormValue(name)
 			}
 		}
 		return nil
@@ -199,19 +199,17 @@ func httpBase(ctx *context.Context) *serviceHandler {
 					return nil
 				}
 
-				if task.IsForkPullRequest {
-					if accessMode > perm.AccessModeRead {
-						ctx.PlainText(http.StatusForbidden, "User permission denied")
-						return nil
-					}
-					environ = append(environ, fmt.Sprintf("%s=%d", repo_module.EnvActionPerm, perm.AccessModeRead))
-				} else {
-					if accessMode > perm.AccessModeWrite {
-						ctx.PlainText(http.StatusForbidden, "User permission denied")
-						return nil
-					}
-					environ = append(environ, fmt.Sprintf("%s=%d", repo_module.EnvActionPerm, perm.AccessModeWrite))
+				p, err := access_model.GetActionsUserRepoPermission(ctx, repo, task)
+				if err != nil {
+					ctx.ServerError("GetActionsUserRepoPermission", err)
+					return nil
+				}
+				taskAccessMode := min(p.UnitAccessMode(unitType), perm.AccessModeWrite)
+				if accessMode > taskAccessMode {
+					ctx.PlainText(http.StatusForbidden, "User permission denied")
+					return nil
 				}
+				environ = append(environ, fmt.Sprintf("%s=%d", repo_module.EnvActionPerm, taskAccessMode))
 			} else {
 				p, err := access_model.GetUserRepoPermission(ctx, repo, ctx.Doer)
 				if err != nil {
@@ -122,8 +122,7 @@ func NewMilestonePost(ctx *context.Context) {
 		return
 	}
 
-	if err := issues_model.NewMilestone(ctx, &issues_model.Milestone{
-		RepoID:       ctx.Repo.Repository.ID,
+	if err := issue.NewMilestone(ctx, ctx.Doer, ctx.Repo.Repository, &issues_model.Milestone{
 		Name:         form.Title,
 		Content:      form.Content,
 		DeadlineUnix: deadlineUnix,
@@ -190,7 +189,7 @@ func EditMilestonePost(ctx *context.Context) {
 	m.Name = form.Title
 	m.Content = form.Content
 	m.DeadlineUnix = deadlineUnix
-	if err = issues_model.UpdateMilestone(ctx, m, m.IsClosed); err != nil {
+	if err = issue.UpdateMilestone(ctx, ctx.Doer, ctx.Repo.Repository, m, m.IsClosed); err != nil {
 		ctx.ServerError("UpdateMilestone", err)
 		return
 	}
@@ -213,7 +212,7 @@ func ChangeMilestoneStatus(ctx *context.Context) {
 	}
 	id := ctx.PathParamInt64("id")
 
-	if err := issues_model.ChangeMilestoneStatusByRepoIDAndID(ctx, ctx.Repo.Repository.ID, id, toClose); err != nil {
+	if err := issue.ChangeMilestoneStatus(ctx, ctx.Doer, ctx.Repo.Repository, id, toClose); err != nil {
 		if issues_model.IsErrMilestoneNotExist(err) {
 			ctx.NotFound(err)
 		} else {
@@ -226,7 +225,7 @@ func ChangeMilestoneStatus(ctx *context.Context) {
 
 // DeleteMilestone delete a milestone
 func DeleteMilestone(ctx *context.Context) {
-	if err := issues_model.DeleteMilestoneByRepoID(ctx, ctx.Repo.Repository.ID, ctx.FormInt64("id")); err != nil {
+	if err := issue.DeleteMilestone(ctx, ctx.Doer, ctx.Repo.Repository, ctx.FormInt64("id")); err != nil {
 		ctx.Flash.Error("DeleteMilestoneByRepoID: " + err.Error())
 	} else {
 		ctx.Flash.Success(ctx.Tr("repo.milestones.deletion_success"))
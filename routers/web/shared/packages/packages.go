@@ -4,20 +4,20 @@
 package packages
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
-	"time"
 
 	packages_model "code.gitea.io/gitea/models/packages"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/log"
-	"code.gitea.io/gitea/modules/optional"
 	"code.gitea.io/gitea/modules/templates"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/forms"
 	cargo_service "code.gitea.io/gitea/services/packages/cargo"
-	container_service "code.gitea.io/gitea/services/packages/container"
+	cleanup_service "code.gitea.io/gitea/services/packages/cleanup"
 )
 
 func SetPackagesContext(ctx *context.Context, owner *user_model.User) {
@@ -91,6 +91,8 @@ func performRuleEditPost(ctx *context.Context, owner *user_model.User, pcr *pack
 	pcr.OwnerID = owner.ID
 	pcr.KeepCount = form.KeepCount
 	pcr.KeepPattern = form.KeepPattern
+	pcr.KeepSemverRange = form.KeepSemverRange
+	pcr.KeepLatestPerMajor = form.KeepLatestPerMajor
 	pcr.RemoveDays = form.RemoveDays
 	pcr.RemovePattern = form.RemovePattern
 	pcr.MatchFullName = form.MatchFullName
@@ -104,28 +106,26 @@ func performRuleEditPost(ctx *context.Context, owner *user_model.User, pcr *pack
 		return
 	}
 
+	var err error
 	if isEditRule {
-		if err := packages_model.UpdateCleanupRule(ctx, pcr); err != nil {
-			ctx.ServerError("UpdateCleanupRule", err)
-			return
-		}
+		err = cleanup_service.UpdateRule(ctx, pcr)
 	} else {
 		pcr.Type = packages_model.Type(form.Type)
-
-		if has, err := packages_model.HasOwnerCleanupRuleForPackageType(ctx, owner.ID, pcr.Type); err != nil {
-			ctx.ServerError("HasOwnerCleanupRuleForPackageType", err)
-			return
-		} else if has {
+		pcr, err = cleanup_service.CreateRule(ctx, pcr)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, util.ErrAlreadyExist):
 			ctx.Data["Err_Type"] = true
 			ctx.HTML(http.StatusOK, template)
-			return
-		}
-
-		var err error
-		if pcr, err = packages_model.InsertCleanupRule(ctx, pcr); err != nil {
-			ctx.ServerError("InsertCleanupRule", err)
-			return
+		case errors.Is(err, util.ErrInvalidArgument):
+			// the other fields are validated by the form binding
+			ctx.Data["Err_KeepSemverRange"] = true
+			ctx.RenderWithErr(ctx.Tr("packages.owner.settings.cleanuprules.keep.semver_range.invalid"), template, form)
+		default:
+			ctx.ServerError("SaveCleanupRule", err)
 		}
+		return
 	}
 
 	ctx.Flash.Success(ctx.Tr("packages.owner.settings.cleanuprules.success.update"))
@@ -138,69 +138,12 @@ func SetRulePreviewContext(ctx *context.Context, owner *user_model.User) {
 		return
 	}
 
-	if err := pcr.CompiledPattern(); err != nil {
-		ctx.ServerError("CompiledPattern", err)
-		return
-	}
-
-	olderThan := time.Now().AddDate(0, 0, -pcr.RemoveDays)
-
-	packages, err := packages_model.GetPackagesByType(ctx, pcr.OwnerID, pcr.Type)
+	versionsToRemove, err := cleanup_service.PreviewCleanupRule(ctx, pcr)
 	if err != nil {
-		ctx.ServerError("GetPackagesByType", err)
+		ctx.ServerError("PreviewCleanupRule", err)
 		return
 	}
 
-	versionsToRemove := make([]*packages_model.PackageDescriptor, 0, 10)
-
-	for _, p := range packages {
-		pvs, _, err := packages_model.SearchVersions(ctx, &packages_model.PackageSearchOptions{
-			PackageID:  p.ID,
-			IsInternal: optional.Some(false),
-			Sort:       packages_model.SortCreatedDesc,
-		})
-		if err != nil {
-			ctx.ServerError("SearchVersions", err)
-			return
-		}
-		if pcr.KeepCount > 0 {
-			if pcr.KeepCount < len(pvs) {
-				pvs = pvs[pcr.KeepCount:]
-			} else {
-				pvs = nil
-			}
-		}
-		for _, pv := range pvs {
-			if skip, err := container_service.ShouldBeSkipped(ctx, pcr, p, pv); err != nil {
-				ctx.ServerError("ShouldBeSkipped", err)
-				return
-			} else if skip {
-				continue
-			}
-
-			toMatch := pv.LowerVersion
-			if pcr.MatchFullName {
-				toMatch = p.LowerName + "/" + pv.LowerVersion
-			}
-			if pcr.KeepPatternMatcher != nil && pcr.KeepPatternMatcher.MatchString(toMatch) {
-				continue
-			}
-			if pv.CreatedUnix.AsLocalTime().After(olderThan) {
-				continue
-			}
-			if pcr.RemovePatternMatcher != nil && !pcr.RemovePatternMatcher.MatchString(toMatch) {
-				continue
-			}
-
-			pd, err := packages_model.GetPackageDescriptor(ctx, pv)
-			if err != nil {
-				ctx.ServerError("GetPackageDescriptor", err)
-				return
-			}
-			versionsToRemove = append(versionsToRemove, pd)
-		}
-	}
-
 	ctx.Data["CleanupRule"] = pcr
 	ctx.Data["VersionsToRemove"] = versionsToRemove
 }
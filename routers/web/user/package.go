@@ -35,6 +35,7 @@ import (
 	"code.gitea.io/gitea/services/forms"
 	packages_service "code.gitea.io/gitea/services/packages"
 	container_service "code.gitea.io/gitea/services/packages/container"
+	hex_service "code.gitea.io/gitea/services/packages/hex"
 )
 
 const (
@@ -483,6 +484,10 @@ func PackageSettingsPost(ctx *context.Context) {
 		return
 	case "delete":
 		err := packages_service.RemovePackageVersion(ctx, ctx.Doer, ctx.Package.Descriptor.Version)
+		if err == nil && ctx.Package.Descriptor.Package.Type == packages_model.TypeHex {
+			// the signed resources of the Hex registry must not list the deleted version
+			err = hex_service.BuildSpecificRepositoryFiles(ctx, ctx.Package.Owner.ID, ctx.Package.Descriptor.Package.Name)
+		}
 		if err != nil {
 			log.Error("Error deleting package: %v", err)
 			ctx.Flash.Error(ctx.Tr("packages.settings.delete.error"))
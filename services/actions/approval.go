@@ -0,0 +1,114 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// GetOwnerApprovalPolicy returns the approval policy of the runs from forks in the repositories owned by the user or the organization,
+// it requires approval for first-time contributors if the owner doesn't set it.
+func GetOwnerApprovalPolicy(ctx context.Context, ownerID int64) (repo_model.ActionsApprovalPolicy, error) {
+	value, err := user_model.GetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsApprovalPolicy)
+	if err != nil {
+		return "", err
+	}
+	if policy := repo_model.ActionsApprovalPolicy(value); policy != "" && policy.IsValid() {
+		return policy, nil
+	}
+	return repo_model.ActionsApprovalPolicyFirstTimeContributors, nil
+}
+
+// SetOwnerApprovalPolicy sets the approval policy of the runs from forks in the repositories owned by the user or the organization
+func SetOwnerApprovalPolicy(ctx context.Context, ownerID int64, policy repo_model.ActionsApprovalPolicy) error {
+	if policy == "" || !policy.IsValid() {
+		return util.NewInvalidArgumentErrorf("invalid approval policy %q", policy)
+	}
+	return user_model.SetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsApprovalPolicy, string(policy))
+}
+
+// GetRepoApprovalPolicy returns the approval policy of the runs from forks in the repository,
+// it follows the owner of the repository if the repository doesn't set it.
+func GetRepoApprovalPolicy(ctx context.Context, repo *repo_model.Repository) (repo_model.ActionsApprovalPolicy, error) {
+	cfgUnit, err := repo.GetUnit(ctx, unit.TypeActions)
+	if err != nil && !repo_model.IsErrUnitTypeNotExist(err) {
+		return "", err
+	}
+	if cfgUnit != nil {
+		if policy := cfgUnit.ActionsConfig().ApprovalPolicy; policy != "" {
+			return policy, nil
+		}
+	}
+	return GetOwnerApprovalPolicy(ctx, repo.OwnerID)
+}
+
+// SetRepoApprovalPolicy sets the approval policy of the runs from forks in the repository,
+// the empty policy means following the owner of the repository.
+func SetRepoApprovalPolicy(ctx context.Context, repo *repo_model.Repository, policy repo_model.ActionsApprovalPolicy) error {
+	if !policy.IsValid() {
+		return util.NewInvalidArgumentErrorf("invalid approval policy %q", policy)
+	}
+	cfgUnit, err := repo.GetUnit(ctx, unit.TypeActions)
+	if err != nil {
+		return err
+	}
+	cfgUnit.ActionsConfig().ApprovalPolicy = policy
+	return repo_model.UpdateRepoUnit(ctx, cfgUnit)
+}
+
+// ApproveRun approves a run waiting for approval.
+// Its jobs keep blocked and are started by the job emitter, which checks the concurrency groups of the run and the jobs.
+func ApproveRun(ctx context.Context, run *actions_model.ActionRun, doer *user_model.User) error {
+	if !run.NeedApproval {
+		return util.NewInvalidArgumentErrorf("run %d doesn't need approval", run.ID)
+	}
+
+	run.NeedApproval = false
+	run.ApprovedBy = doer.ID
+	if err := actions_model.UpdateRun(ctx, run, "need_approval", "approved_by"); err != nil {
+		return err
+	}
+
+	if err := EmitJobsIfReady(run.ID); err != nil {
+		log.Error("Emit ready jobs of run %d: %v", run.ID, err)
+	}
+	return nil
+}
+
+// RejectRun rejects a run waiting for approval and cancels all of its jobs
+func RejectRun(ctx context.Context, run *actions_model.ActionRun, doer *user_model.User) error {
+	if !run.NeedApproval {
+		return util.NewInvalidArgumentErrorf("run %d doesn't need approval", run.ID)
+	}
+
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+	if err != nil {
+		return fmt.Errorf("GetRunJobsByRunID: %w", err)
+	}
+
+	var cancelledJobs []*actions_model.ActionRunJob
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		run.NeedApproval = false
+		if err := actions_model.UpdateRun(ctx, run, "need_approval"); err != nil {
+			return err
+		}
+		cancelledJobs, err = actions_model.CancelJobs(ctx, jobs)
+		return err
+	}); err != nil {
+		return err
+	}
+	log.Trace("run %d is rejected by user %d", run.ID, doer.ID)
+
+	notifyWorkflowJobStatusUpdate(ctx, cancelledJobs)
+	return nil
+}
@@ -0,0 +1,362 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/perm"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/attestation"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/util"
+)
+
+const (
+	// SLSAProvenancePredicateType is the predicate type of the build provenance attestations
+	SLSAProvenancePredicateType = "https://slsa.dev/provenance/v1"
+	// AttestationBuildType describes how the Actions workflows build the software artifacts
+	AttestationBuildType = "https://gitea.com/gitea/actions/buildtypes/workflow/v1"
+)
+
+// SLSAProvenance is the predicate of the build provenance attestations, see https://slsa.dev/spec/v1.0/provenance
+type SLSAProvenance struct {
+	BuildDefinition SLSABuildDefinition `json:"buildDefinition"`
+	RunDetails      SLSARunDetails      `json:"runDetails"`
+}
+
+type SLSABuildDefinition struct {
+	BuildType            string                    `json:"buildType"`
+	ExternalParameters   SLSAExternalParameters    `json:"externalParameters"`
+	InternalParameters   map[string]string         `json:"internalParameters"`
+	ResolvedDependencies []*SLSAResourceDescriptor `json:"resolvedDependencies"`
+}
+
+type SLSAExternalParameters struct {
+	Workflow SLSAWorkflow `json:"workflow"`
+}
+
+type SLSAWorkflow struct {
+	Ref        string `json:"ref"`
+	Repository string `json:"repository"`
+	Path       string `json:"path"`
+}
+
+type SLSAResourceDescriptor struct {
+	URI    string            `json:"uri"`
+	Digest map[string]string `json:"digest"`
+}
+
+type SLSARunDetails struct {
+	Builder  SLSABuilder       `json:"builder"`
+	Metadata SLSABuildMetadata `json:"metadata"`
+}
+
+type SLSABuilder struct {
+	ID      string            `json:"id"`
+	Version map[string]string `json:"version,omitempty"`
+}
+
+type SLSABuildMetadata struct {
+	InvocationID string     `json:"invocationId"`
+	StartedOn    *time.Time `json:"startedOn,omitempty"`
+}
+
+// GetAttestationSigningKey returns the instance key to sign the attestations and its ID,
+// it's the key of the ID tokens so the attestations can be verified by the published JWKS
+func GetAttestationSigningKey() (attestation.Key, string, error) {
+	key, err := GetIDTokenSigningKey()
+	if err != nil {
+		return nil, "", err
+	}
+	jwk, err := key.ToJWK()
+	if err != nil {
+		return nil, "", err
+	}
+	return key, jwk["kid"], nil
+}
+
+// CreateAttestationOptions are the options to create an attestation for a software artifact built by a task
+type CreateAttestationOptions struct {
+	SubjectName   string
+	SubjectDigest string
+	// ArtifactName is the name of an artifact uploaded by the run to attach the attestation to
+	ArtifactName string
+	// PackageType, PackageName and PackageVersion identify a package version of the repository owner to attach the attestation to
+	PackageType    string
+	PackageName    string
+	PackageVersion string
+}
+
+// findAttestedArtifact returns the ID of the artifact uploaded by the run which has a file of the sha256 digest,
+// the attestation must describe the content of the artifact, or it could be attached to anything
+func findAttestedArtifact(ctx context.Context, run *actions_model.ActionRun, artifactName, digest string) (int64, error) {
+	artifacts, err := actions_model.GetRunAttemptArtifacts(ctx, run.ID, run.Attempt, artifactName)
+	if err != nil {
+		return 0, err
+	}
+	artifacts = slices.DeleteFunc(artifacts, func(art *actions_model.ActionArtifact) bool {
+		return art.Status != actions_model.ArtifactStatusUploadConfirmed
+	})
+	if len(artifacts) == 0 {
+		return 0, util.NewNotExistErrorf("artifact %q of the run does not exist", artifactName)
+	}
+
+	for _, art := range artifacts {
+		sum, err := artifactFileSHA256(art)
+		if err != nil {
+			return 0, fmt.Errorf("read artifact %d: %w", art.ID, err)
+		}
+		if sum == digest {
+			return art.ID, nil
+		}
+	}
+	return 0, util.NewInvalidArgumentErrorf("the subject digest doesn't match any file of the artifact")
+}
+
+// artifactFileSHA256 returns the hex sha256 of the file of the artifact as it was uploaded
+func artifactFileSHA256(art *actions_model.ActionArtifact) (string, error) {
+	f, err := storage.ActionsArtifacts.Open(art.StoragePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	// the files of the v3 artifacts may be stored compressed, the v4 artifacts are stored as the uploaded zip files
+	if art.ContentEncoding == "gzip" {
+		gzr, err := gzip.NewReader(f)
+		if err != nil {
+			return "", err
+		}
+		defer gzr.Close()
+		r = gzr
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// CreateAttestation creates a signed build provenance attestation of a software artifact built by the running task
+func CreateAttestation(ctx context.Context, task *actions_model.ActionTask, opts CreateAttestationOptions) (*actions_model.ActionAttestation, error) {
+	if !task.Status.IsRunning() {
+		return nil, util.NewPermissionDeniedErrorf("task %d is not running", task.ID)
+	}
+	if err := task.LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+	if task.TokenPermissions[actions_model.TokenScopeAttestations] < perm.AccessModeWrite {
+		return nil, util.NewPermissionDeniedErrorf("the job requires the permission attestations: write")
+	}
+	algorithm, digest, err := attestation.ParseDigest(opts.SubjectDigest)
+	if err != nil {
+		return nil, util.NewInvalidArgumentErrorf("%v", err)
+	}
+
+	job, run := task.Job, task.Job.Run
+	if err := run.LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+	attest := &actions_model.ActionAttestation{
+		RepoID:        task.RepoID,
+		OwnerID:       task.OwnerID,
+		RunID:         run.ID,
+		TaskID:        task.ID,
+		SubjectName:   opts.SubjectName,
+		SubjectDigest: algorithm + ":" + digest,
+		PredicateType: SLSAProvenancePredicateType,
+	}
+
+	if opts.ArtifactName != "" {
+		artifactID, err := findAttestedArtifact(ctx, run, opts.ArtifactName, digest)
+		if err != nil {
+			return nil, err
+		}
+		attest.ArtifactID = artifactID
+	}
+
+	if opts.PackageType != "" || opts.PackageName != "" || opts.PackageVersion != "" {
+		if task.TokenPermissions[actions_model.TokenScopePackages] < perm.AccessModeWrite {
+			return nil, util.NewPermissionDeniedErrorf("the job requires the permission packages: write to attach attestations to packages")
+		}
+		pv, err := packages_model.GetVersionByNameAndVersion(ctx, run.Repo.OwnerID, packages_model.Type(opts.PackageType), opts.PackageName, opts.PackageVersion)
+		if err != nil {
+			if errors.Is(err, packages_model.ErrPackageNotExist) {
+				return nil, util.NewNotExistErrorf("package %s %s %s does not exist", opts.PackageType, opts.PackageName, opts.PackageVersion)
+			}
+			return nil, err
+		}
+		// the attestation must describe one of the files of the package version, or it could be attached to anything
+		has, err := packages_model.HasFiles(ctx, &packages_model.PackageFileSearchOptions{
+			VersionID:     pv.ID,
+			HashAlgorithm: algorithm,
+			Hash:          digest,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !has {
+			return nil, util.NewInvalidArgumentErrorf("the subject digest doesn't match any file of the package version")
+		}
+		attest.PackageVersionID = pv.ID
+	}
+
+	predicate, err := generateSLSAProvenance(ctx, task, run, job)
+	if err != nil {
+		return nil, err
+	}
+	key, keyID, err := GetAttestationSigningKey()
+	if err != nil {
+		return nil, err
+	}
+	envelope, err := attestation.Sign(&attestation.Statement{
+		Type:          attestation.StatementType,
+		Subject:       []*attestation.Subject{{Name: opts.SubjectName, Digest: map[string]string{algorithm: digest}}},
+		PredicateType: SLSAProvenancePredicateType,
+		Predicate:     predicate,
+	}, keyID, key)
+	if err != nil {
+		return nil, err
+	}
+	envelopeJSON, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, err
+	}
+	attest.KeyID = keyID
+	attest.Envelope = string(envelopeJSON)
+
+	if err := db.Insert(ctx, attest); err != nil {
+		return nil, err
+	}
+	return attest, nil
+}
+
+func generateSLSAProvenance(ctx context.Context, task *actions_model.ActionTask, run *actions_model.ActionRun, job *actions_model.ActionRunJob) (*SLSAProvenance, error) {
+	giteaCtx := GenerateGiteaContext(run, job)
+	ref := util.GetMapValueOrDefault(giteaCtx, "ref", "")
+	sha := util.GetMapValueOrDefault(giteaCtx, "sha", "")
+
+	runner, err := actions_model.GetRunnerByID(ctx, task.RunnerID)
+	if err != nil && !errors.Is(err, util.ErrNotExist) {
+		return nil, err
+	}
+	runnerName, runnerVersion := "", ""
+	if runner != nil {
+		runnerName, runnerVersion = runner.Name, runner.Version
+	}
+
+	provenance := &SLSAProvenance{
+		BuildDefinition: SLSABuildDefinition{
+			BuildType: AttestationBuildType,
+			ExternalParameters: SLSAExternalParameters{
+				Workflow: SLSAWorkflow{
+					Ref:        ref,
+					Repository: run.Repo.HTMLURL(),
+					Path:       run.WorkflowID,
+				},
+			},
+			InternalParameters: map[string]string{
+				"event_name":          run.TriggerEvent,
+				"repository_id":       strconv.FormatInt(run.RepoID, 10),
+				"repository_owner_id": strconv.FormatInt(run.Repo.OwnerID, 10),
+				"run_id":              strconv.FormatInt(run.ID, 10),
+				"run_number":          strconv.FormatInt(run.Index, 10),
+				"run_attempt":         strconv.FormatInt(run.Attempt, 10),
+				"job":                 job.JobID,
+				"runner_id":           strconv.FormatInt(task.RunnerID, 10),
+				"runner_name":         runnerName,
+			},
+			ResolvedDependencies: []*SLSAResourceDescriptor{{
+				URI:    fmt.Sprintf("git+%s@%s", run.Repo.HTMLURL(), ref),
+				Digest: map[string]string{"gitCommit": sha},
+			}},
+		},
+		RunDetails: SLSARunDetails{
+			Builder: SLSABuilder{ID: fmt.Sprintf("%sactions/runners/%d", setting.AppURL, task.RunnerID)},
+			Metadata: SLSABuildMetadata{
+				InvocationID: fmt.Sprintf("%s/attempts/%d", run.HTMLURL(), run.Attempt),
+			},
+		},
+	}
+	if runnerVersion != "" {
+		provenance.RunDetails.Builder.Version = map[string]string{"runner": runnerVersion}
+	}
+	if task.Started > 0 {
+		provenance.RunDetails.Metadata.StartedOn = util.ToPointer(task.Started.AsTime().UTC())
+	}
+	return provenance, nil
+}
+
+// AttestationVerification is the result of verifying an attestation
+type AttestationVerification struct {
+	Statement *attestation.Statement
+	KeyID     string
+	// Attestation is the stored attestation of the repository with the same envelope, it's nil if not found
+	Attestation *actions_model.ActionAttestation
+}
+
+// VerifyAttestation verifies the envelope is signed by the instance key and the artifact was built by the repository,
+// subjectDigest is optional, the statement must have the subject if it's given.
+func VerifyAttestation(ctx context.Context, repo *repo_model.Repository, envelope *attestation.Envelope, subjectDigest string) (*AttestationVerification, error) {
+	key, keyID, err := GetAttestationSigningKey()
+	if err != nil {
+		return nil, err
+	}
+	statement, err := attestation.Verify(envelope, keyID, key)
+	if err != nil {
+		return nil, util.NewInvalidArgumentErrorf("%v", err)
+	}
+	if subjectDigest != "" && !statement.HasSubject(subjectDigest) {
+		return nil, util.NewInvalidArgumentErrorf("the attestation has no subject with the digest %s", subjectDigest)
+	}
+	if statement.PredicateType != SLSAProvenancePredicateType {
+		return nil, util.NewInvalidArgumentErrorf("unsupported predicate type %q", statement.PredicateType)
+	}
+	predicateJSON, err := json.Marshal(statement.Predicate)
+	if err != nil {
+		return nil, err
+	}
+	var provenance SLSAProvenance
+	if err := json.Unmarshal(predicateJSON, &provenance); err != nil {
+		return nil, util.NewInvalidArgumentErrorf("invalid predicate: %v", err)
+	}
+	if provenance.BuildDefinition.ExternalParameters.Workflow.Repository != repo.HTMLURL() {
+		return nil, util.NewInvalidArgumentErrorf("the artifact was not built by the repository %s", repo.FullName())
+	}
+
+	verification := &AttestationVerification{Statement: statement, KeyID: keyID}
+	if runID, err := strconv.ParseInt(provenance.BuildDefinition.InternalParameters["run_id"], 10, 64); err == nil {
+		attestations, err := db.Find[actions_model.ActionAttestation](ctx, actions_model.FindAttestationsOptions{RepoID: repo.ID, RunID: runID})
+		if err != nil {
+			return nil, err
+		}
+		envelopeJSON, err := json.Marshal(envelope)
+		if err != nil {
+			return nil, err
+		}
+		for _, attest := range attestations {
+			if attest.Envelope == string(envelopeJSON) {
+				verification.Attestation = attest
+				break
+			}
+		}
+	}
+	return verification, nil
+}
@@ -0,0 +1,164 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/optional"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// incompleteCacheTimeout is how long an uploading cache is kept before it's considered abandoned
+const incompleteCacheTimeout = 24 * time.Hour
+
+// GetCacheRefsOfTask returns the ref which the caches created by the task are scoped to,
+// and the refs whose caches can be restored by the task in order of priority:
+// the ref of the run, the base branch of the pull request and the default branch of the repository.
+func GetCacheRefsOfTask(ctx context.Context, task *actions_model.ActionTask) (string, []string, error) {
+	if err := task.LoadJob(ctx); err != nil {
+		return "", nil, err
+	}
+	if err := task.Job.LoadRun(ctx); err != nil {
+		return "", nil, err
+	}
+	run := task.Job.Run
+	if err := run.LoadRepo(ctx); err != nil {
+		return "", nil, err
+	}
+
+	refs := []string{run.Ref}
+	addRef := func(ref string) {
+		for _, r := range refs {
+			if r == ref {
+				return
+			}
+		}
+		refs = append(refs, ref)
+	}
+	if run.Event.IsPullRequest() {
+		payload, err := run.GetPullRequestEventPayload()
+		if err != nil {
+			return "", nil, err
+		}
+		if payload.PullRequest != nil && payload.PullRequest.Base != nil && payload.PullRequest.Base.Ref != "" {
+			addRef(git.RefNameFromBranch(payload.PullRequest.Base.Ref).String())
+		}
+	}
+	if run.Repo.DefaultBranch != "" {
+		addRef(git.RefNameFromBranch(run.Repo.DefaultBranch).String())
+	}
+	return run.Ref, refs, nil
+}
+
+// DeleteCache deletes a cache with its content and uploading chunks in the storage
+func DeleteCache(ctx context.Context, cache *actions_model.ActionCache) error {
+	if err := actions_model.DeleteCacheByID(ctx, cache.ID); err != nil {
+		return err
+	}
+	if err := storage.ActionsCaches.Delete(cache.StoragePath()); err != nil {
+		log.Warn("Cannot delete the content of cache %d: %v", cache.ID, err)
+	}
+	DeleteCacheTmpChunks(cache)
+	return nil
+}
+
+// DeleteCacheTmpChunks deletes the uploading chunks of a cache in the storage
+func DeleteCacheTmpChunks(cache *actions_model.ActionCache) {
+	if err := storage.ActionsCaches.IterateObjects(cache.TmpStorageDir(), func(path string, _ storage.Object) error {
+		return storage.ActionsCaches.Delete(path)
+	}); err != nil {
+		log.Warn("Cannot delete the uploading chunks of cache %d: %v", cache.ID, err)
+	}
+}
+
+// EvictRepoCaches removes the least recently used caches of a repository until the total size doesn't exceed the limit
+func EvictRepoCaches(ctx context.Context, repoID int64) error {
+	size, err := actions_model.GetRepoCacheSize(ctx, repoID)
+	if err != nil {
+		return err
+	}
+	for size > setting.Actions.CacheMaxSize {
+		caches, err := db.Find[actions_model.ActionCache](ctx, actions_model.FindCachesOptions{
+			ListOptions:         db.ListOptions{PageSize: deleteCacheBatchSize},
+			RepoID:              repoID,
+			IsComplete:          optional.Some(true),
+			OrderByLastAccessed: true,
+		})
+		if err != nil {
+			return err
+		}
+		if len(caches) == 0 {
+			break
+		}
+		for _, cache := range caches {
+			if size <= setting.Actions.CacheMaxSize {
+				break
+			}
+			if err := DeleteCache(ctx, cache); err != nil {
+				return fmt.Errorf("delete cache %d: %w", cache.ID, err)
+			}
+			size -= cache.Size
+			log.Trace("Cache %d of repo %d is deleted (due to size limit)", cache.ID, repoID)
+		}
+	}
+	return nil
+}
+
+// deleteCacheBatchSize is the batch size of deleting caches
+const deleteCacheBatchSize = 100
+
+// CleanupCaches removes the caches which haven't been accessed in the retention period,
+// the abandoned uploading caches, and the least recently used caches of the repositories exceeding the size limit.
+func CleanupCaches(ctx context.Context) error {
+	count := 0
+	for _, opts := range []actions_model.FindCachesOptions{
+		{
+			IsComplete:       optional.Some(true),
+			LastAccessBefore: timeutil.TimeStampNow().AddDuration(-time.Duration(setting.Actions.CacheRetentionDays) * 24 * time.Hour),
+		},
+		{
+			IsComplete:    optional.Some(false),
+			CreatedBefore: timeutil.TimeStampNow().AddDuration(-incompleteCacheTimeout),
+		},
+	} {
+		opts.ListOptions = db.ListOptions{PageSize: deleteCacheBatchSize}
+		for {
+			// the deleted caches are not found again, so always query the first page
+			caches, err := db.Find[actions_model.ActionCache](ctx, opts)
+			if err != nil {
+				return fmt.Errorf("find caches: %w", err)
+			}
+			if len(caches) == 0 {
+				break
+			}
+			for _, cache := range caches {
+				if err := DeleteCache(ctx, cache); err != nil {
+					return fmt.Errorf("delete cache %d: %w", cache.ID, err)
+				}
+				count++
+			}
+		}
+	}
+	log.Info("Removed %d expired caches", count)
+
+	repoSizes, err := actions_model.FindReposExceedingCacheSize(ctx, setting.Actions.CacheMaxSize)
+	if err != nil {
+		return fmt.Errorf("find repos exceeding cache size: %w", err)
+	}
+	for _, repoSize := range repoSizes {
+		if err := EvictRepoCaches(ctx, repoSize.RepoID); err != nil {
+			log.Error("Cannot evict caches of repo %d: %v", repoSize.RepoID, err)
+		}
+	}
+	return nil
+}
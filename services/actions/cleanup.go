@@ -7,10 +7,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
 	actions_module "code.gitea.io/gitea/modules/actions"
 	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/log"
@@ -105,23 +107,72 @@ func removeTaskLog(ctx context.Context, task *actions_model.ActionTask) {
 		log.Error("Failed to remove log %s (in storage %v) of task %v: %v", task.LogFilename, task.LogInStorage, task.ID, err)
 		// do not return error here, go on
 	}
+	removeStaleTaskLog(task)
 }
 
-// CleanupExpiredLogs removes logs which are older than the configured retention time
+func removeStaleTaskLog(task *actions_model.ActionTask) {
+	if task.StaleLogFilename == "" {
+		return
+	}
+	if err := storage.Actions.Delete(task.StaleLogFilename); err != nil {
+		log.Error("Failed to remove stale log %s of task %v: %v", task.StaleLogFilename, task.ID, err)
+		// do not return error here, go on
+	}
+	task.StaleLogFilename = ""
+}
+
+// CleanupExpiredLogs removes logs which are older than the configured retention time,
+// the repositories and their owners can have shorter retention periods than the instance.
 func CleanupExpiredLogs(ctx context.Context) error {
 	olderThan := timeutil.TimeStampNow().AddDuration(-time.Duration(setting.Actions.LogRetentionDays) * 24 * time.Hour)
+	count, err := expireLogs(ctx, olderThan, 0)
+	if err != nil {
+		return err
+	}
+
+	// the logs can't be kept for less than a day, so only the repositories having logs older than a day need to be checked
+	repoIDs, err := actions_model.FindRepoIDsWithOldTasksToExpire(ctx, timeutil.TimeStampNow().AddDuration(-24*time.Hour))
+	if err != nil {
+		return fmt.Errorf("find repositories with old tasks: %w", err)
+	}
+	for _, repoID := range repoIDs {
+		repo, err := repo_model.GetRepositoryByID(ctx, repoID)
+		if err != nil {
+			log.Error("Failed to get repository %d: %v", repoID, err)
+			continue
+		}
+		days, err := GetRepoLogRetentionDays(ctx, repo)
+		if err != nil {
+			log.Error("Failed to get log retention days of repository %d: %v", repoID, err)
+			continue
+		}
+		if days >= setting.Actions.LogRetentionDays {
+			continue
+		}
+		n, err := expireLogs(ctx, timeutil.TimeStampNow().AddDuration(-time.Duration(days)*24*time.Hour), repoID)
+		if err != nil {
+			return err
+		}
+		count += n
+	}
 
+	log.Info("Removed %d logs", count)
+	return nil
+}
+
+// expireLogs removes the logs of the tasks older than olderThan, the tasks belong to the repository if repoID isn't 0
+func expireLogs(ctx context.Context, olderThan timeutil.TimeStamp, repoID int64) (int, error) {
 	count := 0
 	for {
-		tasks, err := actions_model.FindOldTasksToExpire(ctx, olderThan, deleteLogBatchSize)
+		tasks, err := actions_model.FindOldTasksToExpire(ctx, olderThan, repoID, deleteLogBatchSize)
 		if err != nil {
-			return fmt.Errorf("find old tasks: %w", err)
+			return count, fmt.Errorf("find old tasks: %w", err)
 		}
 		for _, task := range tasks {
 			removeTaskLog(ctx, task)
 			task.LogIndexes = nil // clear log indexes since it's a heavy field
 			task.LogExpired = true
-			if err := actions_model.UpdateTask(ctx, task, "log_indexes", "log_expired"); err != nil {
+			if err := actions_model.UpdateTask(ctx, task, "log_indexes", "log_expired", "stale_log_filename"); err != nil {
 				log.Error("Failed to update task %v: %v", task.ID, err)
 				// do not return error here, continue to next task
 				continue
@@ -133,8 +184,111 @@ func CleanupExpiredLogs(ctx context.Context) error {
 			break
 		}
 	}
+	return count, nil
+}
 
-	log.Info("Removed %d logs", count)
+// logArchiveGracePeriod is how long to wait after a task is stopped before archiving its log,
+// since the runners may still upload the last lines of the tasks stopped by the server
+const logArchiveGracePeriod = 24 * time.Hour
+
+// staleLogGracePeriod is how long to keep the uncompressed log after compressing it,
+// so the requests which opened the log before the compression can finish reading it
+const staleLogGracePeriod = time.Hour
+
+// ArchiveLogs moves the logs of the stopped tasks which are left in DBFS to object storage,
+// and compresses the uncompressed logs in object storage with zstd if the log compression is enabled.
+// The uncompressed logs replaced by the previous runs are removed once they are no longer read.
+func ArchiveLogs(ctx context.Context) error {
+	if err := removeStaleLogs(ctx); err != nil {
+		return err
+	}
+
+	stoppedBefore := timeutil.TimeStampNow().AddDuration(-logArchiveGracePeriod)
+	compress := setting.Actions.LogCompression.IsZstd()
+
+	count := 0
+	var lastID int64
+	for {
+		tasks, err := actions_model.FindTasksToArchiveLogs(ctx, stoppedBefore, compress, lastID, deleteLogBatchSize)
+		if err != nil {
+			return fmt.Errorf("find tasks to archive logs: %w", err)
+		}
+		for _, task := range tasks {
+			lastID = task.ID
+			if err := archiveTaskLog(ctx, task, compress); err != nil {
+				log.Error("Failed to archive log %s of task %v: %v", task.LogFilename, task.ID, err)
+				// do not return error here, continue to next task
+				continue
+			}
+			count++
+		}
+		if len(tasks) < deleteLogBatchSize {
+			break
+		}
+	}
+
+	log.Info("Archived %d logs", count)
+	return nil
+}
+
+func archiveTaskLog(ctx context.Context, task *actions_model.ActionTask, compress bool) error {
+	if !task.LogInStorage {
+		remove, err := actions_module.TransferLogs(ctx, task.LogFilename)
+		if err != nil {
+			return err
+		}
+		task.LogInStorage = true
+		if err := actions_model.UpdateTask(ctx, task, "log_in_storage"); err != nil {
+			return err
+		}
+		remove()
+	}
+
+	if !compress || strings.HasSuffix(task.LogFilename, ".zst") {
+		return nil
+	}
+	filename, err := actions_module.CompressLogs(task.LogFilename)
+	if err != nil {
+		return err
+	}
+	// the uncompressed log isn't removed right now, it's removed by removeStaleLogs after the grace period
+	task.StaleLogFilename = task.LogFilename
+	task.LogFilename = filename
+	if err := actions_model.UpdateTask(ctx, task, "log_filename", "stale_log_filename"); err != nil {
+		if err := storage.Actions.Delete(filename); err != nil {
+			log.Warn("Failed to remove compressed log %s: %v", filename, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// removeStaleLogs removes the uncompressed logs which have been replaced by the compressed ones for the grace period
+func removeStaleLogs(ctx context.Context) error {
+	updatedBefore := timeutil.TimeStampNow().AddDuration(-staleLogGracePeriod)
+	count := 0
+	var lastID int64
+	for {
+		tasks, err := actions_model.FindTasksWithStaleLogs(ctx, updatedBefore, lastID, deleteLogBatchSize)
+		if err != nil {
+			return fmt.Errorf("find tasks with stale logs: %w", err)
+		}
+		for _, task := range tasks {
+			lastID = task.ID
+			removeStaleTaskLog(task)
+			if err := actions_model.UpdateTask(ctx, task, "stale_log_filename"); err != nil {
+				log.Error("Failed to update task %v: %v", task.ID, err)
+				// do not return error here, continue to next task
+				continue
+			}
+			count++
+		}
+		if len(tasks) < deleteLogBatchSize {
+			break
+		}
+	}
+
+	log.Info("Removed %d stale logs", count)
 	return nil
 }
 
@@ -211,6 +365,10 @@ func DeleteRun(ctx context.Context, run *actions_model.ActionRun) error {
 		RepoID: repoID,
 		RunID:  run.ID,
 	})
+	recordsToDelete = append(recordsToDelete, &actions_model.ActionRunAttempt{
+		RepoID: repoID,
+		RunID:  run.ID,
+	})
 	for _, tas := range tasks {
 		recordsToDelete = append(recordsToDelete, &actions_model.ActionTask{
 			RepoID: repoID,
@@ -223,6 +381,9 @@ func DeleteRun(ctx context.Context, run *actions_model.ActionRun) error {
 		recordsToDelete = append(recordsToDelete, &actions_model.ActionTaskOutput{
 			TaskID: tas.ID,
 		})
+		recordsToDelete = append(recordsToDelete, &actions_model.ActionTaskSummary{
+			TaskID: tas.ID,
+		})
 	}
 	recordsToDelete = append(recordsToDelete, &actions_model.ActionArtifact{
 		RepoID: repoID,
@@ -46,6 +46,7 @@ func notifyWorkflowJobStatusUpdate(ctx context.Context, jobs []*actions_model.Ac
 			job := jobs[0]
 			notify_service.WorkflowRunStatusUpdate(ctx, job.Run.Repo, job.Run.TriggerUser, job.Run)
 		}
+		EmitJobsOfConcurrencyGroups(ctx, jobs...)
 	}
 }
 
@@ -129,6 +130,7 @@ func CancelAbandonedJobs(ctx context.Context) error {
 		if updated {
 			NotifyWorkflowRunStatusUpdateWithReload(ctx, job)
 			notify_service.WorkflowJobStatusUpdate(ctx, job.Run.Repo, job.Run.TriggerUser, job, nil)
+			EmitJobsOfConcurrencyGroups(ctx, job)
 		}
 	}
 
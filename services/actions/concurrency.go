@@ -0,0 +1,112 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/json"
+
+	"github.com/nektos/act/pkg/jobparser"
+	act_model "github.com/nektos/act/pkg/model"
+	"gopkg.in/yaml.v3"
+)
+
+// EvaluateRunConcurrencyFillModel evaluates the expressions in a workflow-level concurrency,
+// and fills the run's model fields with `concurrency.group` and `concurrency.cancel-in-progress`.
+// Workflow-level concurrency doesn't depend on the job outputs, so it can always be evaluated if there is no syntax error.
+// See https://docs.github.com/en/actions/writing-workflows/workflow-syntax-for-github-actions#concurrency
+func EvaluateRunConcurrencyFillModel(ctx context.Context, run *actions_model.ActionRun, wfRawConcurrency *act_model.RawConcurrency, vars map[string]string) error {
+	if wfRawConcurrency == nil || wfRawConcurrency.Group == "" {
+		return nil
+	}
+
+	if err := run.LoadAttributes(ctx); err != nil {
+		return fmt.Errorf("run LoadAttributes: %w", err)
+	}
+
+	actionsRunCtx := GenerateGiteaContext(run, nil)
+	// the interpreter requires the result of the current job, an empty job id is used for workflow-level expressions
+	jobResults := map[string]*jobparser.JobResult{"": {}}
+
+	var err error
+	run.ConcurrencyGroup, run.ConcurrencyCancel, err = jobparser.EvaluateConcurrency(wfRawConcurrency, "", nil, actionsRunCtx, jobResults, vars, getWorkflowDispatchInputs(run))
+	if err != nil {
+		return fmt.Errorf("evaluate concurrency: %w", err)
+	}
+	return nil
+}
+
+// EvaluateJobConcurrencyFillModel evaluates the expressions in a job-level concurrency,
+// and fills the job's model fields with `concurrency.group` and `concurrency.cancel-in-progress`.
+// Job-level concurrency may depend on other job's outputs (via `needs`): `concurrency.group: my-group-${{ needs.job1.outputs.out1 }}`
+// So this function should only be called when the needs of the job have been resolved.
+func EvaluateJobConcurrencyFillModel(ctx context.Context, run *actions_model.ActionRun, actionRunJob *actions_model.ActionRunJob, vars map[string]string) error {
+	if actionRunJob.RawConcurrency == "" {
+		actionRunJob.IsConcurrencyEvaluated = true
+		return nil
+	}
+
+	if err := run.LoadAttributes(ctx); err != nil {
+		return fmt.Errorf("run LoadAttributes: %w", err)
+	}
+
+	var rawConcurrency act_model.RawConcurrency
+	if err := yaml.Unmarshal([]byte(actionRunJob.RawConcurrency), &rawConcurrency); err != nil {
+		return fmt.Errorf("unmarshal raw concurrency: %w", err)
+	}
+
+	singleWorkflows, err := jobparser.Parse(actionRunJob.WorkflowPayload)
+	if err != nil {
+		return fmt.Errorf("parse single workflow: %w", err)
+	} else if len(singleWorkflows) != 1 {
+		return errors.New("not single workflow")
+	}
+	_, singleWorkflowJob := singleWorkflows[0].Job()
+
+	taskNeeds, err := FindTaskNeeds(ctx, actionRunJob)
+	if err != nil {
+		return fmt.Errorf("find task needs: %w", err)
+	}
+	jobResults := make(map[string]*jobparser.JobResult, len(taskNeeds)+1)
+	for jobID, taskNeed := range taskNeeds {
+		jobResults[jobID] = &jobparser.JobResult{
+			Result:  taskNeed.Result.String(),
+			Outputs: taskNeed.Outputs,
+		}
+	}
+	jobResults[actionRunJob.JobID] = &jobparser.JobResult{
+		Needs: actionRunJob.Needs,
+	}
+
+	inputs, err := getJobInputs(run, actionRunJob)
+	if err != nil {
+		return fmt.Errorf("get inputs: %w", err)
+	}
+
+	actionsJobCtx := GenerateGiteaContext(run, actionRunJob)
+	actionRunJob.ConcurrencyGroup, actionRunJob.ConcurrencyCancel, err = jobparser.EvaluateConcurrency(&rawConcurrency, actionRunJob.JobID, singleWorkflowJob, actionsJobCtx, jobResults, vars, inputs)
+	if err != nil {
+		return fmt.Errorf("evaluate concurrency: %w", err)
+	}
+	actionRunJob.IsConcurrencyEvaluated = true
+	return nil
+}
+
+// getWorkflowDispatchInputs returns the inputs of a run triggered by workflow_dispatch, or nil for other events
+func getWorkflowDispatchInputs(run *actions_model.ActionRun) map[string]any {
+	if run.TriggerEvent != "workflow_dispatch" {
+		return nil
+	}
+	var payload struct {
+		Inputs map[string]any `json:"inputs"`
+	}
+	if err := json.Unmarshal([]byte(run.EventPayload), &payload); err != nil {
+		return nil
+	}
+	return payload.Inputs
+}
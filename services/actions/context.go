@@ -90,7 +90,7 @@ func GenerateGiteaContext(run *actions_model.ActionRun, job *actions_model.Actio
 	if job != nil {
 		gitContext["job"] = job.JobID
 		gitContext["run_id"] = strconv.FormatInt(job.RunID, 10)
-		gitContext["run_attempt"] = strconv.FormatInt(job.Attempt, 10)
+		gitContext["run_attempt"] = strconv.FormatInt(run.Attempt, 10)
 	}
 
 	return gitContext
@@ -114,8 +114,12 @@ func FindTaskNeeds(ctx context.Context, job *actions_model.ActionRunJob) (map[st
 	}
 
 	jobIDJobs := make(map[string][]*actions_model.ActionRunJob)
-	for _, job := range jobs {
-		jobIDJobs[job.JobID] = append(jobIDJobs[job.JobID], job)
+	for _, j := range jobs {
+		if j.ParentJobID != job.ParentJobID {
+			// the needs only refer to the jobs of the same workflow, which may be a reusable workflow
+			continue
+		}
+		jobIDJobs[j.JobID] = append(jobIDJobs[j.JobID], j)
 	}
 
 	ret := make(map[string]*TaskNeed, len(needs))
@@ -125,6 +129,11 @@ func FindTaskNeeds(ctx context.Context, job *actions_model.ActionRunJob) (map[st
 		}
 		var jobOutputs map[string]string
 		for _, job := range jobsWithSameID {
+			if job.IsReusableWorkflow && job.Status.IsDone() {
+				// the outputs of a job calling a reusable workflow are evaluated by the server
+				jobOutputs = mergeTwoOutputs(job.Outputs, jobOutputs)
+				continue
+			}
 			if job.TaskID == 0 || !job.Status.IsDone() {
 				// it shouldn't happen, or the job has been rerun
 				continue
@@ -0,0 +1,367 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	secret_model "code.gitea.io/gitea/models/secret"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"gopkg.in/yaml.v3"
+)
+
+// readRawEnvironments reads the raw `jobs.<job_id>.environment` of the jobs in the workflow content,
+// since they are not kept in the payloads of the jobs parsed by jobparser.
+func readRawEnvironments(content []byte) (map[string]string, error) {
+	var wf struct {
+		Jobs map[string]struct {
+			Environment yaml.Node `yaml:"environment"`
+		} `yaml:"jobs"`
+	}
+	if err := yaml.Unmarshal(content, &wf); err != nil {
+		return nil, fmt.Errorf("read environments: %w", err)
+	}
+	ret := make(map[string]string)
+	for id, job := range wf.Jobs {
+		if job.Environment.Kind == 0 {
+			continue
+		}
+		raw, err := yaml.Marshal(&job.Environment)
+		if err != nil {
+			return nil, fmt.Errorf("marshal environment of job %q: %w", id, err)
+		}
+		ret[id] = string(raw)
+	}
+	return ret, nil
+}
+
+// parseRawEnvironmentName returns the unevaluated name of a raw environment,
+// which is either the name itself or a mapping with the name and the url.
+// See https://docs.github.com/en/actions/writing-workflows/workflow-syntax-for-github-actions#jobsjob_idenvironment
+func parseRawEnvironmentName(rawEnvironment string) (string, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(rawEnvironment), &node); err != nil {
+		return "", err
+	}
+	if node.Kind == yaml.DocumentNode && len(node.Content) == 1 {
+		node = *node.Content[0]
+	}
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Value, nil
+	case yaml.MappingNode:
+		var env struct {
+			Name string `yaml:"name"`
+		}
+		if err := node.Decode(&env); err != nil {
+			return "", err
+		}
+		return env.Name, nil
+	}
+	return "", fmt.Errorf("invalid environment %q", rawEnvironment)
+}
+
+// checkJobEnvironment checks the protection rules of the environment targeted by a job whose needs have been resolved,
+// it returns the status the job should be changed to and whether the environment fields of the job have been changed.
+// The job keeps blocked until it's approved by a required reviewer and the wait timer of the environment expires,
+// and it fails if its ref can't deploy to the environment or it's rejected by a reviewer.
+func checkJobEnvironment(ctx context.Context, run *actions_model.ActionRun, job *actions_model.ActionRunJob, jobs []*actions_model.ActionRunJob, vars map[string]string) (actions_model.Status, bool, error) {
+	var changed bool
+	if !job.IsEnvironmentEvaluated {
+		name, err := parseRawEnvironmentName(job.RawEnvironment)
+		if err != nil {
+			log.Error("Parse environment of job %d: %v", job.ID, err)
+			return actions_model.StatusFailure, true, nil
+		}
+		_, wfJob, err := parseSingleWorkflowJob(job)
+		if err != nil {
+			log.Error("Parse job %d: %v", job.ID, err)
+			return actions_model.StatusFailure, true, nil
+		}
+		results, err := jobResultsOfScope(ctx, job, jobs)
+		if err != nil {
+			return 0, false, err
+		}
+		inputs, err := getJobInputs(run, job)
+		if err != nil {
+			log.Error("Get inputs of job %d: %v", job.ID, err)
+			return actions_model.StatusFailure, true, nil
+		}
+		interpreter := newJobInterpreter(run, job, wfJob, results, vars, inputs)
+		name = strings.TrimSpace(jobparser.NewExpressionEvaluator(interpreter).Interpolate(name))
+		if name != "" {
+			env, err := actions_model.GetOrInsertEnvironment(ctx, job.RepoID, name)
+			if errors.Is(err, util.ErrInvalidArgument) {
+				log.Error("Get environment %q of job %d: %v", name, job.ID, err)
+				return actions_model.StatusFailure, true, nil
+			} else if err != nil {
+				return 0, false, err
+			}
+			job.EnvironmentID = env.ID
+		}
+		job.IsEnvironmentEvaluated = true
+		changed = true
+	}
+	if job.EnvironmentID == 0 {
+		return actions_model.StatusWaiting, changed, nil
+	}
+
+	env, err := actions_model.GetEnvironmentByRepoAndID(ctx, job.RepoID, job.EnvironmentID)
+	if errors.Is(err, util.ErrNotExist) {
+		// the environment has been deleted, there is nothing to protect
+		return actions_model.StatusWaiting, changed, nil
+	} else if err != nil {
+		return 0, false, err
+	}
+
+	if !env.IsBranchAllowed(git.RefName(run.Ref).ShortName()) {
+		log.Info("Job %d can't deploy to environment %q from %s", job.ID, env.Name, run.Ref)
+		return actions_model.StatusFailure, changed, nil
+	}
+
+	if env.HasRequiredReviewers() {
+		review, err := actions_model.GetDeploymentReviewOfJob(ctx, job)
+		if err != nil {
+			return 0, false, err
+		}
+		if review == nil {
+			return actions_model.StatusBlocked, changed, nil
+		}
+		if review.State == actions_model.DeploymentReviewStateRejected {
+			return actions_model.StatusFailure, changed, nil
+		}
+	}
+
+	if env.WaitTimer > 0 {
+		if job.EnvironmentWaitUntil == 0 {
+			job.EnvironmentWaitUntil = timeutil.TimeStampNow().AddDuration(time.Duration(env.WaitTimer) * time.Minute)
+			changed = true
+		}
+		if timeutil.TimeStampNow() < job.EnvironmentWaitUntil {
+			return actions_model.StatusBlocked, changed, nil
+		}
+	}
+	return actions_model.StatusWaiting, changed, nil
+}
+
+// StartJobsWaitingForEnvironments emits the runs which have jobs whose wait timers of environments have expired
+func StartJobsWaitingForEnvironments(ctx context.Context) error {
+	jobs, err := db.Find[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{
+		Statuses:                   []actions_model.Status{actions_model.StatusBlocked},
+		EnvironmentWaitUntilBefore: timeutil.TimeStampNow(),
+	})
+	if err != nil {
+		return fmt.Errorf("find jobs waiting for environments: %w", err)
+	}
+	runIDs := make(container.Set[int64])
+	for _, job := range jobs {
+		runIDs.Add(job.RunID)
+	}
+	for runID := range runIDs {
+		if err := EmitJobsIfReady(runID); err != nil {
+			log.Error("Emit ready jobs of run %d: %v", runID, err)
+		}
+	}
+	return nil
+}
+
+// DeleteEnvironment deletes an environment with its secrets and variables
+func DeleteEnvironment(ctx context.Context, env *actions_model.ActionEnvironment) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.DeleteByBean(ctx, &secret_model.Secret{RepoID: env.RepoID, EnvironmentID: env.ID}); err != nil {
+			return err
+		}
+		if _, err := db.DeleteByBean(ctx, &actions_model.ActionVariable{RepoID: env.RepoID, EnvironmentID: env.ID}); err != nil {
+			return err
+		}
+		return actions_model.DeleteEnvironment(ctx, env)
+	})
+}
+
+// PendingDeployment represents the jobs of a run which target the same environment and are waiting for reviews
+type PendingDeployment struct {
+	Environment *actions_model.ActionEnvironment
+	Jobs        []*actions_model.ActionRunJob
+}
+
+// GetPendingDeployments returns the environments which are waiting for reviews to deploy the jobs of the run
+func GetPendingDeployments(ctx context.Context, run *actions_model.ActionRun) ([]*PendingDeployment, error) {
+	jobs, err := db.Find[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{
+		RunID:    run.ID,
+		Statuses: []actions_model.Status{actions_model.StatusBlocked},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*PendingDeployment
+	envs := make(map[int64]*PendingDeployment)
+	for _, job := range jobs {
+		if job.EnvironmentID == 0 {
+			continue
+		}
+		pending, ok := envs[job.EnvironmentID]
+		if !ok {
+			env, err := actions_model.GetEnvironmentByRepoAndID(ctx, job.RepoID, job.EnvironmentID)
+			if errors.Is(err, util.ErrNotExist) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			pending = &PendingDeployment{Environment: env}
+			envs[env.ID] = pending
+			ret = append(ret, pending)
+		}
+		if !pending.Environment.HasRequiredReviewers() {
+			continue
+		}
+		if review, err := actions_model.GetDeploymentReviewOfJob(ctx, job); err != nil {
+			return nil, err
+		} else if review == nil {
+			pending.Jobs = append(pending.Jobs, job)
+		}
+	}
+	return slices.DeleteFunc(ret, func(p *PendingDeployment) bool { return len(p.Jobs) == 0 }), nil
+}
+
+// CanUserReviewEnvironment returns whether the user is a required reviewer of the environment for the run
+func CanUserReviewEnvironment(ctx context.Context, env *actions_model.ActionEnvironment, run *actions_model.ActionRun, doer *user_model.User) (bool, error) {
+	if doer == nil || env.PreventSelfReview && doer.ID == run.TriggerUserID {
+		return false, nil
+	}
+	if slices.Contains(env.ReviewerUserIDs, doer.ID) {
+		return true, nil
+	}
+	if err := run.LoadRepo(ctx); err != nil {
+		return false, err
+	}
+	for _, teamID := range env.ReviewerTeamIDs {
+		if ok, err := organization.IsTeamMember(ctx, run.Repo.OwnerID, teamID, doer.ID); err != nil {
+			return false, err
+		} else if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ReviewPendingDeployments approves or rejects the jobs of the run waiting for reviews to deploy to the environments,
+// the doer must be a required reviewer of all the environments.
+func ReviewPendingDeployments(ctx context.Context, run *actions_model.ActionRun, doer *user_model.User, environmentIDs []int64, state actions_model.DeploymentReviewState, comment string) error {
+	pendingDeployments, err := GetPendingDeployments(ctx, run)
+	if err != nil {
+		return err
+	}
+
+	var reviews []*actions_model.ActionDeploymentReview
+	for _, envID := range environmentIDs {
+		idx := slices.IndexFunc(pendingDeployments, func(p *PendingDeployment) bool { return p.Environment.ID == envID })
+		if idx < 0 {
+			return util.NewInvalidArgumentErrorf("environment %d is not waiting for review", envID)
+		}
+		pending := pendingDeployments[idx]
+		if ok, err := CanUserReviewEnvironment(ctx, pending.Environment, run, doer); err != nil {
+			return err
+		} else if !ok {
+			return util.NewPermissionDeniedErrorf("user %s can't review environment %q", doer.Name, pending.Environment.Name)
+		}
+		for _, job := range pending.Jobs {
+			reviews = append(reviews, &actions_model.ActionDeploymentReview{
+				RepoID:        run.RepoID,
+				RunID:         run.ID,
+				JobID:         job.ID,
+				JobAttempt:    job.Attempt,
+				EnvironmentID: envID,
+				ReviewerID:    doer.ID,
+				State:         state,
+				Comment:       comment,
+			})
+		}
+	}
+	if len(reviews) == 0 {
+		return util.NewInvalidArgumentErrorf("no environment to review")
+	}
+
+	if err := db.Insert(ctx, reviews); err != nil {
+		return err
+	}
+	return EmitJobsIfReady(run.ID)
+}
+
+// CreateOrUpdateEnvironment creates the environment of the repository if it doesn't exist, otherwise updates its protection rules.
+// The required reviewers must be the users who can read the actions of the repository, or the teams of the organization owning the repository.
+func CreateOrUpdateEnvironment(ctx context.Context, repo *repo_model.Repository, opts *actions_model.ActionEnvironment) (*actions_model.ActionEnvironment, bool, error) {
+	if err := validateEnvironmentReviewers(ctx, repo, opts.ReviewerUserIDs, opts.ReviewerTeamIDs); err != nil {
+		return nil, false, err
+	}
+
+	env, err := actions_model.GetEnvironmentByRepoAndName(ctx, repo.ID, opts.Name)
+	if errors.Is(err, util.ErrNotExist) {
+		opts.RepoID = repo.ID
+		if err := actions_model.InsertEnvironment(ctx, opts); err != nil {
+			return nil, false, err
+		}
+		return opts, true, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	env.ReviewerUserIDs = opts.ReviewerUserIDs
+	env.ReviewerTeamIDs = opts.ReviewerTeamIDs
+	env.PreventSelfReview = opts.PreventSelfReview
+	env.WaitTimer = opts.WaitTimer
+	env.BranchPatterns = opts.BranchPatterns
+	if err := actions_model.UpdateEnvironment(ctx, env, "reviewer_user_ids", "reviewer_team_ids", "prevent_self_review", "wait_timer", "branch_patterns"); err != nil {
+		return nil, false, err
+	}
+	return env, false, nil
+}
+
+func validateEnvironmentReviewers(ctx context.Context, repo *repo_model.Repository, userIDs, teamIDs []int64) error {
+	users, err := user_model.GetUsersByIDs(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+	for _, u := range users {
+		perm, err := access_model.GetUserRepoPermission(ctx, repo, u)
+		if err != nil {
+			return err
+		}
+		if !perm.CanRead(unit.TypeActions) {
+			return util.NewInvalidArgumentErrorf("user %s can't read the actions of the repository", u.Name)
+		}
+	}
+	if len(users) != len(container.SetOf(userIDs...)) {
+		return util.NewInvalidArgumentErrorf("some reviewers don't exist")
+	}
+
+	teams, err := organization.GetTeamsByIDs(ctx, teamIDs)
+	if err != nil {
+		return err
+	}
+	for _, id := range teamIDs {
+		if team, ok := teams[id]; !ok || team.OrgID != repo.OwnerID {
+			return util.NewInvalidArgumentErrorf("team %d doesn't belong to the owner of the repository", id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadRawEnvironments(t *testing.T) {
+	content := []byte(`
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo build
+  staging:
+    runs-on: ubuntu-latest
+    environment: staging
+    steps:
+      - run: echo staging
+  production:
+    runs-on: ubuntu-latest
+    environment:
+      name: ${{ vars.ENV_NAME }}
+      url: https://example.com
+    steps:
+      - run: echo production
+`)
+	rawEnvironments, err := readRawEnvironments(content)
+	require.NoError(t, err)
+	require.Len(t, rawEnvironments, 2)
+
+	name, err := parseRawEnvironmentName(rawEnvironments["staging"])
+	require.NoError(t, err)
+	assert.Equal(t, "staging", name)
+
+	name, err = parseRawEnvironmentName(rawEnvironments["production"])
+	require.NoError(t, err)
+	assert.Equal(t, "${{ vars.ENV_NAME }}", name)
+
+	_, err = parseRawEnvironmentName("- a\n- b\n")
+	assert.Error(t, err)
+}
+
+func TestEnvironmentIsBranchAllowed(t *testing.T) {
+	env := &actions_model.ActionEnvironment{}
+	assert.True(t, env.IsBranchAllowed("any"))
+
+	env.BranchPatterns = []string{"main", "release/*"}
+	assert.True(t, env.IsBranchAllowed("main"))
+	assert.True(t, env.IsBranchAllowed("release/v1"))
+	assert.False(t, env.IsBranchAllowed("release/v1/hotfix"))
+	assert.False(t, env.IsBranchAllowed("feature"))
+}
+
+func TestEnvironmentValidate(t *testing.T) {
+	env := &actions_model.ActionEnvironment{Name: " Production ", ReviewerUserIDs: []int64{3, 1, 3}}
+	require.NoError(t, env.Validate())
+	assert.Equal(t, "Production", env.Name)
+	assert.Equal(t, "production", env.LowerName)
+	assert.Equal(t, []int64{1, 3}, env.ReviewerUserIDs)
+
+	assert.Error(t, (&actions_model.ActionEnvironment{Name: " "}).Validate())
+	assert.Error(t, (&actions_model.ActionEnvironment{Name: "a", WaitTimer: actions_model.EnvironmentMaxWaitTimer + 1}).Validate())
+	assert.Error(t, (&actions_model.ActionEnvironment{Name: "a", BranchPatterns: []string{"[a"}}).Validate())
+	assert.Error(t, (&actions_model.ActionEnvironment{Name: "a", ReviewerUserIDs: []int64{1, 2, 3, 4, 5, 6, 7}}).Validate())
+}
@@ -0,0 +1,221 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/perm"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/oauth2_provider"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/nektos/act/pkg/jobparser"
+)
+
+// IDTokenExpiration is the lifetime of the OIDC ID tokens issued to Actions jobs
+const IDTokenExpiration = 10 * time.Minute
+
+// IDTokenClaims represents the claims of an OIDC ID token issued to an Actions job,
+// they are compatible with GitHub's, see https://docs.github.com/en/actions/security-for-github-actions/security-hardening-your-deployments/about-security-hardening-with-openid-connect#understanding-the-oidc-token
+type IDTokenClaims struct {
+	jwt.RegisteredClaims
+
+	Repository        string `json:"repository"`
+	RepositoryID      string `json:"repository_id"`
+	RepositoryOwner   string `json:"repository_owner"`
+	RepositoryOwnerID string `json:"repository_owner_id"`
+	Ref               string `json:"ref"`
+	RefType           string `json:"ref_type"`
+	Sha               string `json:"sha"`
+	Workflow          string `json:"workflow"`
+	WorkflowRef       string `json:"workflow_ref"` // Gitea doesn't record the directory of the workflow file, so it's "owner/repo/<workflow file>@<ref>"
+	JobWorkflowRef    string `json:"job_workflow_ref"`
+	EventName         string `json:"event_name"`
+	RunID             string `json:"run_id"`
+	RunNumber         string `json:"run_number"`
+	RunAttempt        string `json:"run_attempt"`
+	Job               string `json:"job"`
+	Actor             string `json:"actor"`
+	ActorID           string `json:"actor_id"`
+	Environment       string `json:"environment,omitempty"`
+	HeadRef           string `json:"head_ref,omitempty"`
+	BaseRef           string `json:"base_ref,omitempty"`
+}
+
+// IDTokenIssuer returns the issuer of the OIDC ID tokens issued to Actions jobs,
+// the discovery document is served at "<issuer>/.well-known/openid-configuration".
+func IDTokenIssuer() string {
+	return setting.AppURL + "api/actions"
+}
+
+// IDTokenRequestURL returns the URL used by the jobs to request ID tokens,
+// the clients like `@actions/core` append the "audience" query parameter to it.
+func IDTokenRequestURL() string {
+	return IDTokenIssuer() + "/idtoken?api-version=2.0"
+}
+
+// GetIDTokenSigningKey returns the key to sign the ID tokens, it must be asymmetric so the relying parties can verify the tokens by the JWKS
+func GetIDTokenSigningKey() (oauth2_provider.JWTSigningKey, error) {
+	key := oauth2_provider.DefaultSigningKey
+	if key == nil {
+		return nil, util.NewNotExistErrorf("no signing key for ID tokens")
+	}
+	if key.IsSymmetric() {
+		return nil, util.NewInvalidArgumentErrorf("ID tokens can't be signed by the symmetric algorithm %s", key.SigningMethod().Alg())
+	}
+	return key, nil
+}
+
+// CreateIDToken creates a signed OIDC ID token for the running task.
+// The audience defaults to the URL of the repository owner like GitHub does.
+func CreateIDToken(ctx context.Context, task *actions_model.ActionTask, audience string) (string, error) {
+	if !task.Status.IsRunning() {
+		return "", util.NewPermissionDeniedErrorf("task %d is not running", task.ID)
+	}
+	if err := task.LoadAttributes(ctx); err != nil {
+		return "", err
+	}
+	if task.TokenPermissions[actions_model.TokenScopeIDToken] < perm.AccessModeWrite {
+		return "", util.NewPermissionDeniedErrorf("the job requires the permission id-token: write")
+	}
+
+	signingKey, err := GetIDTokenSigningKey()
+	if err != nil {
+		return "", err
+	}
+
+	claims, err := generateIDTokenClaims(ctx, task, audience)
+	if err != nil {
+		return "", err
+	}
+	jwtToken := jwt.NewWithClaims(signingKey.SigningMethod(), claims)
+	signingKey.PreProcessToken(jwtToken)
+	return jwtToken.SignedString(signingKey.SignKey())
+}
+
+func generateIDTokenClaims(ctx context.Context, task *actions_model.ActionTask, audience string) (*IDTokenClaims, error) {
+	job, run := task.Job, task.Job.Run
+	if err := run.LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+	if err := run.Repo.LoadOwner(ctx); err != nil {
+		return nil, err
+	}
+
+	giteaCtx := GenerateGiteaContext(run, job)
+	ref := util.GetMapValueOrDefault(giteaCtx, "ref", "")
+	repoFullName := run.Repo.FullName()
+	workflowRef := fmt.Sprintf("%s/%s@%s", repoFullName, run.WorkflowID, ref)
+	jobWorkflowRef, err := getJobWorkflowRef(ctx, job, workflowRef, repoFullName, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if audience == "" {
+		audience = run.Repo.Owner.HTMLURL()
+	}
+
+	var environment string
+	if job.EnvironmentID > 0 {
+		env, err := actions_model.GetEnvironmentByRepoAndID(ctx, job.RepoID, job.EnvironmentID)
+		if err != nil && !errors.Is(err, util.ErrNotExist) {
+			return nil, err
+		} else if err == nil {
+			environment = env.Name
+		}
+	}
+
+	subject := "repo:" + repoFullName
+	switch {
+	case environment != "":
+		subject += ":environment:" + environment
+	case run.TriggerEvent == actions_module.GithubEventPullRequest:
+		subject += ":pull_request"
+	case ref != "":
+		subject += ":ref:" + ref
+	}
+
+	now := time.Now()
+	return &IDTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    IDTokenIssuer(),
+			Subject:   subject,
+			Audience:  jwt.ClaimStrings{audience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(IDTokenExpiration)),
+			NotBefore: jwt.NewNumericDate(now),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        fmt.Sprintf("%d-%d", task.ID, now.UnixNano()),
+		},
+		Repository:        repoFullName,
+		RepositoryID:      strconv.FormatInt(run.RepoID, 10),
+		RepositoryOwner:   run.Repo.OwnerName,
+		RepositoryOwnerID: strconv.FormatInt(run.Repo.OwnerID, 10),
+		Ref:               ref,
+		RefType:           string(git.RefName(ref).RefType()),
+		Sha:               util.GetMapValueOrDefault(giteaCtx, "sha", ""),
+		Workflow:          run.WorkflowID,
+		WorkflowRef:       workflowRef,
+		JobWorkflowRef:    jobWorkflowRef,
+		EventName:         run.TriggerEvent,
+		RunID:             strconv.FormatInt(run.ID, 10),
+		RunNumber:         strconv.FormatInt(run.Index, 10),
+		RunAttempt:        strconv.FormatInt(run.Attempt, 10),
+		Job:               job.JobID,
+		Actor:             run.TriggerUser.Name,
+		ActorID:           strconv.FormatInt(run.TriggerUserID, 10),
+		Environment:       environment,
+		HeadRef:           util.GetMapValueOrDefault(giteaCtx, "head_ref", ""),
+		BaseRef:           util.GetMapValueOrDefault(giteaCtx, "base_ref", ""),
+	}, nil
+}
+
+// getJobWorkflowRef returns the ref of the workflow which the job belongs to, it differs from the workflow ref for the jobs of reusable workflows
+func getJobWorkflowRef(ctx context.Context, job *actions_model.ActionRunJob, workflowRef, repoFullName, ref string) (string, error) {
+	if job.ParentJobID == 0 {
+		return workflowRef, nil
+	}
+	caller, err := actions_model.GetRunJobByID(ctx, job.ParentJobID)
+	if err != nil {
+		return "", err
+	}
+	_, wfJob, err := parseSingleWorkflowJob(caller)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(wfJob.Uses, "./") {
+		return fmt.Sprintf("%s/%s@%s", repoFullName, strings.TrimPrefix(wfJob.Uses, "./"), ref), nil
+	}
+	return wfJob.Uses, nil
+}
+
+// injectIDTokenRequestEnv adds the environment variables to request ID tokens to the workflow payload sent to the runner
+func injectIDTokenRequestEnv(workflowPayload []byte, requestToken string) ([]byte, error) {
+	singleWorkflows, err := jobparser.Parse(workflowPayload)
+	if err != nil {
+		return nil, fmt.Errorf("parse single workflow: %w", err)
+	} else if len(singleWorkflows) != 1 {
+		return nil, errors.New("not single workflow")
+	}
+	id, job := singleWorkflows[0].Job()
+	if err := addJobEnv(job, map[string]string{
+		"ACTIONS_ID_TOKEN_REQUEST_URL":   IDTokenRequestURL(),
+		"ACTIONS_ID_TOKEN_REQUEST_TOKEN": requestToken,
+	}); err != nil {
+		return nil, err
+	}
+	if err := singleWorkflows[0].SetJob(id, job); err != nil {
+		return nil, err
+	}
+	return singleWorkflows[0].Marshal()
+}
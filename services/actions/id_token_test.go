@@ -0,0 +1,38 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInjectIDTokenRequestEnv(t *testing.T) {
+	payload := []byte(`
+name: test
+on: push
+jobs:
+  job1:
+    runs-on: ubuntu-latest
+    env:
+      FOO: bar
+    steps:
+      - run: echo $FOO
+`)
+	injected, err := injectIDTokenRequestEnv(payload, "request-token")
+	require.NoError(t, err)
+
+	singleWorkflows, err := jobparser.Parse(injected)
+	require.NoError(t, err)
+	require.Len(t, singleWorkflows, 1)
+	_, job := singleWorkflows[0].Job()
+	var env map[string]string
+	require.NoError(t, job.Env.Decode(&env))
+	assert.Equal(t, "bar", env["FOO"])
+	assert.Equal(t, "request-token", env["ACTIONS_ID_TOKEN_REQUEST_TOKEN"])
+	assert.Equal(t, IDTokenRequestURL(), env["ACTIONS_ID_TOKEN_REQUEST_URL"])
+}
@@ -37,4 +37,18 @@ type API interface {
 	ListWorkflowJobs(*context.APIContext)
 	// ListWorkflowRuns list runs
 	ListWorkflowRuns(*context.APIContext)
+	// GetWorkflowPermissions get the default permissions of the tokens
+	GetWorkflowPermissions(*context.APIContext)
+	// UpdateWorkflowPermissions update the default permissions of the tokens
+	UpdateWorkflowPermissions(*context.APIContext)
+	// GetForkPRContributorApproval get the approval policy of the runs from forks
+	GetForkPRContributorApproval(*context.APIContext)
+	// UpdateForkPRContributorApproval update the approval policy of the runs from forks
+	UpdateForkPRContributorApproval(*context.APIContext)
+	// GetLogRetention get the retention period of the job logs
+	GetLogRetention(*context.APIContext)
+	// UpdateLogRetention update the retention period of the job logs
+	UpdateLogRetention(*context.APIContext)
+	// GetUsage get the monthly usage report of the jobs
+	GetUsage(*context.APIContext)
 }
@@ -7,12 +7,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 
 	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/graceful"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/timeutil"
 	notify_service "code.gitea.io/gitea/services/notify"
 
 	"github.com/nektos/act/pkg/jobparser"
@@ -51,49 +55,310 @@ func checkJobsOfRun(ctx context.Context, runID int64) error {
 	if err != nil {
 		return err
 	}
-	var updatedjobs []*actions_model.ActionRunJob
+	if len(jobs) == 0 {
+		return nil
+	}
+	run, err := actions_model.GetRunByRepoAndID(ctx, jobs[0].RepoID, runID)
+	if err != nil {
+		return err
+	}
+	if run.NeedApproval {
+		// the jobs can't be emitted until the run is approved
+		return nil
+	}
+	for _, job := range jobs {
+		job.Run = run
+	}
+
+	var updatedJobs, cancelledJobs []*actions_model.ActionRunJob
+	var shouldReEmit bool
 	if err := db.WithTx(ctx, func(ctx context.Context) error {
-		idToJobs := make(map[string][]*actions_model.ActionRunJob, len(jobs))
-		for _, job := range jobs {
-			idToJobs[job.JobID] = append(idToJobs[job.JobID], job)
+		// the unfinished jobs of the matrices with failed jobs are cancelled before resolving the other jobs
+		cancelled, err := cancelFailFastMatrixJobs(ctx, jobs)
+		cancelledJobs = append(cancelledJobs, cancelled...)
+		if err != nil {
+			return fmt.Errorf("cancelFailFastMatrixJobs: %w", err)
 		}
 
+		var vars map[string]string
 		updates := newJobStatusResolver(jobs).Resolve()
 		for _, job := range jobs {
-			if status, ok := updates[job.ID]; ok {
-				job.Status = status
-				if n, err := actions_model.UpdateRunJob(ctx, job, builder.Eq{"status": actions_model.StatusBlocked}, "status"); err != nil {
+			status, ok := updates[job.ID]
+			if !ok {
+				continue
+			}
+			cols := []string{"status"}
+			if status.IsWaiting() && job.RawMatrix != "" {
+				if vars == nil {
+					if vars, err = actions_model.GetVariablesOfRun(ctx, run); err != nil {
+						return fmt.Errorf("GetVariablesOfRun: %w", err)
+					}
+				}
+				// a job with a dynamic matrix is expanded into the jobs of the matrix combinations, which will be started by the next emitting
+				if status, err = expandDynamicMatrix(ctx, run, job, jobs, vars); err != nil {
 					return err
-				} else if n != 1 {
-					return fmt.Errorf("no affected for updating blocked job %v", job.ID)
 				}
-				updatedjobs = append(updatedjobs, job)
+				if status.IsDone() {
+					job.Stopped = timeutil.TimeStampNow()
+				}
+				cols = append(cols, "name", "runs_on", "workflow_payload", "raw_matrix", "stopped")
+				shouldReEmit = true
+			} else if status.IsWaiting() {
+				if vars == nil {
+					if vars, err = actions_model.GetVariablesOfRun(ctx, run); err != nil {
+						return fmt.Errorf("GetVariablesOfRun: %w", err)
+					}
+				}
+				wasEvaluated := job.IsConcurrencyEvaluated
+				shouldBlock, cancelled, err := checkJobConcurrency(ctx, run, job, vars)
+				cancelledJobs = append(cancelledJobs, cancelled...)
+				if err != nil {
+					return err
+				}
+				changed := wasEvaluated != job.IsConcurrencyEvaluated
+				cols = append(cols, "is_concurrency_evaluated", "concurrency_group", "concurrency_cancel")
+				if shouldBlock {
+					status = actions_model.StatusBlocked
+				} else if job.RawEnvironment != "" && !job.IsReusableWorkflow {
+					var envChanged bool
+					if status, envChanged, err = checkJobEnvironment(ctx, run, job, jobs, vars); err != nil {
+						return err
+					}
+					changed = changed || envChanged
+					cols = append(cols, "is_environment_evaluated", "environment_id", "environment_wait_until")
+				}
+				if status.IsBlocked() && !changed {
+					// nothing changed, keep waiting for the concurrency group or the protection rules of the environment,
+					// otherwise the job is saved even if it keeps blocked, so the evaluated concurrency and environment are kept
+					continue
+				}
+				if job.IsReusableWorkflow && status.IsWaiting() {
+					// a job calling a reusable workflow is never picked by runners, it keeps running until the called jobs are done
+					var children []*actions_model.ActionRunJob
+					if status, children, err = startReusableWorkflowCaller(ctx, run, job, jobs, vars); err != nil {
+						return err
+					}
+					if status.IsRunning() {
+						job.Started = timeutil.TimeStampNow()
+					} else {
+						job.Stopped = timeutil.TimeStampNow()
+					}
+					cols = append(cols, "outputs", "started", "stopped")
+					shouldReEmit = shouldReEmit || len(children) > 0 || status.IsDone()
+				}
+			}
+			job.Status = status
+			if n, err := actions_model.UpdateRunJob(ctx, job, builder.Eq{"status": actions_model.StatusBlocked}, cols...); err != nil {
+				return err
+			} else if n != 1 {
+				return fmt.Errorf("no affected for updating blocked job %v", job.ID)
+			}
+			if !status.IsBlocked() {
+				updatedJobs = append(updatedJobs, job)
+			}
+		}
+
+		for _, job := range jobs {
+			if !job.IsReusableWorkflow || !job.Status.IsRunning() {
+				continue
+			}
+			if vars == nil {
+				if vars, err = actions_model.GetVariablesOfRun(ctx, run); err != nil {
+					return fmt.Errorf("GetVariablesOfRun: %w", err)
+				}
+			}
+			if done, err := finishReusableWorkflowCaller(ctx, run, job, jobs, vars); err != nil {
+				return err
+			} else if done {
+				updatedJobs = append(updatedJobs, job)
+				shouldReEmit = true
 			}
 		}
 		return nil
 	}); err != nil {
 		return err
 	}
+	notifyWorkflowJobStatusUpdate(ctx, cancelledJobs)
 	CreateCommitStatus(ctx, jobs...)
-	for _, job := range updatedjobs {
+	for _, job := range updatedJobs {
 		_ = job.LoadAttributes(ctx)
 		notify_service.WorkflowJobStatusUpdate(ctx, job.Run.Repo, job.Run.TriggerUser, job, nil)
 	}
-	if len(jobs) > 0 {
-		runUpdated := true
-		for _, job := range jobs {
-			if !job.Status.IsDone() {
-				runUpdated = false
-				break
-			}
+	runUpdated := true
+	for _, job := range jobs {
+		if !job.Status.IsDone() {
+			runUpdated = false
+			break
 		}
-		if runUpdated {
-			NotifyWorkflowRunStatusUpdateWithReload(ctx, jobs[0])
+	}
+	if runUpdated {
+		NotifyWorkflowRunStatusUpdateWithReload(ctx, jobs[0])
+	}
+	// the jobs skipped above may release their concurrency groups
+	EmitJobsOfConcurrencyGroups(ctx, updatedJobs...)
+	if shouldReEmit {
+		// the jobs of reusable workflows or matrices have been inserted or finished, the other jobs may be ready now
+		if err := EmitJobsIfReady(runID); err != nil {
+			log.Error("Emit ready jobs of run %d: %v", runID, err)
 		}
 	}
 	return nil
 }
 
+// startReusableWorkflowCaller starts a job calling a reusable workflow when its needs have been resolved.
+// It evaluates the `if` condition of the job and inserts the called jobs if they haven't been inserted,
+// then returns the new status of the job and the inserted jobs.
+// A caller which fails to call the reusable workflow is marked as failed instead of returning an error,
+// otherwise the whole run will be stuck.
+func startReusableWorkflowCaller(ctx context.Context, run *actions_model.ActionRun, job *actions_model.ActionRunJob, jobs []*actions_model.ActionRunJob, vars map[string]string) (actions_model.Status, []*actions_model.ActionRunJob, error) {
+	_, wfJob, err := parseSingleWorkflowJob(job)
+	if err != nil {
+		log.Error("Parse job %d: %v", job.ID, err)
+		return actions_model.StatusFailure, nil, nil
+	}
+	results, err := jobResultsOfScope(ctx, job, jobs)
+	if err != nil {
+		return 0, nil, err
+	}
+	inputs, err := getJobInputs(run, job)
+	if err != nil {
+		log.Error("Get inputs of job %d: %v", job.ID, err)
+		return actions_model.StatusFailure, nil, nil
+	}
+	interpreter := newJobInterpreter(run, job, wfJob, results, vars, inputs)
+
+	if ok, err := evaluateReusableWorkflowCallerIf(interpreter, wfJob); err != nil {
+		log.Error("Evaluate if condition of job %d: %v", job.ID, err)
+		return actions_model.StatusFailure, nil, nil
+	} else if !ok {
+		return actions_model.StatusSkipped, nil, nil
+	}
+
+	for _, j := range jobs {
+		if j.ParentJobID == job.ID {
+			// the called jobs have been inserted and are being rerun, the outputs have to be reset to the raw expressions
+			_, callConfig, err := loadReusableWorkflow(ctx, run, wfJob)
+			if err != nil {
+				log.Error("Load reusable workflow of job %d: %v", job.ID, err)
+				return actions_model.StatusFailure, nil, nil
+			}
+			setRawReusableWorkflowOutputs(job, callConfig)
+			return actions_model.StatusRunning, nil, nil
+		}
+	}
+
+	children, err := expandReusableWorkflow(ctx, run, job, wfJob, interpreter, jobs, vars)
+	if err != nil {
+		log.Error("Call reusable workflow of job %d: %v", job.ID, err)
+		return actions_model.StatusFailure, nil, nil
+	}
+	return actions_model.StatusRunning, children, nil
+}
+
+// finishReusableWorkflowCaller finishes a running job calling a reusable workflow if all the called jobs are done,
+// the status of the job is aggregated from the called jobs, and the outputs of the reusable workflow are evaluated.
+func finishReusableWorkflowCaller(ctx context.Context, run *actions_model.ActionRun, job *actions_model.ActionRunJob, jobs []*actions_model.ActionRunJob, vars map[string]string) (bool, error) {
+	children := make([]*actions_model.ActionRunJob, 0, len(jobs))
+	for _, j := range jobs {
+		if j.ParentJobID != job.ID {
+			continue
+		}
+		if !j.Status.IsDone() {
+			return false, nil
+		}
+		children = append(children, j)
+	}
+	if len(children) == 0 {
+		return false, nil
+	}
+
+	outputs, err := evaluateReusableWorkflowOutputs(ctx, run, job, children, vars)
+	if err != nil {
+		return false, err
+	}
+	job.Outputs = outputs
+	job.Status = actions_model.AggregateJobStatus(children)
+	job.Stopped = timeutil.TimeStampNow()
+	if n, err := actions_model.UpdateRunJob(ctx, job, builder.Eq{"status": actions_model.StatusRunning}, "status", "outputs", "stopped"); err != nil {
+		return false, err
+	} else if n != 1 {
+		return false, fmt.Errorf("no affected for updating running job %v", job.ID)
+	}
+	return true, nil
+}
+
+// checkJobConcurrency checks whether a job whose needs have been resolved should still be blocked by the concurrency of its run or itself.
+// The job-level concurrency will be evaluated if it hasn't been, and the jobs of the same concurrency group will be cancelled if needed.
+func checkJobConcurrency(ctx context.Context, run *actions_model.ActionRun, job *actions_model.ActionRunJob, vars map[string]string) (bool, []*actions_model.ActionRunJob, error) {
+	if shouldBlock, err := actions_model.ShouldBlockRunByConcurrency(ctx, run); err != nil {
+		return false, nil, fmt.Errorf("ShouldBlockRunByConcurrency: %w", err)
+	} else if shouldBlock {
+		return true, nil, nil
+	}
+
+	var cancelledJobs []*actions_model.ActionRunJob
+	if !job.IsConcurrencyEvaluated {
+		if err := EvaluateJobConcurrencyFillModel(ctx, run, job, vars); err != nil {
+			return false, nil, fmt.Errorf("EvaluateJobConcurrencyFillModel: %w", err)
+		}
+		var err error
+		if cancelledJobs, err = actions_model.CancelPreviousJobsByJobConcurrency(ctx, job); err != nil {
+			return false, cancelledJobs, fmt.Errorf("CancelPreviousJobsByJobConcurrency: %w", err)
+		}
+	}
+
+	shouldBlock, err := actions_model.ShouldBlockJobByConcurrency(ctx, job)
+	if err != nil {
+		return false, cancelledJobs, fmt.Errorf("ShouldBlockJobByConcurrency: %w", err)
+	}
+	return shouldBlock, cancelledJobs, nil
+}
+
+// EmitJobsOfConcurrencyGroups emits the runs which have jobs blocked by the concurrency groups of the given jobs or their runs.
+// It should be called after the jobs are done, so the blocked jobs of the same concurrency groups may be able to run.
+func EmitJobsOfConcurrencyGroups(ctx context.Context, jobs ...*actions_model.ActionRunJob) {
+	type repoGroup struct {
+		RepoID int64
+		Group  string
+	}
+	groups := make(container.Set[repoGroup])
+	for _, job := range jobs {
+		if !job.Status.IsDone() {
+			continue
+		}
+		if job.ConcurrencyGroup != "" {
+			groups.Add(repoGroup{job.RepoID, job.ConcurrencyGroup})
+		}
+		if err := job.LoadRun(ctx); err != nil {
+			log.Error("LoadRun: %v", err)
+			continue
+		}
+		if job.Run.ConcurrencyGroup != "" {
+			groups.Add(repoGroup{job.RepoID, job.Run.ConcurrencyGroup})
+		}
+	}
+
+	runIDs := make(container.Set[int64])
+	for group := range groups {
+		runs, blockedJobs, err := actions_model.GetConcurrentRunsAndJobs(ctx, group.RepoID, group.Group, []actions_model.Status{actions_model.StatusBlocked})
+		if err != nil {
+			log.Error("GetConcurrentRunsAndJobs: %v", err)
+			continue
+		}
+		for _, run := range runs {
+			runIDs.Add(run.ID)
+		}
+		for _, job := range blockedJobs {
+			runIDs.Add(job.RunID)
+		}
+	}
+	for runID := range runIDs {
+		if err := EmitJobsIfReady(runID); err != nil {
+			log.Error("Emit ready jobs of run %d: %v", runID, err)
+		}
+	}
+}
+
 func NotifyWorkflowRunStatusUpdateWithReload(ctx context.Context, job *actions_model.ActionRunJob) {
 	job.Run = nil
 	if err := job.LoadAttributes(ctx); err != nil {
@@ -110,10 +375,16 @@ type jobStatusResolver struct {
 }
 
 func newJobStatusResolver(jobs actions_model.ActionJobList) *jobStatusResolver {
-	idToJobs := make(map[string][]*actions_model.ActionRunJob, len(jobs))
+	// the needs of a job refer to the jobs of the same workflow, which may be a reusable workflow
+	type scopedJobID struct {
+		ParentJobID int64
+		JobID       string
+	}
+	idToJobs := make(map[scopedJobID][]*actions_model.ActionRunJob, len(jobs))
 	jobMap := make(map[int64]*actions_model.ActionRunJob)
 	for _, job := range jobs {
-		idToJobs[job.JobID] = append(idToJobs[job.JobID], job)
+		id := scopedJobID{job.ParentJobID, job.JobID}
+		idToJobs[id] = append(idToJobs[id], job)
 		jobMap[job.ID] = job
 	}
 
@@ -122,7 +393,7 @@ func newJobStatusResolver(jobs actions_model.ActionJobList) *jobStatusResolver {
 	for _, job := range jobs {
 		statuses[job.ID] = job.Status
 		for _, need := range job.Needs {
-			for _, v := range idToJobs[need] {
+			for _, v := range idToJobs[scopedJobID{job.ParentJobID, need}] {
 				needs[job.ID] = append(needs[job.ID], v.ID)
 			}
 		}
@@ -151,10 +422,16 @@ func (r *jobStatusResolver) Resolve() map[int64]actions_model.Status {
 
 func (r *jobStatusResolver) resolve() map[int64]actions_model.Status {
 	ret := map[int64]actions_model.Status{}
-	for id, status := range r.statuses {
+	// resolve the jobs in order, so the jobs of a matrix with max-parallel limit start in order
+	for _, id := range slices.Sorted(maps.Keys(r.statuses)) {
+		status := r.statuses[id]
 		if status != actions_model.StatusBlocked {
 			continue
 		}
+		if parentID := r.jobMap[id].ParentJobID; parentID > 0 && r.statuses[parentID] != actions_model.StatusRunning {
+			// the jobs of a reusable workflow can't run until the caller job is running
+			continue
+		}
 		allDone, allSucceed := true, true
 		for _, need := range r.needs[id] {
 			needStatus := r.statuses[need]
@@ -166,6 +443,10 @@ func (r *jobStatusResolver) resolve() map[int64]actions_model.Status {
 			}
 		}
 		if allDone {
+			if r.reachMaxParallel(id, ret) {
+				// the job keeps blocked until one of the running jobs of its matrix is done
+				continue
+			}
 			if allSucceed {
 				ret[id] = actions_model.StatusWaiting
 			} else {
@@ -189,3 +470,26 @@ func (r *jobStatusResolver) resolve() map[int64]actions_model.Status {
 	}
 	return ret
 }
+
+// reachMaxParallel returns whether the matrix of the job has reached its max-parallel limit,
+// the jobs which are going to be waiting in the same round are counted too.
+func (r *jobStatusResolver) reachMaxParallel(id int64, updated map[int64]actions_model.Status) bool {
+	job := r.jobMap[id]
+	if job.MaxParallel <= 0 {
+		return false
+	}
+	running := 0
+	for otherID, other := range r.jobMap {
+		if otherID == id || other.ParentJobID != job.ParentJobID || other.JobID != job.JobID {
+			continue
+		}
+		status, ok := updated[otherID]
+		if !ok {
+			status = r.statuses[otherID]
+		}
+		if status.In(actions_model.StatusWaiting, actions_model.StatusRunning) {
+			running++
+		}
+	}
+	return running >= job.MaxParallel
+}
@@ -126,6 +126,51 @@ jobs:
 			},
 			want: map[int64]actions_model.Status{2: actions_model.StatusSkipped},
 		},
+		{
+			name: "jobs of reusable workflow wait for the caller",
+			jobs: actions_model.ActionJobList{
+				{ID: 1, JobID: "caller", Status: actions_model.StatusBlocked, Needs: []string{}, IsReusableWorkflow: true},
+				{ID: 2, JobID: "build", Status: actions_model.StatusBlocked, Needs: []string{}, ParentJobID: 1},
+				{ID: 3, JobID: "build", Status: actions_model.StatusSuccess, Needs: []string{}},
+			},
+			want: map[int64]actions_model.Status{1: actions_model.StatusWaiting},
+		},
+		{
+			name: "needs of reusable workflow jobs refer to the same workflow",
+			jobs: actions_model.ActionJobList{
+				{ID: 1, JobID: "build", Status: actions_model.StatusSuccess, Needs: []string{}},
+				{ID: 2, JobID: "caller", Status: actions_model.StatusRunning, Needs: []string{}, IsReusableWorkflow: true},
+				{ID: 3, JobID: "build", Status: actions_model.StatusRunning, Needs: []string{}, ParentJobID: 2},
+				{ID: 4, JobID: "test", Status: actions_model.StatusBlocked, Needs: []string{"build"}, ParentJobID: 2},
+				{ID: 5, JobID: "lint", Status: actions_model.StatusBlocked, Needs: []string{}, ParentJobID: 2},
+			},
+			want: map[int64]actions_model.Status{5: actions_model.StatusWaiting},
+		},
+		{
+			name: "jobs of matrix wait for max-parallel",
+			jobs: actions_model.ActionJobList{
+				{ID: 1, JobID: "setup", Status: actions_model.StatusSuccess, Needs: []string{}},
+				{ID: 2, JobID: "build", Status: actions_model.StatusBlocked, Needs: []string{"setup"}, MaxParallel: 2},
+				{ID: 3, JobID: "build", Status: actions_model.StatusBlocked, Needs: []string{"setup"}, MaxParallel: 2},
+				{ID: 4, JobID: "build", Status: actions_model.StatusBlocked, Needs: []string{"setup"}, MaxParallel: 2},
+				{ID: 5, JobID: "test", Status: actions_model.StatusBlocked, Needs: []string{}, MaxParallel: 1},
+			},
+			want: map[int64]actions_model.Status{
+				2: actions_model.StatusWaiting,
+				3: actions_model.StatusWaiting,
+				5: actions_model.StatusWaiting,
+			},
+		},
+		{
+			name: "job of matrix starts after another one is done",
+			jobs: actions_model.ActionJobList{
+				{ID: 1, JobID: "build", Status: actions_model.StatusSuccess, Needs: []string{}, MaxParallel: 2},
+				{ID: 2, JobID: "build", Status: actions_model.StatusRunning, Needs: []string{}, MaxParallel: 2},
+				{ID: 3, JobID: "build", Status: actions_model.StatusBlocked, Needs: []string{}, MaxParallel: 2},
+				{ID: 4, JobID: "build", Status: actions_model.StatusBlocked, Needs: []string{}, MaxParallel: 2},
+			},
+			want: map[int64]actions_model.Status{3: actions_model.StatusWaiting},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,69 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"strconv"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// GetOwnerLogRetentionDays returns the retention period in days of the job logs in the repositories owned by the user or the organization,
+// it follows the instance if the owner doesn't set it. It's never longer than the retention period of the instance.
+func GetOwnerLogRetentionDays(ctx context.Context, ownerID int64) (int64, error) {
+	value, err := user_model.GetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsLogRetentionDays)
+	if err != nil {
+		return 0, err
+	}
+	if days, err := strconv.ParseInt(value, 10, 64); err == nil && days > 0 {
+		return min(days, setting.Actions.LogRetentionDays), nil
+	}
+	return setting.Actions.LogRetentionDays, nil
+}
+
+// SetOwnerLogRetentionDays sets the retention period in days of the job logs in the repositories owned by the user or the organization,
+// 0 means following the instance.
+func SetOwnerLogRetentionDays(ctx context.Context, ownerID, days int64) error {
+	if days < 0 || days > setting.Actions.LogRetentionDays {
+		return util.NewInvalidArgumentErrorf("the log retention days must be between 1 and %d", setting.Actions.LogRetentionDays)
+	}
+	if days == 0 {
+		return user_model.DeleteUserSetting(ctx, ownerID, user_model.SettingsKeyActionsLogRetentionDays)
+	}
+	return user_model.SetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsLogRetentionDays, strconv.FormatInt(days, 10))
+}
+
+// GetRepoLogRetentionDays returns the retention period in days of the job logs in the repository,
+// it follows the owner of the repository if the repository doesn't set it. It's never longer than the retention period of the instance.
+func GetRepoLogRetentionDays(ctx context.Context, repo *repo_model.Repository) (int64, error) {
+	cfgUnit, err := repo.GetUnit(ctx, unit.TypeActions)
+	if err != nil && !repo_model.IsErrUnitTypeNotExist(err) {
+		return 0, err
+	}
+	if cfgUnit != nil {
+		if days := cfgUnit.ActionsConfig().LogRetentionDays; days > 0 {
+			return min(days, setting.Actions.LogRetentionDays), nil
+		}
+	}
+	return GetOwnerLogRetentionDays(ctx, repo.OwnerID)
+}
+
+// SetRepoLogRetentionDays sets the retention period in days of the job logs in the repository,
+// 0 means following the owner of the repository.
+func SetRepoLogRetentionDays(ctx context.Context, repo *repo_model.Repository, days int64) error {
+	if days < 0 || days > setting.Actions.LogRetentionDays {
+		return util.NewInvalidArgumentErrorf("the log retention days must be between 1 and %d", setting.Actions.LogRetentionDays)
+	}
+	cfgUnit, err := repo.GetUnit(ctx, unit.TypeActions)
+	if err != nil {
+		return err
+	}
+	cfgUnit.ActionsConfig().LogRetentionDays = days
+	return repo_model.UpdateRepoUnit(ctx, cfgUnit)
+}
@@ -0,0 +1,53 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogRetentionDays(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	defer test.MockVariableValue(&setting.Actions.LogRetentionDays, 90)()
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	days, err := GetRepoLogRetentionDays(t.Context(), repo)
+	require.NoError(t, err)
+	assert.EqualValues(t, 90, days)
+
+	assert.ErrorIs(t, SetOwnerLogRetentionDays(t.Context(), repo.OwnerID, 91), util.ErrInvalidArgument)
+	assert.ErrorIs(t, SetRepoLogRetentionDays(t.Context(), repo, -1), util.ErrInvalidArgument)
+
+	require.NoError(t, SetOwnerLogRetentionDays(t.Context(), repo.OwnerID, 30))
+	days, err = GetRepoLogRetentionDays(t.Context(), repo)
+	require.NoError(t, err)
+	assert.EqualValues(t, 30, days)
+
+	require.NoError(t, SetRepoLogRetentionDays(t.Context(), repo, 60))
+	repo = unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	days, err = GetRepoLogRetentionDays(t.Context(), repo)
+	require.NoError(t, err)
+	assert.EqualValues(t, 60, days)
+
+	// the retention periods are never longer than the instance's
+	setting.Actions.LogRetentionDays = 45
+	days, err = GetRepoLogRetentionDays(t.Context(), repo)
+	require.NoError(t, err)
+	assert.EqualValues(t, 45, days)
+
+	require.NoError(t, SetOwnerLogRetentionDays(t.Context(), repo.OwnerID, 0))
+	value, err := user_model.GetUserSetting(t.Context(), repo.OwnerID, user_model.SettingsKeyActionsLogRetentionDays)
+	require.NoError(t, err)
+	assert.Empty(t, value)
+}
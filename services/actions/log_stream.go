@@ -0,0 +1,161 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/eventsource"
+	api "code.gitea.io/gitea/modules/structs"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+)
+
+const (
+	// taskLogStreamSyncInterval is how often the log streams check the stored log lines and the state of the task,
+	// the published chunks may be dropped for the slow streams or be published by another instance
+	taskLogStreamSyncInterval = 5 * time.Second
+	// taskLogStreamGracePeriod is how long the log streams of a done task wait for the last lines which haven't been uploaded
+	taskLogStreamGracePeriod = 10 * time.Second
+)
+
+func taskLogTopic(taskID int64) string {
+	return fmt.Sprintf("actions-task-logs-%d", taskID)
+}
+
+func toLogChunk(offset int64, rows []*runnerv1.LogRow) *api.ActionWorkflowJobLogChunk {
+	chunk := &api.ActionWorkflowJobLogChunk{
+		Offset: offset,
+		Lines:  make([]*api.ActionWorkflowJobLogLine, 0, len(rows)),
+	}
+	for i, row := range rows {
+		chunk.Lines = append(chunk.Lines, &api.ActionWorkflowJobLogLine{
+			Index:   offset + int64(i),
+			Time:    row.Time.AsTime(),
+			Message: row.Content,
+		})
+	}
+	return chunk
+}
+
+// PublishTaskLogs publishes the log lines uploaded by the runner to the log streams of the task,
+// offset is the index of the first line in the log of the task
+func PublishTaskLogs(taskID, offset int64, rows []*runnerv1.LogRow) {
+	if len(rows) == 0 {
+		return
+	}
+	eventsource.GetManager().SendTopicMessage(taskLogTopic(taskID), &eventsource.Event{
+		Name: "logs",
+		Data: toLogChunk(offset, rows),
+		ID:   strconv.FormatInt(offset+int64(len(rows)), 10),
+	})
+}
+
+// PublishTaskLogsCompleted notifies the log streams of the task that all lines have been uploaded
+func PublishTaskLogsCompleted(taskID int64) {
+	eventsource.GetManager().SendTopicMessage(taskLogTopic(taskID), &eventsource.Event{Name: "completed"})
+}
+
+// StreamTaskLogs sends the log lines of the task from the offset to the callback until all lines are sent
+// after the task is done, or the context is done.
+// The published chunks are sent as soon as they arrive, the missing lines are read from the stored log,
+// so a stream can be resumed from any offset. An empty chunk is sent periodically if there are no new lines.
+func StreamTaskLogs(ctx context.Context, taskID, offset int64, send func(*api.ActionWorkflowJobLogChunk) error) error {
+	topic := taskLogTopic(taskID)
+	messageChan := eventsource.GetManager().RegisterTopic(topic)
+	defer func() {
+		eventsource.GetManager().UnregisterTopic(topic, messageChan)
+		// the channel is closed after unregistering, drain it in case a message was sent meanwhile
+		for range messageChan {
+		}
+	}()
+
+	offset = max(offset, 0)
+	// sync sends the stored lines which haven't been sent, and returns whether the stream is finished
+	sync := func() (bool, error) {
+		task, err := actions_model.GetTaskByID(ctx, taskID)
+		if err != nil {
+			return false, err
+		}
+		if task.LogExpired {
+			return false, actions_model.ErrTaskLogExpired
+		}
+		if offset < task.LogLength && offset < int64(len(task.LogIndexes)) {
+			rows, err := actions.ReadLogs(ctx, task.LogInStorage, task.LogFilename, task.LogIndexes[offset], task.LogLength-offset)
+			if err != nil {
+				return false, fmt.Errorf("ReadLogs: %w", err)
+			}
+			if len(rows) > 0 {
+				if err := send(toLogChunk(offset, rows)); err != nil {
+					return false, err
+				}
+				offset += int64(len(rows))
+			}
+		}
+		if offset < task.LogLength {
+			return false, nil
+		}
+		// the runners upload all lines before the log is moved to the storage,
+		// but the tasks which are stopped by the server may never get the last lines
+		return task.LogInStorage ||
+			task.Status.IsDone() && time.Since(task.Stopped.AsTime()) > taskLogStreamGracePeriod, nil
+	}
+
+	if finished, err := sync(); err != nil || finished {
+		return err
+	}
+
+	ticker := time.NewTicker(taskLogStreamSyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			sent := offset
+			if finished, err := sync(); err != nil || finished {
+				return err
+			}
+			if sent == offset {
+				// keep the connection alive
+				if err := send(&api.ActionWorkflowJobLogChunk{Offset: offset}); err != nil {
+					return err
+				}
+			}
+		case event, ok := <-messageChan:
+			if !ok {
+				return nil
+			}
+			chunk, ok := event.Data.(*api.ActionWorkflowJobLogChunk)
+			if !ok {
+				// the log is complete, or the stream should check the log again
+				if finished, err := sync(); err != nil || finished {
+					return err
+				}
+				continue
+			}
+			end := chunk.Offset + int64(len(chunk.Lines))
+			if end <= offset {
+				continue
+			}
+			if chunk.Offset > offset {
+				// some chunks have been dropped, read the missing lines from the stored log
+				if finished, err := sync(); err != nil || finished {
+					return err
+				}
+				continue
+			}
+			chunk = &api.ActionWorkflowJobLogChunk{Offset: offset, Lines: chunk.Lines[offset-chunk.Offset:]}
+			if err := send(chunk); err != nil {
+				return err
+			}
+			offset = end
+		}
+	}
+}
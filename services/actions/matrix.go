@@ -0,0 +1,355 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/nektos/act/pkg/exprparser"
+	"github.com/nektos/act/pkg/jobparser"
+	act_model "github.com/nektos/act/pkg/model"
+	"gopkg.in/yaml.v3"
+)
+
+// isDynamicMatrix returns whether the matrix is computed by expressions, like `${{ fromJSON(needs.setup.outputs.matrix) }}`,
+// or has values computed by expressions, like `os: ${{ fromJSON(needs.setup.outputs.os) }}`.
+func isDynamicMatrix(node *yaml.Node) bool {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return strings.Contains(node.Value, "${{")
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			if v := node.Content[i]; v.Kind == yaml.ScalarNode && strings.Contains(v.Value, "${{") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// prepareDynamicMatrices finds the jobs with dynamic matrices, which can't be expanded until their needs are done.
+// jobparser parses such a job into a single job without the matrix, so the raw name and `runs-on` are restored to it,
+// then it's kept as a placeholder and will be expanded by the job emitter, see expandDynamicMatrix.
+// It returns the raw matrices of the jobs.
+func prepareDynamicMatrices(content []byte, singleWorkflows []*jobparser.SingleWorkflow) (map[string]string, error) {
+	wf, err := act_model.ReadWorkflow(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("read workflow: %w", err)
+	}
+	ret := make(map[string]string)
+	for _, v := range singleWorkflows {
+		id, job := v.Job()
+		origin := wf.GetJob(id)
+		if origin == nil || origin.Strategy == nil || !isDynamicMatrix(&origin.Strategy.RawMatrix) {
+			continue
+		}
+		raw, err := yaml.Marshal(&origin.Strategy.RawMatrix)
+		if err != nil {
+			return nil, fmt.Errorf("marshal matrix of job %q: %w", id, err)
+		}
+		ret[id] = string(raw)
+
+		job.Name = origin.Name
+		if job.Name == "" {
+			job.Name = id
+		}
+		job.RawRunsOn = origin.RawRunsOn
+		if err := v.SetJob(id, job); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// getMaxParallel returns the `max-parallel` of the matrix of a job, 0 means unlimited
+func getMaxParallel(job *jobparser.Job) int {
+	if job.Strategy.MaxParallelString == "" {
+		return 0
+	}
+	maxParallel, err := strconv.Atoi(job.Strategy.MaxParallelString)
+	if err != nil || maxParallel <= 0 {
+		return 0
+	}
+	return maxParallel
+}
+
+// isFailFast returns whether the `fail-fast` of the matrix of a job is enabled, it's enabled by default.
+// See https://docs.github.com/en/actions/writing-workflows/workflow-syntax-for-github-actions#jobsjob_idstrategyfail-fast
+func isFailFast(job *jobparser.Job) bool {
+	if job.Strategy.FailFastString == "" {
+		return true
+	}
+	failFast, err := strconv.ParseBool(job.Strategy.FailFastString)
+	return err != nil || failFast
+}
+
+// evaluateDynamicMatrix evaluates a raw matrix and returns its combinations in the same order as jobparser does
+func evaluateDynamicMatrix(interpreter exprparser.Interpreter, rawMatrix string) ([]map[string]any, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(rawMatrix), &node); err != nil {
+		return nil, fmt.Errorf("unmarshal matrix: %w", err)
+	}
+	if node.Kind == yaml.DocumentNode && len(node.Content) == 1 {
+		node = *node.Content[0]
+	}
+	if err := jobparser.NewExpressionEvaluator(interpreter).EvaluateYamlNode(&node); err != nil {
+		return nil, fmt.Errorf("evaluate matrix: %w", err)
+	}
+
+	job := &act_model.Job{Strategy: &act_model.Strategy{RawMatrix: node}}
+	if job.Matrix() == nil {
+		return nil, errors.New("matrix is not a mapping of lists")
+	}
+	matrixes, err := job.GetMatrixes()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(matrixes, func(i, j int) bool {
+		return matrixName(matrixes[i]) < matrixName(matrixes[j])
+	})
+	return matrixes, nil
+}
+
+// expandDynamicMatrix expands a job with a dynamic matrix into the jobs of the matrix combinations after its needs are done.
+// The job itself becomes the job of the first combination and the jobs of the other combinations are inserted,
+// all of them keep blocked until the next emitting, so their concurrency, environments and max-parallel limit will be checked.
+// A job whose matrix is invalid or empty is marked as failed instead of returning an error, otherwise the whole run will be stuck.
+func expandDynamicMatrix(ctx context.Context, run *actions_model.ActionRun, job *actions_model.ActionRunJob, jobs []*actions_model.ActionRunJob, vars map[string]string) (actions_model.Status, error) {
+	if err := run.LoadAttributes(ctx); err != nil {
+		return 0, err
+	}
+	singleWorkflow, wfJob, err := parseSingleWorkflowJob(job)
+	if err != nil {
+		log.Error("Parse job %d: %v", job.ID, err)
+		return actions_model.StatusFailure, nil
+	}
+	results, err := jobResultsOfScope(ctx, job, jobs)
+	if err != nil {
+		return 0, err
+	}
+	inputs, err := getJobInputs(run, job)
+	if err != nil {
+		log.Error("Get inputs of job %d: %v", job.ID, err)
+		return actions_model.StatusFailure, nil
+	}
+	matrixes, err := evaluateDynamicMatrix(newJobInterpreter(run, job, wfJob, results, vars, inputs), job.RawMatrix)
+	if err != nil {
+		log.Error("Evaluate matrix of job %d: %v", job.ID, err)
+		return actions_model.StatusFailure, nil
+	} else if len(matrixes) == 0 {
+		log.Error("Matrix of job %d has no combinations", job.ID)
+		return actions_model.StatusFailure, nil
+	}
+
+	// the jobs of a reusable workflow are named after the caller, see expandReusableWorkflow
+	var namePrefix string
+	for _, j := range jobs {
+		if job.ParentJobID > 0 && j.ID == job.ParentJobID {
+			namePrefix = j.Name + " / "
+		}
+	}
+	// jobparser evaluates `runs-on` when parsing the payload, so the raw one has to be read from the payload directly
+	wf, err := act_model.ReadWorkflow(bytes.NewReader(job.WorkflowPayload))
+	if err != nil {
+		log.Error("Read workflow of job %d: %v", job.ID, err)
+		return actions_model.StatusFailure, nil
+	}
+	rawRunsOn := wf.GetJob(job.JobID).RunsOn()
+
+	for i, matrix := range matrixes {
+		matrixJob := wfJob.Clone()
+		matrixJob.Strategy.RawMatrix = encodeMatrix(matrix)
+		evaluator := jobparser.NewExpressionEvaluator(newJobInterpreter(run, job, matrixJob, results, vars, inputs))
+		matrixJob.Name = nameWithMatrix(wfJob.Name, matrix, evaluator)
+		runsOn := make([]string, len(rawRunsOn))
+		for k, v := range rawRunsOn {
+			runsOn[k] = evaluator.Interpolate(v)
+		}
+		matrixJob.RawRunsOn = encodeRunsOn(runsOn)
+		if err := singleWorkflow.SetJob(job.JobID, matrixJob); err != nil {
+			return 0, err
+		}
+		payload, _ := singleWorkflow.Marshal()
+		name := util.EllipsisDisplayString(namePrefix+matrixJob.Name, 255)
+
+		if i == 0 {
+			job.Name = name
+			job.RunsOn = runsOn
+			job.WorkflowPayload = payload
+			job.RawMatrix = ""
+			continue
+		}
+		matrixRunJob := &actions_model.ActionRunJob{
+			RunID:              job.RunID,
+			RepoID:             job.RepoID,
+			OwnerID:            job.OwnerID,
+			CommitSHA:          job.CommitSHA,
+			IsForkPullRequest:  job.IsForkPullRequest,
+			Name:               name,
+			WorkflowPayload:    payload,
+			JobID:              job.JobID,
+			Needs:              job.Needs,
+			RunsOn:             runsOn,
+			Status:             actions_model.StatusBlocked,
+			RawConcurrency:     job.RawConcurrency,
+			IsReusableWorkflow: job.IsReusableWorkflow,
+			ParentJobID:        job.ParentJobID,
+			RawEnvironment:     job.RawEnvironment,
+			MaxParallel:        job.MaxParallel,
+		}
+		if err := db.Insert(ctx, matrixRunJob); err != nil {
+			return 0, err
+		}
+	}
+	return actions_model.StatusBlocked, nil
+}
+
+// cancelFailFastMatrixJobs cancels the unfinished jobs of the matrices which have failed jobs and enable `fail-fast`,
+// the jobs of the reusable workflows called by the cancelled jobs are cancelled too.
+// Only the jobs failed in the current attempt of the run are counted, so the rerun jobs of a matrix aren't cancelled
+// by the jobs which failed in the previous attempt and aren't rerun.
+func cancelFailFastMatrixJobs(ctx context.Context, jobs []*actions_model.ActionRunJob) ([]*actions_model.ActionRunJob, error) {
+	type matrixID struct {
+		ParentJobID int64
+		JobID       string
+	}
+	matrixJobs := make(map[matrixID][]*actions_model.ActionRunJob)
+	for _, job := range jobs {
+		id := matrixID{job.ParentJobID, job.JobID}
+		matrixJobs[id] = append(matrixJobs[id], job)
+	}
+
+	previousResults, err := getPreviousAttemptResults(ctx, jobs)
+	if err != nil {
+		return nil, err
+	}
+
+	var cancelledJobs []*actions_model.ActionRunJob
+	for _, sameMatrixJobs := range matrixJobs {
+		if len(sameMatrixJobs) < 2 {
+			continue
+		}
+		var failedJob *actions_model.ActionRunJob
+		var unfinishedJobs []*actions_model.ActionRunJob
+		for _, job := range sameMatrixJobs {
+			if job.Status == actions_model.StatusFailure {
+				if result, ok := previousResults[job.ID]; ok && result.Attempt == job.Attempt && result.Stopped == job.Stopped {
+					// the job isn't rerun, it failed in the previous attempt
+					continue
+				}
+				failedJob = job
+			} else if !job.Status.IsDone() {
+				unfinishedJobs = append(unfinishedJobs, job)
+			}
+		}
+		if failedJob == nil || len(unfinishedJobs) == 0 {
+			continue
+		}
+		if _, wfJob, err := parseSingleWorkflowJob(failedJob); err != nil || !isFailFast(wfJob) {
+			continue
+		}
+		cancelled, err := actions_model.CancelJobs(ctx, withCalledJobs(unfinishedJobs, jobs))
+		cancelledJobs = append(cancelledJobs, cancelled...)
+		if err != nil {
+			return cancelledJobs, err
+		}
+	}
+	return cancelledJobs, nil
+}
+
+// getPreviousAttemptResults returns the results of the jobs in the previous attempt of the run by the ids of the jobs,
+// it returns nil if the run has never been rerun.
+func getPreviousAttemptResults(ctx context.Context, jobs []*actions_model.ActionRunJob) (map[int64]*actions_model.ActionRunAttemptJob, error) {
+	if len(jobs) == 0 || jobs[0].Run == nil || jobs[0].Run.Attempt <= 1 {
+		return nil, nil
+	}
+	run := jobs[0].Run
+	attempt, err := actions_model.GetRunAttempt(ctx, run.ID, run.Attempt-1)
+	if err != nil {
+		return nil, fmt.Errorf("GetRunAttempt: %w", err)
+	}
+	results := make(map[int64]*actions_model.ActionRunAttemptJob, len(attempt.Jobs))
+	for _, job := range attempt.Jobs {
+		results[job.ID] = job
+	}
+	return results, nil
+}
+
+// withCalledJobs returns the given jobs and the jobs of the reusable workflows called by them, including the nested ones
+func withCalledJobs(callers, jobs []*actions_model.ActionRunJob) []*actions_model.ActionRunJob {
+	ret := slices.Clone(callers)
+	for i := 0; i < len(ret); i++ {
+		if !ret[i].IsReusableWorkflow {
+			continue
+		}
+		for _, job := range jobs {
+			if job.ParentJobID == ret[i].ID {
+				ret = append(ret, job)
+			}
+		}
+	}
+	return ret
+}
+
+// encodeMatrix encodes a combination of a matrix in the same way as jobparser,
+// so the combination can be decoded from the payload, see newJobInterpreter.
+func encodeMatrix(matrix map[string]any) yaml.Node {
+	if len(matrix) == 0 {
+		return yaml.Node{}
+	}
+	value := make(map[string][]any, len(matrix))
+	for k, v := range matrix {
+		value[k] = []any{v}
+	}
+	node := yaml.Node{}
+	_ = node.Encode(value)
+	return node
+}
+
+// encodeRunsOn encodes the evaluated `runs-on` of a job in the same way as jobparser
+func encodeRunsOn(runsOn []string) yaml.Node {
+	node := yaml.Node{}
+	if len(runsOn) == 1 {
+		_ = node.Encode(runsOn[0])
+	} else {
+		_ = node.Encode(runsOn)
+	}
+	return node
+}
+
+// nameWithMatrix returns the name of a job of a matrix combination in the same way as jobparser
+func nameWithMatrix(name string, matrix map[string]any, evaluator *jobparser.ExpressionEvaluator) string {
+	if len(matrix) == 0 {
+		return name
+	}
+	if !strings.Contains(name, "${{") || !strings.Contains(name, "}}") {
+		return name + " " + matrixName(matrix)
+	}
+	return evaluator.Interpolate(name)
+}
+
+func matrixName(matrix map[string]any) string {
+	keys := make([]string, 0, len(matrix))
+	for k := range matrix {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	values := make([]string, 0, len(matrix))
+	for _, k := range keys {
+		values = append(values, fmt.Sprint(matrix[k]))
+	}
+	return fmt.Sprintf("(%s)", strings.Join(values, ", "))
+}
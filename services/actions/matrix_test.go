@@ -0,0 +1,138 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+
+	"github.com/nektos/act/pkg/exprparser"
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrepareDynamicMatrices(t *testing.T) {
+	content := []byte(`
+on: push
+jobs:
+  setup:
+    runs-on: ubuntu-latest
+    outputs:
+      matrix: ${{ steps.set.outputs.matrix }}
+    steps:
+      - id: set
+        run: echo 'matrix={"os":["linux","windows"]}' >> $GITHUB_OUTPUT
+  static:
+    runs-on: ${{ matrix.os }}
+    strategy:
+      max-parallel: 1
+      matrix:
+        os: [linux, windows]
+    steps:
+      - run: echo static
+  dynamic:
+    name: build on ${{ matrix.os }}
+    needs: setup
+    runs-on: ${{ matrix.os }}
+    strategy:
+      fail-fast: false
+      max-parallel: 3
+      matrix: ${{ fromJSON(needs.setup.outputs.matrix) }}
+    steps:
+      - run: echo dynamic
+  dynamic-values:
+    needs: setup
+    runs-on: ubuntu-latest
+    strategy:
+      matrix:
+        os: ${{ fromJSON(needs.setup.outputs.os) }}
+        version: [1, 2]
+    steps:
+      - run: echo dynamic values
+`)
+	singleWorkflows, err := jobparser.Parse(content)
+	require.NoError(t, err)
+	rawMatrices, err := prepareDynamicMatrices(content, singleWorkflows)
+	require.NoError(t, err)
+	assert.Len(t, rawMatrices, 2)
+	assert.Contains(t, rawMatrices["dynamic"], "fromJSON(needs.setup.outputs.matrix)")
+	assert.Contains(t, rawMatrices["dynamic-values"], "fromJSON(needs.setup.outputs.os)")
+
+	for _, v := range singleWorkflows {
+		id, job := v.Job()
+		switch id {
+		case "static":
+			assert.Equal(t, 1, getMaxParallel(job))
+			assert.True(t, isFailFast(job))
+		case "dynamic":
+			// the raw name and runs-on are kept until the matrix is expanded
+			assert.Equal(t, "build on ${{ matrix.os }}", job.Name)
+			assert.Equal(t, []string{"${{ matrix.os }}"}, job.RunsOn())
+			assert.Equal(t, 3, getMaxParallel(job))
+			assert.False(t, isFailFast(job))
+		case "dynamic-values":
+			assert.Equal(t, "dynamic-values", job.Name)
+		}
+	}
+}
+
+func TestEvaluateDynamicMatrix(t *testing.T) {
+	interpreter := exprparser.NewInterpeter(&exprparser.EvaluationEnvironment{
+		Needs: map[string]exprparser.Needs{
+			"setup": {Outputs: map[string]string{
+				"matrix": `{"os":["linux","windows"],"include":[{"os":"linux","arch":"arm64"}]}`,
+				"os":     `["linux","windows"]`,
+			}},
+		},
+	}, exprparser.Config{})
+
+	matrixes, err := evaluateDynamicMatrix(interpreter, "${{ fromJSON(needs.setup.outputs.matrix) }}\n")
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{
+		{"os": "linux", "arch": "arm64"},
+		{"os": "windows"},
+	}, matrixes)
+
+	matrixes, err = evaluateDynamicMatrix(interpreter, "os: ${{ fromJSON(needs.setup.outputs.os) }}\nversion: [1, 2]\n")
+	require.NoError(t, err)
+	assert.Len(t, matrixes, 4)
+	assert.Equal(t, map[string]any{"os": "linux", "version": 1}, matrixes[0])
+
+	_, err = evaluateDynamicMatrix(interpreter, "${{ needs.setup.outputs.os }}\n")
+	assert.Error(t, err)
+}
+
+func TestCancelFailFastMatrixJobs(t *testing.T) {
+	payload := func(failFast string) []byte {
+		return []byte(`
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    strategy:
+      fail-fast: ` + failFast + `
+      matrix:
+        os: [linux]
+    steps:
+      - run: echo build
+`)
+	}
+	jobs := []*actions_model.ActionRunJob{
+		{ID: 1, JobID: "build", Status: actions_model.StatusFailure, WorkflowPayload: payload("false")},
+		{ID: 2, JobID: "build", Status: actions_model.StatusRunning, WorkflowPayload: payload("false")},
+		{ID: 3, JobID: "test", Status: actions_model.StatusFailure, WorkflowPayload: payload("true")},
+		{ID: 4, JobID: "lint", Status: actions_model.StatusWaiting, WorkflowPayload: payload("true")},
+	}
+	// nothing is cancelled if fail-fast is disabled or the failed job isn't in a matrix
+	cancelled, err := cancelFailFastMatrixJobs(t.Context(), jobs)
+	require.NoError(t, err)
+	assert.Empty(t, cancelled)
+
+	assert.ElementsMatch(t, jobs[2:3], withCalledJobs(jobs[2:3], jobs))
+	callers := []*actions_model.ActionRunJob{{ID: 5, JobID: "caller", IsReusableWorkflow: true}}
+	called := &actions_model.ActionRunJob{ID: 6, JobID: "build", ParentJobID: 5}
+	assert.Equal(t, []*actions_model.ActionRunJob{callers[0], called}, withCalledJobs(callers, append(jobs, called)))
+}
@@ -648,6 +648,45 @@ func (n *actionsNotifier) DeleteRelease(ctx context.Context, doer *user_model.Us
 	notifyRelease(ctx, doer, rel, api.HookReleaseDeleted)
 }
 
+func (n *actionsNotifier) NewLabel(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, label *issues_model.Label) {
+	ctx = withMethod(ctx, "NewLabel")
+	notifyLabel(ctx, doer, repo, label, api.HookLabelCreated)
+}
+
+func (n *actionsNotifier) UpdateLabel(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, label *issues_model.Label) {
+	ctx = withMethod(ctx, "UpdateLabel")
+	notifyLabel(ctx, doer, repo, label, api.HookLabelEdited)
+}
+
+func (n *actionsNotifier) DeleteLabel(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, label *issues_model.Label) {
+	ctx = withMethod(ctx, "DeleteLabel")
+	notifyLabel(ctx, doer, repo, label, api.HookLabelDeleted)
+}
+
+func (n *actionsNotifier) NewMilestone(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, milestone *issues_model.Milestone) {
+	ctx = withMethod(ctx, "NewMilestone")
+	notifyMilestone(ctx, doer, repo, milestone, api.HookMilestoneCreated)
+}
+
+func (n *actionsNotifier) UpdateMilestone(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, milestone *issues_model.Milestone) {
+	ctx = withMethod(ctx, "UpdateMilestone")
+	notifyMilestone(ctx, doer, repo, milestone, api.HookMilestoneEdited)
+}
+
+func (n *actionsNotifier) ChangeMilestoneStatus(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, milestone *issues_model.Milestone, isClosed bool) {
+	ctx = withMethod(ctx, "ChangeMilestoneStatus")
+	if isClosed {
+		notifyMilestone(ctx, doer, repo, milestone, api.HookMilestoneClosed)
+	} else {
+		notifyMilestone(ctx, doer, repo, milestone, api.HookMilestoneOpened)
+	}
+}
+
+func (n *actionsNotifier) DeleteMilestone(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, milestone *issues_model.Milestone) {
+	ctx = withMethod(ctx, "DeleteMilestone")
+	notifyMilestone(ctx, doer, repo, milestone, api.HookMilestoneDeleted)
+}
+
 func (n *actionsNotifier) PackageCreate(ctx context.Context, doer *user_model.User, pd *packages_model.PackageDescriptor) {
 	ctx = withMethod(ctx, "PackageCreate")
 	notifyPackage(ctx, doer, pd, api.HookPackageCreated)
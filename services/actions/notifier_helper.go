@@ -27,7 +27,6 @@ import (
 	api "code.gitea.io/gitea/modules/structs"
 	webhook_module "code.gitea.io/gitea/modules/webhook"
 	"code.gitea.io/gitea/services/convert"
-	notify_service "code.gitea.io/gitea/services/notify"
 
 	"github.com/nektos/act/pkg/jobparser"
 	"github.com/nektos/act/pkg/model"
@@ -119,7 +118,8 @@ func (input *notifyInput) Notify(ctx context.Context) {
 
 func notify(ctx context.Context, input *notifyInput) error {
 	shouldDetectSchedules := input.Event == webhook_module.HookEventPush && input.Ref.BranchName() == input.Repo.DefaultBranch
-	if input.Doer.IsGiteaActions() {
+	// like GitHub, the repository_dispatch event sent with the token of a job still triggers workflows
+	if input.Doer.IsGiteaActions() && input.Event != webhook_module.HookEventRepositoryDispatch {
 		// avoiding triggering cyclically, for example:
 		// a comment of an issue will trigger the runner to add a new comment as reply,
 		// and the new comment will trigger the runner again.
@@ -346,29 +346,6 @@ func handleWorkflows(
 
 		run.NeedApproval = need
 
-		if err := run.LoadAttributes(ctx); err != nil {
-			log.Error("LoadAttributes: %v", err)
-			continue
-		}
-
-		vars, err := actions_model.GetVariablesOfRun(ctx, run)
-		if err != nil {
-			log.Error("GetVariablesOfRun: %v", err)
-			continue
-		}
-
-		giteaCtx := GenerateGiteaContext(run, nil)
-
-		jobs, err := jobparser.Parse(dwf.Content, jobparser.WithVars(vars), jobparser.WithGitContext(giteaCtx.ToGitHubContext()))
-		if err != nil {
-			log.Error("jobparser.Parse: %v", err)
-			continue
-		}
-
-		if len(jobs) > 0 && jobs[0].RunName != "" {
-			run.Title = jobs[0].RunName
-		}
-
 		// cancel running jobs if the event is push or pull_request_sync
 		if run.Event == webhook_module.HookEventPush ||
 			run.Event == webhook_module.HookEventPullRequestSync {
@@ -383,28 +360,8 @@ func handleWorkflows(
 			}
 		}
 
-		if err := actions_model.InsertRun(ctx, run, jobs); err != nil {
-			log.Error("InsertRun: %v", err)
-			continue
-		}
-
-		alljobs, err := db.Find[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{RunID: run.ID})
-		if err != nil {
-			log.Error("FindRunJobs: %v", err)
-			continue
-		}
-		CreateCommitStatus(ctx, alljobs...)
-		if len(alljobs) > 0 {
-			job := alljobs[0]
-			err := job.LoadRun(ctx)
-			if err != nil {
-				log.Error("LoadRun: %v", err)
-				continue
-			}
-			notify_service.WorkflowRunStatusUpdate(ctx, job.Run.Repo, job.Run.TriggerUser, job.Run)
-		}
-		for _, job := range alljobs {
-			notify_service.WorkflowJobStatusUpdate(ctx, input.Repo, input.Doer, job, nil)
+		if err := PrepareRunAndInsert(ctx, dwf.Content, run); err != nil {
+			log.Error("PrepareRunAndInsert: %v", err)
 		}
 	}
 	return nil
@@ -433,6 +390,32 @@ func notifyRelease(ctx context.Context, doer *user_model.User, rel *repo_model.R
 		Notify(ctx)
 }
 
+func notifyLabel(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, label *issues_model.Label, action api.HookLabelAction) {
+	permission, _ := access_model.GetUserRepoPermission(ctx, repo, doer)
+
+	newNotifyInput(repo, doer, webhook_module.HookEventLabel).
+		WithPayload(&api.LabelPayload{
+			Action:     action,
+			Label:      convert.ToLabel(label, repo, nil),
+			Repository: convert.ToRepo(ctx, repo, permission),
+			Sender:     convert.ToUser(ctx, doer, nil),
+		}).
+		Notify(ctx)
+}
+
+func notifyMilestone(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, milestone *issues_model.Milestone, action api.HookMilestoneAction) {
+	permission, _ := access_model.GetUserRepoPermission(ctx, repo, doer)
+
+	newNotifyInput(repo, doer, webhook_module.HookEventMilestone).
+		WithPayload(&api.MilestonePayload{
+			Action:     action,
+			Milestone:  convert.ToAPIMilestone(milestone),
+			Repository: convert.ToRepo(ctx, repo, permission),
+			Sender:     convert.ToUser(ctx, doer, nil),
+		}).
+		Notify(ctx)
+}
+
 func notifyPackage(ctx context.Context, sender *user_model.User, pd *packages_model.PackageDescriptor, action api.HookPackageAction) {
 	if pd.Repository == nil {
 		// When a package is uploaded to an organization, it could trigger an event to notify.
@@ -478,6 +461,19 @@ func ifNeedApproval(ctx context.Context, run *actions_model.ActionRun, repo *rep
 		return false, nil
 	}
 
+	policy, err := GetRepoApprovalPolicy(ctx, repo)
+	if err != nil {
+		return false, fmt.Errorf("GetRepoApprovalPolicy: %w", err)
+	}
+	switch policy {
+	case repo_model.ActionsApprovalPolicyNone:
+		log.Trace("do not need approval because repo %d doesn't require approval", repo.ID)
+		return false, nil
+	case repo_model.ActionsApprovalPolicyAllExternalContributors:
+		log.Trace("need approval because repo %d requires approval for all external contributors", repo.ID)
+		return true, nil
+	}
+
 	// don't need approval if the user has been approved before
 	if count, err := db.Count[actions_model.ActionRun](ctx, actions_model.FindRunOptions{
 		RepoID:        repo.ID,
@@ -538,7 +534,11 @@ func handleSchedules(
 			log.Error("ReadWorkflow: %v", err)
 			continue
 		}
-		schedules := workflow.OnSchedule()
+		schedules, err := actions_module.GetScheduleSpecs(workflow)
+		if err != nil {
+			log.Error("GetScheduleSpecs: %v", err)
+			continue
+		}
 		if len(schedules) == 0 {
 			log.Warn("no schedule event")
 			continue
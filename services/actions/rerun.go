@@ -4,12 +4,270 @@
 package actions
 
 import (
+	"context"
+	"fmt"
+
 	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unit"
 	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+	notify_service "code.gitea.io/gitea/services/notify"
+
+	"xorm.io/builder"
+)
+
+// the variables to enable the debug logging of the runners, see
+// https://docs.github.com/en/actions/monitoring-and-troubleshooting-workflows/troubleshooting-workflows/enabling-debug-logging
+const (
+	debugStepLoggingVariable   = "ACTIONS_STEP_DEBUG"
+	debugRunnerLoggingVariable = "ACTIONS_RUNNER_DEBUG"
 )
 
+// RerunRun reruns all jobs of the run as a new attempt
+func RerunRun(ctx context.Context, run *actions_model.ActionRun, debug bool) error {
+	if err := checkRunRerunnable(ctx, run); err != nil {
+		return err
+	}
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+	if err != nil {
+		return fmt.Errorf("GetRunJobsByRunID: %w", err)
+	}
+	return rerunJobs(ctx, run, jobs, jobs, debug)
+}
+
+// RerunFailedJobs reruns the failed and cancelled jobs of the run and the jobs depending on them as a new attempt
+func RerunFailedJobs(ctx context.Context, run *actions_model.ActionRun, debug bool) error {
+	if err := checkRunRerunnable(ctx, run); err != nil {
+		return err
+	}
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+	if err != nil {
+		return fmt.Errorf("GetRunJobsByRunID: %w", err)
+	}
+	failedJobs := GetFailedRerunJobs(jobs)
+	if len(failedJobs) == 0 {
+		return util.ErrorWrapLocale(
+			util.NewInvalidArgumentErrorf("run %d has no failed jobs", run.ID),
+			"actions.runs.no_failed_jobs",
+		)
+	}
+	return rerunJobs(ctx, run, jobs, failedJobs, debug)
+}
+
+// RerunJob reruns the job of the run and the jobs depending on it as a new attempt
+func RerunJob(ctx context.Context, run *actions_model.ActionRun, job *actions_model.ActionRunJob, debug bool) error {
+	if job.RunID != run.ID {
+		return util.NewInvalidArgumentErrorf("job %d doesn't belong to run %d", job.ID, run.ID)
+	}
+	if err := checkRunRerunnable(ctx, run); err != nil {
+		return err
+	}
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+	if err != nil {
+		return fmt.Errorf("GetRunJobsByRunID: %w", err)
+	}
+	for _, j := range jobs {
+		if j.ID == job.ID {
+			return rerunJobs(ctx, run, jobs, GetAllRerunJobs(j, jobs), debug)
+		}
+	}
+	return util.NewNotExistErrorf("job %d of run %d", job.ID, run.ID)
+}
+
+// checkRunRerunnable checks whether the jobs of the run can be rerun
+func checkRunRerunnable(ctx context.Context, run *actions_model.ActionRun) error {
+	if err := run.LoadRepo(ctx); err != nil {
+		return err
+	}
+	// can not rerun job when workflow is disabled
+	cfgUnit := run.Repo.MustGetUnit(ctx, unit.TypeActions)
+	if cfgUnit.ActionsConfig().IsWorkflowDisabled(run.WorkflowID) {
+		return util.ErrorWrapLocale(
+			util.NewInvalidArgumentErrorf("workflow %s is disabled", run.WorkflowID),
+			"actions.workflow.disabled",
+		)
+	}
+	if !run.Status.IsDone() {
+		return util.ErrorWrapLocale(
+			util.NewInvalidArgumentErrorf("run %d is not done", run.ID),
+			"actions.runs.not_done",
+		)
+	}
+	if policy, err := checkOwnerUsageQuota(ctx, run.OwnerID); err != nil {
+		return err
+	} else if policy == actions_model.UsageQuotaPolicyHard {
+		return util.ErrorWrapLocale(
+			util.NewInvalidArgumentErrorf("the owner of run %d has used up the usage quota", run.ID),
+			"actions.usage_quota_rerun_blocked",
+		)
+	}
+	return nil
+}
+
+// GetFailedRerunJobs returns the failed and cancelled jobs and all jobs that need to be rerun with them
+func GetFailedRerunJobs(allJobs []*actions_model.ActionRunJob) []*actions_model.ActionRunJob {
+	rerunJobSet := make(container.Set[int64])
+	for _, job := range allJobs {
+		if job.Status != actions_model.StatusFailure && job.Status != actions_model.StatusCancelled {
+			continue
+		}
+		for _, j := range GetAllRerunJobs(job, allJobs) {
+			rerunJobSet.Add(j.ID)
+		}
+	}
+	// keep the order of the jobs
+	var rerunJobs []*actions_model.ActionRunJob
+	for _, job := range allJobs {
+		if rerunJobSet.Contains(job.ID) {
+			rerunJobs = append(rerunJobs, job)
+		}
+	}
+	return rerunJobs
+}
+
+// rerunJobs records the current attempt of the run and starts a new attempt with the rerun jobs,
+// the other jobs keep their results in the new attempt.
+func rerunJobs(ctx context.Context, run *actions_model.ActionRun, allJobs, rerunJobs []*actions_model.ActionRunJob, debug bool) error {
+	if len(rerunJobs) == 0 {
+		return util.NewInvalidArgumentErrorf("run %d has no jobs to rerun", run.ID)
+	}
+
+	rerunJobIDs := make(container.Set[string], len(rerunJobs))
+	for _, j := range rerunJobs {
+		rerunJobIDs.Add(fmt.Sprintf("%d/%s", j.ParentJobID, j.JobID))
+	}
+
+	var updatedJobs, cancelledJobs []*actions_model.ActionRunJob
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		if err := actions_model.InsertRunAttempt(ctx, actions_model.NewRunAttempt(run, allJobs)); err != nil {
+			return fmt.Errorf("InsertRunAttempt: %w", err)
+		}
+
+		run.PreviousDuration = run.Duration()
+		run.Started = 0
+		run.Stopped = 0
+		run.Attempt++
+		run.IsDebugEnabled = debug
+		if err := actions_model.UpdateRun(ctx, run, "started", "stopped", "previous_duration", "attempt", "is_debug_enabled"); err != nil {
+			return err
+		}
+
+		// the new attempt takes the workflow-level concurrency group like a new run
+		cancelled, err := actions_model.CancelPreviousJobsByRunConcurrency(ctx, run)
+		cancelledJobs = append(cancelledJobs, cancelled...)
+		if err != nil {
+			return fmt.Errorf("CancelPreviousJobsByRunConcurrency: %w", err)
+		}
+
+		for _, j := range rerunJobs {
+			// the jobs which need other rerun jobs should wait for them,
+			// the jobs of reusable workflows should wait for their callers to be started by the job emitter,
+			// and the other jobs which have to be checked before running or whose run or themselves declare concurrency
+			// should wait for the job emitter too, which checks the concurrency groups
+			shouldBlock := j.ParentJobID > 0 || j.IsStartedByJobEmitter() || run.ConcurrencyGroup != "" || j.RawConcurrency != ""
+			for _, need := range j.Needs {
+				if rerunJobIDs.Contains(fmt.Sprintf("%d/%s", j.ParentJobID, need)) {
+					shouldBlock = true
+					break
+				}
+			}
+			updated, err := resetRerunJob(ctx, j, shouldBlock)
+			if err != nil {
+				return err
+			}
+			if updated {
+				updatedJobs = append(updatedJobs, j)
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	notifyWorkflowJobStatusUpdate(ctx, cancelledJobs)
+	CreateCommitStatus(ctx, updatedJobs...)
+	if len(updatedJobs) > 0 {
+		NotifyWorkflowRunStatusUpdateWithReload(ctx, updatedJobs[0])
+	}
+	for _, job := range updatedJobs {
+		_ = job.LoadAttributes(ctx)
+		notify_service.WorkflowJobStatusUpdate(ctx, job.Run.Repo, job.Run.TriggerUser, job, nil)
+	}
+
+	if err := EmitJobsIfReady(run.ID); err != nil {
+		log.Error("Emit ready jobs of run %d: %v", run.ID, err)
+	}
+	return nil
+}
+
+// resetRerunJob resets the job to be picked by runners again, the tasks of the previous attempts are kept
+func resetRerunJob(ctx context.Context, job *actions_model.ActionRunJob, shouldBlock bool) (bool, error) {
+	status := job.Status
+	if !status.IsDone() {
+		return false, nil
+	}
+
+	job.TaskID = 0
+	job.Status = actions_model.StatusWaiting
+	if shouldBlock {
+		job.Status = actions_model.StatusBlocked
+	}
+	job.Started = 0
+	job.Stopped = 0
+	// the environment is evaluated again, and the protection rules have to be passed again
+	job.IsEnvironmentEvaluated = false
+	job.EnvironmentID = 0
+	job.EnvironmentWaitUntil = 0
+	// the concurrency is evaluated again by the job emitter
+	job.IsConcurrencyEvaluated = false
+	job.ConcurrencyGroup = ""
+	job.ConcurrencyCancel = false
+
+	n, err := actions_model.UpdateRunJob(ctx, job, builder.Eq{"status": status}, "task_id", "status", "started", "stopped", "is_environment_evaluated", "environment_id", "environment_wait_until",
+		"is_concurrency_evaluated", "concurrency_group", "concurrency_cancel")
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
 // GetAllRerunJobs get all jobs that need to be rerun when job should be rerun
 func GetAllRerunJobs(job *actions_model.ActionRunJob, allJobs []*actions_model.ActionRunJob) []*actions_model.ActionRunJob {
+	rerunJobSet := make(container.Set[*actions_model.ActionRunJob])
+	var rerunJobs []*actions_model.ActionRunJob
+	add := func(jobs ...*actions_model.ActionRunJob) {
+		for _, j := range jobs {
+			if rerunJobSet.Add(j) {
+				rerunJobs = append(rerunJobs, j)
+			}
+		}
+	}
+
+	for _, j := range getRerunJobsOfWorkflow(job, allJobs) {
+		add(j)
+		add(getReusableWorkflowJobs(j, allJobs)...)
+	}
+
+	// the callers of the job and the jobs depending on them should be rerun too
+	idToJob := make(map[int64]*actions_model.ActionRunJob, len(allJobs))
+	for _, j := range allJobs {
+		idToJob[j.ID] = j
+	}
+	for caller := getCallerJob(job, idToJob); caller != nil; caller = getCallerJob(caller, idToJob) {
+		add(caller)
+		for _, j := range getRerunJobsOfWorkflow(caller, allJobs)[1:] {
+			add(j)
+			add(getReusableWorkflowJobs(j, allJobs)...)
+		}
+	}
+
+	return rerunJobs
+}
+
+// getRerunJobsOfWorkflow returns the job and the jobs depending on it in the same workflow
+func getRerunJobsOfWorkflow(job *actions_model.ActionRunJob, allJobs []*actions_model.ActionRunJob) []*actions_model.ActionRunJob {
 	rerunJobs := []*actions_model.ActionRunJob{job}
 	rerunJobsIDSet := make(container.Set[string])
 	rerunJobsIDSet.Add(job.JobID)
@@ -17,7 +275,7 @@ func GetAllRerunJobs(job *actions_model.ActionRunJob, allJobs []*actions_model.A
 	for {
 		found := false
 		for _, j := range allJobs {
-			if rerunJobsIDSet.Contains(j.JobID) {
+			if j.ParentJobID != job.ParentJobID || rerunJobsIDSet.Contains(j.JobID) {
 				continue
 			}
 			for _, need := range j.Needs {
@@ -36,3 +294,25 @@ func GetAllRerunJobs(job *actions_model.ActionRunJob, allJobs []*actions_model.A
 
 	return rerunJobs
 }
+
+// getReusableWorkflowJobs returns all the jobs of the reusable workflows called by the job, including the nested ones
+func getReusableWorkflowJobs(job *actions_model.ActionRunJob, allJobs []*actions_model.ActionRunJob) []*actions_model.ActionRunJob {
+	if !job.IsReusableWorkflow {
+		return nil
+	}
+	var ret []*actions_model.ActionRunJob
+	for _, j := range allJobs {
+		if j.ParentJobID == job.ID {
+			ret = append(ret, j)
+			ret = append(ret, getReusableWorkflowJobs(j, allJobs)...)
+		}
+	}
+	return ret
+}
+
+func getCallerJob(job *actions_model.ActionRunJob, idToJob map[int64]*actions_model.ActionRunJob) *actions_model.ActionRunJob {
+	if job.ParentJobID == 0 {
+		return nil
+	}
+	return idToJob[job.ParentJobID]
+}
@@ -46,3 +46,53 @@ func TestGetAllRerunJobs(t *testing.T) {
 		assert.ElementsMatch(t, tc.rerunJobs, rerunJobs)
 	}
 }
+
+func TestGetAllRerunJobsOfReusableWorkflow(t *testing.T) {
+	job1 := &actions_model.ActionRunJob{ID: 1, JobID: "job1"}
+	caller := &actions_model.ActionRunJob{ID: 2, JobID: "caller", Needs: []string{"job1"}, IsReusableWorkflow: true}
+	job3 := &actions_model.ActionRunJob{ID: 3, JobID: "job3", Needs: []string{"caller"}}
+	child1 := &actions_model.ActionRunJob{ID: 4, JobID: "job1", ParentJobID: 2}
+	child2 := &actions_model.ActionRunJob{ID: 5, JobID: "child2", Needs: []string{"job1"}, ParentJobID: 2}
+	child3 := &actions_model.ActionRunJob{ID: 6, JobID: "child3", ParentJobID: 2}
+
+	jobs := []*actions_model.ActionRunJob{job1, caller, job3, child1, child2, child3}
+
+	testCases := []struct {
+		job       *actions_model.ActionRunJob
+		rerunJobs []*actions_model.ActionRunJob
+	}{
+		{
+			job1,
+			[]*actions_model.ActionRunJob{job1, caller, job3, child1, child2, child3},
+		},
+		{
+			caller,
+			[]*actions_model.ActionRunJob{caller, job3, child1, child2, child3},
+		},
+		{
+			child1,
+			[]*actions_model.ActionRunJob{child1, child2, caller, job3},
+		},
+		{
+			child3,
+			[]*actions_model.ActionRunJob{child3, caller, job3},
+		},
+	}
+
+	for _, tc := range testCases {
+		rerunJobs := GetAllRerunJobs(tc.job, jobs)
+		assert.ElementsMatch(t, tc.rerunJobs, rerunJobs)
+	}
+}
+
+func TestGetFailedRerunJobs(t *testing.T) {
+	job1 := &actions_model.ActionRunJob{ID: 1, JobID: "job1", Status: actions_model.StatusSuccess}
+	job2 := &actions_model.ActionRunJob{ID: 2, JobID: "job2", Status: actions_model.StatusFailure}
+	job3 := &actions_model.ActionRunJob{ID: 3, JobID: "job3", Needs: []string{"job1"}, Status: actions_model.StatusSuccess}
+	job4 := &actions_model.ActionRunJob{ID: 4, JobID: "job4", Needs: []string{"job2"}, Status: actions_model.StatusSkipped}
+	job5 := &actions_model.ActionRunJob{ID: 5, JobID: "job5", Status: actions_model.StatusCancelled}
+	job6 := &actions_model.ActionRunJob{ID: 6, JobID: "job6", Needs: []string{"job3", "job5"}, Status: actions_model.StatusSkipped}
+
+	assert.Equal(t, []*actions_model.ActionRunJob{job2, job4, job5, job6}, GetFailedRerunJobs([]*actions_model.ActionRunJob{job1, job2, job3, job4, job5, job6}))
+	assert.Empty(t, GetFailedRerunJobs([]*actions_model.ActionRunJob{job1, job3}))
+}
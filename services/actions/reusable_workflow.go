@@ -0,0 +1,487 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/nektos/act/pkg/exprparser"
+	"github.com/nektos/act/pkg/jobparser"
+	act_model "github.com/nektos/act/pkg/model"
+	"gopkg.in/yaml.v3"
+)
+
+// inputEnvPrefix is the prefix of the environment variables which pass the inputs of a reusable workflow to its jobs,
+// act_runner reads the `inputs` context from them.
+const inputEnvPrefix = "INPUT_"
+
+// parseSingleWorkflowJob parses the payload of a job
+func parseSingleWorkflowJob(job *actions_model.ActionRunJob) (*jobparser.SingleWorkflow, *jobparser.Job, error) {
+	singleWorkflows, err := jobparser.Parse(job.WorkflowPayload)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse single workflow: %w", err)
+	} else if len(singleWorkflows) != 1 {
+		return nil, nil, errors.New("not single workflow")
+	}
+	_, wfJob := singleWorkflows[0].Job()
+	return singleWorkflows[0], wfJob, nil
+}
+
+// getJobInputs returns the `inputs` context of a job.
+// The inputs of a job in a reusable workflow are passed by the environment variables, see expandReusableWorkflow.
+func getJobInputs(run *actions_model.ActionRun, job *actions_model.ActionRunJob) (map[string]any, error) {
+	if job.ParentJobID == 0 {
+		return getWorkflowDispatchInputs(run), nil
+	}
+	_, wfJob, err := parseSingleWorkflowJob(job)
+	if err != nil {
+		return nil, err
+	}
+	var env map[string]string
+	if err := wfJob.Env.Decode(&env); err != nil {
+		return nil, fmt.Errorf("decode env: %w", err)
+	}
+	inputs := make(map[string]any)
+	for k, v := range env {
+		if name, ok := strings.CutPrefix(k, inputEnvPrefix); ok {
+			inputs[strings.ToLower(name)] = v
+		}
+	}
+	return inputs, nil
+}
+
+// jobResultsOfScope returns the results of the jobs which belong to the same workflow as the given job,
+// it's used to evaluate the expressions of the job, since the status functions like `failure()` require the transitive needs.
+func jobResultsOfScope(ctx context.Context, job *actions_model.ActionRunJob, jobs []*actions_model.ActionRunJob) (map[string]*jobparser.JobResult, error) {
+	taskNeeds, err := FindTaskNeeds(ctx, job)
+	if err != nil {
+		return nil, fmt.Errorf("find task needs: %w", err)
+	}
+
+	idToJobs := make(map[string][]*actions_model.ActionRunJob)
+	for _, j := range jobs {
+		if j.ParentJobID == job.ParentJobID {
+			idToJobs[j.JobID] = append(idToJobs[j.JobID], j)
+		}
+	}
+	results := make(map[string]*jobparser.JobResult, len(idToJobs))
+	for jobID, sameIDJobs := range idToJobs {
+		result := &jobparser.JobResult{
+			Needs:  sameIDJobs[0].Needs,
+			Result: actions_model.AggregateJobStatus(sameIDJobs).String(),
+		}
+		if taskNeed, ok := taskNeeds[jobID]; ok {
+			result.Outputs = taskNeed.Outputs
+		}
+		results[jobID] = result
+	}
+	results[job.JobID] = &jobparser.JobResult{Needs: job.Needs}
+	return results, nil
+}
+
+// newJobInterpreter returns an interpreter to evaluate the expressions of a job on the server side,
+// the job-level contexts and the status functions are available, see jobparser.NewInterpeter.
+func newJobInterpreter(run *actions_model.ActionRun, job *actions_model.ActionRunJob, wfJob *jobparser.Job, results map[string]*jobparser.JobResult, vars map[string]string, inputs map[string]any) exprparser.Interpreter {
+	// the matrix of a job is encoded as the lists of the single values of its combination, see encodeMatrix
+	var rawMatrix map[string][]any
+	_ = wfJob.Strategy.RawMatrix.Decode(&rawMatrix)
+	matrix := make(map[string]any, len(rawMatrix))
+	for k, v := range rawMatrix {
+		if len(v) > 0 {
+			matrix[k] = v[0]
+		}
+	}
+	strategy := &act_model.Strategy{
+		FailFastString:    wfJob.Strategy.FailFastString,
+		MaxParallelString: wfJob.Strategy.MaxParallelString,
+	}
+
+	actRun := &act_model.Run{
+		Workflow: &act_model.Workflow{Jobs: map[string]*act_model.Job{}},
+		JobID:    job.JobID,
+	}
+	needs := make(map[string]exprparser.Needs, len(job.Needs))
+	for id, result := range results {
+		rawNeeds := yaml.Node{}
+		_ = rawNeeds.Encode(result.Needs)
+		actRun.Workflow.Jobs[id] = &act_model.Job{
+			RawNeeds: rawNeeds,
+			Result:   result.Result,
+			Outputs:  result.Outputs,
+		}
+	}
+	for _, need := range job.Needs {
+		if result, ok := results[need]; ok {
+			needs[need] = exprparser.Needs{Outputs: result.Outputs, Result: result.Result}
+		}
+	}
+
+	giteaCtx := GenerateGiteaContext(run, job)
+	return exprparser.NewInterpeter(&exprparser.EvaluationEnvironment{
+		Github: giteaCtx.ToGitHubContext(),
+		Job:    &act_model.JobContext{},
+		Strategy: map[string]any{
+			"fail-fast":    strategy.GetFailFast(),
+			"max-parallel": strategy.GetMaxParallel(),
+		},
+		Matrix: matrix,
+		Needs:  needs,
+		Inputs: inputs,
+		Vars:   vars,
+	}, exprparser.Config{Run: actRun, Context: "job"})
+}
+
+// evaluateReusableWorkflowCallerIf evaluates the `if` condition of a job calling a reusable workflow.
+// Such a job is never picked by runners, so the condition has to be evaluated by the server.
+func evaluateReusableWorkflowCallerIf(interpreter exprparser.Interpreter, wfJob *jobparser.Job) (bool, error) {
+	var cond string
+	if err := wfJob.If.Decode(&cond); err != nil && wfJob.If.Kind != 0 {
+		return false, fmt.Errorf("decode if: %w", err)
+	}
+	cond = strings.TrimSpace(cond)
+	if strings.HasPrefix(cond, "${{") && strings.HasSuffix(cond, "}}") {
+		cond = strings.TrimSuffix(strings.TrimPrefix(cond, "${{"), "}}")
+	}
+	result, err := interpreter.Evaluate(cond, exprparser.DefaultStatusCheckSuccess)
+	if err != nil {
+		return false, fmt.Errorf("evaluate if %q: %w", cond, err)
+	}
+	return exprparser.IsTruthy(result), nil
+}
+
+// loadReusableWorkflowContent loads the content of a reusable workflow.
+// A workflow of another repository can be used only if it passes checkReusableWorkflowAccess.
+func loadReusableWorkflowContent(ctx context.Context, run *actions_model.ActionRun, ref *actions_module.ReusableWorkflowRef) ([]byte, error) {
+	repo, commitID := run.Repo, run.CommitSHA
+	if !ref.IsLocal() {
+		var err error
+		repo, err = repo_model.GetRepositoryByOwnerAndName(ctx, ref.OwnerName, ref.RepoName)
+		if err != nil {
+			return nil, fmt.Errorf("get repository %s/%s: %w", ref.OwnerName, ref.RepoName, err)
+		}
+		if err := checkReusableWorkflowAccess(ctx, run, repo); err != nil {
+			return nil, err
+		}
+		commitID = ref.Ref
+	}
+
+	gitRepo, err := gitrepo.OpenRepository(ctx, repo)
+	if err != nil {
+		return nil, fmt.Errorf("open repository %s: %w", repo.FullName(), err)
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetCommit(commitID)
+	if err != nil {
+		return nil, fmt.Errorf("get commit %q of %s: %w", commitID, repo.FullName(), err)
+	}
+	entry, err := commit.GetTreeEntryByPath(ref.Path)
+	if err != nil {
+		return nil, fmt.Errorf("get workflow %q of %s: %w", ref.Path, repo.FullName(), err)
+	}
+	return actions_module.GetContentFromEntry(entry)
+}
+
+// checkReusableWorkflowAccess checks whether the run can use a workflow of another repository.
+// The trigger user must be able to read the code of the called repository,
+// and a private workflow must not be pulled into the logs of a public repository.
+func checkReusableWorkflowAccess(ctx context.Context, run *actions_model.ActionRun, repo *repo_model.Repository) error {
+	if repo.IsPrivate && !run.Repo.IsPrivate {
+		return util.NewPermissionDeniedErrorf("public repository %s can not use reusable workflow of private repository %s", run.Repo.FullName(), repo.FullName())
+	}
+
+	perm, err := access_model.GetUserRepoPermission(ctx, repo, run.TriggerUser)
+	if err != nil {
+		return fmt.Errorf("GetUserRepoPermission: %w", err)
+	}
+	if !perm.CanRead(unit.TypeCode) {
+		return util.NewPermissionDeniedErrorf("user %s can not access reusable workflow of repository %s", run.TriggerUser.Name, repo.FullName())
+	}
+	return nil
+}
+
+// isWorkflowCallable returns whether the workflow is triggered by `workflow_call`
+func isWorkflowCallable(wf *act_model.Workflow) bool {
+	switch wf.RawOn.Kind {
+	case yaml.ScalarNode:
+		return wf.RawOn.Value == "workflow_call"
+	case yaml.SequenceNode:
+		for _, n := range wf.RawOn.Content {
+			if n.Value == "workflow_call" {
+				return true
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i < len(wf.RawOn.Content); i += 2 {
+			if wf.RawOn.Content[i].Value == "workflow_call" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// reusableWorkflowDepth returns how many reusable workflows the job is nested in
+func reusableWorkflowDepth(job *actions_model.ActionRunJob, jobs []*actions_model.ActionRunJob) int {
+	idToJob := make(map[int64]*actions_model.ActionRunJob, len(jobs))
+	for _, j := range jobs {
+		idToJob[j.ID] = j
+	}
+	depth := 0
+	for parent := idToJob[job.ParentJobID]; parent != nil; parent = idToJob[parent.ParentJobID] {
+		depth++
+	}
+	return depth
+}
+
+// expandReusableWorkflow inserts the jobs of the reusable workflow called by the caller job.
+// The inputs of the reusable workflow are evaluated and passed to the called jobs by the environment variables,
+// and the raw expressions of the outputs are stored in the caller job until the called jobs are done.
+func expandReusableWorkflow(ctx context.Context, run *actions_model.ActionRun, caller *actions_model.ActionRunJob, wfJob *jobparser.Job, interpreter exprparser.Interpreter, jobs []*actions_model.ActionRunJob, vars map[string]string) ([]*actions_model.ActionRunJob, error) {
+	if reusableWorkflowDepth(caller, jobs) >= actions_module.ReusableWorkflowMaxDepth {
+		return nil, fmt.Errorf("reusable workflows can be nested at most %d levels", actions_module.ReusableWorkflowMaxDepth)
+	}
+
+	content, callConfig, err := loadReusableWorkflow(ctx, run, wfJob)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluator := jobparser.NewExpressionEvaluator(interpreter)
+	inputs := make(map[string]string, len(callConfig.Inputs))
+	for name, input := range callConfig.Inputs {
+		value, ok := wfJob.With[name]
+		if !ok {
+			if input.Required {
+				return nil, fmt.Errorf("input %q of workflow %q is required", name, wfJob.Uses)
+			}
+			inputs[name] = evaluator.Interpolate(input.Default)
+			continue
+		}
+		if s, ok := value.(string); ok {
+			inputs[name] = evaluator.Interpolate(s)
+		} else {
+			inputs[name] = fmt.Sprint(value)
+		}
+	}
+
+	inputsEnv := make(map[string]string, len(inputs))
+	for name, value := range inputs {
+		inputsEnv[inputEnvPrefix+strings.ToUpper(name)] = value
+	}
+
+	giteaCtx := GenerateGiteaContext(run, nil)
+	singleWorkflows, err := jobparser.Parse(content, jobparser.WithVars(vars), jobparser.WithGitContext(giteaCtx.ToGitHubContext()))
+	if err != nil {
+		return nil, fmt.Errorf("parse workflow %q: %w", wfJob.Uses, err)
+	}
+
+	rawEnvironments, err := readRawEnvironments(content)
+	if err != nil {
+		return nil, fmt.Errorf("parse workflow %q: %w", wfJob.Uses, err)
+	}
+
+	rawMatrices, err := prepareDynamicMatrices(content, singleWorkflows)
+	if err != nil {
+		return nil, fmt.Errorf("parse workflow %q: %w", wfJob.Uses, err)
+	}
+
+	children := make([]*actions_model.ActionRunJob, 0, len(singleWorkflows))
+	for _, v := range singleWorkflows {
+		id, job := v.Job()
+		needs := job.Needs()
+		if err := addJobEnv(job, inputsEnv); err != nil {
+			return nil, err
+		}
+		if err := v.SetJob(id, job.EraseNeeds()); err != nil {
+			return nil, err
+		}
+		payload, _ := v.Marshal()
+
+		child := &actions_model.ActionRunJob{
+			RunID:              run.ID,
+			RepoID:             run.RepoID,
+			OwnerID:            run.OwnerID,
+			CommitSHA:          run.CommitSHA,
+			IsForkPullRequest:  run.IsForkPullRequest,
+			Name:               util.EllipsisDisplayString(caller.Name+" / "+job.Name, 255),
+			WorkflowPayload:    payload,
+			JobID:              id,
+			Needs:              needs,
+			RunsOn:             job.RunsOn(),
+			Status:             actions_model.StatusBlocked,
+			IsReusableWorkflow: actions_module.IsReusableWorkflowUses(job.Uses),
+			ParentJobID:        caller.ID,
+			RawEnvironment:     rawEnvironments[id],
+			RawMatrix:          rawMatrices[id],
+			MaxParallel:        getMaxParallel(job),
+		}
+		if job.RawConcurrency != nil {
+			rawConcurrency, err := yaml.Marshal(job.RawConcurrency)
+			if err != nil {
+				return nil, fmt.Errorf("marshal raw concurrency: %w", err)
+			}
+			child.RawConcurrency = string(rawConcurrency)
+		}
+		if err := db.Insert(ctx, child); err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+
+	setRawReusableWorkflowOutputs(caller, callConfig)
+	return children, nil
+}
+
+// loadReusableWorkflow loads the content and the `workflow_call` config of the reusable workflow called by a job
+func loadReusableWorkflow(ctx context.Context, run *actions_model.ActionRun, wfJob *jobparser.Job) ([]byte, *act_model.WorkflowCall, error) {
+	ref, err := actions_module.ParseReusableWorkflowUses(wfJob.Uses)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := run.LoadAttributes(ctx); err != nil {
+		return nil, nil, err
+	}
+	content, err := loadReusableWorkflowContent(ctx, run, ref)
+	if err != nil {
+		return nil, nil, err
+	}
+	wf, err := act_model.ReadWorkflow(bytes.NewReader(content))
+	if err != nil {
+		return nil, nil, fmt.Errorf("read workflow %q: %w", wfJob.Uses, err)
+	}
+	if !isWorkflowCallable(wf) {
+		return nil, nil, fmt.Errorf("workflow %q is not triggered by workflow_call", wfJob.Uses)
+	}
+	return content, wf.WorkflowCallConfig(), nil
+}
+
+// setRawReusableWorkflowOutputs stores the raw expressions of the outputs in the caller job,
+// they will be evaluated after the called jobs are done.
+func setRawReusableWorkflowOutputs(caller *actions_model.ActionRunJob, callConfig *act_model.WorkflowCall) {
+	caller.Outputs = make(map[string]string, len(callConfig.Outputs))
+	for name, output := range callConfig.Outputs {
+		caller.Outputs[name] = output.Value
+	}
+}
+
+// addJobEnv adds the environment variables to a job, the existing ones with the same names are overridden
+func addJobEnv(job *jobparser.Job, env map[string]string) error {
+	jobEnv := map[string]string{}
+	if job.Env.Kind != 0 {
+		if err := job.Env.Decode(&jobEnv); err != nil {
+			return fmt.Errorf("decode env: %w", err)
+		}
+	}
+	for k, v := range env {
+		jobEnv[k] = v
+	}
+	return job.Env.Encode(jobEnv)
+}
+
+// evaluateReusableWorkflowOutputs evaluates the outputs of a reusable workflow after the called jobs are done
+func evaluateReusableWorkflowOutputs(ctx context.Context, run *actions_model.ActionRun, caller *actions_model.ActionRunJob, children []*actions_model.ActionRunJob, vars map[string]string) (map[string]string, error) {
+	if len(caller.Outputs) == 0 {
+		return nil, nil
+	}
+
+	jobOutputs := make(map[string]*act_model.WorkflowCallResult, len(children))
+	for _, child := range children {
+		outputs := child.Outputs
+		if !child.IsReusableWorkflow && child.TaskID != 0 {
+			got, err := actions_model.FindTaskOutputByTaskID(ctx, child.TaskID)
+			if err != nil {
+				return nil, fmt.Errorf("FindTaskOutputByTaskID: %w", err)
+			}
+			outputs = make(map[string]string, len(got))
+			for _, v := range got {
+				outputs[v.OutputKey] = v.OutputValue
+			}
+		}
+		if result, ok := jobOutputs[child.JobID]; ok {
+			result.Outputs = mergeTwoOutputs(outputs, result.Outputs)
+		} else {
+			jobOutputs[child.JobID] = &act_model.WorkflowCallResult{Outputs: outputs}
+		}
+	}
+
+	inputs, err := getJobInputs(run, children[0])
+	if err != nil {
+		return nil, err
+	}
+	giteaCtx := GenerateGiteaContext(run, caller)
+	evaluator := jobparser.NewExpressionEvaluator(exprparser.NewInterpeter(&exprparser.EvaluationEnvironment{
+		Github: giteaCtx.ToGitHubContext(),
+		Jobs:   &jobOutputs,
+		Inputs: inputs,
+		Vars:   vars,
+	}, exprparser.Config{}))
+
+	outputs := make(map[string]string, len(caller.Outputs))
+	for name, value := range caller.Outputs {
+		outputs[name] = evaluator.Interpolate(value)
+	}
+	return outputs, nil
+}
+
+// getSecretsOfReusableWorkflowJob returns the secrets available to a job of a reusable workflow.
+// The caller job passes the secrets by `secrets: inherit` or a mapping of the secrets,
+// GITHUB_TOKEN and GITEA_TOKEN are always available.
+// See https://docs.github.com/en/actions/writing-workflows/workflow-syntax-for-github-actions#jobsjob_idsecrets
+func getSecretsOfReusableWorkflowJob(ctx context.Context, job *actions_model.ActionRunJob, secrets map[string]string) (map[string]string, error) {
+	if job.ParentJobID == 0 {
+		return secrets, nil
+	}
+	caller, err := actions_model.GetRunJobByID(ctx, job.ParentJobID)
+	if err != nil {
+		return nil, fmt.Errorf("GetRunJobByID: %w", err)
+	}
+	// the caller may be in a reusable workflow too
+	callerSecrets, err := getSecretsOfReusableWorkflowJob(ctx, caller, secrets)
+	if err != nil {
+		return nil, err
+	}
+	_, wfJob, err := parseSingleWorkflowJob(caller)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := map[string]string{
+		"GITHUB_TOKEN": secrets["GITHUB_TOKEN"],
+		"GITEA_TOKEN":  secrets["GITEA_TOKEN"],
+	}
+	switch wfJob.RawSecrets.Kind {
+	case yaml.ScalarNode:
+		if wfJob.RawSecrets.Value == "inherit" {
+			for k, v := range callerSecrets {
+				ret[k] = v
+			}
+		}
+	case yaml.MappingNode:
+		var mapping map[string]string
+		if err := wfJob.RawSecrets.Decode(&mapping); err != nil {
+			return nil, fmt.Errorf("decode secrets: %w", err)
+		}
+		evaluator := jobparser.NewExpressionEvaluator(exprparser.NewInterpeter(&exprparser.EvaluationEnvironment{
+			Secrets: callerSecrets,
+		}, exprparser.Config{}))
+		for k, v := range mapping {
+			ret[k] = evaluator.Interpolate(v)
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckReusableWorkflowAccess(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	user4 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+	publicRepo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	privateRepo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 2})
+	callingRepo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 16})
+
+	cases := []struct {
+		name        string
+		run         *actions_model.ActionRun
+		called      *repo_model.Repository
+		expectedErr error
+	}{
+		{
+			name:   "private workflow of the same owner",
+			run:    &actions_model.ActionRun{Repo: callingRepo, TriggerUser: user2},
+			called: privateRepo,
+		},
+		{
+			name:        "private workflow of the same owner unreadable by the trigger user",
+			run:         &actions_model.ActionRun{Repo: callingRepo, TriggerUser: user4},
+			called:      privateRepo,
+			expectedErr: util.ErrPermissionDenied,
+		},
+		{
+			name:        "private workflow used by a public repository",
+			run:         &actions_model.ActionRun{Repo: publicRepo, TriggerUser: user2},
+			called:      privateRepo,
+			expectedErr: util.ErrPermissionDenied,
+		},
+		{
+			name:   "public workflow",
+			run:    &actions_model.ActionRun{Repo: callingRepo, TriggerUser: user4},
+			called: publicRepo,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := checkReusableWorkflowAccess(t.Context(), c.run, c.called)
+			if c.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, c.expectedErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+	notify_service "code.gitea.io/gitea/services/notify"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"gopkg.in/yaml.v3"
+)
+
+// PrepareRunAndInsert prepares a run and inserts it into the database.
+// It parses the workflow content, evaluates the run title and the workflow-level concurrency,
+// then inserts the run and its jobs and notifies their statuses.
+func PrepareRunAndInsert(ctx context.Context, content []byte, run *actions_model.ActionRun) error {
+	if err := run.LoadAttributes(ctx); err != nil {
+		return fmt.Errorf("LoadAttributes: %w", err)
+	}
+
+	vars, err := actions_model.GetVariablesOfRun(ctx, run)
+	if err != nil {
+		return fmt.Errorf("GetVariablesOfRun: %w", err)
+	}
+
+	giteaCtx := GenerateGiteaContext(run, nil)
+
+	jobs, err := jobparser.Parse(content, jobparser.WithVars(vars), jobparser.WithGitContext(giteaCtx.ToGitHubContext()))
+	if err != nil {
+		return fmt.Errorf("jobparser.Parse: %w", err)
+	}
+
+	if len(jobs) > 0 && jobs[0].RunName != "" {
+		run.Title = jobs[0].RunName
+	}
+
+	wfRawConcurrency, err := jobparser.ReadWorkflowRawConcurrency(content)
+	if err != nil {
+		return fmt.Errorf("ReadWorkflowRawConcurrency: %w", err)
+	}
+	if err := EvaluateRunConcurrencyFillModel(ctx, run, wfRawConcurrency, vars); err != nil {
+		return fmt.Errorf("EvaluateRunConcurrencyFillModel: %w", err)
+	}
+
+	rawEnvironments, err := readRawEnvironments(content)
+	if err != nil {
+		return fmt.Errorf("readRawEnvironments: %w", err)
+	}
+
+	rawMatrices, err := prepareDynamicMatrices(content, jobs)
+	if err != nil {
+		return fmt.Errorf("prepareDynamicMatrices: %w", err)
+	}
+
+	if err := InsertRun(ctx, run, jobs, vars, rawEnvironments, rawMatrices); err != nil {
+		return fmt.Errorf("InsertRun: %w", err)
+	}
+
+	allJobs, err := db.Find[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{RunID: run.ID})
+	if err != nil {
+		log.Error("FindRunJobs: %v", err)
+		return nil
+	}
+
+	// don't create commit status for cron job
+	if !run.IsSchedule() {
+		CreateCommitStatus(ctx, allJobs...)
+	}
+	notify_service.WorkflowRunStatusUpdate(ctx, run.Repo, run.TriggerUser, run)
+	for _, job := range allJobs {
+		notify_service.WorkflowJobStatusUpdate(ctx, run.Repo, run.TriggerUser, job, nil)
+	}
+
+	return nil
+}
+
+// InsertRun inserts a run and its jobs.
+// The title will be cut off at 255 characters if it's longer than 255 characters.
+// The jobs will be blocked if the concurrency group of the run or the job has a job in progress,
+// and the pending runs and jobs of the same concurrency groups will be cancelled.
+// The jobs targeting environments are blocked until the job emitter checks the protection rules of the environments,
+// and the jobs with dynamic matrices or max-parallel limits are blocked until the job emitter expands or starts them.
+// If the owner has exceeded the usage quota, the run is marked, and its jobs are cancelled without running if the quota is hard.
+func InsertRun(ctx context.Context, run *actions_model.ActionRun, jobs []*jobparser.SingleWorkflow, vars map[string]string, rawEnvironments, rawMatrices map[string]string) error {
+	quotaPolicy, err := checkOwnerUsageQuota(ctx, run.OwnerID)
+	if err != nil {
+		return fmt.Errorf("checkOwnerUsageQuota: %w", err)
+	}
+	run.QuotaExceeded = quotaPolicy != ""
+	blockedByQuota := quotaPolicy == actions_model.UsageQuotaPolicyHard
+
+	var cancelledJobs []*actions_model.ActionRunJob
+	var shouldEmit bool
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		var blockedByRunConcurrency bool
+		if !blockedByQuota {
+			cancelled, err := actions_model.CancelPreviousJobsByRunConcurrency(ctx, run)
+			cancelledJobs = append(cancelledJobs, cancelled...)
+			if err != nil {
+				return fmt.Errorf("CancelPreviousJobsByRunConcurrency: %w", err)
+			}
+			if blockedByRunConcurrency, err = actions_model.ShouldBlockRunByConcurrency(ctx, run); err != nil {
+				return fmt.Errorf("ShouldBlockRunByConcurrency: %w", err)
+			}
+		}
+
+		index, err := db.GetNextResourceIndex(ctx, "action_run_index", run.RepoID)
+		if err != nil {
+			return err
+		}
+		run.Index = index
+		run.Title = util.EllipsisDisplayString(run.Title, 255)
+		run.Attempt = 1
+		if blockedByRunConcurrency {
+			run.Status = actions_model.StatusBlocked
+		}
+
+		if err := db.Insert(ctx, run); err != nil {
+			return err
+		}
+
+		if err := run.LoadRepo(ctx); err != nil {
+			return err
+		}
+
+		if err := actions_model.UpdateRepoRunsNumbers(ctx, run.Repo); err != nil {
+			return err
+		}
+
+		runJobs := make([]*actions_model.ActionRunJob, 0, len(jobs))
+		var hasWaiting bool
+		for _, v := range jobs {
+			id, job := v.Job()
+			needs := job.Needs()
+			if err := v.SetJob(id, job.EraseNeeds()); err != nil {
+				return err
+			}
+			payload, _ := v.Marshal()
+
+			job.Name = util.EllipsisDisplayString(job.Name, 255)
+			runJob := &actions_model.ActionRunJob{
+				RunID:              run.ID,
+				RepoID:             run.RepoID,
+				OwnerID:            run.OwnerID,
+				CommitSHA:          run.CommitSHA,
+				IsForkPullRequest:  run.IsForkPullRequest,
+				Name:               job.Name,
+				WorkflowPayload:    payload,
+				JobID:              id,
+				Needs:              needs,
+				RunsOn:             job.RunsOn(),
+				Status:             actions_model.StatusWaiting,
+				IsReusableWorkflow: actions_module.IsReusableWorkflowUses(job.Uses),
+				RawEnvironment:     rawEnvironments[id],
+				RawMatrix:          rawMatrices[id],
+				MaxParallel:        getMaxParallel(job),
+			}
+			if job.RawConcurrency != nil {
+				rawConcurrency, err := yaml.Marshal(job.RawConcurrency)
+				if err != nil {
+					return fmt.Errorf("marshal raw concurrency: %w", err)
+				}
+				runJob.RawConcurrency = string(rawConcurrency)
+			}
+
+			if blockedByQuota {
+				runJob.Status = actions_model.StatusCancelled
+				runJob.Stopped = timeutil.TimeStampNow()
+				if err := db.Insert(ctx, runJob); err != nil {
+					return err
+				}
+				runJobs = append(runJobs, runJob)
+				continue
+			}
+
+			startedByEmitter := runJob.IsStartedByJobEmitter()
+			shouldBlock := len(needs) > 0 || run.NeedApproval || blockedByRunConcurrency || startedByEmitter
+			// A job which is ready to run evaluates its concurrency now,
+			// otherwise it will be evaluated by the job emitter once its needs have been resolved.
+			if !shouldBlock {
+				if err := EvaluateJobConcurrencyFillModel(ctx, run, runJob, vars); err != nil {
+					return fmt.Errorf("EvaluateJobConcurrencyFillModel: %w", err)
+				}
+				cancelled, err := actions_model.CancelPreviousJobsByJobConcurrency(ctx, runJob)
+				cancelledJobs = append(cancelledJobs, cancelled...)
+				if err != nil {
+					return fmt.Errorf("CancelPreviousJobsByJobConcurrency: %w", err)
+				}
+				if shouldBlock, err = actions_model.ShouldBlockJobByConcurrency(ctx, runJob); err != nil {
+					return fmt.Errorf("ShouldBlockJobByConcurrency: %w", err)
+				}
+			}
+			shouldEmit = shouldEmit || startedByEmitter
+			if shouldBlock {
+				runJob.Status = actions_model.StatusBlocked
+			} else {
+				hasWaiting = true
+			}
+
+			// insert the jobs one by one, so the following jobs can find the previous ones of the same concurrency group
+			if err := db.Insert(ctx, runJob); err != nil {
+				return err
+			}
+			runJobs = append(runJobs, runJob)
+		}
+
+		if status := actions_model.AggregateJobStatus(runJobs); len(runJobs) > 0 && status != run.Status {
+			run.Status = status
+			cols := []string{"status"}
+			if blockedByQuota {
+				run.Stopped = timeutil.TimeStampNow()
+				cols = append(cols, "stopped")
+			}
+			if err := actions_model.UpdateRun(ctx, run, cols...); err != nil {
+				return fmt.Errorf("update run %d: %w", run.ID, err)
+			}
+		}
+
+		// if there is a job in the waiting status, increase tasks version.
+		if hasWaiting {
+			if err := actions_model.IncreaseTaskVersion(ctx, run.OwnerID, run.RepoID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	notifyWorkflowJobStatusUpdate(ctx, cancelledJobs)
+	if shouldEmit {
+		// let the job emitter start the jobs which have to be checked before running
+		if err := EmitJobsIfReady(run.ID); err != nil {
+			log.Error("Emit ready jobs of run %d: %v", run.ID, err)
+		}
+	}
+	return nil
+}
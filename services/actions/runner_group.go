@@ -0,0 +1,60 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// CreateRunnerGroup creates a runner group after checking its selected repositories
+func CreateRunnerGroup(ctx context.Context, group *actions_model.ActionRunnerGroup) error {
+	if err := checkRunnerGroupRepos(ctx, group); err != nil {
+		return err
+	}
+	return actions_model.InsertRunnerGroup(ctx, group)
+}
+
+// UpdateRunnerGroup updates a runner group after checking its selected repositories
+func UpdateRunnerGroup(ctx context.Context, group *actions_model.ActionRunnerGroup, cols ...string) error {
+	if err := checkRunnerGroupRepos(ctx, group); err != nil {
+		return err
+	}
+	return actions_model.UpdateRunnerGroup(ctx, group, cols...)
+}
+
+// checkRunnerGroupRepos checks the selected repositories exist,
+// and they belong to the owner of the group if it's not an instance-level group
+func checkRunnerGroupRepos(ctx context.Context, group *actions_model.ActionRunnerGroup) error {
+	for _, repoID := range group.RepoIDs {
+		repo, err := repo_model.GetRepositoryByID(ctx, repoID)
+		if err != nil {
+			if errors.Is(err, util.ErrNotExist) {
+				return util.NewInvalidArgumentErrorf("repository %d doesn't exist", repoID)
+			}
+			return err
+		}
+		if group.OwnerID != 0 && repo.OwnerID != group.OwnerID {
+			return util.NewInvalidArgumentErrorf("repository %d doesn't belong to the owner of the runner group", repoID)
+		}
+	}
+	return nil
+}
+
+// SetRunnerGroup adds the runner to the group, or removes it from its group if the group is nil.
+// Only the runners of the owner of the group can be added, and the repository-level runners can't belong to any group.
+func SetRunnerGroup(ctx context.Context, runner *actions_model.ActionRunner, group *actions_model.ActionRunnerGroup) error {
+	runner.GroupID = 0
+	if group != nil {
+		if runner.RepoID != 0 || runner.OwnerID != group.OwnerID {
+			return util.NewInvalidArgumentErrorf("runner %d can't be added to runner group %d", runner.ID, group.ID)
+		}
+		runner.GroupID = group.ID
+	}
+	return actions_model.UpdateRunner(ctx, runner, "group_id")
+}
@@ -13,11 +13,9 @@ import (
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unit"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/timeutil"
 	webhook_module "code.gitea.io/gitea/modules/webhook"
-	notify_service "code.gitea.io/gitea/services/notify"
-
-	"github.com/nektos/act/pkg/jobparser"
 )
 
 // StartScheduleTasks start the task
@@ -53,20 +51,6 @@ func startTasks(ctx context.Context) error {
 
 		// Loop through each spec and create a schedule task for it
 		for _, row := range specs {
-			// cancel running jobs if the event is push
-			if row.Schedule.Event == webhook_module.HookEventPush {
-				// cancel running jobs of the same workflow
-				if err := CancelPreviousJobs(
-					ctx,
-					row.RepoID,
-					row.Schedule.Ref,
-					row.Schedule.WorkflowID,
-					webhook_module.HookEventSchedule,
-				); err != nil {
-					log.Error("CancelPreviousJobs: %v", err)
-				}
-			}
-
 			if row.Repo.IsArchived {
 				// Skip if the repo is archived
 				continue
@@ -80,14 +64,6 @@ func startTasks(ctx context.Context) error {
 				}
 				return fmt.Errorf("GetUnit: %w", err)
 			}
-			if cfg.ActionsConfig().IsWorkflowDisabled(row.Schedule.WorkflowID) {
-				continue
-			}
-
-			if err := CreateScheduleTask(ctx, row.Schedule); err != nil {
-				log.Error("CreateScheduleTask: %v", err)
-				return err
-			}
 
 			// Parse the spec
 			schedule, err := row.Parse()
@@ -96,10 +72,35 @@ func startTasks(ctx context.Context) error {
 				return err
 			}
 
-			// Update the spec's next run time and previous run time
-			row.Prev = row.Next
+			if skipReason := getScheduleSkipReason(now, row, cfg.ActionsConfig()); skipReason != "" {
+				row.LastSkipped = row.Next
+				row.LastSkipReason = skipReason
+			} else {
+				// cancel running jobs if the event is push
+				if row.Schedule.Event == webhook_module.HookEventPush {
+					// cancel running jobs of the same workflow
+					if err := CancelPreviousJobs(
+						ctx,
+						row.RepoID,
+						row.Schedule.Ref,
+						row.Schedule.WorkflowID,
+						webhook_module.HookEventSchedule,
+					); err != nil {
+						log.Error("CancelPreviousJobs: %v", err)
+					}
+				}
+
+				if err := CreateScheduleTask(ctx, row.Schedule); err != nil {
+					log.Error("CreateScheduleTask: %v", err)
+					return err
+				}
+				row.Prev = row.Next
+			}
+
+			// Update the spec's next run time and previous run time,
+			// the next run time is always after now, so at most one run is started for the missed times
 			row.Next = timeutil.TimeStamp(schedule.Next(now.Add(1 * time.Minute)).Unix())
-			if err := actions_model.UpdateScheduleSpec(ctx, row, "prev", "next"); err != nil {
+			if err := actions_model.UpdateScheduleSpec(ctx, row, "prev", "next", "last_skipped", "last_skip_reason"); err != nil {
 				log.Error("UpdateScheduleSpec: %v", err)
 				return err
 			}
@@ -114,6 +115,18 @@ func startTasks(ctx context.Context) error {
 	return nil
 }
 
+// getScheduleSkipReason returns why the scheduled run of the spec due at now should be skipped, or empty if it should start
+func getScheduleSkipReason(now time.Time, spec *actions_model.ActionScheduleSpec, cfg *repo_model.ActionsConfig) actions_model.ScheduleSkipReason {
+	if cfg.IsWorkflowDisabled(spec.Schedule.WorkflowID) {
+		return actions_model.ScheduleSkipReasonWorkflowDisabled
+	}
+	// the run is late if Gitea was down or the cron task was paused at the scheduled time
+	if setting.Actions.ScheduleCatchUp.IsNone() && now.Sub(spec.Next.AsTime()) > setting.Actions.ScheduleMissTolerance {
+		return actions_model.ScheduleSkipReasonMissed
+	}
+	return ""
+}
+
 // CreateScheduleTask creates a scheduled task from a cron action schedule.
 // It creates an action run based on the schedule, inserts it into the database, and creates commit statuses for each job.
 func CreateScheduleTask(ctx context.Context, cron *actions_model.ActionSchedule) error {
@@ -133,35 +146,11 @@ func CreateScheduleTask(ctx context.Context, cron *actions_model.ActionSchedule)
 		Status:        actions_model.StatusWaiting,
 	}
 
-	vars, err := actions_model.GetVariablesOfRun(ctx, run)
-	if err != nil {
-		log.Error("GetVariablesOfRun: %v", err)
-		return err
-	}
-
-	// Parse the workflow specification from the cron schedule
-	workflows, err := jobparser.Parse(cron.Content, jobparser.WithVars(vars))
-	if err != nil {
+	// Parse the workflow specification from the cron schedule, and insert the action run and its associated jobs into the database
+	if err := PrepareRunAndInsert(ctx, cron.Content, run); err != nil {
 		return err
 	}
 
-	// Insert the action run and its associated jobs into the database
-	if err := actions_model.InsertRun(ctx, run, workflows); err != nil {
-		return err
-	}
-	allJobs, err := db.Find[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{RunID: run.ID})
-	if err != nil {
-		log.Error("FindRunJobs: %v", err)
-	}
-	err = run.LoadAttributes(ctx)
-	if err != nil {
-		log.Error("LoadAttributes: %v", err)
-	}
-	notify_service.WorkflowRunStatusUpdate(ctx, run.Repo, run.TriggerUser, run)
-	for _, job := range allJobs {
-		notify_service.WorkflowJobStatusUpdate(ctx, run.Repo, run.TriggerUser, job, nil)
-	}
-
 	// Return nil if no errors occurred
 	return nil
 }
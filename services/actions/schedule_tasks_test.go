@@ -0,0 +1,41 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetScheduleSkipReason(t *testing.T) {
+	defer test.MockVariableValue(&setting.Actions.ScheduleMissTolerance, 10*time.Minute)()
+
+	now := time.Now()
+	spec := &actions_model.ActionScheduleSpec{
+		Next:     timeutil.TimeStamp(now.Add(-time.Minute).Unix()),
+		Schedule: &actions_model.ActionSchedule{WorkflowID: "nightly.yml"},
+	}
+	cfg := &repo_model.ActionsConfig{}
+	assert.Empty(t, getScheduleSkipReason(now, spec, cfg))
+
+	// one run is started for the runs missed during a downtime by default
+	spec.Next = timeutil.TimeStamp(now.Add(-time.Hour).Unix())
+	assert.Empty(t, getScheduleSkipReason(now, spec, cfg))
+
+	t.Run("CatchUpNone", func(t *testing.T) {
+		defer test.MockVariableValue(&setting.Actions.ScheduleCatchUp, "none")()
+		assert.Equal(t, actions_model.ScheduleSkipReasonMissed, getScheduleSkipReason(now, spec, cfg))
+	})
+
+	cfg.DisableWorkflow("nightly.yml")
+	assert.Equal(t, actions_model.ScheduleSkipReasonWorkflowDisabled, getScheduleSkipReason(now, spec, cfg))
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+)
+
+// StepSummary is the summary written by a step of a job
+type StepSummary struct {
+	Job      *actions_model.ActionRunJob
+	StepName string
+	*actions_model.ActionTaskSummary
+}
+
+// GetStepSummaries returns the summaries of the current tasks of the jobs, ordered by the jobs and the steps
+func GetStepSummaries(ctx context.Context, jobs []*actions_model.ActionRunJob) ([]*StepSummary, error) {
+	taskIDs := make([]int64, 0, len(jobs))
+	for _, job := range jobs {
+		if job.TaskID > 0 {
+			taskIDs = append(taskIDs, job.TaskID)
+		}
+	}
+	summaries, err := actions_model.FindTaskSummariesByTaskIDs(ctx, taskIDs)
+	if err != nil {
+		return nil, fmt.Errorf("FindTaskSummariesByTaskIDs: %w", err)
+	}
+
+	taskSummaries := make(map[int64][]*actions_model.ActionTaskSummary, len(summaries))
+	for _, summary := range summaries {
+		taskSummaries[summary.TaskID] = append(taskSummaries[summary.TaskID], summary)
+	}
+	ret := make([]*StepSummary, 0, len(summaries))
+	for _, job := range jobs {
+		if len(taskSummaries[job.TaskID]) == 0 {
+			continue
+		}
+		steps, err := actions_model.GetTaskStepsByTaskID(ctx, job.TaskID)
+		if err != nil {
+			return nil, fmt.Errorf("GetTaskStepsByTaskID: %w", err)
+		}
+		stepNames := make(map[int64]string, len(steps))
+		for _, step := range steps {
+			stepNames[step.Index] = step.Name
+		}
+		for _, summary := range taskSummaries[job.TaskID] {
+			ret = append(ret, &StepSummary{
+				Job:               job,
+				StepName:          stepNames[summary.StepIndex],
+				ActionTaskSummary: summary,
+			})
+		}
+	}
+	return ret, nil
+}
@@ -7,9 +7,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 
 	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/perm"
 	secret_model "code.gitea.io/gitea/models/secret"
 	notify_service "code.gitea.io/gitea/services/notify"
 
@@ -63,10 +65,24 @@ func PickTask(ctx context.Context, runner *actions_model.ActionRunner) (*runnerv
 		if err != nil {
 			return fmt.Errorf("GetSecretsOfTask: %w", err)
 		}
+		if secrets, err = getSecretsOfReusableWorkflowJob(ctx, job, secrets); err != nil {
+			return fmt.Errorf("getSecretsOfReusableWorkflowJob: %w", err)
+		}
+		// the secrets of the environment are not passed by the callers of reusable workflows
+		envSecrets, err := secret_model.GetSecretsOfEnvironment(ctx, t)
+		if err != nil {
+			return fmt.Errorf("GetSecretsOfEnvironment: %w", err)
+		}
+		maps.Copy(secrets, envSecrets)
 
-		vars, err := actions_model.GetVariablesOfRun(ctx, t.Job.Run)
+		vars, err := actions_model.GetVariablesOfJob(ctx, t.Job)
 		if err != nil {
-			return fmt.Errorf("GetVariablesOfRun: %w", err)
+			return fmt.Errorf("GetVariablesOfJob: %w", err)
+		}
+		if job.Run.IsDebugEnabled {
+			// the runner enables the debug logging by the same variables as GitHub Actions
+			vars[debugStepLoggingVariable] = "true"
+			vars[debugRunnerLoggingVariable] = "true"
 		}
 
 		needs, err := findTaskNeeds(ctx, job)
@@ -79,9 +95,29 @@ func PickTask(ctx context.Context, runner *actions_model.ActionRunner) (*runnerv
 			return fmt.Errorf("generateTaskContext: %w", err)
 		}
 
+		// the permissions are decided when the task is created, so changing the defaults won't affect the running tasks
+		if t.TokenPermissions, err = GetJobTokenPermissions(ctx, job); err != nil {
+			return fmt.Errorf("GetJobTokenPermissions: %w", err)
+		}
+		if err := actions_model.UpdateTask(ctx, t, "token_permissions"); err != nil {
+			return fmt.Errorf("UpdateTask: %w", err)
+		}
+
+		workflowPayload := t.Job.WorkflowPayload
+		if t.TokenPermissions[actions_model.TokenScopeIDToken] >= perm.AccessModeWrite {
+			requestToken, err := CreateAuthorizationToken(t.ID, t.Job.RunID, t.JobID)
+			if err != nil {
+				return fmt.Errorf("CreateAuthorizationToken: %w", err)
+			}
+			// the token is only sent to the runner, it's not stored in the job
+			if workflowPayload, err = injectIDTokenRequestEnv(workflowPayload, requestToken); err != nil {
+				return fmt.Errorf("injectIDTokenRequestEnv: %w", err)
+			}
+		}
+
 		task = &runnerv1.Task{
 			Id:              t.ID,
-			WorkflowPayload: t.Job.WorkflowPayload,
+			WorkflowPayload: workflowPayload,
 			Context:         taskContext,
 			Secrets:         secrets,
 			Vars:            vars,
@@ -0,0 +1,175 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/perm"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/util"
+
+	"gopkg.in/yaml.v3"
+)
+
+// GetOwnerTokenPermissionMode returns the default permission mode of the Actions tokens of the repositories owned by the user or the organization,
+// it's permissive if the owner doesn't set it.
+func GetOwnerTokenPermissionMode(ctx context.Context, ownerID int64) (repo_model.ActionsTokenPermissionMode, error) {
+	value, err := user_model.GetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsTokenPermissionMode)
+	if err != nil {
+		return "", err
+	}
+	if mode := repo_model.ActionsTokenPermissionMode(value); mode != "" && mode.IsValid() {
+		return mode, nil
+	}
+	return repo_model.ActionsTokenPermissionModePermissive, nil
+}
+
+// SetOwnerTokenPermissionMode sets the default permission mode of the Actions tokens of the repositories owned by the user or the organization
+func SetOwnerTokenPermissionMode(ctx context.Context, ownerID int64, mode repo_model.ActionsTokenPermissionMode) error {
+	if mode == "" || !mode.IsValid() {
+		return util.NewInvalidArgumentErrorf("invalid token permission mode %q", mode)
+	}
+	return user_model.SetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsTokenPermissionMode, string(mode))
+}
+
+// GetRepoTokenPermissionMode returns the default permission mode of the Actions tokens of the repository,
+// it follows the owner of the repository if the repository doesn't set it.
+func GetRepoTokenPermissionMode(ctx context.Context, repo *repo_model.Repository) (repo_model.ActionsTokenPermissionMode, error) {
+	cfgUnit, err := repo.GetUnit(ctx, unit.TypeActions)
+	if err != nil && !repo_model.IsErrUnitTypeNotExist(err) {
+		return "", err
+	}
+	if cfgUnit != nil {
+		if mode := cfgUnit.ActionsConfig().TokenPermissionMode; mode != "" {
+			return mode, nil
+		}
+	}
+	return GetOwnerTokenPermissionMode(ctx, repo.OwnerID)
+}
+
+// SetRepoTokenPermissionMode sets the default permission mode of the Actions tokens of the repository,
+// the empty mode means following the owner of the repository.
+func SetRepoTokenPermissionMode(ctx context.Context, repo *repo_model.Repository, mode repo_model.ActionsTokenPermissionMode) error {
+	if !mode.IsValid() {
+		return util.NewInvalidArgumentErrorf("invalid token permission mode %q", mode)
+	}
+	cfgUnit, err := repo.GetUnit(ctx, unit.TypeActions)
+	if err != nil {
+		return err
+	}
+	cfgUnit.ActionsConfig().TokenPermissionMode = mode
+	return repo_model.UpdateRepoUnit(ctx, cfgUnit)
+}
+
+// getDefaultTokenPermissions returns the permissions of the jobs which don't declare the `permissions`
+func getDefaultTokenPermissions(ctx context.Context, repo *repo_model.Repository) (actions_model.TokenPermissions, error) {
+	mode, err := GetRepoTokenPermissionMode(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	if mode == repo_model.ActionsTokenPermissionModeRestricted {
+		return actions_model.NewRepoTokenPermissions(perm.AccessModeRead), nil
+	}
+	return actions_model.NewRepoTokenPermissions(perm.AccessModeWrite), nil
+}
+
+// GetJobTokenPermissions returns the permissions of the token of the job, which are decided by the first of:
+// the `permissions` of the job, the `permissions` of the workflow, the permissions of the caller if the job is in a reusable workflow,
+// and the default permissions of the repository.
+// The jobs in the reusable workflows can't have more permissions than the callers,
+// and the runs triggered by the pull requests from forks only have the read access.
+func GetJobTokenPermissions(ctx context.Context, job *actions_model.ActionRunJob) (actions_model.TokenPermissions, error) {
+	if err := job.LoadRun(ctx); err != nil {
+		return nil, err
+	}
+	if err := job.Run.LoadRepo(ctx); err != nil {
+		return nil, err
+	}
+
+	perms, err := getJobTokenPermissions(ctx, job.Run, job)
+	if err != nil {
+		return nil, err
+	}
+	if job.Run.IsForkPullRequest && job.Run.TriggerEvent != actions_module.GithubEventPullRequestTarget {
+		perms = perms.LimitTo(perm.AccessModeRead)
+	}
+	return perms, nil
+}
+
+func getJobTokenPermissions(ctx context.Context, run *actions_model.ActionRun, job *actions_model.ActionRunJob) (actions_model.TokenPermissions, error) {
+	singleWorkflow, wfJob, err := parseSingleWorkflowJob(job)
+	if err != nil {
+		return nil, err
+	}
+	rawPermissions := wfJob.RawPermissions
+	if rawPermissions.Kind == 0 {
+		rawPermissions = singleWorkflow.RawPermissions
+	}
+	perms, err := parseTokenPermissions(&rawPermissions)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.ParentJobID == 0 {
+		if perms != nil {
+			return perms, nil
+		}
+		return getDefaultTokenPermissions(ctx, run.Repo)
+	}
+
+	caller, err := actions_model.GetRunJobByID(ctx, job.ParentJobID)
+	if err != nil {
+		return nil, err
+	}
+	callerPerms, err := getJobTokenPermissions(ctx, run, caller)
+	if err != nil {
+		return nil, err
+	}
+	if perms == nil {
+		return callerPerms, nil
+	}
+	return perms.Intersect(callerPerms), nil
+}
+
+// parseTokenPermissions parses the `permissions` of a workflow or a job, it returns nil if the permissions are not declared.
+// The unknown scopes are ignored, and the invalid access levels are treated as "none".
+// See https://docs.github.com/en/actions/writing-workflows/workflow-syntax-for-github-actions#permissions
+func parseTokenPermissions(rawPermissions *yaml.Node) (actions_model.TokenPermissions, error) {
+	switch rawPermissions.Kind {
+	case yaml.ScalarNode:
+		switch rawPermissions.Value {
+		case "read-all":
+			return actions_model.NewRepoTokenPermissions(perm.AccessModeRead), nil
+		case "write-all":
+			perms := actions_model.NewRepoTokenPermissions(perm.AccessModeWrite)
+			perms[actions_model.TokenScopeIDToken] = perm.AccessModeWrite
+			perms[actions_model.TokenScopeAttestations] = perm.AccessModeWrite
+			return perms, nil
+		}
+		return actions_model.TokenPermissions{}, nil
+	case yaml.MappingNode:
+		var permissions map[string]string
+		if err := rawPermissions.Decode(&permissions); err != nil {
+			return nil, fmt.Errorf("decode permissions: %w", err)
+		}
+		perms := make(actions_model.TokenPermissions, len(permissions))
+		for key, value := range permissions {
+			scope := actions_model.TokenScope(key)
+			if !scope.IsValid() {
+				continue
+			}
+			if mode := perm.ParseAccessMode(value, perm.AccessModeRead, perm.AccessModeWrite); mode > perm.AccessModeNone {
+				perms[scope] = mode
+			}
+		}
+		return perms, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/perm"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseTokenPermissions(t *testing.T) {
+	writeAll := actions_model.NewRepoTokenPermissions(perm.AccessModeWrite)
+	writeAll[actions_model.TokenScopeIDToken] = perm.AccessModeWrite
+	writeAll[actions_model.TokenScopeAttestations] = perm.AccessModeWrite
+
+	testCases := []struct {
+		name     string
+		yaml     string
+		expected actions_model.TokenPermissions
+	}{
+		{"not declared", `name: test`, nil},
+		{"read-all", `permissions: read-all`, actions_model.NewRepoTokenPermissions(perm.AccessModeRead)},
+		{"write-all", `permissions: write-all`, writeAll},
+		{"empty", `permissions: {}`, actions_model.TokenPermissions{}},
+		{"invalid", `permissions: invalid`, actions_model.TokenPermissions{}},
+		{
+			"scopes",
+			"permissions:\n  contents: read\n  issues: write\n  pull-requests: none\n  id-token: write\n  attestations: write\n  unknown: write",
+			actions_model.TokenPermissions{
+				actions_model.TokenScopeContents:     perm.AccessModeRead,
+				actions_model.TokenScopeIssues:       perm.AccessModeWrite,
+				actions_model.TokenScopeIDToken:      perm.AccessModeWrite,
+				actions_model.TokenScopeAttestations: perm.AccessModeWrite,
+			},
+		},
+		{"invalid access", "permissions:\n  contents: admin", actions_model.TokenPermissions{}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var doc struct {
+				Permissions yaml.Node `yaml:"permissions"`
+			}
+			require.NoError(t, yaml.Unmarshal([]byte(tc.yaml), &doc))
+			perms, err := parseTokenPermissions(&doc.Permissions)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, perms)
+		})
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// UsageMonthFormat is the format of the months of the usage reports
+const UsageMonthFormat = "2006-01"
+
+// UsageQuota is the monthly quota of the running time of the jobs in the repositories owned by a user or an organization
+type UsageQuota struct {
+	Minutes int64 // 0 means unlimited
+	Policy  actions_model.UsageQuotaPolicy
+}
+
+// UsageReport is the running time of the jobs in a month, by repository and runner labels
+type UsageReport struct {
+	Month        time.Time
+	Usages       []*Usage
+	TotalSeconds int64
+	TotalMinutes int64
+}
+
+// Usage is the running time of the jobs of a repository which run on the same labels
+type Usage struct {
+	Repo    *repo_model.Repository
+	Labels  []string
+	Jobs    int64
+	Seconds int64
+	Minutes int64
+}
+
+// ParseUsageMonth parses the month of a usage report, the empty string means the current month
+func ParseUsageMonth(value string) (time.Time, error) {
+	if value == "" {
+		now := time.Now().UTC()
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC), nil
+	}
+	month, err := time.ParseInLocation(UsageMonthFormat, value, time.UTC)
+	if err != nil {
+		return time.Time{}, util.NewInvalidArgumentErrorf("invalid month %q, it should be like %q", value, UsageMonthFormat)
+	}
+	return month, nil
+}
+
+// usageMonthRange returns the range of the timestamps of the month, the months of the usages are in UTC
+func usageMonthRange(month time.Time) (start, end timeutil.TimeStamp) {
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return timeutil.TimeStamp(first.Unix()), timeutil.TimeStamp(first.AddDate(0, 1, 0).Unix())
+}
+
+// secondsToMinutes rounds up the running time to minutes
+func secondsToMinutes(seconds int64) int64 {
+	return (seconds + 59) / 60
+}
+
+// GetOwnerUsageQuota returns the usage quota of the user or the organization
+func GetOwnerUsageQuota(ctx context.Context, ownerID int64) (*UsageQuota, error) {
+	settings, err := user_model.GetSettings(ctx, ownerID, []string{
+		user_model.SettingsKeyActionsUsageQuotaMinutes,
+		user_model.SettingsKeyActionsUsageQuotaPolicy,
+	})
+	if err != nil {
+		return nil, err
+	}
+	quota := &UsageQuota{Policy: actions_model.UsageQuotaPolicySoft}
+	if s, ok := settings[user_model.SettingsKeyActionsUsageQuotaMinutes]; ok {
+		if minutes, err := strconv.ParseInt(s.SettingValue, 10, 64); err == nil && minutes > 0 {
+			quota.Minutes = minutes
+		}
+	}
+	if s, ok := settings[user_model.SettingsKeyActionsUsageQuotaPolicy]; ok {
+		if policy := actions_model.UsageQuotaPolicy(s.SettingValue); policy.IsValid() {
+			quota.Policy = policy
+		}
+	}
+	return quota, nil
+}
+
+// SetOwnerUsageQuota sets the usage quota of the user or the organization, 0 minutes removes the quota.
+// The policy is soft if it's empty.
+func SetOwnerUsageQuota(ctx context.Context, ownerID int64, quota *UsageQuota) error {
+	if quota.Minutes < 0 {
+		return util.NewInvalidArgumentErrorf("the quota minutes can't be negative")
+	}
+	if quota.Policy == "" {
+		quota.Policy = actions_model.UsageQuotaPolicySoft
+	}
+	if !quota.Policy.IsValid() {
+		return util.NewInvalidArgumentErrorf("invalid usage quota policy %q", quota.Policy)
+	}
+	if quota.Minutes == 0 {
+		if err := user_model.DeleteUserSetting(ctx, ownerID, user_model.SettingsKeyActionsUsageQuotaMinutes); err != nil {
+			return err
+		}
+		return user_model.DeleteUserSetting(ctx, ownerID, user_model.SettingsKeyActionsUsageQuotaPolicy)
+	}
+	if err := user_model.SetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsUsageQuotaMinutes, strconv.FormatInt(quota.Minutes, 10)); err != nil {
+		return err
+	}
+	return user_model.SetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsUsageQuotaPolicy, string(quota.Policy))
+}
+
+// GetOwnerUsedMinutes returns the running time in minutes of the jobs in the repositories owned by the user or the organization in the month
+func GetOwnerUsedMinutes(ctx context.Context, ownerID int64, month time.Time) (int64, error) {
+	start, end := usageMonthRange(month)
+	seconds, err := actions_model.SumTaskSeconds(ctx, actions_model.FindTaskUsagesOptions{
+		OwnerID:       ownerID,
+		StoppedAfter:  start,
+		StoppedBefore: end,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return secondsToMinutes(seconds), nil
+}
+
+// checkOwnerUsageQuota returns the policy of the usage quota if the owner has used up the quota of the current month,
+// otherwise it returns an empty policy.
+func checkOwnerUsageQuota(ctx context.Context, ownerID int64) (actions_model.UsageQuotaPolicy, error) {
+	quota, err := GetOwnerUsageQuota(ctx, ownerID)
+	if err != nil {
+		return "", err
+	}
+	if quota.Minutes == 0 {
+		return "", nil
+	}
+	month, _ := ParseUsageMonth("")
+	used, err := GetOwnerUsedMinutes(ctx, ownerID, month)
+	if err != nil {
+		return "", err
+	}
+	if used < quota.Minutes {
+		return "", nil
+	}
+	log.Debug("owner %d has used %d minutes of the usage quota %d", ownerID, used, quota.Minutes)
+	return quota.Policy, nil
+}
+
+// GetUsageReport returns the running time of the jobs in the month, of the repository if repoID is not 0,
+// otherwise of the repositories owned by the user or the organization.
+func GetUsageReport(ctx context.Context, ownerID, repoID int64, month time.Time) (*UsageReport, error) {
+	start, end := usageMonthRange(month)
+	taskUsages, err := actions_model.FindTaskUsages(ctx, actions_model.FindTaskUsagesOptions{
+		OwnerID:       ownerID,
+		RepoID:        repoID,
+		StoppedAfter:  start,
+		StoppedBefore: end,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	repoIDs := make([]int64, 0, len(taskUsages))
+	for _, taskUsage := range taskUsages {
+		repoIDs = append(repoIDs, taskUsage.RepoID)
+	}
+	repos, err := repo_model.GetRepositoriesMapByIDs(ctx, repoIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &UsageReport{Month: start.AsTime().UTC()}
+	// the jobs running on the same labels in different orders are counted together
+	usages := make(map[string]*Usage, len(taskUsages))
+	for _, taskUsage := range taskUsages {
+		repo, ok := repos[taskUsage.RepoID]
+		if !ok {
+			// the repository has been deleted
+			continue
+		}
+		labels := slices.Clone(taskUsage.RunsOn)
+		slices.Sort(labels)
+		key := strconv.FormatInt(repo.ID, 10) + ":" + strings.Join(labels, ",")
+		usage, ok := usages[key]
+		if !ok {
+			usage = &Usage{Repo: repo, Labels: labels}
+			usages[key] = usage
+			report.Usages = append(report.Usages, usage)
+		}
+		usage.Jobs += taskUsage.Tasks
+		usage.Seconds += taskUsage.Seconds
+		report.TotalSeconds += taskUsage.Seconds
+	}
+	for _, usage := range report.Usages {
+		usage.Minutes = secondsToMinutes(usage.Seconds)
+	}
+	report.TotalMinutes = secondsToMinutes(report.TotalSeconds)
+	return report, nil
+}
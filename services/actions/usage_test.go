@@ -0,0 +1,65 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUsageMonth(t *testing.T) {
+	month, err := ParseUsageMonth("2025-02")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), month)
+	start, end := usageMonthRange(month)
+	assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC).Unix()-start.AsTime().Unix(), int64(end-start))
+
+	_, err = ParseUsageMonth("2025-13")
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+
+	month, err = ParseUsageMonth("")
+	require.NoError(t, err)
+	assert.Equal(t, 1, month.Day())
+
+	assert.EqualValues(t, 0, secondsToMinutes(0))
+	assert.EqualValues(t, 1, secondsToMinutes(1))
+	assert.EqualValues(t, 2, secondsToMinutes(61))
+}
+
+func TestOwnerUsageQuota(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	quota, err := GetOwnerUsageQuota(t.Context(), 2)
+	require.NoError(t, err)
+	assert.Zero(t, quota.Minutes)
+	policy, err := checkOwnerUsageQuota(t.Context(), 2)
+	require.NoError(t, err)
+	assert.Empty(t, policy)
+
+	assert.ErrorIs(t, SetOwnerUsageQuota(t.Context(), 2, &UsageQuota{Minutes: -1}), util.ErrInvalidArgument)
+	assert.ErrorIs(t, SetOwnerUsageQuota(t.Context(), 2, &UsageQuota{Minutes: 10, Policy: "unknown"}), util.ErrInvalidArgument)
+
+	require.NoError(t, SetOwnerUsageQuota(t.Context(), 2, &UsageQuota{Minutes: 10, Policy: actions_model.UsageQuotaPolicyHard}))
+	quota, err = GetOwnerUsageQuota(t.Context(), 2)
+	require.NoError(t, err)
+	assert.EqualValues(t, 10, quota.Minutes)
+	assert.Equal(t, actions_model.UsageQuotaPolicyHard, quota.Policy)
+	// no jobs have run this month
+	policy, err = checkOwnerUsageQuota(t.Context(), 2)
+	require.NoError(t, err)
+	assert.Empty(t, policy)
+
+	require.NoError(t, SetOwnerUsageQuota(t.Context(), 2, &UsageQuota{}))
+	quota, err = GetOwnerUsageQuota(t.Context(), 2)
+	require.NoError(t, err)
+	assert.Zero(t, quota.Minutes)
+	assert.Equal(t, actions_model.UsageQuotaPolicySoft, quota.Policy)
+}
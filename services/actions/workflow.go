@@ -4,11 +4,16 @@
 package actions
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
 	"strings"
 
 	actions_model "code.gitea.io/gitea/models/actions"
-	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/models/perm"
 	access_model "code.gitea.io/gitea/models/perm/access"
 	repo_model "code.gitea.io/gitea/models/repo"
@@ -20,15 +25,14 @@ import (
 	"code.gitea.io/gitea/modules/reqctx"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
-	"code.gitea.io/gitea/services/context"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+	gitea_context "code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/convert"
-	notify_service "code.gitea.io/gitea/services/notify"
 
-	"github.com/nektos/act/pkg/jobparser"
 	"github.com/nektos/act/pkg/model"
 )
 
-func EnableOrDisableWorkflow(ctx *context.APIContext, workflowID string, isEnable bool) error {
+func EnableOrDisableWorkflow(ctx *gitea_context.APIContext, workflowID string, isEnable bool) error {
 	workflow, err := convert.GetActionWorkflow(ctx, ctx.Repo.GitRepo, ctx.Repo.Repository, workflowID)
 	if err != nil {
 		return err
@@ -46,7 +50,48 @@ func EnableOrDisableWorkflow(ctx *context.APIContext, workflowID string, isEnabl
 	return repo_model.UpdateRepoUnit(ctx, cfgUnit)
 }
 
-func DispatchActionWorkflow(ctx reqctx.RequestContext, doer *user_model.User, repo *repo_model.Repository, gitRepo *git.Repository, workflowID, ref string, processInputs func(model *model.WorkflowDispatch, inputs map[string]any) error) error {
+const (
+	repositoryDispatchEventTypeMaxLength    = 100
+	repositoryDispatchClientPayloadMaxProps = 10
+)
+
+// DispatchRepositoryEvent triggers the workflows of the default branch which are listening to the repository_dispatch event with the event type.
+func DispatchRepositoryEvent(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, eventType string, clientPayload map[string]any) error {
+	if eventType == "" || len(eventType) > repositoryDispatchEventTypeMaxLength {
+		return util.NewInvalidArgumentErrorf("event type must be between 1 and %d characters", repositoryDispatchEventTypeMaxLength)
+	}
+	if len(clientPayload) > repositoryDispatchClientPayloadMaxProps {
+		return util.NewInvalidArgumentErrorf("client payload can't have more than %d top-level properties", repositoryDispatchClientPayloadMaxProps)
+	}
+	if repo.IsEmpty {
+		return util.NewInvalidArgumentErrorf("repository is empty")
+	}
+	if clientPayload == nil {
+		clientPayload = map[string]any{}
+	}
+
+	permission, err := access_model.GetUserRepoPermission(ctx, repo, doer)
+	if err != nil {
+		return err
+	}
+
+	ctx = withMethod(ctx, "DispatchRepositoryEvent")
+	newNotifyInput(repo, doer, webhook_module.HookEventRepositoryDispatch).
+		WithRef(git.RefNameFromBranch(repo.DefaultBranch).String()).
+		WithPayload(&api.RepositoryDispatchPayload{
+			Action:        eventType,
+			Branch:        repo.DefaultBranch,
+			ClientPayload: clientPayload,
+			Repository:    convert.ToRepo(ctx, repo, permission),
+			Sender:        convert.ToUser(ctx, doer, nil),
+		}).
+		Notify(ctx)
+	return nil
+}
+
+// DispatchActionWorkflow creates a workflow_dispatch run of the workflow,
+// processInputs collects the provided inputs which are validated against the inputs declared by the workflow
+func DispatchActionWorkflow(ctx reqctx.RequestContext, doer *user_model.User, repo *repo_model.Repository, gitRepo *git.Repository, workflowID, ref string, processInputs func(model *model.WorkflowDispatch, inputs map[string]string) error) error {
 	if workflowID == "" {
 		return util.ErrorWrapLocale(
 			util.NewNotExistErrorf("workflowID is empty"),
@@ -97,7 +142,6 @@ func DispatchActionWorkflow(ctx reqctx.RequestContext, doer *user_model.User, re
 	}
 
 	// find workflow from commit
-	var workflows []*jobparser.SingleWorkflow
 	var entry *git.TreeEntry
 
 	run := &actions_model.ActionRun{
@@ -136,18 +180,12 @@ func DispatchActionWorkflow(ctx reqctx.RequestContext, doer *user_model.User, re
 		return err
 	}
 
-	giteaCtx := GenerateGiteaContext(run, nil)
-
-	workflows, err = jobparser.Parse(content, jobparser.WithGitContext(giteaCtx.ToGitHubContext()))
+	workflow, err := model.ReadWorkflow(bytes.NewReader(content))
 	if err != nil {
 		return err
 	}
 
-	if len(workflows) > 0 && workflows[0].RunName != "" {
-		run.Title = workflows[0].RunName
-	}
-
-	if len(workflows) == 0 {
+	if len(workflow.Jobs) == 0 {
 		return util.ErrorWrapLocale(
 			util.NewNotExistErrorf("workflow %q doesn't exist", workflowID),
 			"actions.workflow.not_found", workflowID,
@@ -155,12 +193,13 @@ func DispatchActionWorkflow(ctx reqctx.RequestContext, doer *user_model.User, re
 	}
 
 	// get inputs from post
-	workflow := &model.Workflow{
-		RawOn: workflows[0].RawOn,
-	}
 	inputsWithDefaults := make(map[string]any)
 	if workflowDispatch := workflow.WorkflowDispatchConfig(); workflowDispatch != nil {
-		if err = processInputs(workflowDispatch, inputsWithDefaults); err != nil {
+		providedInputs := make(map[string]string)
+		if err = processInputs(workflowDispatch, providedInputs); err != nil {
+			return err
+		}
+		if inputsWithDefaults, err = ResolveWorkflowDispatchInputs(ctx, repo, workflowDispatch, providedInputs); err != nil {
 			return err
 		}
 	}
@@ -194,26 +233,99 @@ func DispatchActionWorkflow(ctx reqctx.RequestContext, doer *user_model.User, re
 	}
 
 	// Insert the action run and its associated jobs into the database
-	if err := actions_model.InsertRun(ctx, run, workflows); err != nil {
-		return fmt.Errorf("InsertRun: %w", err)
+	if err := PrepareRunAndInsert(ctx, content, run); err != nil {
+		return fmt.Errorf("PrepareRunAndInsert: %w", err)
 	}
+	return nil
+}
 
-	allJobs, err := db.Find[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{RunID: run.ID})
-	if err != nil {
-		log.Error("FindRunJobs: %v", err)
+// WorkflowDispatchInputsError is returned when the provided inputs don't match the inputs declared by the workflow
+type WorkflowDispatchInputsError struct {
+	InvalidInputs map[string]string // the reasons by the names of the invalid inputs
+}
+
+func (err *WorkflowDispatchInputsError) Error() string {
+	names := slices.Sorted(maps.Keys(err.InvalidInputs))
+	reasons := make([]string, 0, len(names))
+	for _, name := range names {
+		reasons = append(reasons, fmt.Sprintf("%s: %s", name, err.InvalidInputs[name]))
 	}
-	CreateCommitStatus(ctx, allJobs...)
-	if len(allJobs) > 0 {
-		job := allJobs[0]
-		err := job.LoadRun(ctx)
+	return "invalid inputs: " + strings.Join(reasons, "; ")
+}
+
+func (err *WorkflowDispatchInputsError) Unwrap() error {
+	return util.ErrInvalidArgument
+}
+
+// ResolveWorkflowDispatchInputs validates the provided inputs against the inputs declared by the workflow,
+// and returns all declared inputs with the defaults of the ones which are not provided
+func ResolveWorkflowDispatchInputs(ctx context.Context, repo *repo_model.Repository, workflowDispatch *model.WorkflowDispatch, provided map[string]string) (map[string]any, error) {
+	invalidInputs := make(map[string]string)
+	for name := range provided {
+		if _, ok := workflowDispatch.Inputs[name]; !ok {
+			invalidInputs[name] = "unexpected input"
+		}
+	}
+
+	inputs := make(map[string]any, len(workflowDispatch.Inputs))
+	for name, config := range workflowDispatch.Inputs {
+		value := provided[name]
+		if value == "" {
+			value = config.Default
+		}
+		value, err := validateWorkflowDispatchInput(ctx, repo, config, value)
+		if errors.Is(err, util.ErrInvalidArgument) {
+			invalidInputs[name] = err.Error()
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		inputs[name] = value
+	}
+
+	if len(invalidInputs) > 0 {
+		err := &WorkflowDispatchInputsError{InvalidInputs: invalidInputs}
+		return nil, util.ErrorWrapLocale(err, "actions.workflow.invalid_inputs", err.Error())
+	}
+	return inputs, nil
+}
+
+// validateWorkflowDispatchInput checks the value by the type of the input and returns the normalized value,
+// see https://docs.github.com/en/actions/writing-workflows/workflow-syntax-for-github-actions#onworkflow_dispatchinputsinput_idtype
+func validateWorkflowDispatchInput(ctx context.Context, repo *repo_model.Repository, config model.WorkflowDispatchInput, value string) (string, error) {
+	if config.Type == "boolean" {
+		if value == "" {
+			return "false", nil
+		}
+		b, err := strconv.ParseBool(value)
 		if err != nil {
-			log.Error("LoadRun: %v", err)
-		} else {
-			notify_service.WorkflowRunStatusUpdate(ctx, job.Run.Repo, job.Run.TriggerUser, job.Run)
+			return "", util.NewInvalidArgumentErrorf("%q is not a boolean", value)
+		}
+		return strconv.FormatBool(b), nil
+	}
+
+	if value == "" {
+		if config.Required {
+			return "", util.NewInvalidArgumentErrorf("input is required")
 		}
+		return "", nil
 	}
-	for _, job := range allJobs {
-		notify_service.WorkflowJobStatusUpdate(ctx, repo, doer, job, nil)
+
+	switch config.Type {
+	case "choice":
+		if !slices.Contains(config.Options, value) {
+			return "", util.NewInvalidArgumentErrorf("%q is not one of the options %q", value, config.Options)
+		}
+	case "number":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return "", util.NewInvalidArgumentErrorf("%q is not a number", value)
+		}
+	case "environment":
+		if _, err := actions_model.GetEnvironmentByRepoAndName(ctx, repo.ID, value); errors.Is(err, util.ErrNotExist) {
+			return "", util.NewInvalidArgumentErrorf("environment %q doesn't exist", value)
+		} else if err != nil {
+			return "", err
+		}
 	}
-	return nil
+	return value, nil
 }
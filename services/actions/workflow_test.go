@@ -0,0 +1,57 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/nektos/act/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveWorkflowDispatchInputs(t *testing.T) {
+	workflowDispatch := &model.WorkflowDispatch{
+		Inputs: map[string]model.WorkflowDispatchInput{
+			"message": {Default: "hello"},
+			"level":   {Type: "choice", Options: []string{"debug", "info"}, Required: true},
+			"debug":   {Type: "boolean", Default: "true"},
+			"verbose": {Type: "boolean"},
+			"retries": {Type: "number"},
+		},
+	}
+
+	inputs, err := ResolveWorkflowDispatchInputs(t.Context(), nil, workflowDispatch, map[string]string{
+		"level":   "debug",
+		"verbose": "1",
+		"retries": "3",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"message": "hello",
+		"level":   "debug",
+		"debug":   "true",
+		"verbose": "true",
+		"retries": "3",
+	}, inputs)
+
+	_, err = ResolveWorkflowDispatchInputs(t.Context(), nil, workflowDispatch, map[string]string{
+		"debug":   "yes",
+		"retries": "three",
+		"unknown": "value",
+	})
+	var inputsErr *WorkflowDispatchInputsError
+	require.ErrorAs(t, err, &inputsErr)
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	assert.Equal(t, map[string]string{
+		"level":   "input is required",
+		"debug":   `"yes" is not a boolean`,
+		"retries": `"three" is not a number`,
+		"unknown": "unexpected input",
+	}, inputsErr.InvalidInputs)
+	assert.Equal(t, `invalid inputs: debug: "yes" is not a boolean; level: input is required; retries: "three" is not a number; unknown: unexpected input`, inputsErr.Error())
+	assert.NotNil(t, util.ErrorAsLocale(err))
+}
@@ -7,9 +7,12 @@ import (
 	"fmt"
 	"net/http"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/organization"
 	packages_model "code.gitea.io/gitea/models/packages"
 	"code.gitea.io/gitea/models/perm"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/setting"
@@ -101,7 +104,12 @@ func determineAccessMode(ctx *Base, pkg *Package, doer *user_model.User) (perm.A
 		return perm.AccessModeNone, nil
 	}
 
-	// TODO: ActionUser permission check
+	if doer != nil && doer.IsGiteaActions() {
+		if taskID, ok := ctx.Data["ActionsTaskID"].(int64); ok {
+			return determineActionsAccessMode(ctx, pkg, taskID)
+		}
+	}
+
 	accessMode := perm.AccessModeNone
 	if pkg.Owner.IsOrganization() {
 		org := organization.OrgFromUser(pkg.Owner)
@@ -148,6 +156,33 @@ func determineAccessMode(ctx *Base, pkg *Package, doer *user_model.User) (perm.A
 	return accessMode, nil
 }
 
+// determineActionsAccessMode returns the access of the token of an Actions task to the package.
+// The token can access the packages of the owner of its repository by the "packages" permission of the task,
+// and it can read the packages of the public owners.
+func determineActionsAccessMode(ctx *Base, pkg *Package, taskID int64) (perm.AccessMode, error) {
+	task, err := actions_model.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return perm.AccessModeNone, err
+	}
+
+	accessMode := perm.AccessModeNone
+	if task.OwnerID == pkg.Owner.ID {
+		repo, err := repo_model.GetRepositoryByID(ctx, task.RepoID)
+		if err != nil {
+			return perm.AccessModeNone, err
+		}
+		p, err := access_model.GetActionsUserRepoPermission(ctx, repo, task)
+		if err != nil {
+			return perm.AccessModeNone, err
+		}
+		accessMode = min(p.UnitAccessMode(unit.TypePackages), perm.AccessModeWrite)
+	}
+	if accessMode == perm.AccessModeNone && pkg.Owner.Visibility == structs.VisibleTypePublic {
+		accessMode = perm.AccessModeRead
+	}
+	return accessMode, nil
+}
+
 // PackageContexter initializes a package context for a request.
 func PackageContexter() func(next http.Handler) http.Handler {
 	renderer := templates.HTMLRenderer()
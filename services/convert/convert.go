@@ -8,8 +8,10 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"maps"
 	"net/url"
 	"path"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -29,9 +31,11 @@ import (
 	"code.gitea.io/gitea/modules/actions"
 	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 	asymkey_service "code.gitea.io/gitea/services/asymkey"
 	"code.gitea.io/gitea/services/gitdiff"
@@ -253,11 +257,16 @@ func ToActionWorkflowRun(ctx context.Context, repo *repo_model.Repository, run *
 		return nil, err
 	}
 	status, conclusion := ToActionsStatus(run.Status)
+	if run.NeedApproval && !run.Status.IsDone() {
+		// the run is waiting for the approval of a maintainer, like GitHub does
+		conclusion = "action_required"
+	}
 	return &api.ActionWorkflowRun{
 		ID:           run.ID,
 		URL:          fmt.Sprintf("%s/actions/runs/%d", repo.APIURL(), run.ID),
 		HTMLURL:      run.HTMLURL(),
 		RunNumber:    run.Index,
+		RunAttempt:   run.Attempt,
 		StartedAt:    run.Started.AsLocalTime(),
 		CompletedAt:  run.Stopped.AsLocalTime(),
 		Event:        string(run.Event),
@@ -270,7 +279,8 @@ func ToActionWorkflowRun(ctx context.Context, repo *repo_model.Repository, run *
 		Repository:   ToRepo(ctx, repo, access_model.Permission{AccessMode: perm.AccessModeNone}),
 		TriggerActor: ToUser(ctx, run.TriggerUser, nil),
 		// We do not have a way to get a different User for the actor than the trigger user
-		Actor: ToUser(ctx, run.TriggerUser, nil),
+		Actor:         ToUser(ctx, run.TriggerUser, nil),
+		QuotaExceeded: run.QuotaExceeded,
 	}, nil
 }
 
@@ -340,6 +350,7 @@ func ToActionWorkflowJob(ctx context.Context, repo *repo_model.Repository, task
 	var runnerID int64
 	var runnerName string
 	var steps []*api.ActionWorkflowStep
+	var logsExpired bool
 
 	if job.TaskID != 0 {
 		if task == nil {
@@ -350,6 +361,7 @@ func ToActionWorkflowJob(ctx context.Context, repo *repo_model.Repository, task
 		}
 
 		runnerID = task.RunnerID
+		logsExpired = task.LogExpired
 		if runner, ok, _ := db.GetByID[actions_model.ActionRunner](ctx, runnerID); ok {
 			runnerName = runner.Name
 		}
@@ -384,12 +396,25 @@ func ToActionWorkflowJob(ctx context.Context, repo *repo_model.Repository, task
 		RunnerID:    runnerID,
 		RunnerName:  runnerName,
 		Steps:       steps,
+		LogsExpired: logsExpired,
 		CreatedAt:   job.Created.AsTime().UTC(),
 		StartedAt:   job.Started.AsTime().UTC(),
 		CompletedAt: job.Stopped.AsTime().UTC(),
 	}, nil
 }
 
+// ToActionWorkflowStepSummary converts the summary written by a step of the job to the API format
+func ToActionWorkflowStepSummary(job *actions_model.ActionRunJob, stepName string, summary *actions_model.ActionTaskSummary) *api.ActionWorkflowStepSummary {
+	return &api.ActionWorkflowStepSummary{
+		JobID:      job.ID,
+		JobName:    job.Name,
+		StepNumber: summary.StepIndex,
+		StepName:   stepName,
+		Content:    summary.Content,
+		CreatedAt:  summary.Created.AsTime().UTC(),
+	}
+}
+
 func getActionWorkflowEntry(ctx context.Context, repo *repo_model.Repository, commit *git.Commit, folder string, entry *git.TreeEntry) *api.ActionWorkflow {
 	cfgUnit := repo.MustGetUnit(ctx, unit.TypeActions)
 	cfg := cfgUnit.ActionsConfig()
@@ -422,6 +447,7 @@ func getActionWorkflowEntry(ctx context.Context, repo *repo_model.Repository, co
 
 	content, err := actions.GetContentFromEntry(entry)
 	name := entry.Name()
+	var inputs []*api.ActionWorkflowInput
 	if err == nil {
 		workflow, err := model.ReadWorkflow(bytes.NewReader(content))
 		if err == nil {
@@ -429,6 +455,9 @@ func getActionWorkflowEntry(ctx context.Context, repo *repo_model.Repository, co
 			if workflow.Name != "" {
 				name = workflow.Name
 			}
+			if workflowDispatch := workflow.WorkflowDispatchConfig(); workflowDispatch != nil {
+				inputs = toActionWorkflowInputs(workflowDispatch)
+			}
 		} else {
 			log.Error("getActionWorkflowEntry: Failed to parse workflow: %v", err)
 		}
@@ -446,7 +475,29 @@ func getActionWorkflowEntry(ctx context.Context, repo *repo_model.Repository, co
 		URL:       workflowURL,
 		HTMLURL:   workflowRepoURL,
 		BadgeURL:  badgeURL,
+		Inputs:    inputs,
+	}
+}
+
+// toActionWorkflowInputs converts the declared inputs of the workflow_dispatch event, the inputs are sorted by names
+func toActionWorkflowInputs(workflowDispatch *model.WorkflowDispatch) []*api.ActionWorkflowInput {
+	inputs := make([]*api.ActionWorkflowInput, 0, len(workflowDispatch.Inputs))
+	for _, name := range slices.Sorted(maps.Keys(workflowDispatch.Inputs)) {
+		config := workflowDispatch.Inputs[name]
+		inputType := config.Type
+		if inputType == "" {
+			inputType = "string"
+		}
+		inputs = append(inputs, &api.ActionWorkflowInput{
+			Name:        name,
+			Description: config.Description,
+			Type:        inputType,
+			Required:    config.Required,
+			Default:     config.Default,
+			Options:     config.Options,
+		})
 	}
+	return inputs
 }
 
 func ListActionWorkflows(ctx context.Context, gitrepo *git.Repository, repo *repo_model.Repository) ([]*api.ActionWorkflow, error) {
@@ -483,6 +534,61 @@ func GetActionWorkflow(ctx context.Context, gitrepo *git.Repository, repo *repo_
 	return nil, util.NewNotExistErrorf("workflow %q not found", workflowID)
 }
 
+// ToActionWorkflowSchedule converts an actions_model.ActionScheduleSpec to an api.ActionWorkflowSchedule,
+// lastRun is the latest run of the workflow triggered by a schedule, it can be nil
+func ToActionWorkflowSchedule(ctx context.Context, repo *repo_model.Repository, spec *actions_model.ActionScheduleSpec, lastRun *actions_model.ActionRun) (*api.ActionWorkflowSchedule, error) {
+	upcoming, err := spec.UpcomingTimes(spec.Next.AsTime(), 4)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := &api.ActionWorkflowSchedule{
+		Cron:           spec.Cron(),
+		Timezone:       spec.Timezone(),
+		NextRunAt:      spec.Next.AsLocalTime(),
+		UpcomingRunsAt: []time.Time{spec.Next.AsLocalTime()},
+		LastSkipReason: string(spec.LastSkipReason),
+	}
+	if spec.Schedule != nil {
+		schedule.WorkflowID = spec.Schedule.WorkflowID
+	}
+	for _, t := range upcoming {
+		schedule.UpcomingRunsAt = append(schedule.UpcomingRunsAt, timeutil.TimeStamp(t.Unix()).AsLocalTime())
+	}
+	if spec.Prev > 0 {
+		schedule.LastScheduledAt = util.ToPointer(spec.Prev.AsLocalTime())
+	}
+	if spec.LastSkipped > 0 {
+		schedule.LastSkippedAt = util.ToPointer(spec.LastSkipped.AsLocalTime())
+	}
+	if lastRun != nil {
+		if schedule.LastRun, err = ToActionWorkflowRun(ctx, repo, lastRun); err != nil {
+			return nil, err
+		}
+	}
+	return schedule, nil
+}
+
+// ToActionAttestation converts an actions_model.ActionAttestation to an api.ActionAttestation
+func ToActionAttestation(attest *actions_model.ActionAttestation) (*api.ActionAttestation, error) {
+	var envelope api.ActionAttestationEnvelope
+	if err := json.Unmarshal([]byte(attest.Envelope), &envelope); err != nil {
+		return nil, err
+	}
+	return &api.ActionAttestation{
+		ID:               attest.ID,
+		SubjectName:      attest.SubjectName,
+		SubjectDigest:    attest.SubjectDigest,
+		PredicateType:    attest.PredicateType,
+		RunID:            attest.RunID,
+		ArtifactID:       attest.ArtifactID,
+		PackageVersionID: attest.PackageVersionID,
+		KeyID:            attest.KeyID,
+		Envelope:         &envelope,
+		CreatedAt:        attest.Created.AsLocalTime(),
+	}, nil
+}
+
 // ToActionArtifact convert a actions_model.ActionArtifact to an api.ActionArtifact
 func ToActionArtifact(repo *repo_model.Repository, art *actions_model.ActionArtifact) (*api.ActionArtifact, error) {
 	url := fmt.Sprintf("%s/actions/artifacts/%d", repo.APIURL(), art.ID)
@@ -499,6 +605,7 @@ func ToActionArtifact(repo *repo_model.Repository, art *actions_model.ActionArti
 		ExpiresAt:          art.ExpiredUnix.AsLocalTime(),
 		WorkflowRun: &api.ActionWorkflowRun{
 			ID:           art.RunID,
+			RunAttempt:   art.RunAttempt,
 			RepositoryID: art.RepoID,
 			HeadSha:      art.CommitSHA,
 		},
@@ -520,12 +627,31 @@ func ToActionRunner(ctx context.Context, runner *actions_model.ActionRunner) *ap
 		}
 	}
 	return &api.ActionRunner{
-		ID:        runner.ID,
-		Name:      runner.Name,
-		Status:    apiStatus,
-		Busy:      status == runnerv1.RunnerStatus_RUNNER_STATUS_ACTIVE,
-		Ephemeral: runner.Ephemeral,
-		Labels:    labels,
+		ID:            runner.ID,
+		Name:          runner.Name,
+		Status:        apiStatus,
+		Busy:          status == runnerv1.RunnerStatus_RUNNER_STATUS_ACTIVE,
+		Ephemeral:     runner.Ephemeral,
+		Labels:        labels,
+		RunnerGroupID: runner.GroupID,
+	}
+}
+
+// ToActionRunnerGroup converts a runner group to the API format
+func ToActionRunnerGroup(group *actions_model.ActionRunnerGroup) *api.ActionRunnerGroup {
+	visibility := "all"
+	if group.RestrictRepos {
+		visibility = "selected"
+	}
+	return &api.ActionRunnerGroup{
+		ID:                    group.ID,
+		Name:                  group.Name,
+		Visibility:            visibility,
+		SelectedRepositoryIDs: util.SliceNilAsEmpty(group.RepoIDs),
+		WorkflowPatterns:      util.SliceNilAsEmpty(group.WorkflowPatterns),
+		RefPatterns:           util.SliceNilAsEmpty(group.RefPatterns),
+		Created:               group.Created.AsTime(),
+		Updated:               group.Updated.AsTime(),
 	}
 }
 
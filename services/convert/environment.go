@@ -0,0 +1,58 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"context"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/organization"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToActionEnvironment converts ActionEnvironment to API format
+func ToActionEnvironment(ctx context.Context, env *actions_model.ActionEnvironment, doer *user_model.User) (*api.ActionEnvironment, error) {
+	reviewers := make([]*api.ActionEnvironmentReviewer, 0, len(env.ReviewerUserIDs)+len(env.ReviewerTeamIDs))
+
+	users, err := user_model.GetUsersByIDs(ctx, env.ReviewerUserIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		reviewers = append(reviewers, &api.ActionEnvironmentReviewer{Type: "User", User: ToUser(ctx, u, doer)})
+	}
+
+	teams, err := organization.GetTeamsByIDs(ctx, env.ReviewerTeamIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range env.ReviewerTeamIDs {
+		team, ok := teams[id]
+		if !ok {
+			continue
+		}
+		apiTeam, err := ToTeam(ctx, team)
+		if err != nil {
+			return nil, err
+		}
+		reviewers = append(reviewers, &api.ActionEnvironmentReviewer{Type: "Team", Team: apiTeam})
+	}
+
+	branchPatterns := env.BranchPatterns
+	if branchPatterns == nil {
+		branchPatterns = []string{}
+	}
+
+	return &api.ActionEnvironment{
+		ID:                env.ID,
+		Name:              env.Name,
+		WaitTimer:         env.WaitTimer,
+		PreventSelfReview: env.PreventSelfReview,
+		Reviewers:         reviewers,
+		BranchPatterns:    branchPatterns,
+		Created:           env.Created.AsTime(),
+		Updated:           env.Updated.AsTime(),
+	}, nil
+}
@@ -51,3 +51,55 @@ func ToPackageFile(pfd *packages.PackageFileDescriptor) *api.PackageFile {
 		HashSHA512: pfd.Blob.HashSHA512,
 	}
 }
+
+// ToPackageRemote converts packages.PackageRemote to api.PackageRemote
+func ToPackageRemote(pr *packages.PackageRemote) *api.PackageRemote {
+	return &api.PackageRemote{
+		Type:        string(pr.Type),
+		URL:         pr.URL,
+		Username:    pr.Username,
+		HasPassword: pr.PasswordEncrypted != "",
+		MetadataTTL: pr.MetadataTTL,
+		Enabled:     pr.Enabled,
+		CreatedAt:   pr.CreatedUnix.AsTime(),
+		UpdatedAt:   pr.UpdatedUnix.AsTime(),
+	}
+}
+
+// ToPackageCleanupRule converts packages.PackageCleanupRule to api.PackageCleanupRule
+func ToPackageCleanupRule(pcr *packages.PackageCleanupRule) *api.PackageCleanupRule {
+	return &api.PackageCleanupRule{
+		ID:                 pcr.ID,
+		Type:               string(pcr.Type),
+		Enabled:            pcr.Enabled,
+		KeepCount:          pcr.KeepCount,
+		KeepPattern:        pcr.KeepPattern,
+		KeepSemverRange:    pcr.KeepSemverRange,
+		KeepLatestPerMajor: pcr.KeepLatestPerMajor,
+		RemoveDays:         pcr.RemoveDays,
+		RemovePattern:      pcr.RemovePattern,
+		MatchFullName:      pcr.MatchFullName,
+		CreatedAt:          pcr.CreatedUnix.AsTime(),
+		UpdatedAt:          pcr.UpdatedUnix.AsTime(),
+	}
+}
+
+// ToPackageCleanupRun converts packages.PackageCleanupRun and its removed versions to api.PackageCleanupRun
+func ToPackageCleanupRun(run *packages.PackageCleanupRun, versions []*packages.PackageCleanupRunVersion) *api.PackageCleanupRun {
+	apiVersions := make([]*api.PackageCleanupRunVersion, 0, len(versions))
+	for _, v := range versions {
+		apiVersions = append(apiVersions, &api.PackageCleanupRunVersion{
+			Name:    v.PackageName,
+			Version: v.Version,
+			Size:    v.Size,
+		})
+	}
+
+	return &api.PackageCleanupRun{
+		ID:        run.ID,
+		RuleID:    run.RuleID,
+		Type:      string(run.Type),
+		Versions:  apiVersions,
+		CreatedAt: run.CreatedUnix.AsTime(),
+	}
+}
@@ -19,7 +19,10 @@ func initActionsTasks() {
 	registerStopEndlessTasks()
 	registerCancelAbandonedJobs()
 	registerScheduleTasks()
+	registerStartJobsWaitingForEnvironments()
 	registerActionsCleanup()
+	registerActionsCachesCleanup()
+	registerArchiveActionsLogs()
 }
 
 func registerStopZombieTasks() {
@@ -65,6 +68,16 @@ func registerScheduleTasks() {
 	})
 }
 
+func registerStartJobsWaitingForEnvironments() {
+	RegisterTaskFatal("start_jobs_waiting_for_environments", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: true,
+		Schedule:   "@every 1m",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return actions_service.StartJobsWaitingForEnvironments(ctx)
+	})
+}
+
 func registerActionsCleanup() {
 	RegisterTaskFatal("cleanup_actions", &BaseConfig{
 		Enabled:    true,
@@ -74,3 +87,23 @@ func registerActionsCleanup() {
 		return actions_service.Cleanup(ctx)
 	})
 }
+
+func registerActionsCachesCleanup() {
+	RegisterTaskFatal("cleanup_actions_caches", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 1h",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return actions_service.CleanupCaches(ctx)
+	})
+}
+
+func registerArchiveActionsLogs() {
+	RegisterTaskFatal("archive_actions_logs", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@midnight",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return actions_service.ArchiveLogs(ctx)
+	})
+}
@@ -9,10 +9,50 @@ import (
 	"code.gitea.io/gitea/models/db"
 	issues_model "code.gitea.io/gitea/models/issues"
 	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
 	notify_service "code.gitea.io/gitea/services/notify"
 )
 
+// NewRepoLabel creates a new label in the repository
+func NewRepoLabel(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, label *issues_model.Label) error {
+	label.RepoID = repo.ID
+	if err := issues_model.NewLabel(ctx, label); err != nil {
+		return err
+	}
+
+	notify_service.NewLabel(ctx, doer, repo, label)
+	return nil
+}
+
+// UpdateRepoLabel updates a label of the repository
+func UpdateRepoLabel(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, label *issues_model.Label) error {
+	if err := issues_model.UpdateLabel(ctx, label); err != nil {
+		return err
+	}
+
+	notify_service.UpdateLabel(ctx, doer, repo, label)
+	return nil
+}
+
+// DeleteRepoLabel deletes a label of the repository, it does nothing if the label doesn't exist
+func DeleteRepoLabel(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, labelID int64) error {
+	label, err := issues_model.GetLabelInRepoByID(ctx, repo.ID, labelID)
+	if err != nil {
+		if issues_model.IsErrRepoLabelNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	if err := issues_model.DeleteLabel(ctx, repo.ID, label.ID); err != nil {
+		return err
+	}
+
+	notify_service.DeleteLabel(ctx, doer, repo, label)
+	return nil
+}
+
 // ClearLabels clears all of an issue's labels
 func ClearLabels(ctx context.Context, issue *issues_model.Issue, doer *user_model.User) error {
 	if err := issues_model.ClearIssueLabels(ctx, issue, doer); err != nil {
@@ -10,6 +10,7 @@ import (
 
 	"code.gitea.io/gitea/models/db"
 	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
 	notify_service "code.gitea.io/gitea/services/notify"
 )
@@ -87,3 +88,60 @@ func ChangeMilestoneAssign(ctx context.Context, issue *issues_model.Issue, doer
 
 	return nil
 }
+
+// NewMilestone creates a new milestone in the repository
+func NewMilestone(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, milestone *issues_model.Milestone) error {
+	milestone.RepoID = repo.ID
+	if err := issues_model.NewMilestone(ctx, milestone); err != nil {
+		return err
+	}
+
+	notify_service.NewMilestone(ctx, doer, repo, milestone)
+	return nil
+}
+
+// UpdateMilestone updates a milestone of the repository
+func UpdateMilestone(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, milestone *issues_model.Milestone, oldIsClosed bool) error {
+	if err := issues_model.UpdateMilestone(ctx, milestone, oldIsClosed); err != nil {
+		return err
+	}
+
+	notify_service.UpdateMilestone(ctx, doer, repo, milestone)
+	if milestone.IsClosed != oldIsClosed {
+		notify_service.ChangeMilestoneStatus(ctx, doer, repo, milestone, milestone.IsClosed)
+	}
+	return nil
+}
+
+// ChangeMilestoneStatus closes or reopens a milestone of the repository
+func ChangeMilestoneStatus(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, milestoneID int64, isClosed bool) error {
+	if err := issues_model.ChangeMilestoneStatusByRepoIDAndID(ctx, repo.ID, milestoneID, isClosed); err != nil {
+		return err
+	}
+
+	milestone, err := issues_model.GetMilestoneByRepoID(ctx, repo.ID, milestoneID)
+	if err != nil {
+		return err
+	}
+
+	notify_service.ChangeMilestoneStatus(ctx, doer, repo, milestone, isClosed)
+	return nil
+}
+
+// DeleteMilestone deletes a milestone of the repository, it does nothing if the milestone doesn't exist
+func DeleteMilestone(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, milestoneID int64) error {
+	milestone, err := issues_model.GetMilestoneByRepoID(ctx, repo.ID, milestoneID)
+	if err != nil {
+		if issues_model.IsErrMilestoneNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	if err := issues_model.DeleteMilestoneByRepoID(ctx, repo.ID, milestone.ID); err != nil {
+		return err
+	}
+
+	notify_service.DeleteMilestone(ctx, doer, repo, milestone)
+	return nil
+}
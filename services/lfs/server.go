@@ -526,14 +526,12 @@ func authenticate(ctx *context.Context, repository *repo_model.Repository, autho
 			log.Error("Unable to GetTaskByID for task[%d] Error: %v", taskID, err)
 			return false
 		}
-		if task.RepoID != repository.ID {
+		perm, err := access_model.GetActionsUserRepoPermission(ctx, repository, task)
+		if err != nil {
+			log.Error("Unable to GetActionsUserRepoPermission for task[%d] Error: %v", taskID, err)
 			return false
 		}
-
-		if task.IsForkPullRequest {
-			return accessMode <= perm_model.AccessModeRead
-		}
-		return accessMode <= perm_model.AccessModeWrite
+		return perm.CanAccess(accessMode, unit.TypeCode)
 	}
 
 	// ctx.IsSigned is unnecessary here, this will be checked in perm.CanAccess
@@ -10,7 +10,9 @@ import (
 	"sort"
 
 	actions_model "code.gitea.io/gitea/models/actions"
+	access_model "code.gitea.io/gitea/models/perm/access"
 	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/log"
@@ -35,12 +37,14 @@ func generateMessageIDForActionsWorkflowRunStatusEmail(repo *repo_model.Reposito
 
 func composeAndSendActionsWorkflowRunStatusEmail(ctx context.Context, repo *repo_model.Repository, run *actions_model.ActionRun, sender *user_model.User, recipients []*user_model.User) {
 	subject := "Run"
-	switch run.Status {
-	case actions_model.StatusFailure:
+	switch {
+	case run.NeedApproval:
+		subject += " waiting for approval"
+	case run.Status == actions_model.StatusFailure:
 		subject += " failed"
-	case actions_model.StatusCancelled:
+	case run.Status == actions_model.StatusCancelled:
 		subject += " cancelled"
-	case actions_model.StatusSuccess:
+	case run.Status == actions_model.StatusSuccess:
 		subject += " succeeded"
 	}
 	subject = fmt.Sprintf("%s: %s (%s)", subject, run.WorkflowID, base.ShortSha(run.CommitSHA))
@@ -102,6 +106,9 @@ func composeAndSendActionsWorkflowRunStatusEmail(ctx context.Context, repo *repo
 		case actions_model.StatusCancelled:
 			runStatusText = "All jobs have been cancelled"
 		}
+		if run.NeedApproval {
+			runStatusText = "The run from a fork pull request is waiting for approval"
+		}
 		var mailBody bytes.Buffer
 		if err := LoadedTemplates().BodyTemplates.ExecuteTemplate(&mailBody, tplWorkflowRun, map[string]any{
 			"Subject":       subject,
@@ -163,3 +170,44 @@ func MailActionsTrigger(ctx context.Context, sender *user_model.User, repo *repo
 		composeAndSendActionsWorkflowRunStatusEmail(ctx, repo, run, sender, recipients)
 	}
 }
+
+// MailActionsApprovalRequired notifies the users who can approve the run that it's waiting for approval
+func MailActionsApprovalRequired(ctx context.Context, sender *user_model.User, repo *repo_model.Repository, run *actions_model.ActionRun) {
+	if setting.MailService == nil {
+		return
+	}
+
+	writers, err := access_model.GetRepoWriters(ctx, repo)
+	if err != nil {
+		log.Error("GetRepoWriters: %v", err)
+		return
+	}
+
+	recipients := make([]*user_model.User, 0, len(writers))
+	for _, user := range writers {
+		if user.ID == sender.ID || !user.IsMailable() {
+			continue
+		}
+		notifyPref, err := user_model.GetUserSetting(ctx, user.ID,
+			user_model.SettingsKeyEmailNotificationGiteaActions, user_model.SettingEmailNotificationGiteaActionsFailureOnly)
+		if err != nil {
+			log.Error("GetUserSetting: %v", err)
+			return
+		}
+		if notifyPref == user_model.SettingEmailNotificationGiteaActionsDisabled {
+			continue
+		}
+		perm, err := access_model.GetUserRepoPermission(ctx, repo, user)
+		if err != nil {
+			log.Error("GetUserRepoPermission: %v", err)
+			return
+		}
+		if perm.CanWrite(unit.TypeActions) {
+			recipients = append(recipients, user)
+		}
+	}
+
+	if len(recipients) > 0 {
+		composeAndSendActionsWorkflowRunStatusEmail(ctx, repo, run, sender, recipients)
+	}
+}
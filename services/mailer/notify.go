@@ -208,6 +208,10 @@ func (m *mailNotifier) RepoPendingTransfer(ctx context.Context, doer, newOwner *
 }
 
 func (m *mailNotifier) WorkflowRunStatusUpdate(ctx context.Context, repo *repo_model.Repository, sender *user_model.User, run *actions_model.ActionRun) {
+	if run.NeedApproval && !run.Status.IsDone() {
+		MailActionsApprovalRequired(ctx, sender, repo, run)
+		return
+	}
 	if !run.Status.IsDone() {
 		return
 	}
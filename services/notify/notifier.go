@@ -65,6 +65,15 @@ type Notifier interface {
 	UpdateRelease(ctx context.Context, doer *user_model.User, rel *repo_model.Release)
 	DeleteRelease(ctx context.Context, doer *user_model.User, rel *repo_model.Release)
 
+	NewLabel(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, label *issues_model.Label)
+	UpdateLabel(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, label *issues_model.Label)
+	DeleteLabel(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, label *issues_model.Label)
+
+	NewMilestone(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, milestone *issues_model.Milestone)
+	UpdateMilestone(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, milestone *issues_model.Milestone)
+	ChangeMilestoneStatus(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, milestone *issues_model.Milestone, isClosed bool)
+	DeleteMilestone(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, milestone *issues_model.Milestone)
+
 	PushCommits(ctx context.Context, pusher *user_model.User, repo *repo_model.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits)
 	CreateRef(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, refFullName git.RefName, refID string)
 	DeleteRef(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, refFullName git.RefName)
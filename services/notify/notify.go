@@ -216,6 +216,55 @@ func DeleteRelease(ctx context.Context, doer *user_model.User, rel *repo_model.R
 	}
 }
 
+// NewLabel notifies new label to notifiers
+func NewLabel(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, label *issues_model.Label) {
+	for _, notifier := range notifiers {
+		notifier.NewLabel(ctx, doer, repo, label)
+	}
+}
+
+// UpdateLabel notifies update label to notifiers
+func UpdateLabel(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, label *issues_model.Label) {
+	for _, notifier := range notifiers {
+		notifier.UpdateLabel(ctx, doer, repo, label)
+	}
+}
+
+// DeleteLabel notifies delete label to notifiers
+func DeleteLabel(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, label *issues_model.Label) {
+	for _, notifier := range notifiers {
+		notifier.DeleteLabel(ctx, doer, repo, label)
+	}
+}
+
+// NewMilestone notifies new milestone to notifiers
+func NewMilestone(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, milestone *issues_model.Milestone) {
+	for _, notifier := range notifiers {
+		notifier.NewMilestone(ctx, doer, repo, milestone)
+	}
+}
+
+// UpdateMilestone notifies update milestone to notifiers
+func UpdateMilestone(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, milestone *issues_model.Milestone) {
+	for _, notifier := range notifiers {
+		notifier.UpdateMilestone(ctx, doer, repo, milestone)
+	}
+}
+
+// ChangeMilestoneStatus notifies close or reopen milestone to notifiers
+func ChangeMilestoneStatus(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, milestone *issues_model.Milestone, isClosed bool) {
+	for _, notifier := range notifiers {
+		notifier.ChangeMilestoneStatus(ctx, doer, repo, milestone, isClosed)
+	}
+}
+
+// DeleteMilestone notifies delete milestone to notifiers
+func DeleteMilestone(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, milestone *issues_model.Milestone) {
+	for _, notifier := range notifiers {
+		notifier.DeleteMilestone(ctx, doer, repo, milestone)
+	}
+}
+
 // IssueChangeMilestone notifies change milestone to notifiers
 func IssueChangeMilestone(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldMilestoneID int64) {
 	for _, notifier := range notifiers {
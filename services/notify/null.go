@@ -110,6 +110,34 @@ func (*NullNotifier) UpdateRelease(ctx context.Context, doer *user_model.User, r
 func (*NullNotifier) DeleteRelease(ctx context.Context, doer *user_model.User, rel *repo_model.Release) {
 }
 
+// NewLabel places a place holder function
+func (*NullNotifier) NewLabel(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, label *issues_model.Label) {
+}
+
+// UpdateLabel places a place holder function
+func (*NullNotifier) UpdateLabel(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, label *issues_model.Label) {
+}
+
+// DeleteLabel places a place holder function
+func (*NullNotifier) DeleteLabel(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, label *issues_model.Label) {
+}
+
+// NewMilestone places a place holder function
+func (*NullNotifier) NewMilestone(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, milestone *issues_model.Milestone) {
+}
+
+// UpdateMilestone places a place holder function
+func (*NullNotifier) UpdateMilestone(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, milestone *issues_model.Milestone) {
+}
+
+// ChangeMilestoneStatus places a place holder function
+func (*NullNotifier) ChangeMilestoneStatus(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, milestone *issues_model.Milestone, isClosed bool) {
+}
+
+// DeleteMilestone places a place holder function
+func (*NullNotifier) DeleteMilestone(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, milestone *issues_model.Milestone) {
+}
+
 // IssueChangeMilestone places a place holder function
 func (*NullNotifier) IssueChangeMilestone(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldMilestoneID int64) {
 }
@@ -11,7 +11,8 @@ import (
 
 // Init initializes the oauth source
 func Init(ctx context.Context) error {
-	if !setting.OAuth2.Enabled {
+	// the signing key is also used to sign the OIDC ID tokens of Actions jobs
+	if !setting.OAuth2.Enabled && !setting.Actions.Enabled {
 		return nil
 	}
 
@@ -11,6 +11,7 @@ import (
 	"code.gitea.io/gitea/models/db"
 	packages_model "code.gitea.io/gitea/models/packages"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/optional"
 	packages_module "code.gitea.io/gitea/modules/packages"
@@ -20,7 +21,10 @@ import (
 	cargo_service "code.gitea.io/gitea/services/packages/cargo"
 	container_service "code.gitea.io/gitea/services/packages/container"
 	debian_service "code.gitea.io/gitea/services/packages/debian"
+	hex_service "code.gitea.io/gitea/services/packages/hex"
 	rpm_service "code.gitea.io/gitea/services/packages/rpm"
+
+	"github.com/hashicorp/go-version"
 )
 
 // CleanupTask executes cleanup rules and cleanup expired package data
@@ -32,7 +36,8 @@ func CleanupTask(ctx context.Context, olderThan time.Duration) error {
 	return CleanupExpiredData(ctx, olderThan)
 }
 
-func executeCleanupOneRulePackage(ctx context.Context, pcr *packages_model.PackageCleanupRule, p *packages_model.Package) (versionDeleted bool, err error) {
+// getVersionsToRemove returns the versions of the package which get removed by the rule
+func getVersionsToRemove(ctx context.Context, pcr *packages_model.PackageCleanupRule, p *packages_model.Package) ([]*packages_model.PackageVersion, error) {
 	olderThan := time.Now().AddDate(0, 0, -pcr.RemoveDays)
 	pvs, _, err := packages_model.SearchVersions(ctx, &packages_model.PackageSearchOptions{
 		PackageID:  p.ID,
@@ -40,8 +45,14 @@ func executeCleanupOneRulePackage(ctx context.Context, pcr *packages_model.Packa
 		Sort:       packages_model.SortCreatedDesc,
 	})
 	if err != nil {
-		return false, fmt.Errorf("CleanupRule [%d]: SearchVersions failed: %w", pcr.ID, err)
+		return nil, fmt.Errorf("CleanupRule [%d]: SearchVersions failed: %w", pcr.ID, err)
+	}
+
+	var latestPerMajor container.Set[int64]
+	if pcr.KeepLatestPerMajor {
+		latestPerMajor = getLatestVersionPerMajor(pvs)
 	}
+
 	if pcr.KeepCount > 0 {
 		if pcr.KeepCount < len(pvs) {
 			pvs = pvs[pcr.KeepCount:]
@@ -49,10 +60,12 @@ func executeCleanupOneRulePackage(ctx context.Context, pcr *packages_model.Packa
 			pvs = nil
 		}
 	}
+
+	removed := make([]*packages_model.PackageVersion, 0, len(pvs))
 	for _, pv := range pvs {
 		if pcr.Type == packages_model.TypeContainer {
 			if skip, err := container_service.ShouldBeSkipped(ctx, pcr, p, pv); err != nil {
-				return false, fmt.Errorf("CleanupRule [%d]: container.ShouldBeSkipped failed: %w", pcr.ID, err)
+				return nil, fmt.Errorf("CleanupRule [%d]: container.ShouldBeSkipped failed: %w", pcr.ID, err)
 			} else if skip {
 				log.Debug("Rule[%d]: keep '%s/%s' (container)", pcr.ID, p.Name, pv.Version)
 				continue
@@ -66,6 +79,16 @@ func executeCleanupOneRulePackage(ctx context.Context, pcr *packages_model.Packa
 			log.Debug("Rule[%d]: keep '%s/%s' (keep pattern)", pcr.ID, p.Name, pv.Version)
 			continue
 		}
+		if pcr.KeepSemverMatcher != nil {
+			if v, err := version.NewVersion(pv.Version); err == nil && pcr.KeepSemverMatcher.Check(v) {
+				log.Debug("Rule[%d]: keep '%s/%s' (semver range)", pcr.ID, p.Name, pv.Version)
+				continue
+			}
+		}
+		if latestPerMajor.Contains(pv.ID) {
+			log.Debug("Rule[%d]: keep '%s/%s' (latest per major)", pcr.ID, p.Name, pv.Version)
+			continue
+		}
 		if pv.CreatedUnix.AsLocalTime().After(olderThan) {
 			log.Debug("Rule[%d]: keep '%s/%s' (remove days) %v", pcr.ID, p.Name, pv.Version, pv.CreatedUnix.FormatDate())
 			continue
@@ -75,13 +98,72 @@ func executeCleanupOneRulePackage(ctx context.Context, pcr *packages_model.Packa
 			continue
 		}
 		log.Debug("Rule[%d]: remove '%s/%s'", pcr.ID, p.Name, pv.Version)
+		removed = append(removed, pv)
+	}
+
+	if pcr.Type == packages_model.TypeContainer && len(removed) > 0 {
+		removed, err = container_service.ResolveManifestReferences(ctx, p, removed)
+		if err != nil {
+			return nil, fmt.Errorf("CleanupRule [%d]: container.ResolveManifestReferences failed: %w", pcr.ID, err)
+		}
+	}
+
+	return removed, nil
+}
+
+// getLatestVersionPerMajor returns the ids of the highest stable version of every major version.
+// Versions which are not valid semantic versions are ignored.
+func getLatestVersionPerMajor(pvs []*packages_model.PackageVersion) container.Set[int64] {
+	type latestVersion struct {
+		id      int64
+		version *version.Version
+	}
+
+	latest := make(map[int64]*latestVersion)
+	for _, pv := range pvs {
+		v, err := version.NewVersion(pv.Version)
+		if err != nil || v.Prerelease() != "" {
+			continue
+		}
+		major := v.Segments64()[0]
+		if l, ok := latest[major]; !ok || v.GreaterThan(l.version) {
+			latest[major] = &latestVersion{id: pv.ID, version: v}
+		}
+	}
+
+	ids := make(container.Set[int64], len(latest))
+	for _, l := range latest {
+		ids.Add(l.id)
+	}
+	return ids
+}
+
+func executeCleanupOneRulePackage(ctx context.Context, pcr *packages_model.PackageCleanupRule, p *packages_model.Package) ([]*packages_model.PackageCleanupRunVersion, error) {
+	pvs, err := getVersionsToRemove(ctx, pcr, p)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := make([]*packages_model.PackageCleanupRunVersion, 0, len(pvs))
+	for _, pv := range pvs {
+		size, err := packages_model.CalculateFileSize(ctx, &packages_model.PackageFileSearchOptions{
+			VersionID: pv.ID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("CleanupRule [%d]: CalculateFileSize failed: %w", pcr.ID, err)
+		}
+
 		if err := packages_service.DeletePackageVersionAndReferences(ctx, pv); err != nil {
 			log.Error("CleanupRule [%d]: DeletePackageVersionAndReferences failed: %v", pcr.ID, err)
 			continue
 		}
-		versionDeleted = true
+		deleted = append(deleted, &packages_model.PackageCleanupRunVersion{
+			PackageName: p.Name,
+			Version:     pv.Version,
+			Size:        size,
+		})
 	}
-	return versionDeleted, nil
+	return deleted, nil
 }
 
 func executeCleanupOneRule(ctx context.Context, pcr *packages_model.PackageCleanupRule) error {
@@ -94,19 +176,19 @@ func executeCleanupOneRule(ctx context.Context, pcr *packages_model.PackageClean
 		return fmt.Errorf("CleanupRule [%d]: GetPackagesByType failed: %w", pcr.ID, err)
 	}
 
-	anyVersionDeleted := false
+	deletedVersions := make([]*packages_model.PackageCleanupRunVersion, 0, 10)
 	for _, p := range packages {
-		versionDeleted := false
+		var deleted []*packages_model.PackageCleanupRunVersion
 		err = db.WithTx(ctx, func(ctx context.Context) (err error) {
-			versionDeleted, err = executeCleanupOneRulePackage(ctx, pcr, p)
+			deleted, err = executeCleanupOneRulePackage(ctx, pcr, p)
 			return err
 		})
 		if err != nil {
 			log.Error("CleanupRule [%d]: executeCleanupOneRulePackage(%d) failed: %v", pcr.ID, p.ID, err)
 			continue
 		}
-		anyVersionDeleted = anyVersionDeleted || versionDeleted
-		if versionDeleted {
+		deletedVersions = append(deletedVersions, deleted...)
+		if len(deleted) > 0 {
 			if pcr.Type == packages_model.TypeCargo {
 				owner, err := user_model.GetUserByID(ctx, pcr.OwnerID)
 				if err != nil {
@@ -119,35 +201,77 @@ func executeCleanupOneRule(ctx context.Context, pcr *packages_model.PackageClean
 		}
 	}
 
-	if anyVersionDeleted {
-		switch pcr.Type {
-		case packages_model.TypeDebian:
-			if err := debian_service.BuildAllRepositoryFiles(ctx, pcr.OwnerID); err != nil {
-				return fmt.Errorf("CleanupRule [%d]: debian.BuildAllRepositoryFiles failed: %w", pcr.ID, err)
-			}
-		case packages_model.TypeAlpine:
-			if err := alpine_service.BuildAllRepositoryFiles(ctx, pcr.OwnerID); err != nil {
-				return fmt.Errorf("CleanupRule [%d]: alpine.BuildAllRepositoryFiles failed: %w", pcr.ID, err)
-			}
-		case packages_model.TypeRpm:
-			if err := rpm_service.BuildAllRepositoryFiles(ctx, pcr.OwnerID); err != nil {
-				return fmt.Errorf("CleanupRule [%d]: rpm.BuildAllRepositoryFiles failed: %w", pcr.ID, err)
-			}
-		case packages_model.TypeArch:
-			release, err := arch_service.AquireRegistryLock(ctx, pcr.OwnerID)
-			if err != nil {
-				return err
-			}
-			defer release()
+	if len(deletedVersions) == 0 {
+		return nil
+	}
 
-			if err := arch_service.BuildAllRepositoryFiles(ctx, pcr.OwnerID); err != nil {
-				return fmt.Errorf("CleanupRule [%d]: arch.BuildAllRepositoryFiles failed: %w", pcr.ID, err)
-			}
+	if err := packages_model.InsertCleanupRun(ctx, &packages_model.PackageCleanupRun{
+		RuleID:  pcr.ID,
+		OwnerID: pcr.OwnerID,
+		Type:    pcr.Type,
+	}, deletedVersions); err != nil {
+		return fmt.Errorf("CleanupRule [%d]: InsertCleanupRun failed: %w", pcr.ID, err)
+	}
+
+	switch pcr.Type {
+	case packages_model.TypeDebian:
+		if err := debian_service.BuildAllRepositoryFiles(ctx, pcr.OwnerID); err != nil {
+			return fmt.Errorf("CleanupRule [%d]: debian.BuildAllRepositoryFiles failed: %w", pcr.ID, err)
+		}
+	case packages_model.TypeAlpine:
+		if err := alpine_service.BuildAllRepositoryFiles(ctx, pcr.OwnerID); err != nil {
+			return fmt.Errorf("CleanupRule [%d]: alpine.BuildAllRepositoryFiles failed: %w", pcr.ID, err)
+		}
+	case packages_model.TypeRpm:
+		if err := rpm_service.BuildAllRepositoryFiles(ctx, pcr.OwnerID); err != nil {
+			return fmt.Errorf("CleanupRule [%d]: rpm.BuildAllRepositoryFiles failed: %w", pcr.ID, err)
+		}
+	case packages_model.TypeArch:
+		release, err := arch_service.AquireRegistryLock(ctx, pcr.OwnerID)
+		if err != nil {
+			return err
+		}
+		defer release()
+
+		if err := arch_service.BuildAllRepositoryFiles(ctx, pcr.OwnerID); err != nil {
+			return fmt.Errorf("CleanupRule [%d]: arch.BuildAllRepositoryFiles failed: %w", pcr.ID, err)
+		}
+	case packages_model.TypeHex:
+		if err := hex_service.BuildAllRepositoryFiles(ctx, pcr.OwnerID); err != nil {
+			return fmt.Errorf("CleanupRule [%d]: hex.BuildAllRepositoryFiles failed: %w", pcr.ID, err)
 		}
 	}
 	return nil
 }
 
+// PreviewCleanupRule returns the package versions which would be removed if the rule is executed now
+func PreviewCleanupRule(ctx context.Context, pcr *packages_model.PackageCleanupRule) ([]*packages_model.PackageDescriptor, error) {
+	if err := pcr.CompiledPattern(); err != nil {
+		return nil, fmt.Errorf("CleanupRule [%d]: CompilePattern failed: %w", pcr.ID, err)
+	}
+
+	packages, err := packages_model.GetPackagesByType(ctx, pcr.OwnerID, pcr.Type)
+	if err != nil {
+		return nil, fmt.Errorf("CleanupRule [%d]: GetPackagesByType failed: %w", pcr.ID, err)
+	}
+
+	pds := make([]*packages_model.PackageDescriptor, 0, 10)
+	for _, p := range packages {
+		pvs, err := getVersionsToRemove(ctx, pcr, p)
+		if err != nil {
+			return nil, err
+		}
+		for _, pv := range pvs {
+			pd, err := packages_model.GetPackageDescriptor(ctx, pv)
+			if err != nil {
+				return nil, fmt.Errorf("CleanupRule [%d]: GetPackageDescriptor failed: %w", pcr.ID, err)
+			}
+			pds = append(pds, pd)
+		}
+	}
+	return pds, nil
+}
+
 func ExecuteCleanupRules(ctx context.Context) error {
 	return packages_model.IterateEnabledCleanupRules(ctx, func(ctx context.Context, pcr *packages_model.PackageCleanupRule) error {
 		select {
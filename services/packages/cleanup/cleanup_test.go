@@ -0,0 +1,24 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package container
+
+import (
+	"testing"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetLatestVersionPerMajor(t *testing.T) {
+	versions := []string{"1.0.0", "1.2.0", "1.10.0", "v2.0.0", "2.1.0-rc1", "3.0.0-beta", "latest", "0.9.1", "0.10.0"}
+
+	pvs := make([]*packages_model.PackageVersion, 0, len(versions))
+	for i, v := range versions {
+		pvs = append(pvs, &packages_model.PackageVersion{ID: int64(i + 1), Version: v})
+	}
+
+	latest := getLatestVersionPerMajor(pvs)
+	assert.ElementsMatch(t, []int64{3, 4, 9}, latest.Values())
+}
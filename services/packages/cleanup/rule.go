@@ -0,0 +1,58 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package container
+
+import (
+	"context"
+	"slices"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// CreateRule validates and inserts a new cleanup rule. An owner can only have one rule per package type.
+func CreateRule(ctx context.Context, pcr *packages_model.PackageCleanupRule) (*packages_model.PackageCleanupRule, error) {
+	if !slices.Contains(packages_model.TypeList, pcr.Type) {
+		return nil, util.NewInvalidArgumentErrorf("package type %q is invalid", pcr.Type)
+	}
+	if err := validateRule(pcr); err != nil {
+		return nil, err
+	}
+
+	has, err := packages_model.HasOwnerCleanupRuleForPackageType(ctx, pcr.OwnerID, pcr.Type)
+	if err != nil {
+		return nil, err
+	}
+	if has {
+		return nil, util.NewAlreadyExistErrorf("a cleanup rule for package type %q exists already", pcr.Type)
+	}
+
+	return packages_model.InsertCleanupRule(ctx, pcr)
+}
+
+// UpdateRule validates and updates a cleanup rule
+func UpdateRule(ctx context.Context, pcr *packages_model.PackageCleanupRule) error {
+	if err := validateRule(pcr); err != nil {
+		return err
+	}
+	return packages_model.UpdateCleanupRule(ctx, pcr)
+}
+
+func validateRule(pcr *packages_model.PackageCleanupRule) error {
+	if pcr.KeepCount < 0 {
+		return util.NewInvalidArgumentErrorf("keep count must not be negative")
+	}
+	if pcr.RemoveDays < 0 {
+		return util.NewInvalidArgumentErrorf("remove days must not be negative")
+	}
+
+	// the settings may have changed since the patterns were compiled
+	pcr.KeepPatternMatcher = nil
+	pcr.KeepSemverMatcher = nil
+	pcr.RemovePatternMatcher = nil
+	if err := pcr.CompiledPattern(); err != nil {
+		return util.NewInvalidArgumentErrorf("invalid pattern: %v", err)
+	}
+	return nil
+}
@@ -5,10 +5,12 @@ package container
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	packages_model "code.gitea.io/gitea/models/packages"
 	container_model "code.gitea.io/gitea/models/packages/container"
+	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/optional"
 	container_module "code.gitea.io/gitea/modules/packages/container"
 	packages_service "code.gitea.io/gitea/services/packages"
@@ -79,30 +81,132 @@ func cleanupExpiredUploadedBlobs(ctx context.Context, olderThan time.Duration) e
 	return nil
 }
 
+// ShouldBeSkipped checks if a version must be kept by a cleanup rule regardless of its settings.
+// Manifests referenced by image indexes are handled by ResolveManifestReferences.
 func ShouldBeSkipped(ctx context.Context, pcr *packages_model.PackageCleanupRule, p *packages_model.Package, pv *packages_model.PackageVersion) (bool, error) {
 	// Always skip the "latest" tag
 	if pv.LowerVersion == "latest" {
 		return true, nil
 	}
 
-	// Check if the version is a digest (or untagged)
-	if digest.Digest(pv.LowerVersion).Validate() == nil {
-		// Check if there is another manifest referencing this version
-		has, err := packages_model.ExistVersion(ctx, &packages_model.PackageSearchOptions{
-			PackageID: p.ID,
-			Properties: map[string]string{
-				container_module.PropertyManifestReference: pv.LowerVersion,
-			},
+	// Check if the version is a referrer (signature, SBOM, ...) of another manifest
+	subjects, err := packages_model.GetPropertiesByName(ctx, packages_model.PropertyTypeVersion, pv.ID, container_module.PropertyManifestSubject)
+	if err != nil {
+		return false, err
+	}
+	for _, subject := range subjects {
+		// Skip it as long as the subject exists
+		_, err := container_model.GetContainerBlob(ctx, &container_model.BlobSearchOptions{
+			OwnerID:    p.OwnerID,
+			Image:      p.LowerName,
+			Digest:     subject.Value,
+			IsManifest: true,
 		})
+		if err == nil {
+			return true, nil
+		} else if !errors.Is(err, container_model.ErrContainerBlobNotExist) {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
+
+// ResolveManifestReferences adjusts the versions selected for removal to keep multi-arch images intact.
+// Manifests referenced by an image index which is kept are kept too. Untagged manifests which are
+// only referenced by removed image indexes are removed together with them because they are not reachable anymore.
+func ResolveManifestReferences(ctx context.Context, p *packages_model.Package, pvs []*packages_model.PackageVersion) ([]*packages_model.PackageVersion, error) {
+	selectedIDs := make(container.Set[int64], len(pvs))
+	for _, pv := range pvs {
+		selectedIDs.Add(pv.ID)
+	}
+
+	removed := make([]*packages_model.PackageVersion, 0, len(pvs))
+	removedIDs := make(container.Set[int64], len(pvs))
+	for _, pv := range pvs {
+		referenced, err := isReferencedByIndex(ctx, p, pv, selectedIDs)
+		if err != nil {
+			return nil, err
+		}
+		if !referenced {
+			removed = append(removed, pv)
+			removedIDs.Add(pv.ID)
+		}
+	}
+
+	// image indexes can't reference other indexes, so the appended children don't need to be visited
+	for _, pv := range removed {
+		references, err := packages_model.GetPropertiesByName(ctx, packages_model.PropertyTypeVersion, pv.ID, container_module.PropertyManifestReference)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, reference := range references {
+			children, err := container_model.GetManifestVersions(ctx, &container_model.BlobSearchOptions{
+				OwnerID:    p.OwnerID,
+				Image:      p.LowerName,
+				Digest:     reference.Value,
+				IsManifest: true,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			for _, child := range children {
+				// Tagged manifests are still reachable and are handled by the rule itself
+				if removedIDs.Contains(child.ID) || digest.Digest(child.LowerVersion).Validate() != nil {
+					continue
+				}
+
+				if referenced, err := isReferencedByIndex(ctx, p, child, removedIDs); err != nil {
+					return nil, err
+				} else if referenced {
+					continue
+				}
+
+				removed = append(removed, child)
+				removedIDs.Add(child.ID)
+			}
+		}
+	}
+
+	return removed, nil
+}
+
+// isReferencedByIndex checks if an image index which is not in ignored references the manifest of the version
+func isReferencedByIndex(ctx context.Context, p *packages_model.Package, pv *packages_model.PackageVersion, ignored container.Set[int64]) (bool, error) {
+	manifestDigest := pv.LowerVersion
+	if digest.Digest(manifestDigest).Validate() != nil {
+		// A tagged manifest is referenced by its digest which is the key of the current manifest file
+		manifestDigest = ""
+		pfs, err := packages_model.GetFilesByVersionID(ctx, pv.ID)
 		if err != nil {
 			return false, err
 		}
+		for _, pf := range pfs {
+			if pf.IsLead && pf.LowerName == container_module.ManifestFilename {
+				manifestDigest = pf.CompositeKey
+				break
+			}
+		}
+		if manifestDigest == "" {
+			return false, nil
+		}
+	}
 
-		// Skip it if the version is referenced
-		if has {
+	pvs, _, err := packages_model.SearchVersions(ctx, &packages_model.PackageSearchOptions{
+		PackageID: p.ID,
+		Properties: map[string]string{
+			container_module.PropertyManifestReference: manifestDigest,
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, index := range pvs {
+		if index.ID != pv.ID && !ignored.Contains(index.ID) {
 			return true, nil
 		}
 	}
-
 	return false, nil
 }
@@ -0,0 +1,344 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package hex
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/globallock"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	hex_module "code.gitea.io/gitea/modules/packages/hex"
+	"code.gitea.io/gitea/modules/util"
+	packages_service "code.gitea.io/gitea/services/packages"
+)
+
+// https://github.com/hexpm/specifications/blob/main/registry-v2.md
+
+const (
+	NamesFilename    = "names"
+	VersionsFilename = "versions"
+	// PackageCompositeKey distinguishes the resources of the packages from the lists of all packages
+	PackageCompositeKey = "package"
+)
+
+// RepositoryName returns the name of the Hex repository of the owner.
+// Clients verify that the resources belong to the repository they were added with.
+func RepositoryName(owner *user_model.User) string {
+	return owner.Name
+}
+
+// GetOrCreateKeyPair gets or creates the RSA keys used to sign the registry resources
+func GetOrCreateKeyPair(ctx context.Context, ownerID int64) (string, string, error) {
+	priv, err := user_model.GetSetting(ctx, ownerID, hex_module.SettingKeyPrivate)
+	if err != nil && !errors.Is(err, util.ErrNotExist) {
+		return "", "", err
+	}
+
+	pub, err := user_model.GetSetting(ctx, ownerID, hex_module.SettingKeyPublic)
+	if err != nil && !errors.Is(err, util.ErrNotExist) {
+		return "", "", err
+	}
+
+	if priv == "" || pub == "" {
+		priv, pub, err = util.GenerateKeyPair(4096)
+		if err != nil {
+			return "", "", err
+		}
+
+		if err := user_model.SetUserSetting(ctx, ownerID, hex_module.SettingKeyPrivate, priv); err != nil {
+			return "", "", err
+		}
+
+		if err := user_model.SetUserSetting(ctx, ownerID, hex_module.SettingKeyPublic, pub); err != nil {
+			return "", "", err
+		}
+	}
+
+	return priv, pub, nil
+}
+
+// AcquireRegistryLock acquires a lock for the owner so the resources of the registry are built one at a time
+func AcquireRegistryLock(ctx context.Context, ownerID int64) (globallock.ReleaseFunc, error) {
+	return globallock.Lock(ctx, fmt.Sprintf("packages_hex_%d", ownerID))
+}
+
+// GetOrCreateRepositoryVersion gets or creates the internal repository package
+// The Hex registry stores the signed resources in this package, so they don't need to be built on every request.
+func GetOrCreateRepositoryVersion(ctx context.Context, ownerID int64) (*packages_model.PackageVersion, error) {
+	return packages_service.GetOrCreateInternalPackageVersion(ctx, ownerID, packages_model.TypeHex, hex_module.RepositoryPackage, hex_module.RepositoryVersion)
+}
+
+// PackageFileInfo returns the file info of the stored /packages/<name> resource
+func PackageFileInfo(name string) *packages_service.PackageFileInfo {
+	return &packages_service.PackageFileInfo{
+		Filename:     name,
+		CompositeKey: PackageCompositeKey,
+	}
+}
+
+// BuildAllRepositoryFiles (re)builds the signed resources of all packages of the owner
+func BuildAllRepositoryFiles(ctx context.Context, ownerID int64) error {
+	release, err := AcquireRegistryLock(ctx, ownerID)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	owner, err := user_model.GetUserByID(ctx, ownerID)
+	if err != nil {
+		return err
+	}
+	pv, err := GetOrCreateRepositoryVersion(ctx, ownerID)
+	if err != nil {
+		return err
+	}
+
+	// 1. Delete all existing resources
+	pfs, err := packages_model.GetFilesByVersionID(ctx, pv.ID)
+	if err != nil {
+		return err
+	}
+	for _, pf := range pfs {
+		if err := packages_service.DeletePackageFile(ctx, pf); err != nil {
+			return err
+		}
+	}
+
+	// 2. (Re)Build the resources of the existing packages
+	releases, err := loadAllReleases(ctx, owner)
+	if err != nil {
+		return err
+	}
+	for _, pds := range releases {
+		if err := buildPackage(ctx, owner, pv, pds); err != nil {
+			return fmt.Errorf("failed to build the resource of package %s: %w", pds[0].Package.Name, err)
+		}
+	}
+	return buildLists(ctx, owner, pv, releases)
+}
+
+// BuildSpecificRepositoryFiles (re)builds the signed resource of the package and the lists of all packages of the owner.
+// The resource of the package is deleted if it has no versions anymore.
+func BuildSpecificRepositoryFiles(ctx context.Context, ownerID int64, packageName string) error {
+	release, err := AcquireRegistryLock(ctx, ownerID)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	owner, err := user_model.GetUserByID(ctx, ownerID)
+	if err != nil {
+		return err
+	}
+	pv, err := GetOrCreateRepositoryVersion(ctx, ownerID)
+	if err != nil {
+		return err
+	}
+
+	releases, err := loadAllReleases(ctx, owner)
+	if err != nil {
+		return err
+	}
+
+	idx := slices.IndexFunc(releases, func(pds []*packages_model.PackageDescriptor) bool {
+		return strings.EqualFold(pds[0].Package.Name, packageName)
+	})
+	if idx == -1 {
+		pfi := PackageFileInfo(packageName)
+		pf, err := packages_model.GetFileForVersionByName(ctx, pv.ID, pfi.Filename, pfi.CompositeKey)
+		if err != nil && !errors.Is(err, util.ErrNotExist) {
+			return err
+		}
+		if pf != nil {
+			if err := packages_service.DeletePackageFile(ctx, pf); err != nil {
+				return err
+			}
+		}
+	} else if err := buildPackage(ctx, owner, pv, releases[idx]); err != nil {
+		return err
+	}
+
+	return buildLists(ctx, owner, pv, releases)
+}
+
+// buildLists builds the signed /names and /versions resources
+func buildLists(ctx context.Context, owner *user_model.User, repoVersion *packages_model.PackageVersion, releases [][]*packages_model.PackageDescriptor) error {
+	names := make([]*hex_module.NamesEntry, 0, len(releases))
+	versions := make([]*hex_module.VersionsEntry, 0, len(releases))
+	for _, pds := range releases {
+		namesEntry := &hex_module.NamesEntry{
+			Name: pds[0].Package.Name,
+		}
+		versionsEntry := &hex_module.VersionsEntry{
+			Name:     pds[0].Package.Name,
+			Versions: make([]string, 0, len(pds)),
+		}
+		for i, pd := range pds {
+			if t := pd.Version.CreatedUnix.AsTime(); t.After(namesEntry.UpdatedAt) {
+				namesEntry.UpdatedAt = t
+			}
+			versionsEntry.Versions = append(versionsEntry.Versions, pd.Version.Version)
+			if retirementFromDescriptor(pd) != nil {
+				versionsEntry.Retired = append(versionsEntry.Retired, i)
+			}
+		}
+		names = append(names, namesEntry)
+		versions = append(versions, versionsEntry)
+	}
+
+	if err := signAndStore(ctx, owner, repoVersion, &packages_service.PackageFileInfo{Filename: NamesFilename}, hex_module.EncodeNames(RepositoryName(owner), names)); err != nil {
+		return err
+	}
+	return signAndStore(ctx, owner, repoVersion, &packages_service.PackageFileInfo{Filename: VersionsFilename}, hex_module.EncodeVersions(RepositoryName(owner), versions))
+}
+
+// buildPackage builds the signed /packages/<name> resource with all releases of the package
+func buildPackage(ctx context.Context, owner *user_model.User, repoVersion *packages_model.PackageVersion, pds []*packages_model.PackageDescriptor) error {
+	releases := make([]*hex_module.Release, 0, len(pds))
+	for _, pd := range pds {
+		innerChecksum, err := hex.DecodeString(pd.VersionProperties.GetByName(hex_module.PropertyInnerChecksum))
+		if err != nil {
+			return err
+		}
+		outerChecksum, err := hex.DecodeString(pd.Files[0].Blob.HashSHA256)
+		if err != nil {
+			return err
+		}
+
+		releases = append(releases, &hex_module.Release{
+			Version:       pd.Version.Version,
+			InnerChecksum: innerChecksum,
+			OuterChecksum: outerChecksum,
+			Requirements:  pd.Metadata.(*hex_module.Metadata).Requirements,
+			Retired:       retirementFromDescriptor(pd),
+		})
+	}
+
+	p := pds[0].Package
+	return signAndStore(ctx, owner, repoVersion, PackageFileInfo(p.Name), hex_module.EncodePackage(RepositoryName(owner), p.Name, releases))
+}
+
+// RetireRelease marks the package version as retired.
+// Retired releases are still available but clients warn when resolving them.
+func RetireRelease(ctx context.Context, pv *packages_model.PackageVersion, retirement *hex_module.Retirement) error {
+	if err := packages_model.InsertOrUpdateProperty(ctx, packages_model.PropertyTypeVersion, pv.ID, hex_module.PropertyRetiredReason, retirement.Reason.String()); err != nil {
+		return err
+	}
+	if retirement.Message == "" {
+		return packages_model.DeletePropertiesByName(ctx, packages_model.PropertyTypeVersion, pv.ID, hex_module.PropertyRetiredMessage)
+	}
+	return packages_model.InsertOrUpdateProperty(ctx, packages_model.PropertyTypeVersion, pv.ID, hex_module.PropertyRetiredMessage, retirement.Message)
+}
+
+// UnretireRelease removes the retirement of the package version
+func UnretireRelease(ctx context.Context, pv *packages_model.PackageVersion) error {
+	if err := packages_model.DeletePropertiesByName(ctx, packages_model.PropertyTypeVersion, pv.ID, hex_module.PropertyRetiredReason); err != nil {
+		return err
+	}
+	return packages_model.DeletePropertiesByName(ctx, packages_model.PropertyTypeVersion, pv.ID, hex_module.PropertyRetiredMessage)
+}
+
+func retirementFromDescriptor(pd *packages_model.PackageDescriptor) *hex_module.Retirement {
+	reason, err := hex_module.ParseRetirementReason(pd.VersionProperties.GetByName(hex_module.PropertyRetiredReason))
+	if err != nil {
+		return nil
+	}
+	return &hex_module.Retirement{
+		Reason:  reason,
+		Message: pd.VersionProperties.GetByName(hex_module.PropertyRetiredMessage),
+	}
+}
+
+// loadAllReleases returns the releases of all packages of the owner, ordered by package name
+func loadAllReleases(ctx context.Context, owner *user_model.User) ([][]*packages_model.PackageDescriptor, error) {
+	ps, err := packages_model.GetPackagesByType(ctx, owner.ID, packages_model.TypeHex)
+	if err != nil {
+		return nil, fmt.Errorf("GetPackagesByType: %w", err)
+	}
+
+	sort.Slice(ps, func(i, j int) bool {
+		return ps[i].LowerName < ps[j].LowerName
+	})
+
+	releases := make([][]*packages_model.PackageDescriptor, 0, len(ps))
+	for _, p := range ps {
+		pds, err := loadReleases(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		if len(pds) > 0 {
+			releases = append(releases, pds)
+		}
+	}
+	return releases, nil
+}
+
+func loadReleases(ctx context.Context, p *packages_model.Package) ([]*packages_model.PackageDescriptor, error) {
+	pvs, _, err := packages_model.SearchVersions(ctx, &packages_model.PackageSearchOptions{
+		PackageID: p.ID,
+		Sort:      packages_model.SortVersionAsc,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("SearchVersions[%s]: %w", p.Name, err)
+	}
+
+	pds, err := packages_model.GetPackageDescriptors(ctx, pvs)
+	if err != nil {
+		return nil, fmt.Errorf("GetPackageDescriptors[%s]: %w", p.Name, err)
+	}
+	return pds, nil
+}
+
+// signAndStore signs the payload and stores it in the repository package
+func signAndStore(ctx context.Context, owner *user_model.User, repoVersion *packages_model.PackageVersion, pfi *packages_service.PackageFileInfo, payload []byte) error {
+	priv, _, err := GetOrCreateKeyPair(ctx, owner.ID)
+	if err != nil {
+		return err
+	}
+
+	block, _ := pem.Decode([]byte(priv))
+	if block == nil {
+		return errors.New("failed to decode private key pem")
+	}
+
+	privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return err
+	}
+
+	data, err := hex_module.SignAndCompress(privateKey, payload)
+	if err != nil {
+		return err
+	}
+
+	buf, err := packages_module.CreateHashedBufferFromReader(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer buf.Close()
+
+	_, err = packages_service.AddFileToPackageVersionInternal(
+		ctx,
+		repoVersion,
+		&packages_service.PackageFileCreationInfo{
+			PackageFileInfo:   *pfi,
+			Creator:           user_model.NewGhostUser(),
+			Data:              buf,
+			IsLead:            false,
+			OverwriteExisting: true,
+		},
+	)
+	return err
+}
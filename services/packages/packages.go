@@ -13,6 +13,7 @@ import (
 	"net/url"
 	"strings"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
 	packages_model "code.gitea.io/gitea/models/packages"
 	repo_model "code.gitea.io/gitea/models/repo"
@@ -350,6 +351,50 @@ func CheckSizeQuotaExceeded(ctx context.Context, doer, owner *user_model.User, p
 		return nil
 	}
 
+	if typeSpecificSize := getTypeSpecificSizeLimit(packageType); typeSpecificSize > -1 && typeSpecificSize < uploadSize {
+		return ErrQuotaTypeSize
+	}
+
+	if setting.Packages.LimitTotalOwnerSize > -1 {
+		totalSize, err := packages_model.CalculateFileSize(ctx, &packages_model.PackageFileSearchOptions{
+			OwnerID: owner.ID,
+		})
+		if err != nil {
+			log.Error("CalculateFileSize failed: %v", err)
+			return err
+		}
+		if totalSize+uploadSize > setting.Packages.LimitTotalOwnerSize {
+			return ErrQuotaTotalSize
+		}
+	}
+
+	return nil
+}
+
+// GetMaxUploadSize returns the maximum size of a file which the doer can upload for the owner, -1 if the size isn't limited.
+// The size isn't limited if the doer is an admin.
+func GetMaxUploadSize(ctx context.Context, doer *user_model.User, ownerID int64, packageType packages_model.Type) (int64, error) {
+	if doer.IsAdmin {
+		return -1, nil
+	}
+
+	maxSize := getTypeSpecificSizeLimit(packageType)
+	if setting.Packages.LimitTotalOwnerSize > -1 {
+		totalSize, err := packages_model.CalculateFileSize(ctx, &packages_model.PackageFileSearchOptions{
+			OwnerID: ownerID,
+		})
+		if err != nil {
+			return 0, err
+		}
+		remaining := max(setting.Packages.LimitTotalOwnerSize-totalSize, 0)
+		if maxSize == -1 || remaining < maxSize {
+			maxSize = remaining
+		}
+	}
+	return maxSize, nil
+}
+
+func getTypeSpecificSizeLimit(packageType packages_model.Type) int64 {
 	var typeSpecificSize int64
 	switch packageType {
 	case packages_model.TypeAlpine:
@@ -378,6 +423,8 @@ func CheckSizeQuotaExceeded(ctx context.Context, doer, owner *user_model.User, p
 		typeSpecificSize = setting.Packages.LimitSizeGo
 	case packages_model.TypeHelm:
 		typeSpecificSize = setting.Packages.LimitSizeHelm
+	case packages_model.TypeHex:
+		typeSpecificSize = setting.Packages.LimitSizeHex
 	case packages_model.TypeMaven:
 		typeSpecificSize = setting.Packages.LimitSizeMaven
 	case packages_model.TypeNpm:
@@ -394,27 +441,12 @@ func CheckSizeQuotaExceeded(ctx context.Context, doer, owner *user_model.User, p
 		typeSpecificSize = setting.Packages.LimitSizeRubyGems
 	case packages_model.TypeSwift:
 		typeSpecificSize = setting.Packages.LimitSizeSwift
+	case packages_model.TypeTerraform:
+		typeSpecificSize = setting.Packages.LimitSizeTerraform
 	case packages_model.TypeVagrant:
 		typeSpecificSize = setting.Packages.LimitSizeVagrant
 	}
-	if typeSpecificSize > -1 && typeSpecificSize < uploadSize {
-		return ErrQuotaTypeSize
-	}
-
-	if setting.Packages.LimitTotalOwnerSize > -1 {
-		totalSize, err := packages_model.CalculateFileSize(ctx, &packages_model.PackageFileSearchOptions{
-			OwnerID: owner.ID,
-		})
-		if err != nil {
-			log.Error("CalculateFileSize failed: %v", err)
-			return err
-		}
-		if totalSize+uploadSize > setting.Packages.LimitTotalOwnerSize {
-			return ErrQuotaTotalSize
-		}
-	}
-
-	return nil
+	return typeSpecificSize
 }
 
 // GetOrCreateInternalPackageVersion gets or creates an internal package
@@ -553,6 +585,10 @@ func DeletePackageVersionAndReferences(ctx context.Context, pv *packages_model.P
 		}
 	}
 
+	if err := actions_model.DetachAttestationsFromPackageVersion(ctx, pv.ID); err != nil {
+		return err
+	}
+
 	return packages_model.DeleteVersionByID(ctx, pv.ID)
 }
 
@@ -0,0 +1,277 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package remote
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/hostmatcher"
+	"code.gitea.io/gitea/modules/json"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	"code.gitea.io/gitea/modules/proxy"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+	packages_service "code.gitea.io/gitea/services/packages"
+)
+
+// maximum size of a metadata document fetched from a remote
+const maxMetadataSize = 64 * 1024 * 1024
+
+var (
+	ErrRemoteNotExist     = util.NewNotExistErrorf("package does not exist at the remote")
+	ErrMetadataTooLarge   = util.NewInvalidArgumentErrorf("metadata of the remote is too large")
+	challengeParamMatcher = regexp.MustCompile(`(\w+)="([^"]*)"`)
+)
+
+// Client requests the content of packages from a remote
+type Client struct {
+	remote   *packages_model.PackageRemote
+	baseURL  string
+	host     string
+	password string
+	token    string
+	client   *http.Client
+}
+
+// NewClient creates a client for the remote
+func NewClient(pr *packages_model.PackageRemote) (*Client, error) {
+	u, err := url.Parse(pr.URL)
+	if err != nil {
+		return nil, err
+	}
+	password, err := pr.Password()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		remote:   pr,
+		baseURL:  strings.TrimSuffix(pr.URL, "/"),
+		host:     u.Host,
+		password: password,
+		client:   remoteHTTPClient(),
+	}, nil
+}
+
+// remoteHTTPClient is shared by all clients to reuse the connections to the remotes
+var remoteHTTPClient = sync.OnceValue(func() *http.Client {
+	allowedHostListValue := setting.Packages.RemoteAllowedHostList
+	if allowedHostListValue == "" {
+		allowedHostListValue = hostmatcher.MatchBuiltinExternal
+	}
+	allowedHostMatcher := hostmatcher.ParseHostMatchList("packages.REMOTE_ALLOWED_HOST_LIST", allowedHostListValue)
+
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:           proxy.Proxy(),
+			DialContext:     hostmatcher.NewDialContext("package remote", allowedHostMatcher, nil, setting.Proxy.ProxyURLFixed),
+			IdleConnTimeout: 90 * time.Second,
+		},
+	}
+})
+
+// URL returns the absolute URL of a path at the remote
+func (c *Client) URL(p string) string {
+	return c.baseURL + p
+}
+
+// Open requests the target which is either a path at the remote or an absolute URL.
+// ErrRemoteNotExist is returned if the remote doesn't have the content. The caller has to close the body.
+func (c *Client) Open(ctx context.Context, method, target string, header http.Header) (*http.Response, error) {
+	if strings.HasPrefix(target, "/") {
+		target = c.URL(target)
+	}
+
+	resp, err := c.do(ctx, method, target, header)
+	if err != nil {
+		return nil, err
+	}
+
+	// OCI registries require to fetch a token for the requested scope
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		if len(challenge) > 7 && strings.EqualFold(challenge[:7], "Bearer ") {
+			resp.Body.Close()
+
+			if c.token, err = c.fetchToken(ctx, challenge[7:]); err != nil {
+				return nil, err
+			}
+			if resp, err = c.do(ctx, method, target, header); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrRemoteNotExist
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("remote %s responded with status %d", target, resp.StatusCode)
+	}
+	return resp, nil
+}
+
+func (c *Client) do(ctx context.Context, method, target string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	// the credentials are only sent to the remote itself and not to other hosts like CDNs
+	if req.URL.Host == c.host {
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		} else if c.remote.Username != "" || c.password != "" {
+			req.SetBasicAuth(c.remote.Username, c.password)
+		}
+	}
+	return c.client.Do(req)
+}
+
+// https://distribution.github.io/distribution/spec/auth/token/
+func (c *Client) fetchToken(ctx context.Context, challenge string) (string, error) {
+	params := make(map[string]string)
+	for _, match := range challengeParamMatcher.FindAllStringSubmatch(challenge, -1) {
+		params[strings.ToLower(match[1])] = match[2]
+	}
+	if params["realm"] == "" {
+		return "", fmt.Errorf("remote %s requested an invalid authentication", c.baseURL)
+	}
+
+	u, err := url.Parse(params["realm"])
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	for _, name := range []string{"service", "scope"} {
+		if params[name] != "" {
+			q.Set(name, params[name])
+		}
+	}
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if c.remote.Username != "" || c.password != "" {
+		req.SetBasicAuth(c.remote.Username, c.password)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("remote %s responded with status %d to the token request", c.baseURL, resp.StatusCode)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", err
+	}
+	return util.IfZero(token.Token, token.AccessToken), nil
+}
+
+// Download fetches the target into a buffer. The caller has to close the buffer.
+// A quota error is returned if the content is larger than the doer is allowed to store for the owner of the remote.
+func (c *Client) Download(ctx context.Context, doer *user_model.User, target string, header http.Header) (*packages_module.HashedBuffer, error) {
+	maxSize, err := packages_service.GetMaxUploadSize(ctx, doer, c.remote.OwnerID, c.remote.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Open(ctx, http.MethodGet, target, header)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if maxSize == -1 {
+		return packages_module.CreateHashedBufferFromReader(resp.Body)
+	}
+	if resp.ContentLength > maxSize {
+		return nil, c.quotaError(ctx, doer, resp.ContentLength)
+	}
+
+	buf, err := packages_module.CreateHashedBufferFromReader(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if buf.Size() > maxSize {
+		buf.Close()
+		return nil, c.quotaError(ctx, doer, buf.Size())
+	}
+	return buf, nil
+}
+
+// quotaError returns the quota error of the size which exceeds the maximum upload size
+func (c *Client) quotaError(ctx context.Context, doer *user_model.User, size int64) error {
+	owner, err := user_model.GetUserByID(ctx, c.remote.OwnerID)
+	if err != nil {
+		return err
+	}
+	if err := packages_service.CheckSizeQuotaExceeded(ctx, doer, owner, c.remote.Type, size); err != nil {
+		return err
+	}
+	return packages_service.ErrQuotaTotalSize
+}
+
+// GetMetadata fetches the mutable metadata document at the target. The document is cached for the metadata TTL of the remote.
+func (c *Client) GetMetadata(ctx context.Context, target string, header http.Header) ([]byte, error) {
+	data, err := c.Cached("metadata:"+target, func() (string, error) {
+		resp, err := c.Open(ctx, http.MethodGet, target, header)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxMetadataSize+1))
+		if err != nil {
+			return "", err
+		}
+		if len(data) > maxMetadataSize {
+			return "", ErrMetadataTooLarge
+		}
+		return string(data), nil
+	})
+	return []byte(data), err
+}
+
+// Cached returns the cached value of the key or caches the value returned by getFunc for the metadata TTL of the remote.
+// Errors are not cached.
+func (c *Client) Cached(key string, getFunc func() (string, error)) (string, error) {
+	// the cache is invalidated if the remote is changed
+	key = fmt.Sprintf("package_remote_%d_%d:%s", c.remote.ID, c.remote.UpdatedUnix, key)
+
+	if value, ok := cache.GetCache().Get(key); ok {
+		return value, nil
+	}
+
+	value, err := getFunc()
+	if err != nil {
+		return "", err
+	}
+	_ = cache.GetCache().Put(key, value, c.remote.GetMetadataTTL())
+	return value, nil
+}
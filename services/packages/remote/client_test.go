@@ -0,0 +1,63 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package remote
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+	packages_service "code.gitea.io/gitea/services/packages"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientDownload(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	content := "test"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/unsized" {
+			// flushing before writing the content omits the Content-Length header
+			w.(http.Flusher).Flush()
+		}
+		_, _ = io.WriteString(w, content)
+	}))
+	defer server.Close()
+
+	c, err := NewClient(&packages_model.PackageRemote{OwnerID: 2, Type: packages_model.TypeMaven, URL: server.URL})
+	require.NoError(t, err)
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+	admin := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 1})
+
+	for _, target := range []string{"/sized", "/unsized"} {
+		t.Run(target, func(t *testing.T) {
+			defer test.MockVariableValue(&setting.Packages.LimitSizeMaven, int64(len(content)))()
+
+			buf, err := c.Download(t.Context(), user, target, nil)
+			require.NoError(t, err)
+			assert.EqualValues(t, len(content), buf.Size())
+			buf.Close()
+
+			setting.Packages.LimitSizeMaven = int64(len(content) - 1)
+
+			_, err = c.Download(t.Context(), user, target, nil)
+			assert.ErrorIs(t, err, packages_service.ErrQuotaTypeSize)
+
+			// the size of the packages fetched on behalf of admins isn't limited
+			buf, err = c.Download(t.Context(), admin, target, nil)
+			require.NoError(t, err)
+			assert.EqualValues(t, len(content), buf.Size())
+			buf.Close()
+		})
+	}
+}
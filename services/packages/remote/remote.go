@@ -0,0 +1,142 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package remote
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/optional"
+	"code.gitea.io/gitea/modules/util"
+	packages_service "code.gitea.io/gitea/services/packages"
+)
+
+// PropertyProxied marks the packages which were fetched from a remote
+const PropertyProxied = "remote.proxied"
+
+// RemoteOptions are the options to configure the remote of a package type
+type RemoteOptions struct {
+	URL         string
+	Username    string
+	Password    optional.Option[string]
+	MetadataTTL int64
+	Enabled     bool
+}
+
+// SetRemote creates or updates the remote of the owner for the package type
+func SetRemote(ctx context.Context, owner *user_model.User, packageType packages_model.Type, opts *RemoteOptions) (*packages_model.PackageRemote, error) {
+	if !packageType.IsRemoteSupported() {
+		return nil, util.NewInvalidArgumentErrorf("package type %q can't be fetched from a remote", packageType)
+	}
+	if u, err := url.Parse(opts.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, util.NewInvalidArgumentErrorf("remote url %q is invalid", opts.URL)
+	}
+	if opts.MetadataTTL < 0 {
+		return nil, util.NewInvalidArgumentErrorf("metadata ttl must not be negative")
+	}
+
+	pr, err := packages_model.GetRemoteByOwnerAndType(ctx, owner.ID, packageType)
+	if err != nil {
+		if !errors.Is(err, util.ErrNotExist) {
+			return nil, err
+		}
+		pr = &packages_model.PackageRemote{
+			OwnerID: owner.ID,
+			Type:    packageType,
+		}
+	}
+
+	pr.URL = strings.TrimSuffix(opts.URL, "/")
+	pr.Username = strings.TrimSpace(opts.Username)
+	pr.MetadataTTL = opts.MetadataTTL
+	pr.Enabled = opts.Enabled
+	if opts.Password.Has() {
+		if err := pr.SetPassword(opts.Password.Value()); err != nil {
+			return nil, err
+		}
+	}
+
+	if pr.ID == 0 {
+		err = packages_model.InsertRemote(ctx, pr)
+	} else {
+		err = packages_model.UpdateRemote(ctx, pr)
+	}
+	return pr, err
+}
+
+// DeleteRemote deletes the remote of the owner for the package type. The packages fetched from it are kept.
+func DeleteRemote(ctx context.Context, owner *user_model.User, packageType packages_model.Type) error {
+	pr, err := packages_model.GetRemoteByOwnerAndType(ctx, owner.ID, packageType)
+	if err != nil {
+		return err
+	}
+	return packages_model.DeleteRemoteByID(ctx, pr.ID)
+}
+
+// GetRemote returns the remote from which the package is fetched. It returns nil if the owner has no enabled remote
+// for the package type or if the package was uploaded to the owner directly, local packages always take precedence.
+func GetRemote(ctx context.Context, owner *user_model.User, packageType packages_model.Type, packageName string) (*packages_model.PackageRemote, error) {
+	pr, err := packages_model.GetRemoteByOwnerAndType(ctx, owner.ID, packageType)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !pr.Enabled {
+		return nil, nil
+	}
+
+	p, err := packages_model.GetPackageByName(ctx, owner.ID, packageType, packageName)
+	if err != nil {
+		if errors.Is(err, packages_model.ErrPackageNotExist) {
+			return pr, nil
+		}
+		return nil, err
+	}
+	proxied, err := IsProxied(ctx, p.ID)
+	if err != nil || !proxied {
+		return nil, err
+	}
+	return pr, nil
+}
+
+// IsProxied returns if the package was fetched from a remote
+func IsProxied(ctx context.Context, packageID int64) (bool, error) {
+	pps, err := packages_model.GetPropertiesByName(ctx, packages_model.PropertyTypePackage, packageID, PropertyProxied)
+	return len(pps) > 0, err
+}
+
+// MarkProxied marks the package as fetched from a remote
+func MarkProxied(ctx context.Context, packageID int64) error {
+	return packages_model.InsertOrUpdateProperty(ctx, packages_model.PropertyTypePackage, packageID, PropertyProxied, "")
+}
+
+// CreatePackageOrAddFile stores a file fetched from a remote and marks its package as proxied.
+// A file which was already stored by a concurrent request is not an error.
+func CreatePackageOrAddFile(ctx context.Context, pvci *packages_service.PackageCreationInfo, pfci *packages_service.PackageFileCreationInfo) (*packages_model.PackageVersion, error) {
+	pv, _, err := packages_service.CreatePackageOrAddFileToExisting(ctx, pvci, pfci)
+	if err != nil {
+		if !errors.Is(err, packages_model.ErrDuplicatePackageFile) {
+			return nil, err
+		}
+		pv, err = packages_model.GetVersionByNameAndVersion(ctx, pvci.Owner.ID, pvci.PackageType, pvci.Name, pvci.Version)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pv, MarkProxied(ctx, pv.PackageID)
+}
+
+// Creator returns the user which is recorded as creator of the packages fetched on behalf of the doer
+func Creator(doer *user_model.User) *user_model.User {
+	if doer == nil {
+		return user_model.NewGhostUser()
+	}
+	return doer
+}
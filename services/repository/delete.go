@@ -81,6 +81,12 @@ func DeleteRepositoryDirectly(ctx context.Context, repoID int64, ignoreOrgTeams
 		return fmt.Errorf("list actions artifacts of repo %v: %w", repoID, err)
 	}
 
+	// Query the caches of this repo, they will be needed after they have been deleted to remove caches files in ObjectStorage
+	caches, err := db.Find[actions_model.ActionCache](ctx, actions_model.FindCachesOptions{RepoID: repoID})
+	if err != nil {
+		return fmt.Errorf("list actions caches of repo %v: %w", repoID, err)
+	}
+
 	// In case owner is a organization, we have to change repo specific teams
 	// if ignoreOrgTeams is not true
 	var org *user_model.User
@@ -170,13 +176,19 @@ func DeleteRepositoryDirectly(ctx context.Context, repoID int64, ignoreOrgTeams
 		&secret_model.Secret{RepoID: repoID},
 		&actions_model.ActionTaskStep{RepoID: repoID},
 		&actions_model.ActionTask{RepoID: repoID},
+		&actions_model.ActionTaskSummary{RepoID: repoID},
 		&actions_model.ActionRunJob{RepoID: repoID},
+		&actions_model.ActionRunAttempt{RepoID: repoID},
 		&actions_model.ActionRun{RepoID: repoID},
 		&actions_model.ActionRunner{RepoID: repoID},
 		&actions_model.ActionScheduleSpec{RepoID: repoID},
 		&actions_model.ActionSchedule{RepoID: repoID},
 		&actions_model.ActionArtifact{RepoID: repoID},
 		&actions_model.ActionRunnerToken{RepoID: repoID},
+		&actions_model.ActionEnvironment{RepoID: repoID},
+		&actions_model.ActionDeploymentReview{RepoID: repoID},
+		&actions_model.ActionCache{RepoID: repoID},
+		&actions_model.ActionAttestation{RepoID: repoID},
 		&issues_model.IssuePin{RepoID: repoID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %w", err)
@@ -359,6 +371,12 @@ func DeleteRepositoryDirectly(ctx context.Context, repoID int64, ignoreOrgTeams
 			log.Error("remove log file %q: %v", task.LogFilename, err)
 			// go on
 		}
+		if task.StaleLogFilename != "" {
+			if err := storage.Actions.Delete(task.StaleLogFilename); err != nil {
+				log.Error("remove stale log file %q: %v", task.StaleLogFilename, err)
+				// go on
+			}
+		}
 	}
 
 	// delete actions artifacts in ObjectStorage after the repo have already been deleted
@@ -369,6 +387,15 @@ func DeleteRepositoryDirectly(ctx context.Context, repoID int64, ignoreOrgTeams
 		}
 	}
 
+	// delete actions caches in ObjectStorage after the repo have already been deleted
+	for _, cache := range caches {
+		if err := storage.ActionsCaches.Delete(cache.StoragePath()); err != nil {
+			log.Error("remove cache file %q: %v", cache.StoragePath(), err)
+			// go on
+		}
+		actions_service.DeleteCacheTmpChunks(cache)
+	}
+
 	return nil
 }
 
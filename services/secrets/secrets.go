@@ -81,3 +81,54 @@ func deleteSecret(ctx context.Context, s *secret_model.Secret) error {
 	}
 	return nil
 }
+
+// CreateOrUpdateEnvironmentSecret creates or updates a secret of an environment of the repository
+func CreateOrUpdateEnvironmentSecret(ctx context.Context, repoID, environmentID int64, name, data, description string) (*secret_model.Secret, bool, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, false, err
+	}
+
+	s, err := db.Find[secret_model.Secret](ctx, secret_model.FindSecretsOptions{
+		RepoID:        repoID,
+		EnvironmentID: environmentID,
+		Name:          name,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(s) == 0 {
+		s, err := secret_model.InsertEncryptedEnvironmentSecret(ctx, repoID, environmentID, name, data, description)
+		if err != nil {
+			return nil, false, err
+		}
+		return s, true, nil
+	}
+
+	if err := secret_model.UpdateSecret(ctx, s[0].ID, data, description); err != nil {
+		return nil, false, err
+	}
+
+	return s[0], false, nil
+}
+
+// DeleteEnvironmentSecretByName deletes a secret of an environment of the repository
+func DeleteEnvironmentSecretByName(ctx context.Context, repoID, environmentID int64, name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	s, err := db.Find[secret_model.Secret](ctx, secret_model.FindSecretsOptions{
+		RepoID:        repoID,
+		EnvironmentID: environmentID,
+		Name:          name,
+	})
+	if err != nil {
+		return err
+	}
+	if len(s) != 1 {
+		return secret_model.ErrSecretNotFound{}
+	}
+
+	return deleteSecret(ctx, s[0])
+}
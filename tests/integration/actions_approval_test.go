@@ -0,0 +1,194 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	pull_service "code.gitea.io/gitea/services/pull"
+	repo_service "code.gitea.io/gitea/services/repository"
+	files_service "code.gitea.io/gitea/services/repository/files"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionsApproval(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2}) // owner of the base repo
+		user4 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4}) // owner of the forked repo
+		session := loginUser(t, user2.Name)
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository)
+
+		baseRepo, err := repo_service.CreateRepository(db.DefaultContext, user2, user2, repo_service.CreateRepoOptions{
+			Name:          "actions-approval",
+			AutoInit:      true,
+			Readme:        "Default",
+			DefaultBranch: "main",
+		})
+		require.NoError(t, err)
+		changeFile := func(repo *repo_model.Repository, doer *user_model.User, treePath, content, newBranch string) {
+			_, err := files_service.ChangeRepoFiles(git.DefaultContext, repo, doer, &files_service.ChangeRepoFilesOptions{
+				Files: []*files_service.ChangeRepoFile{
+					{
+						Operation:     "create",
+						TreePath:      treePath,
+						ContentReader: strings.NewReader(content),
+					},
+				},
+				Message:   "add " + treePath,
+				OldBranch: "main",
+				NewBranch: newBranch,
+				Author:    &files_service.IdentityOptions{GitUserName: doer.Name, GitUserEmail: doer.Email},
+				Committer: &files_service.IdentityOptions{GitUserName: doer.Name, GitUserEmail: doer.Email},
+				Dates:     &files_service.CommitDateOptions{Author: time.Now(), Committer: time.Now()},
+			})
+			require.NoError(t, err)
+		}
+		changeFile(baseRepo, user2, ".gitea/workflows/pr.yml", `name: test
+on: pull_request
+concurrency:
+  group: actions-approval
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo helloworld
+`, "main")
+		// the workflows of pull requests are read from the head branches, so fork the repository after adding the workflow
+		forkedRepo, err := repo_service.ForkRepository(git.DefaultContext, user2, user4, repo_service.ForkRepoOptions{
+			BaseRepo: baseRepo,
+			Name:     "actions-approval-fork",
+		})
+		require.NoError(t, err)
+
+		// createForkPullRequest creates a pull request from the fork and returns the run triggered by it
+		createForkPullRequest := func(branch string) *actions_model.ActionRun {
+			changeFile(forkedRepo, user4, branch+".txt", branch, branch)
+			pullIssue := &issues_model.Issue{
+				RepoID:   baseRepo.ID,
+				Title:    "pull request from " + branch,
+				PosterID: user4.ID,
+				Poster:   user4,
+				IsPull:   true,
+			}
+			pullRequest := &issues_model.PullRequest{
+				HeadRepoID: forkedRepo.ID,
+				BaseRepoID: baseRepo.ID,
+				HeadBranch: branch,
+				BaseBranch: "main",
+				HeadRepo:   forkedRepo,
+				BaseRepo:   baseRepo,
+				Type:       issues_model.PullRequestGitea,
+			}
+			require.NoError(t, pull_service.NewPullRequest(git.DefaultContext, &pull_service.NewPullRequestOptions{Repo: baseRepo, Issue: pullIssue, PullRequest: pullRequest}))
+			return unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: baseRepo.ID, Title: "add " + branch + ".txt"})
+		}
+		reviewRuns := func(state string, runIDs ...int64) *api.ActionWorkflowRunsResponse {
+			req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs/approvals", user2.Name, baseRepo.Name), &api.ReviewActionRunsOption{
+				RunIDs: runIDs,
+				State:  state,
+			}).AddTokenAuth(token)
+			resp := MakeRequest(t, req, http.StatusOK)
+			var runsResp api.ActionWorkflowRunsResponse
+			DecodeJSON(t, resp, &runsResp)
+			return &runsResp
+		}
+		setApprovalPolicy := func(policy string) {
+			req := NewRequestWithJSON(t, "PUT", fmt.Sprintf("/api/v1/repos/%s/%s/actions/permissions/fork-pr-contributor-approval", user2.Name, baseRepo.Name), &api.EditActionForkPRContributorApprovalOption{
+				ApprovalPolicy: policy,
+			}).AddTokenAuth(token)
+			resp := MakeRequest(t, req, http.StatusOK)
+			var approval api.ActionForkPRContributorApproval
+			DecodeJSON(t, resp, &approval)
+			assert.Equal(t, policy, approval.ApprovalPolicy)
+			assert.False(t, approval.Inherited)
+		}
+
+		// the repository follows the owner by default
+		req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/permissions/fork-pr-contributor-approval", user2.Name, baseRepo.Name)).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var approval api.ActionForkPRContributorApproval
+		DecodeJSON(t, resp, &approval)
+		assert.Equal(t, "first_time_contributors", approval.ApprovalPolicy)
+		assert.True(t, approval.Inherited)
+		req = NewRequestWithJSON(t, "PUT", fmt.Sprintf("/api/v1/repos/%s/%s/actions/permissions/fork-pr-contributor-approval", user2.Name, baseRepo.Name), &api.EditActionForkPRContributorApprovalOption{
+			ApprovalPolicy: "invalid",
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		// the first-time contributor needs approval
+		run1 := createForkPullRequest("branch-1")
+		assert.True(t, run1.NeedApproval)
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs?status=action_required", user2.Name, baseRepo.Name)).AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		var runsResp api.ActionWorkflowRunsResponse
+		DecodeJSON(t, resp, &runsResp)
+		require.Len(t, runsResp.Entries, 1)
+		assert.Equal(t, run1.ID, runsResp.Entries[0].ID)
+		assert.Equal(t, "action_required", runsResp.Entries[0].Conclusion)
+		req = NewRequest(t, "GET", fmt.Sprintf("/%s/%s/actions", user2.Name, baseRepo.Name))
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		assert.Contains(t, resp.Body.String(), "/actions/approve-all")
+
+		// the rejected run is cancelled
+		runsResp = *reviewRuns("rejected", run1.ID)
+		require.Len(t, runsResp.Entries, 1)
+		assert.Equal(t, "cancelled", runsResp.Entries[0].Conclusion)
+		run1 = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: run1.ID})
+		assert.False(t, run1.NeedApproval)
+		assert.Equal(t, actions_model.StatusCancelled, run1.Status)
+
+		// a rejected contributor is still a first-time contributor
+		run2 := createForkPullRequest("branch-2")
+		assert.True(t, run2.NeedApproval)
+		req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs/approvals", user2.Name, baseRepo.Name), &api.ReviewActionRunsOption{
+			RunIDs: []int64{run1.ID},
+			State:  "approved",
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusBadRequest)
+		runsResp = *reviewRuns("approved")
+		require.Len(t, runsResp.Entries, 1)
+		assert.Equal(t, run2.ID, runsResp.Entries[0].ID)
+		run2 = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: run2.ID})
+		assert.False(t, run2.NeedApproval)
+		assert.Equal(t, user2.ID, run2.ApprovedBy)
+		assert.Equal(t, actions_model.StatusWaiting, run2.Status)
+
+		// the approved contributor doesn't need approval anymore
+		run3 := createForkPullRequest("branch-3")
+		assert.False(t, run3.NeedApproval)
+
+		// all external contributors need approval
+		setApprovalPolicy("all_external_contributors")
+		run4 := createForkPullRequest("branch-4")
+		assert.True(t, run4.NeedApproval)
+
+		// the approved run still waits for the concurrency group
+		runsResp = *reviewRuns("approved", run4.ID)
+		require.Len(t, runsResp.Entries, 1)
+		run4 = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: run4.ID})
+		assert.False(t, run4.NeedApproval)
+		assert.Equal(t, actions_model.StatusBlocked, run4.Status)
+
+		// no contributors need approval
+		setApprovalPolicy("none")
+		run5 := createForkPullRequest("branch-5")
+		assert.False(t, run5.NeedApproval)
+	})
+}
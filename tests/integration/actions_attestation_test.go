@@ -0,0 +1,242 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/storage"
+	api "code.gitea.io/gitea/modules/structs"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionsAttestations(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		session := loginUser(t, user2.Name)
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository, auth_model.AccessTokenScopeWriteUser, auth_model.AccessTokenScopeWritePackage)
+
+		apiRepo := createActionsTestRepo(t, token, "actions-attestations", false)
+		otherRepo := createActionsTestRepo(t, token, "actions-attestations-other", false)
+		runner := newMockRunner()
+		runner.registerAsRepoRunner(t, user2.Name, apiRepo.Name, "mock-runner", []string{"ubuntu-latest"}, false)
+
+		wfTreePath := ".gitea/workflows/release.yml"
+		wfFileContent := `name: release
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    permissions:
+      contents: read
+      attestations: write
+      packages: write
+    steps:
+      - run: echo build
+  test:
+    runs-on: ubuntu-latest
+    needs: build
+    permissions:
+      contents: read
+    steps:
+      - run: echo test
+`
+		opts := getWorkflowCreateFileOptions(user2, apiRepo.DefaultBranch, "create "+wfTreePath, wfFileContent)
+		createWorkflowFile(t, token, user2.Name, apiRepo.Name, wfTreePath, opts)
+
+		task := runner.fetchTask(t)
+		taskToken := task.Context.GetFields()["token"].GetStringValue()
+		actionTask := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: task.Id})
+		job := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: actionTask.JobID})
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: job.RunID})
+
+		content := []byte("the binary built by the job")
+		sum := sha256.Sum256(content)
+		digest := "sha256:" + hex.EncodeToString(sum[:])
+
+		storagePath := fmt.Sprintf("attestations/%d/app.zip", run.ID)
+		_, err := storage.ActionsArtifacts.Save(storagePath, bytes.NewReader(content), int64(len(content)))
+		require.NoError(t, err)
+		artifact := &actions_model.ActionArtifact{
+			RunID:           run.ID,
+			RepoID:          apiRepo.ID,
+			OwnerID:         user2.ID,
+			ArtifactName:    "app",
+			ArtifactPath:    "app.zip",
+			StoragePath:     storagePath,
+			FileSize:        int64(len(content)),
+			ContentEncoding: "application/zip",
+			Status:          actions_model.ArtifactStatusUploadConfirmed,
+		}
+		require.NoError(t, db.Insert(db.DefaultContext, artifact))
+
+		req := NewRequestWithBody(t, "PUT", fmt.Sprintf("/api/packages/%s/generic/app/1.0.0/app", user2.Name), bytes.NewReader(content)).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusCreated)
+
+		attestationsURL := fmt.Sprintf("/api/v1/repos/%s/%s/actions/attestations", user2.Name, apiRepo.Name)
+		createAttestation := func(t *testing.T, token string, opts *api.CreateActionAttestationOption, expectedStatus int) *api.ActionAttestation {
+			req := NewRequestWithJSON(t, "POST", attestationsURL, opts).AddTokenAuth(token)
+			resp := MakeRequest(t, req, expectedStatus)
+			if expectedStatus != http.StatusCreated {
+				return nil
+			}
+			var attestation api.ActionAttestation
+			DecodeJSON(t, resp, &attestation)
+			return &attestation
+		}
+
+		var artifactAttestation, packageAttestation *api.ActionAttestation
+		t.Run("Create", func(t *testing.T) {
+			// only the jobs can request attestations
+			createAttestation(t, token, &api.CreateActionAttestationOption{SubjectName: "app", SubjectDigest: digest}, http.StatusForbidden)
+
+			createAttestation(t, taskToken, &api.CreateActionAttestationOption{SubjectName: "app", SubjectDigest: "md5:abc"}, http.StatusBadRequest)
+			createAttestation(t, taskToken, &api.CreateActionAttestationOption{SubjectName: "app", SubjectDigest: digest, ArtifactName: "missing"}, http.StatusNotFound)
+			// the subject must be the content of the artifact
+			createAttestation(t, taskToken, &api.CreateActionAttestationOption{
+				SubjectName: "app", SubjectDigest: "sha256:" + hex.EncodeToString(make([]byte, 32)), ArtifactName: "app",
+			}, http.StatusBadRequest)
+			createAttestation(t, taskToken, &api.CreateActionAttestationOption{
+				SubjectName: "app", SubjectDigest: "sha256:" + hex.EncodeToString(make([]byte, 32)),
+				PackageType: "generic", PackageName: "app", PackageVersion: "1.0.0",
+			}, http.StatusBadRequest)
+
+			artifactAttestation = createAttestation(t, taskToken, &api.CreateActionAttestationOption{SubjectName: "app", SubjectDigest: digest, ArtifactName: "app"}, http.StatusCreated)
+			assert.Equal(t, digest, artifactAttestation.SubjectDigest)
+			assert.Equal(t, "https://slsa.dev/provenance/v1", artifactAttestation.PredicateType)
+			assert.Equal(t, run.ID, artifactAttestation.RunID)
+			assert.Equal(t, artifact.ID, artifactAttestation.ArtifactID)
+			assert.NotEmpty(t, artifactAttestation.KeyID)
+			assert.Equal(t, "application/vnd.in-toto+json", artifactAttestation.Envelope.PayloadType)
+
+			payload, err := base64.StdEncoding.DecodeString(artifactAttestation.Envelope.Payload)
+			require.NoError(t, err)
+			var statement struct {
+				Type    string `json:"_type"`
+				Subject []struct {
+					Name   string            `json:"name"`
+					Digest map[string]string `json:"digest"`
+				} `json:"subject"`
+				Predicate struct {
+					BuildDefinition struct {
+						ExternalParameters struct {
+							Workflow struct {
+								Repository string `json:"repository"`
+								Path       string `json:"path"`
+							} `json:"workflow"`
+						} `json:"externalParameters"`
+						InternalParameters   map[string]string `json:"internalParameters"`
+						ResolvedDependencies []struct {
+							Digest map[string]string `json:"digest"`
+						} `json:"resolvedDependencies"`
+					} `json:"buildDefinition"`
+				} `json:"predicate"`
+			}
+			require.NoError(t, json.Unmarshal(payload, &statement))
+			assert.Equal(t, "https://in-toto.io/Statement/v1", statement.Type)
+			require.Len(t, statement.Subject, 1)
+			assert.Equal(t, hex.EncodeToString(sum[:]), statement.Subject[0].Digest["sha256"])
+			buildDefinition := statement.Predicate.BuildDefinition
+			assert.Equal(t, apiRepo.HTMLURL, buildDefinition.ExternalParameters.Workflow.Repository)
+			assert.Equal(t, "release.yml", buildDefinition.ExternalParameters.Workflow.Path)
+			assert.Equal(t, fmt.Sprint(run.ID), buildDefinition.InternalParameters["run_id"])
+			assert.Equal(t, "mock-runner", buildDefinition.InternalParameters["runner_name"])
+			require.Len(t, buildDefinition.ResolvedDependencies, 1)
+			assert.Equal(t, run.CommitSHA, buildDefinition.ResolvedDependencies[0].Digest["gitCommit"])
+
+			packageAttestation = createAttestation(t, taskToken, &api.CreateActionAttestationOption{
+				SubjectName: "app", SubjectDigest: digest,
+				PackageType: "generic", PackageName: "app", PackageVersion: "1.0.0",
+			}, http.StatusCreated)
+			assert.NotZero(t, packageAttestation.PackageVersionID)
+		})
+
+		t.Run("List", func(t *testing.T) {
+			req := NewRequest(t, "GET", attestationsURL+"?subject_digest="+url.QueryEscape(digest)).AddTokenAuth(token)
+			resp := MakeRequest(t, req, http.StatusOK)
+			var attestations []*api.ActionAttestation
+			DecodeJSON(t, resp, &attestations)
+			assert.Len(t, attestations, 2)
+			assert.Equal(t, "2", resp.Header().Get("X-Total-Count"))
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s?artifact_id=%d", attestationsURL, artifact.ID)).AddTokenAuth(token)
+			resp = MakeRequest(t, req, http.StatusOK)
+			DecodeJSON(t, resp, &attestations)
+			require.Len(t, attestations, 1)
+			assert.Equal(t, artifactAttestation.ID, attestations[0].ID)
+
+			req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/packages/%s/generic/app/1.0.0/attestations", user2.Name)).AddTokenAuth(token)
+			resp = MakeRequest(t, req, http.StatusOK)
+			DecodeJSON(t, resp, &attestations)
+			require.Len(t, attestations, 1)
+			assert.Equal(t, packageAttestation.ID, attestations[0].ID)
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/%d", attestationsURL, artifactAttestation.ID)).AddTokenAuth(token)
+			resp = MakeRequest(t, req, http.StatusOK)
+			var attestation api.ActionAttestation
+			DecodeJSON(t, resp, &attestation)
+			assert.Equal(t, artifactAttestation.Envelope, attestation.Envelope)
+
+			req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/attestations/%d", user2.Name, otherRepo.Name, artifactAttestation.ID)).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusNotFound)
+		})
+
+		t.Run("Verify", func(t *testing.T) {
+			verify := func(t *testing.T, repoName string, opts *api.VerifyActionAttestationOption) *api.ActionAttestationVerification {
+				req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/actions/attestations/verify", user2.Name, repoName), opts).AddTokenAuth(token)
+				resp := MakeRequest(t, req, http.StatusOK)
+				var verification api.ActionAttestationVerification
+				DecodeJSON(t, resp, &verification)
+				return &verification
+			}
+
+			verification := verify(t, apiRepo.Name, &api.VerifyActionAttestationOption{Envelope: artifactAttestation.Envelope, SubjectDigest: digest})
+			assert.True(t, verification.Verified, verification.Message)
+			assert.Equal(t, artifactAttestation.KeyID, verification.KeyID)
+			assert.Equal(t, "https://slsa.dev/provenance/v1", verification.Statement["predicateType"])
+			// the attestations of the same artifact built by the same job have the same statement
+			require.NotNil(t, verification.Attestation)
+			assert.Contains(t, []int64{artifactAttestation.ID, packageAttestation.ID}, verification.Attestation.ID)
+
+			verification = verify(t, apiRepo.Name, &api.VerifyActionAttestationOption{Envelope: artifactAttestation.Envelope, SubjectDigest: "sha256:" + hex.EncodeToString(make([]byte, 32))})
+			assert.False(t, verification.Verified)
+			assert.Contains(t, verification.Message, "no subject")
+
+			verification = verify(t, otherRepo.Name, &api.VerifyActionAttestationOption{Envelope: artifactAttestation.Envelope})
+			assert.False(t, verification.Verified)
+			assert.Contains(t, verification.Message, "not built by the repository")
+
+			tampered := *artifactAttestation.Envelope
+			tampered.Payload = base64.StdEncoding.EncodeToString([]byte(`{"_type":"https://in-toto.io/Statement/v1"}`))
+			verification = verify(t, apiRepo.Name, &api.VerifyActionAttestationOption{Envelope: &tampered})
+			assert.False(t, verification.Verified)
+			assert.Contains(t, verification.Message, "invalid signature")
+		})
+
+		t.Run("Permission", func(t *testing.T) {
+			runner.execTask(t, task, &mockTaskOutcome{result: runnerv1.Result_RESULT_SUCCESS})
+			// the tokens of the finished jobs are invalid
+			createAttestation(t, taskToken, &api.CreateActionAttestationOption{SubjectName: "app", SubjectDigest: digest}, http.StatusUnauthorized)
+
+			task := runner.fetchTask(t)
+			createAttestation(t, task.Context.GetFields()["token"].GetStringValue(), &api.CreateActionAttestationOption{SubjectName: "app", SubjectDigest: digest}, http.StatusForbidden)
+		})
+	})
+}
@@ -0,0 +1,175 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/modules/timeutil"
+	actions_service "code.gitea.io/gitea/services/actions"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestActionsLogRetention(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		session := loginUser(t, user2.Name)
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository, auth_model.AccessTokenScopeWriteUser)
+
+		apiRepo := createActionsTestRepo(t, token, "actions-log-retention", false)
+		repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: apiRepo.ID})
+		runner := newMockRunner()
+		runner.registerAsRepoRunner(t, user2.Name, repo.Name, "mock-runner", []string{"ubuntu-latest"}, false)
+
+		// the log is stored uncompressed
+		defer test.MockVariableValue(&setting.Actions.LogCompression, "none")()
+		treePath := ".gitea/workflows/log-retention.yml"
+		fileContent := `name: log-retention
+on: push
+jobs:
+  job1:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hello
+`
+		opts := getWorkflowCreateFileOptions(user2, repo.DefaultBranch, "create "+treePath, fileContent)
+		createWorkflowFile(t, token, user2.Name, repo.Name, treePath, opts)
+
+		now := time.Now()
+		logRows := []*runnerv1.LogRow{
+			{Time: timestamppb.New(now.Add(1 * time.Second)), Content: "hello"},
+			{Time: timestamppb.New(now.Add(2 * time.Second)), Content: "world"},
+		}
+		task := runner.fetchTask(t)
+		runner.execTask(t, task, &mockTaskOutcome{result: runnerv1.Result_RESULT_SUCCESS, logRows: logRows})
+		runIndex := task.Context.GetFields()["run_number"].GetStringValue()
+		actionTask := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: task.Id})
+		uncompressed := fmt.Sprintf("%s/%02x/%d.log", repo.FullName(), task.Id%256, task.Id)
+		assert.Equal(t, uncompressed, actionTask.LogFilename)
+
+		// the task was stopped two days ago
+		_, err := db.GetEngine(t.Context()).ID(task.Id).Cols("stopped").
+			Update(&actions_model.ActionTask{Stopped: timeutil.TimeStampNow().AddDuration(-48 * time.Hour)})
+		require.NoError(t, err)
+
+		assertLogs := func(t *testing.T) {
+			req := NewRequest(t, "GET", fmt.Sprintf("/%s/%s/actions/runs/%s/jobs/0/logs", user2.Name, repo.Name, runIndex)).
+				AddTokenAuth(token)
+			resp := MakeRequest(t, req, http.StatusOK)
+			lines := strings.Split(strings.TrimSpace(resp.Body.String()), "\n")
+			if assert.Len(t, lines, len(logRows)) {
+				for i, row := range logRows {
+					assert.True(t, strings.HasSuffix(lines[i], " "+row.Content))
+				}
+			}
+		}
+
+		t.Run("ArchiveLogs", func(t *testing.T) {
+			defer test.MockVariableValue(&setting.Actions.LogCompression, "zstd")()
+			require.NoError(t, actions_service.ArchiveLogs(t.Context()))
+
+			actionTask := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: task.Id})
+			assert.Equal(t, uncompressed+".zst", actionTask.LogFilename)
+			assert.True(t, actionTask.LogInStorage)
+			_, err := storage.Actions.Stat(actionTask.LogFilename)
+			assert.NoError(t, err)
+			assertLogs(t)
+
+			// the uncompressed log is kept for the requests which are still reading it
+			assert.Equal(t, uncompressed, actionTask.StaleLogFilename)
+			_, err = storage.Actions.Stat(uncompressed)
+			assert.NoError(t, err)
+			require.NoError(t, actions_service.ArchiveLogs(t.Context()))
+			_, err = storage.Actions.Stat(uncompressed)
+			assert.NoError(t, err)
+
+			// and it's removed by the next run after the grace period
+			_, err = db.GetEngine(t.Context()).ID(task.Id).Cols("updated").NoAutoTime().
+				Update(&actions_model.ActionTask{Updated: timeutil.TimeStampNow().AddDuration(-2 * time.Hour)})
+			require.NoError(t, err)
+			require.NoError(t, actions_service.ArchiveLogs(t.Context()))
+			actionTask = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: task.Id})
+			assert.Empty(t, actionTask.StaleLogFilename)
+			_, err = storage.Actions.Stat(uncompressed)
+			assert.Error(t, err)
+
+			assertLogs(t)
+		})
+
+		t.Run("RetentionAPI", func(t *testing.T) {
+			retentionURL := fmt.Sprintf("/api/v1/repos/%s/%s/actions/permissions/log-retention", user2.Name, repo.Name)
+			req := NewRequest(t, "GET", retentionURL).AddTokenAuth(token)
+			resp := MakeRequest(t, req, http.StatusOK)
+			var retention api.ActionLogRetention
+			DecodeJSON(t, resp, &retention)
+			assert.Equal(t, setting.Actions.LogRetentionDays, retention.Days)
+			assert.Equal(t, setting.Actions.LogRetentionDays, retention.MaximumAllowedDays)
+			assert.True(t, retention.Inherited)
+
+			// the logs are kept since they are in the retention period
+			require.NoError(t, actions_service.CleanupExpiredLogs(t.Context()))
+			assertLogs(t)
+
+			req = NewRequestWithJSON(t, "PUT", retentionURL, &api.EditActionLogRetentionOption{Days: setting.Actions.LogRetentionDays + 1}).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+			// the repository follows the retention period of its owner
+			require.NoError(t, actions_service.SetOwnerLogRetentionDays(t.Context(), user2.ID, 30))
+			req = NewRequest(t, "GET", retentionURL).AddTokenAuth(token)
+			resp = MakeRequest(t, req, http.StatusOK)
+			DecodeJSON(t, resp, &retention)
+			assert.EqualValues(t, 30, retention.Days)
+			assert.True(t, retention.Inherited)
+			require.NoError(t, actions_service.SetOwnerLogRetentionDays(t.Context(), user2.ID, 0))
+
+			req = NewRequestWithJSON(t, "PUT", retentionURL, &api.EditActionLogRetentionOption{Days: 1}).AddTokenAuth(token)
+			resp = MakeRequest(t, req, http.StatusOK)
+			DecodeJSON(t, resp, &retention)
+			assert.EqualValues(t, 1, retention.Days)
+			assert.False(t, retention.Inherited)
+		})
+
+		t.Run("ExpiredLogs", func(t *testing.T) {
+			require.NoError(t, actions_service.CleanupExpiredLogs(t.Context()))
+
+			actionTask := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: task.Id})
+			assert.True(t, actionTask.LogExpired)
+			_, err := storage.Actions.Stat(actionTask.LogFilename)
+			assert.Error(t, err)
+
+			req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/jobs/%d", user2.Name, repo.Name, actionTask.JobID)).
+				AddTokenAuth(token)
+			resp := MakeRequest(t, req, http.StatusOK)
+			var job api.ActionWorkflowJob
+			DecodeJSON(t, resp, &job)
+			assert.True(t, job.LogsExpired)
+
+			req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/jobs/%d/logs", user2.Name, repo.Name, actionTask.JobID)).
+				AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusGone)
+			req = NewRequest(t, "GET", fmt.Sprintf("/%s/%s/actions/runs/%s/jobs/0/logs", user2.Name, repo.Name, runIndex)).
+				AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusGone)
+		})
+	})
+}
@@ -0,0 +1,182 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	api "code.gitea.io/gitea/modules/structs"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type logStreamEvent struct {
+	name string
+	data string
+	id   string
+}
+
+func readLogStreamEvent(t *testing.T, r *bufio.Reader) *logStreamEvent {
+	event := &logStreamEvent{}
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			if event.name == "" {
+				continue
+			}
+			return event
+		}
+		key, value, _ := strings.Cut(line, ": ")
+		switch key {
+		case "event":
+			event.name = value
+		case "data":
+			event.data += value
+		case "id":
+			event.id = value
+		}
+	}
+}
+
+func TestActionsLogStream(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		session := loginUser(t, user2.Name)
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository, auth_model.AccessTokenScopeWriteUser)
+
+		apiRepo := createActionsTestRepo(t, token, "actions-log-stream", false)
+		runner := newMockRunner()
+		runner.registerAsRepoRunner(t, user2.Name, apiRepo.Name, "mock-runner", []string{"ubuntu-latest"}, false)
+
+		wfTreePath := ".gitea/workflows/log-stream.yml"
+		wfFileContent := `name: log-stream
+on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo test
+`
+		opts := getWorkflowCreateFileOptions(user2, apiRepo.DefaultBranch, "create "+wfTreePath, wfFileContent)
+		createWorkflowFile(t, token, user2.Name, apiRepo.Name, wfTreePath, opts)
+
+		task := runner.fetchTask(t)
+		actionTask := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: task.Id})
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: apiRepo.ID, WorkflowID: "log-stream.yml"})
+		streamURL := fmt.Sprintf("/api/v1/repos/%s/%s/actions/jobs/%d/logs/stream", user2.Name, apiRepo.Name, actionTask.JobID)
+
+		updateLog := func(t *testing.T, index int64, noMore bool, contents ...string) {
+			rows := make([]*runnerv1.LogRow, 0, len(contents))
+			for _, content := range contents {
+				rows = append(rows, &runnerv1.LogRow{Time: timestamppb.Now(), Content: content})
+			}
+			_, err := runner.client.runnerServiceClient.UpdateLog(t.Context(), connect.NewRequest(&runnerv1.UpdateLogRequest{
+				TaskId: task.Id,
+				Index:  index,
+				Rows:   rows,
+				NoMore: noMore,
+			}))
+			require.NoError(t, err)
+		}
+		updateLog(t, 0, false, "line 0", "line 1")
+
+		t.Run("Live", func(t *testing.T) {
+			req, err := http.NewRequestWithContext(t.Context(), "GET", strings.TrimSuffix(u.String(), "/")+streamURL+"?offset=1", nil)
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "token "+token)
+			req.Header.Set("Accept", "text/event-stream")
+			resp, err := (&http.Client{Timeout: time.Minute}).Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+			reader := bufio.NewReader(resp.Body)
+
+			readChunk := func(t *testing.T) (*api.ActionWorkflowJobLogChunk, string) {
+				event := readLogStreamEvent(t, reader)
+				require.Equal(t, "logs", event.name)
+				var chunk api.ActionWorkflowJobLogChunk
+				require.NoError(t, json.Unmarshal([]byte(event.data), &chunk))
+				return &chunk, event.id
+			}
+
+			// the stored lines are sent from the offset
+			chunk, id := readChunk(t)
+			assert.EqualValues(t, 1, chunk.Offset)
+			require.Len(t, chunk.Lines, 1)
+			assert.Equal(t, "line 1", chunk.Lines[0].Message)
+			assert.Equal(t, "2", id)
+
+			// the uploaded lines are sent as soon as they arrive
+			updateLog(t, 2, false, "line 2")
+			chunk, id = readChunk(t)
+			assert.EqualValues(t, 2, chunk.Offset)
+			require.Len(t, chunk.Lines, 1)
+			assert.EqualValues(t, 2, chunk.Lines[0].Index)
+			assert.Equal(t, "line 2", chunk.Lines[0].Message)
+			assert.Equal(t, "3", id)
+
+			// the resent lines are not duplicated
+			updateLog(t, 2, true, "line 2", "line 3")
+			chunk, id = readChunk(t)
+			assert.EqualValues(t, 3, chunk.Offset)
+			require.Len(t, chunk.Lines, 1)
+			assert.Equal(t, "line 3", chunk.Lines[0].Message)
+			assert.Equal(t, "4", id)
+
+			// the stream ends when all lines have been uploaded
+			assert.Equal(t, "end", readLogStreamEvent(t, reader).name)
+		})
+
+		_, err := runner.client.runnerServiceClient.UpdateTask(t.Context(), connect.NewRequest(&runnerv1.UpdateTaskRequest{
+			State: &runnerv1.TaskState{
+				Id:        task.Id,
+				Result:    runnerv1.Result_RESULT_SUCCESS,
+				StoppedAt: timestamppb.Now(),
+			},
+		}))
+		require.NoError(t, err)
+
+		t.Run("Resume", func(t *testing.T) {
+			// the plain text stream is for the terminals
+			req := NewRequest(t, "GET", streamURL+"?offset=2").AddTokenAuth(token)
+			resp := MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, "line 2\nline 3\n", resp.Body.String())
+
+			// a reconnecting event source resumes from the last event
+			req = NewRequest(t, "GET", streamURL).AddTokenAuth(token)
+			req.Header.Set("Accept", "text/event-stream")
+			req.Header.Set("Last-Event-ID", "4")
+			resp = MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, "event: end\n\n", resp.Body.String())
+
+			req = NewRequest(t, "GET", streamURL+"?offset=-1").AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusBadRequest)
+
+			// the web UI uses the index of the job in the run
+			req = NewRequest(t, "GET", fmt.Sprintf("/%s/%s/actions/runs/%d/jobs/0/logs/stream", user2.Name, apiRepo.Name, run.Index))
+			resp = session.MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, "line 0\nline 1\nline 2\nline 3\n", resp.Body.String())
+			req = NewRequest(t, "GET", fmt.Sprintf("/%s/%s/actions/runs/%d/jobs/1/logs/stream", user2.Name, apiRepo.Name, run.Index))
+			session.MakeRequest(t, req, http.StatusNotFound)
+		})
+	})
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionsDynamicMatrix(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		session := loginUser(t, user2.Name)
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository, auth_model.AccessTokenScopeWriteUser)
+
+		apiRepo := createActionsTestRepo(t, token, "actions-dynamic-matrix", false)
+		runner := newMockRunner()
+		runner.registerAsRepoRunner(t, user2.Name, apiRepo.Name, "mock-runner", []string{"ubuntu-latest", "linux", "windows", "macos"}, false)
+
+		wfTreePath := ".gitea/workflows/dynamic-matrix.yml"
+		wfFileContent := `name: dynamic-matrix
+on: push
+jobs:
+  setup:
+    runs-on: ubuntu-latest
+    outputs:
+      matrix: ${{ steps.set.outputs.matrix }}
+    steps:
+      - id: set
+        run: echo 'matrix={"os":["linux","windows","macos"]}' >> $GITHUB_OUTPUT
+  build:
+    needs: setup
+    runs-on: ${{ matrix.os }}
+    strategy:
+      max-parallel: 1
+      matrix: ${{ fromJSON(needs.setup.outputs.matrix) }}
+    steps:
+      - run: echo build
+`
+		opts := getWorkflowCreateFileOptions(user2, apiRepo.DefaultBranch, "create "+wfTreePath, wfFileContent)
+		createWorkflowFile(t, token, user2.Name, apiRepo.Name, wfTreePath, opts)
+
+		task := runner.fetchTask(t)
+		assert.Equal(t, "setup", getTaskJobNameByTaskID(t, token, user2.Name, apiRepo.Name, task.Id))
+		runner.execTask(t, task, &mockTaskOutcome{
+			result:  runnerv1.Result_RESULT_SUCCESS,
+			outputs: map[string]string{"matrix": `{"os":["linux","windows","macos"]}`},
+		})
+
+		findBuildJobs := func() []*actions_model.ActionRunJob {
+			jobs, err := db.Find[actions_model.ActionRunJob](t.Context(), actions_model.FindRunJobOptions{RepoID: apiRepo.ID})
+			require.NoError(t, err)
+			ret := make([]*actions_model.ActionRunJob, 0, len(jobs))
+			for _, job := range jobs {
+				if job.JobID == "build" {
+					ret = append(ret, job)
+				}
+			}
+			return ret
+		}
+		countStartedJobs := func(jobs []*actions_model.ActionRunJob) int {
+			n := 0
+			for _, job := range jobs {
+				if job.Status.In(actions_model.StatusWaiting, actions_model.StatusRunning) {
+					n++
+				}
+			}
+			return n
+		}
+
+		// the matrix is expanded after the setup job is done, and only one job of it runs at a time
+		task = runner.fetchTask(t)
+		assert.Equal(t, "build (linux)", getTaskJobNameByTaskID(t, token, user2.Name, apiRepo.Name, task.Id))
+		buildJobs := findBuildJobs()
+		require.Len(t, buildJobs, 3)
+		assert.Equal(t, 1, countStartedJobs(buildJobs))
+		for _, job := range buildJobs {
+			assert.Empty(t, job.RawMatrix)
+			assert.Equal(t, 1, job.MaxParallel)
+			assert.Len(t, job.RunsOn, 1)
+			assert.Contains(t, job.Name, job.RunsOn[0])
+		}
+		runner.execTask(t, task, &mockTaskOutcome{result: runnerv1.Result_RESULT_SUCCESS})
+
+		task = runner.fetchTask(t)
+		assert.Equal(t, "build (macos)", getTaskJobNameByTaskID(t, token, user2.Name, apiRepo.Name, task.Id))
+		assert.Equal(t, 1, countStartedJobs(findBuildJobs()))
+
+		// the unfinished jobs of the matrix are cancelled since fail-fast is enabled by default
+		runner.execTask(t, task, &mockTaskOutcome{result: runnerv1.Result_RESULT_FAILURE})
+		assert.Eventually(t, func() bool {
+			for _, job := range findBuildJobs() {
+				if job.Name == "build (windows)" {
+					return job.Status == actions_model.StatusCancelled
+				}
+			}
+			return false
+		}, 10*time.Second, 100*time.Millisecond)
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: apiRepo.ID})
+		assert.True(t, run.Status.IsDone())
+	})
+}
@@ -0,0 +1,134 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsRepositoryDispatch(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		session := loginUser(t, user2.Name)
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository, auth_model.AccessTokenScopeWriteIssue, auth_model.AccessTokenScopeWriteUser)
+
+		apiRepo := createActionsTestRepo(t, token, "actions-repository-dispatch", false)
+		repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: apiRepo.ID})
+		runner := newMockRunner()
+		runner.registerAsRepoRunner(t, user2.Name, repo.Name, "mock-runner", []string{"ubuntu-latest"}, false)
+
+		workflows := map[string]string{
+			"dispatch.yml": `name: dispatch
+on:
+  repository_dispatch:
+    types: [deploy]
+jobs:
+  job1:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo ${{ github.event.client_payload.env }}
+`,
+			"label.yml": `name: label
+on:
+  label:
+    types: [created]
+jobs:
+  job1:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo ${{ github.event.label.name }}
+`,
+			"create.yml": `name: create
+on:
+  create:
+    branches: [release/*]
+jobs:
+  job1:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo ${{ github.ref }}
+`,
+		}
+		for name, content := range workflows {
+			treePath := ".gitea/workflows/" + name
+			opts := getWorkflowCreateFileOptions(user2, repo.DefaultBranch, "create "+treePath, content)
+			createWorkflowFile(t, token, user2.Name, repo.Name, treePath, opts)
+		}
+		unittest.AssertCount(t, &actions_model.ActionRun{RepoID: repo.ID}, 0)
+
+		assertTriggered := func(t *testing.T, event string) *runnerv1.Task {
+			task := runner.fetchTask(t)
+			gtCtx := task.Context.GetFields()
+			assert.Equal(t, event, gtCtx["event_name"].GetStringValue())
+			runner.execTask(t, task, &mockTaskOutcome{result: runnerv1.Result_RESULT_SUCCESS})
+			return task
+		}
+
+		t.Run("RepositoryDispatch", func(t *testing.T) {
+			dispatchURL := fmt.Sprintf("/api/v1/repos/%s/%s/dispatches", user2.Name, repo.Name)
+
+			req := NewRequestWithJSON(t, "POST", dispatchURL, &api.CreateRepositoryDispatchOption{}).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+			// the type isn't listened by the workflow
+			req = NewRequestWithJSON(t, "POST", dispatchURL, &api.CreateRepositoryDispatchOption{EventType: "rollback"}).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusNoContent)
+			unittest.AssertCount(t, &actions_model.ActionRun{RepoID: repo.ID}, 0)
+
+			req = NewRequestWithJSON(t, "POST", dispatchURL, &api.CreateRepositoryDispatchOption{
+				EventType:     "deploy",
+				ClientPayload: map[string]any{"env": "production"},
+			}).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusNoContent)
+			task := assertTriggered(t, "repository_dispatch")
+			event := task.Context.GetFields()["event"].GetStructValue().AsMap()
+			assert.Equal(t, "deploy", event["action"])
+			assert.Equal(t, map[string]any{"env": "production"}, event["client_payload"])
+			assert.Equal(t, "refs/heads/"+repo.DefaultBranch, task.Context.GetFields()["ref"].GetStringValue())
+		})
+
+		t.Run("Label", func(t *testing.T) {
+			req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/labels", user2.Name, repo.Name), &api.CreateLabelOption{
+				Name:  "triage",
+				Color: "#abcdef",
+			}).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusCreated)
+			task := assertTriggered(t, "label")
+			event := task.Context.GetFields()["event"].GetStructValue().AsMap()
+			assert.Equal(t, "created", event["action"])
+		})
+
+		t.Run("CreateBranch", func(t *testing.T) {
+			count := unittest.GetCount(t, &actions_model.ActionRun{RepoID: repo.ID})
+			createBranch := func(name string) {
+				req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/branches", user2.Name, repo.Name), &api.CreateBranchRepoOption{
+					BranchName:    name,
+					OldBranchName: repo.DefaultBranch,
+				}).AddTokenAuth(token)
+				MakeRequest(t, req, http.StatusCreated)
+			}
+
+			// the branch doesn't match the filter of the workflow
+			createBranch("feature")
+			unittest.AssertCount(t, &actions_model.ActionRun{RepoID: repo.ID}, count)
+
+			createBranch("release/v1")
+			task := assertTriggered(t, "create")
+			assert.Equal(t, "refs/heads/release/v1", task.Context.GetFields()["ref"].GetStringValue())
+		})
+	})
+}
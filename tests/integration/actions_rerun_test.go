@@ -0,0 +1,281 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/web/repo/actions"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestActionsRerunFailedJobs(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		session := loginUser(t, user2.Name)
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository, auth_model.AccessTokenScopeWriteUser)
+
+		apiRepo := createActionsTestRepo(t, token, "actions-rerun-failed", false)
+		runner := newMockRunner()
+		runner.registerAsRepoRunner(t, user2.Name, apiRepo.Name, "mock-runner", []string{"ubuntu-latest"}, false)
+
+		wfTreePath := ".gitea/workflows/rerun-failed.yml"
+		wfFileContent := `name: rerun-failed
+on: push
+jobs:
+  job1:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo job1
+  job2:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo job2
+  job3:
+    runs-on: ubuntu-latest
+    needs: [job2]
+    steps:
+      - run: echo job3
+`
+		opts := getWorkflowCreateFileOptions(user2, apiRepo.DefaultBranch, "create "+wfTreePath, wfFileContent)
+		createWorkflowFile(t, token, user2.Name, apiRepo.Name, wfTreePath, opts)
+
+		// the first attempt: job1 succeeds, job2 fails and job3 is skipped
+		for range 2 {
+			task := runner.fetchTask(t)
+			assert.Equal(t, "1", task.Context.GetFields()["run_attempt"].GetStringValue())
+			assert.NotContains(t, task.Vars, "ACTIONS_STEP_DEBUG")
+			result := runnerv1.Result_RESULT_SUCCESS
+			if getTaskJobNameByTaskID(t, token, user2.Name, apiRepo.Name, task.Id) == "job2" {
+				result = runnerv1.Result_RESULT_FAILURE
+			}
+			runner.execTask(t, task, &mockTaskOutcome{
+				result:  result,
+				logRows: []*runnerv1.LogRow{{Time: timestamppb.Now(), Content: "attempt 1"}},
+			})
+		}
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: apiRepo.ID, WorkflowID: "rerun-failed.yml"})
+		assert.Equal(t, actions_model.StatusFailure, run.Status)
+		assert.EqualValues(t, 1, run.Attempt)
+		job1 := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{RunID: run.ID, JobID: "job1"})
+		job2 := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{RunID: run.ID, JobID: "job2"})
+		runLink := fmt.Sprintf("/%s/%s/actions/runs/%d", user2.Name, apiRepo.Name, run.Index)
+
+		// rerun the failed jobs with debug logging
+		req := NewRequestWithValues(t, "POST", runLink+"/rerun-failed?debug=true", map[string]string{
+			"_csrf": GetUserCSRFToken(t, session),
+		})
+		session.MakeRequest(t, req, http.StatusOK)
+		run = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: run.ID})
+		assert.EqualValues(t, 2, run.Attempt)
+		assert.True(t, run.IsDebugEnabled)
+		unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunAttempt{RunID: run.ID, Attempt: 1, Status: actions_model.StatusFailure})
+
+		// only job2 and job3 are rerun, and the successful job1 keeps its result
+		for _, jobID := range []string{"job2", "job3"} {
+			task := runner.fetchTask(t)
+			assert.Equal(t, jobID, getTaskJobNameByTaskID(t, token, user2.Name, apiRepo.Name, task.Id))
+			assert.Equal(t, "2", task.Context.GetFields()["run_attempt"].GetStringValue())
+			assert.Equal(t, "true", task.Vars["ACTIONS_STEP_DEBUG"])
+			assert.Equal(t, "true", task.Vars["ACTIONS_RUNNER_DEBUG"])
+			runner.execTask(t, task, &mockTaskOutcome{result: runnerv1.Result_RESULT_SUCCESS})
+		}
+		run = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: run.ID})
+		assert.Equal(t, actions_model.StatusSuccess, run.Status)
+		assert.Equal(t, job1.TaskID, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: job1.ID}).TaskID)
+
+		// nothing to rerun if no jobs failed
+		req = NewRequestWithValues(t, "POST", runLink+"/rerun-failed", map[string]string{
+			"_csrf": GetUserCSRFToken(t, session),
+		})
+		session.MakeRequest(t, req, http.StatusBadRequest)
+
+		// the previous attempt is still available in the API
+		var apiRun api.ActionWorkflowRun
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs/%d/attempts/1", user2.Name, apiRepo.Name, run.ID)).AddTokenAuth(token)
+		DecodeJSON(t, MakeRequest(t, req, http.StatusOK), &apiRun)
+		assert.EqualValues(t, 1, apiRun.RunAttempt)
+		assert.Equal(t, "failure", apiRun.Conclusion)
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs/%d/attempts/2", user2.Name, apiRepo.Name, run.ID)).AddTokenAuth(token)
+		DecodeJSON(t, MakeRequest(t, req, http.StatusOK), &apiRun)
+		assert.EqualValues(t, 2, apiRun.RunAttempt)
+		assert.Equal(t, "success", apiRun.Conclusion)
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs/%d/attempts/3", user2.Name, apiRepo.Name, run.ID)).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		var apiJobs api.ActionWorkflowJobsResponse
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs/%d/attempts/1/jobs", user2.Name, apiRepo.Name, run.ID)).AddTokenAuth(token)
+		DecodeJSON(t, MakeRequest(t, req, http.StatusOK), &apiJobs)
+		require.Len(t, apiJobs.Entries, 3)
+		conclusions := make(map[string]string, len(apiJobs.Entries))
+		for _, job := range apiJobs.Entries {
+			conclusions[job.Name] = job.Conclusion
+		}
+		assert.Equal(t, map[string]string{"job1": "success", "job2": "failure", "job3": "skipped"}, conclusions)
+
+		// the previous attempt is still available in the UI, including the logs
+		req = NewRequestWithValues(t, "POST", runLink+"/jobs/1?attempt=1", map[string]string{
+			"_csrf": GetUserCSRFToken(t, session),
+		})
+		var viewResp actions.ViewResponse
+		require.NoError(t, json.Unmarshal(session.MakeRequest(t, req, http.StatusOK).Body.Bytes(), &viewResp))
+		assert.EqualValues(t, 1, viewResp.State.Run.Attempt)
+		assert.False(t, viewResp.State.Run.IsLatestAttempt)
+		assert.False(t, viewResp.State.Run.CanRerun)
+		assert.Len(t, viewResp.State.Run.Attempts, 2)
+		assert.Equal(t, actions_model.StatusFailure.String(), viewResp.State.Run.Jobs[1].Status)
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/jobs/1/logs?attempt=1", runLink))
+		session.MakeRequest(t, req, http.StatusOK)
+		assert.NotEqual(t, job2.TaskID, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: job2.ID}).TaskID)
+	})
+}
+
+func TestActionsRerunConcurrency(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		session := loginUser(t, user2.Name)
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository, auth_model.AccessTokenScopeWriteUser)
+
+		// prepareRuns creates a failed run and a waiting run of the same concurrency group, then reruns the failed one
+		prepareRuns := func(t *testing.T, repoName, cancelInProgress string) (*actions_model.ActionRun, *actions_model.ActionRun, *mockRunner) {
+			apiRepo := createActionsTestRepo(t, token, repoName, false)
+			runner := newMockRunner()
+			runner.registerAsRepoRunner(t, user2.Name, apiRepo.Name, "mock-runner", []string{"ubuntu-latest"}, false)
+
+			wfTreePath := ".gitea/workflows/rerun-concurrency.yml"
+			wfFileContent := `name: rerun-concurrency
+on:
+  push:
+  workflow_dispatch:
+concurrency:
+  group: rerun-concurrency
+  cancel-in-progress: ` + cancelInProgress + `
+jobs:
+  job1:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo job1
+`
+			opts := getWorkflowCreateFileOptions(user2, apiRepo.DefaultBranch, "create "+wfTreePath, wfFileContent)
+			createWorkflowFile(t, token, user2.Name, apiRepo.Name, wfTreePath, opts)
+			runner.execTask(t, runner.fetchTask(t), &mockTaskOutcome{result: runnerv1.Result_RESULT_FAILURE})
+			run1 := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: apiRepo.ID, Event: "push"})
+			assert.Equal(t, actions_model.StatusFailure, run1.Status)
+
+			req := NewRequestWithURLValues(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/actions/workflows/rerun-concurrency.yml/dispatches", user2.Name, apiRepo.Name), url.Values{"ref": {apiRepo.DefaultBranch}}).
+				AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusNoContent)
+			run2 := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: apiRepo.ID, Event: "workflow_dispatch"})
+			assert.Equal(t, actions_model.StatusWaiting, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{RunID: run2.ID}).Status)
+
+			req = NewRequestWithValues(t, "POST", fmt.Sprintf("/%s/%s/actions/runs/%d/rerun", user2.Name, apiRepo.Name, run1.Index), map[string]string{
+				"_csrf": GetUserCSRFToken(t, session),
+			})
+			session.MakeRequest(t, req, http.StatusOK)
+			return run1, run2, runner
+		}
+
+		fetchTaskOfRun := func(t *testing.T, runner *mockRunner) int64 {
+			task := runner.fetchTask(t)
+			runner.execTask(t, task, &mockTaskOutcome{result: runnerv1.Result_RESULT_SUCCESS})
+			actionTask := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: task.Id})
+			return unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: actionTask.JobID}).RunID
+		}
+
+		t.Run("Block", func(t *testing.T) {
+			run1, run2, runner := prepareRuns(t, "actions-rerun-concurrency-block", "false")
+
+			// the rerun job waits for the run in progress of the same concurrency group
+			assert.Equal(t, actions_model.StatusBlocked, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{RunID: run1.ID}).Status)
+			assert.Equal(t, run2.ID, fetchTaskOfRun(t, runner))
+			assert.Equal(t, run1.ID, fetchTaskOfRun(t, runner))
+			assert.Equal(t, actions_model.StatusSuccess, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: run1.ID}).Status)
+		})
+
+		t.Run("CancelInProgress", func(t *testing.T) {
+			run1, run2, runner := prepareRuns(t, "actions-rerun-concurrency-cancel", "true")
+
+			// the rerun cancels the run in progress of the same concurrency group
+			assert.Equal(t, actions_model.StatusCancelled, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{RunID: run2.ID}).Status)
+			assert.Equal(t, run1.ID, fetchTaskOfRun(t, runner))
+			assert.Equal(t, actions_model.StatusSuccess, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: run1.ID}).Status)
+		})
+	})
+}
+
+func TestActionsRerunFailFastMatrixJob(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		session := loginUser(t, user2.Name)
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository, auth_model.AccessTokenScopeWriteUser)
+
+		apiRepo := createActionsTestRepo(t, token, "actions-rerun-fail-fast", false)
+		runner := newMockRunner()
+		runner.registerAsRepoRunner(t, user2.Name, apiRepo.Name, "mock-runner", []string{"ubuntu-latest"}, false)
+
+		wfTreePath := ".gitea/workflows/rerun-fail-fast.yml"
+		wfFileContent := `name: rerun-fail-fast
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    strategy:
+      fail-fast: true
+      max-parallel: 1
+      matrix:
+        os: [linux, windows]
+    steps:
+      - run: echo ${{ matrix.os }}
+`
+		opts := getWorkflowCreateFileOptions(user2, apiRepo.DefaultBranch, "create "+wfTreePath, wfFileContent)
+		createWorkflowFile(t, token, user2.Name, apiRepo.Name, wfTreePath, opts)
+
+		// the first attempt: the first job fails and the second one is cancelled by fail-fast before it starts
+		task := runner.fetchTask(t)
+		runner.execTask(t, task, &mockTaskOutcome{result: runnerv1.Result_RESULT_FAILURE})
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: apiRepo.ID, WorkflowID: "rerun-fail-fast.yml"})
+		jobs, err := actions_model.GetRunJobsByRunID(t.Context(), run.ID)
+		require.NoError(t, err)
+		require.Len(t, jobs, 2)
+		failedIndex, cancelledIndex := 0, 1
+		assert.Equal(t, actions_model.StatusFailure, jobs[failedIndex].Status)
+		assert.Equal(t, actions_model.StatusCancelled, jobs[cancelledIndex].Status)
+
+		rerunJob := func(t *testing.T, jobIndex int) {
+			req := NewRequestWithValues(t, "POST", fmt.Sprintf("/%s/%s/actions/runs/%d/jobs/%d/rerun", user2.Name, apiRepo.Name, run.Index, jobIndex), map[string]string{
+				"_csrf": GetUserCSRFToken(t, session),
+			})
+			session.MakeRequest(t, req, http.StatusOK)
+		}
+
+		// rerun the cancelled job, it isn't cancelled by the job which failed in the previous attempt
+		rerunJob(t, cancelledIndex)
+		task = runner.fetchTask(t)
+		assert.Equal(t, jobs[cancelledIndex].ID, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: task.Id}).JobID)
+		runner.execTask(t, task, &mockTaskOutcome{result: runnerv1.Result_RESULT_FAILURE})
+
+		// rerun a single failed job while the other one has also failed in the previous attempt
+		rerunJob(t, failedIndex)
+		task = runner.fetchTask(t)
+		assert.Equal(t, jobs[failedIndex].ID, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: task.Id}).JobID)
+		runner.execTask(t, task, &mockTaskOutcome{result: runnerv1.Result_RESULT_SUCCESS})
+		run = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: run.ID})
+		assert.EqualValues(t, 3, run.Attempt)
+		assert.Equal(t, actions_model.StatusFailure, run.Status)
+		assert.Equal(t, actions_model.StatusFailure, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: jobs[cancelledIndex].ID}).Status)
+	})
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/modules/timeutil"
+	actions_service "code.gitea.io/gitea/services/actions"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionsSchedules(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		session := loginUser(t, user2.Name)
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository, auth_model.AccessTokenScopeWriteUser)
+
+		apiRepo := createActionsTestRepo(t, token, "actions-schedules", false)
+
+		wfTreePath := ".gitea/workflows/nightly.yml"
+		wfFileContent := `name: nightly
+on:
+  schedule:
+    - cron: "30 2 * * *"
+      timezone: Europe/Berlin
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo test
+`
+		opts := getWorkflowCreateFileOptions(user2, apiRepo.DefaultBranch, "create "+wfTreePath, wfFileContent)
+		createWorkflowFile(t, token, user2.Name, apiRepo.Name, wfTreePath, opts)
+
+		spec := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionScheduleSpec{RepoID: apiRepo.ID})
+		assert.Equal(t, "CRON_TZ=Europe/Berlin 30 2 * * *", spec.Spec)
+
+		listSchedules := func(t *testing.T) *api.ActionWorkflowSchedule {
+			req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/schedules", user2.Name, apiRepo.Name)).AddTokenAuth(token)
+			resp := MakeRequest(t, req, http.StatusOK)
+			var schedules []*api.ActionWorkflowSchedule
+			DecodeJSON(t, resp, &schedules)
+			require.Len(t, schedules, 1)
+			assert.Equal(t, "1", resp.Header().Get("X-Total-Count"))
+			return schedules[0]
+		}
+
+		schedule := listSchedules(t)
+		assert.Equal(t, "nightly.yml", schedule.WorkflowID)
+		assert.Equal(t, "30 2 * * *", schedule.Cron)
+		assert.Equal(t, "Europe/Berlin", schedule.Timezone)
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		require.NoError(t, err)
+		nextRunAt := schedule.NextRunAt.In(berlin)
+		assert.Equal(t, 2, nextRunAt.Hour())
+		assert.Equal(t, 30, nextRunAt.Minute())
+		require.Len(t, schedule.UpcomingRunsAt, 5)
+		assert.True(t, schedule.UpcomingRunsAt[0].Equal(schedule.NextRunAt))
+		assert.Nil(t, schedule.LastScheduledAt)
+		assert.Nil(t, schedule.LastSkippedAt)
+		assert.Nil(t, schedule.LastRun)
+
+		setNext := func(t *testing.T, next time.Time) timeutil.TimeStamp {
+			spec.Next = timeutil.TimeStamp(next.Unix())
+			require.NoError(t, actions_model.UpdateScheduleSpec(db.DefaultContext, spec, "next"))
+			return spec.Next
+		}
+
+		t.Run("CatchUpNone", func(t *testing.T) {
+			defer test.MockVariableValue(&setting.Actions.ScheduleCatchUp, "none")()
+			missed := setNext(t, time.Now().Add(-time.Hour))
+			require.NoError(t, actions_service.StartScheduleTasks(db.DefaultContext))
+
+			spec = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionScheduleSpec{ID: spec.ID})
+			assert.Equal(t, missed, spec.LastSkipped)
+			assert.Equal(t, actions_model.ScheduleSkipReasonMissed, spec.LastSkipReason)
+			assert.Greater(t, spec.Next.AsTime(), time.Now())
+			unittest.AssertNotExistsBean(t, &actions_model.ActionRun{RepoID: apiRepo.ID, WorkflowID: "nightly.yml"})
+
+			schedule := listSchedules(t)
+			require.NotNil(t, schedule.LastSkippedAt)
+			assert.EqualValues(t, missed, schedule.LastSkippedAt.Unix())
+			assert.Equal(t, "missed", schedule.LastSkipReason)
+
+			// the skipped runs are shown on the actions page of the workflow
+			req := NewRequest(t, "GET", fmt.Sprintf("/%s/%s/actions?workflow=nightly.yml", user2.Name, apiRepo.Name))
+			resp := session.MakeRequest(t, req, http.StatusOK)
+			assert.Contains(t, resp.Body.String(), "because it was missed while the server was unavailable")
+		})
+
+		t.Run("CatchUpOnce", func(t *testing.T) {
+			missed := setNext(t, time.Now().Add(-time.Hour))
+			require.NoError(t, actions_service.StartScheduleTasks(db.DefaultContext))
+
+			spec = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionScheduleSpec{ID: spec.ID})
+			assert.Equal(t, missed, spec.Prev)
+			assert.Greater(t, spec.Next.AsTime(), time.Now())
+			run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: apiRepo.ID, WorkflowID: "nightly.yml"})
+			assert.Equal(t, "schedule", run.TriggerEvent)
+
+			schedule := listSchedules(t)
+			require.NotNil(t, schedule.LastScheduledAt)
+			assert.EqualValues(t, missed, schedule.LastScheduledAt.Unix())
+			require.NotNil(t, schedule.LastRun)
+			assert.Equal(t, run.ID, schedule.LastRun.ID)
+		})
+	})
+}
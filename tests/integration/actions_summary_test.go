@@ -0,0 +1,100 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/web/repo/actions"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionsStepSummaries(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		session := loginUser(t, user2.Name)
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository, auth_model.AccessTokenScopeWriteUser)
+
+		apiRepo := createActionsTestRepo(t, token, "actions-step-summaries", false)
+		runner := newMockRunner()
+		runner.registerAsRepoRunner(t, user2.Name, apiRepo.Name, "mock-runner", []string{"ubuntu-latest"}, false)
+
+		wfTreePath := ".gitea/workflows/step-summaries.yml"
+		wfFileContent := `name: step-summaries
+on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    outputs:
+      result: ${{ steps.report.outputs.result }}
+    steps:
+      - run: echo "## Test results" >> $GITHUB_STEP_SUMMARY
+      - id: report
+        run: echo "result=ok" >> $GITHUB_OUTPUT
+`
+		opts := getWorkflowCreateFileOptions(user2, apiRepo.DefaultBranch, "create "+wfTreePath, wfFileContent)
+		createWorkflowFile(t, token, user2.Name, apiRepo.Name, wfTreePath, opts)
+
+		task := runner.fetchTask(t)
+		runtimeToken := task.Context.GetFields()["gitea_runtime_token"].GetStringValue()
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: apiRepo.ID, WorkflowID: "step-summaries.yml"})
+		uploadStepSummary := func(stepIndex, content string, expectedStatus int) {
+			req := NewRequestWithBody(t, "PUT", fmt.Sprintf("/api/actions_pipeline/_apis/pipelines/workflows/%d/steps/%s/summary", run.ID, stepIndex), strings.NewReader(content)).
+				AddTokenAuth(runtimeToken)
+			MakeRequest(t, req, expectedStatus)
+		}
+		// the summaries are uploaded by the runner with the runtime token while the task is running
+		uploadStepSummary("1", "second step", http.StatusCreated)
+		uploadStepSummary("0", "## Test results\n\n| passed | failed |\n| --- | --- |\n| 10 | 0 |\n<script>alert(1)</script>", http.StatusCreated)
+		uploadStepSummary("0", "resent summaries are ignored", http.StatusCreated)
+		uploadStepSummary("bad", "invalid step index", http.StatusBadRequest)
+		uploadStepSummary("2", strings.Repeat("a", 1024*1024+1), http.StatusRequestEntityTooLarge)
+		req := NewRequestWithBody(t, "PUT", fmt.Sprintf("/api/actions_pipeline/_apis/pipelines/workflows/%d/steps/0/summary", run.ID), strings.NewReader("no token"))
+		MakeRequest(t, req, http.StatusUnauthorized)
+
+		runner.execTask(t, task, &mockTaskOutcome{
+			result:  runnerv1.Result_RESULT_SUCCESS,
+			outputs: map[string]string{"result": "ok"},
+		})
+
+		// the summaries are available in the API
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs/%d/summaries", user2.Name, apiRepo.Name, run.ID)).AddTokenAuth(token)
+		var apiSummaries []*api.ActionWorkflowStepSummary
+		DecodeJSON(t, MakeRequest(t, req, http.StatusOK), &apiSummaries)
+		require.Len(t, apiSummaries, 2)
+		assert.Equal(t, "test", apiSummaries[0].JobName)
+		assert.EqualValues(t, 0, apiSummaries[0].StepNumber)
+		assert.Contains(t, apiSummaries[0].Content, "## Test results")
+		assert.EqualValues(t, 1, apiSummaries[1].StepNumber)
+		assert.Equal(t, "second step", apiSummaries[1].Content)
+
+		// the summaries are rendered and sanitized on the run page
+		runLink := fmt.Sprintf("/%s/%s/actions/runs/%d", user2.Name, apiRepo.Name, run.Index)
+		req = NewRequest(t, "GET", runLink+"/summary")
+		session.MakeRequest(t, req, http.StatusOK)
+		req = NewRequestWithValues(t, "POST", runLink+"/summary", map[string]string{
+			"_csrf": GetUserCSRFToken(t, session),
+		})
+		var viewResp actions.ViewSummaryResponse
+		require.NoError(t, json.Unmarshal(session.MakeRequest(t, req, http.StatusOK).Body.Bytes(), &viewResp))
+		require.Len(t, viewResp.Summaries, 2)
+		html := string(viewResp.Summaries[0].HTML)
+		assert.Contains(t, html, "<h2")
+		assert.Contains(t, html, "<table>")
+		assert.NotContains(t, html, "<script>")
+	})
+}
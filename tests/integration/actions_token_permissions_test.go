@@ -0,0 +1,117 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/perm"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsTokenPermissions(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		session := loginUser(t, user2.Name)
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository, auth_model.AccessTokenScopeWriteUser)
+
+		apiRepo := createActionsTestRepo(t, token, "actions-token-permissions", false)
+		runner := newMockRunner()
+		runner.registerAsRepoRunner(t, user2.Name, apiRepo.Name, "mock-runner", []string{"ubuntu-latest"}, false)
+
+		// the repository follows the owner by default
+		req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/permissions/workflow", user2.Name, apiRepo.Name)).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var workflowPerms api.ActionWorkflowPermissions
+		DecodeJSON(t, resp, &workflowPerms)
+		assert.Equal(t, "write", workflowPerms.DefaultWorkflowPermissions)
+		assert.True(t, workflowPerms.Inherited)
+
+		req = NewRequestWithJSON(t, "PUT", fmt.Sprintf("/api/v1/repos/%s/%s/actions/permissions/workflow", user2.Name, apiRepo.Name), &api.EditActionWorkflowPermissionsOption{
+			DefaultWorkflowPermissions: "invalid",
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+		req = NewRequestWithJSON(t, "PUT", fmt.Sprintf("/api/v1/repos/%s/%s/actions/permissions/workflow", user2.Name, apiRepo.Name), &api.EditActionWorkflowPermissionsOption{
+			DefaultWorkflowPermissions: "read",
+		}).AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &workflowPerms)
+		assert.Equal(t, "read", workflowPerms.DefaultWorkflowPermissions)
+		assert.False(t, workflowPerms.Inherited)
+
+		wfTreePath := ".gitea/workflows/token-permissions.yml"
+		wfFileContent := `name: token-permissions
+on: push
+jobs:
+  job-default:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo default
+  job-declared:
+    runs-on: ubuntu-latest
+    permissions:
+      contents: read
+      issues: write
+    steps:
+      - run: echo declared
+`
+		opts := getWorkflowCreateFileOptions(user2, apiRepo.DefaultBranch, "create "+wfTreePath, wfFileContent)
+		createWorkflowFile(t, token, user2.Name, apiRepo.Name, wfTreePath, opts)
+
+		taskTokens := make(map[string]string)
+		for range 2 {
+			task := runner.fetchTask(t)
+			actionTask := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: task.Id})
+			actionRunJob := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: actionTask.JobID})
+			switch actionRunJob.JobID {
+			case "job-default":
+				assert.Equal(t, actions_model.NewRepoTokenPermissions(perm.AccessModeRead), actionTask.TokenPermissions)
+			case "job-declared":
+				assert.Equal(t, actions_model.TokenPermissions{
+					actions_model.TokenScopeContents: perm.AccessModeRead,
+					actions_model.TokenScopeIssues:   perm.AccessModeWrite,
+				}, actionTask.TokenPermissions)
+			}
+			taskTokens[actionRunJob.JobID] = task.Context.GetFields()["token"].GetStringValue()
+		}
+
+		createLabel := func(taskToken string, expectedStatus int) {
+			req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/labels", user2.Name, apiRepo.Name), &api.CreateLabelOption{
+				Name:  "label-" + taskToken[len(taskToken)-8:],
+				Color: "abcdef",
+			}).AddTokenAuth(taskToken)
+			MakeRequest(t, req, expectedStatus)
+		}
+		createFile := func(taskToken string, expectedStatus int) {
+			opts := getWorkflowCreateFileOptions(user2, apiRepo.DefaultBranch, "create file by actions", "content")
+			req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/contents/%s", user2.Name, apiRepo.Name, "actions.txt"), opts).
+				AddTokenAuth(taskToken)
+			MakeRequest(t, req, expectedStatus)
+		}
+		readFile := func(taskToken string, expectedStatus int) {
+			req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/contents/%s", user2.Name, apiRepo.Name, wfTreePath)).
+				AddTokenAuth(taskToken)
+			MakeRequest(t, req, expectedStatus)
+		}
+
+		// the default permissions of the repository are read-only
+		readFile(taskTokens["job-default"], http.StatusOK)
+		createFile(taskTokens["job-default"], http.StatusForbidden)
+		createLabel(taskTokens["job-default"], http.StatusForbidden)
+
+		// the declared permissions override the default permissions
+		readFile(taskTokens["job-declared"], http.StatusOK)
+		createFile(taskTokens["job-declared"], http.StatusForbidden)
+		createLabel(taskTokens["job-declared"], http.StatusCreated)
+	})
+}
@@ -0,0 +1,167 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionsUsage(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		session := loginUser(t, user2.Name)
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository, auth_model.AccessTokenScopeWriteUser)
+		adminToken := getTokenForLoggedInUser(t, loginUser(t, "user1"), auth_model.AccessTokenScopeWriteAdmin)
+
+		apiRepo := createActionsTestRepo(t, token, "actions-usage", false)
+		repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: apiRepo.ID})
+		runner := newMockRunner()
+		runner.registerAsRepoRunner(t, user2.Name, repo.Name, "mock-runner", []string{"ubuntu-latest"}, false)
+
+		treePath := ".gitea/workflows/usage.yml"
+		fileContent := `name: usage
+on: push
+jobs:
+  job1:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hello
+`
+		opts := getWorkflowCreateFileOptions(user2, repo.DefaultBranch, "create "+treePath, fileContent)
+		createWorkflowFile(t, token, user2.Name, repo.Name, treePath, opts)
+		task := runner.fetchTask(t)
+		runner.execTask(t, task, &mockTaskOutcome{result: runnerv1.Result_RESULT_SUCCESS})
+
+		// the task has run for 90 seconds
+		actionTask := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: task.Id})
+		_, err := db.GetEngine(t.Context()).ID(task.Id).Cols("started").
+			Update(&actions_model.ActionTask{Started: actionTask.Stopped - 90})
+		require.NoError(t, err)
+
+		pushFile := func(t *testing.T, name string) *actions_model.ActionRun {
+			opts := getWorkflowCreateFileOptions(user2, repo.DefaultBranch, "create "+name, "hello")
+			createWorkflowFile(t, token, user2.Name, repo.Name, name, opts)
+			run, err := actions_model.GetLatestRun(t.Context(), repo.ID)
+			require.NoError(t, err)
+			return run
+		}
+
+		t.Run("Report", func(t *testing.T) {
+			req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/usage", user2.Name, repo.Name)).AddTokenAuth(token)
+			resp := MakeRequest(t, req, http.StatusOK)
+			var report api.ActionUsageReport
+			DecodeJSON(t, resp, &report)
+			assert.Equal(t, time.Now().UTC().Format("2006-01"), report.Month)
+			assert.EqualValues(t, 90, report.TotalDurationSeconds)
+			assert.EqualValues(t, 2, report.TotalMinutes)
+			assert.Nil(t, report.Quota)
+			if assert.Len(t, report.Usages, 1) {
+				assert.Equal(t, repo.FullName(), report.Usages[0].Repository)
+				assert.Equal(t, []string{"ubuntu-latest"}, report.Usages[0].Labels)
+				assert.EqualValues(t, 1, report.Usages[0].Jobs)
+				assert.EqualValues(t, 2, report.Usages[0].Minutes)
+			}
+
+			req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/usage?format=csv", user2.Name, repo.Name)).AddTokenAuth(token)
+			resp = MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, "text/csv; charset=utf-8", resp.Header().Get("Content-Type"))
+			assert.Equal(t, []string{
+				"repository,labels,jobs,duration_seconds,minutes",
+				repo.FullName() + ",ubuntu-latest,1,90,2",
+			}, strings.Split(strings.TrimSpace(resp.Body.String()), "\n"))
+
+			// no jobs have run in the month
+			req = NewRequest(t, "GET", "/api/v1/user/actions/usage?month=2020-01").AddTokenAuth(token)
+			resp = MakeRequest(t, req, http.StatusOK)
+			report = api.ActionUsageReport{}
+			DecodeJSON(t, resp, &report)
+			assert.Empty(t, report.Usages)
+			if assert.NotNil(t, report.Quota) {
+				assert.Zero(t, report.Quota.Minutes)
+				assert.False(t, report.Quota.Exceeded)
+			}
+
+			req = NewRequest(t, "GET", "/api/v1/user/actions/usage?month=2020-1-1").AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusUnprocessableEntity)
+		})
+
+		quotaURL := fmt.Sprintf("/api/v1/admin/users/%s/actions/usage-quota", user2.Name)
+		setQuota := func(t *testing.T, policy string) {
+			req := NewRequestWithJSON(t, "PUT", quotaURL, &api.EditActionUsageQuotaOption{Minutes: 1, Policy: policy}).AddTokenAuth(adminToken)
+			resp := MakeRequest(t, req, http.StatusOK)
+			var quota api.ActionUsageQuota
+			DecodeJSON(t, resp, &quota)
+			assert.EqualValues(t, 1, quota.Minutes)
+			assert.Equal(t, policy, quota.Policy)
+			assert.True(t, quota.Exceeded)
+		}
+
+		t.Run("QuotaAPI", func(t *testing.T) {
+			req := NewRequestWithJSON(t, "PUT", quotaURL, &api.EditActionUsageQuotaOption{Minutes: 1}).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusForbidden)
+			req = NewRequestWithJSON(t, "PUT", quotaURL, &api.EditActionUsageQuotaOption{Minutes: 1, Policy: "unknown"}).AddTokenAuth(adminToken)
+			MakeRequest(t, req, http.StatusUnprocessableEntity)
+		})
+
+		t.Run("SoftQuota", func(t *testing.T) {
+			setQuota(t, "soft")
+			run := pushFile(t, "soft.txt")
+			assert.True(t, run.QuotaExceeded)
+			assert.Equal(t, actions_model.StatusWaiting, run.Status)
+			task := runner.fetchTask(t)
+			runner.execTask(t, task, &mockTaskOutcome{result: runnerv1.Result_RESULT_SUCCESS})
+		})
+
+		t.Run("HardQuota", func(t *testing.T) {
+			setQuota(t, "hard")
+			run := pushFile(t, "hard.txt")
+			assert.True(t, run.QuotaExceeded)
+			assert.Equal(t, actions_model.StatusCancelled, run.Status)
+			assert.NotZero(t, run.Stopped)
+			jobs, err := actions_model.GetRunJobsByRunID(t.Context(), run.ID)
+			require.NoError(t, err)
+			for _, job := range jobs {
+				assert.Equal(t, actions_model.StatusCancelled, job.Status)
+				assert.Zero(t, job.TaskID)
+			}
+
+			req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs/%d", user2.Name, repo.Name, run.ID)).AddTokenAuth(token)
+			resp := MakeRequest(t, req, http.StatusOK)
+			var apiRun api.ActionWorkflowRun
+			DecodeJSON(t, resp, &apiRun)
+			assert.True(t, apiRun.QuotaExceeded)
+			assert.Equal(t, "cancelled", apiRun.Conclusion)
+
+			req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs/%d/rerun", user2.Name, repo.Name, run.ID), &api.RerunActionRunOption{}).
+				AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusBadRequest)
+
+			// the runs start again after the quota is removed
+			req = NewRequestWithJSON(t, "PUT", quotaURL, &api.EditActionUsageQuotaOption{}).AddTokenAuth(adminToken)
+			MakeRequest(t, req, http.StatusOK)
+			run = pushFile(t, "unlimited.txt")
+			assert.False(t, run.QuotaExceeded)
+			task := runner.fetchTask(t)
+			runner.execTask(t, task, &mockTaskOutcome{result: runnerv1.Result_RESULT_SUCCESS})
+			assert.Equal(t, actions_model.StatusSuccess, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: run.ID}).Status)
+		})
+	})
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkflowDispatchInputsValidation(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		session := loginUser(t, user2.Name)
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository, auth_model.AccessTokenScopeWriteUser)
+
+		apiRepo := createActionsTestRepo(t, token, "workflow-dispatch-inputs", false)
+		wfTreePath := ".gitea/workflows/dispatch-inputs.yml"
+		wfFileContent := `name: dispatch-inputs
+on:
+  workflow_dispatch:
+    inputs:
+      name:
+        description: the name to greet
+        required: true
+      level:
+        type: choice
+        options: [debug, info]
+        default: info
+      dry_run:
+        type: boolean
+      count:
+        type: number
+      target:
+        type: environment
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo ${{ inputs.name }}
+`
+		opts := getWorkflowCreateFileOptions(user2, apiRepo.DefaultBranch, "create "+wfTreePath, wfFileContent)
+		createWorkflowFile(t, token, user2.Name, apiRepo.Name, wfTreePath, opts)
+		workflowURL := fmt.Sprintf("/api/v1/repos/%s/%s/actions/workflows/dispatch-inputs.yml", user2.Name, apiRepo.Name)
+
+		t.Run("Schema", func(t *testing.T) {
+			req := NewRequest(t, "GET", workflowURL).AddTokenAuth(token)
+			var workflow api.ActionWorkflow
+			DecodeJSON(t, MakeRequest(t, req, http.StatusOK), &workflow)
+			require.Len(t, workflow.Inputs, 5)
+			assert.Equal(t, &api.ActionWorkflowInput{Name: "count", Type: "number"}, workflow.Inputs[0])
+			assert.Equal(t, &api.ActionWorkflowInput{Name: "dry_run", Type: "boolean"}, workflow.Inputs[1])
+			assert.Equal(t, &api.ActionWorkflowInput{Name: "level", Type: "choice", Default: "info", Options: []string{"debug", "info"}}, workflow.Inputs[2])
+			assert.Equal(t, &api.ActionWorkflowInput{Name: "name", Description: "the name to greet", Type: "string", Required: true}, workflow.Inputs[3])
+			assert.Equal(t, &api.ActionWorkflowInput{Name: "target", Type: "environment"}, workflow.Inputs[4])
+		})
+
+		t.Run("Invalid", func(t *testing.T) {
+			req := NewRequestWithJSON(t, "POST", workflowURL+"/dispatches", &api.CreateActionWorkflowDispatch{
+				Ref: apiRepo.DefaultBranch,
+				Inputs: map[string]string{
+					"level":   "trace",
+					"dry_run": "maybe",
+					"count":   "ten",
+					"target":  "production",
+					"nmae":    "typo",
+				},
+			}).AddTokenAuth(token)
+			resp := MakeRequest(t, req, http.StatusUnprocessableEntity)
+			var apiErr struct {
+				Message       string            `json:"message"`
+				InvalidInputs map[string]string `json:"invalidInputs"`
+			}
+			DecodeJSON(t, resp, &apiErr)
+			assert.Equal(t, map[string]string{
+				"name":    "input is required",
+				"level":   `"trace" is not one of the options ["debug" "info"]`,
+				"dry_run": `"maybe" is not a boolean`,
+				"count":   `"ten" is not a number`,
+				"target":  `environment "production" doesn't exist`,
+				"nmae":    "unexpected input",
+			}, apiErr.InvalidInputs)
+			assert.Contains(t, apiErr.Message, "invalid inputs: ")
+			unittest.AssertNotExistsBean(t, &actions_model.ActionRun{RepoID: apiRepo.ID})
+
+			// the web UI shows the error
+			req = NewRequestWithValues(t, "POST", fmt.Sprintf("/%s/%s/actions/run?workflow=dispatch-inputs.yml", user2.Name, apiRepo.Name), map[string]string{
+				"_csrf": GetUserCSRFToken(t, session),
+				"ref":   "refs/heads/" + apiRepo.DefaultBranch,
+				"level": "trace",
+			})
+			session.MakeRequest(t, req, http.StatusSeeOther)
+			flashMsg := session.GetCookieFlashMessage()
+			assert.Contains(t, flashMsg.ErrorMsg, "The workflow inputs are invalid")
+			unittest.AssertNotExistsBean(t, &actions_model.ActionRun{RepoID: apiRepo.ID})
+		})
+
+		t.Run("Valid", func(t *testing.T) {
+			req := NewRequestWithJSON(t, "PUT", fmt.Sprintf("/api/v1/repos/%s/%s/environments/production", user2.Name, apiRepo.Name), &api.CreateOrUpdateEnvironmentOption{}).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusCreated)
+
+			req = NewRequestWithJSON(t, "POST", workflowURL+"/dispatches", &api.CreateActionWorkflowDispatch{
+				Ref: apiRepo.DefaultBranch,
+				Inputs: map[string]string{
+					"name":    "gitea",
+					"dry_run": "True",
+					"count":   "1.5",
+					"target":  "production",
+				},
+			}).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusNoContent)
+
+			run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: apiRepo.ID, WorkflowID: "dispatch-inputs.yml"})
+			var payload api.WorkflowDispatchPayload
+			require.NoError(t, json.Unmarshal([]byte(run.EventPayload), &payload))
+			assert.Equal(t, map[string]any{
+				"name":    "gitea",
+				"level":   "info",
+				"dry_run": "true",
+				"count":   "1.5",
+				"target":  "production",
+			}, payload.Inputs)
+		})
+	})
+}
@@ -15,7 +15,9 @@ import (
 	"testing"
 	"time"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
@@ -485,6 +487,79 @@ func TestActionsArtifactV4ListAndGetPublicApi(t *testing.T) {
 	}
 }
 
+func TestActionsArtifactV4RunAttemptsPublicApi(t *testing.T) {
+	defer prepareTestEnvActionsArtifacts(t)()
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 4})
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: repo.OwnerID})
+	session := loginUser(t, user.Name)
+	token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeReadRepository)
+
+	// rerun the run 792, the first attempt has uploaded "artifact-v4-download"
+	_, err := db.GetEngine(t.Context()).ID(792).Cols("attempt").Update(&actions_model.ActionRun{Attempt: 2})
+	assert.NoError(t, err)
+	assert.NoError(t, db.Insert(t.Context(), &actions_model.ActionRunAttempt{RepoID: repo.ID, RunID: 792, Attempt: 1, Status: actions_model.StatusSuccess}))
+
+	runnerToken, err := actions_service.CreateAuthorizationToken(48, 792, 193)
+	assert.NoError(t, err)
+	req := NewRequestWithBody(t, "POST", "/twirp/github.actions.results.api.v1.ArtifactService/CreateArtifact", toProtoJSON(&actions.CreateArtifactRequest{
+		Version:                 4,
+		Name:                    "artifact-v4-download",
+		WorkflowRunBackendId:    "792",
+		WorkflowJobRunBackendId: "193",
+	})).AddTokenAuth(runnerToken)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var uploadResp actions.CreateArtifactResponse
+	protojson.Unmarshal(resp.Body.Bytes(), &uploadResp)
+	assert.True(t, uploadResp.Ok)
+
+	// while the second attempt is still uploading the artifact, both attempts use the one uploaded by the first attempt
+	listAttemptArtifacts := func(attempt int64) *api.ActionArtifactsResponse {
+		req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/actions/runs/792/attempts/%d/artifacts?name=artifact-v4-download", repo.FullName(), attempt)).
+			AddTokenAuth(token)
+		var listResp api.ActionArtifactsResponse
+		DecodeJSON(t, MakeRequest(t, req, http.StatusOK), &listResp)
+		return &listResp
+	}
+	for _, attempt := range []int64{1, 2} {
+		listResp := listAttemptArtifacts(attempt)
+		if assert.Len(t, listResp.Entries, 1) {
+			assert.EqualValues(t, 22, listResp.Entries[0].ID)
+			assert.EqualValues(t, 1, listResp.Entries[0].WorkflowRun.RunAttempt)
+		}
+	}
+
+	idx := strings.Index(uploadResp.SignedUploadUrl, "/twirp/")
+	body := strings.Repeat("B", 2048)
+	req = NewRequestWithBody(t, "PUT", uploadResp.SignedUploadUrl[idx:]+"&comp=block", strings.NewReader(body))
+	MakeRequest(t, req, http.StatusCreated)
+	sha := sha256.Sum256([]byte(body))
+	req = NewRequestWithBody(t, "POST", "/twirp/github.actions.results.api.v1.ArtifactService/FinalizeArtifact", toProtoJSON(&actions.FinalizeArtifactRequest{
+		Name:                    "artifact-v4-download",
+		Size:                    2048,
+		Hash:                    wrapperspb.String("sha256:" + hex.EncodeToString(sha[:])),
+		WorkflowRunBackendId:    "792",
+		WorkflowJobRunBackendId: "193",
+	})).AddTokenAuth(runnerToken)
+	MakeRequest(t, req, http.StatusOK)
+
+	listResp := listAttemptArtifacts(1)
+	if assert.Len(t, listResp.Entries, 1) {
+		assert.EqualValues(t, 22, listResp.Entries[0].ID)
+		assert.EqualValues(t, 1024, listResp.Entries[0].SizeInBytes)
+	}
+	listResp = listAttemptArtifacts(2)
+	if assert.Len(t, listResp.Entries, 1) {
+		assert.NotEqualValues(t, 22, listResp.Entries[0].ID)
+		assert.EqualValues(t, 2048, listResp.Entries[0].SizeInBytes)
+		assert.EqualValues(t, 2, listResp.Entries[0].WorkflowRun.RunAttempt)
+	}
+
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/actions/runs/792/attempts/3/artifacts", repo.FullName())).
+		AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNotFound)
+}
+
 func TestActionsArtifactV4GetArtifactMismatchedRepoNotFound(t *testing.T) {
 	defer prepareTestEnvActionsArtifacts(t)()
 
@@ -0,0 +1,199 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/routers/api/actions"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func prepareTestEnvActionsCaches(t *testing.T) func() {
+	t.Helper()
+	f := tests.PrepareTestEnv(t, 1)
+	assert.NoError(t, storage.Clean(storage.ActionsCaches))
+	return f
+}
+
+type findCacheResponse struct {
+	Result          string `json:"result"`
+	ArchiveLocation string `json:"archiveLocation"`
+	CacheKey        string `json:"cacheKey"`
+	Scope           string `json:"scope"`
+}
+
+func TestActionsCache(t *testing.T) {
+	defer prepareTestEnvActionsCaches(t)()
+
+	token, err := actions_service.CreateAuthorizationToken(48, 792, 193)
+	require.NoError(t, err)
+
+	// not found
+	req := NewRequest(t, "GET", "/api/actions_cache/_apis/artifactcache/cache?keys=linux-deps-abc,linux-deps&version=v1").AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+
+	// reserve a cache
+	req = NewRequestWithJSON(t, "POST", "/api/actions_cache/_apis/artifactcache/caches", map[string]any{
+		"key":       "linux-deps-abc",
+		"version":   "v1",
+		"cacheSize": 2048,
+	}).AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusCreated)
+	var reserveResp struct {
+		CacheID int64 `json:"cacheId"`
+	}
+	DecodeJSON(t, resp, &reserveResp)
+	require.Positive(t, reserveResp.CacheID)
+	cacheURL := fmt.Sprintf("/api/actions_cache/_apis/artifactcache/caches/%d", reserveResp.CacheID)
+
+	// the content length is required
+	req = NewRequestWithBody(t, "PATCH", cacheURL, strings.NewReader(strings.Repeat("A", 1024))).AddTokenAuth(token)
+	req.Header.Set("Content-Range", "bytes 0-1023/*")
+	req.ContentLength = -1
+	MakeRequest(t, req, http.StatusLengthRequired)
+
+	// upload chunks in reverse order
+	req = NewRequestWithBody(t, "PATCH", cacheURL, strings.NewReader(strings.Repeat("B", 1024))).AddTokenAuth(token)
+	req.Header.Set("Content-Range", "bytes 1024-2047/*")
+	MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequestWithBody(t, "PATCH", cacheURL, strings.NewReader(strings.Repeat("A", 1024))).AddTokenAuth(token)
+	req.Header.Set("Content-Range", "bytes 0-1023/*")
+	MakeRequest(t, req, http.StatusNoContent)
+
+	// the size doesn't match
+	req = NewRequestWithJSON(t, "POST", cacheURL, map[string]any{"size": 4096}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusBadRequest)
+
+	req = NewRequestWithJSON(t, "POST", cacheURL, map[string]any{"size": 2048}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+	cache := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionCache{ID: reserveResp.CacheID})
+	assert.True(t, cache.IsComplete)
+	assert.EqualValues(t, 2048, cache.Size)
+
+	// the cache with the same key and version can't be created again
+	req = NewRequestWithJSON(t, "POST", "/api/actions_cache/_apis/artifactcache/caches", map[string]any{
+		"key":     "linux-deps-abc",
+		"version": "v1",
+	}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusConflict)
+
+	// restore by the restore key
+	req = NewRequest(t, "GET", "/api/actions_cache/_apis/artifactcache/cache?keys=linux-deps-def,linux-deps&version=v1").AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var findResp findCacheResponse
+	DecodeJSON(t, resp, &findResp)
+	assert.Equal(t, "hit", findResp.Result)
+	assert.Equal(t, "linux-deps-abc", findResp.CacheKey)
+
+	// the version doesn't match
+	req = NewRequest(t, "GET", "/api/actions_cache/_apis/artifactcache/cache?keys=linux-deps-abc&version=v2").AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+
+	// download without the token
+	idx := strings.Index(findResp.ArchiveLocation, "/api/actions_cache/")
+	req = NewRequest(t, "GET", findResp.ArchiveLocation[idx:])
+	resp = MakeRequest(t, req, http.StatusOK)
+	assert.Equal(t, strings.Repeat("A", 1024)+strings.Repeat("B", 1024), resp.Body.String())
+
+	// the signature is checked
+	req = NewRequest(t, "GET", strings.Replace(findResp.ArchiveLocation[idx:], "sig=", "sig=invalid", 1))
+	MakeRequest(t, req, http.StatusUnauthorized)
+}
+
+func TestActionsCacheV4(t *testing.T) {
+	defer prepareTestEnvActionsCaches(t)()
+
+	token, err := actions_service.CreateAuthorizationToken(48, 792, 193)
+	require.NoError(t, err)
+
+	// create a cache entry
+	req := NewRequestWithBody(t, "POST", "/twirp/github.actions.results.api.v1.CacheService/CreateCacheEntry", toProtoJSON(&actions.CreateCacheEntryRequest{
+		Key:     "npm-abc",
+		Version: "v1",
+	})).AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var createResp actions.CreateCacheEntryResponse
+	require.NoError(t, protojson.Unmarshal(resp.Body.Bytes(), &createResp))
+	assert.True(t, createResp.Ok)
+	assert.Contains(t, createResp.SignedUploadUrl, "/twirp/github.actions.results.api.v1.CacheService/UploadCache")
+
+	// upload blocks and the block list
+	idx := strings.Index(createResp.SignedUploadUrl, "/twirp/")
+	uploadURL := createResp.SignedUploadUrl[idx:]
+	req = NewRequestWithBody(t, "PUT", uploadURL+"&comp=block&blockid=block2", strings.NewReader(strings.Repeat("D", 512)))
+	req.ContentLength = -1
+	MakeRequest(t, req, http.StatusLengthRequired)
+	req = NewRequestWithBody(t, "PUT", uploadURL+"&comp=block&blockid=block2", strings.NewReader(strings.Repeat("D", 512)))
+	MakeRequest(t, req, http.StatusCreated)
+	req = NewRequestWithBody(t, "PUT", uploadURL+"&comp=block&blockid=block1", strings.NewReader(strings.Repeat("C", 512)))
+	MakeRequest(t, req, http.StatusCreated)
+	blockList := `<?xml version="1.0" encoding="utf-8"?><BlockList><Latest>block1</Latest><Latest>block2</Latest></BlockList>`
+	req = NewRequestWithBody(t, "PUT", uploadURL+"&comp=blocklist", strings.NewReader(blockList))
+	MakeRequest(t, req, http.StatusCreated)
+
+	// finalize the cache entry
+	req = NewRequestWithBody(t, "POST", "/twirp/github.actions.results.api.v1.CacheService/FinalizeCacheEntryUpload", toProtoJSON(&actions.FinalizeCacheEntryUploadRequest{
+		Key:       "npm-abc",
+		Version:   "v1",
+		SizeBytes: 1024,
+	})).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var finalizeResp actions.FinalizeCacheEntryUploadResponse
+	require.NoError(t, protojson.Unmarshal(resp.Body.Bytes(), &finalizeResp))
+	assert.True(t, finalizeResp.Ok)
+	assert.Positive(t, finalizeResp.EntryId)
+
+	// the cache with the same key and version can't be created again
+	req = NewRequestWithBody(t, "POST", "/twirp/github.actions.results.api.v1.CacheService/CreateCacheEntry", toProtoJSON(&actions.CreateCacheEntryRequest{
+		Key:     "npm-abc",
+		Version: "v1",
+	})).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	require.NoError(t, protojson.Unmarshal(resp.Body.Bytes(), &createResp))
+	assert.False(t, createResp.Ok)
+
+	// get the download url by the restore key
+	req = NewRequestWithBody(t, "POST", "/twirp/github.actions.results.api.v1.CacheService/GetCacheEntryDownloadURL", toProtoJSON(&actions.GetCacheEntryDownloadURLRequest{
+		Key:         "npm-def",
+		RestoreKeys: []string{"npm-"},
+		Version:     "v1",
+	})).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var downloadResp actions.GetCacheEntryDownloadURLResponse
+	require.NoError(t, protojson.Unmarshal(resp.Body.Bytes(), &downloadResp))
+	assert.True(t, downloadResp.Ok)
+	assert.Equal(t, "npm-abc", downloadResp.MatchedKey)
+
+	idx = strings.Index(downloadResp.SignedDownloadUrl, "/twirp/")
+	req = NewRequest(t, "GET", downloadResp.SignedDownloadUrl[idx:])
+	resp = MakeRequest(t, req, http.StatusOK)
+	assert.Equal(t, strings.Repeat("C", 512)+strings.Repeat("D", 512), resp.Body.String())
+
+	// range requests are supported
+	req = NewRequest(t, "GET", downloadResp.SignedDownloadUrl[idx:])
+	req.Header.Set("Range", "bytes=510-513")
+	resp = MakeRequest(t, req, http.StatusPartialContent)
+	assert.Equal(t, "CCDD", resp.Body.String())
+
+	// not found
+	req = NewRequestWithBody(t, "POST", "/twirp/github.actions.results.api.v1.CacheService/GetCacheEntryDownloadURL", toProtoJSON(&actions.GetCacheEntryDownloadURLRequest{
+		Key:     "pip-abc",
+		Version: "v1",
+	})).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	require.NoError(t, protojson.Unmarshal(resp.Body.Bytes(), &downloadResp))
+	assert.False(t, downloadResp.Ok)
+}
@@ -0,0 +1,134 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIActionsRunOperations(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		session := loginUser(t, user2.Name)
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository, auth_model.AccessTokenScopeWriteUser)
+		readToken := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeReadRepository)
+
+		apiRepo := createActionsTestRepo(t, token, "api-actions-run-operations", false)
+		runner := newMockRunner()
+		runner.registerAsRepoRunner(t, user2.Name, apiRepo.Name, "mock-runner", []string{"ubuntu-latest"}, false)
+
+		wfTreePath := ".gitea/workflows/run-operations.yml"
+		wfFileContent := `name: run-operations
+on: push
+jobs:
+  job1:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo job1
+  job2:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo job2
+`
+		opts := getWorkflowCreateFileOptions(user2, apiRepo.DefaultBranch, "create "+wfTreePath, wfFileContent)
+		createWorkflowFile(t, token, user2.Name, apiRepo.Name, wfTreePath, opts)
+
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: apiRepo.ID, WorkflowID: "run-operations.yml"})
+		runURL := fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs/%d", user2.Name, apiRepo.Name, run.ID)
+		job1 := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{RunID: run.ID, JobID: "job1"})
+
+		runOperation := func(t *testing.T, path string, body any, expectedStatus int) *api.ActionWorkflowRun {
+			t.Helper()
+			req := NewRequestWithJSON(t, "POST", runURL+path, body).AddTokenAuth(token)
+			resp := MakeRequest(t, req, expectedStatus)
+			if expectedStatus != http.StatusOK {
+				return nil
+			}
+			apiRun := &api.ActionWorkflowRun{}
+			DecodeJSON(t, resp, apiRun)
+			return apiRun
+		}
+		execTasks := func(t *testing.T, count int) {
+			t.Helper()
+			for range count {
+				task := runner.fetchTask(t)
+				runner.execTask(t, task, &mockTaskOutcome{result: runnerv1.Result_RESULT_SUCCESS})
+			}
+		}
+
+		t.Run("ReadOnlyToken", func(t *testing.T) {
+			req := NewRequest(t, "POST", runURL+"/cancel").AddTokenAuth(readToken)
+			MakeRequest(t, req, http.StatusForbidden)
+		})
+
+		t.Run("Cancel", func(t *testing.T) {
+			// one job is running and the other one is waiting
+			runner.fetchTask(t)
+			apiRun := runOperation(t, "/cancel", nil, http.StatusOK)
+			assert.Equal(t, "completed", apiRun.Status)
+			assert.Equal(t, "cancelled", apiRun.Conclusion)
+			unittest.AssertCount(t, &actions_model.ActionRunJob{RunID: run.ID, Status: actions_model.StatusCancelled}, 2)
+
+			// the run is already done
+			runOperation(t, "/cancel", nil, http.StatusBadRequest)
+		})
+
+		t.Run("RerunFailedJobs", func(t *testing.T) {
+			apiRun := runOperation(t, "/rerun-failed-jobs", &api.RerunActionRunOption{EnableDebugLogging: true}, http.StatusOK)
+			assert.EqualValues(t, 2, apiRun.RunAttempt)
+			assert.Equal(t, "queued", apiRun.Status)
+			assert.True(t, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: run.ID}).IsDebugEnabled)
+
+			// the run is not done
+			runOperation(t, "/rerun", &api.RerunActionRunOption{}, http.StatusBadRequest)
+
+			execTasks(t, 2)
+
+			// there are no failed jobs
+			runOperation(t, "/rerun-failed-jobs", &api.RerunActionRunOption{}, http.StatusBadRequest)
+		})
+
+		t.Run("RerunJob", func(t *testing.T) {
+			runOperation(t, "/jobs/0/rerun", &api.RerunActionRunOption{}, http.StatusNotFound)
+
+			apiRun := runOperation(t, fmt.Sprintf("/jobs/%d/rerun", job1.ID), &api.RerunActionRunOption{}, http.StatusOK)
+			assert.EqualValues(t, 3, apiRun.RunAttempt)
+			assert.False(t, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: run.ID}).IsDebugEnabled)
+			job2 := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{RunID: run.ID, JobID: "job2"})
+			assert.Equal(t, actions_model.StatusSuccess, job2.Status)
+
+			execTasks(t, 1)
+		})
+
+		t.Run("Rerun", func(t *testing.T) {
+			apiRun := runOperation(t, "/rerun", &api.RerunActionRunOption{}, http.StatusOK)
+			assert.EqualValues(t, 4, apiRun.RunAttempt)
+
+			execTasks(t, 2)
+			assert.Equal(t, actions_model.StatusSuccess, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: run.ID}).Status)
+		})
+
+		t.Run("Approve", func(t *testing.T) {
+			// the run doesn't need approval
+			runOperation(t, "/approve", nil, http.StatusBadRequest)
+		})
+
+		t.Run("NotFound", func(t *testing.T) {
+			req := NewRequest(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs/%d/cancel", user2.Name, apiRepo.Name, run.ID+1000)).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusNotFound)
+		})
+	})
+}
@@ -0,0 +1,186 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIActionsRunnerGroups(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		session := loginUser(t, user2.Name)
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteOrganization, auth_model.AccessTokenScopeWriteRepository)
+
+		createOrgRepo := func(t *testing.T, name string) *api.Repository {
+			req := NewRequestWithJSON(t, "POST", "/api/v1/orgs/org3/repos", &api.CreateRepoOption{
+				Name:          name,
+				AutoInit:      true,
+				Readme:        "Default",
+				DefaultBranch: "main",
+			}).AddTokenAuth(token)
+			apiRepo := &api.Repository{}
+			DecodeJSON(t, MakeRequest(t, req, http.StatusCreated), apiRepo)
+			return apiRepo
+		}
+		allowedRepo := createOrgRepo(t, "runner-group-allowed")
+		deniedRepo := createOrgRepo(t, "runner-group-denied")
+
+		// register an org-level runner
+		req := NewRequest(t, "GET", "/api/v1/orgs/org3/actions/runners/registration-token").AddTokenAuth(token)
+		var registrationToken struct {
+			Token string `json:"token"`
+		}
+		DecodeJSON(t, MakeRequest(t, req, http.StatusOK), &registrationToken)
+		runner := newMockRunner()
+		runner.doRegister(t, "group-runner", registrationToken.Token, []string{"group-runner"}, false)
+		orgRunner := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunner{Name: "group-runner", OwnerID: 3})
+
+		groupsURL := "/api/v1/orgs/org3/actions/runner-groups"
+		var group api.ActionRunnerGroup
+		t.Run("Create", func(t *testing.T) {
+			req := NewRequestWithJSON(t, "POST", groupsURL, &api.CreateActionRunnerGroupOption{
+				Name:                  "production",
+				Visibility:            "selected",
+				SelectedRepositoryIDs: []int64{allowedRepo.ID},
+				WorkflowPatterns:      []string{"deploy*.yml"},
+				RefPatterns:           []string{"refs/heads/main"},
+			}).AddTokenAuth(token)
+			DecodeJSON(t, MakeRequest(t, req, http.StatusCreated), &group)
+			assert.Equal(t, "production", group.Name)
+			assert.Equal(t, "selected", group.Visibility)
+			assert.Equal(t, []int64{allowedRepo.ID}, group.SelectedRepositoryIDs)
+
+			// the name is case-insensitive
+			req = NewRequestWithJSON(t, "POST", groupsURL, &api.CreateActionRunnerGroupOption{Name: "Production"}).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusConflict)
+
+			// the repositories of other owners can't be selected
+			req = NewRequestWithJSON(t, "POST", groupsURL, &api.CreateActionRunnerGroupOption{
+				Name:                  "other",
+				Visibility:            "selected",
+				SelectedRepositoryIDs: []int64{1},
+			}).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusBadRequest)
+
+			req = NewRequest(t, "GET", groupsURL).AddTokenAuth(token)
+			var groups []*api.ActionRunnerGroup
+			DecodeJSON(t, MakeRequest(t, req, http.StatusOK), &groups)
+			require.Len(t, groups, 1)
+			assert.Equal(t, group.ID, groups[0].ID)
+		})
+
+		groupURL := fmt.Sprintf("%s/%d", groupsURL, group.ID)
+		t.Run("AddRunner", func(t *testing.T) {
+			req := NewRequest(t, "PUT", fmt.Sprintf("%s/runners/%d", groupURL, orgRunner.ID)).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusNoContent)
+
+			req = NewRequest(t, "GET", groupURL+"/runners").AddTokenAuth(token)
+			var runners api.ActionRunnersResponse
+			DecodeJSON(t, MakeRequest(t, req, http.StatusOK), &runners)
+			require.Len(t, runners.Entries, 1)
+			assert.Equal(t, orgRunner.ID, runners.Entries[0].ID)
+			assert.Equal(t, group.ID, runners.Entries[0].RunnerGroupID)
+
+			// the instance-level groups can't have org-level runners
+			adminToken := getUserToken(t, "user1", auth_model.AccessTokenScopeWriteAdmin)
+			req = NewRequestWithJSON(t, "POST", "/api/v1/admin/actions/runner-groups", &api.CreateActionRunnerGroupOption{Name: "instance"}).AddTokenAuth(adminToken)
+			var instanceGroup api.ActionRunnerGroup
+			DecodeJSON(t, MakeRequest(t, req, http.StatusCreated), &instanceGroup)
+			req = NewRequest(t, "PUT", fmt.Sprintf("/api/v1/admin/actions/runner-groups/%d/runners/%d", instanceGroup.ID, orgRunner.ID)).AddTokenAuth(adminToken)
+			MakeRequest(t, req, http.StatusBadRequest)
+			// the groups of other owners are not found
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/%d", groupsURL, instanceGroup.ID)).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusNotFound)
+		})
+
+		t.Run("PickTasks", func(t *testing.T) {
+			createWorkflow := func(t *testing.T, repo *api.Repository, fileName string) *actions_model.ActionRunJob {
+				treePath := ".gitea/workflows/" + fileName
+				// the workflow is only triggered by the commit creating it
+				content := `name: runner-group
+on:
+  push:
+    paths:
+      - ` + treePath + `
+jobs:
+  job:
+    runs-on: group-runner
+    steps:
+      - run: echo ok
+`
+				opts := getWorkflowCreateFileOptions(user2, repo.DefaultBranch, "create "+treePath, content)
+				createWorkflowFile(t, token, "org3", repo.Name, treePath, opts)
+				run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID, WorkflowID: fileName})
+				return unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{RunID: run.ID})
+			}
+			fetchNoTask := func(t *testing.T) {
+				resp, err := runner.client.runnerServiceClient.FetchTask(t.Context(), connect.NewRequest(&runnerv1.FetchTaskRequest{}))
+				require.NoError(t, err)
+				assert.Nil(t, resp.Msg.Task)
+			}
+
+			// the workflow and the repository are not allowed by the group
+			buildJob := createWorkflow(t, allowedRepo, "build.yml")
+			deniedJob := createWorkflow(t, deniedRepo, "deploy.yml")
+			fetchNoTask(t)
+
+			deployJob := createWorkflow(t, allowedRepo, "deploy.yml")
+			task := runner.fetchTask(t)
+			runner.execTask(t, task, &mockTaskOutcome{result: runnerv1.Result_RESULT_SUCCESS})
+			assert.Equal(t, deployJob.ID, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: task.Id}).JobID)
+			fetchNoTask(t)
+
+			// the runner can run all jobs of the org after it's removed from the group
+			req := NewRequest(t, "DELETE", fmt.Sprintf("%s/runners/%d", groupURL, orgRunner.ID)).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusNoContent)
+			var jobIDs []int64
+			for range 2 {
+				task := runner.fetchTask(t)
+				runner.execTask(t, task, &mockTaskOutcome{result: runnerv1.Result_RESULT_SUCCESS})
+				jobIDs = append(jobIDs, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: task.Id}).JobID)
+			}
+			assert.ElementsMatch(t, []int64{buildJob.ID, deniedJob.ID}, jobIDs)
+		})
+
+		t.Run("EditAndDelete", func(t *testing.T) {
+			name := "deploy"
+			visibility := "all"
+			req := NewRequestWithJSON(t, "PATCH", groupURL, &api.EditActionRunnerGroupOption{
+				Name:       &name,
+				Visibility: &visibility,
+			}).AddTokenAuth(token)
+			var edited api.ActionRunnerGroup
+			DecodeJSON(t, MakeRequest(t, req, http.StatusOK), &edited)
+			assert.Equal(t, "deploy", edited.Name)
+			assert.Equal(t, "all", edited.Visibility)
+			// the fields which are not set are not changed
+			assert.Equal(t, []string{"deploy*.yml"}, edited.WorkflowPatterns)
+
+			req = NewRequestWithJSON(t, "PATCH", groupURL, &api.EditActionRunnerGroupOption{RefPatterns: []string{"refs/heads/[main"}}).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusBadRequest)
+
+			req = NewRequest(t, "PUT", fmt.Sprintf("%s/runners/%d", groupURL, orgRunner.ID)).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusNoContent)
+			req = NewRequest(t, "DELETE", groupURL).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusNoContent)
+			unittest.AssertNotExistsBean(t, &actions_model.ActionRunnerGroup{ID: group.ID})
+			assert.Zero(t, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunner{ID: orgRunner.ID}).GroupID)
+		})
+	})
+}
@@ -8,8 +8,10 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"strconv"
 	"strings"
 	"sync"
@@ -25,6 +27,7 @@ import (
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/test"
 	package_service "code.gitea.io/gitea/services/packages"
+	packages_cleanup_service "code.gitea.io/gitea/services/packages/cleanup"
 	"code.gitea.io/gitea/tests"
 
 	oci "github.com/opencontainers/image-spec/specs-go/v1"
@@ -833,3 +836,230 @@ func TestPackageContainer(t *testing.T) {
 		session.MakeRequest(t, req, http.StatusSeeOther)
 	})
 }
+
+func TestPackageContainerReferrers(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	url := fmt.Sprintf("%sv2/%s/referrers-test", setting.AppURL, user.Name)
+
+	uploadBlob := func(t *testing.T, content string) string {
+		blobDigest := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(content)))
+		req := NewRequestWithBody(t, "POST", fmt.Sprintf("%s/blobs/uploads?digest=%s", url, blobDigest), strings.NewReader(content)).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusCreated)
+		return blobDigest
+	}
+	uploadManifest := func(t *testing.T, reference, content string) *httptest.ResponseRecorder {
+		req := NewRequestWithBody(t, "PUT", fmt.Sprintf("%s/manifests/%s", url, reference), strings.NewReader(content)).
+			AddBasicAuth(user.Name).
+			SetHeader("Content-Type", oci.MediaTypeImageManifest)
+		return MakeRequest(t, req, http.StatusCreated)
+	}
+	getReferrers := func(t *testing.T, subject, query string) (*oci.Index, *httptest.ResponseRecorder) {
+		req := NewRequest(t, "GET", fmt.Sprintf("%s/referrers/%s%s", url, subject, query)).
+			AddBasicAuth(user.Name)
+		resp := MakeRequest(t, req, http.StatusOK)
+		assert.Equal(t, oci.MediaTypeImageIndex, resp.Header().Get("Content-Type"))
+		var index oci.Index
+		DecodeJSON(t, resp, &index)
+		return &index, resp
+	}
+
+	configContent := `{"architecture":"amd64","os":"linux"}`
+	configDigest := uploadBlob(t, configContent)
+	emptyDigest := uploadBlob(t, "{}")
+
+	subjectContent := fmt.Sprintf(`{"schemaVersion":2,"mediaType":"%s","config":{"mediaType":"%s","digest":"%s","size":%d},"layers":[]}`, oci.MediaTypeImageManifest, oci.MediaTypeImageConfig, configDigest, len(configContent))
+	subjectDigest := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(subjectContent)))
+	resp := uploadManifest(t, "v1", subjectContent)
+	assert.Empty(t, resp.Header().Get("OCI-Subject"))
+
+	referrerContent := func(artifactType string) string {
+		return fmt.Sprintf(`{"schemaVersion":2,"mediaType":"%s","artifactType":"%s","config":{"mediaType":"%s","digest":"%s","size":2},"layers":[],"subject":{"mediaType":"%s","digest":"%s","size":%d},"annotations":{"type":"%s"}}`, oci.MediaTypeImageManifest, artifactType, oci.MediaTypeEmptyJSON, emptyDigest, oci.MediaTypeImageManifest, subjectDigest, len(subjectContent), artifactType)
+	}
+	signatureContent := referrerContent("application/vnd.dev.cosign.artifact.sig.v1+json")
+	signatureDigest := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(signatureContent)))
+	sbomContent := referrerContent("application/spdx+json")
+	sbomDigest := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(sbomContent)))
+
+	t.Run("UploadReferrer", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		resp := uploadManifest(t, signatureDigest, signatureContent)
+		assert.Equal(t, subjectDigest, resp.Header().Get("OCI-Subject"))
+		resp = uploadManifest(t, sbomDigest, sbomContent)
+		assert.Equal(t, subjectDigest, resp.Header().Get("OCI-Subject"))
+
+		pv, err := packages_model.GetVersionByNameAndVersion(db.DefaultContext, user.ID, packages_model.TypeContainer, "referrers-test", signatureDigest)
+		assert.NoError(t, err)
+		pd, err := packages_model.GetPackageDescriptor(db.DefaultContext, pv)
+		assert.NoError(t, err)
+		assert.Equal(t, subjectDigest, pd.VersionProperties.GetByName(container_module.PropertyManifestSubject))
+		metadata := pd.Metadata.(*container_module.Metadata)
+		assert.Equal(t, subjectDigest, metadata.Subject)
+		assert.Equal(t, "application/vnd.dev.cosign.artifact.sig.v1+json", metadata.ArtifactType)
+	})
+
+	t.Run("GetReferrers", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", url+"/referrers/invalid").
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusBadRequest)
+
+		index, _ := getReferrers(t, "sha256:0000000000000000000000000000000000000000000000000000000000000000", "")
+		assert.Equal(t, 2, index.SchemaVersion)
+		assert.Empty(t, index.Manifests)
+
+		index, resp := getReferrers(t, subjectDigest, "")
+		assert.Empty(t, resp.Header().Get("OCI-Filters-Applied"))
+		assert.Len(t, index.Manifests, 2)
+		for _, m := range index.Manifests {
+			assert.Equal(t, oci.MediaTypeImageManifest, m.MediaType)
+			assert.Equal(t, map[string]string{"type": m.ArtifactType}, m.Annotations)
+			switch m.ArtifactType {
+			case "application/vnd.dev.cosign.artifact.sig.v1+json":
+				assert.EqualValues(t, signatureDigest, m.Digest)
+				assert.EqualValues(t, len(signatureContent), m.Size)
+			case "application/spdx+json":
+				assert.EqualValues(t, sbomDigest, m.Digest)
+			default:
+				assert.FailNow(t, "unknown referrer", m.ArtifactType)
+			}
+		}
+
+		index, resp = getReferrers(t, subjectDigest, "?artifactType=application/spdx%2Bjson")
+		assert.Equal(t, "artifactType", resp.Header().Get("OCI-Filters-Applied"))
+		if assert.Len(t, index.Manifests, 1) {
+			assert.EqualValues(t, sbomDigest, index.Manifests[0].Digest)
+		}
+	})
+
+	t.Run("Cleanup", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		pcr, err := packages_model.InsertCleanupRule(db.DefaultContext, &packages_model.PackageCleanupRule{
+			Enabled:     true,
+			OwnerID:     user.ID,
+			Type:        packages_model.TypeContainer,
+			KeepPattern: `v1`,
+		})
+		assert.NoError(t, err)
+		defer func() {
+			assert.NoError(t, packages_model.DeleteCleanupRuleByID(db.DefaultContext, pcr.ID))
+		}()
+
+		// the referrers are kept while the subject exists
+		assert.NoError(t, packages_cleanup_service.CleanupTask(db.DefaultContext, 0))
+		index, _ := getReferrers(t, subjectDigest, "")
+		assert.Len(t, index.Manifests, 2)
+
+		req := NewRequest(t, "DELETE", url+"/manifests/v1").
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusAccepted)
+
+		assert.NoError(t, packages_cleanup_service.CleanupTask(db.DefaultContext, 0))
+		index, _ = getReferrers(t, subjectDigest, "")
+		assert.Empty(t, index.Manifests)
+	})
+}
+
+func TestPackageContainerIndexCleanup(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	image := "index-cleanup-test"
+	url := fmt.Sprintf("%sv2/%s/%s", setting.AppURL, user.Name, image)
+
+	uploadBlob := func(t *testing.T, content string) string {
+		blobDigest := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(content)))
+		req := NewRequestWithBody(t, "POST", fmt.Sprintf("%s/blobs/uploads?digest=%s", url, blobDigest), strings.NewReader(content)).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusCreated)
+		return blobDigest
+	}
+	uploadManifest := func(t *testing.T, reference, mediaType, content string) string {
+		req := NewRequestWithBody(t, "PUT", fmt.Sprintf("%s/manifests/%s", url, reference), strings.NewReader(content)).
+			AddBasicAuth(user.Name).
+			SetHeader("Content-Type", mediaType)
+		MakeRequest(t, req, http.StatusCreated)
+		return fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(content)))
+	}
+	imageManifest := func(t *testing.T, arch string) string {
+		configContent := fmt.Sprintf(`{"architecture":"%s","os":"linux"}`, arch)
+		configDigest := uploadBlob(t, configContent)
+		return fmt.Sprintf(`{"schemaVersion":2,"mediaType":"%s","config":{"mediaType":"%s","digest":"%s","size":%d},"layers":[]}`, oci.MediaTypeImageManifest, oci.MediaTypeImageConfig, configDigest, len(configContent))
+	}
+	versionExists := func(t *testing.T, version string) bool {
+		_, err := packages_model.GetVersionByNameAndVersion(db.DefaultContext, user.ID, packages_model.TypeContainer, image, version)
+		if errors.Is(err, packages_model.ErrPackageNotExist) {
+			return false
+		}
+		assert.NoError(t, err)
+		return true
+	}
+
+	amd64Content := imageManifest(t, "amd64")
+	amd64Digest := uploadManifest(t, "amd64", oci.MediaTypeImageManifest, amd64Content)
+	arm64Content := imageManifest(t, "arm64")
+	arm64Digest := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(arm64Content)))
+	uploadManifest(t, arm64Digest, oci.MediaTypeImageManifest, arm64Content)
+
+	indexContent := fmt.Sprintf(`{"schemaVersion":2,"mediaType":"%s","manifests":[{"mediaType":"%s","digest":"%s","size":%d,"platform":{"os":"linux","architecture":"amd64"}},{"mediaType":"%s","digest":"%s","size":%d,"platform":{"os":"linux","architecture":"arm64"}}]}`,
+		oci.MediaTypeImageIndex, oci.MediaTypeImageManifest, amd64Digest, len(amd64Content), oci.MediaTypeImageManifest, arm64Digest, len(arm64Content))
+	uploadManifest(t, "v1", oci.MediaTypeImageIndex, indexContent)
+
+	pcr, err := packages_model.InsertCleanupRule(db.DefaultContext, &packages_model.PackageCleanupRule{
+		Enabled:       true,
+		OwnerID:       user.ID,
+		Type:          packages_model.TypeContainer,
+		KeepPattern:   `v1`,
+		RemovePattern: `v\d+|amd64`,
+	})
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, packages_model.DeleteCleanupRuleByID(db.DefaultContext, pcr.ID))
+	}()
+
+	t.Run("KeepReferencedManifests", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		// the tagged child manifest matches the remove pattern but is kept as long as the index exists
+		assert.NoError(t, packages_cleanup_service.CleanupTask(db.DefaultContext, 0))
+		assert.True(t, versionExists(t, "v1"))
+		assert.True(t, versionExists(t, "amd64"))
+		assert.True(t, versionExists(t, arm64Digest))
+	})
+
+	t.Run("RemoveOrphanedManifests", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		pcr.KeepPattern = ""
+		assert.NoError(t, packages_model.UpdateCleanupRule(db.DefaultContext, pcr))
+
+		pds, err := packages_cleanup_service.PreviewCleanupRule(db.DefaultContext, pcr)
+		assert.NoError(t, err)
+		versions := make([]string, 0, len(pds))
+		for _, pd := range pds {
+			versions = append(versions, pd.Version.Version)
+		}
+		assert.ElementsMatch(t, []string{"v1", "amd64", arm64Digest}, versions)
+
+		// the untagged child manifest doesn't match the remove pattern but is not reachable without the index
+		assert.NoError(t, packages_cleanup_service.CleanupTask(db.DefaultContext, 0))
+		assert.False(t, versionExists(t, "v1"))
+		assert.False(t, versionExists(t, "amd64"))
+		assert.False(t, versionExists(t, arm64Digest))
+
+		runs, _, err := packages_model.GetCleanupRunsByRuleID(db.DefaultContext, pcr.ID, nil)
+		assert.NoError(t, err)
+		if assert.Len(t, runs, 1) {
+			versions, err := packages_model.GetCleanupRunVersionsByRunIDs(db.DefaultContext, []int64{runs[0].ID})
+			assert.NoError(t, err)
+			assert.Len(t, versions[runs[0].ID], 3)
+		}
+	})
+}
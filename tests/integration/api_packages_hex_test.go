@@ -0,0 +1,363 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	hex_module "code.gitea.io/gitea/modules/packages/hex"
+	hex_service "code.gitea.io/gitea/services/packages/hex"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestPackageHex(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	packageName := "gitea_test"
+	packageVersion := "1.0.1"
+	packageDescription := "Package Description"
+
+	createPackage := func(version string) []byte {
+		var contents bytes.Buffer
+		zw := gzip.NewWriter(&contents)
+		tw := tar.NewWriter(zw)
+		readme := "# Gitea"
+		_ = tw.WriteHeader(&tar.Header{Name: "README.md", Mode: 0o600, Size: int64(len(readme))})
+		_, _ = tw.Write([]byte(readme))
+		_ = tw.Close()
+		_ = zw.Close()
+
+		metadata := fmt.Sprintf(`{<<"name">>,<<"%s">>}.
+{<<"version">>,<<"%s">>}.
+{<<"app">>,<<"%s">>}.
+{<<"description">>,<<"%s">>}.
+{<<"licenses">>,[<<"MIT">>]}.
+{<<"requirements">>,[{<<"jason">>,[{<<"app">>,<<"jason">>},{<<"optional">>,false},{<<"requirement">>,<<"~> 1.4">>}]}]}.
+`, packageName, version, packageName, packageDescription)
+
+		h := sha256.New()
+		h.Write([]byte("3"))
+		h.Write([]byte(metadata))
+		h.Write(contents.Bytes())
+
+		var buf bytes.Buffer
+		tw = tar.NewWriter(&buf)
+		for _, f := range []struct {
+			Name    string
+			Content []byte
+		}{
+			{"VERSION", []byte("3")},
+			{"CHECKSUM", []byte(strings.ToUpper(hex.EncodeToString(h.Sum(nil))))},
+			{"metadata.config", []byte(metadata)},
+			{"contents.tar.gz", contents.Bytes()},
+		} {
+			_ = tw.WriteHeader(&tar.Header{Name: f.Name, Mode: 0o600, Size: int64(len(f.Content))})
+			_, _ = tw.Write(f.Content)
+		}
+		_ = tw.Close()
+		return buf.Bytes()
+	}
+
+	content := createPackage(packageVersion)
+	contentHash := sha256.Sum256(content)
+
+	root := fmt.Sprintf("/api/packages/%s/hex", user.Name)
+
+	var publicKey *rsa.PublicKey
+
+	// readResource verifies the signature of a registry resource and returns the decoded fields of the payload
+	readResource := func(t *testing.T, url string) map[protowire.Number][][]byte {
+		req := NewRequest(t, "GET", url)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		zr, err := gzip.NewReader(resp.Body)
+		require.NoError(t, err)
+		signed, err := io.ReadAll(zr)
+		require.NoError(t, err)
+
+		envelope := decodeProtobufFields(t, signed)
+		require.Len(t, envelope[1], 1)
+		require.Len(t, envelope[2], 1)
+
+		hash := sha512.Sum512(envelope[1][0])
+		require.NoError(t, rsa.VerifyPKCS1v15(publicKey, crypto.SHA512, hash[:], envelope[2][0]))
+
+		return decodeProtobufFields(t, envelope[1][0])
+	}
+
+	t.Run("PublicKey", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", root+"/public_key")
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		block, _ := pem.Decode(resp.Body.Bytes())
+		require.NotNil(t, block)
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		require.NoError(t, err)
+		publicKey = key.(*rsa.PublicKey)
+	})
+
+	t.Run("Upload", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		url := root + "/api/publish"
+
+		req := NewRequestWithBody(t, "POST", url, bytes.NewReader(content))
+		MakeRequest(t, req, http.StatusUnauthorized)
+
+		req = NewRequestWithBody(t, "POST", url, strings.NewReader("invalid")).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusBadRequest)
+
+		req = NewRequestWithBody(t, "POST", url, bytes.NewReader(createPackage("1.0"))).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		// the Hex client sends the API key without a scheme
+		token := getUserToken(t, user.Name, auth_model.AccessTokenScopeWritePackage)
+
+		req = NewRequestWithBody(t, "POST", url, bytes.NewReader(content)).
+			SetHeader("Authorization", token).
+			SetHeader("Accept", hex_module.ContentTypeErlang)
+		resp := MakeRequest(t, req, http.StatusCreated)
+		assert.Equal(t, hex_module.ContentTypeErlang, resp.Header().Get("Content-Type"))
+
+		body, err := hex_module.DecodeExternalTerm(resp.Body.Bytes())
+		require.NoError(t, err)
+		assert.Equal(t, packageVersion, body.(map[string]any)["version"])
+		assert.Equal(t, hex.EncodeToString(contentHash[:]), body.(map[string]any)["checksum"])
+
+		pvs, err := packages.GetVersionsByPackageType(db.DefaultContext, user.ID, packages.TypeHex)
+		require.NoError(t, err)
+		require.Len(t, pvs, 1)
+
+		pd, err := packages.GetPackageDescriptor(db.DefaultContext, pvs[0])
+		require.NoError(t, err)
+		assert.IsType(t, &hex_module.Metadata{}, pd.Metadata)
+		metadata := pd.Metadata.(*hex_module.Metadata)
+		assert.Equal(t, packageDescription, metadata.Description)
+		assert.Equal(t, "# Gitea", metadata.Readme)
+		assert.Equal(t, []string{"MIT"}, metadata.Licenses)
+		require.Len(t, metadata.Requirements, 1)
+		assert.Equal(t, "jason", metadata.Requirements[0].Name)
+		require.Len(t, pd.Files, 1)
+		assert.Equal(t, fmt.Sprintf("%s-%s.tar", packageName, packageVersion), pd.Files[0].File.Name)
+		assert.True(t, pd.Files[0].File.IsLead)
+
+		req = NewRequestWithBody(t, "POST", root+"/api/repos/"+user.Name+"/publish", bytes.NewReader(content)).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusConflict)
+
+		req = NewRequestWithBody(t, "POST", url+"?replace=true", bytes.NewReader(content)).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusCreated)
+
+		req = NewRequestWithBody(t, "POST", url, bytes.NewReader(createPackage("1.1.0"))).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusCreated)
+
+		// the signed resources are built on upload instead of on every request
+		repoVersion, err := hex_service.GetOrCreateRepositoryVersion(db.DefaultContext, user.ID)
+		require.NoError(t, err)
+		pfs, err := packages.GetFilesByVersionID(db.DefaultContext, repoVersion.ID)
+		require.NoError(t, err)
+		filenames := make([]string, 0, len(pfs))
+		for _, pf := range pfs {
+			filenames = append(filenames, pf.CompositeKey+"/"+pf.Name)
+		}
+		assert.ElementsMatch(t, []string{"/names", "/versions", hex_service.PackageCompositeKey + "/" + packageName}, filenames)
+	})
+
+	t.Run("Names", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		names := readResource(t, root+"/names")
+		assert.Equal(t, [][]byte{[]byte(user.Name)}, names[2])
+		require.Len(t, names[1], 1)
+		assert.Equal(t, [][]byte{[]byte(packageName)}, decodeProtobufFields(t, names[1][0])[1])
+	})
+
+	t.Run("Versions", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		versions := readResource(t, root+"/versions")
+		require.Len(t, versions[1], 1)
+
+		pkg := decodeProtobufFields(t, versions[1][0])
+		assert.Equal(t, [][]byte{[]byte(packageName)}, pkg[1])
+		assert.Equal(t, [][]byte{[]byte(packageVersion), []byte("1.1.0")}, pkg[2])
+		assert.Empty(t, pkg[3])
+	})
+
+	t.Run("Package", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		p := readResource(t, root+"/packages/"+packageName)
+		assert.Equal(t, [][]byte{[]byte(packageName)}, p[2])
+		assert.Equal(t, [][]byte{[]byte(user.Name)}, p[3])
+		require.Len(t, p[1], 2)
+
+		release := decodeProtobufFields(t, p[1][0])
+		assert.Equal(t, [][]byte{[]byte(packageVersion)}, release[1])
+		assert.Len(t, release[2][0], 32)
+		assert.Equal(t, [][]byte{contentHash[:]}, release[5])
+		require.Len(t, release[3], 1)
+		dependency := decodeProtobufFields(t, release[3][0])
+		assert.Equal(t, [][]byte{[]byte("jason")}, dependency[1])
+		assert.Equal(t, [][]byte{[]byte("~> 1.4")}, dependency[2])
+
+		req := NewRequest(t, "GET", root+"/packages/unknown")
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("Download", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", fmt.Sprintf("%s/tarballs/%s-%s.tar", root, packageName, packageVersion))
+		resp := MakeRequest(t, req, http.StatusOK)
+		assert.Equal(t, content, resp.Body.Bytes())
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/tarballs/%s-%s.tar", root, packageName, "9.9.9"))
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("Retire", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		url := fmt.Sprintf("%s/api/packages/%s/releases/%s/retire", root, packageName, packageVersion)
+
+		body, err := hex_module.EncodeExternalTerm(map[string]any{"reason": "security", "message": "CVE"})
+		require.NoError(t, err)
+
+		req := NewRequestWithBody(t, "POST", url, bytes.NewReader(body))
+		MakeRequest(t, req, http.StatusUnauthorized)
+
+		req = NewRequestWithBody(t, "POST", url, strings.NewReader(`{"reason":"unknown"}`)).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestWithBody(t, "POST", url, bytes.NewReader(body)).
+			SetHeader("Content-Type", hex_module.ContentTypeErlang).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		versions := readResource(t, root+"/versions")
+		assert.Equal(t, [][]byte{{0}}, decodeProtobufFields(t, versions[1][0])[3])
+
+		p := readResource(t, root+"/packages/"+packageName)
+		retired := decodeProtobufFields(t, decodeProtobufFields(t, p[1][0])[4][0])
+		assert.Equal(t, [][]byte{{byte(hex_module.RetiredSecurity)}}, retired[1])
+		assert.Equal(t, [][]byte{[]byte("CVE")}, retired[2])
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/%s/-/packages/hex/%s/%s", user.Name, packageName, packageVersion))
+		resp := MakeRequest(t, req, http.StatusOK)
+		assert.Contains(t, resp.Body.String(), "This release has been retired (reason: security).")
+
+		req = NewRequest(t, "DELETE", url).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		versions = readResource(t, root+"/versions")
+		assert.Empty(t, decodeProtobufFields(t, versions[1][0])[3])
+	})
+
+	t.Run("DeleteFromAPI", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequestWithBody(t, "POST", root+"/api/publish", bytes.NewReader(createPackage("1.2.0"))).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusCreated)
+
+		token := getUserToken(t, user.Name, auth_model.AccessTokenScopeWritePackage)
+		req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/packages/%s/hex/%s/1.2.0", user.Name, packageName)).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		versions := readResource(t, root+"/versions")
+		assert.Equal(t, [][]byte{[]byte(packageVersion), []byte("1.1.0")}, decodeProtobufFields(t, versions[1][0])[2])
+		p := readResource(t, root+"/packages/"+packageName)
+		assert.Len(t, p[1], 2)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		for _, version := range []string{packageVersion, "1.1.0"} {
+			url := fmt.Sprintf("%s/api/packages/%s/releases/%s", root, packageName, version)
+
+			req := NewRequest(t, "DELETE", url)
+			MakeRequest(t, req, http.StatusUnauthorized)
+
+			req = NewRequest(t, "DELETE", url).
+				AddBasicAuth(user.Name)
+			MakeRequest(t, req, http.StatusNoContent)
+
+			req = NewRequest(t, "DELETE", url).
+				AddBasicAuth(user.Name)
+			MakeRequest(t, req, http.StatusNotFound)
+		}
+
+		pvs, err := packages.GetVersionsByPackageType(db.DefaultContext, user.ID, packages.TypeHex)
+		assert.NoError(t, err)
+		assert.Empty(t, pvs)
+
+		names := readResource(t, root+"/names")
+		assert.Empty(t, names[1])
+
+		req := NewRequest(t, "GET", root+"/packages/"+packageName)
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+}
+
+// decodeProtobufFields returns the raw values of all length delimited and varint fields grouped by field number
+func decodeProtobufFields(t *testing.T, b []byte) map[protowire.Number][][]byte {
+	fields := make(map[protowire.Number][][]byte)
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		require.GreaterOrEqual(t, n, 0)
+		b = b[n:]
+
+		switch typ {
+		case protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			require.GreaterOrEqual(t, n, 0)
+			fields[num] = append(fields[num], protowire.AppendVarint(nil, v))
+			b = b[n:]
+		case protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
+			require.GreaterOrEqual(t, n, 0)
+			fields[num] = append(fields[num], v)
+			b = b[n:]
+		default:
+			t.Fatalf("unexpected wire type %d", typ)
+		}
+	}
+	return fields
+}
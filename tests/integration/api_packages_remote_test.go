@@ -0,0 +1,420 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	container_module "code.gitea.io/gitea/modules/packages/container"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/test"
+	remote_service "code.gitea.io/gitea/services/packages/remote"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackageRemote(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *neturl.URL) {
+		defer test.MockVariableValue(&setting.Packages.RemoteAllowedHostList, "loopback")()
+
+		upstream := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+
+		token := getUserToken(t, user.Name, auth_model.AccessTokenScopeWritePackage)
+		upstreamToken := getUserToken(t, upstream.Name, auth_model.AccessTokenScopeWritePackage)
+
+		remotesURL := fmt.Sprintf("/api/v1/packages/%s/-/remotes", user.Name)
+
+		setRemote := func(t *testing.T, packageType, url, username string) {
+			opts := api.SetPackageRemoteOption{URL: url, Username: username, Enabled: true}
+			if username != "" {
+				password := userPassword
+				opts.Password = &password
+			}
+			req := NewRequestWithJSON(t, "PUT", remotesURL+"/"+packageType, opts).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusOK)
+		}
+
+		assertProxied := func(t *testing.T, packageType packages_model.Type, name string) {
+			p, err := packages_model.GetPackageByName(db.DefaultContext, user.ID, packageType, name)
+			require.NoError(t, err)
+			proxied, err := remote_service.IsProxied(db.DefaultContext, p.ID)
+			assert.NoError(t, err)
+			assert.True(t, proxied)
+		}
+
+		t.Run("Manage", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			req := NewRequestWithJSON(t, "PUT", remotesURL+"/npm", api.SetPackageRemoteOption{URL: "https://registry.npmjs.org", Enabled: true})
+			MakeRequest(t, req, http.StatusUnauthorized)
+
+			req = NewRequestWithJSON(t, "PUT", remotesURL+"/npm", api.SetPackageRemoteOption{URL: "https://registry.npmjs.org", Enabled: true}).
+				AddTokenAuth(upstreamToken)
+			MakeRequest(t, req, http.StatusForbidden)
+
+			req = NewRequestWithJSON(t, "PUT", remotesURL+"/generic", api.SetPackageRemoteOption{URL: "https://example.com", Enabled: true}).
+				AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+			req = NewRequestWithJSON(t, "PUT", remotesURL+"/npm", api.SetPackageRemoteOption{URL: "ftp://example.com", Enabled: true}).
+				AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+			password := "secret"
+			req = NewRequestWithJSON(t, "PUT", remotesURL+"/npm", api.SetPackageRemoteOption{
+				URL:         "https://registry.npmjs.org",
+				Username:    "npm-user",
+				Password:    &password,
+				MetadataTTL: 60,
+				Enabled:     true,
+			}).AddTokenAuth(token)
+			resp := MakeRequest(t, req, http.StatusOK)
+
+			var remote *api.PackageRemote
+			DecodeJSON(t, resp, &remote)
+			assert.Equal(t, "npm", remote.Type)
+			assert.Equal(t, "https://registry.npmjs.org", remote.URL)
+			assert.Equal(t, "npm-user", remote.Username)
+			assert.True(t, remote.HasPassword)
+			assert.EqualValues(t, 60, remote.MetadataTTL)
+			assert.True(t, remote.Enabled)
+
+			pr, err := packages_model.GetRemoteByOwnerAndType(db.DefaultContext, user.ID, packages_model.TypeNpm)
+			require.NoError(t, err)
+			assert.NotEqual(t, password, pr.PasswordEncrypted)
+			decrypted, err := pr.Password()
+			assert.NoError(t, err)
+			assert.Equal(t, password, decrypted)
+
+			// the password is kept if it is not set
+			req = NewRequestWithJSON(t, "PUT", remotesURL+"/npm", api.SetPackageRemoteOption{URL: "https://registry.npmjs.org", Username: "npm-user", Enabled: true}).
+				AddTokenAuth(token)
+			resp = MakeRequest(t, req, http.StatusOK)
+			DecodeJSON(t, resp, &remote)
+			assert.True(t, remote.HasPassword)
+
+			req = NewRequest(t, "GET", remotesURL).AddTokenAuth(token)
+			resp = MakeRequest(t, req, http.StatusOK)
+
+			var remotes []*api.PackageRemote
+			DecodeJSON(t, resp, &remotes)
+			assert.Len(t, remotes, 1)
+
+			req = NewRequest(t, "GET", remotesURL+"/npm").AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusOK)
+
+			req = NewRequest(t, "DELETE", remotesURL+"/npm").AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusNoContent)
+
+			req = NewRequest(t, "GET", remotesURL+"/npm").AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusNotFound)
+
+			req = NewRequest(t, "DELETE", remotesURL+"/npm").AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusNotFound)
+		})
+
+		t.Run("Npm", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			packageName := "@scope/test-package"
+			packageVersion := "1.0.1"
+			filename := "test-package-1.0.1.tgz"
+			data := "H4sIAAAAAAAA/ytITM5OTE/VL4DQelnF+XkMVAYGBgZmJiYK2MRBwNDcSIHB2NTMwNDQzMwAqA7IMDUxA9LUdgg2UFpcklgEdAql5kD8ogCnhwio5lJQUMpLzE1VslJQcihOzi9I1S9JLS7RhSYIJR2QgrLUouLM/DyQGkM9Az1D3YIiqExKanFyUWZBCVQ2BKhVwQVJDKwosbQkI78IJO/tZ+LsbRykxFXLNdA+HwWjYBSMgpENACgAbtAACAAA"
+
+			upload := `{
+				"_id": "` + packageName + `",
+				"name": "` + packageName + `",
+				"dist-tags": {"latest": "` + packageVersion + `"},
+				"versions": {
+					"` + packageVersion + `": {
+						"name": "` + packageName + `",
+						"version": "` + packageVersion + `",
+						"dist": {
+							"integrity": "sha512-yA4FJsVhetynGfOC1jFf79BuS+jrHbm0fhh+aHzCQkOaOBXKf9oBnC4a6DnLLnEsHQDRLYd00cwj8sCXpC+wIg==",
+							"shasum": "aaa7eaf852a948b0aa05afeda35b1badca155d90"
+						}
+					}
+				},
+				"_attachments": {
+					"` + filename + `": {"data": "` + data + `"}
+				}
+			}`
+
+			req := NewRequestWithBody(t, "PUT", fmt.Sprintf("/api/packages/%s/npm/%s", upstream.Name, neturl.QueryEscape(packageName)), strings.NewReader(upload)).
+				AddTokenAuth(upstreamToken)
+			MakeRequest(t, req, http.StatusCreated)
+
+			root := fmt.Sprintf("/api/packages/%s/npm/%s", user.Name, neturl.QueryEscape(packageName))
+
+			req = NewRequest(t, "GET", root)
+			MakeRequest(t, req, http.StatusNotFound)
+
+			setRemote(t, "npm", fmt.Sprintf("%sapi/packages/%s/npm", setting.AppURL, upstream.Name), "")
+
+			req = NewRequest(t, "GET", root)
+			resp := MakeRequest(t, req, http.StatusOK)
+
+			var result map[string]any
+			DecodeJSON(t, resp, &result)
+			versions := result["versions"].(map[string]any)
+			assert.Contains(t, versions, packageVersion)
+			dist := versions[packageVersion].(map[string]any)["dist"].(map[string]any)
+			assert.Equal(t, fmt.Sprintf("%sapi/packages/%s/npm/%s/-/%s/%s", setting.AppURL, user.Name, neturl.QueryEscape(packageName), packageVersion, filename), dist["tarball"])
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/-/%s/%s", root, packageVersion, filename))
+			resp = MakeRequest(t, req, http.StatusOK)
+
+			b, _ := base64.StdEncoding.DecodeString(data)
+			assert.Equal(t, b, resp.Body.Bytes())
+
+			assertProxied(t, packages_model.TypeNpm, packageName)
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/-/%s/%s", root, "9.9.9", "test-package-9.9.9.tgz"))
+			MakeRequest(t, req, http.StatusNotFound)
+		})
+
+		t.Run("PyPI", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			packageName := "test-package"
+			packageVersion := "1.0.1"
+			filename := "test_package-1.0.1-py3-none-any.whl"
+			content := "test"
+			hashSHA256 := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			part, _ := writer.CreateFormFile("content", filename)
+			_, _ = io.Copy(part, strings.NewReader(content))
+			_ = writer.WriteField("name", packageName)
+			_ = writer.WriteField("version", packageVersion)
+			_ = writer.WriteField("sha256_digest", hashSHA256)
+			_ = writer.WriteField("requires_python", "3.6")
+			_ = writer.Close()
+
+			req := NewRequestWithBody(t, "POST", fmt.Sprintf("/api/packages/%s/pypi", upstream.Name), body).
+				SetHeader("Content-Type", writer.FormDataContentType()).
+				AddBasicAuth(upstream.Name)
+			MakeRequest(t, req, http.StatusCreated)
+
+			root := fmt.Sprintf("/api/packages/%s/pypi", user.Name)
+
+			setRemote(t, "pypi", fmt.Sprintf("%sapi/packages/%s/pypi", setting.AppURL, upstream.Name), "")
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/simple/%s", root, packageName))
+			resp := MakeRequest(t, req, http.StatusOK)
+
+			htmlDoc := NewHTMLParser(t, resp.Body)
+			nodes := htmlDoc.doc.Find("a")
+			assert.Equal(t, 1, nodes.Length())
+			href, _ := nodes.Attr("href")
+			assert.Equal(t, fmt.Sprintf("%sapi/packages/%s/pypi/files/%s/%s/%s#sha256=%s", setting.AppURL, user.Name, packageName, packageVersion, filename, hashSHA256), href)
+			requiresPython, _ := nodes.Attr("data-requires-python")
+			assert.Equal(t, "3.6", requiresPython)
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/files/%s/%s/%s", root, packageName, packageVersion, filename))
+			resp = MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, content, resp.Body.String())
+
+			assertProxied(t, packages_model.TypePyPI, packageName)
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/files/%s/%s/%s", root, packageName, "9.9.9", "test_package-9.9.9-py3-none-any.whl"))
+			MakeRequest(t, req, http.StatusNotFound)
+		})
+
+		t.Run("Maven", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			packageVersion := "1.0.1"
+			filename := "test-project-1.0.1.jar"
+			content := "test"
+
+			req := NewRequestWithBody(t, "PUT", fmt.Sprintf("/api/packages/%s/maven/com/gitea/test-project/%s/%s", upstream.Name, packageVersion, filename), strings.NewReader(content)).
+				AddBasicAuth(upstream.Name)
+			MakeRequest(t, req, http.StatusCreated)
+
+			root := fmt.Sprintf("/api/packages/%s/maven/com/gitea/test-project", user.Name)
+
+			setRemote(t, "maven", fmt.Sprintf("%sapi/packages/%s/maven", setting.AppURL, upstream.Name), "")
+
+			req = NewRequest(t, "GET", root+"/maven-metadata.xml")
+			resp := MakeRequest(t, req, http.StatusOK)
+			assert.Contains(t, resp.Body.String(), "<version>"+packageVersion+"</version>")
+
+			t.Run("QuotaExceeded", func(t *testing.T) {
+				defer tests.PrintCurrentTest(t)()
+				defer test.MockVariableValue(&setting.Packages.LimitSizeMaven, int64(len(content)-1))()
+
+				req := NewRequest(t, "GET", fmt.Sprintf("%s/%s/%s", root, packageVersion, filename))
+				MakeRequest(t, req, http.StatusForbidden)
+
+				_, err := packages_model.GetPackageByName(db.DefaultContext, user.ID, packages_model.TypeMaven, "com.gitea:test-project")
+				assert.ErrorIs(t, err, packages_model.ErrPackageNotExist)
+			})
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/%s/%s.sha1", root, packageVersion, filename))
+			resp = MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, "a94a8fe5ccb19ba61c4c0873d391e987982fbbd3", resp.Body.String())
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/%s/%s", root, packageVersion, filename))
+			resp = MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, content, resp.Body.String())
+
+			assertProxied(t, packages_model.TypeMaven, "com.gitea:test-project")
+		})
+
+		t.Run("Go", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			packageName := "gitea.com/go-gitea/gitea"
+			packageVersion := "v0.0.1"
+			goModContent := `module "gitea.com/go-gitea/gitea"`
+
+			var buf bytes.Buffer
+			zw := zip.NewWriter(&buf)
+			w, _ := zw.Create(packageName + "@" + packageVersion + "/go.mod")
+			_, _ = w.Write([]byte(goModContent))
+			_ = zw.Close()
+
+			req := NewRequestWithBody(t, "PUT", fmt.Sprintf("/api/packages/%s/go/upload", upstream.Name), bytes.NewReader(buf.Bytes())).
+				AddBasicAuth(upstream.Name)
+			MakeRequest(t, req, http.StatusCreated)
+
+			root := fmt.Sprintf("/api/packages/%s/go/%s", user.Name, packageName)
+
+			setRemote(t, "go", fmt.Sprintf("%sapi/packages/%s/go", setting.AppURL, upstream.Name), "")
+
+			req = NewRequest(t, "GET", root+"/@v/list")
+			resp := MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, packageVersion+"\n", resp.Body.String())
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/@v/%s.mod", root, packageVersion))
+			resp = MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, goModContent, resp.Body.String())
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/@v/%s.zip", root, packageVersion))
+			resp = MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, buf.Bytes(), resp.Body.Bytes())
+
+			assertProxied(t, packages_model.TypeGo, packageName)
+
+			req = NewRequest(t, "GET", root+"/@v/v9.9.9.zip")
+			MakeRequest(t, req, http.StatusNotFound)
+		})
+
+		t.Run("Container", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			image := "remote-test"
+			tag := "latest"
+
+			blobDigest := "sha256:a3ed95caeb02ffe68cdd9fd84406680ae93d633cb16422d00e8a7c22955b46d4"
+			blobContent, _ := base64.StdEncoding.DecodeString(`H4sIAAAJbogA/2IYBaNgFIxYAAgAAP//Lq+17wAEAAA=`)
+
+			configDigest := "sha256:4607e093bec406eaadb6f3a340f63400c9d3a7038680744c406903766b938f0d"
+			configContent := `{"architecture":"amd64","config":{"Env":["PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"],"Cmd":["/true"],"ArgsEscaped":true,"Image":"sha256:9bd8b88dc68b80cffe126cc820e4b52c6e558eb3b37680bfee8e5f3ed7b8c257"},"container":"b89fe92a887d55c0961f02bdfbfd8ac3ddf66167db374770d2d9e9fab3311510","container_config":{"Hostname":"b89fe92a887d","Env":["PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"],"Cmd":["/bin/sh","-c","#(nop) ","CMD [\"/true\"]"],"ArgsEscaped":true,"Image":"sha256:9bd8b88dc68b80cffe126cc820e4b52c6e558eb3b37680bfee8e5f3ed7b8c257"},"created":"2022-01-01T00:00:00.000000000Z","docker_version":"20.10.12","history":[{"created":"2022-01-01T00:00:00.000000000Z","created_by":"/bin/sh -c #(nop) COPY file:0e7589b0c800daaf6fa460d2677101e4676dd9491980210cb345480e513f3602 in /true "},{"created":"2022-01-01T00:00:00.000000001Z","created_by":"/bin/sh -c #(nop)  CMD [\"/true\"]","empty_layer":true}],"os":"linux","rootfs":{"type":"layers","diff_ids":["sha256:0ff3b91bdf21ecdf2f2f3d4372c2098a14dbe06cd678e8f0a85fd4902d00e2e2"]}}`
+
+			manifestDigest := "sha256:4f10484d1c1bb13e3956b4de1cd42db8e0f14a75be1617b60f2de3cd59c803c6"
+			manifestContent := `{"schemaVersion":2,"mediaType":"` + container_module.ContentTypeDockerDistributionManifestV2 + `","config":{"mediaType":"application/vnd.docker.container.image.v1+json","digest":"sha256:4607e093bec406eaadb6f3a340f63400c9d3a7038680744c406903766b938f0d","size":1069},"layers":[{"mediaType":"application/vnd.docker.image.rootfs.diff.tar.gzip","digest":"sha256:a3ed95caeb02ffe68cdd9fd84406680ae93d633cb16422d00e8a7c22955b46d4","size":32}]}`
+
+			getToken := func(t *testing.T, username string) string {
+				req := NewRequest(t, "GET", setting.AppURL+"v2/token").AddBasicAuth(username)
+				resp := MakeRequest(t, req, http.StatusOK)
+
+				var tokenResponse struct {
+					Token string `json:"token"`
+				}
+				DecodeJSON(t, resp, &tokenResponse)
+				return "Bearer " + tokenResponse.Token
+			}
+
+			upstreamURL := fmt.Sprintf("%sv2/%s/%s", setting.AppURL, upstream.Name, image)
+			upstreamContainerToken := getToken(t, upstream.Name)
+
+			req := NewRequestWithBody(t, "POST", fmt.Sprintf("%s/blobs/uploads?digest=%s", upstreamURL, blobDigest), bytes.NewReader(blobContent)).
+				AddTokenAuth(upstreamContainerToken)
+			MakeRequest(t, req, http.StatusCreated)
+
+			req = NewRequestWithBody(t, "POST", fmt.Sprintf("%s/blobs/uploads?digest=%s", upstreamURL, configDigest), strings.NewReader(configContent)).
+				AddTokenAuth(upstreamContainerToken)
+			MakeRequest(t, req, http.StatusCreated)
+
+			req = NewRequestWithBody(t, "PUT", fmt.Sprintf("%s/manifests/%s", upstreamURL, tag), strings.NewReader(manifestContent)).
+				AddTokenAuth(upstreamContainerToken).
+				SetHeader("Content-Type", container_module.ContentTypeDockerDistributionManifestV2)
+			MakeRequest(t, req, http.StatusCreated)
+
+			// the upstream requires a bearer token which is requested with the credentials of the remote
+			setRemote(t, "container", setting.AppURL+upstream.Name, upstream.Name)
+
+			url := fmt.Sprintf("%sv2/%s/%s", setting.AppURL, user.Name, image)
+			containerToken := getToken(t, user.Name)
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/tags/list", url)).
+				AddTokenAuth(containerToken)
+			resp := MakeRequest(t, req, http.StatusOK)
+
+			var tagList struct {
+				Name string   `json:"name"`
+				Tags []string `json:"tags"`
+			}
+			DecodeJSON(t, resp, &tagList)
+			assert.Equal(t, user.LowerName+"/"+image, tagList.Name)
+			assert.Equal(t, []string{tag}, tagList.Tags)
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/manifests/%s", url, tag)).
+				AddTokenAuth(containerToken)
+			resp = MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, manifestDigest, resp.Header().Get("Docker-Content-Digest"))
+			assert.Equal(t, manifestContent, resp.Body.String())
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/blobs/%s", url, blobDigest)).
+				AddTokenAuth(containerToken)
+			resp = MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, blobContent, resp.Body.Bytes())
+
+			assertProxied(t, packages_model.TypeContainer, image)
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/manifests/%s", url, "unknown")).
+				AddTokenAuth(containerToken)
+			MakeRequest(t, req, http.StatusNotFound)
+		})
+
+		t.Run("Disabled", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			req := NewRequestWithJSON(t, "PUT", remotesURL+"/go", api.SetPackageRemoteOption{
+				URL:     fmt.Sprintf("%sapi/packages/%s/go", setting.AppURL, upstream.Name),
+				Enabled: false,
+			}).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusOK)
+
+			// the stored versions are served without the remote
+			req = NewRequest(t, "GET", fmt.Sprintf("/api/packages/%s/go/gitea.com/go-gitea/gitea/@v/list", user.Name))
+			resp := MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, "v0.0.1\n", resp.Body.String())
+
+			req = NewRequest(t, "GET", fmt.Sprintf("/api/packages/%s/go/gitea.com/go-gitea/other/@v/list", user.Name))
+			MakeRequest(t, req, http.StatusNotFound)
+		})
+	})
+}
@@ -0,0 +1,343 @@
+// Copyright 2025 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	terraform_module "code.gitea.io/gitea/modules/packages/terraform"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackageTerraform(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	registryURL := setting.AppURL + "api/packages/-/terraform"
+	root := fmt.Sprintf("/api/packages/%s/terraform", user.Name)
+
+	t.Run("ServiceDiscovery", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", "/.well-known/terraform.json")
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var result map[string]string
+		DecodeJSON(t, resp, &result)
+		assert.Equal(t, registryURL+"/modules/v1/", result["modules.v1"])
+		assert.Equal(t, registryURL+"/providers/v1/", result["providers.v1"])
+	})
+
+	t.Run("Module", func(t *testing.T) {
+		moduleName := "consul"
+		moduleSystem := "aws"
+		moduleVersion := "1.0.0"
+		moduleReadme := "# Consul"
+
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(zw)
+		for name, content := range map[string]string{
+			"main.tf":   `resource "null_resource" "test" {}`,
+			"README.md": moduleReadme,
+		} {
+			_ = tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(content))})
+			_, _ = tw.Write([]byte(content))
+		}
+		_ = tw.Close()
+		_ = zw.Close()
+		content := buf.Bytes()
+
+		url := fmt.Sprintf("%s/modules/%s/%s/%s", root, moduleName, moduleSystem, moduleVersion)
+		filename := fmt.Sprintf("terraform-%s-%s-%s.tar.gz", moduleSystem, moduleName, moduleVersion)
+
+		t.Run("Upload", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			req := NewRequestWithBody(t, "PUT", url, bytes.NewReader(content))
+			MakeRequest(t, req, http.StatusUnauthorized)
+
+			req = NewRequestWithBody(t, "PUT", fmt.Sprintf("%s/modules/%s/%s/v%s", root, moduleName, moduleSystem, moduleVersion), bytes.NewReader(content)).
+				AddBasicAuth(user.Name)
+			MakeRequest(t, req, http.StatusBadRequest)
+
+			req = NewRequestWithBody(t, "PUT", url, strings.NewReader("invalid")).
+				AddBasicAuth(user.Name)
+			MakeRequest(t, req, http.StatusBadRequest)
+
+			req = NewRequestWithBody(t, "PUT", url, bytes.NewReader(content)).
+				AddBasicAuth(user.Name)
+			MakeRequest(t, req, http.StatusCreated)
+
+			pvs, err := packages.GetVersionsByPackageType(db.DefaultContext, user.ID, packages.TypeTerraform)
+			require.NoError(t, err)
+			require.Len(t, pvs, 1)
+
+			pd, err := packages.GetPackageDescriptor(db.DefaultContext, pvs[0])
+			require.NoError(t, err)
+			assert.Equal(t, moduleName+"/"+moduleSystem, pd.Package.Name)
+			assert.Equal(t, moduleVersion, pd.Version.Version)
+			assert.IsType(t, &terraform_module.Metadata{}, pd.Metadata)
+			metadata := pd.Metadata.(*terraform_module.Metadata)
+			assert.Equal(t, terraform_module.KindModule, metadata.Kind)
+			assert.Equal(t, moduleReadme, metadata.Readme)
+			require.Len(t, pd.Files, 1)
+			assert.Equal(t, filename, pd.Files[0].File.Name)
+			assert.True(t, pd.Files[0].File.IsLead)
+
+			req = NewRequestWithBody(t, "PUT", url, bytes.NewReader(content)).
+				AddBasicAuth(user.Name)
+			MakeRequest(t, req, http.StatusConflict)
+
+			req = NewRequest(t, "GET", fmt.Sprintf("/%s/-/packages/terraform/%s%%2F%s/%s", user.Name, moduleName, moduleSystem, moduleVersion))
+			resp := MakeRequest(t, req, http.StatusOK)
+			assert.Contains(t, resp.Body.String(), fmt.Sprintf(`module "%s"`, moduleName))
+		})
+
+		t.Run("EnumerateVersions", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			req := NewRequest(t, "GET", fmt.Sprintf("%s/modules/v1/%s/%s/%s/versions", registryURL, user.Name, moduleName, moduleSystem))
+			resp := MakeRequest(t, req, http.StatusOK)
+
+			var result struct {
+				Modules []struct {
+					Versions []struct {
+						Version string `json:"version"`
+					} `json:"versions"`
+				} `json:"modules"`
+			}
+			DecodeJSON(t, resp, &result)
+			require.Len(t, result.Modules, 1)
+			require.Len(t, result.Modules[0].Versions, 1)
+			assert.Equal(t, moduleVersion, result.Modules[0].Versions[0].Version)
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/modules/v1/%s/%s/%s/versions", registryURL, user.Name, "unknown", moduleSystem))
+			MakeRequest(t, req, http.StatusNotFound)
+		})
+
+		t.Run("Download", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			req := NewRequest(t, "GET", fmt.Sprintf("%s/modules/v1/%s/%s/%s/%s/download", registryURL, user.Name, moduleName, moduleSystem, moduleVersion))
+			resp := MakeRequest(t, req, http.StatusNoContent)
+
+			location := resp.Header().Get("X-Terraform-Get")
+			assert.Equal(t, fmt.Sprintf("%s%s/%s", setting.AppURL, url[1:], filename), location)
+
+			req = NewRequest(t, "GET", location)
+			resp = MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, content, resp.Body.Bytes())
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/modules/v1/%s/%s/%s/%s/download", registryURL, user.Name, moduleName, moduleSystem, "9.9.9"))
+			MakeRequest(t, req, http.StatusNotFound)
+		})
+
+		t.Run("Delete", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			req := NewRequest(t, "DELETE", url)
+			MakeRequest(t, req, http.StatusUnauthorized)
+
+			req = NewRequest(t, "DELETE", url).
+				AddBasicAuth(user.Name)
+			MakeRequest(t, req, http.StatusNoContent)
+
+			pvs, err := packages.GetVersionsByPackageType(db.DefaultContext, user.ID, packages.TypeTerraform)
+			assert.NoError(t, err)
+			assert.Empty(t, pvs)
+
+			req = NewRequest(t, "DELETE", url).
+				AddBasicAuth(user.Name)
+			MakeRequest(t, req, http.StatusNotFound)
+		})
+	})
+
+	t.Run("Provider", func(t *testing.T) {
+		providerType := "random"
+		providerVersion := "2.0.0"
+
+		var archive bytes.Buffer
+		zw := zip.NewWriter(&archive)
+		w, _ := zw.Create("terraform-provider-random_v2.0.0")
+		_, _ = w.Write([]byte("binary"))
+		_ = zw.Close()
+
+		filename := func(suffix string) string {
+			return fmt.Sprintf("terraform-provider-%s_%s_%s", providerType, providerVersion, suffix)
+		}
+
+		archiveHash := sha256.Sum256(archive.Bytes())
+		checksums := fmt.Sprintf("%s  %s\n", hex.EncodeToString(archiveHash[:]), filename("linux_amd64.zip"))
+
+		e, err := openpgp.NewEntity("Gitea", "", "gitea@example.com", nil)
+		require.NoError(t, err)
+
+		var key bytes.Buffer
+		aw, err := armor.Encode(&key, openpgp.PublicKeyType, nil)
+		require.NoError(t, err)
+		require.NoError(t, e.Serialize(aw))
+		require.NoError(t, aw.Close())
+
+		var signature bytes.Buffer
+		require.NoError(t, openpgp.DetachSign(&signature, e, strings.NewReader(checksums), nil))
+
+		var invalidSignature bytes.Buffer
+		require.NoError(t, openpgp.DetachSign(&invalidSignature, e, strings.NewReader("modified"), nil))
+
+		url := fmt.Sprintf("%s/providers/%s/%s", root, providerType, providerVersion)
+
+		uploadFile := func(t *testing.T, name string, content []byte, expectedStatus int) {
+			req := NewRequestWithBody(t, "PUT", url+"/"+name, bytes.NewReader(content)).
+				AddBasicAuth(user.Name)
+			MakeRequest(t, req, expectedStatus)
+		}
+
+		downloadURL := fmt.Sprintf("%s/providers/v1/%s/%s/%s/download/linux/amd64", registryURL, user.Name, providerType, providerVersion)
+
+		t.Run("Upload", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			req := NewRequestWithBody(t, "PUT", url+"/"+filename("linux_amd64.zip"), bytes.NewReader(archive.Bytes()))
+			MakeRequest(t, req, http.StatusUnauthorized)
+
+			uploadFile(t, "random.zip", archive.Bytes(), http.StatusBadRequest)
+			uploadFile(t, filename("linux_amd64.zip"), archive.Bytes(), http.StatusCreated)
+			uploadFile(t, filename("linux_amd64.zip"), archive.Bytes(), http.StatusConflict)
+			uploadFile(t, filename("darwin_arm64.zip"), archive.Bytes(), http.StatusCreated)
+
+			// the provider can't be installed without signature
+			req = NewRequest(t, "GET", downloadURL)
+			MakeRequest(t, req, http.StatusNotFound)
+
+			uploadFile(t, filename("manifest.json"), []byte(`{"version":2}`), http.StatusBadRequest)
+			uploadFile(t, filename("manifest.json"), []byte(`{"version":1,"metadata":{"protocol_versions":["6.0"]}}`), http.StatusCreated)
+			uploadFile(t, filename("signing_key.asc"), []byte("invalid"), http.StatusBadRequest)
+			uploadFile(t, filename("signing_key.asc"), key.Bytes(), http.StatusCreated)
+			uploadFile(t, filename("SHA256SUMS"), []byte(checksums), http.StatusCreated)
+			uploadFile(t, filename("SHA256SUMS.sig"), invalidSignature.Bytes(), http.StatusBadRequest)
+			uploadFile(t, filename("SHA256SUMS.sig"), signature.Bytes(), http.StatusCreated)
+
+			pvs, err := packages.GetVersionsByPackageType(db.DefaultContext, user.ID, packages.TypeTerraform)
+			require.NoError(t, err)
+			require.Len(t, pvs, 1)
+
+			pd, err := packages.GetPackageDescriptor(db.DefaultContext, pvs[0])
+			require.NoError(t, err)
+			assert.Equal(t, providerType, pd.Package.Name)
+			metadata := pd.Metadata.(*terraform_module.Metadata)
+			assert.Equal(t, terraform_module.KindProvider, metadata.Kind)
+			assert.Equal(t, []string{"6.0"}, metadata.Protocols)
+			assert.Len(t, pd.Files, 6)
+
+			req = NewRequest(t, "GET", fmt.Sprintf("/%s/-/packages/terraform/%s/%s", user.Name, providerType, providerVersion))
+			resp := MakeRequest(t, req, http.StatusOK)
+			assert.Contains(t, resp.Body.String(), "required_providers")
+		})
+
+		t.Run("EnumerateVersions", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			req := NewRequest(t, "GET", fmt.Sprintf("%s/providers/v1/%s/%s/versions", registryURL, user.Name, providerType))
+			resp := MakeRequest(t, req, http.StatusOK)
+
+			type platform struct {
+				OS   string `json:"os"`
+				Arch string `json:"arch"`
+			}
+			var result struct {
+				Versions []struct {
+					Version   string     `json:"version"`
+					Protocols []string   `json:"protocols"`
+					Platforms []platform `json:"platforms"`
+				} `json:"versions"`
+			}
+			DecodeJSON(t, resp, &result)
+			require.Len(t, result.Versions, 1)
+			assert.Equal(t, providerVersion, result.Versions[0].Version)
+			assert.Equal(t, []string{"6.0"}, result.Versions[0].Protocols)
+			assert.ElementsMatch(t, []platform{{"linux", "amd64"}, {"darwin", "arm64"}}, result.Versions[0].Platforms)
+		})
+
+		t.Run("Download", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			req := NewRequest(t, "GET", downloadURL)
+			resp := MakeRequest(t, req, http.StatusOK)
+
+			var result struct {
+				Protocols           []string `json:"protocols"`
+				OS                  string   `json:"os"`
+				Arch                string   `json:"arch"`
+				Filename            string   `json:"filename"`
+				DownloadURL         string   `json:"download_url"`
+				ShasumsURL          string   `json:"shasums_url"`
+				ShasumsSignatureURL string   `json:"shasums_signature_url"`
+				Shasum              string   `json:"shasum"`
+				SigningKeys         struct {
+					GPGPublicKeys []struct {
+						KeyID      string `json:"key_id"`
+						ASCIIArmor string `json:"ascii_armor"`
+					} `json:"gpg_public_keys"`
+				} `json:"signing_keys"`
+			}
+			DecodeJSON(t, resp, &result)
+			assert.Equal(t, []string{"6.0"}, result.Protocols)
+			assert.Equal(t, "linux", result.OS)
+			assert.Equal(t, "amd64", result.Arch)
+			assert.Equal(t, filename("linux_amd64.zip"), result.Filename)
+			assert.Equal(t, hex.EncodeToString(archiveHash[:]), result.Shasum)
+			require.Len(t, result.SigningKeys.GPGPublicKeys, 1)
+			assert.Equal(t, strings.ToUpper(e.PrimaryKey.KeyIdString()), result.SigningKeys.GPGPublicKeys[0].KeyID)
+			assert.Equal(t, key.String(), result.SigningKeys.GPGPublicKeys[0].ASCIIArmor)
+
+			req = NewRequest(t, "GET", result.DownloadURL)
+			resp = MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, archive.Bytes(), resp.Body.Bytes())
+
+			req = NewRequest(t, "GET", result.ShasumsURL)
+			resp = MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, checksums, resp.Body.String())
+
+			req = NewRequest(t, "GET", result.ShasumsSignatureURL)
+			resp = MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, signature.Bytes(), resp.Body.Bytes())
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/providers/v1/%s/%s/%s/download/windows/amd64", registryURL, user.Name, providerType, providerVersion))
+			MakeRequest(t, req, http.StatusNotFound)
+		})
+
+		t.Run("Delete", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			req := NewRequest(t, "DELETE", url).
+				AddBasicAuth(user.Name)
+			MakeRequest(t, req, http.StatusNoContent)
+
+			pvs, err := packages.GetVersionsByPackageType(db.DefaultContext, user.ID, packages.TypeTerraform)
+			assert.NoError(t, err)
+			assert.Empty(t, pvs)
+		})
+	})
+}
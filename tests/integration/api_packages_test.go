@@ -216,6 +216,167 @@ func TestPackageAPI(t *testing.T) {
 	})
 }
 
+func TestPackageCleanupRuleAPI(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	session := loginUser(t, user.Name)
+	token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWritePackage)
+
+	rootURL := fmt.Sprintf("/api/v1/packages/%s/-/cleanup-rules", user.Name)
+
+	for _, v := range []string{"1.0.0", "1.1.0", "2.0.0"} {
+		req := NewRequestWithBody(t, "PUT", fmt.Sprintf("/api/packages/%s/generic/package/%s/file.bin", user.Name, v), bytes.NewReader([]byte{1})).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusCreated)
+	}
+
+	var ruleID int64
+
+	t.Run("Create", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequestWithJSON(t, "POST", rootURL, &api.CreatePackageCleanupRuleOption{
+			Type:    "generic",
+			Enabled: true,
+		})
+		MakeRequest(t, req, http.StatusUnauthorized)
+
+		for _, opts := range []*api.CreatePackageCleanupRuleOption{
+			{Type: "dummy"},
+			{Type: "generic", KeepSemverRange: "not a range"},
+			{Type: "generic", KeepPattern: "("},
+			{Type: "generic", KeepCount: -1},
+		} {
+			req = NewRequestWithJSON(t, "POST", rootURL, opts).
+				AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusUnprocessableEntity)
+		}
+
+		req = NewRequestWithJSON(t, "POST", rootURL, &api.CreatePackageCleanupRuleOption{
+			Type:            "generic",
+			Enabled:         true,
+			KeepSemverRange: "~> 1.1",
+		}).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusCreated)
+
+		var rule *api.PackageCleanupRule
+		DecodeJSON(t, resp, &rule)
+		assert.Equal(t, "generic", rule.Type)
+		assert.True(t, rule.Enabled)
+		assert.Equal(t, "~> 1.1", rule.KeepSemverRange)
+		assert.False(t, rule.KeepLatestPerMajor)
+		ruleID = rule.ID
+
+		req = NewRequestWithJSON(t, "POST", rootURL, &api.CreatePackageCleanupRuleOption{
+			Type: "generic",
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusConflict)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", rootURL).
+			AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var rules []*api.PackageCleanupRule
+		DecodeJSON(t, resp, &rules)
+		assert.Len(t, rules, 1)
+		assert.Equal(t, ruleID, rules[0].ID)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/%d", rootURL, ruleID)).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusOK)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/%d", rootURL, ruleID+1)).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("Edit", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequestWithJSON(t, "PATCH", fmt.Sprintf("%s/%d", rootURL, ruleID), &api.EditPackageCleanupRuleOption{
+			KeepSemverRange: util.ToPointer(">= foo"),
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("%s/%d", rootURL, ruleID), &api.EditPackageCleanupRuleOption{
+			KeepLatestPerMajor: util.ToPointer(true),
+		}).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var rule *api.PackageCleanupRule
+		DecodeJSON(t, resp, &rule)
+		assert.True(t, rule.Enabled)
+		assert.Equal(t, "~> 1.1", rule.KeepSemverRange)
+		assert.True(t, rule.KeepLatestPerMajor)
+	})
+
+	t.Run("Preview", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", fmt.Sprintf("%s/%d/preview", rootURL, ruleID)).
+			AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var apiPackages []*api.Package
+		DecodeJSON(t, resp, &apiPackages)
+		assert.Len(t, apiPackages, 1)
+		assert.Equal(t, "package", apiPackages[0].Name)
+		assert.Equal(t, "1.0.0", apiPackages[0].Version)
+	})
+
+	t.Run("History", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		getHistory := func(t *testing.T) []*api.PackageCleanupRun {
+			req := NewRequest(t, "GET", fmt.Sprintf("%s/%d/history", rootURL, ruleID)).
+				AddTokenAuth(token)
+			resp := MakeRequest(t, req, http.StatusOK)
+
+			var runs []*api.PackageCleanupRun
+			DecodeJSON(t, resp, &runs)
+			return runs
+		}
+
+		assert.Empty(t, getHistory(t))
+
+		assert.NoError(t, packages_cleanup_service.CleanupTask(db.DefaultContext, 0))
+
+		runs := getHistory(t)
+		assert.Len(t, runs, 1)
+		assert.Equal(t, ruleID, runs[0].RuleID)
+		assert.Equal(t, "generic", runs[0].Type)
+		assert.Equal(t, []*api.PackageCleanupRunVersion{{Name: "package", Version: "1.0.0", Size: 1}}, runs[0].Versions)
+
+		_, err := packages_model.GetVersionByNameAndVersion(db.DefaultContext, user.ID, packages_model.TypeGeneric, "package", "1.0.0")
+		assert.ErrorIs(t, err, packages_model.ErrPackageNotExist)
+
+		// runs which don't remove anything are not recorded
+		assert.NoError(t, packages_cleanup_service.CleanupTask(db.DefaultContext, 0))
+		assert.Len(t, getHistory(t), 1)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequest(t, "DELETE", fmt.Sprintf("%s/%d", rootURL, ruleID)).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/%d", rootURL, ruleID)).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		runs, _, err := packages_model.GetCleanupRunsByRuleID(db.DefaultContext, ruleID, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, runs)
+	})
+}
+
 func TestPackageAccess(t *testing.T) {
 	defer tests.PrepareTestEnv(t)()
 
@@ -634,6 +795,33 @@ func TestPackageCleanup(t *testing.T) {
 					MatchFullName: true,
 				},
 			},
+			{
+				Name: "KeepSemverRange",
+				Versions: []version{
+					{Version: "1.0.0", ShouldExist: true},
+					{Version: "1.5.0", ShouldExist: true},
+					{Version: "2.0.0", ShouldExist: false},
+					{Version: "nightly", ShouldExist: false},
+				},
+				Rule: &packages_model.PackageCleanupRule{
+					Enabled:         true,
+					KeepSemverRange: ">= 1.0.0, < 2.0.0",
+				},
+			},
+			{
+				Name: "KeepLatestPerMajor",
+				Versions: []version{
+					{Version: "1.0.0", ShouldExist: false},
+					{Version: "1.1.0", ShouldExist: true},
+					{Version: "2.0.0", ShouldExist: false},
+					{Version: "2.3.1", ShouldExist: true},
+					{Version: "3.0.0-rc1", ShouldExist: false},
+				},
+				Rule: &packages_model.PackageCleanupRule{
+					Enabled:            true,
+					KeepLatestPerMajor: true,
+				},
+			},
 			{
 				Name: "Mixed",
 				Versions: func(limit, removeDays int) []version {
@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"testing"
 
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/unittest"
 	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/tests"
@@ -438,6 +440,27 @@ func TestUserSettingsPackagesRulesAdd(t *testing.T) {
 	doc := NewHTMLParser(t, resp.Body)
 
 	assertNavbar(t, doc)
+
+	addRule := func(semverRange string, expectedStatus int) {
+		req := NewRequestWithValues(t, "POST", "/user/settings/packages/rules/add", map[string]string{
+			"_csrf":             GetUserCSRFToken(t, session),
+			"enabled":           "on",
+			"type":              "generic",
+			"keep_semver_range": semverRange,
+			"action":            "save",
+		})
+		session.MakeRequest(t, req, expectedStatus)
+	}
+
+	addRule(">= foo", http.StatusOK)
+	unittest.AssertNotExistsBean(t, &packages_model.PackageCleanupRule{OwnerID: 2, Type: packages_model.TypeGeneric})
+
+	addRule(">= 1.0.0", http.StatusSeeOther)
+	unittest.AssertExistsAndLoadBean(t, &packages_model.PackageCleanupRule{OwnerID: 2, Type: packages_model.TypeGeneric, KeepSemverRange: ">= 1.0.0"})
+
+	// an owner can only have one rule per package type
+	addRule("", http.StatusOK)
+	unittest.AssertCount(t, &packages_model.PackageCleanupRule{OwnerID: 2}, 1)
 }
 
 func TestUserSettingsOrganization(t *testing.T) {
@@ -225,6 +225,8 @@ func PrepareCleanPackageData(t testing.TB) {
 		&packages_model.PackageProperty{},
 		&packages_model.PackageBlobUpload{},
 		&packages_model.PackageCleanupRule{},
+		&packages_model.PackageCleanupRun{},
+		&packages_model.PackageCleanupRunVersion{},
 	))
 	assert.NoError(t, storage.Clean(storage.Packages))
 }
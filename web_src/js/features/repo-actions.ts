@@ -14,11 +14,20 @@ export function initRepositoryActionView() {
     runIndex: el.getAttribute('data-run-index'),
     jobIndex: el.getAttribute('data-job-index'),
     actionsURL: el.getAttribute('data-actions-url'),
+    attempt: el.getAttribute('data-attempt'),
+    viewSummary: el.getAttribute('data-view-summary') === 'true',
     locale: {
       approve: el.getAttribute('data-locale-approve'),
+      reject: el.getAttribute('data-locale-reject'),
       cancel: el.getAttribute('data-locale-cancel'),
       rerun: el.getAttribute('data-locale-rerun'),
       rerun_all: el.getAttribute('data-locale-rerun-all'),
+      rerunFailed: el.getAttribute('data-locale-rerun-failed'),
+      enableDebugLogging: el.getAttribute('data-locale-enable-debug-logging'),
+      attempt: el.getAttribute('data-locale-attempt'),
+      latestAttempt: el.getAttribute('data-locale-latest-attempt'),
+      summary: el.getAttribute('data-locale-summary'),
+      noSummaries: el.getAttribute('data-locale-no-summaries'),
       scheduled: el.getAttribute('data-locale-runs-scheduled'),
       commit: el.getAttribute('data-locale-runs-commit'),
       pushedBy: el.getAttribute('data-locale-runs-pushed-by'),
@@ -30,6 +39,7 @@ export function initRepositoryActionView() {
       showLogSeconds: el.getAttribute('data-locale-show-log-seconds'),
       showFullScreen: el.getAttribute('data-locale-show-full-screen'),
       downloadLogs: el.getAttribute('data-locale-download-logs'),
+      logsExpired: el.getAttribute('data-locale-logs-expired'),
       status: {
         unknown: el.getAttribute('data-locale-status-unknown'),
         waiting: el.getAttribute('data-locale-status-waiting'),